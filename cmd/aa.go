@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+const (
+	aaAccountTypeSimple = "simple"
+	aaAccountTypeSafe   = "safe"
+)
+
+var aaBundlerUrl string
+var aaAccountType string
+var aaSalt int64
+var aaPaymasterAndData string
+
+func init() {
+	aaCmd.PersistentFlags().StringVarP(&aaBundlerUrl, "bundler-url", "", "", "the url of ERC-4337 bundler")
+	aaCmd.PersistentFlags().StringVarP(&aaAccountType, "account-type", "", aaAccountTypeSimple, "simple | safe, the type of smart account. simple is SimpleAccount v0.6, safe is Safe with Safe4337Module v0.2.0")
+	aaCmd.PersistentFlags().Int64VarP(&aaSalt, "salt", "", 0, "the salt used by account factory, different salt results in different account address")
+	for _, cmd := range []*cobra.Command{aaDeployAccountCmd, aaSendCmd} {
+		cmd.Flags().StringVarP(&aaPaymasterAndData, "paymaster-and-data", "", "", "the paymasterAndData of user operation, i.e. paymaster address followed by paymaster specific data. if specified, gas is paid by paymaster instead of smart account")
+	}
+
+	aaCmd.AddCommand(aaDeployAccountCmd)
+
+	addFlags(aaCmd, txFlags)
+}
+
+var aaCmd = &cobra.Command{
+	Use:   "aa",
+	Short: "ERC-4337 account abstraction helpers, the smart account is owned by --private-key",
+}
+
+// buildSmartAccount builds smart account owned by --private-key according to --account-type and --salt.
+func buildSmartAccount() ethutil.SmartAccount {
+	if globalOptPrivateKey == "" {
+		log.Fatalf("--private-key (the owner of smart account) is required for aa command")
+	}
+	owner := buildPrivateKeyFromHex(globalOptPrivateKey)
+
+	switch aaAccountType {
+	case aaAccountTypeSimple:
+		return &ethutil.SimpleAccount{Owner: owner, Salt: big.NewInt(aaSalt)}
+	case aaAccountTypeSafe:
+		return &ethutil.Safe4337Account{Owner: owner, Salt: big.NewInt(aaSalt)}
+	default:
+		log.Fatalf("invalid option for --account-type: %v", aaAccountType)
+	}
+	return nil
+}
+
+// userOperationFees returns max priority fee per gas and max fee per gas of user operation, by --max-priority-fee-per-gas
+// and --max-fee-per-gas or estimated.
+func userOperationFees(ctx context.Context, client *ethclient.Client) (*big.Int, *big.Int) {
+	opts := buildTxOptions(nil)
+	maxPriorityFeePerGas, maxFeePerGas := opts.MaxPriorityFeePerGas, opts.MaxFeePerGas
+	if maxPriorityFeePerGas == nil || maxFeePerGas == nil {
+		estimate, err := newGasOracle(client).EstimateFees(ctx)
+		if errors.Is(err, ethutil.ErrEip1559NotSupported) {
+			// both fees are gas price in legacy chain
+			gasPrice, err := getGasPrice(ctx, client)
+			checkErr(err)
+			maxPriorityFeePerGas, maxFeePerGas = gasPrice, gasPrice
+		} else {
+			checkErr(err)
+			if maxPriorityFeePerGas == nil {
+				maxPriorityFeePerGas = estimate.Tip(globalOptSpeed)
+			}
+			if maxFeePerGas == nil {
+				// tolerate base fee doubling before the user operation is bundled
+				maxFeePerGas = new(big.Int).Add(new(big.Int).Mul(estimate.BaseFee, big.NewInt(2)), maxPriorityFeePerGas)
+			}
+		}
+	}
+	return maxPriorityFeePerGas, maxFeePerGas
+}
+
+// buildUserOperation builds user operation of account (sender) with callData and --paymaster-and-data, gas limits
+// are estimated by bundler, then it's signed by owner of account. initCode is empty if account is deployed.
+func buildUserOperation(ctx context.Context, bundler *ethutil.BundlerClient, account ethutil.SmartAccount, sender common.Address, initCode []byte, callData []byte) *ethutil.UserOperation {
+	if !isValidHexString(aaPaymasterAndData) || (aaPaymasterAndData != "" && len(common.FromHex(aaPaymasterAndData)) < common.AddressLength) {
+		log.Fatalf("--paymaster-and-data must be hex string starting with paymaster address")
+	}
+	client := globalClient.EthClient
+	entryPoint := ethutil.EntryPointV06Address
+
+	nonce, err := ethutil.GetUserOpNonce(ctx, client, entryPoint, sender)
+	checkErr(err)
+	maxPriorityFeePerGas, maxFeePerGas := userOperationFees(ctx, client)
+
+	op := &ethutil.UserOperation{
+		Sender:               sender,
+		Nonce:                (*hexutil.Big)(nonce),
+		InitCode:             initCode,
+		CallData:             callData,
+		CallGasLimit:         (*hexutil.Big)(big.NewInt(0)),
+		VerificationGasLimit: (*hexutil.Big)(big.NewInt(0)),
+		PreVerificationGas:   (*hexutil.Big)(big.NewInt(0)),
+		MaxFeePerGas:         (*hexutil.Big)(maxFeePerGas),
+		MaxPriorityFeePerGas: (*hexutil.Big)(maxPriorityFeePerGas),
+		PaymasterAndData:     common.FromHex(aaPaymasterAndData),
+		Signature:            account.DummySignature(),
+	}
+	if op.InitCode == nil {
+		op.InitCode = []byte{}
+	}
+	if op.PaymasterAndData == nil {
+		op.PaymasterAndData = []byte{}
+	}
+
+	gasEstimate, err := bundler.EstimateUserOperationGas(ctx, op)
+	checkErr(err)
+	op.CallGasLimit = gasEstimate.CallGasLimit
+	op.VerificationGasLimit = gasEstimate.VerificationGasLimit
+	op.PreVerificationGas = gasEstimate.PreVerificationGas
+
+	chainID, err := client.ChainID(ctx)
+	checkErr(err)
+	// the user operation calls the smart account with callData
+	checkPolicy(ctx, nil, chainID, &sender, big.NewInt(0), op.CallData)
+	checkTOTP()
+	op.Signature, err = account.Sign(op, entryPoint, chainID)
+	checkErr(err)
+
+	if !globalOptTerseOutput {
+		opJson, err := json.MarshalIndent(op, "", "  ")
+		checkErr(err)
+		log.Printf("user operation = %s", opJson)
+	}
+	return op
+}
+
+// sendUserOperation funds sender by --private-key if its balance is not enough to pay gas (no paymaster is used),
+// then sends op to bundler and waits for it being bundled. It returns nil with --dry-run.
+func sendUserOperation(ctx context.Context, bundler *ethutil.BundlerClient, op *ethutil.UserOperation) *ethutil.UserOperationReceipt {
+	if len(op.PaymasterAndData) == 0 {
+		prefund := op.RequiredPrefund()
+		balance, err := globalClient.EthClient.PendingBalanceAt(ctx, op.Sender)
+		checkErr(err)
+		if balance.Cmp(prefund) < 0 {
+			missing := new(big.Int).Sub(prefund, balance)
+			log.Printf("funding %v ether to smart account %v", wei2Other(bigInt2Decimal(missing), unitEther), op.Sender.Hex())
+			if !globalOptDryRun {
+				tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &op.Sender, missing, nil, nil)
+				checkErr(err)
+				log.Printf("transaction %s finished", tx)
+			}
+		}
+	}
+
+	if globalOptDryRun {
+		return nil
+	}
+
+	userOpHash, err := bundler.SendUserOperation(ctx, op)
+	checkErr(err)
+	log.Printf("user operation %v sent, waiting for it being bundled", userOpHash.Hex())
+
+	receipt, err := bundler.WaitUserOperationReceipt(ctx, userOpHash)
+	checkErr(err)
+	if !receipt.Success {
+		log.Fatalf("user operation %v failed in tx %v, reason: %v", userOpHash.Hex(), receipt.Receipt.TransactionHash.Hex(), receipt.Reason)
+	}
+	return receipt
+}
+
+var aaDeployAccountCmd = &cobra.Command{
+	Use:   "deploy-account",
+	Short: "Deploy smart account counterfactually, the account is funded by --private-key if needed",
+	Long: "Deploy smart account counterfactually. The account address is computed from factory and salt, " +
+		"the account is funded by --private-key if its balance is not enough to pay gas, " +
+		"then the first UserOperation with initCode is sent to bundler to deploy the account.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if aaBundlerUrl == "" {
+			log.Fatalf("--bundler-url is required for deploy-account command")
+		}
+		account := buildSmartAccount()
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+		client := globalClient.EthClient
+		entryPoint := ethutil.EntryPointV06Address
+
+		initCode, err := account.InitCode()
+		checkErr(err)
+		sender, err := ethutil.GetSenderAddress(ctx, client, entryPoint, initCode)
+		checkErr(err)
+		log.Printf("smart account address is %v", sender.Hex())
+
+		isContract, err := ethutil.IsContractAddress(ctx, client, sender)
+		checkErr(err)
+		if isContract {
+			log.Printf("smart account %v is already deployed", sender.Hex())
+			return
+		}
+
+		owner := extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		// the first user operation does nothing but deploying account
+		callData, err := account.ExecuteData(owner, big.NewInt(0), nil)
+		checkErr(err)
+
+		bundler, err := ethutil.DialBundler(ctx, aaBundlerUrl, entryPoint)
+		checkErr(err)
+		defer bundler.Close()
+
+		op := buildUserOperation(ctx, bundler, account, sender, initCode, callData)
+		receipt := sendUserOperation(ctx, bundler, op)
+		if receipt == nil {
+			return
+		}
+		log.Printf("smart account deployed in tx %v", receipt.Receipt.TransactionHash.Hex())
+		fmt.Printf("%v\n", sender.Hex())
+	},
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var aaDepositUnit string
+var aaDepositPaymaster string
+var aaDepositUnstakeDelay uint32
+
+func init() {
+	for _, c := range []*cobra.Command{aaDepositToCmd, aaWithdrawToCmd, aaAddStakeCmd} {
+		c.Flags().StringVarP(&aaDepositUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+	}
+	for _, c := range []*cobra.Command{aaWithdrawToCmd, aaAddStakeCmd, aaUnlockStakeCmd, aaWithdrawStakeCmd} {
+		c.Flags().StringVarP(&aaDepositPaymaster, "paymaster", "", "", "manage deposit or stake of this paymaster (BasePaymaster owned by --private-key) via its wrapper functions, instead of the deposit or stake of --private-key itself")
+	}
+	aaAddStakeCmd.Flags().Uint32VarP(&aaDepositUnstakeDelay, "unstake-delay", "", 86400, "the unstake delay in seconds")
+
+	aaCmd.AddCommand(aaDepositInfoCmd)
+	aaCmd.AddCommand(aaDepositToCmd)
+	aaCmd.AddCommand(aaWithdrawToCmd)
+	aaCmd.AddCommand(aaAddStakeCmd)
+	aaCmd.AddCommand(aaUnlockStakeCmd)
+	aaCmd.AddCommand(aaWithdrawStakeCmd)
+}
+
+// validateAaDepositArgs checks args are n addresses followed by an amount if withAmount, and checks --paymaster.
+func validateAaDepositArgs(n int, names string, withAmount bool) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		var expected = n
+		if withAmount {
+			expected++
+		}
+		if len(args) != expected {
+			return fmt.Errorf("requires %v", names)
+		}
+		for i := range args[:n] {
+			if !isValidAddressArg(cmd.Context(), args[i]) {
+				return fmt.Errorf("%v is not a valid eth address", args[i])
+			}
+		}
+		if withAmount {
+			if _, err := decimal.NewFromString(args[n]); err != nil {
+				return fmt.Errorf("%v is not a valid amount", args[n])
+			}
+		}
+		if aaDepositPaymaster != "" && !isValidAddressArg(cmd.Context(), aaDepositPaymaster) {
+			return fmt.Errorf("--paymaster %v is not a valid eth address", aaDepositPaymaster)
+		}
+		return nil
+	}
+}
+
+// execEntryPointTx sends tx which calls entryPointFunc of EntryPoint, or paymasterFunc of --paymaster if specified.
+func execEntryPointTx(cmd *cobra.Command, value *big.Int, entryPointFunc string, paymasterFunc string, args []string) {
+	if globalOptPrivateKey == "" {
+		log.Fatalf("--private-key is required for %v command", cmd.Name())
+	}
+	log.Printf("Current network is %v", globalOptNode)
+	InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+	to, funcSignature := ethutil.EntryPointV06Address, entryPointFunc
+	if aaDepositPaymaster != "" {
+		to, funcSignature = mustParseAddressArg(cmd.Context(), aaDepositPaymaster), paymasterFunc
+	}
+	data, err := ethutil.BuildTxInputData(funcSignature, args)
+	checkErr(err)
+	if globalOptShowInputData {
+		log.Printf("input data: 0x%x", data)
+	}
+
+	tx, err := Transact(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &to, value, nil, data)
+	checkErr(err)
+	log.Printf("transaction %s finished", tx)
+}
+
+var aaDepositInfoCmd = &cobra.Command{
+	Use:   "deposit-info address",
+	Short: "Show deposit and stake of account or paymaster in EntryPoint",
+	Args:  validateAaDepositArgs(1, "address", false),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		info, err := ethutil.GetDepositInfo(cmd.Context(), globalClient.EthClient, ethutil.EntryPointV06Address, mustParseAddressArg(cmd.Context(), args[0]))
+		checkErr(err)
+
+		fmt.Printf("deposit: %v ether\n", wei2Other(bigInt2Decimal(info.Deposit), unitEther))
+		fmt.Printf("staked: %v\n", info.Staked)
+		fmt.Printf("stake: %v ether\n", wei2Other(bigInt2Decimal(info.Stake), unitEther))
+		fmt.Printf("unstake delay: %v seconds\n", info.UnstakeDelaySec)
+		if info.WithdrawTime > 0 {
+			fmt.Printf("withdraw time: %v\n", time.Unix(int64(info.WithdrawTime), 0).UTC().Format(time.RFC3339))
+		}
+	},
+}
+
+var aaDepositToCmd = &cobra.Command{
+	Use:   "deposit-to address amount",
+	Short: "Deposit to EntryPoint for account or paymaster, the deposit pays gas of user operations",
+	Args:  validateAaDepositArgs(1, "address and amount", true),
+	Run: func(cmd *cobra.Command, args []string) {
+		value := unify2Wei(decimal.RequireFromString(args[1]), aaDepositUnit).BigInt()
+		// anyone can deposit for any address, so paymaster wrapper is not needed
+		execEntryPointTx(cmd, value, "depositTo(address)", "", []string{hexAddressArg(cmd.Context(), args[0])})
+	},
+}
+
+var aaWithdrawToCmd = &cobra.Command{
+	Use:   "withdraw-to recipient amount",
+	Short: "Withdraw deposit of --private-key (or --paymaster) in EntryPoint to recipient",
+	Args:  validateAaDepositArgs(1, "recipient and amount", true),
+	Run: func(cmd *cobra.Command, args []string) {
+		amount := unify2Wei(decimal.RequireFromString(args[1]), aaDepositUnit).BigInt()
+		execEntryPointTx(cmd, big.NewInt(0), "withdrawTo(address,uint256)", "withdrawTo(address,uint256)", []string{hexAddressArg(cmd.Context(), args[0]), amount.String()})
+	},
+}
+
+var aaAddStakeCmd = &cobra.Command{
+	Use:   "add-stake amount",
+	Short: "Add stake of --private-key (or --paymaster) in EntryPoint, staking is required by paymasters and factories",
+	Args:  validateAaDepositArgs(0, "amount", true),
+	Run: func(cmd *cobra.Command, args []string) {
+		value := unify2Wei(decimal.RequireFromString(args[0]), aaDepositUnit).BigInt()
+		delay := strconv.FormatUint(uint64(aaDepositUnstakeDelay), 10)
+		execEntryPointTx(cmd, value, "addStake(uint32)", "addStake(uint32)", []string{delay})
+	},
+}
+
+var aaUnlockStakeCmd = &cobra.Command{
+	Use:   "unlock-stake",
+	Short: "Unlock stake of --private-key (or --paymaster) in EntryPoint, stake can be withdrawn after unstake delay",
+	Args:  validateAaDepositArgs(0, "no args", false),
+	Run: func(cmd *cobra.Command, args []string) {
+		execEntryPointTx(cmd, big.NewInt(0), "unlockStake()", "unlockStake()", nil)
+	},
+}
+
+var aaWithdrawStakeCmd = &cobra.Command{
+	Use:   "withdraw-stake recipient",
+	Short: "Withdraw unlocked stake of --private-key (or --paymaster) in EntryPoint to recipient",
+	Args:  validateAaDepositArgs(1, "recipient", false),
+	Run: func(cmd *cobra.Command, args []string) {
+		execEntryPointTx(cmd, big.NewInt(0), "withdrawStake(address)", "withdrawStake(address)", []string{hexAddressArg(cmd.Context(), args[0])})
+	},
+}
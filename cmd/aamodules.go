@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var aaModulesFromBlock int64
+var aaModulesCandidates []string
+
+func init() {
+	aaModulesCmd.Flags().Int64VarP(&aaModulesFromBlock, "from-block", "", 0, "scan ModuleInstalled and ModuleUninstalled events from this block, should be the deployment block of account if node limits the range of eth_getLogs")
+	aaModulesCmd.Flags().StringSliceVarP(&aaModulesCandidates, "module", "", nil, "also check this module, the format is type:address, type is validator | executor | fallback | hook or 1-4")
+
+	aaCmd.AddCommand(aaModulesCmd)
+}
+
+// parseModuleCandidate parses type:address, e.g. validator:0x...
+func parseModuleCandidate(candidate string) (ethutil.Module, error) {
+	parts := strings.SplitN(candidate, ":", 2)
+	if len(parts) != 2 || !isValidEthAddress(parts[1]) {
+		return ethutil.Module{}, fmt.Errorf("invalid module %v, the format is type:address", candidate)
+	}
+	for typ, name := range ethutil.ModuleTypeNames {
+		if parts[0] == name || parts[0] == strconv.FormatUint(typ, 10) {
+			return ethutil.Module{Type: typ, Address: common.HexToAddress(parts[1])}, nil
+		}
+	}
+	return ethutil.Module{}, fmt.Errorf("invalid module type %v", parts[0])
+}
+
+var aaModulesCmd = &cobra.Command{
+	Use:   "modules account",
+	Short: "List installed validators, executors, fallback handlers and hooks of ERC-7579 modular account",
+	Long: "List installed validators, executors, fallback handlers and hooks of ERC-7579 modular account. " +
+		"ERC-7579 has no enumeration method, so candidates are collected from ModuleInstalled and ModuleUninstalled " +
+		"events (and --module), then confirmed by isModuleInstalled.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one account")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		for _, candidate := range aaModulesCandidates {
+			if _, err := parseModuleCandidate(candidate); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+		client := globalClient.EthClient
+		account := mustParseAddressArg(ctx, args[0])
+
+		accountId, err := ethutil.GetAccountId(ctx, client, account)
+		checkErr(err)
+		if !globalOptTerseOutput {
+			fmt.Printf("account id: %v\n", accountId)
+		}
+
+		candidates, err := ethutil.ModuleCandidates(ctx, client, account, big.NewInt(aaModulesFromBlock))
+		checkErr(err)
+		for _, c := range aaModulesCandidates {
+			module, _ := parseModuleCandidate(c) // validated in Args
+			candidates = append(candidates, module)
+		}
+
+		var installed int
+		var checked = make(map[ethutil.Module]bool)
+		for _, module := range candidates {
+			if checked[module] {
+				continue
+			}
+			checked[module] = true
+
+			ok, err := ethutil.IsModuleInstalled(ctx, client, account, module.Type, module.Address)
+			checkErr(err)
+			if !ok {
+				continue
+			}
+			installed++
+			ethutil.DescribeModule(ctx, client, &module)
+
+			typeName := ethutil.ModuleTypeNames[module.Type]
+			if typeName == "" {
+				typeName = strconv.FormatUint(module.Type, 10)
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v %v\n", typeName, module.Address.Hex())
+				continue
+			}
+			var meta string
+			if module.Name != "" {
+				meta = fmt.Sprintf(" (%v)", strings.TrimSpace(module.Name+" "+module.Version))
+			}
+			fmt.Printf("%-9v %v%v\n", typeName, module.Address.Hex(), meta)
+		}
+		log.Printf("%v installed modules found in %v candidates", installed, len(checked))
+	},
+}
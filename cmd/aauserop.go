@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var aaSendValue string
+var aaSendUnit string
+var aaSendHexData string
+
+func init() {
+	aaSendCmd.Flags().StringVarP(&aaSendValue, "value", "", "0", "the amount of eth sent by smart account, unit is ether and can be changed by --unit")
+	aaSendCmd.Flags().StringVarP(&aaSendUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+	aaSendCmd.Flags().StringVarP(&aaSendHexData, "hex-data", "", "", "the payload hex data of call")
+
+	aaCmd.AddCommand(aaSendCmd)
+	aaCmd.AddCommand(aaReceiptCmd)
+}
+
+var aaSendCmd = &cobra.Command{
+	Use:   "send to-address",
+	Short: "Call to-address from smart account by a UserOperation sent to bundler, the account is deployed in the same operation if needed",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires to-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if !isValidHexString(aaSendHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if _, err := decimal.NewFromString(aaSendValue); err != nil {
+			return fmt.Errorf("%v is not a valid amount", aaSendValue)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if aaBundlerUrl == "" {
+			log.Fatalf("--bundler-url is required for send command")
+		}
+		account := buildSmartAccount()
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+		entryPoint := ethutil.EntryPointV06Address
+
+		accountInitCode, err := account.InitCode()
+		checkErr(err)
+		sender, err := ethutil.GetSenderAddress(ctx, globalClient.EthClient, entryPoint, accountInitCode)
+		checkErr(err)
+		log.Printf("smart account address is %v", sender.Hex())
+
+		isContract, err := ethutil.IsContractAddress(ctx, globalClient.EthClient, sender)
+		checkErr(err)
+		var initCode []byte
+		if !isContract {
+			log.Printf("smart account %v is not deployed, it will be deployed by the user operation", sender.Hex())
+			initCode = accountInitCode
+		}
+
+		value := unify2Wei(decimal.RequireFromString(aaSendValue), aaSendUnit).BigInt()
+		callData, err := account.ExecuteData(mustParseAddressArg(ctx, args[0]), value, common.FromHex(aaSendHexData))
+		checkErr(err)
+
+		bundler, err := ethutil.DialBundler(ctx, aaBundlerUrl, entryPoint)
+		checkErr(err)
+		defer bundler.Close()
+
+		op := buildUserOperation(ctx, bundler, account, sender, initCode, callData)
+		receipt := sendUserOperation(ctx, bundler, op)
+		if receipt == nil {
+			return
+		}
+		log.Printf("user operation %v is included in tx %v", receipt.UserOpHash.Hex(), receipt.Receipt.TransactionHash.Hex())
+		fmt.Printf("%v\n", receipt.Receipt.TransactionHash.Hex())
+	},
+}
+
+var aaReceiptCmd = &cobra.Command{
+	Use:   "receipt user-op-hash",
+	Short: "Show receipt of UserOperation by eth_getUserOperationReceipt of bundler",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires user-op-hash")
+		}
+		_, err := parseHashes(args)
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if aaBundlerUrl == "" {
+			log.Fatalf("--bundler-url is required for receipt command")
+		}
+		bundler, err := ethutil.DialBundler(cmd.Context(), aaBundlerUrl, ethutil.EntryPointV06Address)
+		checkErr(err)
+		defer bundler.Close()
+
+		receipt, err := bundler.GetUserOperationReceipt(cmd.Context(), common.HexToHash(args[0]))
+		checkErr(err)
+		if receipt == nil {
+			log.Fatalf("receipt of user operation %v is not found, it may be pending or unknown to bundler", args[0])
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v %v\n", receipt.Success, receipt.Receipt.TransactionHash.Hex())
+			return
+		}
+		fmt.Printf("success: %v\n", receipt.Success)
+		if !receipt.Success {
+			fmt.Printf("reason: %v\n", receipt.Reason)
+		}
+		fmt.Printf("tx hash: %v\n", receipt.Receipt.TransactionHash.Hex())
+		if receipt.ActualGasCost != nil {
+			fmt.Printf("actual gas cost: %v ether\n", wei2Other(bigInt2Decimal(receipt.ActualGasCost.ToInt()), unitEther))
+		}
+	},
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var abiAddName string
+var abiShowFunctions bool
+
+func init() {
+	abiAddCmd.Flags().StringVarP(&abiAddName, "name", "", "", "the name of contract, it can be used in place of contract address in call, query and abi commands")
+	abiShowCmd.Flags().BoolVarP(&abiShowFunctions, "functions", "", false, "show signatures of functions instead of abi json")
+
+	abiCmd.AddCommand(abiAddCmd)
+	abiCmd.AddCommand(abiListCmd)
+	abiCmd.AddCommand(abiRemoveCmd)
+	abiCmd.AddCommand(abiShowCmd)
+
+	addFlags(abiCmd, explorerFlags)
+}
+
+var abiCmd = &cobra.Command{
+	Use:   "abi",
+	Short: "Manage the local abi directory ~/.ethutil/abi/, abi is keyed by network and contract address",
+	Long: "Manage the local abi directory ~/.ethutil/abi/, abi is keyed by network and contract address. " +
+		"call, query and access-list load abi of contract from it if only function name is given.",
+}
+
+var abiAddCmd = &cobra.Command{
+	Use:   "add contract-address [abi-file]",
+	Short: "Add abi of contract, the abi is fetched from block explorer or Sourcify if abi-file is not given",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("requires contract-address and optional abi-file")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if abiAddName != "" && (isValidEthAddress(abiAddName) || strings.ContainsAny(abiAddName, " /\\")) {
+			return fmt.Errorf("invalid name %v", abiAddName)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		address := mustParseAddressArg(cmd.Context(), args[0])
+
+		var abiContent string
+		if len(args) == 2 {
+			content, err := os.ReadFile(args[1])
+			checkErr(err)
+			abiContent = string(content)
+		} else {
+			source, err := fetchContractSource(cmd.Context(), address)
+			checkErr(err)
+			abiContent = source.Abi
+		}
+		contractAbi, err := abi.JSON(strings.NewReader(abiContent))
+		if err != nil {
+			log.Fatalf("invalid abi: %v", err)
+		}
+
+		if abiAddName != "" {
+			names, err := loadAbiNames()
+			checkErr(err)
+			if other, ok := names[abiAddName]; ok && other != strings.ToLower(address.Hex()) {
+				log.Fatalf("name %v is used by %v, remove it first", abiAddName, other)
+			}
+			names[abiAddName] = strings.ToLower(address.Hex())
+			checkErr(saveAbiNames(names))
+		}
+		checkErr(writeCachedAbi(address, abiContent))
+
+		log.Printf("abi of %v added, %v functions", address.Hex(), len(contractAbi.Methods))
+	},
+}
+
+var abiListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List abi of current network",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		dir := filepath.Dir(abiCacheFile(common.Address{}))
+		entries, err := os.ReadDir(dir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			checkErr(err)
+		}
+		names, err := loadAbiNames()
+		checkErr(err)
+		var nameOfAddress = make(map[string][]string)
+		for name, address := range names {
+			nameOfAddress[address] = append(nameOfAddress[address], name)
+		}
+
+		for _, entry := range entries {
+			if !isValidEthAddress(strings.TrimSuffix(entry.Name(), ".json")) {
+				continue // e.g. names.json
+			}
+			address := common.HexToAddress(strings.TrimSuffix(entry.Name(), ".json"))
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", address.Hex())
+				continue
+			}
+			var functions = "invalid abi"
+			if content, err := os.ReadFile(filepath.Join(dir, entry.Name())); err == nil {
+				if contractAbi, err := abi.JSON(strings.NewReader(string(content))); err == nil {
+					functions = fmt.Sprintf("%v functions", len(contractAbi.Methods))
+				}
+			}
+			contractNames := nameOfAddress[strings.ToLower(address.Hex())]
+			sort.Strings(contractNames)
+			fmt.Printf("%v %-16v %v\n", address.Hex(), strings.Join(contractNames, ","), functions)
+		}
+	},
+}
+
+var abiRemoveCmd = &cobra.Command{
+	Use:   "remove contract-address|name",
+	Short: "Remove abi of contract and its names",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		address := mustResolveAbiName(args[0])
+
+		names, err := loadAbiNames()
+		checkErr(err)
+		for name, other := range names {
+			if other == strings.ToLower(address.Hex()) {
+				delete(names, name)
+			}
+		}
+		checkErr(saveAbiNames(names))
+
+		if err := os.Remove(abiCacheFile(address)); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				log.Fatalf("abi of %v is not found", address.Hex())
+			}
+			checkErr(err)
+		}
+		log.Printf("abi of %v removed", address.Hex())
+	},
+}
+
+var abiShowCmd = &cobra.Command{
+	Use:   "show contract-address|name",
+	Short: "Show abi of contract, the abi is fetched and added if it's not found",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		address := mustResolveAbiName(args[0])
+
+		abiContent, err := loadAbiByAddress(cmd.Context(), address)
+		checkErr(err)
+		if !abiShowFunctions {
+			fmt.Printf("%v\n", abiContent)
+			return
+		}
+
+		contractAbi, err := abi.JSON(strings.NewReader(abiContent))
+		checkErr(err)
+		var methods []string
+		for _, method := range contractAbi.Methods {
+			methods = append(methods, method.String())
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			fmt.Printf("%v\n", method)
+		}
+	},
+}
+
+// abiNamesFile returns ~/.ethutil/abi/<node>/names.json, which maps name to contract address.
+func abiNamesFile() string {
+	return filepath.Join(filepath.Dir(abiCacheFile(common.Address{})), "names.json")
+}
+
+// loadAbiNames loads names of current network, an empty map is returned if no name is added.
+func loadAbiNames() (map[string]string, error) {
+	var names = make(map[string]string)
+	content, err := os.ReadFile(abiNamesFile())
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return names, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &names); err != nil {
+		return nil, fmt.Errorf("parse %v fail: %w", abiNamesFile(), err)
+	}
+	return names, nil
+}
+
+func saveAbiNames(names map[string]string) error {
+	content, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(abiNamesFile()), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(abiNamesFile(), content, 0600)
+}
+
+// resolveAbiName returns contract address of name added by abi add, nameOrAddress is returned unchanged if it's an
+// address or an unknown name.
+func resolveAbiName(nameOrAddress string) string {
+	if isValidEthAddress(nameOrAddress) {
+		return nameOrAddress
+	}
+	names, err := loadAbiNames()
+	if err != nil {
+		log.Printf("load abi names fail: %v", err)
+		return nameOrAddress
+	}
+	if address, ok := names[nameOrAddress]; ok {
+		return address
+	}
+	return nameOrAddress
+}
+
+func mustResolveAbiName(nameOrAddress string) common.Address {
+	address := resolveAbiName(nameOrAddress)
+	if !isValidEthAddress(address) {
+		log.Fatalf("%v is neither a valid eth address nor a name added by abi add", nameOrAddress)
+	}
+	return common.HexToAddress(address)
+}
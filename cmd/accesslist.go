@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var accessListABIFile string
+var accessListTransferUnit string
+var accessListTransferAmt string
+var accessListFrom string
+var accessListSend bool
+
+func init() {
+	accessListCmd.Flags().StringVarP(&accessListABIFile, "abi-file", "", "", "the path of abi file, if this option specified, 'function signature' can be just function name")
+	accessListCmd.Flags().StringVarP(&accessListTransferUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+	accessListCmd.Flags().StringVarP(&accessListTransferAmt, "value", "", "0", "the amount you want to transfer when call contract, unit is ether and can be changed by --unit")
+	accessListCmd.Flags().StringVarP(&accessListFrom, "from", "", "", "the caller address, default is the address of --private-key")
+	accessListCmd.Flags().BoolVarP(&accessListSend, "send", "", false, "send tx with the generated access list attached, eip2930 tx is sent if --tx-type is eip155")
+
+	addFlags(accessListCmd, txFlags)
+	addFlags(accessListCmd, explorerFlags)
+}
+
+var accessListCmd = &cobra.Command{
+	Use:   "access-list contract-address 'function signature' arg1 arg2 ...",
+	Short: "Create eip2930 access list for contract method call, and optionally send tx with it",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("requires contract-address and function signature")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if accessListFrom != "" && !isValidAddressArg(cmd.Context(), accessListFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", accessListFrom)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if accessListSend && globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required when --send specified")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		contract := mustParseAddressArg(cmd.Context(), args[0])
+		funcSignature := args[1]
+		funcSignature, err := resolveFuncSignature(cmd.Context(), contract, funcSignature, accessListABIFile)
+		checkErr(err)
+		txInputData, err := ethutil.BuildTxInputData(funcSignature, args[2:])
+		checkErr(err)
+
+		var fromAddress common.Address
+		if accessListFrom != "" {
+			fromAddress = mustParseAddressArg(cmd.Context(), accessListFrom)
+		} else if globalOptPrivateKey != "" {
+			fromAddress = extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		}
+
+		var valueInWei = unify2Wei(decimal.RequireFromString(accessListTransferAmt), accessListTransferUnit)
+
+		accessList, gasUsed, err := ethutil.CreateAccessList(cmd.Context(), globalClient.RpcClient, fromAddress, &contract, valueInWei.BigInt(), txInputData)
+		checkErr(err)
+
+		output, err := json.MarshalIndent(accessList, "", "  ")
+		checkErr(err)
+		fmt.Printf("%s\n", output)
+		if !globalOptTerseOutput {
+			fmt.Printf("gas used with access list: %v\n", gasUsed)
+		}
+
+		if !accessListSend {
+			return
+		}
+
+		if globalOptTxType == txTypeEip155 {
+			// eip155 tx can not carry access list
+			globalOptTxType = txTypeEip2930
+		}
+		tx, err := TransactWithAccessList(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &contract, valueInWei.BigInt(), nil, txInputData, accessList)
+		checkErr(err)
+
+		log.Printf("transaction %s finished", tx)
+	},
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var accountShowProxy bool
+
+func init() {
+	accountCmd.Flags().BoolVarP(&accountShowProxy, "proxy", "", false, "also print the EIP-1967 implementation, admin and beacon slots of contract")
+
+	addFlags(accountCmd, blockFlags)
+	addFlags(accountCmd, fiatFlags)
+}
+
+var accountCmd = &cobra.Command{
+	Use:   "account address",
+	Short: "Inspect an account, print its balance, nonce, code size, code hash and whether it's a contract",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+
+		blockNumber := stateBlock(ctx)
+
+		address := mustParseAddressArg(ctx, args[0])
+		state, err := ethutil.GetAccountState(ctx, globalClient.EthClient, address, blockNumber)
+		checkErr(err)
+
+		fmt.Printf("address: %v\n", state.Address.Hex())
+		fmt.Printf("balance: %v ether%v\n", wei2Other(bigInt2Decimal(state.Balance), unitEther), fiatSuffix(ctx, state.Balance))
+		fmt.Printf("nonce: %v\n", state.Nonce)
+		fmt.Printf("contract: %v\n", state.IsContract())
+		fmt.Printf("code size: %v\n", state.CodeSize)
+		fmt.Printf("code hash: %v\n", state.CodeHash.Hex())
+
+		if accountShowProxy && state.IsContract() {
+			for _, slot := range []struct {
+				name string
+				slot common.Hash
+			}{
+				{"implementation", ethutil.Eip1967ImplementationSlot},
+				{"admin", ethutil.Eip1967AdminSlot},
+				{"beacon", ethutil.Eip1967BeaconSlot},
+			} {
+				addr, err := ethutil.GetAddressInSlot(ctx, globalClient.EthClient, address, slot.slot, blockNumber)
+				checkErr(err)
+				if addr == (common.Address{}) {
+					fmt.Printf("eip1967 %v: none\n", slot.name)
+				} else {
+					fmt.Printf("eip1967 %v: %v\n", slot.name, addr.Hex())
+				}
+			}
+		}
+	},
+}
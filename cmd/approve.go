@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var approveExpiresIn time.Duration
+var approveOutput string
+
+func init() {
+	approveCmd.Flags().DurationVarP(&approveExpiresIn, "expires-in", "", 24*time.Hour, "the approval expires after this duration, 0 means never expire")
+	approveCmd.Flags().StringVarP(&approveOutput, "output", "o", "", "the approval file, default is <tx-hash>.approval.json")
+
+	addFlags(approveCmd, txFlags, "totp-file")
+}
+
+// checkApproval exits if tx requires approval (--approvers is specified, and tx has input data or its value reaches
+// --approval-threshold) but none of --approval-file approves it.
+func checkApproval(signedTx *types.Transaction) {
+	if len(globalOptApprovers) == 0 {
+		return
+	}
+	var threshold *big.Int
+	if globalOptApprovalThreshold != "" {
+		threshold = unify2Wei(decimal.RequireFromString(globalOptApprovalThreshold), unitEther).BigInt()
+	}
+	if !ethutil.ApprovalRequired(signedTx, threshold) {
+		return
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+	checkErr(err)
+	var approvers []common.Address
+	for _, approver := range globalOptApprovers {
+		approvers = append(approvers, common.HexToAddress(approver))
+	}
+
+	for _, file := range globalOptApprovalFiles {
+		content, err := os.ReadFile(file)
+		checkErr(err)
+		var approval ethutil.Approval
+		if err := json.Unmarshal(content, &approval); err != nil {
+			log.Fatalf("parse approval file %v fail: %v", file, err)
+		}
+		if approval.TxHash != signedTx.Hash() {
+			continue // approval of other tx
+		}
+		if err := approval.Verify(signedTx.Hash(), sender, approvers, time.Now()); err != nil {
+			log.Fatalf("approval file %v is invalid: %v", file, err)
+		}
+		log.Printf("tx %v is approved by %v", signedTx.Hash().Hex(), approval.Approver.Hex())
+		return
+	}
+
+	rawTx, err := ethutil.GenRawTx(signedTx)
+	checkErr(err)
+	log.Fatalf("tx %v requires approval of another operator. ask one of the approvers to run `ethutil approve %v`, "+
+		"then broadcast it by `ethutil send-raw --approval-file <approval-file> %v`", signedTx.Hash().Hex(), rawTx, rawTx)
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve signed-tx",
+	Short: "Approve signed tx with --private-key as the second operator, the approval file is required to broadcast it when --approvers is specified",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires signed-tx")
+		}
+		if !isValidHexString(args[0]) {
+			return fmt.Errorf("signed-tx must hex string")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for approve command")
+		}
+		signedTx, err := ethutil.ParseRawTx(args[0])
+		checkErr(err)
+		sender, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+		checkErr(err)
+
+		var to = "contract creation"
+		if signedTx.To() != nil {
+			to = signedTx.To().Hex()
+		}
+		log.Printf("tx %v: chain id %v, from %v, to %v, value %v ether, nonce %v, input data %v bytes",
+			signedTx.Hash().Hex(), signedTx.ChainId(), sender.Hex(), to, wei2Other(bigInt2Decimal(signedTx.Value()), unitEther),
+			signedTx.Nonce(), len(signedTx.Data()))
+
+		checkTOTP()
+		var expiresAt int64
+		if approveExpiresIn > 0 {
+			expiresAt = time.Now().Add(approveExpiresIn).Unix()
+		}
+		approval, err := ethutil.NewApproval(signedTx.Hash(), expiresAt, buildPrivateKeyFromHex(globalOptPrivateKey))
+		checkErr(err)
+		if approval.Approver == sender {
+			log.Fatalf("approver %v is sender of tx, approval must come from another operator", sender.Hex())
+		}
+
+		content, err := json.MarshalIndent(approval, "", "  ")
+		checkErr(err)
+		output := approveOutput
+		if output == "" {
+			output = signedTx.Hash().Hex() + ".approval.json"
+		}
+		checkErr(os.WriteFile(output, append(content, '\n'), 0644))
+		fmt.Printf("%v\n", output)
+	},
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var arbRetryableL2Rpc string
+var arbRetryableInbox string
+var arbRetryableL2CallValue string
+var arbRetryableL2GasLimit uint64
+var arbRetryableL2MaxFeePerGas string
+var arbRetryableFeeMargin int64
+var arbRetryableRefundAddress string
+
+// arbInboxMap is the delayed inbox of Arbitrum One and Arbitrum Sepolia on their L1.
+var arbInboxMap = map[string]string{
+	nodeMainnet: "0x4Dbd4fc535Ac27206064B68FfCf827b0A60BAB3f",
+	nodeSepolia: "0xaAe29B0366299461418F5324a79Afc425BE5ae21",
+}
+
+func init() {
+	arbRetryableCmd.PersistentFlags().StringVarP(&arbRetryableL2Rpc, "l2-rpc", "", "", "the node url of Arbitrum chain (L2)")
+
+	arbRetryableCreateCmd.Flags().StringVarP(&arbRetryableInbox, "inbox", "", "", "the Inbox contract on L1, default is the inbox of Arbitrum One on mainnet and Arbitrum Sepolia on sepolia")
+	arbRetryableCreateCmd.Flags().StringVarP(&arbRetryableL2CallValue, "l2-call-value", "", "0", "the value sent to destination on L2, unit is ether")
+	arbRetryableCreateCmd.Flags().Uint64VarP(&arbRetryableL2GasLimit, "l2-gas-limit", "", 0, "the gas limit of auto redeem on L2, 0 means estimated by NodeInterface of --l2-rpc")
+	arbRetryableCreateCmd.Flags().StringVarP(&arbRetryableL2MaxFeePerGas, "l2-max-fee-per-gas", "", "", "the max fee per gas of auto redeem on L2, unit is gwei, default is twice the gas price of --l2-rpc")
+	arbRetryableCreateCmd.Flags().Int64VarP(&arbRetryableFeeMargin, "submission-fee-margin", "", 30, "the percentage added to submission fee, in case L1 base fee rises before the tx is mined")
+	arbRetryableCreateCmd.Flags().StringVarP(&arbRetryableRefundAddress, "refund-address", "", "", "the L2 address receiving excess fee and call value if ticket is not redeemed, default is the sender")
+
+	arbRetryableCmd.AddCommand(arbRetryableCreateCmd)
+	arbRetryableCmd.AddCommand(arbRetryableStatusCmd)
+	arbRetryableCmd.AddCommand(arbRetryableRedeemCmd)
+
+	addFlags(arbRetryableCmd, txFlags)
+}
+
+var arbRetryableCmd = &cobra.Command{
+	Use:   "arb-retryable",
+	Short: "Create, inspect and redeem Arbitrum retryable tickets",
+	Long: "Create, inspect and redeem Arbitrum retryable tickets, i.e. L1 to L2 messages of Arbitrum chains. A ticket is\n" +
+		"redeemed automatically on L2 if its gas limit and max fee per gas are enough, otherwise it must be redeemed\n" +
+		"manually before timeout (7 days), or the L2 call is lost.",
+}
+
+// dialArbL2 connects to --l2-rpc.
+func dialArbL2(ctx context.Context) *ethutil.Client {
+	checkNetworkAllowed("connecting L2 node " + arbRetryableL2Rpc)
+	l2Client, err := ethutil.Dial(ctx, arbRetryableL2Rpc)
+	checkErr(err)
+	return l2Client
+}
+
+var arbRetryableCreateCmd = &cobra.Command{
+	Use:   "create to [hex-data]",
+	Short: "Create a retryable ticket calling to on L2 with hex-data, the submission fee and gas are computed",
+	Long: "Create a retryable ticket by Inbox.createRetryableTicket on L1 (current --node). The submission fee is\n" +
+		"computed from the current L1 base fee plus --submission-fee-margin, the L2 gas limit is estimated by --l2-rpc\n" +
+		"unless --l2-gas-limit is specified, and the tx value covers l2 call value, submission fee and L2 gas.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("requires to and optional hex-data")
+		}
+		if arbRetryableL2Rpc == "" {
+			return fmt.Errorf("--l2-rpc is required")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if len(args) == 2 && !isValidHexString(args[1]) {
+			return fmt.Errorf("%v is not a valid hex string", args[1])
+		}
+		for _, address := range []string{arbRetryableInbox, arbRetryableRefundAddress} {
+			if address != "" && !isValidAddressArg(cmd.Context(), address) {
+				return fmt.Errorf("%v is not a valid eth address", address)
+			}
+		}
+		if _, err := decimal.NewFromString(arbRetryableL2CallValue); err != nil {
+			return fmt.Errorf("--l2-call-value %v is not a valid number", arbRetryableL2CallValue)
+		}
+		if arbRetryableL2MaxFeePerGas != "" {
+			if _, err := decimal.NewFromString(arbRetryableL2MaxFeePerGas); err != nil {
+				return fmt.Errorf("--l2-max-fee-per-gas %v is not a valid number", arbRetryableL2MaxFeePerGas)
+			}
+		}
+		if arbRetryableFeeMargin < 0 {
+			return fmt.Errorf("--submission-fee-margin must not be negative")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for arb-retryable create command")
+		}
+		inbox := hexAddressArg(ctx, arbRetryableInbox)
+		if inbox == "" {
+			var found bool
+			if inbox, found = arbInboxMap[globalOptNode]; !found {
+				log.Fatalf("--inbox is required on network %v", globalOptNode)
+			}
+		}
+		to := mustParseAddressArg(ctx, args[0])
+		var data []byte
+		if len(args) == 2 {
+			data = hexutil.MustDecode(args[1])
+		}
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		sender := extractAddressFromPrivateKey(privateKey)
+		refundAddress := sender
+		if arbRetryableRefundAddress != "" {
+			refundAddress = mustParseAddressArg(ctx, arbRetryableRefundAddress)
+		}
+		l2CallValue := unify2Wei(decimal.RequireFromString(arbRetryableL2CallValue), unitEther).BigInt()
+
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+		l2Client := dialArbL2(ctx)
+		defer l2Client.Close()
+
+		header, err := globalClient.EthClient.HeaderByNumber(ctx, nil)
+		checkErr(err)
+		if header.BaseFee == nil {
+			log.Fatalf("L1 block %v has no base fee", header.Number)
+		}
+		submissionFee := ethutil.RetryableSubmissionFee(len(data), header.BaseFee)
+		submissionFee.Add(submissionFee, new(big.Int).Div(new(big.Int).Mul(submissionFee, big.NewInt(arbRetryableFeeMargin)), big.NewInt(100)))
+
+		gasLimit := arbRetryableL2GasLimit
+		if gasLimit == 0 {
+			gasLimit, err = ethutil.EstimateRetryableGas(ctx, l2Client, sender, to, l2CallValue, refundAddress, data)
+			checkErr(err)
+		}
+		var maxFeePerGas *big.Int
+		if arbRetryableL2MaxFeePerGas != "" {
+			maxFeePerGas = unify2Wei(decimal.RequireFromString(arbRetryableL2MaxFeePerGas), unitGwei).BigInt()
+		} else {
+			l2GasPrice, err := l2Client.EthClient.SuggestGasPrice(ctx)
+			checkErr(err)
+			maxFeePerGas = new(big.Int).Mul(l2GasPrice, big.NewInt(2))
+		}
+		l2GasCost := new(big.Int).Mul(new(big.Int).SetUint64(gasLimit), maxFeePerGas)
+		value := new(big.Int).Add(l2CallValue, submissionFee)
+		value.Add(value, l2GasCost)
+
+		log.Printf("L1 base fee = %v gwei", wei2Other(decimal.NewFromBigInt(header.BaseFee, 0), unitGwei))
+		log.Printf("max submission fee = %v ether", wei2Other(decimal.NewFromBigInt(submissionFee, 0), unitEther))
+		log.Printf("L2 gas limit = %v, max fee per gas = %v gwei, L2 gas cost = %v ether", gasLimit,
+			wei2Other(decimal.NewFromBigInt(maxFeePerGas, 0), unitGwei), wei2Other(decimal.NewFromBigInt(l2GasCost, 0), unitEther))
+		log.Printf("L2 call value = %v ether, total value = %v ether", wei2Other(decimal.NewFromBigInt(l2CallValue, 0), unitEther),
+			wei2Other(decimal.NewFromBigInt(value, 0), unitEther))
+
+		txData, err := ethutil.BuildTxInputData("createRetryableTicket(address,uint256,uint256,address,address,uint256,uint256,bytes)", []string{
+			to.Hex(), l2CallValue.String(), submissionFee.String(), refundAddress.Hex(), refundAddress.Hex(),
+			fmt.Sprint(gasLimit), maxFeePerGas.String(), hexutil.Encode(data),
+		})
+		checkErr(err)
+		inboxAddress := common.HexToAddress(inbox)
+		tx, err := Transact(ctx, globalClient, privateKey, &inboxAddress, value, nil, txData)
+		checkErr(err)
+		log.Printf("transaction %s finished", tx)
+		if globalOptDryRun || transferNotCheck {
+			return
+		}
+
+		receipt, err := globalClient.EthClient.TransactionReceipt(ctx, common.HexToHash(tx))
+		checkErr(err)
+		tickets, err := ethutil.ParseRetryableTickets(receipt)
+		checkErr(err)
+		l2ChainId, err := l2Client.EthClient.ChainID(ctx)
+		checkErr(err)
+		for _, ticket := range tickets {
+			log.Printf("retryable ticket %v created, check it by arb-retryable status %v", ticket.TicketId(l2ChainId).Hex(), tx)
+		}
+	},
+}
+
+// printRetryableStatus prints status of ticket.
+func printRetryableStatus(ticketId common.Hash, status *ethutil.RetryableStatus) {
+	var autoRedeemTx, timeout string
+	if status.AutoRedeemTx != nil {
+		autoRedeemTx = status.AutoRedeemTx.Hex()
+	}
+	if status.Timeout > 0 {
+		timeout = time.Unix(int64(status.Timeout), 0).UTC().Format(time.RFC3339)
+	}
+	if printJSONL(map[string]any{
+		"ticket_id":        ticketId.Hex(),
+		"status":           status.Status,
+		"auto_redeem_tx":   autoRedeemTx,
+		"auto_redeem_ok":   status.AutoRedeemOk,
+		"redeem_before":    timeout,
+		"creation_success": status.CreationReceipt != nil,
+	}) {
+		return
+	}
+	if globalOptTerseOutput {
+		fmt.Printf("%v %v\n", ticketId.Hex(), status.Status)
+		return
+	}
+	fmt.Printf("ticket id: %v\n", ticketId.Hex())
+	fmt.Printf("status: %v\n", status.Status)
+	if status.CreationReceipt != nil {
+		switch {
+		case status.AutoRedeemTx == nil:
+			fmt.Printf("auto redeem: not scheduled, the L2 gas limit or max fee per gas is too low\n")
+		case status.AutoRedeemOk:
+			fmt.Printf("auto redeem: %v succeeded\n", autoRedeemTx)
+		default:
+			fmt.Printf("auto redeem: %v failed\n", autoRedeemTx)
+		}
+	}
+	if timeout != "" {
+		fmt.Printf("redeem before: %v\n", timeout)
+	}
+}
+
+var arbRetryableStatusCmd = &cobra.Command{
+	Use:   "status l1-tx-hash",
+	Short: "Show status of retryable tickets created by L1 tx, including whether auto redeem failed",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires l1-tx-hash")
+		}
+		if arbRetryableL2Rpc == "" {
+			return fmt.Errorf("--l2-rpc is required")
+		}
+		if !isValidHexString(args[0]) || len(args[0]) != 66 {
+			return fmt.Errorf("%v is not a valid tx hash", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+		l2Client := dialArbL2(ctx)
+		defer l2Client.Close()
+
+		receipt, err := globalClient.EthClient.TransactionReceipt(ctx, common.HexToHash(args[0]))
+		checkErr(err)
+		tickets, err := ethutil.ParseRetryableTickets(receipt)
+		checkErr(err)
+		if len(tickets) == 0 {
+			log.Fatalf("no retryable ticket is created by tx %v", args[0])
+		}
+		l2ChainId, err := l2Client.EthClient.ChainID(ctx)
+		checkErr(err)
+		for _, ticket := range tickets {
+			ticketId := ticket.TicketId(l2ChainId)
+			status, err := ethutil.GetRetryableStatus(ctx, l2Client, ticketId)
+			checkErr(err)
+			printRetryableStatus(ticketId, status)
+			if status.Status == ethutil.RetryableRedeemable && !globalOptTerseOutput {
+				log.Printf("auto redeem of ticket %v failed, redeem it by arb-retryable redeem %v", ticketId.Hex(), ticketId.Hex())
+			}
+		}
+	},
+}
+
+var arbRetryableRedeemCmd = &cobra.Command{
+	Use:   "redeem ticket-id",
+	Short: "Redeem retryable ticket on L2 manually, e.g. after auto redeem failed",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires ticket-id")
+		}
+		if arbRetryableL2Rpc == "" {
+			return fmt.Errorf("--l2-rpc is required")
+		}
+		if !isValidHexString(args[0]) || len(args[0]) != 66 {
+			return fmt.Errorf("%v is not a valid ticket id", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for arb-retryable redeem command")
+		}
+		l2Client := dialArbL2(ctx)
+		defer l2Client.Close()
+
+		ticketId := common.HexToHash(args[0])
+		status, err := ethutil.GetRetryableStatus(ctx, l2Client, ticketId)
+		checkErr(err)
+		if status.Status != ethutil.RetryableRedeemable {
+			log.Fatalf("ticket %v is %v, it can not be redeemed", ticketId.Hex(), status.Status)
+		}
+
+		txData, err := ethutil.BuildTxInputData("redeem(bytes32)", []string{ticketId.Hex()})
+		checkErr(err)
+		tx, err := Transact(ctx, l2Client, buildPrivateKeyFromHex(globalOptPrivateKey), &ethutil.ArbRetryableTxAddress, big.NewInt(0), nil, txData)
+		checkErr(err)
+		log.Printf("transaction %s finished", tx)
+	},
+}
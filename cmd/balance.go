@@ -29,6 +29,10 @@ func init() {
 	balanceCmd.Flags().StringVarP(&balanceSortOpt, "sort", "s", "no", "no | asc | desc, sort result")
 	balanceCmd.Flags().StringVarP(&balanceUnit, "unit", "u", "ether", "wei | gwei | ether, unit of balance")
 	balanceCmd.Flags().StringVarP(&balanceInputFile, "input-file", "f", "", "read address from this file, file - means read stdin")
+
+	addFlags(balanceCmd, blockFlags)
+	addFlags(balanceCmd, fiatFlags)
+	addFlags(balanceCmd, exportFlags)
 }
 
 func validationBalanceCmdOpts() bool {
@@ -48,15 +52,33 @@ func validationBalanceCmdOpts() bool {
 
 var addresses []string
 
+// printBalance prints balance of addr in --unit, by --format if specified.
+func printBalance(ctx context.Context, addr string, balance *big.Int) {
+	result := &BalanceResult{Address: common.HexToAddress(addr), Balance: wei2Other(bigInt2Decimal(balance), balanceUnit).String(), BalanceWei: balance}
+	if printFormatted(result) || printJSONL(result) {
+		return
+	}
+	if globalOptTerseOutput {
+		fmt.Printf("%v %s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String())
+	} else {
+		fmt.Printf("addr %v, balance %s %s%s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String(), balanceUnit, fiatSuffix(ctx, balance))
+	}
+}
+
 var balanceCmd = &cobra.Command{
 	Use:   "balance [eth-address1 eth-address2 ...]",
 	Short: "Check eth balance for address",
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) == 0 && len(balanceInputFile) == 0 {
+		if len(args) == 0 && len(balanceInputFile) == 0 && !globalOptStdin {
 			return fmt.Errorf("requires an address at least or specify -f option")
 		}
 
-		if len(balanceInputFile) > 0 {
+		if globalOptStdin {
+			// all addresses are read before querying, as they are queried by multicall in batch
+			forEachInput(nil, []string{jsonlKeyAddress}, func(address string) {
+				addresses = append(addresses, address)
+			})
+		} else if len(balanceInputFile) > 0 {
 			var inputReader = cmd.InOrStdin()
 			if balanceInputFile != "-" {
 				// read from regular file
@@ -79,9 +101,9 @@ var balanceCmd = &cobra.Command{
 		}
 
 		// Validate each address
-		for _, address := range addresses {
-			if !isValidEthAddress(address) {
-				return fmt.Errorf("%v is not a valid eth address", address)
+		for i := range addresses {
+			if !isValidAddressArg(cmd.Context(), addresses[i]) {
+				return fmt.Errorf("%v is not a valid eth address", addresses[i])
 			}
 		}
 
@@ -97,9 +119,9 @@ var balanceCmd = &cobra.Command{
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		type kv struct {
 			addr    string
@@ -109,42 +131,22 @@ var balanceCmd = &cobra.Command{
 		var results []kv
 		var finishOutput = false
 
-		if isMulticallDeployed(globalClient.EthClient) {
-			balances, err := queryEthBalancesByMulticall(addresses)
-			checkErr(err)
-
-			for index, balance := range balances {
-				addr := addresses[index]
+		block := stateBlock(ctx)
+		hexAddresses := make([]string, len(addresses))
+		for i, address := range addresses {
+			hexAddresses[i] = hexAddressArg(ctx, address)
+		}
+		balances, err := queryEthBalances(ctx, hexAddresses, block)
+		checkErr(err)
+		for index, balance := range balances {
+			addr := hexAddresses[index]
 
-				results = append(results, kv{addr, *balance})
+			results = append(results, kv{addr, *balance})
 
-				// print output immediately if no sort demand
-				if balanceSortOpt == sortNo {
-					if globalOptTerseOutput {
-						fmt.Printf("%v %s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String())
-					} else {
-						fmt.Printf("addr %v, balance %s %s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String(), balanceUnit)
-					}
-					finishOutput = true
-				}
-			}
-		} else {
-			for _, addr := range addresses {
-				// check balance one by one
-				balance, err := globalClient.EthClient.BalanceAt(ctx, common.HexToAddress(addr), nil)
-				checkErr(err)
-
-				results = append(results, kv{addr, *balance})
-
-				// print output immediately if no sort demand
-				if balanceSortOpt == sortNo {
-					if globalOptTerseOutput {
-						fmt.Printf("%v %s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String())
-					} else {
-						fmt.Printf("addr %v, balance %s %s\n", addr, wei2Other(bigInt2Decimal(balance), balanceUnit).String(), balanceUnit)
-					}
-					finishOutput = true
-				}
+			// print output immediately if no sort demand
+			if balanceSortOpt == sortNo && globalOptExport == "" {
+				printBalance(ctx, addr, balance)
+				finishOutput = true
 			}
 		}
 
@@ -158,13 +160,19 @@ var balanceCmd = &cobra.Command{
 			})
 		}
 
+		table := newExportTable(exportColumn{"address", columnAddress}, exportColumn{"balance_wei", columnUint256})
+		for _, result := range results {
+			balance := result.balance
+			table.Append(result.addr, &balance)
+		}
+		appendFiatColumn(ctx, table, "balance_wei", nil)
+		if exportResults(table) {
+			return
+		}
+
 		if !finishOutput {
 			for _, result := range results {
-				if globalOptTerseOutput {
-					fmt.Printf("%v %s\n", result.addr, wei2Other(bigInt2Decimal(&result.balance), balanceUnit).String())
-				} else {
-					fmt.Printf("addr %v, balance %s %s\n", result.addr, wei2Other(bigInt2Decimal(&result.balance), balanceUnit).String(), balanceUnit)
-				}
+				printBalance(ctx, result.addr, &result.balance)
 			}
 			finishOutput = true
 		}
@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var benchQps float64
+var benchDuration time.Duration
+var benchConcurrency int
+var benchRequestTimeout time.Duration
+var benchMix []string
+var benchLogsRange uint64
+var benchCallTo string
+var benchCallData string
+var benchTraceMethod string
+
+// benchCallNames are the kinds of call supported by --mix
+var benchCallNames = []string{"block-number", "get-balance", "call", "get-logs", "trace"}
+
+func init() {
+	benchCmd.Flags().Float64VarP(&benchQps, "qps", "", 10, "the target number of calls per second")
+	benchCmd.Flags().DurationVarP(&benchDuration, "duration", "", 30*time.Second, "how long to run against each endpoint")
+	benchCmd.Flags().IntVarP(&benchConcurrency, "concurrency", "", 32, "max in-flight calls, achieved qps is lower than --qps if it's exhausted")
+	benchCmd.Flags().DurationVarP(&benchRequestTimeout, "request-timeout", "", 10*time.Second, "timeout of each call, a timed out call is counted as error")
+	benchCmd.Flags().StringSliceVarP(&benchMix, "mix", "", []string{"block-number:1", "get-balance:2", "call:4", "get-logs:2"},
+		"the mix of calls, the format is name:weight, name is "+strings.Join(benchCallNames, " | "))
+	benchCmd.Flags().Uint64VarP(&benchLogsRange, "logs-range", "", 10, "the number of blocks queried by each eth_getLogs, the range is randomly picked in recent 1000 blocks")
+	benchCmd.Flags().StringVarP(&benchCallTo, "call-to", "", "0x0000000000000000000000000000000000000000", "the contract of eth_call")
+	benchCmd.Flags().StringVarP(&benchCallData, "call-data", "", "0x", "the input data of eth_call")
+	benchCmd.Flags().StringVarP(&benchTraceMethod, "trace-method", "", "debug_traceBlockByNumber", "debug_traceBlockByNumber | trace_block, the method of trace calls, it traces a recent block")
+}
+
+// parseBenchMix parses name:weight items of --mix
+func parseBenchMix(mix []string) (map[string]int, error) {
+	var weights = make(map[string]int)
+	for _, item := range mix {
+		parts := strings.SplitN(item, ":", 2)
+		if len(parts) != 2 || !contains(benchCallNames, parts[0]) {
+			return nil, fmt.Errorf("invalid mix %v, the format is name:weight, name is %v", item, strings.Join(benchCallNames, " | "))
+		}
+		weight, err := strconv.Atoi(parts[1])
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight in mix %v", item)
+		}
+		weights[parts[0]] = weight
+	}
+	return weights, nil
+}
+
+// buildBenchCalls builds calls of --mix, params of calls depending on block number are picked in recent 1000 blocks
+// before latestBlock. callTo is the hex address of --call-to.
+func buildBenchCalls(weights map[string]int, latestBlock uint64, callTo string) []ethutil.BenchCall {
+	var randomBlock = func() uint64 {
+		offset := uint64(rand.Int63n(1000))
+		if offset > latestBlock {
+			return latestBlock
+		}
+		return latestBlock - offset
+	}
+	var randomAddress = func() string {
+		var addr common.Address
+		rand.Read(addr[:])
+		return addr.Hex()
+	}
+
+	var calls []ethutil.BenchCall
+	for _, name := range benchCallNames {
+		weight := weights[name]
+		if weight == 0 {
+			continue
+		}
+		var call = ethutil.BenchCall{Name: name, Weight: weight}
+		switch name {
+		case "block-number":
+			call.Method = "eth_blockNumber"
+		case "get-balance":
+			call.Method = "eth_getBalance"
+			call.Params = func() []any { return []any{randomAddress(), "latest"} }
+		case "call":
+			call.Method = "eth_call"
+			call.Params = func() []any {
+				return []any{map[string]any{"to": callTo, "data": benchCallData}, "latest"}
+			}
+		case "get-logs":
+			call.Method = "eth_getLogs"
+			call.Params = func() []any {
+				to := randomBlock()
+				from := to
+				if to+1 > benchLogsRange {
+					from = to + 1 - benchLogsRange
+				}
+				return []any{map[string]any{"fromBlock": hexutil.EncodeUint64(from), "toBlock": hexutil.EncodeUint64(to)}}
+			}
+		case "trace":
+			call.Method = benchTraceMethod
+			call.Params = func() []any {
+				block := hexutil.EncodeUint64(randomBlock())
+				if benchTraceMethod == "trace_block" {
+					return []any{block}
+				}
+				return []any{block, map[string]any{"tracer": "callTracer"}}
+			}
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [node-url ...]",
+	Short: "Benchmark rpc endpoints with a mix of calls at target qps, report latency percentiles and error rates",
+	Long: "Benchmark rpc endpoints with a mix of calls (eth_getLogs ranges, eth_call, traces, etc.) at target qps, " +
+		"report latency percentiles and error rates. Endpoints are benchmarked one by one, the endpoint of " +
+		"--node is benchmarked if no node-url is given.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if benchQps <= 0 {
+			return fmt.Errorf("--qps must be greater than 0")
+		}
+		if benchConcurrency <= 0 {
+			return fmt.Errorf("--concurrency must be greater than 0")
+		}
+		if benchLogsRange == 0 {
+			return fmt.Errorf("--logs-range must be greater than 0")
+		}
+		if benchTraceMethod != "debug_traceBlockByNumber" && benchTraceMethod != "trace_block" {
+			return fmt.Errorf("invalid --trace-method %v", benchTraceMethod)
+		}
+		if !isValidAddressArg(cmd.Context(), benchCallTo) {
+			return fmt.Errorf("--call-to %v is not a valid eth address", benchCallTo)
+		}
+		if _, err := hexutil.Decode(benchCallData); err != nil {
+			return fmt.Errorf("invalid --call-data: %w", err)
+		}
+		weights, err := parseBenchMix(benchMix)
+		if err != nil {
+			return err
+		}
+		var total int
+		for _, weight := range weights {
+			total += weight
+		}
+		if total == 0 {
+			return fmt.Errorf("the total weight of --mix must be greater than 0")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var endpoints = args
+		if len(endpoints) == 0 {
+			log.Printf("Current network is %v", globalOptNode)
+			endpoints = []string{globalOptNodeUrl}
+		}
+		weights, _ := parseBenchMix(benchMix) // validated in Args
+
+		for _, endpoint := range endpoints {
+			client, err := ethutil.Dial(ctx, endpoint)
+			checkErr(err)
+			latestBlock, err := client.EthClient.BlockNumber(ctx)
+			checkErr(err)
+
+			log.Printf("benchmark %v at %v qps for %v", endpoint, benchQps, benchDuration)
+			report := ethutil.RunBench(ctx, client.RpcClient, buildBenchCalls(weights, latestBlock, hexAddressArg(ctx, benchCallTo)), ethutil.BenchOptions{
+				Qps:            benchQps,
+				Duration:       benchDuration,
+				Concurrency:    benchConcurrency,
+				RequestTimeout: benchRequestTimeout,
+			})
+			client.RpcClient.Close()
+
+			fmt.Printf("endpoint: %v\n", endpoint)
+			fmt.Printf("achieved qps: %.2f\n", report.AchievedQps())
+			fmt.Printf("%-14v %8v %8v %8v %10v %10v %10v %10v\n", "call", "count", "errors", "error%", "p50", "p90", "p99", "max")
+			for _, stats := range append(report.Stats, report.Total) {
+				fmt.Printf("%-14v %8v %8v %8.2f %10v %10v %10v %10v\n", stats.Name, stats.Count, stats.Errors, stats.ErrorRate()*100,
+					stats.P50.Round(time.Millisecond), stats.P90.Round(time.Millisecond),
+					stats.P99.Round(time.Millisecond), stats.Max.Round(time.Millisecond))
+			}
+			for _, stats := range report.Stats {
+				if stats.LastError != nil {
+					log.Printf("last error of %v: %v", stats.Name, stats.LastError)
+				}
+			}
+			fmt.Println()
+		}
+	},
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var buildTxFrom string
+var buildTxValue string
+var buildTxUnit string
+var buildTxHexData string
+var buildTxChainId int64
+
+func init() {
+	buildTxCmd.Flags().StringVarP(&buildTxFrom, "from", "", "", "the sender address, only required when the nonce is queried online (i.e. --nonce not specified)")
+	buildTxCmd.Flags().StringVarP(&buildTxValue, "value", "", "0", "the amount of eth sent with tx, unit is ether and can be changed by --unit")
+	buildTxCmd.Flags().StringVarP(&buildTxUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+	buildTxCmd.Flags().StringVarP(&buildTxHexData, "hex-data", "", "", "the payload hex data of tx")
+	buildTxCmd.Flags().Int64VarP(&buildTxChainId, "chain-id", "", 0, "the chain id, 0 means query it online")
+
+	addFlags(buildTxCmd, txFlags)
+}
+
+var buildTxCmd = &cobra.Command{
+	Use:   "build-tx to-address",
+	Short: "Build unsigned tx, no node is needed if nonce, chain id, gas limit and gas price (or max fees) are all specified",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires to-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if buildTxFrom != "" && !isValidAddressArg(cmd.Context(), buildTxFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", buildTxFrom)
+		}
+		if !isValidHexString(buildTxHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if _, err := decimal.NewFromString(buildTxValue); err != nil {
+			return fmt.Errorf("%v is not a valid amount", buildTxValue)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		var gasPrice *big.Int
+		if globalOptGasPrice != "" {
+			gasPrice = unify2Wei(decimal.RequireFromString(globalOptGasPrice), unitGwei).BigInt()
+		}
+		opts := buildTxOptions(gasPrice)
+		if buildTxChainId > 0 {
+			opts.ChainID = big.NewInt(buildTxChainId)
+		}
+
+		var client *ethclient.Client
+		if !canBuildTxOffline(opts) {
+			if opts.Nonce == nil && buildTxFrom == "" {
+				log.Fatalf("--from is required to query nonce online, or specify --nonce")
+			}
+			log.Printf("Current network is %v", globalOptNode)
+
+			InitGlobalClient(ctx, globalOptNodeUrl)
+			client = globalClient.EthClient
+			opts.GasOracle = newGasOracle(client)
+
+			if opts.ChainID == nil {
+				chainID, err := client.ChainID(ctx)
+				checkErr(err)
+				opts.ChainID = chainID
+			}
+		}
+
+		var data []byte
+		if buildTxHexData != "" {
+			var err error
+			data, err = hexutil.Decode(buildTxHexData)
+			checkErr(err)
+		}
+		to := mustParseAddressArg(ctx, args[0])
+		amount := unify2Wei(decimal.RequireFromString(buildTxValue), buildTxUnit).BigInt()
+
+		tx, err := ethutil.BuildTx(ctx, client, mustParseAddressArg(ctx, buildTxFrom), &to, amount, data, opts)
+		checkErr(err)
+
+		var schedule ethutil.GasSchedule
+		if client != nil {
+			schedule, err = currentGasSchedule(ctx, client)
+		} else {
+			schedule, err = offlineGasSchedule(opts.ChainID)
+		}
+		checkErr(err)
+		checkErr(schedule.ValidateTx(tx))
+
+		unsignedTx, err := ethutil.GenRawTx(tx)
+		checkErr(err)
+
+		if !globalOptTerseOutput {
+			log.Printf("chain id %v, nonce %v, gas limit %v", opts.ChainID, tx.Nonce(), tx.Gas())
+			if globalOptTxType == txTypeEip155 {
+				log.Printf("chain id is not encoded in unsigned eip155 tx, please specify --chain-id %v for sign-tx", opts.ChainID)
+			}
+		}
+		fmt.Printf("%v\n", unsignedTx)
+	},
+}
+
+// canBuildTxOffline returns true if all tx fields which are queried online are specified.
+func canBuildTxOffline(opts ethutil.TxOptions) bool {
+	if opts.Nonce == nil || opts.ChainID == nil || opts.GasLimit == 0 {
+		return false
+	}
+	if opts.TxType == txTypeEip1559 {
+		return opts.MaxPriorityFeePerGas != nil && opts.MaxFeePerGas != nil
+	}
+	return opts.GasPrice != nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// privateTxMaxBlocks is the number of blocks in which private tx can be included, same as the default of flashbots
+const privateTxMaxBlocks = 25
+
+var bundleFile string
+var bundleBlock uint64
+var bundleBlocks uint64
+var bundleFlashbotsRelayUrl string
+var bundleSimulateOnly bool
+
+func init() {
+	bundleCmd.Flags().StringVarP(&bundleFile, "file", "f", "", "read signed txs from this file, one tx per line")
+	bundleCmd.Flags().Uint64VarP(&bundleBlock, "block", "", 0, "the first target block of bundle, 0 means the next block")
+	bundleCmd.Flags().Uint64VarP(&bundleBlocks, "blocks", "", 1, "submit the bundle for this many following blocks")
+	bundleCmd.Flags().StringVarP(&bundleFlashbotsRelayUrl, "flashbots-relay", "", "", "the flashbots relay url, default relay of current chain is used if not specified")
+	bundleCmd.Flags().BoolVarP(&bundleSimulateOnly, "simulate", "", false, "only simulate the bundle by eth_callBundle, do not submit it")
+
+	addFlags(bundleCmd, txFlags)
+}
+
+// flashbotsAuthKey returns --flashbots-auth-key, or a throwaway key which is enough for signing relay requests.
+func flashbotsAuthKey() *ecdsa.PrivateKey {
+	if globalOptFlashbotsAuthKey != "" {
+		return buildPrivateKeyFromHex(globalOptFlashbotsAuthKey)
+	}
+	authKey, err := crypto.GenerateKey()
+	checkErr(err)
+	return authKey
+}
+
+// dialFlashbotsRelay connects to relayUrl, or the default flashbots relay of current chain if relayUrl is empty.
+func dialFlashbotsRelay(ctx context.Context, relayUrl string) (*ethutil.FlashbotsClient, string, error) {
+	if relayUrl == "" {
+		chainID, err := globalClient.EthClient.ChainID(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("ChainID fail: %w", err)
+		}
+		var ok bool
+		if relayUrl, ok = ethutil.FlashbotsRelayUrls[chainID.Uint64()]; !ok {
+			return nil, "", fmt.Errorf("no default flashbots relay for chain %v, please specify the relay url", chainID)
+		}
+	}
+	relay, err := ethutil.DialFlashbots(relayUrl, flashbotsAuthKey())
+	if err != nil {
+		return nil, "", err
+	}
+	return relay, relayUrl, nil
+}
+
+// broadcastTx broadcasts signed tx by eth_sendRawTransaction, or sends it to flashbots relay privately if --private-tx
+// is specified. The tx hash returned by node or relay is returned.
+func broadcastTx(ctx context.Context, client *ethutil.Client, signedTx *types.Transaction) (*common.Hash, error) {
+	if !globalOptPrivateTx {
+		return ethutil.SendRawTransaction(ctx, client.RpcClient, signedTx)
+	}
+
+	relay, relayUrl, err := dialFlashbotsRelay(ctx, globalOptPrivateTxRelay)
+	if err != nil {
+		return nil, err
+	}
+	defer relay.Close()
+
+	current, err := client.EthClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("BlockNumber fail: %w", err)
+	}
+	txHash, err := relay.SendPrivateTransaction(ctx, signedTx, current+privateTxMaxBlocks)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("tx %v is sent to %v privately, it's dropped if not included before block %v", txHash.Hex(), relayUrl, current+privateTxMaxBlocks)
+	return &txHash, nil
+}
+
+// readBundleTxs reads signed txs from args, or from --file if no args.
+func readBundleTxs(args []string) ([]*types.Transaction, error) {
+	rawTxs := args
+	if bundleFile != "" {
+		file, err := os.Open(bundleFile)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" && !strings.HasPrefix(line, "#") {
+				rawTxs = append(rawTxs, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	var txs []*types.Transaction
+	for _, rawTx := range rawTxs {
+		tx, err := ethutil.ParseRawTx(rawTx)
+		if err != nil {
+			return nil, fmt.Errorf("parse signed tx %v fail: %w", rawTx, err)
+		}
+		txs = append(txs, tx)
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("no signed tx in bundle")
+	}
+	return txs, nil
+}
+
+var bundleCmd = &cobra.Command{
+	Use:   "bundle [signed-tx ...]",
+	Short: "Simulate signed txs as a flashbots bundle by eth_callBundle, then submit it by eth_sendBundle",
+	Long: "Simulate signed txs as a flashbots bundle by eth_callBundle, then submit it by eth_sendBundle. " +
+		"Txs in bundle are included atomically and in order, or not included at all, they never appear in public mempool.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && bundleFile != "" {
+			return fmt.Errorf("signed-tx and --file can not be specified at the same time")
+		}
+		if len(args) == 0 && bundleFile == "" {
+			return fmt.Errorf("requires signed-tx or --file")
+		}
+		for _, arg := range args {
+			if !isValidHexString(arg) {
+				return fmt.Errorf("signed-tx %v must hex string", arg)
+			}
+		}
+		if bundleBlocks == 0 {
+			return fmt.Errorf("--blocks must be greater than 0")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		txs, err := readBundleTxs(args)
+		checkErr(err)
+		relay, relayUrl, err := dialFlashbotsRelay(ctx, bundleFlashbotsRelayUrl)
+		checkErr(err)
+		defer relay.Close()
+
+		target := bundleBlock
+		if target == 0 {
+			current, err := globalClient.EthClient.BlockNumber(ctx)
+			checkErr(err)
+			target = current + 1
+		}
+
+		simulation, err := relay.CallBundle(ctx, txs, target)
+		checkErr(err)
+		var reverted bool
+		for _, result := range simulation.Results {
+			status := "ok"
+			if result.Error != "" {
+				status = fmt.Sprintf("error: %v %v", result.Error, result.Revert)
+				reverted = true
+			}
+			log.Printf("tx %v: gas used %v, %v", result.TxHash.Hex(), result.GasUsed, status)
+		}
+		if !globalOptTerseOutput {
+			log.Printf("simulated on state of block %v, total gas used %v, coinbase diff %v ether, bundle gas price %v gwei",
+				simulation.StateBlockNumber, simulation.TotalGasUsed, weiString2Unit(simulation.CoinbaseDiff, unitEther), weiString2Unit(simulation.BundleGasPrice, unitGwei))
+		}
+		if reverted {
+			log.Fatalf("bundle fails in simulation, it's not submitted")
+		}
+		if bundleSimulateOnly || globalOptDryRun {
+			return
+		}
+
+		for _, tx := range txs {
+			checkApproval(tx)
+		}
+		var bundleHash string
+		for block := target; block < target+bundleBlocks; block++ {
+			bundleHash, err = relay.SendBundle(ctx, txs, block)
+			checkErr(err)
+			log.Printf("bundle %v submitted to %v for block %v", bundleHash, relayUrl, block)
+		}
+		fmt.Printf("%v\n", bundleHash)
+	},
+}
+
+// weiString2Unit converts decimal wei string (e.g. returned by relay) to unit, the string is returned as is if it's
+// not a valid number.
+func weiString2Unit(wei string, unit string) string {
+	amount, err := decimal.NewFromString(wei)
+	if err != nil {
+		return wei
+	}
+	return wei2Other(amount, unit).String()
+}
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"context"
 	"log"
 	"os"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/shopspring/decimal"
@@ -18,6 +20,9 @@ func init() {
 	callCmd.Flags().StringVarP(&callCmdABIFile, "abi-file", "", "", "the path of abi file, if this option specified, 'function signature' can be just function name")
 	callCmd.Flags().StringVarP(&callCmdTransferUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
 	callCmd.Flags().StringVarP(&callCmdTransferAmt, "value", "", "0", "the amount you want to transfer when call contract, unit is ether and can be changed by --unit")
+
+	addFlags(callCmd, txFlags)
+	addFlags(callCmd, explorerFlags)
 }
 
 var callCmd = &cobra.Command{
@@ -25,21 +30,21 @@ var callCmd = &cobra.Command{
 	Short: "Invokes the (paid) contract method",
 	Args:  cobra.MinimumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		if !validationCallCmdOpts(args) {
+		if !validationCallCmdOpts(cmd.Context(), args) {
 			_ = cmd.Help()
 			os.Exit(1)
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-		contractAddr := args[0]
+		contractAddr := hexAddressArg(cmd.Context(), args[0])
 		funcSignature := args[1]
 		inputArgData := args[2:]
 
 		if !globalOptDryRun {
 			// don't check contract address if --dry-run specified
-			isContract, err := isContractAddress(globalClient.EthClient, common.HexToAddress(contractAddr))
+			isContract, err := ethutil.IsContractAddress(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr))
 			if err != nil {
 				panic(err)
 			}
@@ -48,18 +53,10 @@ var callCmd = &cobra.Command{
 			}
 		}
 
-		if callCmdABIFile != "" {
-			abiContent, err := os.ReadFile(callCmdABIFile)
-			if err != nil {
-				log.Fatal(err)
-			}
-			funcName := funcSignature
-			funcSignature, err = extractFuncDefinition(string(abiContent), extractFuncName(funcName))
-			checkErr(err)
-			// log.Printf("extract func definition from abi: %v", funcSignature)
-		}
+		funcSignature, err := resolveFuncSignature(cmd.Context(), common.HexToAddress(contractAddr), funcSignature, callCmdABIFile)
+		checkErr(err)
 
-		txInputData, err := buildTxInputData(funcSignature, inputArgData)
+		txInputData, err := ethutil.BuildTxInputData(funcSignature, inputArgData)
 		checkErr(err)
 
 		if globalOptShowInputData {
@@ -73,7 +70,7 @@ var callCmd = &cobra.Command{
 			var valueInWei = unify2Wei(value, callCmdTransferUnit)
 
 			var contract = common.HexToAddress(contractAddr)
-			tx, err := Transact(globalClient.RpcClient, globalClient.EthClient, buildPrivateKeyFromHex(globalOptPrivateKey), &contract, valueInWei.BigInt(), nil, txInputData)
+			tx, err := Transact(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &contract, valueInWei.BigInt(), nil, txInputData)
 			checkErr(err)
 
 			log.Printf("transaction %s finished", tx)
@@ -82,8 +79,9 @@ var callCmd = &cobra.Command{
 	},
 }
 
-func validationCallCmdOpts(args []string) bool {
-	if !isValidEthAddress(args[0]) {
+func validationCallCmdOpts(ctx context.Context, args []string) bool {
+	args[0] = resolveAbiName(args[0]) // contract can be a name added by abi add
+	if !isValidAddressArg(ctx, args[0]) {
 		log.Printf("%s is NOT a valid eth address", args[0])
 		return false
 	}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// globalChainPrefixes are the chain short names (and their chain ids) of EIP-3770 chain-prefixed address arguments,
+// they must match the network connected by InitGlobalClient.
+var globalChainPrefixes = map[string]uint64{}
+
+// splitAddressArg returns the hex address of an address argument, which is a plain hex address, an EIP-3770
+// chain-prefixed address (e.g. oeth:0x...) or a Safe{Wallet} url (e.g. https://app.safe.global/home?safe=eth:0x...).
+// The hex address is returned as it is in arg, the chain prefix is recorded and checked against current network.
+func splitAddressArg(ctx context.Context, arg string) (string, error) {
+	if isValidEthAddress(arg) {
+		return arg, nil
+	}
+	if !ethutil.IsChainAddress(arg) {
+		return "", fmt.Errorf("%v is not a valid eth address", arg)
+	}
+	shortName, chainId, hexAddress, err := ethutil.SplitChainAddress(arg)
+	if err != nil {
+		return "", err
+	}
+	globalChainPrefixes[shortName] = chainId
+	if globalClient != nil { // otherwise it's checked by InitGlobalClient
+		checkChainPrefixes(ctx)
+	}
+	return hexAddress, nil
+}
+
+// isValidAddressArg reports whether arg is a valid address argument, it's used by args validators, see splitAddressArg.
+func isValidAddressArg(ctx context.Context, arg string) bool {
+	_, err := splitAddressArg(ctx, arg)
+	return err == nil
+}
+
+// parseAddressArg parses an address argument, see splitAddressArg.
+func parseAddressArg(ctx context.Context, arg string) (common.Address, error) {
+	hexAddress, err := splitAddressArg(ctx, arg)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.HexToAddress(hexAddress), nil
+}
+
+// mustParseAddressArg parses an address argument checked by isValidAddressArg, it exits if arg is not a valid address.
+// An empty arg (optional flag not set) is parsed as zero address, same as common.HexToAddress.
+func mustParseAddressArg(ctx context.Context, arg string) common.Address {
+	if arg == "" {
+		return common.Address{}
+	}
+	address, err := parseAddressArg(ctx, arg)
+	checkErr(err)
+	return address
+}
+
+// hexAddressArg returns the hex address of an address argument checked by isValidAddressArg, it exits if arg is not
+// a valid address. An empty arg (optional flag not set) is returned as it is.
+func hexAddressArg(ctx context.Context, arg string) string {
+	if arg == "" {
+		return ""
+	}
+	hexAddress, err := splitAddressArg(ctx, arg)
+	checkErr(err)
+	return hexAddress
+}
+
+// checkChainPrefixes exits if any chain-prefixed address in command line is for another chain than the connected
+// network, it prevents copy-paste mistake across chains.
+func checkChainPrefixes(ctx context.Context) {
+	if len(globalChainPrefixes) == 0 {
+		return
+	}
+	chainId, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	matchChainPrefixes(chainId.Uint64())
+}
+
+// matchChainPrefixes exits if any chain-prefixed address in command line is not for chain id.
+func matchChainPrefixes(chainId uint64) {
+	for shortName, id := range globalChainPrefixes {
+		if chainId != id {
+			log.Fatalf("address prefix %v: is for chain %v, but chain id of current network is %v", shortName, id, chainId)
+		}
+	}
+}
+
+// currentChainId returns chain id of current network, node is connected only if it's not a builtin network of --node.
+func currentChainId(ctx context.Context) uint64 {
+	if chainId, ok := nodeChainIdMap[globalOptNode]; ok && globalOptNodeUrl == nodeUrlMap[globalOptNode] {
+		matchChainPrefixes(chainId)
+		return chainId
+	}
+	if globalClient == nil {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+	}
+	chainId, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	return chainId.Uint64()
+}
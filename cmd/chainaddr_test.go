@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSplitAddressArg(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+		prefixes string
+	}{
+		{
+			arg:      "0x24f8209ec5f56a07c94e834627f0651c19aca0ac",
+			expected: "0x24f8209ec5f56a07c94e834627f0651c19aca0ac",
+		},
+		{
+			arg:      "oeth:0x24f8209ec5f56a07c94e834627f0651c19aca0ac",
+			expected: "0x24f8209ec5f56a07c94e834627f0651c19aca0ac",
+			prefixes: "oeth",
+		},
+		{
+			arg:      "eth:0x24F8209EC5f56A07C94e834627F0651c19ACa0ac",
+			expected: "0x24F8209EC5f56A07C94e834627F0651c19ACa0ac",
+			prefixes: "eth",
+		},
+		{
+			arg:      "https://app.safe.global/home?safe=matic:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac",
+			expected: "0x24f8209EC5f56A07C94e834627F0651c19ACa0ac",
+			prefixes: "matic",
+		},
+		{
+			arg: "foo:0x24f8209ec5f56a07c94e834627f0651c19aca0ac",
+		},
+		{
+			arg: "hello",
+		},
+	}
+
+	ctx := context.Background()
+	defer func() { globalChainPrefixes = map[string]uint64{} }()
+	for i, test := range tests {
+		globalChainPrefixes = map[string]uint64{}
+		got, err := splitAddressArg(ctx, test.arg)
+		if isValidAddressArg(ctx, test.arg) != (test.expected != "") {
+			t.Fatalf("test %d: expected valid: %v", i, test.expected != "")
+		}
+		if test.expected == "" {
+			if err == nil {
+				t.Fatalf("test %d: expected error, got: %v", i, got)
+			}
+			continue
+		}
+		if err != nil || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+		var names []string
+		for name := range globalChainPrefixes {
+			names = append(names, name)
+		}
+		if strings.Join(names, ",") != test.prefixes {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.prefixes, names)
+		}
+
+		address, err := parseAddressArg(ctx, test.arg)
+		if err != nil || !strings.EqualFold(address.Hex(), test.expected) {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, address.Hex(), err)
+		}
+		if hexAddress := hexAddressArg(ctx, test.arg); hexAddress != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, hexAddress)
+		}
+	}
+}
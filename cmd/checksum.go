@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var checksumStrict bool
+var checksumChainPrefix bool
+var checksumErc1191ChainId uint64
+
+func init() {
+	checksumCmd.Flags().BoolVarP(&checksumStrict, "strict", "", false, "also treat all lowercase or all uppercase address (no checksum) as invalid")
+	checksumCmd.Flags().BoolVarP(&checksumChainPrefix, "chain-prefix", "", false, "print EIP-3770 chain-prefixed address (e.g. oeth:0x...) of current network, chain prefix of input address is checked against current network")
+	checksumCmd.Flags().Uint64VarP(&checksumErc1191ChainId, "erc1191", "", 0, "print ERC-1191 checksummed address of this chain id (e.g. 30 for RSK) instead of EIP-55, input address in ERC-1191 checksum of this chain or RSK is also valid")
+}
+
+var checksumCmd = &cobra.Command{
+	Use:   "checksum address ...",
+	Short: "Validate EIP-55 (or ERC-1191) checksum of address and print the checksummed address, exit with 1 if any address is invalid",
+	Args:  inputArgs(cobra.MinimumNArgs(1)),
+	Run: func(cmd *cobra.Command, args []string) {
+		var invalid bool
+		var chainId uint64
+		if checksumChainPrefix {
+			chainId = currentChainId(cmd.Context())
+		}
+		var erc1191ChainIds = ethutil.ERC1191ChainIds
+		if checksumErc1191ChainId != 0 {
+			erc1191ChainIds = append([]uint64{checksumErc1191ChainId}, erc1191ChainIds...)
+		}
+		forEachInput(args, []string{jsonlKeyAddress}, func(arg string) {
+			var hexAddress = arg
+			if ethutil.IsChainAddress(arg) {
+				var err error
+				if hexAddress, err = splitAddressArg(cmd.Context(), arg); err != nil {
+					log.Printf("%v", err)
+					invalid = true
+					return
+				}
+				if checksumChainPrefix {
+					matchChainPrefixes(chainId)
+				}
+			}
+			address, checksummed, erc1191ChainId, err := ethutil.DetectChecksum(hexAddress, erc1191ChainIds)
+			var status = "valid checksum"
+			if erc1191ChainId != 0 {
+				status = fmt.Sprintf("valid ERC-1191 checksum of chain %v", erc1191ChainId)
+			}
+			if errors.Is(err, ethutil.ErrInvalidChecksum) {
+				status = "INVALID checksum"
+				invalid = true
+			} else if err != nil {
+				log.Printf("%v", err)
+				invalid = true
+				return
+			} else if !checksummed {
+				status = "no checksum"
+				invalid = invalid || checksumStrict
+			}
+
+			var output = ethutil.ChecksumAddressERC1191(address, checksumErc1191ChainId)
+			if checksumChainPrefix {
+				shortName, ok := ethutil.ChainShortName(chainId)
+				if !ok {
+					log.Fatalf("unknown short name of chain %v", chainId)
+				}
+				output = shortName + ":" + output
+			}
+
+			if printJSONL(map[string]any{jsonlKeyAddress: output, "input": arg, "status": status}) {
+				return
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", output)
+				return
+			}
+			fmt.Printf("%v %v, %v\n", arg, status, output)
+		})
+		if invalid {
+			os.Exit(1)
+		}
+	},
+}
@@ -10,15 +10,12 @@ import (
 	"log"
 	"math/big"
 	"net/http"
+	"os"
 	"regexp"
-	"time"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/shopspring/decimal"
@@ -65,17 +62,6 @@ func isValidEthAddress(v string) bool {
 	return ethAddressRE.MatchString(v)
 }
 
-// isContractAddress returns true if address is a valid eth contract address.
-func isContractAddress(client *ethclient.Client, address common.Address) (bool, error) {
-	bytecode, err := client.CodeAt(context.Background(), address, nil) // nil is latest block
-	if err != nil {
-		return false, err
-	}
-
-	isContract := len(bytecode) > 0
-	return isContract, nil
-}
-
 // has0xPrefix returns true if str starts with 0x or 0X.
 func has0xPrefix(str string) bool {
 	return len(str) >= 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X')
@@ -107,18 +93,36 @@ func bigInt2Decimal(x *big.Int) decimal.Decimal {
 }
 
 // buildPrivateKeyFromHex builds ecdsa.PrivateKey from hex string (the leading 0x is optional),
-// it would panic if input an invalid hex string.
+// it would exit if input an invalid hex string. Key in other formats (e.g. WIF, or a file of SEC1/PKCS#8 PEM or DER
+// exported from HSM) is also accepted, see ethutil.ParsePrivateKeyAny. Keystore V3 file is decrypted by password read
+// from stdin, see buildPrivateKeyFromKeystore.
 func buildPrivateKeyFromHex(privateKeyHex string) *ecdsa.PrivateKey {
-	if has0xPrefix(privateKeyHex) {
-		privateKeyHex = privateKeyHex[2:] // remove leading 0x
+	privateKey, err := ethutil.ParsePrivateKey(privateKeyHex)
+	if err == nil {
+		return privateKey
 	}
 
-	privateKey, err := crypto.HexToECDSA(privateKeyHex)
-	checkErr(err)
-
+	content := readKeyInput(privateKeyHex)
+	if parseKeystoreFile(content) != nil {
+		return buildPrivateKeyFromKeystore(privateKeyHex, content)
+	}
+	privateKey, _, anyErr := ethutil.ParsePrivateKeyAny(content)
+	if anyErr != nil {
+		checkErr(err)
+	}
 	return privateKey
 }
 
+// readKeyInput returns content of file if key is path of an existing file, otherwise key itself.
+func readKeyInput(key string) []byte {
+	if info, err := os.Stat(key); err == nil && info.Mode().IsRegular() {
+		content, err := os.ReadFile(key)
+		checkErr(err)
+		return content
+	}
+	return []byte(key)
+}
+
 // wei2Other converts wei to other unit (specified by targetUnit).
 func wei2Other(sourceAmtInWei decimal.Decimal, targetUnit string) decimal.Decimal {
 	decimal.DivisionPrecision = 18
@@ -148,40 +152,7 @@ func unify2Wei(sourceAmt decimal.Decimal, sourceUnit string) decimal.Decimal {
 
 // extractAddressFromPrivateKey extracts address from ecdsa.PrivateKey.
 func extractAddressFromPrivateKey(privateKey *ecdsa.PrivateKey) common.Address {
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		panic("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
-	}
-
-	return crypto.PubkeyToAddress(*publicKeyECDSA)
-}
-
-// getTxReceipt gets the receipt of tx, re-check util timeout if tx not found.
-func getTxReceipt(client *ethclient.Client, txHash common.Hash, timeout time.Duration) (*types.Receipt, error) {
-	var beginTime = time.Now()
-
-recheck:
-	if rp, err := client.TransactionReceipt(context.Background(), txHash); err != nil {
-		if err == ethereum.NotFound {
-			log.Printf("tx %v not found (may be pending) in network", txHash.String())
-		} else {
-			return nil, fmt.Errorf("TransactionReceipt fail: %w", err)
-		}
-	} else {
-		// no error
-		return rp, nil
-	}
-
-	if timeout > 0 && beginTime.Add(timeout).After(time.Now()) {
-		// timeout
-		return nil, fmt.Errorf("GetReceipt timeout")
-	}
-
-	// not timeout
-	log.Printf("re-check tx %v after 5 seconds", txHash.String())
-	time.Sleep(time.Second * 5)
-	goto recheck
+	return ethutil.AddressFromPrivateKey(privateKey)
 }
 
 const EthGasStationUrl = "https://ethgasstation.info/json/ethgasAPI.json"
@@ -220,221 +191,101 @@ func getGasPriceFromEthGasStation() (*big.Int, error) {
 	return gasPrice, nil
 }
 
-// GenRawTx return raw tx, a hex string with 0x prefix
-func GenRawTx(signedTx *types.Transaction) (string, error) {
-	data, err := signedTx.MarshalBinary()
-	if err != nil {
-		return "", err
+// buildTxOptions builds tx options from global options
+func buildTxOptions(gasPrice *big.Int) ethutil.TxOptions {
+	var opts = ethutil.TxOptions{
+		TxType:   globalOptTxType,
+		GasLimit: globalOptGasLimit,
+		GasPrice: gasPrice,
+		Speed:    globalOptSpeed,
+		Logf:     log.Printf,
 	}
-
-	return hexutil.Encode(data), nil
+	if globalOptNonce >= 0 {
+		var nonce = uint64(globalOptNonce)
+		opts.Nonce = &nonce
+	}
+	if globalOptMaxPriorityFeePerGas != "" {
+		// convert from gwei to wei
+		opts.MaxPriorityFeePerGas = unify2Wei(decimal.RequireFromString(globalOptMaxPriorityFeePerGas), unitGwei).BigInt()
+	}
+	if globalOptMaxFeePerGas != "" {
+		// convert from gwei to wei
+		opts.MaxFeePerGas = unify2Wei(decimal.RequireFromString(globalOptMaxFeePerGas), unitGwei).BigInt()
+	}
+	return opts
 }
 
-// SendRawTransaction broadcast signed tx and return tx returned by rpc node
-func SendRawTransaction(rpcClient *rpc.Client, signedTx *types.Transaction) (*common.Hash, error) {
-	rawTx, err := GenRawTx(signedTx)
-	if err != nil {
-		return nil, err
+// newGasOracle returns gas oracle with the floor --priority-fee-floor
+func newGasOracle(client *ethclient.Client) ethutil.GasOracle {
+	var oracle = &ethutil.DefaultGasOracle{Client: client, Logf: log.Printf}
+	if globalOptPriorityFeeFloor != "" {
+		// convert from gwei to wei
+		oracle.FloorTip = unify2Wei(decimal.RequireFromString(globalOptPriorityFeeFloor), unitGwei).BigInt()
 	}
+	return oracle
+}
 
-	var result hexutil.Bytes
-	err = rpcClient.CallContext(context.Background(), &result, "eth_sendRawTransaction", rawTx)
-	if err != nil {
-		return nil, err
+// checkWeakPrivateKey exits if privateKey is a brainwallet key (keccak256 of a well known short string) and
+// --allow-weak-key is not specified, such keys are swept by bots and must not hold value.
+func checkWeakPrivateKey(privateKey *ecdsa.PrivateKey) {
+	if word, ok := ethutil.IsBrainwalletKey(privateKey); ok {
+		if globalOptAllowWeakKey {
+			log.Printf("WARNING: private key of %v is keccak256(%q), anyone can steal its funds", extractAddressFromPrivateKey(privateKey).Hex(), word)
+			return
+		}
+		log.Fatalf("private key of %v is keccak256(%q), anyone can steal its funds. use --allow-weak-key to use it anyway", extractAddressFromPrivateKey(privateKey).Hex(), word)
 	}
-
-	var hash = common.HexToHash(hexutil.Encode(result))
-	return &hash, nil
 }
 
 // Transact invokes the (paid) contract method.
-func Transact(rpcClient *rpc.Client, client *ethclient.Client, privateKey *ecdsa.PrivateKey, toAddress *common.Address, amount *big.Int, gasPrice *big.Int, data []byte) (string, error) {
+func Transact(ctx context.Context, client *ethutil.Client, privateKey *ecdsa.PrivateKey, toAddress *common.Address, amount *big.Int, gasPrice *big.Int, data []byte) (string, error) {
+	return TransactWithAccessList(ctx, client, privateKey, toAddress, amount, gasPrice, data, nil)
+}
+
+// TransactWithAccessList is same as Transact, but attaches accessList to the tx. accessList is ignored by eip155 tx.
+func TransactWithAccessList(ctx context.Context, client *ethutil.Client, privateKey *ecdsa.PrivateKey, toAddress *common.Address, amount *big.Int, gasPrice *big.Int, data []byte, accessList types.AccessList) (string, error) {
+	checkWeakPrivateKey(privateKey)
 	fromAddress := extractAddressFromPrivateKey(privateKey)
 
-	var nonce uint64
-	var err error
-	if globalOptNonce < 0 {
-		nonce, err = client.PendingNonceAt(context.Background(), fromAddress)
+	// if not specified
+	if gasPrice == nil && globalOptTxType != txTypeEip1559 {
+		var err error
+		gasPrice, err = getGasPrice(ctx, client.EthClient)
 		if err != nil {
-			return "", fmt.Errorf("PendingNonceAt fail: %w", err)
+			return "", err
 		}
-	} else {
-		nonce = uint64(globalOptNonce)
 	}
 
-	gasLimit := globalOptGasLimit
-	if gasLimit == 0 { // if user not specified
-		gasLimit = uint64(gasUsedByTransferEth)
-
-		if toAddress == nil {
-			gasLimit = 7000000 // the default gas limit for deploy contract, can be overwrite by option
-		} else {
-			isContract, err := isContractAddress(client, *toAddress)
-			if err != nil {
-				return "", fmt.Errorf("isContractAddress fail: %w", err)
-			}
-			if isContract { // gasUsedByTransferEth may be not enough if send to contract
-				gasLimit = 900000
-			}
-			if len(data) > 0 { // gasUsedByTransferEth may be not enough if with payload data
-				gasLimit = 900000
-			}
-		}
-	}
-
-	// if not specified
-	if gasPrice == nil {
-		gasPrice, err = getGasPrice(globalClient.EthClient)
-		checkErr(err)
-	}
-
-	var tx *types.Transaction
-
-	if globalOptTxType == txTypeEip1559 {
-		var maxPriorityFeePerGasEstimate = new(big.Int)
-		var maxFeePerGasEstimate = new(big.Int)
-		if globalOptMaxPriorityFeePerGas == "" || globalOptMaxFeePerGas == "" {
-			// Use rpc eth_feeHistory to estimate default maxPriorityFeePerGas and maxFeePerGas
-			// See https://docs.alchemy.com/docs/how-to-build-a-gas-fee-estimator-using-eip-1559
-			//
-			// $ curl -X POST --data '{ "id": 1, "jsonrpc": "2.0", "method": "eth_feeHistory", "params": ["0x4", "latest", [5, 50, 95]] }' https://mainnet.infura.io/v3/21a9f5ba4bce425795cac796a66d7472
-			// {
-			//  "jsonrpc": "2.0",
-			//  "id": 1,
-			//  "result": {
-			//    "baseFeePerGas": [
-			//      "0x4ed3ef336",
-			//      "0x4d2c282cd",
-			//      "0x4db586991",
-			//      "0x4d8275e8e",
-			//      "0x4b5fb0a47"
-			//    ],
-			//    "gasUsedRatio": [
-			//      0.41600023333333336,
-			//      0.5278128666666667,
-			//      0.4897323,
-			//      0.3897776666666667
-			//    ],
-			//    "oldestBlock": "0xffc0a9",
-			//    "reward": [
-			//      [
-			//        "0x6b51f67",
-			//        "0x3b9aca00",
-			//        "0x106853ddd8"
-			//      ],
-			//      [
-			//        "0xa9970dc",
-			//        "0x1dcd6500",
-			//        "0x10abffd64"
-			//      ],
-			//      [
-			//        "0x6190547",
-			//        "0x1dcd6500",
-			//        "0x9becf3d3c"
-			//      ],
-			//      [
-			//        "0x94a104a",
-			//        "0x1dcd6500",
-			//        "0x1032d8cdb"
-			//      ]
-			//    ]
-			//  }
-			// }
-			feeHistory, err := client.FeeHistory(context.Background(), 4, nil, []float64{5, 50, 95})
-			checkErr(err)
-			var slow big.Int
-			slow.Add(feeHistory.Reward[0][0], feeHistory.Reward[1][0])
-			slow.Add(&slow, feeHistory.Reward[2][0])
-			slow.Div(&slow, big.NewInt(3))
-
-			var average big.Int
-			average.Add(feeHistory.Reward[0][1], feeHistory.Reward[1][1])
-			average.Add(&average, feeHistory.Reward[2][1])
-			average.Div(&average, big.NewInt(3))
-
-			var fast big.Int
-			fast.Add(feeHistory.Reward[0][2], feeHistory.Reward[1][2])
-			fast.Add(&fast, feeHistory.Reward[2][2])
-			fast.Div(&fast, big.NewInt(3))
-
-			// Currently, slow/fast are not used. we use average value
-			maxPriorityFeePerGasEstimate = &average
-			// log.Printf("maxPriorityFeePerGasEstimate = %v", maxPriorityFeePerGasEstimate.String())
-
-			pendingBlock, err := client.BlockByNumber(context.Background(), nil)
-			checkErr(err)
-			maxFeePerGasEstimate = maxFeePerGasEstimate.Add(pendingBlock.BaseFee(), maxPriorityFeePerGasEstimate)
-			// log.Printf("maxFeePerGasEstimate = %v", maxFeePerGasEstimate.String())
-		}
-
-		var maxPriorityFeePerGas *big.Int
-		if globalOptMaxPriorityFeePerGas == "" {
-			// Use estimate value
-			maxPriorityFeePerGas = maxPriorityFeePerGasEstimate
-		} else {
-			// Use the value set by the user
-			maxPriorityFeePerGasDecimal, _ := decimal.NewFromString(globalOptMaxPriorityFeePerGas)
-			// convert from gwei to wei
-			maxPriorityFeePerGas = maxPriorityFeePerGasDecimal.Mul(decimal.RequireFromString("1000000000")).BigInt()
-		}
-
-		var maxFeePerGas *big.Int
-		if globalOptMaxFeePerGas == "" {
-			// Use estimate value
-			maxFeePerGas = maxFeePerGasEstimate
-		} else {
-			// Use the value set by the user
-			maxFeePerGasDecimal, _ := decimal.NewFromString(globalOptMaxFeePerGas)
-			// convert from gwei to wei
-			maxFeePerGas = maxFeePerGasDecimal.Mul(decimal.RequireFromString("1000000000")).BigInt()
-		}
-
-		tx = types.NewTx(&types.DynamicFeeTx{
-			Nonce:     nonce,
-			To:        toAddress, // nil means contract creation
-			Value:     amount,
-			Gas:       gasLimit,
-			GasTipCap: maxPriorityFeePerGas,
-			GasFeeCap: maxFeePerGas,
-			Data:      data,
-		})
-	} else {
-		tx = types.NewTx(&types.LegacyTx{
-			Nonce:    nonce,
-			To:       toAddress, // nil means contract creation
-			Value:    amount,
-			Gas:      gasLimit,
-			GasPrice: gasPrice,
-			Data:     data,
-		})
+	opts := buildTxOptions(gasPrice)
+	opts.AccessList = accessList
+	opts.GasOracle = newGasOracle(client.EthClient)
+	tx, err := ethutil.BuildTx(ctx, client.EthClient, fromAddress, toAddress, amount, data, opts)
+	if err != nil {
+		return "", err
 	}
 
-	chainID, err := client.NetworkID(context.Background())
+	schedule, err := currentGasSchedule(ctx, client.EthClient)
 	if err != nil {
-		return "", fmt.Errorf("NetworkID fail: %w", err)
+		return "", err
+	}
+	if err := schedule.ValidateTx(tx); err != nil {
+		return "", err
 	}
 
-	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privateKey)
+	checkPolicy(ctx, client.EthClient, nil, tx.To(), tx.Value(), tx.Data())
+	checkTOTP()
+	signedTx, err := ethutil.SignTx(ctx, client.EthClient, tx, privateKey, nil)
 	if err != nil {
-		return "", fmt.Errorf("SignTx fail: %w", err)
+		return "", err
 	}
 
 	if globalOptShowRawTx {
-		rawTx, _ := GenRawTx(signedTx)
+		rawTx, _ := ethutil.GenRawTx(signedTx)
 		log.Printf("raw tx = %v", rawTx)
 	}
 
 	if globalOptShowEstimateGas {
-		// EstimateGas
-		msg := ethereum.CallMsg{
-			From:     fromAddress,
-			To:       toAddress,
-			Gas:      gasLimit,
-			GasPrice: gasPrice,
-			Value:    amount,
-			Data:     data,
-		}
-		gas, err := client.EstimateGas(context.Background(), msg)
+		gas, err := ethutil.EstimateGas(ctx, client.EthClient, fromAddress, signedTx)
 		if err != nil {
 			return "", fmt.Errorf("EstimateGas fail: %w", err)
 		}
@@ -443,10 +294,12 @@ func Transact(rpcClient *rpc.Client, client *ethclient.Client, privateKey *ecdsa
 
 	if globalOptDryRun {
 		// return tx directly, do not broadcast it
+		printFormatted(newTxResult(signedTx, fromAddress, nil))
 		return signedTx.Hash().String(), nil
 	}
 
-	rpcReturnTx, err := SendRawTransaction(rpcClient, signedTx)
+	checkApproval(signedTx)
+	rpcReturnTx, err := broadcastTx(ctx, client, signedTx)
 	if err != nil {
 		return "", fmt.Errorf("SendRawTransaction fail: %w", err)
 	}
@@ -456,10 +309,11 @@ func Transact(rpcClient *rpc.Client, client *ethclient.Client, privateKey *ecdsa
 	}
 
 	if transferNotCheck {
+		printFormatted(newTxResult(signedTx, fromAddress, nil))
 		return rpcReturnTx.String(), nil
 	}
 
-	rp, err := getTxReceipt(client, *rpcReturnTx, 0)
+	rp, err := ethutil.WaitReceipt(ctx, client, *rpcReturnTx, waitOptions())
 	if err != nil {
 		return "", fmt.Errorf("getTxReceipt fail: %w", err)
 	}
@@ -474,67 +328,18 @@ func Transact(rpcClient *rpc.Client, client *ethclient.Client, privateKey *ecdsa
 		}
 	}
 
+	printFormatted(newTxResult(signedTx, fromAddress, rp))
 	if rp.Status != types.ReceiptStatusSuccessful {
 		return "", fmt.Errorf("tx %v minted, but status is failed, please check it in block explorer", rpcReturnTx.String())
 	}
 
 	if toAddress == nil {
-		log.Printf("the new contract deployed at %v", crypto.CreateAddress(fromAddress, nonce))
+		log.Printf("the new contract deployed at %v", ethutil.ContractAddress(fromAddress, signedTx.Nonce()))
 	}
 
 	return rpcReturnTx.String(), nil
 }
 
-// Call invokes the (constant) contract method.
-func Call(client *ethclient.Client, toAddress common.Address, data []byte) ([]byte, error) {
-	opts := new(bind.CallOpts)
-	msg := ethereum.CallMsg{From: opts.From, To: &toAddress, Data: data}
-	ctx := context.TODO()
-	return client.CallContract(ctx, msg, nil)
-}
-
-// getRecoveryId gets ecdsa recover id (0 or 1) from v.
-func getRecoveryId(v *big.Int) int {
-	// Note: can be simplified by checking parity (i.e. odd-even)
-	var recoveryId int
-	if v.Int64() == 0 || v.Int64() == 1 { // v in eip2718
-		recoveryId = int(v.Int64())
-	} else if v.Int64() == 27 || v.Int64() == 28 { // v before eip155
-		recoveryId = int(v.Int64()) - 27
-	} else { // v in eip155
-		// derive chainId
-		var chainId = int((v.Int64() - 35) / 2)
-		// derive recoveryId
-		recoveryId = int(v.Int64()) - 35 - 2*chainId
-	}
-	return recoveryId
-}
-
-// buildECDSASignature builds a 65-byte compact ECDSA signature (containing the recovery id as the last element)
-func buildECDSASignature(v, r, s *big.Int) []byte {
-	var recoveryId = getRecoveryId(v)
-	// println("recoveryId", recoveryId)
-
-	var rBytes = make([]byte, 32, 32)
-	var sBytes = make([]byte, 32, 32)
-	copy(rBytes[32-len(r.Bytes()):], r.Bytes())
-	copy(sBytes[32-len(s.Bytes()):], s.Bytes())
-
-	var rsBytes = append(rBytes, sBytes...)
-	return append(rsBytes, byte(recoveryId))
-}
-
-// RecoverPubkey recover public key, returns 65 bytes uncompressed public key
-func RecoverPubkey(v, r, s *big.Int, msg []byte) ([]byte, error) {
-	signature := buildECDSASignature(v, r, s)
-
-	// recover public key from msg (hash of data) and ECDSA signature
-	// crypto.Ecrecover msg: 32 bytes hash
-	// crypto.Ecrecover signature: 65-byte compact ECDSA signature
-	// crypto.Ecrecover return 65 bytes uncompressed public key
-	return crypto.Ecrecover(msg, signature)
-}
-
 // getFuncSig recover function signature from 4 bytes hash
 // For example:
 //   param: "0x8c905368"
@@ -578,3 +383,23 @@ func GetFuncSig(funcHash string) ([]string, error) {
 
 	return rc, nil
 }
+
+// stateBlock returns the block of --block whose state is read, nil means latest block.
+func stateBlock(ctx context.Context) *big.Int {
+	block, err := ethutil.ParseBlock(ctx, globalClient.EthClient, globalOptBlock)
+	checkErr(err)
+	if block != nil && !globalOptTerseOutput {
+		log.Printf("state is read at block %v", globalOptBlock)
+	}
+	return block
+}
+
+// waitOptions returns the options of waiting tx receipt declared by --confirmations, --poll-interval and --wait-timeout.
+func waitOptions() ethutil.WaitOptions {
+	return ethutil.WaitOptions{
+		Confirmations: globalOptConfirmations,
+		PollInterval:  globalOptPollInterval,
+		Timeout:       globalOptWaitTimeout,
+		Logf:          log.Printf,
+	}
+}
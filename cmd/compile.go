@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var compileSolc string
+var compileOptimize bool
+var compileRuns int
+var compileEvmVersion string
+var compileOutputDir string
+var compileContractName string
+var compileSaveInput string
+
+func init() {
+	for _, cmd := range []*cobra.Command{compileCmd, deployCmd} {
+		cmd.Flags().StringVarP(&compileSolc, "solc", "", "", "the compiler, solc binary or solcjs, default is solc or solcjs found in PATH")
+		cmd.Flags().BoolVarP(&compileOptimize, "optimize", "", false, "enable the optimizer")
+		cmd.Flags().IntVarP(&compileRuns, "runs", "", 200, "the optimizer runs")
+		cmd.Flags().StringVarP(&compileEvmVersion, "evm-version", "", "", "the evm version, default is the default of compiler")
+	}
+	compileCmd.Flags().StringVarP(&compileOutputDir, "output-dir", "", "build", "the directory of output files <Name>.abi and <Name>.bin")
+	compileCmd.Flags().StringVarP(&compileContractName, "contract-name", "", "", "only output this contract, e.g. Token, or contracts/Token.sol:Token if the name is ambiguous")
+	compileCmd.Flags().StringVarP(&compileSaveInput, "save-input", "", "", "save the standard json input to this file, which can be used by verify")
+}
+
+// solcBinary returns --solc, or solc or solcjs found in PATH.
+func solcBinary() (string, error) {
+	if compileSolc != "" {
+		return compileSolc, nil
+	}
+	for _, binary := range []string{"solc", "solcjs"} {
+		if path, err := exec.LookPath(binary); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("neither solc nor solcjs is found in PATH, please install one or specify --solc")
+}
+
+// compileSources compiles source files by standard json input of compiler settings in flags. Imports are resolved
+// relative to current directory and ./node_modules. It returns the output and the input.
+func compileSources(ctx context.Context, files []string) (*ethutil.SolcOutput, []byte) {
+	var sources = make(map[string]string)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		checkErr(err)
+		sources[filepath.ToSlash(filepath.Clean(file))] = string(content)
+	}
+	input, err := ethutil.StandardJsonInput(sources, ethutil.SolcSettings{Optimize: compileOptimize, Runs: compileRuns, EvmVersion: compileEvmVersion})
+	checkErr(err)
+
+	binary, err := solcBinary()
+	checkErr(err)
+	args := []string{"--standard-json", "--base-path", "."}
+	if info, err := os.Stat("node_modules"); err == nil && info.IsDir() {
+		args = append(args, "--include-path", "node_modules")
+	}
+	child := exec.CommandContext(ctx, binary, args...)
+	var stdout, stderr bytes.Buffer
+	child.Stdin = bytes.NewReader(input)
+	child.Stdout = &stdout
+	child.Stderr = &stderr
+	log.Printf("executing command %v", child.String())
+	if err := child.Run(); err != nil {
+		log.Fatalf("%v: %v", err, stderr.String())
+	}
+
+	// solcjs may print messages before the json output
+	output := stdout.Bytes()
+	if i := bytes.IndexByte(output, '{'); i > 0 {
+		output = output[i:]
+	}
+	result, warnings, err := ethutil.ParseSolcOutput(output)
+	for _, warning := range warnings {
+		log.Printf("%v", warning)
+	}
+	checkErr(err)
+	return result, input
+}
+
+var compileCmd = &cobra.Command{
+	Use:   "compile source-file...",
+	Short: "Compile solidity source files by solc standard json input, write abi and bytecode of contracts",
+	Long: "Compile solidity source files by solc standard json input (solc or solcjs), write <Name>.abi and <Name>.bin of\n" +
+		"contracts to --output-dir, which can be used by deploy --abi-file and --bin-file. Use deploy --source to compile\n" +
+		"and deploy in one command.",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		output, input := compileSources(cmd.Context(), args)
+		if compileSaveInput != "" {
+			checkErr(os.WriteFile(compileSaveInput, input, 0644))
+		}
+
+		var names = output.ContractNames()
+		if compileContractName != "" {
+			fullName, _, err := output.Contract(compileContractName)
+			checkErr(err)
+			names = []string{fullName}
+		}
+		checkErr(os.MkdirAll(compileOutputDir, 0755))
+		var written = make(map[string]string)
+		for _, fullName := range names {
+			_, contract, err := output.Contract(fullName)
+			checkErr(err)
+			name := fullName[strings.LastIndex(fullName, ":")+1:]
+			if other, found := written[name]; found {
+				log.Fatalf("both %v and %v are named %v, please specify --contract-name", other, fullName, name)
+			}
+			written[name] = fullName
+
+			abiFile := filepath.Join(compileOutputDir, name+".abi")
+			checkErr(os.WriteFile(abiFile, contract.Abi, 0644))
+			if contract.Evm.Bytecode.Object == "" { // interface or abstract contract
+				fmt.Printf("%v: %v\n", fullName, abiFile)
+				continue
+			}
+			binFile := filepath.Join(compileOutputDir, name+".bin")
+			checkErr(os.WriteFile(binFile, []byte(contract.Evm.Bytecode.Object), 0644))
+			fmt.Printf("%v: %v %v\n", fullName, abiFile, binFile)
+		}
+	},
+}
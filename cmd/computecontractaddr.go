@@ -1,37 +1,44 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
 )
 
 var computeContractAddrSalt string
 var computeContractAddrInitCode string
+var computeContractAddrInitCodeHash string
 
 func init() {
-	computeContractAddrCmd.Flags().StringVarP(&computeContractAddrSalt, "salt", "", "", "salt, for CREATE2")
+	computeContractAddrCmd.Flags().StringVarP(&computeContractAddrSalt, "salt", "", "", "salt, for CREATE2. salt shorter than 32 bytes is left padded with zeros, same as bytes32(uint256(salt)) in solidity")
 	computeContractAddrCmd.Flags().StringVarP(&computeContractAddrInitCode, "init-code", "", "", "init code, for CREATE2")
+	computeContractAddrCmd.Flags().StringVarP(&computeContractAddrInitCodeHash, "init-code-hash", "", "", "keccak256 of init code, for CREATE2, it can be used instead of --init-code")
+
 }
 
 func validationComputeContractAddrCmdOpts() bool {
-	if len(computeContractAddrSalt) == 0 && len(computeContractAddrInitCode) != 0 {
+	var hasInitCode = len(computeContractAddrInitCode) != 0 || len(computeContractAddrInitCodeHash) != 0
+	if len(computeContractAddrSalt) == 0 && hasInitCode {
 		log.Fatalf("--salt must also provided")
 		return false
 	}
-	if len(computeContractAddrSalt) != 0 && len(computeContractAddrInitCode) == 0 {
-		log.Fatalf("--init-code must also provided")
+	if len(computeContractAddrSalt) != 0 && !hasInitCode {
+		log.Fatalf("--init-code or --init-code-hash must also provided")
+		return false
+	}
+	if len(computeContractAddrInitCode) != 0 && len(computeContractAddrInitCodeHash) != 0 {
+		log.Fatalf("--init-code and --init-code-hash can not be specified at the same time")
 		return false
 	}
 
-	if !isValidHexString(computeContractAddrSalt) {
-		log.Fatalf("--salt must hex string")
+	if !isValidHexString(computeContractAddrSalt) || len(common.FromHex(computeContractAddrSalt)) > common.HashLength {
+		log.Fatalf("--salt must hex string not longer than 32 bytes")
 		return false
 	}
 
@@ -40,12 +47,18 @@ func validationComputeContractAddrCmdOpts() bool {
 		return false
 	}
 
+	if !isValidHexString(computeContractAddrInitCodeHash) || (len(computeContractAddrInitCodeHash) != 0 && len(common.FromHex(computeContractAddrInitCodeHash)) != common.HashLength) {
+		log.Fatalf("--init-code-hash must be 32 bytes hex string")
+		return false
+	}
+
 	return true
 }
 
 var computeContractAddrCmd = &cobra.Command{
-	Use:   "compute-contract-addr deployer-address",
-	Short: "Compute contract address before deployment",
+	Use:     "compute-contract-addr deployer-address",
+	Aliases: []string{"compute-address"},
+	Short:   "Compute contract address before deployment, from deployer and nonce (CREATE) or deployer, salt and init code (CREATE2)",
 	Args: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return fmt.Errorf("requires the address of deployer")
@@ -53,7 +66,7 @@ var computeContractAddrCmd = &cobra.Command{
 		if len(args) > 1 {
 			return fmt.Errorf("you can not specify multiple deployers")
 		}
-		if !isValidEthAddress(args[0]) {
+		if !isValidAddressArg(cmd.Context(), args[0]) {
 			return fmt.Errorf("%v is not a valid eth address", args[0])
 		}
 		return nil
@@ -64,33 +77,46 @@ var computeContractAddrCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		deployerAddr := args[0]
+		deployerAddr := mustParseAddressArg(cmd.Context(), args[0])
 
 		if len(computeContractAddrSalt) == 0 {
 			var nonce uint64
 			if globalOptNonce < 0 {
 				// get nonce online
-				client, err := ethclient.Dial(globalOptNodeUrl)
-				checkErr(err)
+				InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-				nonce, err = client.PendingNonceAt(context.TODO(), common.HexToAddress(deployerAddr))
+				var err error
+				nonce, err = globalClient.EthClient.PendingNonceAt(cmd.Context(), deployerAddr)
 				checkErr(err)
 			} else {
 				nonce = uint64(globalOptNonce)
 			}
-			contractAddr := crypto.CreateAddress(common.HexToAddress(deployerAddr), nonce)
+			contractAddr := ethutil.ContractAddress(deployerAddr, nonce)
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", contractAddr.Hex())
+				return
+			}
 			fmt.Printf("deployer address %v\nnonce %v\ncontract address %v\n",
-				deployerAddr,
-				globalOptNonce,
+				deployerAddr.Hex(),
+				nonce,
 				contractAddr.Hex())
 		} else {
-			var salt32 [32]byte
-			copy(salt32[:], common.FromHex(computeContractAddrSalt))
-			contractAddr := crypto.CreateAddress2(common.HexToAddress(deployerAddr), salt32, crypto.Keccak256(common.FromHex(computeContractAddrInitCode)))
-			fmt.Printf("deployer address %v\nsalt %v\ninit code %v\ncontract address %v\n",
-				deployerAddr,
-				computeContractAddrSalt,
-				computeContractAddrInitCode,
+			salt := common.BytesToHash(common.FromHex(computeContractAddrSalt))
+			var initCodeHash common.Hash
+			if len(computeContractAddrInitCodeHash) != 0 {
+				initCodeHash = common.HexToHash(computeContractAddrInitCodeHash)
+			} else {
+				initCodeHash = crypto.Keccak256Hash(common.FromHex(computeContractAddrInitCode))
+			}
+			contractAddr := ethutil.Create2Address(deployerAddr, salt, initCodeHash)
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", contractAddr.Hex())
+				return
+			}
+			fmt.Printf("deployer address %v\nsalt %v\ninit code hash %v\ncontract address %v\n",
+				deployerAddr.Hex(),
+				salt.Hex(),
+				initCodeHash.Hex(),
 				contractAddr.Hex())
 		}
 	},
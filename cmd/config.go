@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+)
+
+// configFile is the content of config file (default ~/.ethutil/config.json), for example:
+//
+//	{
+//	  "profiles": {
+//	    "mainnet": {
+//	      "node_url": "https://light.example.com",
+//	      "archive_url": "https://archive.example.com",
+//	      "trace_url": "https://trace.example.com",
+//	      "broadcast_url": "https://rpc.flashbots.net",
+//	      "priority_fee_floor": "0.01",
+//	      "price_source": "coingecko",
+//	      "coingecko_api_key": "CG-xxx",
+//	      "price_cache_ttl": "5m",
+//	      "etherscan_api_key": "XXX",
+//	      "explorer_api_url": "https://api.etherscan.io/api",
+//	      "explorer_kind": "etherscan",
+//	      "policy": "/etc/ethutil/policy.json",
+//	      "policy_signer": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb",
+//	      "approvers": ["0x24f8209EC5f56A07C94e834627F0651c19ACa0ac"],
+//	      "approval_threshold": "10",
+//	      "totp_file": "/home/alice/.ethutil/totp.json",
+//	      "require_totp": true
+//	    }
+//	  },
+//	  "explorers": [
+//	    {"chain_ids": [1, 10, 8453, 42161], "api_url": "https://api.etherscan.io/v2/api", "api_key": "XXX", "etherscan_v2": true},
+//	    {"chain_ids": [100], "api_url": "https://gnosis.blockscout.com/api", "timeout": "30s"},
+//	    {"chain_ids": [7777777], "api_url": "https://explorer.zora.energy/api", "kind": "blockscout"}
+//	  ],
+//	  "templates": {
+//	    "top-up-relayer": {
+//	      "description": "Send eth to relayer",
+//	      "args": ["--profile", "mainnet", "transfer", "0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "${amount}"],
+//	      "params": {"amount": "0.1eth"}
+//	    }
+//	  }
+//	}
+type configFile struct {
+	Profiles  map[string]profile    `json:"profiles"`
+	Explorers []explorerConfig      `json:"explorers"`
+	Templates map[string]txTemplate `json:"templates"`
+}
+
+// explorerConfig is an Etherscan-compatible block explorer api serving chains of ChainIds, which is used by explorer
+// api of these chains unless --explorer-api-url is specified.
+type explorerConfig struct {
+	ChainIds    []uint64 `json:"chain_ids"`
+	ApiUrl      string   `json:"api_url"`
+	ApiKey      string   `json:"api_key"`      // --explorer-api-key takes precedence
+	EtherscanV2 bool     `json:"etherscan_v2"` // multi-chain api, chain id is sent as chainid param
+	Timeout     string   `json:"timeout"`      // the deadline of each request, e.g. 30s, default is no deadline
+	Kind        string   `json:"kind"`         // etherscan | blockscout, default is detected by api_url
+}
+
+// profile is a named set of settings selected by --profile
+type profile struct {
+	ethutil.Endpoints
+	PriorityFeeFloor  string   `json:"priority_fee_floor"` // unit is gwei, same as --priority-fee-floor
+	PriceSource       string   `json:"price_source"`       // same as --price-source
+	CoinGeckoApiKey   string   `json:"coingecko_api_key"`
+	PriceCacheTTL     string   `json:"price_cache_ttl"` // e.g. 5m, default is 5 minutes
+	EtherscanApiKey   string   `json:"etherscan_api_key"`
+	ExplorerApiUrl    string   `json:"explorer_api_url"`   // same as --explorer-api-url
+	ExplorerKind      string   `json:"explorer_kind"`      // same as --explorer-kind
+	Policy            string   `json:"policy"`             // same as --policy
+	PolicySigner      string   `json:"policy_signer"`      // same as --policy-signer
+	Approvers         []string `json:"approvers"`          // same as --approvers
+	ApprovalThreshold string   `json:"approval_threshold"` // unit is ether, same as --approval-threshold
+	TotpFile          string   `json:"totp_file"`          // same as --totp-file
+	RequireTotp       bool     `json:"require_totp"`       // signing fails if TOTP file is missing
+}
+
+// defaultConfigFile returns ~/.ethutil/config.json
+func defaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ethutil", "config.json")
+}
+
+// readConfigFile reads and parses config file.
+func readConfigFile(file string) (*configFile, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read config file fail: %w", err)
+	}
+
+	var config configFile
+	if err := json.Unmarshal(content, &config); err != nil {
+		return nil, fmt.Errorf("parse config file %v fail: %w", file, err)
+	}
+	return &config, nil
+}
+
+// loadProfile loads profile name from config file.
+func loadProfile(file string, name string) (*profile, error) {
+	config, err := readConfigFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %v is not found in config file %v", name, file)
+	}
+	return &p, nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var convertPadLen int
+var convertPadRight bool
+var convertUnpadRight bool
+var convertBits int
+
+func init() {
+	convertPadCmd.Flags().IntVarP(&convertPadLen, "len", "", 32, "the length in bytes after padding")
+	convertPadCmd.Flags().BoolVarP(&convertPadRight, "right", "", false, "pad on the right (like bytes<N> and string), default is on the left (like uint and address)")
+	convertUnpadCmd.Flags().BoolVarP(&convertUnpadRight, "right", "", false, "remove zero bytes on the right, default is on the left")
+	convertToUintCmd.Flags().IntVarP(&convertBits, "bits", "", 256, "the bits of integer, 8, 16, ..., 256")
+	convertToIntCmd.Flags().IntVarP(&convertBits, "bits", "", 256, "the bits of integer, 8, 16, ..., 256")
+
+	convertCmd.AddCommand(convertUtf8ToHexCmd)
+	convertCmd.AddCommand(convertHexToUtf8Cmd)
+	convertCmd.AddCommand(convertPadCmd)
+	convertCmd.AddCommand(convertUnpadCmd)
+	convertCmd.AddCommand(convertStringToBytes32Cmd)
+	convertCmd.AddCommand(convertBytes32ToStringCmd)
+	convertCmd.AddCommand(convertToUintCmd)
+	convertCmd.AddCommand(convertToIntCmd)
+	convertCmd.AddCommand(convertToBoolCmd)
+	convertCmd.AddCommand(convertAddressToBytes32Cmd)
+	convertCmd.AddCommand(convertBytes32ToAddressCmd)
+	convertCmd.AddCommand(convertSwapEndianCmd)
+}
+
+var convertCmd = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert literals: utf8 and hex, padding, bytes32 string, integer bounds, bool, address and bytes32, endianness, base64, base58, bech32, IPFS CID, data URI",
+}
+
+// convertHexArgs runs convert on the decoded bytes of each hex arg, and prints the result.
+func convertHexArgs(convert func(data []byte) (string, error)) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			result, err := convert(common.FromHex(arg))
+			checkErr(err)
+			fmt.Printf("%v\n", result)
+		}
+	}
+}
+
+var convertUtf8ToHexCmd = &cobra.Command{
+	Use:   "utf8-to-hex text ...",
+	Short: "Convert UTF-8 text to hex",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Printf("%v\n", hexutil.Encode([]byte(arg)))
+		}
+	},
+}
+
+var convertHexToUtf8Cmd = &cobra.Command{
+	Use:   "hex-to-utf8 hex ...",
+	Short: "Convert hex to UTF-8 text",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("%v is not UTF-8 text", hexutil.Encode(data))
+		}
+		return string(data), nil
+	}),
+}
+
+var convertPadCmd = &cobra.Command{
+	Use:   "pad hex ...",
+	Short: "Pad hex with zero bytes to --len bytes, on the left by default or on the right with --right",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		padded, err := ethutil.PadBytes(data, convertPadLen, convertPadRight)
+		return hexutil.Encode(padded), err
+	}),
+}
+
+var convertUnpadCmd = &cobra.Command{
+	Use:   "unpad hex ...",
+	Short: "Remove zero bytes of hex, on the left by default or on the right with --right",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		return hexutil.Encode(ethutil.UnpadBytes(data, convertUnpadRight)), nil
+	}),
+}
+
+var convertStringToBytes32Cmd = &cobra.Command{
+	Use:   "string-to-bytes32 text ...",
+	Short: "Encode short string (at most 32 bytes) as bytes32, padded on the right",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			h, err := ethutil.StringToBytes32(arg)
+			checkErr(err)
+			fmt.Printf("%v\n", h.Hex())
+		}
+	},
+}
+
+var convertBytes32ToStringCmd = &cobra.Command{
+	Use:   "bytes32-to-string bytes32 ...",
+	Short: "Decode bytes32 encoded short string",
+	Args:  validateHexArgs("bytes32"),
+	Run:   convertHexArgs(ethutil.Bytes32ToString),
+}
+
+var convertToUintCmd = &cobra.Command{
+	Use:   "to-uint value ...",
+	Short: "Convert decimal or hex value to uint<--bits> with bounds checking, print decimal and hex",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			n, err := ethutil.ParseInteger(arg)
+			checkErr(err)
+			checkErr(ethutil.CheckUint(n, convertBits))
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", n)
+				continue
+			}
+			fmt.Printf("%v  %v\n", n, hexutil.EncodeBig(n))
+		}
+	},
+}
+
+var convertToIntCmd = &cobra.Command{
+	Use:   "to-int value ...",
+	Short: "Convert decimal or hex (two's complement) value to int<--bits> with bounds checking, print decimal and two's complement hex",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			n, err := ethutil.ParseInteger(arg)
+			checkErr(err)
+			if has0xPrefix(arg) { // hex is two's complement, e.g. 0xff is -1 of int8
+				checkErr(ethutil.CheckUint(n, convertBits))
+				n = ethutil.FromTwosComplement(n, convertBits)
+			}
+			checkErr(ethutil.CheckInt(n, convertBits))
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", n)
+				continue
+			}
+			padded, err := ethutil.PadBytes(ethutil.ToTwosComplement(n, convertBits).Bytes(), convertBits/8, false)
+			checkErr(err)
+			fmt.Printf("%v  %v\n", n, hexutil.Encode(padded))
+		}
+	},
+}
+
+var convertToBoolCmd = &cobra.Command{
+	Use:   "to-bool value ...",
+	Short: "Decode abi encoded bool from decimal or hex value, only 0 and 1 are valid",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			n, err := ethutil.ParseInteger(arg)
+			checkErr(err)
+			b, err := ethutil.ParseBool(n)
+			checkErr(err)
+			fmt.Printf("%v\n", b)
+		}
+	},
+}
+
+var convertAddressToBytes32Cmd = &cobra.Command{
+	Use:   "address-to-bytes32 address ...",
+	Short: "Pad address on the left to bytes32, as it's encoded in abi and storage",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires address")
+		}
+		for i := range args {
+			if !isValidAddressArg(cmd.Context(), args[i]) {
+				return fmt.Errorf("%v is not a valid eth address", args[i])
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Printf("%v\n", ethutil.AddressToBytes32(mustParseAddressArg(cmd.Context(), arg)).Hex())
+		}
+	},
+}
+
+var convertBytes32ToAddressCmd = &cobra.Command{
+	Use:   "bytes32-to-address bytes32 ...",
+	Short: "Extract address from bytes32, the upper 12 bytes must be zero",
+	Args:  validateHexArgs("bytes32"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		address, err := ethutil.Bytes32ToAddress(data)
+		return address.Hex(), err
+	}),
+}
+
+var convertSwapEndianCmd = &cobra.Command{
+	Use:   "swap-endian hex ...",
+	Short: "Reverse byte order of hex, i.e. convert big-endian to little-endian and vice versa",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		return hexutil.Encode(ethutil.ReverseBytes(data)), nil
+	}),
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var convertBase64Url bool
+var convertBase58Check bool
+var convertBech32m bool
+var convertDataURIOutput string
+
+func init() {
+	convertHexToBase64Cmd.Flags().BoolVarP(&convertBase64Url, "url", "", false, "use URL-safe alphabet without padding")
+	convertHexToBase58Cmd.Flags().BoolVarP(&convertBase58Check, "check", "", false, "append 4 bytes double sha256 checksum (base58check)")
+	convertBase58ToHexCmd.Flags().BoolVarP(&convertBase58Check, "check", "", false, "verify and remove 4 bytes double sha256 checksum (base58check)")
+	convertBech32EncodeCmd.Flags().BoolVarP(&convertBech32m, "m", "", false, "use bech32m (BIP-350) checksum instead of bech32 (BIP-173)")
+	convertDataURIDecodeCmd.Flags().StringVarP(&convertDataURIOutput, "output", "o", "", "write the decoded data to this file, default is printed to stdout")
+
+	convertCmd.AddCommand(convertHexToBase64Cmd)
+	convertCmd.AddCommand(convertBase64ToHexCmd)
+	convertCmd.AddCommand(convertHexToBase58Cmd)
+	convertCmd.AddCommand(convertBase58ToHexCmd)
+	convertCmd.AddCommand(convertBech32EncodeCmd)
+	convertCmd.AddCommand(convertBech32DecodeCmd)
+	convertCmd.AddCommand(convertCidToV1Cmd)
+	convertCmd.AddCommand(convertCidToV0Cmd)
+	convertCmd.AddCommand(convertDataURIDecodeCmd)
+}
+
+var convertHexToBase64Cmd = &cobra.Command{
+	Use:   "hex-to-base64 hex ...",
+	Short: "Encode hex as base64",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		if convertBase64Url {
+			return base64.RawURLEncoding.EncodeToString(data), nil
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	}),
+}
+
+var convertBase64ToHexCmd = &cobra.Command{
+	Use:   "base64-to-hex base64 ...",
+	Short: "Decode standard or URL-safe base64 to hex",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			data, err := ethutil.Base64Decode(arg)
+			checkErr(err)
+			fmt.Printf("%v\n", hexutil.Encode(data))
+		}
+	},
+}
+
+var convertHexToBase58Cmd = &cobra.Command{
+	Use:   "hex-to-base58 hex ...",
+	Short: "Encode hex as base58 (Bitcoin alphabet, also used by IPFS CIDv0 and Solana), or base58check with --check",
+	Args:  validateHexArgs("hex"),
+	Run: convertHexArgs(func(data []byte) (string, error) {
+		if convertBase58Check {
+			return ethutil.Base58CheckEncode(data), nil
+		}
+		return ethutil.Base58Encode(data), nil
+	}),
+}
+
+var convertBase58ToHexCmd = &cobra.Command{
+	Use:   "base58-to-hex base58 ...",
+	Short: "Decode base58 to hex, or base58check with --check",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			var data []byte
+			var err error
+			if convertBase58Check {
+				data, err = ethutil.Base58CheckDecode(arg)
+			} else {
+				data, err = ethutil.Base58Decode(arg)
+			}
+			checkErr(err)
+			fmt.Printf("%v\n", hexutil.Encode(data))
+		}
+	},
+}
+
+var convertBech32EncodeCmd = &cobra.Command{
+	Use:   "bech32-encode hrp hex ...",
+	Short: "Encode hex (e.g. eth address) as bech32 with human-readable part, e.g. address of Cosmos chains",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("requires hrp and hex")
+		}
+		return validateHexArgs("hex")(cmd, args[1:])
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args[1:] {
+			encoded, err := ethutil.Bech32Encode(args[0], common.FromHex(arg), convertBech32m)
+			checkErr(err)
+			fmt.Printf("%v\n", encoded)
+		}
+	},
+}
+
+var convertBech32DecodeCmd = &cobra.Command{
+	Use:   "bech32-decode bech32 ...",
+	Short: "Decode bech32 or bech32m string, print human-readable part and data hex",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			hrp, data, bech32m, err := ethutil.Bech32Decode(arg)
+			checkErr(err)
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", hexutil.Encode(data))
+				continue
+			}
+			var variant = "bech32"
+			if bech32m {
+				variant = "bech32m"
+			}
+			fmt.Printf("%v  %v  %v\n", hrp, hexutil.Encode(data), variant)
+		}
+	},
+}
+
+var convertCidToV1Cmd = &cobra.Command{
+	Use:   "cid-to-v1 cid ...",
+	Short: "Convert IPFS CIDv0 (Qm...) to CIDv1 (bafy...)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			cid, err := ethutil.CIDv0ToV1(arg)
+			checkErr(err)
+			fmt.Printf("%v\n", cid)
+		}
+	},
+}
+
+var convertCidToV0Cmd = &cobra.Command{
+	Use:   "cid-to-v0 cid ...",
+	Short: "Convert IPFS CIDv1 (bafy...) of dag-pb and sha2-256 to CIDv0 (Qm...)",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			cid, err := ethutil.CIDv1ToV0(arg)
+			checkErr(err)
+			fmt.Printf("%v\n", cid)
+		}
+	},
+}
+
+var convertDataURIDecodeCmd = &cobra.Command{
+	Use:   "data-uri-decode data-uri",
+	Short: "Decode data URI (e.g. base64 JSON returned by tokenURI of on-chain NFT), binary data is printed as hex",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		mediaType, data, err := ethutil.ParseDataURI(args[0])
+		checkErr(err)
+		if convertDataURIOutput != "" {
+			checkErr(os.WriteFile(convertDataURIOutput, data, 0644))
+			if !globalOptTerseOutput {
+				fmt.Printf("%v (%v bytes) is written to %v\n", mediaType, len(data), convertDataURIOutput)
+			}
+			return
+		}
+		if !utf8.Valid(data) {
+			fmt.Printf("%v\n", hexutil.Encode(data))
+			return
+		}
+		fmt.Printf("%s\n", data)
+	},
+}
@@ -3,9 +3,13 @@ package cmd
 import (
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -15,7 +19,7 @@ import (
 var decodeTxCmd = &cobra.Command{
 	Use:   "decode-tx tx-data",
 	Short: "Decode raw transaction",
-	Args: func(cmd *cobra.Command, args []string) error {
+	Args: inputArgs(func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
 			return fmt.Errorf("requires tx-data")
 		}
@@ -27,24 +31,80 @@ var decodeTxCmd = &cobra.Command{
 			return fmt.Errorf("tx-data must hex string")
 		}
 		return nil
-	},
+	}),
 	Run: func(cmd *cobra.Command, args []string) {
-		rawTxHexData := args[0]
+		var count int
+		forEachInput(args, []string{jsonlKeyRawTx}, func(rawTxHexData string) {
+			if globalOptJsonl {
+				printDecodedTxJSONL(rawTxHexData)
+				return
+			}
+			if count > 0 {
+				fmt.Printf("\n")
+			}
+			count++
+
+			if strings.HasPrefix(rawTxHexData, "0x") {
+				rawTxHexData = rawTxHexData[2:] // remove leading 0x
+			}
+
+			var firstHex = rawTxHexData[0:2]
+			transactionType, err := strconv.ParseInt(firstHex, 16, 64)
+			checkErr(err)
+
+			if transactionType > 0x7f { // EIP-155
+				decodeEip155(rawTxHexData)
+			} else { // EIP-2718
+				decodeEip2718(int(transactionType), rawTxHexData[2:])
+			}
+		})
+	},
+}
 
-		if strings.HasPrefix(rawTxHexData, "0x") {
-			rawTxHexData = rawTxHexData[2:] // remove leading 0x
-		}
+// decodedTx is the JSONL output of decode-tx
+type decodedTx struct {
+	TxHash               common.Hash     `json:"tx_hash"`
+	Type                 uint8           `json:"type"`
+	ChainId              *big.Int        `json:"chain_id"`
+	Nonce                uint64          `json:"nonce"`
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to"` // null for contract creation
+	Value                *big.Int        `json:"value"`
+	Gas                  uint64          `json:"gas"`
+	GasPrice             *big.Int        `json:"gas_price,omitempty"`
+	MaxFeePerGas         *big.Int        `json:"max_fee_per_gas,omitempty"`
+	MaxPriorityFeePerGas *big.Int        `json:"max_priority_fee_per_gas,omitempty"`
+	Data                 hexutil.Bytes   `json:"data"`
+	RawTx                string          `json:"raw_tx"`
+}
 
-		var firstHex = rawTxHexData[0:2]
-		transactionType, err := strconv.ParseInt(firstHex, 16, 64)
-		checkErr(err)
+// printDecodedTxJSONL prints the decoded raw tx as one line of JSON.
+func printDecodedTxJSONL(rawTx string) {
+	tx, err := ethutil.ParseRawTx(rawTx)
+	checkErr(err)
+	from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	checkErr(err)
 
-		if transactionType > 0x7f { // EIP-155
-			decodeEip155(rawTxHexData)
-		} else { // EIP-2718
-			decodeEip2718(int(transactionType), rawTxHexData[2:])
-		}
-	},
+	decoded := decodedTx{
+		TxHash:  tx.Hash(),
+		Type:    tx.Type(),
+		ChainId: tx.ChainId(),
+		Nonce:   tx.Nonce(),
+		From:    from,
+		To:      tx.To(),
+		Value:   tx.Value(),
+		Gas:     tx.Gas(),
+		Data:    tx.Data(),
+	}
+	decoded.RawTx, err = ethutil.GenRawTx(tx)
+	checkErr(err)
+	if tx.Type() == types.DynamicFeeTxType {
+		decoded.MaxFeePerGas = tx.GasFeeCap()
+		decoded.MaxPriorityFeePerGas = tx.GasTipCap()
+	} else {
+		decoded.GasPrice = tx.GasPrice()
+	}
+	printJSONL(decoded)
 }
 
 func decodeEip155(rawTxHexData string) {
@@ -86,9 +146,9 @@ func decodeEip155(rawTxHexData string) {
 	hash := singer.Hash(tx)
 	fmt.Printf("hash before ecdsa sign (hex) = %x\n", hash.Bytes())
 
-	fmt.Printf("ecdsa recovery id = %d\n", getRecoveryId(v))
+	fmt.Printf("ecdsa recovery id = %d\n", ethutil.GetRecoveryId(v))
 
-	pubkeyBytes, err := RecoverPubkey(v, r, s, hash.Bytes())
+	pubkeyBytes, err := ethutil.RecoverPubkey(v, r, s, hash.Bytes())
 	checkErr(err)
 	fmt.Printf("uncompressed 65 bytes public key of sender (hex) = %x\n", pubkeyBytes)
 
@@ -159,7 +219,7 @@ func decodeEip2930(transactionType int, transactionPayload string) {
 	hash := singer.Hash(tx)
 	fmt.Printf("hash before ecdsa sign (hex) = %x\n", hash.Bytes())
 
-	pubkeyBytes, err := RecoverPubkey(accessListTx.V, accessListTx.R, accessListTx.S, hash.Bytes())
+	pubkeyBytes, err := ethutil.RecoverPubkey(accessListTx.V, accessListTx.R, accessListTx.S, hash.Bytes())
 	checkErr(err)
 	fmt.Printf("uncompressed 65 bytes public key of sender (hex) = %x\n", pubkeyBytes)
 
@@ -219,7 +279,7 @@ func decodeEip1559(transactionType int, transactionPayload string) {
 	hash := singer.Hash(tx)
 	fmt.Printf("hash before ecdsa sign (hex) = %x\n", hash.Bytes())
 
-	pubkeyBytes, err := RecoverPubkey(dynamicFeeTx.V, dynamicFeeTx.R, dynamicFeeTx.S, hash.Bytes())
+	pubkeyBytes, err := ethutil.RecoverPubkey(dynamicFeeTx.V, dynamicFeeTx.R, dynamicFeeTx.S, hash.Bytes())
 	checkErr(err)
 	fmt.Printf("uncompressed 65 bytes public key of sender (hex) = %x\n", pubkeyBytes)
 
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// nodeDefiLlamaChainMap maps network to the chain name used by DefiLlama coins api
+var nodeDefiLlamaChainMap = map[string]string{
+	nodeMainnet: "ethereum",
+	nodeBsc:     "bsc",
+	nodeHeco:    "heco",
+}
+
+func init() {
+	defiCmd.AddCommand(defiProtocolCmd)
+	defiCmd.AddCommand(defiTokenCmd)
+}
+
+// formatUsd formats value as "$1.23B", "$4.56M", "$7.89K" or "$0.12".
+func formatUsd(value decimal.Decimal) string {
+	units := []struct {
+		suffix string
+		size   decimal.Decimal
+	}{
+		{"B", decimal.New(1, 9)},
+		{"M", decimal.New(1, 6)},
+		{"K", decimal.New(1, 3)},
+	}
+	for _, unit := range units {
+		if value.Abs().GreaterThanOrEqual(unit.size) {
+			return "$" + value.Div(unit.size).StringFixed(2) + unit.suffix
+		}
+	}
+	return "$" + value.StringFixed(2)
+}
+
+// formatChange formats percentage change as "+1.23%", or "-" if it's unknown.
+func formatChange(change decimal.NullDecimal) string {
+	if !change.Valid {
+		return "-"
+	}
+	if change.Decimal.IsNegative() {
+		return change.Decimal.StringFixed(2) + "%"
+	}
+	return "+" + change.Decimal.StringFixed(2) + "%"
+}
+
+// defiLlamaTokenKey converts token address (on chain of --node), <chain>:<address> or CoinGecko coin id to the key
+// of DefiLlama coins api.
+func defiLlamaTokenKey(token string) (string, error) {
+	if strings.Contains(token, ":") {
+		return token, nil
+	}
+	if isValidEthAddress(token) {
+		chain, ok := nodeDefiLlamaChainMap[globalOptNode]
+		if !ok {
+			return "", fmt.Errorf("network %v is not supported by defillama, specify token as <chain>:<address>", globalOptNode)
+		}
+		return chain + ":" + common.HexToAddress(token).Hex(), nil
+	}
+	return "coingecko:" + strings.ToLower(token), nil
+}
+
+var defiCmd = &cobra.Command{
+	Use:   "defi",
+	Short: "Look up protocol TVL and token market data from DefiLlama",
+}
+
+var defiProtocolCmd = &cobra.Command{
+	Use:   "protocol name",
+	Short: "Show TVL, chains and market cap of protocol, name is DefiLlama slug (e.g. uniswap-v3), name or symbol",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		protocols, err := ethutil.DefiLlamaProtocols(cmd.Context(), "")
+		checkErr(err)
+		protocol, candidates := ethutil.FindDefiLlamaProtocol(protocols, args[0])
+		if protocol == nil {
+			if len(candidates) == 0 {
+				log.Fatalf("protocol %v is not found in defillama", args[0])
+			}
+			sort.Slice(candidates, func(i, j int) bool { return candidates[i].Tvl.GreaterThan(candidates[j].Tvl) })
+			if len(candidates) > 10 {
+				candidates = candidates[:10]
+			}
+			var slugs []string
+			for _, c := range candidates {
+				slugs = append(slugs, c.Slug)
+			}
+			log.Fatalf("protocol %v is not found in defillama, do you mean: %v", args[0], strings.Join(slugs, ", "))
+		}
+
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", protocol.Tvl.StringFixed(0))
+			return
+		}
+		fmt.Printf("name: %v (%v)\n", protocol.Name, protocol.Slug)
+		if protocol.Symbol != "" && protocol.Symbol != "-" {
+			fmt.Printf("symbol: %v\n", protocol.Symbol)
+		}
+		fmt.Printf("category: %v\n", protocol.Category)
+		fmt.Printf("url: %v\n", protocol.Url)
+		fmt.Printf("tvl: %v (1d %v, 7d %v)\n", formatUsd(protocol.Tvl), formatChange(protocol.Change1d), formatChange(protocol.Change7d))
+		if protocol.Mcap.Valid && protocol.Mcap.Decimal.IsPositive() {
+			fmt.Printf("mcap: %v\n", formatUsd(protocol.Mcap.Decimal))
+		}
+		fmt.Printf("chains:\n")
+		for _, chain := range protocol.Chains {
+			fmt.Printf("  %v: %v\n", chain, formatUsd(protocol.ChainTvls[chain]))
+		}
+	},
+}
+
+var defiTokenCmd = &cobra.Command{
+	Use:   "token token ...",
+	Short: "Show price and 24h change of token, token is address (on chain of --node), <chain>:<address> or CoinGecko coin id",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var keys []string
+		for _, arg := range args {
+			key, err := defiLlamaTokenKey(arg)
+			checkErr(err)
+			keys = append(keys, key)
+		}
+		tokens, err := ethutil.DefiLlamaTokens(cmd.Context(), "", keys)
+		checkErr(err)
+		if len(tokens) < len(keys) {
+			found := make(map[string]bool)
+			for _, token := range tokens {
+				found[token.Key] = true
+			}
+			for _, key := range keys {
+				if !found[key] {
+					log.Printf("token %v is not found in defillama", key)
+				}
+			}
+		}
+
+		for _, token := range tokens {
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", token.Price)
+				continue
+			}
+			fmt.Printf("%v %v: $%v (24h %v), decimals %v, confidence %v, updated at %v\n", token.Key, token.Symbol, token.Price,
+				formatChange(token.Change24h), token.Decimals, token.Confidence, time.Unix(token.Timestamp, 0).UTC().Format(time.RFC3339))
+		}
+	},
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestFormatUsd(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"0.123", "$0.12"},
+		{"999.99", "$999.99"},
+		{"1500", "$1.50K"},
+		{"14012345678", "$14.01B"},
+		{"-2500000", "$-2.50M"},
+	}
+
+	for i, test := range tests {
+		if got := formatUsd(decimal.RequireFromString(test.value)); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
@@ -1,17 +1,15 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/hex"
-	"fmt"
-	"github.com/shopspring/decimal"
-	"github.com/spf13/cobra"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
 )
 
 var deployABIFile string
@@ -24,11 +22,14 @@ var deployValue string
 func init() {
 	deployCmd.Flags().StringVarP(&deployABIFile, "abi-file", "", "", "the path of abi file, if 'constructor signature' is specified, this option cannot be specified")
 	deployCmd.Flags().StringVarP(&deployBinFile, "bin-file", "", "", "the path of byte code file of contract")
-	deployCmd.Flags().StringVarP(&deploySrcFile, "src-file", "", "", "the path of source file of contract, launch tool solcjs to compile it. If this option is specified, --bin-file, --abi-file, 'constructor signature' cannot be specified")
+	deployCmd.Flags().StringVarP(&deploySrcFile, "source", "", "", "the path of source file of contract, compile it by solc or solcjs (see --solc). If this option is specified, --bin-file, --abi-file, 'constructor signature' cannot be specified")
+	deployCmd.Flags().StringVarP(&deploySrcFile, "src-file", "", "", "same as --source")
+	_ = deployCmd.Flags().MarkDeprecated("src-file", "use --source instead")
 	deployCmd.Flags().StringVarP(&deployContractName, "contract-name", "", "", "the contract in source file you want to deploy, if it's not specified, auto find the LAST contract in source file")
 	deployCmd.Flags().StringVarP(&deployValueUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
 	deployCmd.Flags().StringVarP(&deployValue, "value", "", "0", "the amount you want to transfer when deploy contract, unit is ether and can be changed by --unit")
 
+	addFlags(deployCmd, txFlags)
 }
 
 var deployCmd = &cobra.Command{
@@ -36,38 +37,18 @@ var deployCmd = &cobra.Command{
 	Short: "Deploy contract",
 	Run: func(cmd *cobra.Command, args []string) {
 		if deployBinFile == "" && deploySrcFile == "" {
-			log.Fatalf("must specify --bin-file or --src-file")
+			log.Fatalf("must specify --bin-file or --source")
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
 		var funcSignature string
 		var inputArgData []string
-		var bytecodeByteArray []byte
+		var abiContent string
+		var bytecodeHex string
 
 		if deploySrcFile != "" { // source file provided
-			// compile source using solcjs, set deployABIFile, deployBinFile
-
-			dir, err := os.MkdirTemp("", "ethutil-deploy")
-			if err != nil {
-				log.Fatal(err)
-			}
-			defer os.RemoveAll(dir)
-
-			//log.Printf("solcjs output to dir %v", dir)
-
-			cmd := exec.Command("solcjs", "--base-path", ".", "--include-path", "./node_modules", "--bin", "--abi", "--output-dir", dir, deploySrcFile)
-			log.Printf("executing command %v", cmd.String())
-			var out bytes.Buffer
-			var stderr bytes.Buffer
-			cmd.Stdout = &out
-			cmd.Stderr = &stderr
-			err = cmd.Run()
-			if err != nil {
-				log.Fatal(fmt.Sprint(err) + ": " + stderr.String())
-			}
-
 			if deployContractName == "" {
 				log.Printf("option --contract-name is not specified, use last contract in source file")
 				// If contract name is not specified, use last contract in source file
@@ -77,53 +58,51 @@ var deployCmd = &cobra.Command{
 				}
 			}
 
-			log.Printf("deploying contract %v", deployContractName)
-
-			abiMatches, err := filepath.Glob(fmt.Sprintf("%v/*_%v.abi", dir, deployContractName))
+			output, _ := compileSources(cmd.Context(), []string{deploySrcFile})
+			fullName, contract, err := output.Contract(deployContractName)
 			checkErr(err)
-			if len(abiMatches) > 1 {
-				log.Fatalf("more than one abi files found: %v", abiMatches)
+			if contract.Evm.Bytecode.Object == "" {
+				log.Fatalf("contract %v is abstract or interface, it can't be deployed", fullName)
 			}
-			deployABIFile = abiMatches[0]
-
-			binMatches, err := filepath.Glob(fmt.Sprintf("%v/*_%v.bin", dir, deployContractName))
-			checkErr(err)
-			if len(binMatches) > 1 {
-				log.Fatalf("more than one bin files found: %v", binMatches)
+			log.Printf("deploying contract %v", fullName)
+			abiContent = string(contract.Abi)
+			bytecodeHex = contract.Evm.Bytecode.Object
+		} else {
+			if deployABIFile != "" {
+				content, err := os.ReadFile(deployABIFile)
+				checkErr(err)
+				abiContent = string(content)
 			}
-			deployBinFile = binMatches[0]
+			bytecode, err := os.ReadFile(deployBinFile)
+			checkErr(err)
+			bytecodeHex = string(bytecode)
 		}
 
-		if deployABIFile == "" { // abi file not provided
+		if abiContent == "" { // abi not provided
 			if len(args) > 0 {
 				funcSignature = args[0]
 				inputArgData = args[1:]
 			}
-		} else { // abi file provided
-			abiContent, err := os.ReadFile(deployABIFile)
-			checkErr(err)
-
-			funcSignature, err = extractFuncDefinition(string(abiContent), "constructor")
+		} else { // abi provided
+			var err error
+			funcSignature, err = ethutil.ExtractFuncDefinition(abiContent, "constructor")
 			checkErr(err)
 			// log.Printf("extract func definition from abi: %v", funcSignature)
 
 			inputArgData = args[0:]
 		}
 
-		bytecode, err := os.ReadFile(deployBinFile)
-		checkErr(err)
-
-		var bytecodeHex = strings.TrimSpace(string(bytecode))
+		bytecodeHex = strings.TrimSpace(bytecodeHex)
 		// remove leading 0x
 		if strings.HasPrefix(bytecodeHex, "0x") {
 			bytecodeHex = bytecodeHex[2:]
 		}
-		bytecodeByteArray, err = hex.DecodeString(bytecodeHex)
+		bytecodeByteArray, err := hex.DecodeString(bytecodeHex)
 		if err != nil {
 			log.Fatal("--bin-file invalid")
 		}
 
-		txData, err := buildTxDataForContractDeploy(funcSignature, inputArgData, bytecodeByteArray)
+		txData, err := ethutil.BuildTxDataForContractDeploy(funcSignature, inputArgData, bytecodeByteArray)
 		checkErr(err)
 		// log.Printf("txData=%s", hex.Dump(txData))
 
@@ -134,7 +113,7 @@ var deployCmd = &cobra.Command{
 		var value = decimal.RequireFromString(deployValue)
 		var valueInWei = unify2Wei(value, deployValueUnit)
 
-		tx, err := Transact(globalClient.RpcClient, globalClient.EthClient, buildPrivateKeyFromHex(globalOptPrivateKey), nil, valueInWei.BigInt(), nil, txData)
+		tx, err := Transact(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), nil, valueInWei.BigInt(), nil, txData)
 		checkErr(err)
 
 		log.Printf("transaction %s finished", tx)
@@ -161,23 +140,3 @@ func findContractName(deploySrcFile string) string {
 
 	return lastContractName
 }
-
-func buildTxDataForContractDeploy(funcSignature string, inputArgData []string, bytecode []byte) ([]byte, error) {
-	if funcSignature == "" { // no constructor
-		return bytecode, nil
-	}
-
-	_, funcArgTypes, err := parseFuncSignature(funcSignature)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(funcArgTypes) != len(inputArgData) {
-		return nil, fmt.Errorf("invalid input, there are %v args in constructor, but %v args are provided", len(funcArgTypes), len(inputArgData))
-	}
-	data, err := encodeParameters(funcArgTypes, inputArgData)
-	if err != nil {
-		return nil, fmt.Errorf("encodeParameters fail: %v", err)
-	}
-	return append(bytecode, data...), nil
-}
@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"encoding/hex"
-	"github.com/spf13/cobra"
 	"log"
 	"math/big"
 	"os"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
 )
 
+func init() {
+	addFlags(deployErc20Cmd, txFlags)
+}
+
 var deployErc20Cmd = &cobra.Command{
 	Use:   "deploy-erc20 [total-supply [name [symbol [decimals]]]]",
 	Short: "Deploy an ERC20 token",
@@ -42,7 +48,7 @@ var deployErc20Cmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
 		var funcSignature = "constructor(uint256, string, string, uint8)"
 		var inputArgData = []string{totalSupply, name, symbol, decimals}
@@ -53,7 +59,7 @@ var deployErc20Cmd = &cobra.Command{
 			log.Fatal("--bin-file invalid")
 		}
 
-		txData, err := buildTxDataForContractDeploy(funcSignature, inputArgData, bytecodeByteArray)
+		txData, err := ethutil.BuildTxDataForContractDeploy(funcSignature, inputArgData, bytecodeByteArray)
 		checkErr(err)
 		// log.Printf("txData=%s", hex.Dump(txData))
 
@@ -61,7 +67,7 @@ var deployErc20Cmd = &cobra.Command{
 			log.Fatalf("--private-key is required for deploy command")
 		}
 
-		tx, err := Transact(globalClient.RpcClient, globalClient.EthClient, buildPrivateKeyFromHex(globalOptPrivateKey), nil, big.NewInt(0), nil, txData)
+		tx, err := Transact(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), nil, big.NewInt(0), nil, txData)
 		checkErr(err)
 
 		log.Printf("transaction %s finished", tx)
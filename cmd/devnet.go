@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const devnetFundMethodAuto = "auto"
+const devnetFundMethodSetBalance = "set-balance"
+const devnetFundMethodTransfer = "transfer"
+
+var devnetFundBalance string
+var devnetFundUnit string
+var devnetFundMnemonic string
+var devnetFundCount int
+var devnetFundMethod string
+
+func init() {
+	devnetFundCmd.Flags().StringVarP(&devnetFundBalance, "balance", "", "10000", "the target balance of each address, unit is ether and can be changed by --unit")
+	devnetFundCmd.Flags().StringVarP(&devnetFundUnit, "unit", "u", "ether", "wei | gwei | ether, unit of --balance")
+	devnetFundCmd.Flags().StringVarP(&devnetFundMnemonic, "mnemonic", "", ethutil.DevnetMnemonic, "fund the first --count accounts (path m/44'/60'/0'/0/i) of this mnemonic if no address is given")
+	devnetFundCmd.Flags().IntVarP(&devnetFundCount, "count", "n", 10, "the number of accounts of --mnemonic to fund")
+	devnetFundCmd.Flags().StringVarP(&devnetFundMethod, "method", "", devnetFundMethodAuto, "auto | set-balance | transfer, set-balance uses anvil_setBalance or hardhat_setBalance, "+
+		"transfer tops up from --private-key or the first account of eth_accounts (the faucet of geth --dev), auto tries set-balance first")
+
+	devnetCmd.AddCommand(devnetFundCmd)
+
+	addFlags(devnetCmd, txFlags)
+}
+
+var devnetCmd = &cobra.Command{
+	Use:   "devnet",
+	Short: "Local development network (anvil, hardhat, geth --dev) helpers",
+}
+
+var devnetFundCmd = &cobra.Command{
+	Use:   "fund [address ...]",
+	Short: "Fund addresses (default the first accounts of the standard mnemonic) to target balance",
+	Args: func(cmd *cobra.Command, args []string) error {
+		for i := range args {
+			if !isValidAddressArg(cmd.Context(), args[i]) {
+				return fmt.Errorf("%v is not a valid eth address", args[i])
+			}
+		}
+		if len(args) == 0 {
+			if !bip39.IsMnemonicValid(devnetFundMnemonic) {
+				return fmt.Errorf("invalid mnemonic")
+			}
+			if devnetFundCount <= 0 {
+				return fmt.Errorf("--count must be greater than 0")
+			}
+		}
+		if _, err := decimal.NewFromString(devnetFundBalance); err != nil {
+			return fmt.Errorf("invalid --balance: %w", err)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, devnetFundUnit) {
+			return fmt.Errorf("invalid --unit %v", devnetFundUnit)
+		}
+		if !contains([]string{devnetFundMethodAuto, devnetFundMethodSetBalance, devnetFundMethodTransfer}, devnetFundMethod) {
+			return fmt.Errorf("invalid --method %v", devnetFundMethod)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		var addresses []common.Address
+		for _, arg := range args {
+			addresses = append(addresses, mustParseAddressArg(cmd.Context(), arg))
+		}
+		if len(args) == 0 {
+			privateKeys, err := bip44MnemonicKeys(devnetFundMnemonic, devnetFundCount)
+			checkErr(err)
+			for _, privateKey := range privateKeys {
+				addresses = append(addresses, extractAddressFromPrivateKey(privateKey))
+			}
+		}
+		target := unify2Wei(decimal.RequireFromString(devnetFundBalance), devnetFundUnit).BigInt()
+
+		devnetFund(cmd.Context(), os.Stdout, addresses, target, devnetFundMethod)
+	},
+}
+
+// devnetFund funds addresses to target balance by method, and prints the balances to out.
+func devnetFund(ctx context.Context, out io.Writer, addresses []common.Address, target *big.Int, method string) {
+	if method != devnetFundMethodTransfer {
+		err := ethutil.DevnetSetBalance(ctx, globalClient.RpcClient, addresses[0], target)
+		if err == nil {
+			method = devnetFundMethodSetBalance
+		} else if errors.Is(err, ethutil.ErrSetBalanceNotSupported) && method == devnetFundMethodAuto {
+			log.Printf("setBalance rpc is not supported, fall back to transfer")
+			method = devnetFundMethodTransfer
+		} else {
+			checkErr(err)
+		}
+	}
+
+	var faucet common.Address
+	if method == devnetFundMethodTransfer && globalOptPrivateKey == "" {
+		accounts, err := ethutil.DevnetAccounts(ctx, globalClient.RpcClient)
+		checkErr(err)
+		if len(accounts) == 0 {
+			log.Fatalf("no unlocked account in node, --private-key is required for transfer")
+		}
+		faucet = accounts[0]
+		log.Printf("faucet account is %v", faucet.Hex())
+	}
+
+	for i, address := range addresses {
+		if method == devnetFundMethodSetBalance {
+			if i > 0 { // the first one is already set above
+				checkErr(ethutil.DevnetSetBalance(ctx, globalClient.RpcClient, address, target))
+			}
+			fmt.Fprintf(out, "%v %v\n", address.Hex(), wei2Other(bigInt2Decimal(target), devnetFundUnit))
+			continue
+		}
+
+		balance, err := globalClient.EthClient.BalanceAt(ctx, address, nil)
+		checkErr(err)
+		if balance.Cmp(target) >= 0 {
+			log.Printf("balance of %v is enough, skip it", address.Hex())
+			fmt.Fprintf(out, "%v %v\n", address.Hex(), wei2Other(bigInt2Decimal(balance), devnetFundUnit))
+			continue
+		}
+		amount := new(big.Int).Sub(target, balance)
+		if globalOptPrivateKey != "" {
+			_, err = Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &address, amount, nil, nil)
+			checkErr(err)
+		} else {
+			txHash, err := ethutil.DevnetSendValue(ctx, globalClient.RpcClient, faucet, address, amount)
+			checkErr(err)
+			_, err = ethutil.WaitReceipt(ctx, globalClient, txHash, ethutil.WaitOptions{PollInterval: time.Second, Logf: log.Printf})
+			checkErr(err)
+		}
+		fmt.Fprintf(out, "%v %v\n", address.Hex(), wei2Other(bigInt2Decimal(target), devnetFundUnit))
+	}
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const devnetEngineAuto = "auto"
+const devnetEngineAnvil = "anvil"
+const devnetEngineGeth = "geth"
+
+var devnetUpEngine string
+var devnetUpBinary string
+var devnetUpPort int
+var devnetUpChainId uint64
+var devnetUpBlockTime int
+var devnetUpAccounts int
+var devnetUpBalance string
+var devnetUpDataDir string
+var devnetUpReadyTimeout time.Duration
+
+func init() {
+	devnetUpCmd.Flags().StringVarP(&devnetUpEngine, "engine", "", devnetEngineAuto, "auto | anvil | geth, auto uses anvil if it's in PATH, otherwise geth --dev")
+	devnetUpCmd.Flags().StringVarP(&devnetUpBinary, "binary", "", "", "the path of anvil or geth, default is found in PATH")
+	devnetUpCmd.Flags().IntVarP(&devnetUpPort, "port", "", 8545, "the http rpc port")
+	devnetUpCmd.Flags().Uint64VarP(&devnetUpChainId, "chain-id", "", 31337, "the chain id, only supported by anvil (chain id of geth --dev is 1337)")
+	devnetUpCmd.Flags().IntVarP(&devnetUpBlockTime, "block-time", "", 0, "seconds between blocks, 0 means mining a block for each tx")
+	devnetUpCmd.Flags().IntVarP(&devnetUpAccounts, "accounts", "", 10, "the number of funded accounts of the standard mnemonic")
+	devnetUpCmd.Flags().StringVarP(&devnetUpBalance, "balance", "", "10000", "the balance (in ether) of each funded account")
+	devnetUpCmd.Flags().StringVarP(&devnetUpDataDir, "datadir", "", "", "the data directory of geth, default is in memory")
+	devnetUpCmd.Flags().DurationVarP(&devnetUpReadyTimeout, "ready-timeout", "", 30*time.Second, "how long to wait for rpc readiness")
+
+	devnetCmd.AddCommand(devnetUpCmd)
+}
+
+// devnetCommand builds command line of engine, accounts of standard mnemonic are funded by anvil itself, and by
+// devnetFund for geth.
+func devnetCommand(engine string, binary string) *exec.Cmd {
+	if engine == devnetEngineAnvil {
+		args := []string{
+			"--port", strconv.Itoa(devnetUpPort),
+			"--chain-id", strconv.FormatUint(devnetUpChainId, 10),
+			"--accounts", strconv.Itoa(devnetUpAccounts),
+			"--balance", devnetUpBalance,
+			"--mnemonic", ethutil.DevnetMnemonic,
+		}
+		if devnetUpBlockTime > 0 {
+			args = append(args, "--block-time", strconv.Itoa(devnetUpBlockTime))
+		}
+		return exec.Command(binary, args...)
+	}
+
+	args := []string{
+		"--dev",
+		"--dev.period", strconv.Itoa(devnetUpBlockTime),
+		"--http",
+		"--http.addr", "127.0.0.1",
+		"--http.port", strconv.Itoa(devnetUpPort),
+		"--http.api", "eth,net,web3,debug,txpool",
+		"--ipcdisable",
+		"--nodiscover",
+		"--maxpeers", "0",
+	}
+	if devnetUpDataDir != "" {
+		args = append(args, "--datadir", devnetUpDataDir)
+	}
+	return exec.Command(binary, args...)
+}
+
+// waitRpcReady polls eth_chainId of nodeUrl until it succeeds or timeout.
+func waitRpcReady(ctx context.Context, nodeUrl string, timeout time.Duration) (*ethutil.Client, error) {
+	var deadline = time.Now().Add(timeout)
+	for {
+		client, err := ethutil.Dial(ctx, nodeUrl)
+		if err == nil {
+			if _, err = client.EthClient.ChainID(ctx); err == nil {
+				return client, nil
+			}
+			client.RpcClient.Close()
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("rpc %v is not ready after %v: %w", nodeUrl, timeout, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+var devnetUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Launch a local dev chain (anvil or geth --dev), wait for rpc readiness and print the funded accounts",
+	Long: "Launch a local dev chain (anvil or geth --dev), wait for rpc readiness and print the funded accounts. " +
+		"The accounts are derived from the standard mnemonic, they are funded by faucet account in geth --dev. " +
+		"The chain is stopped by Ctrl-C.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("devnet up accepts no args")
+		}
+		if !contains([]string{devnetEngineAuto, devnetEngineAnvil, devnetEngineGeth}, devnetUpEngine) {
+			return fmt.Errorf("invalid --engine %v", devnetUpEngine)
+		}
+		if devnetUpAccounts <= 0 {
+			return fmt.Errorf("--accounts must be greater than 0")
+		}
+		if _, err := decimal.NewFromString(devnetUpBalance); err != nil {
+			return fmt.Errorf("invalid --balance: %w", err)
+		}
+		if devnetUpBlockTime < 0 {
+			return fmt.Errorf("--block-time must not be negative")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var engine = devnetUpEngine
+		if engine == devnetEngineAuto {
+			engine = devnetEngineGeth
+			if _, err := exec.LookPath(devnetEngineAnvil); err == nil {
+				engine = devnetEngineAnvil
+			}
+		}
+		var binary = devnetUpBinary
+		if binary == "" {
+			var err error
+			binary, err = exec.LookPath(engine)
+			if err != nil {
+				log.Fatalf("%v is not found in PATH, install it or specify --binary", engine)
+			}
+		}
+
+		child := devnetCommand(engine, binary)
+		logFile, err := os.CreateTemp("", "ethutil-devnet-*.log")
+		checkErr(err)
+		defer logFile.Close()
+		child.Stdout = logFile
+		child.Stderr = logFile
+		log.Printf("starting %v", child.String())
+		checkErr(child.Start())
+		log.Printf("the output of %v is written to %v", engine, logFile.Name())
+
+		var exited = make(chan error, 1)
+		go func() { exited <- child.Wait() }()
+		var stop = func() {
+			_ = child.Process.Signal(syscall.SIGTERM)
+			select {
+			case <-exited:
+			case <-time.After(10 * time.Second):
+				_ = child.Process.Kill()
+			}
+		}
+
+		ctx := cmd.Context()
+		nodeUrl := fmt.Sprintf("http://127.0.0.1:%d", devnetUpPort)
+		client, err := waitRpcReady(ctx, nodeUrl, devnetUpReadyTimeout)
+		if err != nil {
+			stop()
+			log.Fatalf("%v, see %v", err, logFile.Name())
+		}
+		globalClient = client
+		chainId, err := client.EthClient.ChainID(ctx)
+		checkErr(err)
+
+		privateKeys, err := bip44MnemonicKeys(ethutil.DevnetMnemonic, devnetUpAccounts)
+		checkErr(err)
+		if engine == devnetEngineGeth {
+			log.Printf("funding %v accounts of the standard mnemonic from faucet", len(privateKeys))
+			var addresses []common.Address
+			for _, privateKey := range privateKeys {
+				addresses = append(addresses, extractAddressFromPrivateKey(privateKey))
+			}
+			// balances are printed below with private keys
+			devnetFund(ctx, io.Discard, addresses, unify2Wei(decimal.RequireFromString(devnetUpBalance), unitEther).BigInt(), devnetFundMethodTransfer)
+		}
+
+		fmt.Printf("rpc: %v, chain id: %v, engine: %v\n", nodeUrl, chainId, engine)
+		fmt.Printf("mnemonic: %v\n", ethutil.DevnetMnemonic)
+		fmt.Printf("funded accounts (%v ether each):\n", devnetUpBalance)
+		for i, privateKey := range privateKeys {
+			fmt.Printf("(%d) %v %v\n", i, extractAddressFromPrivateKey(privateKey).Hex(), hexutil.Encode(crypto.FromECDSA(privateKey)))
+		}
+		fmt.Printf("try: ethutil --node-url %v balance %v\n", nodeUrl, extractAddressFromPrivateKey(privateKeys[0]).Hex())
+
+		var signals = make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		select {
+		case <-signals:
+			log.Printf("stopping %v", engine)
+			stop()
+		case err := <-exited:
+			log.Fatalf("%v exited: %v, see %v", engine, err, logFile.Name())
+		case <-ctx.Done():
+			stop()
+		}
+	},
+}
@@ -7,6 +7,10 @@ import (
 	"github.com/spf13/cobra"
 )
 
+func init() {
+	addFlags(dropTxCmd, txFlags)
+}
+
 var dropTxCmd = &cobra.Command{
 	Use:   "drop-tx",
 	Short: "Drop pending tx for address",
@@ -16,19 +20,19 @@ var dropTxCmd = &cobra.Command{
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
 		// send 0 eth to itself
 		// see https://medium.com/fidcom/how-to-remove-ethereum-pending-transaction-f876f211896d
 
-		gasPrice, err := getGasPrice(globalClient.EthClient)
+		gasPrice, err := getGasPrice(cmd.Context(), globalClient.EthClient)
 		checkErr(err)
 
 		gasPrice.Add(gasPrice, big.NewInt(10*1000000000)) // plus 10 gwei
 		log.Printf("gas price change to %v wei", gasPrice)
 
 		addr := extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey)).String()
-		if tx, err := TransferHelper(globalClient.RpcClient, globalClient.EthClient, globalOptPrivateKey, addr, big.NewInt(0), gasPrice, nil); err != nil {
+		if tx, err := TransferHelper(cmd.Context(), globalClient, globalOptPrivateKey, addr, big.NewInt(0), gasPrice, nil); err != nil {
 			log.Fatalf("transfer 0 wei to self fail: %v", err)
 		} else {
 			log.Printf("transfer 0 wei to self finished, tx = %v", tx)
@@ -15,9 +15,37 @@ import (
 )
 
 var dumpAddrCmdDerivationPath string
+var dumpAddrCmdDerivationPreset string
+var dumpAddrCmdIndex int
 
 func init() {
 	dumpAddrCmd.Flags().StringVarP(&dumpAddrCmdDerivationPath, "derivation-path", "", "m/44'/60'/0'/0/0", "the HD derivation path")
+	dumpAddrCmd.Flags().StringVarP(&dumpAddrCmdDerivationPreset, "derivation-preset", "", "", strings.Join(derivationPresetNames, " | ")+", the HD derivation path convention of wallet, overrides --derivation-path")
+	dumpAddrCmd.Flags().IntVarP(&dumpAddrCmdIndex, "index", "", 0, "the account index used with --derivation-preset")
+}
+
+// derivationPresetNames are the names of derivationPathPresets, in the order of popularity
+var derivationPresetNames = []string{"bip44", "ledger-live", "ledger-legacy", "trezor", "mew"}
+
+// derivationPathPresets are the HD derivation path conventions of wallets, %d is replaced by account index
+var derivationPathPresets = map[string]string{
+	"bip44":         "m/44'/60'/0'/0/%d", // MetaMask, Trust Wallet, etc
+	"ledger-live":   "m/44'/60'/%d'/0/0",
+	"ledger-legacy": "m/44'/60'/0'/%d",
+	"trezor":        "m/44'/60'/0'/0/%d",
+	"mew":           "m/44'/60'/0'/%d", // MyEtherWallet before it switched to bip44
+}
+
+// derivationPathOfPreset returns the derivation path of account index in preset.
+func derivationPathOfPreset(preset string, index int) (string, error) {
+	pathFormat, ok := derivationPathPresets[preset]
+	if !ok {
+		return "", fmt.Errorf("invalid derivation preset: %v", preset)
+	}
+	if index < 0 {
+		return "", fmt.Errorf("invalid account index: %v", index)
+	}
+	return fmt.Sprintf(pathFormat, index), nil
 }
 
 var dumpAddrCmd = &cobra.Command{
@@ -37,6 +65,13 @@ var dumpAddrCmd = &cobra.Command{
 			}
 			return fmt.Errorf("invalid private-key-or-mnemonics: %v", arg)
 		}
+		if dumpAddrCmdDerivationPreset != "" {
+			path, err := derivationPathOfPreset(dumpAddrCmdDerivationPreset, dumpAddrCmdIndex)
+			if err != nil {
+				return err
+			}
+			dumpAddrCmdDerivationPath = path
+		}
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -138,3 +173,21 @@ func mnemonicToPrivateKey(mnemonic string, derivationPath string) ([]byte, error
 	privateKey := currentKey.Key // 32 bytes private key
 	return privateKey, nil
 }
+
+// bip44MnemonicKeys returns private keys of the first count accounts (path m/44'/60'/0'/0/i) of mnemonic.
+func bip44MnemonicKeys(mnemonic string, count int) ([]*ecdsa.PrivateKey, error) {
+	var privateKeys []*ecdsa.PrivateKey
+	for i := 0; i < count; i++ {
+		path, _ := derivationPathOfPreset("bip44", i)
+		privateKeyBytes, err := mnemonicToPrivateKey(mnemonic, path)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := crypto.ToECDSA(privateKeyBytes)
+		if err != nil {
+			return nil, err
+		}
+		privateKeys = append(privateKeys, privateKey)
+	}
+	return privateKeys, nil
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var ensRenewYears uint64
+var ensController string
+
+func init() {
+	ensRenewCmd.Flags().Uint64VarP(&ensRenewYears, "years", "", 1, "renew the name for this number of years")
+	ensRenewCmd.Flags().StringVarP(&ensController, "controller", "", "", "the ETHRegistrarController address, default is the controller of mainnet or sepolia")
+
+	ensCmd.AddCommand(ensResolveCmd)
+	ensCmd.AddCommand(ensLookupCmd)
+	ensCmd.AddCommand(ensTextCmd)
+	ensCmd.AddCommand(ensExpiryCmd)
+	ensCmd.AddCommand(ensSetAddrCmd)
+	ensCmd.AddCommand(ensSetTextCmd)
+	ensCmd.AddCommand(ensSetPrimaryCmd)
+	ensCmd.AddCommand(ensRenewCmd)
+
+	addFlags(ensCmd, txFlags)
+}
+
+var ensCmd = &cobra.Command{
+	Use:   "ens",
+	Short: "ENS helpers, e.g. resolve name, set records and primary name, renew name",
+	Long: "ENS helpers, e.g. resolve name, set records and primary name, renew name. " +
+		"Records are set in the resolver of name, the tx must be sent by the owner (or manager) of name.",
+}
+
+// validateEnsArgs checks the first arg is a valid ens name (normalized in place), n args are required.
+func validateEnsArgs(n int, names string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("requires %v", names)
+		}
+		name, err := ethutil.EnsNormalize(args[0])
+		if err != nil {
+			return err
+		}
+		args[0] = name
+		return nil
+	}
+}
+
+var ensResolveCmd = &cobra.Command{
+	Use:   "resolve name",
+	Short: "Show eth address of name",
+	Args:  validateEnsArgs(1, "name"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		address, err := ethutil.EnsResolve(cmd.Context(), globalClient.EthClient, args[0])
+		checkErr(err)
+		fmt.Printf("%v\n", address.Hex())
+	},
+}
+
+var ensLookupCmd = &cobra.Command{
+	Use:   "lookup address",
+	Short: "Show primary name (reverse record) of address, the name is verified by resolving it",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		address := mustParseAddressArg(cmd.Context(), args[0])
+		name, err := ethutil.EnsLookup(cmd.Context(), globalClient.EthClient, address)
+		checkErr(err)
+		if name == "" {
+			log.Fatalf("%v has no primary name", address.Hex())
+		}
+		if resolved, err := ethutil.EnsResolve(cmd.Context(), globalClient.EthClient, name); err != nil || resolved != address {
+			log.Fatalf("primary name %v of %v does not resolve to it, the reverse record can be set by anyone and must not be trusted", name, address.Hex())
+		}
+		fmt.Printf("%v\n", name)
+	},
+}
+
+var ensTextCmd = &cobra.Command{
+	Use:   "text name key",
+	Short: "Show text record of name, e.g. url, avatar, com.twitter",
+	Args:  validateEnsArgs(2, "name and key"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		value, err := ethutil.EnsText(cmd.Context(), globalClient.EthClient, args[0], args[1])
+		checkErr(err)
+		fmt.Printf("%v\n", value)
+	},
+}
+
+var ensExpiryCmd = &cobra.Command{
+	Use:   "expiry name",
+	Short: "Show expiry and status (active, grace period or expired) of .eth name",
+	Args:  validateEnsArgs(1, "name"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		expires, err := ethutil.EnsExpiry(cmd.Context(), globalClient.EthClient, args[0])
+		checkErr(err)
+		if expires.IsZero() {
+			log.Fatalf("%v is not registered", args[0])
+		}
+		status := ethutil.EnsExpiryStatus(expires, time.Now())
+		if globalOptTerseOutput {
+			fmt.Printf("%v %v\n", expires.UTC().Format(time.RFC3339), status)
+			return
+		}
+		fmt.Printf("expires: %v\n", expires.UTC().Format(time.RFC3339))
+		fmt.Printf("grace period ends: %v\n", expires.Add(ethutil.EnsGracePeriod).UTC().Format(time.RFC3339))
+		fmt.Printf("status: %v\n", status)
+	},
+}
+
+var ensSetAddrCmd = &cobra.Command{
+	Use:   "set-addr name [address]",
+	Short: "Set eth address of name, default is the address of --private-key",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("requires name and optional address")
+		}
+		if len(args) == 2 && !isValidAddressArg(cmd.Context(), args[1]) {
+			return fmt.Errorf("%v is not a valid eth address", args[1])
+		}
+		return validateEnsArgs(len(args), "name and optional address")(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var address common.Address
+		if len(args) == 2 {
+			address = mustParseAddressArg(cmd.Context(), args[1])
+		} else if globalOptPrivateKey != "" {
+			address = extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		}
+		data, err := ethutil.EnsSetAddrData(args[0], address)
+		checkErr(err)
+		execEnsResolverTx(cmd, args[0], data)
+	},
+}
+
+var ensSetTextCmd = &cobra.Command{
+	Use:   "set-text name key value",
+	Short: "Set text record of name, empty value deletes the record",
+	Args:  validateEnsArgs(3, "name, key and value"),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := ethutil.EnsSetTextData(args[0], args[1], args[2])
+		checkErr(err)
+		execEnsResolverTx(cmd, args[0], data)
+	},
+}
+
+var ensSetPrimaryCmd = &cobra.Command{
+	Use:   "set-primary name",
+	Short: "Set primary name (reverse record) of --private-key",
+	Args:  validateEnsArgs(1, "name"),
+	Run: func(cmd *cobra.Command, args []string) {
+		initEnsTx(cmd)
+
+		sender := extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		if resolved, err := ethutil.EnsResolve(cmd.Context(), globalClient.EthClient, args[0]); err != nil || resolved != sender {
+			log.Printf("WARNING: %v does not resolve to %v, the primary name is not shown by apps until set-addr is done", args[0], sender.Hex())
+		}
+		reverseRegistrar, err := ethutil.EnsReverseRegistrar(cmd.Context(), globalClient.EthClient)
+		checkErr(err)
+		data, err := ethutil.EnsSetNameData(args[0])
+		checkErr(err)
+		sendEnsTx(cmd.Context(), reverseRegistrar, big.NewInt(0), data)
+	},
+}
+
+var ensRenewCmd = &cobra.Command{
+	Use:   "renew name",
+	Short: "Renew .eth name, anyone can renew any name before its grace period ends",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if ensController != "" && !isValidAddressArg(cmd.Context(), ensController) {
+			return fmt.Errorf("--controller %v is not a valid eth address", ensController)
+		}
+		if ensRenewYears == 0 {
+			return fmt.Errorf("--years must be greater than 0")
+		}
+		return validateEnsArgs(1, "name")(cmd, args)
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		initEnsTx(cmd)
+		ctx := cmd.Context()
+
+		var controller common.Address
+		if ensController != "" {
+			controller = mustParseAddressArg(ctx, ensController)
+		} else {
+			chainID, err := globalClient.EthClient.ChainID(ctx)
+			checkErr(err)
+			var ok bool
+			if controller, ok = ethutil.EnsRegistrarControllers[chainID.Uint64()]; !ok {
+				log.Fatalf("ETHRegistrarController of chain %v is unknown, please specify it by --controller", chainID)
+			}
+		}
+
+		expires, err := ethutil.EnsExpiry(ctx, globalClient.EthClient, args[0])
+		checkErr(err)
+		if expires.IsZero() || ethutil.EnsExpiryStatus(expires, time.Now()) == ethutil.EnsStatusExpired {
+			log.Fatalf("%v is not registered or has expired (grace period ended), it can not be renewed", args[0])
+		}
+
+		duration := time.Duration(ensRenewYears) * 365 * 24 * time.Hour
+		price, err := ethutil.EnsRentPrice(ctx, globalClient.EthClient, controller, args[0], duration)
+		checkErr(err)
+		// the price in eth follows usd price of oracle, pay 5% more for the fluctuation, the excess is refunded
+		value := new(big.Int).Div(new(big.Int).Mul(price, big.NewInt(105)), big.NewInt(100))
+		log.Printf("renew %v (expires %v) for %v years, price %v ether", args[0], expires.UTC().Format(time.RFC3339),
+			ensRenewYears, wei2Other(bigInt2Decimal(price), unitEther))
+
+		data, err := ethutil.EnsRenewData(args[0], duration)
+		checkErr(err)
+		sendEnsTx(ctx, controller, value, data)
+	},
+}
+
+// initEnsTx checks --private-key and connects node for ens commands sending tx.
+func initEnsTx(cmd *cobra.Command) {
+	if globalOptPrivateKey == "" {
+		log.Fatalf("--private-key is required for %v command", cmd.Name())
+	}
+	log.Printf("Current network is %v", globalOptNode)
+	InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+}
+
+// execEnsResolverTx sends tx calling resolver of name with data.
+func execEnsResolverTx(cmd *cobra.Command, name string, data []byte) {
+	initEnsTx(cmd)
+	resolver, err := ethutil.EnsResolver(cmd.Context(), globalClient.EthClient, name)
+	checkErr(err)
+	sendEnsTx(cmd.Context(), resolver, big.NewInt(0), data)
+}
+
+// sendEnsTx sends tx calling to with data and value from --private-key.
+func sendEnsTx(ctx context.Context, to common.Address, value *big.Int, data []byte) {
+	if globalOptShowInputData {
+		log.Printf("input data: 0x%x", data)
+	}
+	tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &to, value, nil, data)
+	checkErr(err)
+	log.Printf("transaction %s finished", tx)
+}
@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"log"
 	"math/big"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
@@ -35,6 +38,29 @@ func changeContractState(erc20FuncName string) bool {
 	return false
 }
 
+// approveIfInsufficient approves amount of token to spender if allowance of sender (the address of privateKey) is
+// less than amount.
+func approveIfInsufficient(ctx context.Context, privateKey *ecdsa.PrivateKey, token, spender common.Address, amount *big.Int) {
+	owner := extractAddressFromPrivateKey(privateKey)
+	values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, token, erc20FuncSignature["allowance"], []string{owner.Hex(), spender.Hex()})
+	checkErr(err)
+	if allowance := values[0].(*big.Int); allowance.Cmp(amount) >= 0 {
+		return
+	}
+	log.Printf("allowance of %v for %v is insufficient, approve %v to it", token.Hex(), spender.Hex(), amount)
+	approveData, err := ethutil.BuildTxInputData(erc20FuncSignature["approve"], []string{spender.Hex(), amount.String()})
+	checkErr(err)
+	tx, err := Transact(ctx, globalClient, privateKey, &token, big.NewInt(0), nil, approveData)
+	checkErr(err)
+	log.Printf("transaction %s finished", tx)
+}
+
+func init() {
+	addFlags(erc20Cmd, txFlags)
+	addFlags(erc20Cmd, blockFlags)
+	addFlags(erc20Cmd, fiatFlags)
+}
+
 var erc20Cmd = &cobra.Command{
 	Use:   "erc20 contract-address approve/transfer/transferFrom/balanceOf/allowance/totalSupply/name/symbol/decimals/mint [args]",
 	Short: "Call ERC20 contract, a helper for subcommand call/query",
@@ -42,7 +68,7 @@ var erc20Cmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
 		contractAddr := args[0]
 		funcName := args[1]
@@ -50,7 +76,7 @@ var erc20Cmd = &cobra.Command{
 
 		if !globalOptDryRun {
 			// don't check contract address if --dry-run specified
-			isContract, err := isContractAddress(globalClient.EthClient, common.HexToAddress(contractAddr))
+			isContract, err := ethutil.IsContractAddress(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr))
 			if err != nil {
 				panic(err)
 			}
@@ -64,7 +90,7 @@ var erc20Cmd = &cobra.Command{
 			log.Fatalf("%v is NOT supported", funcName)
 		}
 
-		txInputData, err := buildTxInputData(funcSignature, inputArgData)
+		txInputData, err := ethutil.BuildTxInputData(funcSignature, inputArgData)
 		checkErr(err)
 
 		if globalOptShowInputData {
@@ -76,13 +102,13 @@ var erc20Cmd = &cobra.Command{
 				log.Fatalf("--private-key is required for this command")
 			} else {
 				var contract = common.HexToAddress(contractAddr)
-				tx, err := Transact(globalClient.RpcClient, globalClient.EthClient, buildPrivateKeyFromHex(globalOptPrivateKey), &contract, big.NewInt(0), nil, txInputData)
+				tx, err := Transact(cmd.Context(), globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &contract, big.NewInt(0), nil, txInputData)
 				checkErr(err)
 
 				log.Printf("transaction %s finished", tx)
 			}
 		} else {
-			output, err := Call(globalClient.EthClient, common.HexToAddress(contractAddr), txInputData)
+			output, err := ethutil.Call(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr), txInputData, stateBlock(cmd.Context()))
 			checkErr(err)
 
 			printContractReturnData(funcSignature, output)
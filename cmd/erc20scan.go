@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+// erc20ScanMulticallChunkSize is the max number of balanceOf calls in one multicall, which keeps the gas of eth_call
+// below the limit of most nodes
+const erc20ScanMulticallChunkSize = 500
+
+// erc20ScanPriceChunkSize is the max number of tokens in one request of DefiLlama coins api
+const erc20ScanPriceChunkSize = 50
+
+var erc20ScanTokenList string
+
+func init() {
+	erc20ScanCmd.Flags().StringVarP(&erc20ScanTokenList, "token-list", "", ethutil.DefaultTokenListUrl, "the token list (https://tokenlists.org format), url or file")
+
+	erc20Cmd.AddCommand(erc20ScanCmd)
+}
+
+// erc20ScanPrices returns usd prices of tokens on current network by DefiLlama, tokens without price are absent.
+func erc20ScanPrices(ctx context.Context, tokens []common.Address) map[common.Address]decimal.Decimal {
+	chain, ok := nodeDefiLlamaChainMap[globalOptNode]
+	if !ok {
+		log.Printf("fiat value is unavailable for network %v", globalOptNode)
+		return nil
+	}
+	var prices = make(map[common.Address]decimal.Decimal)
+	for start := 0; start < len(tokens); start += erc20ScanPriceChunkSize {
+		end := start + erc20ScanPriceChunkSize
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		var keys []string
+		for _, token := range tokens[start:end] {
+			keys = append(keys, chain+":"+token.Hex())
+		}
+		result, err := ethutil.DefiLlamaTokens(ctx, "", keys)
+		if err != nil {
+			log.Printf("get token prices from defillama fail: %v", err)
+			return prices
+		}
+		for _, token := range result {
+			prices[common.HexToAddress(strings.TrimPrefix(token.Key, chain+":"))] = token.Price
+		}
+	}
+	return prices
+}
+
+// erc20ScanTokens returns tokens of current network in token list, which is an url or a file.
+func erc20ScanTokens(ctx context.Context, tokenList string) ([]ethutil.TokenListToken, error) {
+	chainID, err := globalClient.EthClient.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ethutil.LoadTokenList(ctx, tokenList)
+	if err != nil {
+		return nil, err
+	}
+	tokens := list.TokensOfChain(chainID.Uint64())
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no token of chain %v is found in token list %v", chainID, tokenList)
+	}
+	log.Printf("scanning %v tokens of chain %v in token list %v", len(tokens), chainID, list.Name)
+	return tokens, nil
+}
+
+// erc20ScanBalances returns non-zero balances of owner at block for tokens, balanceOf of tokens are called by
+// Multicall3 if it's deployed, otherwise by JSON-RPC batch requests.
+func erc20ScanBalances(ctx context.Context, owner common.Address, tokens []ethutil.TokenListToken, block *big.Int) ([]ethutil.TokenBalance, error) {
+	calls := ethutil.TokenBalanceCalls(owner, tokens)
+	var results []ethutil.MulticallResult
+	var err error
+	if isMulticallDeployed(ctx, globalClient.EthClient, block) {
+		results, err = ethutil.MulticallTryAggregate(ctx, globalClient.EthClient, calls, block, erc20ScanMulticallChunkSize)
+	} else {
+		results, err = ethutil.BatchTryEthCalls(ctx, globalClient.RpcClient, calls, block, globalOptRpcBatchSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ethutil.NonZeroTokenBalances(tokens, results), nil
+}
+
+var erc20ScanCmd = &cobra.Command{
+	Use:   "scan address",
+	Short: "Show non-zero balances of address for all tokens in token list",
+	Long: "Show non-zero balances of address for all tokens of current network in token list (default is the Uniswap " +
+		"default list), balanceOf of tokens are called by Multicall3 if it's deployed, otherwise by JSON-RPC batch " +
+		"requests. Values in USD from DefiLlama are shown if --show-fiat usd is specified.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("address is required")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		owner := mustParseAddressArg(ctx, args[0])
+		tokens, err := erc20ScanTokens(ctx, erc20ScanTokenList)
+		checkErr(err)
+		balances, err := erc20ScanBalances(ctx, owner, tokens, stateBlock(ctx))
+		checkErr(err)
+
+		var prices map[common.Address]decimal.Decimal
+		if globalOptShowFiat != "" && len(balances) > 0 {
+			if strings.ToLower(globalOptShowFiat) != "usd" {
+				log.Printf("fiat value of tokens is only available in usd, not in %v", globalOptShowFiat)
+			} else {
+				var addresses []common.Address
+				for _, balance := range balances {
+					addresses = append(addresses, balance.Token.Address)
+				}
+				prices = erc20ScanPrices(ctx, addresses)
+			}
+		}
+
+		type row struct {
+			token   ethutil.TokenListToken
+			balance decimal.Decimal
+			value   *decimal.Decimal // nil if price is unavailable
+		}
+		var rows []row
+		var total decimal.Decimal
+		for _, balance := range balances {
+			r := row{token: balance.Token, balance: bigInt2Decimal(balance.Balance).Shift(-int32(balance.Token.Decimals))}
+			if price, ok := prices[balance.Token.Address]; ok {
+				value := r.balance.Mul(price).Round(2)
+				r.value = &value
+				total = total.Add(value)
+			}
+			rows = append(rows, r)
+		}
+		// tokens of larger value first, then tokens without price by symbol
+		sort.SliceStable(rows, func(i, j int) bool {
+			if rows[i].value != nil && rows[j].value != nil {
+				return rows[i].value.GreaterThan(*rows[j].value)
+			}
+			if (rows[i].value == nil) != (rows[j].value == nil) {
+				return rows[i].value != nil
+			}
+			return rows[i].token.Symbol < rows[j].token.Symbol
+		})
+
+		for _, r := range rows {
+			line := map[string]any{
+				jsonlKeyAddress: owner.Hex(),
+				"token":         r.token.Address.Hex(),
+				"symbol":        r.token.Symbol,
+				"decimals":      r.token.Decimals,
+				"balance":       r.balance.String(),
+			}
+			if r.value != nil {
+				line["value_usd"] = r.value.StringFixed(2)
+			}
+			if printJSONL(line) {
+				continue
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v %v %v\n", r.token.Address.Hex(), r.token.Symbol, r.balance)
+				continue
+			}
+			var value string
+			if r.value != nil {
+				value = fmt.Sprintf(" (%v USD)", r.value.StringFixed(2))
+			}
+			fmt.Printf("%-10v %v %v (decimals %v)%v\n", r.token.Symbol, r.token.Address.Hex(), r.balance, r.token.Decimals, value)
+		}
+		if globalOptJsonl || globalOptTerseOutput {
+			return
+		}
+		if len(rows) == 0 {
+			fmt.Printf("no token balance of %v is found in %v tokens\n", owner.Hex(), len(tokens))
+			return
+		}
+		if prices != nil {
+			fmt.Printf("total: %v USD of %v tokens\n", total.StringFixed(2), len(rows))
+		} else {
+			fmt.Printf("total: %v tokens\n", len(rows))
+		}
+	},
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var erc4626Receiver string
+var erc4626Owner string
+var erc4626NoApprove bool
+
+// erc4626Op is a deposit or withdrawal operation of ERC-4626 vault. The amount of deposit and withdraw is assets,
+// the amount of mint and redeem is shares.
+type erc4626Op struct {
+	previewSig     string
+	txSig          string
+	amountIsAssets bool
+	hasOwner       bool
+}
+
+var erc4626Ops = map[string]erc4626Op{
+	"deposit":  {"previewDeposit(uint256)", "deposit(uint256,address)", true, false},
+	"mint":     {"previewMint(uint256)", "mint(uint256,address)", false, false},
+	"withdraw": {"previewWithdraw(uint256)", "withdraw(uint256,address,address)", true, true},
+	"redeem":   {"previewRedeem(uint256)", "redeem(uint256,address,address)", false, true},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{erc4626DepositCmd, erc4626MintCmd, erc4626WithdrawCmd, erc4626RedeemCmd} {
+		c.Flags().StringVarP(&erc4626Receiver, "receiver", "", "", "the receiver of shares (deposit, mint) or assets (withdraw, redeem), default is the sender")
+	}
+	for _, c := range []*cobra.Command{erc4626DepositCmd, erc4626MintCmd} {
+		c.Flags().BoolVarP(&erc4626NoApprove, "no-approve", "", false, "do not approve assets to vault automatically when allowance is insufficient")
+	}
+	for _, c := range []*cobra.Command{erc4626WithdrawCmd, erc4626RedeemCmd} {
+		c.Flags().StringVarP(&erc4626Owner, "owner", "", "", "the owner of shares, default is the sender, sender must have allowance of shares if it's not the owner")
+	}
+
+	erc4626Cmd.AddCommand(erc4626InfoCmd)
+	erc4626Cmd.AddCommand(erc4626ConvertToSharesCmd)
+	erc4626Cmd.AddCommand(erc4626ConvertToAssetsCmd)
+	erc4626Cmd.AddCommand(erc4626PreviewCmd)
+	erc4626Cmd.AddCommand(erc4626DepositCmd)
+	erc4626Cmd.AddCommand(erc4626MintCmd)
+	erc4626Cmd.AddCommand(erc4626WithdrawCmd)
+	erc4626Cmd.AddCommand(erc4626RedeemCmd)
+
+	addFlags(erc4626Cmd, txFlags)
+}
+
+var erc4626Cmd = &cobra.Command{
+	Use:   "erc4626",
+	Short: "Query and interact with ERC-4626 tokenized vaults",
+	Long: "Query and interact with ERC-4626 tokenized vaults (yield vaults). Amounts are in units of the token, i.e.\n" +
+		"assets are scaled by decimals of the asset token and shares are scaled by decimals of the vault.",
+}
+
+// erc4626Args returns a validator of args: vault address followed by amounts.
+func erc4626Args(usage string, amounts int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1+amounts {
+			return fmt.Errorf("requires %v", usage)
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		for _, amount := range args[1:] {
+			if _, err := decimal.NewFromString(amount); err != nil {
+				return fmt.Errorf("%v is not a valid amount", amount)
+			}
+		}
+		for _, address := range []string{erc4626Receiver, erc4626Owner} {
+			if address != "" && !isValidAddressArg(cmd.Context(), address) {
+				return fmt.Errorf("%v is not a valid eth address", address)
+			}
+		}
+		return nil
+	}
+}
+
+// erc4626VaultInfo connects to node and queries info of vault.
+func erc4626VaultInfo(ctx context.Context, vault common.Address) *ethutil.VaultInfo {
+	log.Printf("Current network is %v", globalOptNode)
+	InitGlobalClient(ctx, globalOptNodeUrl)
+	info, err := ethutil.QueryVaultInfo(ctx, globalClient.EthClient, vault)
+	if err != nil {
+		log.Fatalf("query vault %v fail, it may not be an ERC-4626 vault: %v", vault.Hex(), err)
+	}
+	return info
+}
+
+// erc4626Decimals returns decimals of assets or shares.
+func erc4626Decimals(info *ethutil.VaultInfo, isAssets bool) int32 {
+	if isAssets {
+		return int32(info.AssetDecimals)
+	}
+	return int32(info.ShareDecimals)
+}
+
+// erc4626ToUnits converts amount (e.g. 1.5) of assets or shares to the smallest unit.
+func erc4626ToUnits(info *ethutil.VaultInfo, amount string, isAssets bool) *big.Int {
+	return decimal.RequireFromString(amount).Shift(erc4626Decimals(info, isAssets)).BigInt()
+}
+
+// erc4626Format formats amount of assets or shares with decimals and symbol.
+func erc4626Format(info *ethutil.VaultInfo, amount *big.Int, isAssets bool) string {
+	symbol := info.ShareSymbol
+	if isAssets {
+		symbol = info.AssetSymbol
+	}
+	value := bigInt2Decimal(amount).Shift(-erc4626Decimals(info, isAssets)).String()
+	if globalOptTerseOutput || symbol == "" {
+		return value
+	}
+	return value + " " + symbol
+}
+
+var erc4626InfoCmd = &cobra.Command{
+	Use:   "info vault",
+	Short: "Show asset, total assets, total supply and share price of vault",
+	Args:  erc4626Args("vault", 0),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		vault := mustParseAddressArg(ctx, args[0])
+		info := erc4626VaultInfo(ctx, vault)
+		oneShare := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(info.ShareDecimals)), nil)
+		sharePrice, err := ethutil.VaultQuery(ctx, globalClient.EthClient, vault, "convertToAssets(uint256)", oneShare.String())
+		checkErr(err)
+
+		if printJSONL(map[string]any{
+			"vault":          vault.Hex(),
+			"asset":          info.Asset.Hex(),
+			"asset_symbol":   info.AssetSymbol,
+			"asset_decimals": info.AssetDecimals,
+			"share_symbol":   info.ShareSymbol,
+			"share_decimals": info.ShareDecimals,
+			"total_assets":   bigInt2Decimal(info.TotalAssets).Shift(-int32(info.AssetDecimals)).String(),
+			"total_supply":   bigInt2Decimal(info.TotalSupply).Shift(-int32(info.ShareDecimals)).String(),
+			"share_price":    bigInt2Decimal(sharePrice).Shift(-int32(info.AssetDecimals)).String(),
+		}) {
+			return
+		}
+		fmt.Printf("asset: %v\n", info.Asset.Hex())
+		fmt.Printf("asset decimals: %v\n", info.AssetDecimals)
+		fmt.Printf("share decimals: %v\n", info.ShareDecimals)
+		fmt.Printf("total assets: %v\n", erc4626Format(info, info.TotalAssets, true))
+		fmt.Printf("total supply: %v\n", erc4626Format(info, info.TotalSupply, false))
+		fmt.Printf("share price: %v\n", erc4626Format(info, sharePrice, true))
+	},
+}
+
+// runErc4626Query converts amount by view function funcSig of vault and prints the result.
+func runErc4626Query(ctx context.Context, vault common.Address, funcSig string, amount string, amountIsAssets bool) {
+	info := erc4626VaultInfo(ctx, vault)
+	input := erc4626ToUnits(info, amount, amountIsAssets)
+	output, err := ethutil.VaultQuery(ctx, globalClient.EthClient, vault, funcSig, input.String())
+	checkErr(err)
+	if printJSONL(map[string]any{
+		"vault":  vault.Hex(),
+		"func":   funcSig,
+		"input":  bigInt2Decimal(input).Shift(-erc4626Decimals(info, amountIsAssets)).String(),
+		"output": bigInt2Decimal(output).Shift(-erc4626Decimals(info, !amountIsAssets)).String(),
+	}) {
+		return
+	}
+	fmt.Printf("%v\n", erc4626Format(info, output, !amountIsAssets))
+}
+
+var erc4626ConvertToSharesCmd = &cobra.Command{
+	Use:   "convert-to-shares vault assets",
+	Short: "Convert amount of assets to shares, ignoring fees and limits",
+	Args:  erc4626Args("vault and assets", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Query(cmd.Context(), mustParseAddressArg(cmd.Context(), args[0]), "convertToShares(uint256)", args[1], true)
+	},
+}
+
+var erc4626ConvertToAssetsCmd = &cobra.Command{
+	Use:   "convert-to-assets vault shares",
+	Short: "Convert amount of shares to assets, ignoring fees and limits",
+	Args:  erc4626Args("vault and shares", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Query(cmd.Context(), mustParseAddressArg(cmd.Context(), args[0]), "convertToAssets(uint256)", args[1], false)
+	},
+}
+
+var erc4626PreviewCmd = &cobra.Command{
+	Use:   "preview deposit|mint|withdraw|redeem vault amount",
+	Short: "Preview the shares of deposit or withdraw, or the assets of mint or redeem, including fees",
+	Long: "Preview the result of operation at current block, including fees: deposit assets and withdraw assets show the\n" +
+		"shares minted and burned, mint shares and redeem shares show the assets required and received.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires deposit|mint|withdraw|redeem, vault and amount")
+		}
+		if _, ok := erc4626Ops[args[0]]; !ok {
+			return fmt.Errorf("invalid operation %v, must be deposit, mint, withdraw or redeem", args[0])
+		}
+		return erc4626Args("deposit|mint|withdraw|redeem, vault and amount", 1)(cmd, args[1:])
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		op := erc4626Ops[args[0]]
+		runErc4626Query(cmd.Context(), mustParseAddressArg(cmd.Context(), args[1]), op.previewSig, args[2], op.amountIsAssets)
+	},
+}
+
+// runErc4626Transact sends tx of operation opName, approving assets to vault first for deposit and mint.
+func runErc4626Transact(ctx context.Context, opName string, vault common.Address, amount string) {
+	if globalOptPrivateKey == "" {
+		log.Fatalf("--private-key is required for erc4626 %v command", opName)
+	}
+	op := erc4626Ops[opName]
+	info := erc4626VaultInfo(ctx, vault)
+	privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+	sender := extractAddressFromPrivateKey(privateKey)
+	receiver, owner := sender, sender
+	if erc4626Receiver != "" {
+		receiver = mustParseAddressArg(ctx, erc4626Receiver)
+	}
+	if erc4626Owner != "" {
+		owner = mustParseAddressArg(ctx, erc4626Owner)
+	}
+
+	units := erc4626ToUnits(info, amount, op.amountIsAssets)
+	preview, err := ethutil.VaultQuery(ctx, globalClient.EthClient, vault, op.previewSig, units.String())
+	checkErr(err)
+	log.Printf("%v %v, preview result is %v", opName, erc4626Format(info, units, op.amountIsAssets),
+		erc4626Format(info, preview, !op.amountIsAssets))
+
+	if !op.hasOwner && !erc4626NoApprove {
+		// assets pulled from sender: the amount of deposit, or the preview of mint
+		required := units
+		if !op.amountIsAssets {
+			required = preview
+		}
+		approveIfInsufficient(ctx, privateKey, info.Asset, vault, required)
+	}
+
+	txArgs := []string{units.String(), receiver.Hex()}
+	if op.hasOwner {
+		txArgs = append(txArgs, owner.Hex())
+	}
+	txData, err := ethutil.BuildTxInputData(op.txSig, txArgs)
+	checkErr(err)
+	tx, err := Transact(ctx, globalClient, privateKey, &vault, big.NewInt(0), nil, txData)
+	checkErr(err)
+	log.Printf("transaction %s finished", tx)
+}
+
+var erc4626DepositCmd = &cobra.Command{
+	Use:   "deposit vault assets",
+	Short: "Deposit assets to vault and mint shares to receiver",
+	Args:  erc4626Args("vault and assets", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Transact(cmd.Context(), "deposit", mustParseAddressArg(cmd.Context(), args[0]), args[1])
+	},
+}
+
+var erc4626MintCmd = &cobra.Command{
+	Use:   "mint vault shares",
+	Short: "Mint exact shares to receiver by depositing assets",
+	Args:  erc4626Args("vault and shares", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Transact(cmd.Context(), "mint", mustParseAddressArg(cmd.Context(), args[0]), args[1])
+	},
+}
+
+var erc4626WithdrawCmd = &cobra.Command{
+	Use:   "withdraw vault assets",
+	Short: "Withdraw exact assets from vault to receiver by burning shares of owner",
+	Args:  erc4626Args("vault and assets", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Transact(cmd.Context(), "withdraw", mustParseAddressArg(cmd.Context(), args[0]), args[1])
+	},
+}
+
+var erc4626RedeemCmd = &cobra.Command{
+	Use:   "redeem vault shares",
+	Short: "Redeem shares of owner and send assets to receiver",
+	Args:  erc4626Args("vault and shares", 1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runErc4626Transact(cmd.Context(), "redeem", mustParseAddressArg(cmd.Context(), args[0]), args[1])
+	},
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var estimateGasFrom string
+var estimateGasValue string
+var estimateGasUnit string
+var estimateGasHexData string
+var estimateGasFork string
+
+func init() {
+	estimateGasCmd.Flags().StringVarP(&estimateGasFrom, "from", "", "", "the caller address, default is the address of --private-key")
+	estimateGasCmd.Flags().StringVarP(&estimateGasValue, "value", "", "0", "the amount of eth sent with call, unit is ether and can be changed by --unit")
+	estimateGasCmd.Flags().StringVarP(&estimateGasUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+	estimateGasCmd.Flags().StringVarP(&estimateGasHexData, "hex-data", "", "", "the payload hex data of call, can not be used together with function signature")
+	estimateGasCmd.Flags().StringVarP(&estimateGasFork, "fork", "", "", "the hardfork of gas schedule of intrinsic gas, e.g. shanghai, prague, default is the fork of current chain at latest block")
+
+	addFlags(estimateGasCmd, fiatFlags)
+}
+
+// currentGasSchedule returns the gas schedule of the fork of current chain at latest block, the latest fork is used
+// for chains without known fork activations.
+func currentGasSchedule(ctx context.Context, client *ethclient.Client) (ethutil.GasSchedule, error) {
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return ethutil.GasSchedule{}, fmt.Errorf("ChainID fail: %w", err)
+	}
+	fork := ethutil.LatestGasFork
+	if _, ok := ethutil.KnownForkActivations[chainId.Uint64()]; ok {
+		header, err := client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return ethutil.GasSchedule{}, fmt.Errorf("HeaderByNumber fail: %w", err)
+		}
+		fork = ethutil.ForkAt(chainId.Uint64(), header.Number.Uint64(), header.Time)
+	}
+	return ethutil.GasScheduleOf(fork)
+}
+
+// offlineGasSchedule returns the gas schedule of the fork of chain at current time without querying node, forks
+// activated by block number are regarded as active. The latest fork is used for chains without known fork activations.
+func offlineGasSchedule(chainId *big.Int) (ethutil.GasSchedule, error) {
+	fork := ethutil.LatestGasFork
+	if chainId != nil {
+		fork = ethutil.ForkAt(chainId.Uint64(), math.MaxUint64, uint64(time.Now().Unix()))
+	}
+	return ethutil.GasScheduleOf(fork)
+}
+
+var estimateGasCmd = &cobra.Command{
+	Use:   "estimate-gas to-address ['function signature' arg1 arg2 ...]",
+	Short: "Estimate gas of a call, and report intrinsic gas, calldata cost and the cost in ether",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires to-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if estimateGasFrom != "" && !isValidAddressArg(cmd.Context(), estimateGasFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", estimateGasFrom)
+		}
+		if len(args) > 1 && estimateGasHexData != "" {
+			return fmt.Errorf("function signature and --hex-data can not be specified at the same time")
+		}
+		if !isValidHexString(estimateGasHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if _, err := decimal.NewFromString(estimateGasValue); err != nil {
+			return fmt.Errorf("%v is not a valid amount", estimateGasValue)
+		}
+		if estimateGasFork != "" && !contains(ethutil.GasForks, estimateGasFork) {
+			return fmt.Errorf("invalid --fork %v, must be one of %v", estimateGasFork, strings.Join(ethutil.GasForks, ", "))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+
+		var data []byte
+		var err error
+		if len(args) > 1 {
+			data, err = ethutil.BuildTxInputData(args[1], args[2:])
+			checkErr(err)
+		} else if estimateGasHexData != "" {
+			data, err = hexutil.Decode(estimateGasHexData)
+			checkErr(err)
+		}
+
+		var fromAddress common.Address
+		if estimateGasFrom != "" {
+			fromAddress = mustParseAddressArg(ctx, estimateGasFrom)
+		} else if globalOptPrivateKey != "" {
+			fromAddress = extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		}
+
+		to := mustParseAddressArg(ctx, args[0])
+		value := unify2Wei(decimal.RequireFromString(estimateGasValue), estimateGasUnit).BigInt()
+		gas, err := globalClient.EthClient.EstimateGas(ctx, ethereum.CallMsg{
+			From:  fromAddress,
+			To:    &to,
+			Value: value,
+			Data:  data,
+		})
+		checkErr(err)
+
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", gas)
+			return
+		}
+
+		var schedule ethutil.GasSchedule
+		if estimateGasFork != "" {
+			schedule, err = ethutil.GasScheduleOf(estimateGasFork)
+		} else {
+			schedule, err = currentGasSchedule(ctx, globalClient.EthClient)
+		}
+		checkErr(err)
+		intrinsic := schedule.IntrinsicGas(data, nil, false)
+		gasPrice, err := getGasPrice(ctx, globalClient.EthClient)
+		checkErr(err)
+		cost := bigInt2Decimal(gasPrice).Mul(decimal.NewFromInt(int64(gas)))
+
+		fmt.Printf("estimated gas: %v\n", gas)
+		fmt.Printf("intrinsic gas (%v): %v\n", schedule.Fork, intrinsic.Total)
+		fmt.Printf("  base: %v\n", intrinsic.Base)
+		fmt.Printf("  calldata: %v (%v zero bytes, %v non-zero bytes)\n", intrinsic.CalldataGas, intrinsic.ZeroBytes, intrinsic.NonZeroBytes)
+		if intrinsic.FloorGas > 0 {
+			fmt.Printf("calldata floor (eip7623): %v\n", intrinsic.FloorGas)
+		}
+		if minGas := intrinsic.MinGasLimit(); gas > minGas {
+			fmt.Printf("execution gas: %v\n", gas-minGas)
+		}
+		fmt.Printf("gas price: %v gwei\n", wei2Other(bigInt2Decimal(gasPrice), unitGwei))
+		fmt.Printf("cost: %v ether%v\n", wei2Other(cost, unitEther), fiatSuffix(ctx, cost.BigInt()))
+	},
+}
@@ -0,0 +1,343 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var explorerPage int
+var explorerOffset int
+var explorerStartBlock uint64
+var explorerEndBlock uint64
+var explorerAsc bool
+
+func init() {
+	for _, cmd := range []*cobra.Command{txListCmd, internalTxsCmd} {
+		cmd.Flags().IntVarP(&explorerPage, "page", "", 1, "the page number, starts from 1")
+		cmd.Flags().IntVarP(&explorerOffset, "offset", "", 20, "the number of txs per page")
+		cmd.Flags().Uint64VarP(&explorerStartBlock, "start-block", "", 0, "only list txs since this block")
+		cmd.Flags().Uint64VarP(&explorerEndBlock, "end-block", "", 0, "only list txs until this block, 0 means latest")
+		cmd.Flags().BoolVarP(&explorerAsc, "asc", "", false, "list the oldest txs first, default is the latest first")
+	}
+
+	addFlags(txListCmd, explorerFlags)
+	addFlags(internalTxsCmd, explorerFlags)
+	addFlags(explorerGasOracleCmd, explorerFlags)
+}
+
+// globalExplorerConfigs caches explorers of config file, see loadExplorerConfigs.
+var globalExplorerConfigs *[]explorerConfig
+
+// loadExplorerConfigs returns explorers of config file, nil if config file doesn't exist.
+func loadExplorerConfigs() ([]explorerConfig, error) {
+	if globalExplorerConfigs != nil {
+		return *globalExplorerConfigs, nil
+	}
+	var explorers []explorerConfig
+	if globalOptConfigFile != "" {
+		config, err := readConfigFile(globalOptConfigFile)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if config != nil {
+			explorers = config.Explorers
+		}
+	}
+	for _, explorer := range explorers {
+		if explorer.ApiUrl == "" {
+			return nil, fmt.Errorf("api_url of explorer of chains %v is missing in config file", explorer.ChainIds)
+		}
+		if explorer.Kind != "" && !contains([]string{ethutil.ExplorerKindEtherscan, ethutil.ExplorerKindBlockscout}, explorer.Kind) {
+			return nil, fmt.Errorf("invalid kind %v of explorer %v in config file", explorer.Kind, explorer.ApiUrl)
+		}
+		if explorer.Timeout != "" {
+			if _, err := time.ParseDuration(explorer.Timeout); err != nil {
+				return nil, fmt.Errorf("invalid timeout %v of explorer %v in config file", explorer.Timeout, explorer.ApiUrl)
+			}
+		}
+	}
+	globalExplorerConfigs = &explorers
+	return explorers, nil
+}
+
+// explorerOfChain returns client of explorer serving chainId in config file, or client of defaultUrl if no explorer
+// of config file serves chainId. The api key is apiKey, --explorer-api-key, api_key of the explorer in config file or
+// etherscan_api_key of profile, the first one specified.
+func explorerOfChain(chainId uint64, defaultUrl string, apiKey string) (*ethutil.ExplorerClient, error) {
+	explorers, err := loadExplorerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, explorer := range explorers {
+		if !containsChainId(explorer.ChainIds, chainId) {
+			continue
+		}
+		if apiKey == "" {
+			apiKey = globalOptExplorerApiKey
+		}
+		if apiKey == "" {
+			apiKey = explorer.ApiKey
+		}
+		client := newExplorerClientOf(explorer.ApiUrl, apiKey)
+		if explorer.EtherscanV2 {
+			client.ChainId = chainId
+		}
+		client.Timeout, _ = time.ParseDuration(explorer.Timeout) // validated by loadExplorerConfigs, empty means 0
+		if explorer.Kind != "" {
+			client.Kind = explorer.Kind
+		}
+		return client, nil
+	}
+	if defaultUrl == "" {
+		return nil, fmt.Errorf("block explorer of chain %v is unknown, please specify --explorer-api-url or explorers in config file", chainId)
+	}
+	return newExplorerClientOf(defaultUrl, apiKey), nil
+}
+
+// containsChainId returns true if chainIds contains chainId.
+func containsChainId(chainIds []uint64, chainId uint64) bool {
+	for _, id := range chainIds {
+		if id == chainId {
+			return true
+		}
+	}
+	return false
+}
+
+// newExplorerClient returns client of --explorer-api-url, or the explorer serving chain of --node in config file, or
+// the default block explorer of --node. The api key is --explorer-api-key, api_key of the explorer in config file or
+// etherscan_api_key of profile.
+func newExplorerClient(ctx context.Context) (*ethutil.ExplorerClient, error) {
+	if globalOptExplorerApiUrl != "" {
+		return newExplorerClientOf(globalOptExplorerApiUrl, ""), nil
+	}
+	explorers, err := loadExplorerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	if len(explorers) == 0 {
+		// the chain id is only needed to route to explorers of config file, node is not connected without them
+		if baseUrl := nodeApiUrlMap[globalOptNode]; baseUrl != "" {
+			return newExplorerClientOf(baseUrl, ""), nil
+		}
+		return nil, fmt.Errorf("block explorer of network %v is unknown, please specify --explorer-api-url", globalOptNode)
+	}
+	chainId := currentChainId(ctx)
+	var defaultUrl string
+	if chainId == nodeChainIdMap[globalOptNode] {
+		defaultUrl = nodeApiUrlMap[globalOptNode]
+	}
+	return explorerOfChain(chainId, defaultUrl, "")
+}
+
+// newExplorerClientOf returns client of explorer api baseUrl, the api key is --explorer-api-key or etherscan_api_key
+// of profile if apiKey is empty. The kind is --explorer-kind, or detected by baseUrl.
+func newExplorerClientOf(baseUrl string, apiKey string) *ethutil.ExplorerClient {
+	checkNetworkAllowed("requesting block explorer " + baseUrl)
+	if apiKey == "" {
+		apiKey = globalOptExplorerApiKey
+	}
+	if apiKey == "" {
+		apiKey = globalEtherscanApiKey
+	}
+	kind := globalOptExplorerKind
+	if kind == "" {
+		kind = ethutil.DetectExplorerKind(baseUrl)
+	}
+	return &ethutil.ExplorerClient{BaseUrl: baseUrl, ApiKey: apiKey, Kind: kind}
+}
+
+// explorerQuery returns the list query of flags.
+func explorerQuery() ethutil.ExplorerQuery {
+	return ethutil.ExplorerQuery{
+		StartBlock: explorerStartBlock,
+		EndBlock:   explorerEndBlock,
+		Page:       explorerPage,
+		Offset:     explorerOffset,
+		Desc:       !explorerAsc,
+	}
+}
+
+// explorerTo returns "to" of explorer tx, or the created contract if tx creates contract.
+func explorerTo(to string, contractAddress string) string {
+	if to == "" && contractAddress != "" {
+		return "create " + common.HexToAddress(contractAddress).Hex()
+	}
+	return common.HexToAddress(to).Hex()
+}
+
+// explorerStatus returns "ok" or "failed"
+func explorerStatus(failed bool) string {
+	if failed {
+		return "failed"
+	}
+	return "ok"
+}
+
+// validateExplorerListArgs validates args of txlist and internal-txs.
+func validateExplorerListArgs() error {
+	if explorerPage < 1 {
+		return fmt.Errorf("--page must be greater than 0")
+	}
+	if explorerOffset < 1 {
+		return fmt.Errorf("--offset must be greater than 0")
+	}
+	return nil
+}
+
+var txListCmd = &cobra.Command{
+	Use:   "txlist address",
+	Short: "List txs sent from or to address by block explorer api",
+	Long: "List txs sent from or to address by Etherscan-compatible block explorer api, which JSON-RPC can't " +
+		"query efficiently. The explorer is --explorer-api-url (default the block explorer of --node).",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("address is required")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return validateExplorerListArgs()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		explorer, err := newExplorerClient(cmd.Context())
+		checkErr(err)
+		address := mustParseAddressArg(cmd.Context(), args[0])
+		txs, err := explorer.TxList(cmd.Context(), address, explorerQuery())
+		checkErr(err)
+
+		for _, tx := range txs {
+			if printJSONL(map[string]any{
+				"block":     tx.BlockNumber,
+				"time":      tx.Time().UTC().Format(time.RFC3339),
+				"hash":      tx.Hash.Hex(),
+				"nonce":     tx.Nonce,
+				"from":      tx.From.Hex(),
+				"to":        explorerTo(tx.To, tx.ContractAddress),
+				"value_wei": tx.Value.String(),
+				"gas_used":  tx.GasUsed,
+				"gas_price": tx.GasPrice.String(),
+				"status":    explorerStatus(tx.Failed()),
+				"method":    tx.FunctionName,
+			}) {
+				continue
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", tx.Hash.Hex())
+				continue
+			}
+			var direction = "in"
+			if tx.From == address {
+				direction = "out"
+			}
+			var method string
+			if name := strings.SplitN(tx.FunctionName, "(", 2)[0]; name != "" {
+				method = " " + name
+			}
+			fmt.Printf("%v %v %v %-3v %v -> %v %v ether%v %v\n", tx.BlockNumber, tx.Time().UTC().Format(time.RFC3339),
+				tx.Hash.Hex(), direction, tx.From.Hex(), explorerTo(tx.To, tx.ContractAddress),
+				wei2Other(tx.Value, unitEther), method, explorerStatus(tx.Failed()))
+		}
+		if len(txs) == 0 {
+			log.Printf("no tx is found")
+		}
+	},
+}
+
+var internalTxsCmd = &cobra.Command{
+	Use:   "internal-txs address|tx-hash",
+	Short: "List internal txs (value transfers and contract creations in call trace) of address or tx by block explorer api",
+	Long: "List internal txs (value transfers and contract creations in call trace) of address or tx by " +
+		"Etherscan-compatible block explorer api, which requires tracing by JSON-RPC. The explorer is " +
+		"--explorer-api-url (default the block explorer of --node).",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("address or tx hash is required")
+		}
+		if !isValidEthAddress(args[0]) && !(len(args[0]) == 2+2*common.HashLength && isValidHexString(args[0])) {
+			return fmt.Errorf("%v is neither a valid eth address nor tx hash", args[0])
+		}
+		return validateExplorerListArgs()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		explorer, err := newExplorerClient(cmd.Context())
+		checkErr(err)
+		var txs []ethutil.ExplorerInternalTx
+		if isValidEthAddress(args[0]) {
+			txs, err = explorer.InternalTxsByAddress(cmd.Context(), common.HexToAddress(args[0]), explorerQuery())
+		} else {
+			txs, err = explorer.InternalTxsByHash(cmd.Context(), common.HexToHash(args[0]))
+		}
+		checkErr(err)
+
+		for _, tx := range txs {
+			var line = map[string]any{
+				"block":     tx.BlockNumber,
+				"time":      time.Unix(tx.TimeStamp, 0).UTC().Format(time.RFC3339),
+				"from":      tx.From.Hex(),
+				"to":        explorerTo(tx.To, tx.ContractAddress),
+				"value_wei": tx.Value.String(),
+				"type":      tx.Type,
+				"status":    explorerStatus(tx.Failed()),
+			}
+			if tx.Hash != (common.Hash{}) {
+				line["hash"] = tx.Hash.Hex()
+			}
+			if printJSONL(line) {
+				continue
+			}
+			var hash string
+			if tx.Hash != (common.Hash{}) {
+				hash = " " + tx.Hash.Hex()
+			}
+			fmt.Printf("%v%v %v %v -> %v %v ether %v\n", tx.BlockNumber, hash, tx.Type, tx.From.Hex(),
+				explorerTo(tx.To, tx.ContractAddress), wei2Other(tx.Value, unitEther), explorerStatus(tx.Failed()))
+		}
+		if len(txs) == 0 {
+			log.Printf("no internal tx is found")
+		}
+	},
+}
+
+var explorerGasOracleCmd = &cobra.Command{
+	Use:   "gas-oracle",
+	Short: "Show gas price suggestions (safe, propose, fast) of block explorer",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		explorer, err := newExplorerClient(cmd.Context())
+		checkErr(err)
+		oracle, err := explorer.GasOracle(cmd.Context())
+		checkErr(err)
+
+		if printJSONL(map[string]any{
+			"last_block":     oracle.LastBlock,
+			"safe_gwei":      oracle.SafeGasPrice.String(),
+			"propose_gwei":   oracle.ProposeGasPrice.String(),
+			"fast_gwei":      oracle.FastGasPrice.String(),
+			"base_fee_gwei":  oracle.SuggestBaseFee.String(),
+			"gas_used_ratio": oracle.GasUsedRatio,
+		}) {
+			return
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v %v %v\n", oracle.SafeGasPrice, oracle.ProposeGasPrice, oracle.FastGasPrice)
+			return
+		}
+		fmt.Printf("last block: %v\n", oracle.LastBlock)
+		fmt.Printf("safe: %v gwei\n", oracle.SafeGasPrice)
+		fmt.Printf("propose: %v gwei\n", oracle.ProposeGasPrice)
+		fmt.Printf("fast: %v gwei\n", oracle.FastGasPrice)
+		if !oracle.SuggestBaseFee.IsZero() {
+			fmt.Printf("base fee: %v gwei\n", oracle.SuggestBaseFee)
+		}
+	},
+}
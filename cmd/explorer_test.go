@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+)
+
+func TestExplorerOfChain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	content := `{
+  "explorers": [
+    {"chain_ids": [1, 10], "api_url": "https://api.etherscan.io/v2/api", "api_key": "V2KEY", "etherscan_v2": true},
+    {"chain_ids": [100], "api_url": "https://gnosis.blockscout.com/api", "timeout": "30s"},
+    {"chain_ids": [7777777], "api_url": "https://explorer.zora.energy/api", "kind": "blockscout"}
+  ]
+}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	oldConfigFile, oldApiKey, oldEtherscanApiKey := globalOptConfigFile, globalOptExplorerApiKey, globalEtherscanApiKey
+	defer func() {
+		globalOptConfigFile, globalOptExplorerApiKey, globalEtherscanApiKey = oldConfigFile, oldApiKey, oldEtherscanApiKey
+		globalExplorerConfigs = nil
+	}()
+	globalOptConfigFile, globalOptExplorerApiKey, globalEtherscanApiKey, globalExplorerConfigs = file, "", "PROFILEKEY", nil
+
+	tests := []struct {
+		name        string
+		chainId     uint64
+		defaultUrl  string
+		apiKey      string
+		wantUrl     string
+		wantKey     string
+		wantChainId uint64
+		wantTimeout time.Duration
+		wantKind    string
+	}{
+		{"etherscan v2", 10, "", "", "https://api.etherscan.io/v2/api", "V2KEY", 10, 0, ethutil.ExplorerKindEtherscan},
+		{"explicit key", 1, "https://api.etherscan.io/api", "KEY", "https://api.etherscan.io/v2/api", "KEY", 1, 0, ethutil.ExplorerKindEtherscan},
+		{"blockscout", 100, "", "", "https://gnosis.blockscout.com/api", "PROFILEKEY", 0, 30 * time.Second, ethutil.ExplorerKindBlockscout},
+		{"blockscout of custom domain", 7777777, "", "", "https://explorer.zora.energy/api", "PROFILEKEY", 0, 0, ethutil.ExplorerKindBlockscout},
+		{"default", 56, "https://api.bscscan.com/api", "", "https://api.bscscan.com/api", "PROFILEKEY", 0, 0, ethutil.ExplorerKindEtherscan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := explorerOfChain(tt.chainId, tt.defaultUrl, tt.apiKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.BaseUrl != tt.wantUrl || client.ApiKey != tt.wantKey || client.ChainId != tt.wantChainId || client.Timeout != tt.wantTimeout || client.Kind != tt.wantKind {
+				t.Errorf("unexpected client %+v", client)
+			}
+		})
+	}
+
+	if _, err := explorerOfChain(8453, "", ""); err == nil {
+		t.Errorf("expected error of unknown explorer")
+	}
+}
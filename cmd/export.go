@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+)
+
+const exportFormatDune = "dune"
+const exportFormatBigQuery = "bigquery"
+
+// column types of exportTable
+const (
+	columnString    = "string"
+	columnAddress   = "address" // common.Address, exported as lowercase 0x-prefixed hex
+	columnBytes     = "bytes"   // []byte or common.Hash, exported as lowercase 0x-prefixed hex
+	columnUint256   = "uint256" // *big.Int, exported as decimal string since it overflows int64 and BigQuery BIGNUMERIC
+	columnInt64     = "int64"
+	columnDouble    = "double"
+	columnBool      = "bool"
+	columnTimestamp = "timestamp" // time.Time
+)
+
+// bigQueryTypes maps column types to BigQuery standard sql types
+var bigQueryTypes = map[string]string{
+	columnString:    "STRING",
+	columnAddress:   "STRING",
+	columnBytes:     "STRING",
+	columnUint256:   "STRING",
+	columnInt64:     "INT64",
+	columnDouble:    "FLOAT64",
+	columnBool:      "BOOL",
+	columnTimestamp: "TIMESTAMP",
+}
+
+type exportColumn struct {
+	Name string // snake_case, as required by Dune and BigQuery
+	Type string
+}
+
+// exportTable is the scan result exported by --export
+type exportTable struct {
+	Columns []exportColumn
+	Rows    [][]any
+}
+
+func newExportTable(columns ...exportColumn) *exportTable {
+	return &exportTable{Columns: columns}
+}
+
+// Append appends a row, values must be in the order of columns.
+func (t *exportTable) Append(values ...any) {
+	if len(values) != len(t.Columns) {
+		panic(fmt.Sprintf("%v values are appended to table with %v columns", len(values), len(t.Columns)))
+	}
+	t.Rows = append(t.Rows, values)
+}
+
+// formatExportValue formats value of column type typ as string, hex is normalized to lowercase with 0x prefix.
+func formatExportValue(typ string, value any) string {
+	if value == nil {
+		return ""
+	}
+	switch typ {
+	case columnAddress:
+		switch v := value.(type) {
+		case common.Address:
+			return strings.ToLower(v.Hex())
+		case string:
+			return strings.ToLower(common.HexToAddress(v).Hex())
+		}
+	case columnBytes:
+		switch v := value.(type) {
+		case []byte:
+			return hexutil.Encode(v)
+		case common.Hash:
+			return hexutil.Encode(v.Bytes())
+		case string:
+			return strings.ToLower(v)
+		}
+	case columnUint256:
+		if v, ok := value.(*big.Int); ok {
+			return v.String()
+		}
+	case columnTimestamp:
+		if v, ok := value.(time.Time); ok {
+			return v.UTC().Format("2006-01-02 15:04:05")
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+// writeDuneCsv writes table as csv accepted by Dune upload, the header is column names.
+func writeDuneCsv(w io.Writer, t *exportTable) error {
+	writer := csv.NewWriter(w)
+	var header []string
+	for _, column := range t.Columns {
+		header = append(header, column.Name)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		var record []string
+		for i, value := range row {
+			record = append(record, formatExportValue(t.Columns[i].Type, value))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeBigQueryJson writes table as newline delimited json accepted by BigQuery load jobs.
+func writeBigQueryJson(w io.Writer, t *exportTable) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range t.Rows {
+		var record = make(map[string]any)
+		for i, value := range row {
+			column := t.Columns[i]
+			if value == nil {
+				record[column.Name] = nil
+				continue
+			}
+			str := formatExportValue(column.Type, value)
+			switch column.Type {
+			case columnInt64:
+				n, err := strconv.ParseInt(str, 10, 64)
+				if err != nil {
+					return fmt.Errorf("column %v: %w", column.Name, err)
+				}
+				record[column.Name] = n
+			case columnDouble:
+				f, err := strconv.ParseFloat(str, 64)
+				if err != nil {
+					return fmt.Errorf("column %v: %w", column.Name, err)
+				}
+				record[column.Name] = f
+			case columnBool:
+				record[column.Name] = str == "true"
+			case columnTimestamp:
+				record[column.Name] = str + " UTC"
+			default:
+				record[column.Name] = str
+			}
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bigQuerySchema returns the BigQuery json schema of table.
+func bigQuerySchema(t *exportTable) ([]byte, error) {
+	type field struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+		Mode string `json:"mode"`
+	}
+	var fields []field
+	for _, column := range t.Columns {
+		fields = append(fields, field{Name: column.Name, Type: bigQueryTypes[column.Type], Mode: "NULLABLE"})
+	}
+	return json.MarshalIndent(fields, "", "  ")
+}
+
+// appendFiatColumn appends column <name>_<fiat> (e.g. balance_usd for balance_wei) to table if --show-fiat is specified.
+// It's the fiat value of wei column at the time returned by at (e.g. block time of tx, as required by tax and
+// accounting), or at current price if at is nil. The value is null if price is unavailable.
+func appendFiatColumn(ctx context.Context, t *exportTable, weiColumn string, at func(row []any) time.Time) {
+	if globalOptShowFiat == "" {
+		return
+	}
+	var index = -1
+	for i, column := range t.Columns {
+		if column.Name == weiColumn {
+			index = i
+		}
+	}
+	if index < 0 {
+		panic(fmt.Sprintf("column %v is not found", weiColumn))
+	}
+
+	name := strings.TrimSuffix(weiColumn, "_wei") + "_" + strings.ToLower(globalOptShowFiat)
+	t.Columns = append(t.Columns, exportColumn{name, columnDouble})
+	for i, row := range t.Rows {
+		var value any // null if price is unavailable
+		if amount, ok := row[index].(*big.Int); ok {
+			var fiat decimal.Decimal
+			if at == nil {
+				fiat, ok = fiatValue(ctx, amount)
+			} else {
+				fiat, ok = fiatValueAt(ctx, amount, at(row))
+			}
+			if ok {
+				value = fiat
+			}
+		}
+		t.Rows[i] = append(row, value)
+	}
+}
+
+// exportResults writes table in --export format to --export-file (default stdout), it returns false if --export
+// is not specified and nothing is written. For BigQuery, the schema is written to <export-file>.schema.json.
+func exportResults(t *exportTable) bool {
+	if globalOptExport == "" {
+		return false
+	}
+
+	var w io.Writer = os.Stdout
+	if globalOptExportFile != "" {
+		f, err := os.Create(globalOptExportFile)
+		checkErr(err)
+		defer f.Close()
+		w = f
+	}
+
+	switch globalOptExport {
+	case exportFormatDune:
+		checkErr(writeDuneCsv(w, t))
+	case exportFormatBigQuery:
+		checkErr(writeBigQueryJson(w, t))
+		schema, err := bigQuerySchema(t)
+		checkErr(err)
+		if globalOptExportFile != "" {
+			checkErr(os.WriteFile(globalOptExportFile+".schema.json", schema, 0644))
+		} else {
+			log.Printf("bigquery schema:\n%s", schema)
+		}
+	}
+	if globalOptExportFile != "" {
+		log.Printf("%v rows exported to %v", len(t.Rows), globalOptExportFile)
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+func TestExportTable(t *testing.T) {
+	table := newExportTable(exportColumn{"address", columnAddress}, exportColumn{"balance_wei", columnUint256},
+		exportColumn{"tx_hash", columnBytes}, exportColumn{"nonce", columnInt64}, exportColumn{"block_time", columnTimestamp})
+	balance, _ := new(big.Int).SetString("115792089237316195423570985008687907853269984665640564039457584007913129639935", 10)
+	table.Append("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", balance,
+		common.HexToHash("0xABCDEF0000000000000000000000000000000000000000000000000000000001"), uint64(7),
+		time.Date(2023, 5, 1, 8, 30, 0, 0, time.UTC))
+
+	tests := []struct {
+		write func(*bytes.Buffer) error
+		want  string
+	}{
+		{
+			write: func(b *bytes.Buffer) error { return writeDuneCsv(b, table) },
+			want: "address,balance_wei,tx_hash,nonce,block_time\n" +
+				"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb,115792089237316195423570985008687907853269984665640564039457584007913129639935," +
+				"0xabcdef0000000000000000000000000000000000000000000000000000000001,7,2023-05-01 08:30:00\n",
+		},
+		{
+			write: func(b *bytes.Buffer) error { return writeBigQueryJson(b, table) },
+			want: `{"address":"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb",` +
+				`"balance_wei":"115792089237316195423570985008687907853269984665640564039457584007913129639935",` +
+				`"block_time":"2023-05-01 08:30:00 UTC","nonce":7,` +
+				`"tx_hash":"0xabcdef0000000000000000000000000000000000000000000000000000000001"}` + "\n",
+		},
+	}
+
+	for i, tt := range tests {
+		var b bytes.Buffer
+		if err := tt.write(&b); err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if b.String() != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, b.String())
+		}
+	}
+}
+
+type fixedPriceOracle struct{}
+
+func (o *fixedPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	return decimal.NewFromInt(2000), nil
+}
+
+func (o *fixedPriceOracle) PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error) {
+	return decimal.NewFromInt(int64(1000 * t.Day())), nil
+}
+
+func TestAppendFiatColumn(t *testing.T) {
+	globalOptShowFiat, globalOptNode, globalPriceOracle = "USD", nodeMainnet, &ethutil.CachedPriceOracle{Oracle: &fixedPriceOracle{}, TTL: time.Hour}
+	defer func() { globalOptShowFiat, globalOptNode, globalPriceOracle = "", nodeGoerli, nil }()
+
+	oneEther, _ := new(big.Int).SetString("1500000000000000000", 10)
+	tests := []struct {
+		at   func(row []any) time.Time
+		want string
+	}{
+		{nil, "value_wei,block_time,value_usd\n1500000000000000000,2023-05-01 08:30:00,3000\n,2023-05-02 08:30:00,\n"},
+		{func(row []any) time.Time { return row[1].(time.Time) }, "value_wei,block_time,value_usd\n1500000000000000000,2023-05-01 08:30:00,1500\n,2023-05-02 08:30:00,\n"},
+	}
+
+	for i, tt := range tests {
+		table := newExportTable(exportColumn{"value_wei", columnUint256}, exportColumn{"block_time", columnTimestamp})
+		table.Append(oneEther, time.Date(2023, 5, 1, 8, 30, 0, 0, time.UTC))
+		table.Append(nil, time.Date(2023, 5, 2, 8, 30, 0, 0, time.UTC))
+		appendFiatColumn(context.Background(), table, "value_wei", tt.at)
+
+		var b bytes.Buffer
+		if err := writeDuneCsv(&b, table); err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if b.String() != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, b.String())
+		}
+	}
+}
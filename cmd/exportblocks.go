@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+)
+
+const exportBlocksFormatRlp = "rlp"
+const exportBlocksFormatEra1 = "era1"
+
+var exportBlocksFormat string
+var exportBlocksOutput string
+var exportBlocksNetwork string
+
+func init() {
+	exportBlocksCmd.Flags().StringVarP(&exportBlocksFormat, "format", "", exportBlocksFormatRlp, "rlp | era1. rlp is the concatenated rlp of blocks (the format of geth export/import), era1 archives pre-merge blocks with receipts and total difficulty")
+	exportBlocksCmd.Flags().StringVarP(&exportBlocksOutput, "output", "o", "", "the output file of rlp format (default blocks-<from>-<to>.rlp), or the output directory of era1 files (default current directory)")
+	exportBlocksCmd.Flags().StringVarP(&exportBlocksNetwork, "era1-network", "", "", "the network name in era1 file names, default is --node")
+}
+
+var exportBlocksCmd = &cobra.Command{
+	Use:   "export-blocks from-block to-block",
+	Short: "Export full blocks in range (inclusive) to rlp or era1 files for offline analysis and archival",
+	Long: "Export full blocks in range [from-block, to-block] fetched from node. The rlp format can be imported by\n" +
+		"`geth import`. The era1 format (https://github.com/eth-clients/e2store-format-specs) also includes receipts and\n" +
+		"total difficulty, blocks are split into files of 8192 blocks aligned to multiples of 8192, and only pre-merge\n" +
+		"blocks are allowed.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("requires from-block and to-block")
+		}
+		from, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%v is not a valid block number", args[0])
+		}
+		to, err := strconv.ParseUint(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("%v is not a valid block number", args[1])
+		}
+		if from > to {
+			return fmt.Errorf("from-block %v is greater than to-block %v", from, to)
+		}
+		if !contains([]string{exportBlocksFormatRlp, exportBlocksFormatEra1}, exportBlocksFormat) {
+			return fmt.Errorf("invalid --format %v", exportBlocksFormat)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		from, _ := strconv.ParseUint(args[0], 10, 64)
+		to, _ := strconv.ParseUint(args[1], 10, 64)
+		if exportBlocksFormat == exportBlocksFormatEra1 {
+			exportEra1(ctx, from, to)
+			return
+		}
+
+		output := exportBlocksOutput
+		if output == "" {
+			output = fmt.Sprintf("blocks-%v-%v.rlp", from, to)
+		}
+		f, err := os.Create(output)
+		checkErr(err)
+		defer f.Close()
+		w := bufio.NewWriter(f)
+		for number := from; number <= to; number++ {
+			block, err := globalClient.EthClient.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+			checkErr(err)
+			checkErr(rlp.Encode(w, block))
+			logExportProgress(number, from, to)
+		}
+		checkErr(w.Flush())
+		if printJSONL(map[string]any{"file": output, "from_block": from, "to_block": to}) {
+			return
+		}
+		fmt.Printf("%v\n", output)
+	},
+}
+
+// logExportProgress logs progress every 1000 blocks.
+func logExportProgress(number, from, to uint64) {
+	if exported := number - from + 1; exported%1000 == 0 && number != to {
+		log.Printf("exported %v of %v blocks", exported, to-from+1)
+	}
+}
+
+// exportEra1 writes blocks in [from, to] to era1 files, one file for blocks of each era (8192 blocks).
+func exportEra1(ctx context.Context, from, to uint64) {
+	dir := exportBlocksOutput
+	if dir == "" {
+		dir = "."
+	}
+	checkErr(os.MkdirAll(dir, 0755))
+	network := exportBlocksNetwork
+	if network == "" {
+		network = globalOptNode
+	}
+
+	for start := from; start <= to; {
+		end := (start/ethutil.Era1MaxBlocks+1)*ethutil.Era1MaxBlocks - 1
+		if end > to {
+			end = to
+		}
+		tmp, err := os.CreateTemp(dir, "*.era1.tmp")
+		checkErr(err)
+		w := bufio.NewWriter(tmp)
+		writer, err := ethutil.NewEra1Writer(w, start)
+		checkErr(err)
+		for number := start; number <= end; number++ {
+			blockNumber := new(big.Int).SetUint64(number)
+			block, err := globalClient.EthClient.BlockByNumber(ctx, blockNumber)
+			checkErr(err)
+			receipts, err := ethutil.BlockReceipts(ctx, globalClient, block)
+			checkErr(err)
+			td, err := ethutil.BlockTotalDifficulty(ctx, globalClient, blockNumber)
+			checkErr(err)
+			if err := writer.Add(block, receipts, td); err != nil {
+				_ = os.Remove(tmp.Name())
+				log.Fatalf("%v", err)
+			}
+			logExportProgress(number, from, to)
+		}
+		root, err := writer.Finalize()
+		checkErr(err)
+		checkErr(w.Flush())
+		checkErr(tmp.Close())
+
+		output := filepath.Join(dir, ethutil.Era1FileName(network, start, root))
+		checkErr(os.Rename(tmp.Name(), output))
+		if !printJSONL(map[string]any{"file": output, "from_block": start, "to_block": end, "accumulator": root.Hex()}) {
+			fmt.Printf("%v\n", output)
+		}
+		start = end + 1
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+const (
+	walletFormatMetaMask      = "metamask"
+	walletFormatWalletConnect = "walletconnect"
+	walletFormatEIP681        = "eip681"
+)
+
+var exportTxFormat string
+var exportTxFrom string
+var exportTxChainId int64
+
+func init() {
+	exportTxCmd.Flags().StringVarP(&exportTxFormat, "format", "", walletFormatMetaMask, "metamask | walletconnect | eip681, metamask is the params of eth_sendTransaction, walletconnect is the params of v2 session request, eip681 is payment link (only for eth and ERC20 transfer)")
+	exportTxCmd.Flags().StringVarP(&exportTxFrom, "from", "", "", "the sender address, i.e. the account of wallet signing tx")
+	exportTxCmd.Flags().Int64VarP(&exportTxChainId, "chain-id", "", 0, "the chain id, required for eip155 tx as its chain id is not encoded in unsigned tx")
+}
+
+var exportTxCmd = &cobra.Command{
+	Use:   "export-tx unsigned-tx",
+	Short: "Export unsigned tx (created by build-tx) as a signing request of other wallets, e.g. MetaMask, WalletConnect, EIP-681 link",
+	Long: "Export unsigned tx (created by build-tx) as a signing request of other wallets, so end users sign tx " +
+		"prepared by ethutil in their own wallet. metamask format is the params of eth_sendTransaction (also accepted " +
+		"by Ledger Live and most injected wallets), walletconnect format is the params of WalletConnect v2 session " +
+		"request, eip681 format is a payment link which can be shown as QR code.",
+	Args: inputArgs(func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one unsigned-tx")
+		}
+		if !isValidHexString(args[0]) {
+			return fmt.Errorf("unsigned-tx must hex string")
+		}
+		return nil
+	}),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !contains([]string{walletFormatMetaMask, walletFormatWalletConnect, walletFormatEIP681}, exportTxFormat) {
+			return fmt.Errorf("invalid --format %v", exportTxFormat)
+		}
+		if exportTxFrom != "" && !isValidAddressArg(cmd.Context(), exportTxFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", exportTxFrom)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var from *common.Address
+		if exportTxFrom != "" {
+			address := mustParseAddressArg(cmd.Context(), exportTxFrom)
+			from = &address
+		}
+		var chainID *big.Int
+		if exportTxChainId > 0 {
+			chainID = big.NewInt(exportTxChainId)
+		}
+
+		forEachInput(args, []string{jsonlKeyRawTx}, func(rawTx string) {
+			tx, err := ethutil.ParseRawTx(rawTx)
+			checkErr(err)
+
+			if exportTxFormat == walletFormatEIP681 {
+				link, err := ethutil.EIP681Link(tx, chainID)
+				checkErr(err)
+				fmt.Printf("%v\n", link)
+				return
+			}
+
+			params, err := ethutil.NewWalletTxParams(tx, from, chainID)
+			checkErr(err)
+			var request any = params
+			if exportTxFormat == walletFormatWalletConnect {
+				request = ethutil.NewWalletConnectRequest(params)
+			}
+			var content []byte
+			if globalOptJsonl {
+				content, err = json.Marshal(request)
+			} else {
+				content, err = json.MarshalIndent(request, "", "  ")
+			}
+			checkErr(err)
+			fmt.Printf("%s\n", content)
+		})
+	},
+}
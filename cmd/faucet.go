@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var faucetListen string
+var faucetAmount string
+var faucetUnit string
+var faucetToken string
+var faucetInterval time.Duration
+var faucetMaxPerMinute int
+var faucetGateUrl string
+var faucetApiToken string
+var faucetTrustProxy bool
+
+func init() {
+	faucetCmd.Flags().StringVarP(&faucetListen, "listen", "", "127.0.0.1:8080", "the address of http server")
+	faucetCmd.Flags().StringVarP(&faucetAmount, "amount", "", "0.1", "the amount of each drip, unit is ether and can be changed by --unit, or unit of token if --token is specified")
+	faucetCmd.Flags().StringVarP(&faucetUnit, "unit", "u", "ether", "wei | gwei | ether, unit of --amount, ignored if --token is specified")
+	faucetCmd.Flags().StringVarP(&faucetToken, "token", "", "", "drip this ERC20 token instead of eth")
+	faucetCmd.Flags().DurationVarP(&faucetInterval, "interval", "", 24*time.Hour, "each address and each ip can be served once per interval")
+	faucetCmd.Flags().IntVarP(&faucetMaxPerMinute, "max-per-minute", "", 30, "max drips per minute of all requesters, 0 means no limit")
+	faucetCmd.Flags().StringVarP(&faucetGateUrl, "gate-url", "", "", "the gate hook, request {address, ip, captcha} is posted to it before each drip, and is allowed if it responds 2xx")
+	faucetCmd.Flags().StringVarP(&faucetApiToken, "api-token", "", "", "if specified, requests must carry header 'Authorization: Bearer <api-token>'")
+	faucetCmd.Flags().BoolVarP(&faucetTrustProxy, "trust-proxy", "", false, "use the first ip in X-Forwarded-For header as ip of requester, only enable it behind a reverse proxy")
+
+	addFlags(faucetCmd, txFlags)
+}
+
+// faucet serves drips of eth or token, txs are sent one by one with nonces assigned locally.
+type faucet struct {
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	token      *common.Address // nil means eth
+	amount     *big.Int        // in wei or smallest unit of token
+	display    string          // e.g. "0.1 ether"
+	nonces     *ethutil.NonceManager
+	limiter    *ethutil.FaucetLimiter
+
+	mu sync.Mutex // serializes sending, so txs are broadcast in nonce order
+}
+
+// send sends a drip to address, and returns the tx hash.
+func (f *faucet) send(ctx context.Context, to common.Address) (common.Hash, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var txTo, value, data = &to, f.amount, []byte(nil)
+	if f.token != nil {
+		var err error
+		data, err = ethutil.BuildTxInputData(erc20FuncSignature["transfer"], []string{to.Hex(), f.amount.String()})
+		if err != nil {
+			return common.Hash{}, err
+		}
+		txTo, value = f.token, big.NewInt(0)
+	}
+
+	var gasPrice *big.Int
+	if globalOptTxType != txTypeEip1559 {
+		var err error
+		if gasPrice, err = getGasPrice(ctx, globalClient.EthClient); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	nonce, err := f.nonces.Next(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	opts := buildTxOptions(gasPrice)
+	opts.Nonce = &nonce
+	opts.GasOracle = newGasOracle(globalClient.EthClient)
+	tx, err := ethutil.BuildTx(ctx, globalClient.EthClient, f.address, txTo, value, data, opts)
+	if err == nil {
+		tx, err = ethutil.SignTx(ctx, globalClient.EthClient, tx, f.privateKey, nil)
+	}
+	if err == nil {
+		_, err = broadcastTx(ctx, globalClient, tx)
+	}
+	if err != nil {
+		// the nonce is not used, query pending nonce again for next drip
+		f.nonces.Reset()
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// requesterIp returns ip of requester, the first ip in X-Forwarded-For is used if --trust-proxy is specified.
+func requesterIp(r *http.Request) string {
+	if faucetTrustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// faucetReply writes v as json response with status code.
+func faucetReply(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (f *faucet) handleDrip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		faucetReply(w, http.StatusMethodNotAllowed, map[string]string{"error": "POST is required"})
+		return
+	}
+	if faucetApiToken != "" && r.Header.Get("Authorization") != "Bearer "+faucetApiToken {
+		faucetReply(w, http.StatusUnauthorized, map[string]string{"error": "invalid api token"})
+		return
+	}
+	var req struct {
+		Address string `json:"address"`
+		Captcha string `json:"captcha"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil || !isValidEthAddress(req.Address) {
+		faucetReply(w, http.StatusBadRequest, map[string]string{"error": "body {\"address\": \"0x...\"} is required"})
+		return
+	}
+	address, ip := common.HexToAddress(req.Address), requesterIp(r)
+
+	if faucetGateUrl != "" {
+		err := ethutil.CheckFaucetGate(r.Context(), faucetGateUrl, ethutil.FaucetGateRequest{Address: address, Ip: ip, Captcha: req.Captcha})
+		if err != nil {
+			log.Printf("drip to %v (ip %v) is rejected: %v", address.Hex(), ip, err)
+			faucetReply(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+			return
+		}
+	}
+	if wait := f.limiter.Allow("address:"+address.Hex(), "ip:"+ip); wait > 0 {
+		w.Header().Set("Retry-After", fmt.Sprintf("%.0f", math.Ceil(wait.Seconds())))
+		faucetReply(w, http.StatusTooManyRequests, map[string]string{"error": fmt.Sprintf("rate limited, retry after %v", wait.Round(time.Second))})
+		return
+	}
+
+	txHash, err := f.send(r.Context(), address)
+	if err != nil {
+		log.Printf("drip to %v (ip %v) fail: %v", address.Hex(), ip, err)
+		faucetReply(w, http.StatusInternalServerError, map[string]string{"error": "send tx fail"})
+		return
+	}
+	log.Printf("drip %v to %v (ip %v), tx %v", f.display, address.Hex(), ip, txHash.Hex())
+	faucetReply(w, http.StatusOK, map[string]string{"tx": txHash.Hex(), "amount": f.display})
+}
+
+func (f *faucet) handleStatus(w http.ResponseWriter, r *http.Request) {
+	var status = map[string]any{jsonlKeyAddress: f.address.Hex(), "amount": f.display}
+	var balance *big.Int
+	var err error
+	if f.token != nil {
+		status["token"] = f.token.Hex()
+		var values []any
+		values, err = ethutil.CallAndUnpack(r.Context(), globalClient.EthClient, *f.token, erc20FuncSignature["balanceOf"], []string{f.address.Hex()})
+		if err == nil {
+			balance = values[0].(*big.Int)
+		}
+	} else {
+		balance, err = globalClient.EthClient.BalanceAt(r.Context(), f.address, nil)
+	}
+	if err != nil {
+		faucetReply(w, http.StatusInternalServerError, map[string]string{"error": "query balance fail"})
+		return
+	}
+	status["balance"] = balance.String()
+	if f.amount.Sign() > 0 {
+		status["drips_left"] = new(big.Int).Div(balance, f.amount).String()
+	}
+	faucetReply(w, http.StatusOK, status)
+}
+
+var faucetCmd = &cobra.Command{
+	Use:   "faucet",
+	Short: "Run a faucet http server which drips small amount of eth or token to requesters, for private testnets",
+	Long: "Run a faucet http server which drips --amount of eth (or --token) from account of --private-key.\n\n" +
+		"POST /drip with body {\"address\": \"0x...\", \"captcha\": \"...\"} sends a drip and returns {\"tx\": \"0x...\"}, " +
+		"GET /status returns the faucet address and balance. Each address and each ip can be served once per " +
+		"--interval, and all requesters are served at most --max-per-minute times per minute, 429 with Retry-After " +
+		"is returned if rate limited. Requests can be gated by --api-token, and by --gate-url which verifies captcha " +
+		"or any other condition. Drips are sent one by one with locally assigned nonces, so they don't wait for " +
+		"previous drips to be mined. --policy and --approvers are not supported, TOTP code (if enrolled) is asked " +
+		"once before serving.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("no args are required")
+		}
+		if _, err := decimal.NewFromString(faucetAmount); err != nil {
+			return fmt.Errorf("invalid --amount: %w", err)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, faucetUnit) {
+			return fmt.Errorf("invalid --unit %v", faucetUnit)
+		}
+		if faucetToken != "" && !isValidAddressArg(cmd.Context(), faucetToken) {
+			return fmt.Errorf("%v is not a valid eth address", faucetToken)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for %v command", cmd.Name())
+		}
+		// drips are sent unattended, they can't be confirmed or approved one by one
+		if globalOptPolicy != "" || len(globalOptApprovers) > 0 {
+			log.Fatalf("--policy and --approvers are not supported by %v command", cmd.Name())
+		}
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		checkWeakPrivateKey(privateKey)
+		// TOTP code is required once before serving, as drips are signed without operator
+		checkTOTP()
+		f := &faucet{
+			privateKey: privateKey,
+			address:    extractAddressFromPrivateKey(privateKey),
+			limiter:    &ethutil.FaucetLimiter{Interval: faucetInterval, MaxPerMinute: faucetMaxPerMinute},
+		}
+		f.nonces = &ethutil.NonceManager{Client: globalClient.EthClient, Account: f.address}
+		amount := decimal.RequireFromString(faucetAmount)
+		if faucetToken != "" {
+			token := mustParseAddressArg(ctx, faucetToken)
+			values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, token, erc20FuncSignature["decimals"], nil)
+			checkErr(err)
+			symbol := token.Hex()
+			if values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, token, erc20FuncSignature["symbol"], nil); err == nil {
+				symbol = values[0].(string)
+			}
+			f.token = &token
+			f.amount = amount.Shift(int32(values[0].(uint8))).BigInt()
+			f.display = amount.String() + " " + symbol
+		} else {
+			f.amount = unify2Wei(amount, faucetUnit).BigInt()
+			f.display = amount.String() + " " + faucetUnit
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/drip", f.handleDrip)
+		mux.HandleFunc("/status", f.handleStatus)
+		server := &http.Server{Addr: faucetListen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		log.Printf("faucet %v drips %v, listening on %v", f.address.Hex(), f.display, faucetListen)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("faucet server fail: %v", err)
+		}
+	},
+}
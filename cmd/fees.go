@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var feesBlocks uint64
+var feesPercentiles []float64
+
+func init() {
+	feesCmd.Flags().Uint64VarP(&feesBlocks, "blocks", "", 20, "the number of recent blocks in the window")
+	feesCmd.Flags().Float64SliceVarP(&feesPercentiles, "percentiles", "", []float64{10, 50, 90}, "the percentiles of priority fee of txs in each block, comma separated and increasing")
+
+	addFlags(feesCmd, txFlags, "priority-fee-floor")
+	addFlags(feesCmd, blockFlags)
+}
+
+var feesCmd = &cobra.Command{
+	Use:   "fees",
+	Short: "Show base fee, priority fee percentiles and gas used ratio of recent blocks",
+	Long: "Show base fee, priority fee percentiles and gas used ratio of recent blocks by eth_feeHistory, the window " +
+		"ends at --block (default latest). The summary and the max priority fee estimations (slow, average and fast) " +
+		"used by the tx sending commands are shown after the blocks. Each block is printed as a JSON line if --jsonl " +
+		"is specified.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("no args are required")
+		}
+		if feesBlocks == 0 {
+			return fmt.Errorf("--blocks must be greater than 0")
+		}
+		for i, p := range feesPercentiles {
+			if p < 0 || p > 100 {
+				return fmt.Errorf("percentile %v is not in [0, 100]", p)
+			}
+			if i > 0 && p < feesPercentiles[i-1] {
+				return fmt.Errorf("--percentiles must be increasing")
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		history, err := ethutil.GetFeeHistory(ctx, globalClient.EthClient, feesBlocks, stateBlock(ctx), feesPercentiles)
+		checkErr(err)
+		if len(history.Blocks) == 0 {
+			log.Fatalf("no fee history is returned, %v", ethutil.ErrEip1559NotSupported)
+		}
+
+		if globalOptJsonl {
+			for _, block := range history.Blocks {
+				var rewards = make(map[string]string)
+				for i, reward := range block.Rewards {
+					rewards[fmt.Sprintf("p%v", feesPercentiles[i])] = reward.String()
+				}
+				printJSONL(map[string]any{
+					"block":          block.Number,
+					"base_fee":       block.BaseFee.String(),
+					"gas_used_ratio": block.GasUsedRatio,
+					"priority_fees":  rewards,
+				})
+			}
+			return
+		}
+
+		var header = fmt.Sprintf("%-10v %14v %-22v", "block", "base fee", "gas used")
+		for _, p := range feesPercentiles {
+			header += fmt.Sprintf(" %10v", fmt.Sprintf("p%v tip", p))
+		}
+		fmt.Printf("%v\n", header)
+		for _, block := range history.Blocks {
+			// 100% gas used ratio is shown as a bar of 10 chars, the target gas (50%) is half of it
+			bar := strings.Repeat("#", int(block.GasUsedRatio*10+0.5))
+			line := fmt.Sprintf("%-10v %14v %-22v", block.Number, feesGwei(block.BaseFee),
+				fmt.Sprintf("%5.1f%% %v", block.GasUsedRatio*100, bar))
+			for i := range feesPercentiles {
+				if i < len(block.Rewards) {
+					line += fmt.Sprintf(" %10v", feesGwei(block.Rewards[i]))
+				} else {
+					line += fmt.Sprintf(" %10v", "-")
+				}
+			}
+			fmt.Printf("%v\n", line)
+		}
+		fmt.Printf("(fees are in gwei)\n\n")
+
+		if stats := history.BaseFeeStats(); stats != nil {
+			fmt.Printf("base fee: min %v, avg %v, max %v, next %v gwei\n",
+				feesGwei(stats.Min), feesGwei(stats.Avg), feesGwei(stats.Max), feesGwei(history.NextBaseFee))
+		}
+		for i, p := range feesPercentiles {
+			if stats := history.RewardStats(i); stats != nil {
+				fmt.Printf("p%v tip: min %v, avg %v, max %v gwei\n", p, feesGwei(stats.Min), feesGwei(stats.Avg), feesGwei(stats.Max))
+			}
+		}
+		fmt.Printf("gas used ratio: avg %.1f%%\n", history.AvgGasUsedRatio()*100)
+
+		estimate, err := newGasOracle(globalClient.EthClient).EstimateFees(ctx)
+		if err != nil {
+			log.Printf("WARNING: estimate fees fail: %v", err)
+			return
+		}
+		fmt.Printf("tip estimations (%v): slow %v, average %v, fast %v gwei\n", estimate.Source,
+			feesGwei(estimate.Slow), feesGwei(estimate.Average), feesGwei(estimate.Fast))
+	},
+}
+
+// feesGwei returns wei in gwei with at most 3 decimal places.
+func feesGwei(wei *big.Int) string {
+	return wei2Other(bigInt2Decimal(wei), unitGwei).Round(3).String()
+}
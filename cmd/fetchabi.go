@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var fetchAbiRefresh bool
+
+func init() {
+	fetchAbiCmd.Flags().BoolVarP(&fetchAbiRefresh, "refresh", "", false, "ignore the cached abi and fetch it again")
+
+	addFlags(fetchAbiCmd, explorerFlags)
+}
+
+var fetchAbiCmd = &cobra.Command{
+	Use:   "fetch-abi contract-address",
+	Short: "Fetch abi of verified contract from block explorer platform (eg. etherscan) or Sourcify, the abi is cached locally",
+	Long: "Fetch abi of verified contract from block explorer platform (eg. etherscan) or Sourcify. " +
+		"The abi is cached in ~/.ethutil/abi/, call, query and access-list load it by contract address " +
+		"if only function name is given.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one contract-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		address := mustParseAddressArg(cmd.Context(), args[0])
+
+		var abi string
+		var err error
+		if fetchAbiRefresh {
+			var source *ethutil.ContractSource
+			source, err = fetchContractSource(cmd.Context(), address)
+			if err == nil {
+				abi = source.Abi
+				saveCachedAbi(address, abi)
+			}
+		} else {
+			abi, err = loadAbiByAddress(cmd.Context(), address)
+		}
+		checkErr(err)
+
+		fmt.Printf("%v\n", abi)
+	},
+}
+
+// abiCacheFile returns ~/.ethutil/abi/<node>/<address>.json
+func abiCacheFile(address common.Address) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ethutil", "abi", globalOptNode, strings.ToLower(address.Hex())+".json")
+}
+
+// writeCachedAbi writes abi of address to cache.
+func writeCachedAbi(address common.Address, abi string) error {
+	file := abiCacheFile(address)
+	if file == "" {
+		return fmt.Errorf("home directory is unavailable")
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("create abi cache directory fail: %w", err)
+	}
+	return os.WriteFile(file, []byte(abi), 0600)
+}
+
+// saveCachedAbi saves abi of address to cache, cache is best effort and error is only logged.
+func saveCachedAbi(address common.Address, abi string) {
+	if abi == "" {
+		return
+	}
+	if err := writeCachedAbi(address, abi); err != nil {
+		log.Printf("save abi cache fail: %v", err)
+	}
+}
+
+// fetchContractSource fetches verified source of address from block explorer of --node, and falls back to Sourcify
+// if contract is not verified in block explorer or block explorer is unavailable.
+func fetchContractSource(ctx context.Context, address common.Address) (*ethutil.ContractSource, error) {
+	var source *ethutil.ContractSource
+	var err = fmt.Errorf("block explorer of network %v is unknown", globalOptNode)
+	if explorer, explorerErr := newExplorerClient(ctx); explorerErr == nil {
+		source, err = explorer.ContractSource(ctx, address)
+	}
+	if err == nil {
+		return source, nil
+	}
+	if !errors.Is(err, ethutil.ErrContractNotVerified) {
+		log.Printf("fetch source from block explorer fail: %v", err)
+	}
+
+	log.Printf("try to fetch source from sourcify")
+	sourcifySource, sourcifyErr := ethutil.FetchSourcifySource(ctx, nodeChainIdMap[globalOptNode], address)
+	if sourcifyErr != nil {
+		if errors.Is(err, ethutil.ErrContractNotVerified) && errors.Is(sourcifyErr, ethutil.ErrContractNotVerified) {
+			return nil, ethutil.ErrContractNotVerified
+		}
+		return nil, fmt.Errorf("fetch source fail, block explorer: %v, sourcify: %w", err, sourcifyErr)
+	}
+	return sourcifySource, nil
+}
+
+// loadAbiByAddress returns abi of address from cache, and fetches it if it's not cached.
+func loadAbiByAddress(ctx context.Context, address common.Address) (string, error) {
+	if file := abiCacheFile(address); file != "" {
+		if content, err := os.ReadFile(file); err == nil {
+			return string(content), nil
+		}
+	}
+
+	source, err := fetchContractSource(ctx, address)
+	if err != nil {
+		return "", fmt.Errorf("fetch abi of %v fail: %w", address.Hex(), err)
+	}
+	saveCachedAbi(address, source.Abi)
+	return source.Abi, nil
+}
+
+// resolveFuncSignature returns the function definition of funcSignature in contract. If abiFile is specified,
+// the definition is extracted from it. Otherwise, if funcSignature is only a function name (without parentheses),
+// the abi of contract is loaded by address, and funcSignature is kept as a function without args if abi is
+// unavailable.
+func resolveFuncSignature(ctx context.Context, contract common.Address, funcSignature string, abiFile string) (string, error) {
+	funcName := ethutil.ExtractFuncName(funcSignature)
+	if abiFile != "" {
+		abiContent, err := os.ReadFile(abiFile)
+		if err != nil {
+			return "", err
+		}
+		return ethutil.ExtractFuncDefinition(string(abiContent), funcName)
+	}
+	if strings.Contains(funcSignature, "(") {
+		return funcSignature, nil
+	}
+
+	abiContent, err := loadAbiByAddress(ctx, contract)
+	if err != nil {
+		log.Printf("load abi fail: %v, treat %v as function without args", err, funcSignature)
+		return funcSignature, nil
+	}
+	return ethutil.ExtractFuncDefinition(abiContent, funcName)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var finalityL2Rpc string
+var finalityRollup string
+var finalityContract string
+var finalityRollupId uint32
+var finalityBatch int64
+
+func init() {
+	finalityCmd.Flags().StringVarP(&finalityL2Rpc, "l2-rpc", "", "", "the node url of zk-rollup (L2)")
+	finalityCmd.Flags().StringVarP(&finalityRollup, "rollup", "", "", "linea | scroll | zkevm, default is detected by chain id of --l2-rpc")
+	finalityCmd.Flags().StringVarP(&finalityContract, "contract", "", "", "the L1 contract of rollup (LineaRollup, ScrollChain or PolygonRollupManager), default is the contract of known chain")
+	finalityCmd.Flags().Uint32VarP(&finalityRollupId, "rollup-id", "", 1, "the rollup id in PolygonRollupManager, only for zkevm")
+	finalityCmd.Flags().Int64VarP(&finalityBatch, "batch", "", -1, "the batch containing the tx, required by scroll (shown in scrollscan), default is queried from --l2-rpc for zkevm")
+}
+
+// stageStatus returns yes, no or unknown.
+func stageStatus(reached bool, unknown bool) string {
+	if unknown {
+		return "unknown"
+	}
+	if reached {
+		return "yes"
+	}
+	return "no"
+}
+
+var finalityCmd = &cobra.Command{
+	Use:   "finality --l2-rpc url l2-tx-hash",
+	Short: "Show whether the batch of zk-rollup tx is committed, proven and finalized on L1",
+	Long: "Show whether the batch containing a tx of zk-rollup (Linea, Scroll or Polygon zkEVM) is committed, proven and\n" +
+		"finalized on L1 (current --node), by querying the rollup contract on L1. Linea only exposes the last finalized\n" +
+		"L2 block on L1, so committed is unknown before finalization.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires l2-tx-hash")
+		}
+		if !isValidHexString(args[0]) || len(args[0]) != 66 {
+			return fmt.Errorf("%v is not a valid tx hash", args[0])
+		}
+		if finalityL2Rpc == "" {
+			return fmt.Errorf("--l2-rpc is required")
+		}
+		if finalityRollup != "" && !contains([]string{ethutil.ZkRollupLinea, ethutil.ZkRollupScroll, ethutil.ZkRollupZkEvm}, finalityRollup) {
+			return fmt.Errorf("invalid --rollup %v", finalityRollup)
+		}
+		if finalityContract != "" && !isValidAddressArg(cmd.Context(), finalityContract) {
+			return fmt.Errorf("%v is not a valid eth address", finalityContract)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		checkNetworkAllowed("connecting L2 node " + finalityL2Rpc)
+		l2Client, err := ethutil.Dial(ctx, finalityL2Rpc)
+		checkErr(err)
+		defer l2Client.Close()
+
+		l2ChainId, err := l2Client.EthClient.ChainID(ctx)
+		checkErr(err)
+		config := ethutil.KnownZkRollups[l2ChainId.Uint64()]
+		if finalityRollup != "" {
+			config.Rollup = finalityRollup
+		}
+		if finalityContract != "" {
+			config.Contract = mustParseAddressArg(ctx, finalityContract)
+		}
+		if cmd.Flags().Changed("rollup-id") || config.RollupId == 0 {
+			config.RollupId = finalityRollupId
+		}
+		if config.Rollup == "" || config.Contract == (common.Address{}) {
+			log.Fatalf("L2 chain %v is not a known zk-rollup, --rollup and --contract are required", l2ChainId)
+		}
+
+		receipt, err := l2Client.EthClient.TransactionReceipt(ctx, common.HexToHash(args[0]))
+		checkErr(err)
+		blockNumber := receipt.BlockNumber.Uint64()
+
+		var batch *big.Int
+		if finalityBatch >= 0 {
+			batch = big.NewInt(finalityBatch)
+		} else if config.Rollup == ethutil.ZkRollupZkEvm {
+			batch, err = ethutil.ZkEvmBatchOfBlock(ctx, l2Client, blockNumber)
+			checkErr(err)
+		} else if config.Rollup == ethutil.ZkRollupScroll {
+			log.Fatalf("--batch is required by scroll, the batch of L2 block is not available on L1")
+		}
+
+		finality, err := ethutil.ZkRollupBlockFinality(ctx, globalClient, config, blockNumber, batch)
+		checkErr(err)
+
+		committed := stageStatus(finality.Committed, finality.CommitUnknown)
+		proven := stageStatus(finality.Proven, false)
+		finalized := stageStatus(finality.Finalized, false)
+		var batchText string
+		if finality.Batch != nil {
+			batchText = finality.Batch.String()
+		}
+		if printJSONL(map[string]any{
+			"rollup":         config.Rollup,
+			"l2_block":       blockNumber,
+			"batch":          batchText,
+			"committed":      committed,
+			"proven":         proven,
+			"finalized":      finalized,
+			"last_finalized": finality.LastFinalized.String(),
+		}) {
+			return
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", finality.Finalized)
+			return
+		}
+		fmt.Printf("rollup: %v\n", config.Rollup)
+		fmt.Printf("L2 block: %v\n", blockNumber)
+		if finality.Batch != nil {
+			fmt.Printf("batch: %v\n", finality.Batch)
+			fmt.Printf("last finalized batch: %v\n", finality.LastFinalized)
+		} else {
+			fmt.Printf("last finalized L2 block: %v\n", finality.LastFinalized)
+		}
+		fmt.Printf("committed: %v\n", committed)
+		fmt.Printf("proven: %v\n", proven)
+		fmt.Printf("finalized: %v\n", finalized)
+	},
+}
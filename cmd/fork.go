@@ -0,0 +1,354 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var forkExecABIFile string
+var forkExecHexData string
+var forkExecTx string
+var forkExecFrom string
+var forkExecValue string
+var forkExecUnit string
+var forkExecFund string
+var forkExecForkUrl string
+var forkExecForkBlock int64
+var forkExecAttach string
+var forkExecBinary string
+var forkExecPort int
+var forkExecReadyTimeout time.Duration
+var forkExecNoDecode bool
+
+func init() {
+	forkExecCmd.Flags().StringVarP(&forkExecABIFile, "abi-file", "", "", "the path of abi file, if this option specified, 'function definition' can be just function name")
+	forkExecCmd.Flags().StringVarP(&forkExecHexData, "hex-data", "", "", "the input hex data")
+	forkExecCmd.Flags().StringVarP(&forkExecTx, "tx", "", "", "replay this raw tx, signed tx is sent as is, unsigned tx is sent from --from (or its sender) by impersonation")
+	forkExecCmd.Flags().StringVarP(&forkExecFrom, "from", "", "", "the impersonated sender, default is address of --private-key")
+	forkExecCmd.Flags().StringVarP(&forkExecValue, "value", "", "0", "the amount of eth sent with tx, unit is ether and can be changed by --unit")
+	forkExecCmd.Flags().StringVarP(&forkExecUnit, "unit", "u", unitEther, "wei | gwei | ether, unit of --value")
+	forkExecCmd.Flags().StringVarP(&forkExecFund, "fund", "", "", "set balance (in ether) of sender before tx")
+	forkExecCmd.Flags().StringVarP(&forkExecForkUrl, "fork-url", "", "", "the rpc url forked by anvil, default is url of current network")
+	forkExecCmd.Flags().Int64VarP(&forkExecForkBlock, "fork-block", "", -1, "fork at this block, -1 means latest block")
+	forkExecCmd.Flags().StringVarP(&forkExecAttach, "attach", "", "", "use a running fork node (anvil or hardhat) at this rpc url instead of launching anvil")
+	forkExecCmd.Flags().StringVarP(&forkExecBinary, "binary", "", "", "the path of anvil, default is found in PATH")
+	forkExecCmd.Flags().IntVarP(&forkExecPort, "port", "", 0, "the http rpc port of launched anvil, 0 means a free port")
+	forkExecCmd.Flags().DurationVarP(&forkExecReadyTimeout, "ready-timeout", "", 60*time.Second, "how long to wait for rpc readiness of launched anvil")
+	forkExecCmd.Flags().BoolVarP(&forkExecNoDecode, "no-decode", "", false, "do not look up function names of call tree of reverted tx from https://openchain.xyz/signatures")
+
+	forkCmd.AddCommand(forkExecCmd)
+
+	addFlags(forkCmd, explorerFlags)
+}
+
+var forkCmd = &cobra.Command{
+	Use:   "fork",
+	Short: "Replay tx on a local fork (anvil or hardhat) of current network",
+}
+
+// freePort returns a free tcp port of localhost.
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// startForkNode launches anvil forking forkUrl, and returns its rpc url and a function to stop it.
+func startForkNode(ctx context.Context, forkUrl string) (string, func()) {
+	var binary = forkExecBinary
+	if binary == "" {
+		var err error
+		binary, err = exec.LookPath(devnetEngineAnvil)
+		if err != nil {
+			log.Fatalf("anvil is not found in PATH, install it, specify --binary or use --attach")
+		}
+	}
+	var port = forkExecPort
+	if port == 0 {
+		var err error
+		port, err = freePort()
+		checkErr(err)
+	}
+
+	args := []string{"--fork-url", forkUrl, "--port", strconv.Itoa(port)}
+	if forkExecForkBlock >= 0 {
+		args = append(args, "--fork-block-number", strconv.FormatInt(forkExecForkBlock, 10))
+	}
+	child := exec.Command(binary, args...)
+	logFile, err := os.CreateTemp("", "ethutil-fork-*.log")
+	checkErr(err)
+	child.Stdout = logFile
+	child.Stderr = logFile
+	log.Printf("starting anvil forking %v", forkUrl)
+	checkErr(child.Start())
+	log.Printf("the output of anvil is written to %v", logFile.Name())
+
+	var exited = make(chan error, 1)
+	go func() { exited <- child.Wait() }()
+	var stop = func() {
+		_ = child.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-exited:
+		case <-time.After(10 * time.Second):
+			_ = child.Process.Kill()
+		}
+		logFile.Close()
+	}
+
+	nodeUrl := fmt.Sprintf("http://127.0.0.1:%d", port)
+	client, err := waitRpcReady(ctx, nodeUrl, forkExecReadyTimeout)
+	if err != nil {
+		stop()
+		log.Fatalf("%v, see %v", err, logFile.Name())
+	}
+	client.RpcClient.Close()
+	return nodeUrl, stop
+}
+
+// forkExecCallArgs builds the tx sent by impersonation from args or unsigned --tx.
+func forkExecCallArgs(ctx context.Context, args []string) ethutil.CallArgs {
+	var callArgs ethutil.CallArgs
+	if forkExecFrom != "" {
+		from := mustParseAddressArg(ctx, forkExecFrom)
+		callArgs.From = &from
+	} else if globalOptPrivateKey != "" {
+		from := extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		callArgs.From = &from
+	}
+
+	if forkExecTx != "" {
+		tx, err := ethutil.ParseRawTx(forkExecTx)
+		checkErr(err)
+		if callArgs.From == nil {
+			log.Fatalf("--from or --private-key is required to replay unsigned tx")
+		}
+		callArgs.To = tx.To()
+		callArgs.Value = (*hexutil.Big)(tx.Value())
+		callArgs.Data = tx.Data()
+		if tx.Gas() > 0 {
+			gas := hexutil.Uint64(tx.Gas())
+			callArgs.Gas = &gas
+		}
+		return callArgs
+	}
+
+	if callArgs.From == nil {
+		log.Fatalf("--from or --private-key is required")
+	}
+	to := mustParseAddressArg(ctx, args[0])
+	callArgs.To = &to
+	callArgs.Value = (*hexutil.Big)(unify2Wei(decimal.RequireFromString(forkExecValue), forkExecUnit).BigInt())
+	if len(args) > 1 {
+		funcSignature, err := resolveFuncSignature(ctx, to, args[1], forkExecABIFile)
+		checkErr(err)
+		callArgs.Data, err = ethutil.BuildTxInputData(funcSignature, args[2:])
+		checkErr(err)
+	} else if forkExecHexData != "" {
+		callArgs.Data = common.FromHex(forkExecHexData)
+	}
+	return callArgs
+}
+
+// isSignedTx reports whether raw tx carries a signature.
+func isSignedTx(tx *types.Transaction) bool {
+	_, r, s := tx.RawSignatureValues()
+	return r != nil && s != nil && (r.Sign() != 0 || s.Sign() != 0)
+}
+
+// printStateDiffs prints balance, nonce, code and storage changes of accounts.
+func printStateDiffs(diffs []ethutil.AccountDiff) {
+	if len(diffs) == 0 {
+		fmt.Printf("no state changes\n")
+		return
+	}
+	for _, diff := range diffs {
+		fmt.Printf("%v\n", diff.Address.Hex())
+		if diff.BalanceBefore.Cmp(diff.BalanceAfter) != 0 {
+			change := new(big.Int).Sub(diff.BalanceAfter, diff.BalanceBefore)
+			var sign = ""
+			if change.Sign() > 0 {
+				sign = "+"
+			}
+			fmt.Printf("  balance: %v -> %v (%v%v ether)\n",
+				wei2Other(bigInt2Decimal(diff.BalanceBefore), unitEther).String(),
+				wei2Other(bigInt2Decimal(diff.BalanceAfter), unitEther).String(),
+				sign, wei2Other(bigInt2Decimal(change), unitEther).String())
+		}
+		if diff.NonceBefore != diff.NonceAfter {
+			fmt.Printf("  nonce: %v -> %v\n", diff.NonceBefore, diff.NonceAfter)
+		}
+		if diff.CodeChanged {
+			fmt.Printf("  code: changed\n")
+		}
+		for _, slot := range diff.Storage {
+			fmt.Printf("  storage %v: %v -> %v\n", slot.Slot.Hex(), slot.Before.Hex(), slot.After.Hex())
+		}
+	}
+}
+
+var forkExecCmd = &cobra.Command{
+	Use:   "exec [to-address ['function definition' arg1 arg2 ...]]",
+	Short: "Execute tx on a local fork by impersonation, then print receipt, revert trace and state diffs",
+	Long: "Execute tx on a local fork of current network, launched by anvil (or attached by --attach), with sender " +
+		"impersonated and optionally funded (--fund), then print receipt, call tree of reverted tx and balance, nonce, " +
+		"code and storage diffs. A raw tx can be replayed by --tx. Nothing is broadcast to current network.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if forkExecTx != "" {
+			if len(args) > 0 || forkExecHexData != "" {
+				return fmt.Errorf("--tx can not be specified with to-address or --hex-data")
+			}
+		} else if len(args) < 1 {
+			return fmt.Errorf("requires to-address or --tx")
+		}
+		if len(forkExecHexData) > 0 && len(args) > 1 {
+			return fmt.Errorf("--hex-data and 'function definition' cannot be specified at the same time")
+		}
+		if forkExecHexData != "" && !isValidHexString(forkExecHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if forkExecFrom != "" && !isValidAddressArg(cmd.Context(), forkExecFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", forkExecFrom)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, forkExecUnit) {
+			return fmt.Errorf("invalid unit %v", forkExecUnit)
+		}
+		if _, err := decimal.NewFromString(forkExecValue); err != nil {
+			return fmt.Errorf("--value %v is not a valid amount", forkExecValue)
+		}
+		if forkExecFund != "" {
+			if _, err := decimal.NewFromString(forkExecFund); err != nil {
+				return fmt.Errorf("--fund %v is not a valid amount", forkExecFund)
+			}
+		}
+		if forkExecAttach != "" && (forkExecForkUrl != "" || forkExecForkBlock >= 0) {
+			return fmt.Errorf("--attach can not be specified with --fork-url or --fork-block")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			args[0] = resolveAbiName(args[0]) // contract can be a name added by abi add
+			if !isValidAddressArg(cmd.Context(), args[0]) {
+				log.Fatalf("%s is NOT a valid eth address", args[0])
+			}
+		}
+
+		ctx := cmd.Context()
+		var nodeUrl = forkExecAttach
+		if nodeUrl == "" {
+			log.Printf("Current network is %v", globalOptNode)
+			var forkUrl = forkExecForkUrl
+			if forkUrl == "" {
+				forkUrl = globalOptNodeUrl
+			}
+			var stop func()
+			nodeUrl, stop = startForkNode(ctx, forkUrl)
+			defer stop()
+		}
+		InitGlobalClient(ctx, nodeUrl)
+		rpcClient := globalClient.RpcClient
+
+		var signedTx *types.Transaction
+		var callArgs ethutil.CallArgs
+		if forkExecTx != "" {
+			tx, err := ethutil.ParseRawTx(forkExecTx)
+			checkErr(err)
+			if isSignedTx(tx) {
+				signedTx = tx
+				sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+				checkErr(err)
+				callArgs.From = &sender
+				callArgs.To = tx.To()
+			}
+		}
+		if signedTx == nil {
+			callArgs = forkExecCallArgs(ctx, args)
+			checkErr(ethutil.ForkImpersonateAccount(ctx, rpcClient, *callArgs.From))
+		}
+		if forkExecFund != "" {
+			balance := unify2Wei(decimal.RequireFromString(forkExecFund), unitEther).BigInt()
+			checkErr(ethutil.DevnetSetBalance(ctx, rpcClient, *callArgs.From, balance))
+			log.Printf("balance of %v is set to %v ether", callArgs.From.Hex(), forkExecFund)
+		}
+		if globalOptShowInputData && signedTx == nil {
+			log.Printf("input data = %v", hexutil.Encode(callArgs.Data))
+		}
+
+		var txHash common.Hash
+		if signedTx != nil {
+			hash, err := ethutil.SendRawTransaction(ctx, rpcClient, signedTx)
+			checkErr(err)
+			txHash = *hash
+		} else {
+			var err error
+			txHash, err = ethutil.ForkSendTransaction(ctx, rpcClient, callArgs)
+			checkErr(err)
+		}
+		receipt, err := ethutil.WaitReceipt(ctx, globalClient, txHash, ethutil.WaitOptions{PollInterval: time.Second, Logf: log.Printf})
+		checkErr(err)
+
+		var status = "success"
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			status = "reverted"
+		}
+		fmt.Printf("tx %v on fork, block %v, status %v, gas used %v\n", txHash.Hex(), receipt.BlockNumber, status, receipt.GasUsed)
+		if receipt.ContractAddress != (common.Address{}) {
+			fmt.Printf("contract created at %v\n", receipt.ContractAddress.Hex())
+		}
+
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			result, err := ethutil.TraceTransaction(ctx, rpcClient, txHash, ethutil.TracerCall)
+			if err != nil {
+				log.Printf("trace reverted tx fail: %v", err)
+			} else {
+				var frame ethutil.CallFrame
+				checkErr(json.Unmarshal(result, &frame))
+				var funcSigs = make(funcSigCache)
+				ethutil.RenderCallTree(os.Stdout, &frame, func(selector []byte) string {
+					if forkExecNoDecode {
+						return ""
+					}
+					return strings.Join(funcSigs.lookup(hexutil.Encode(selector)), " | ")
+				})
+			}
+		}
+
+		diffs, err := ethutil.TraceStateDiff(ctx, rpcClient, txHash)
+		if err != nil {
+			// fall back to balance changes of sender and receiver
+			log.Printf("trace state diff fail: %v, only balance changes of sender and receiver are printed", err)
+			diffs = nil
+			for _, address := range []*common.Address{callArgs.From, callArgs.To} {
+				if address == nil {
+					continue
+				}
+				before, err := globalClient.EthClient.BalanceAt(ctx, *address, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+				checkErr(err)
+				after, err := globalClient.EthClient.BalanceAt(ctx, *address, receipt.BlockNumber)
+				checkErr(err)
+				diffs = append(diffs, ethutil.AccountDiff{Address: *address, BalanceBefore: before, BalanceAfter: after})
+			}
+		}
+		printStateDiffs(diffs)
+	},
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+)
+
+// globalFormatTemplate is parsed from --format in initConfig
+var globalFormatTemplate *template.Template
+
+// formatFuncs are the functions available in --format template
+var formatFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		content, err := json.Marshal(v)
+		return string(content), err
+	},
+	"ether": func(wei *big.Int) string {
+		if wei == nil {
+			return ""
+		}
+		return wei2Other(bigInt2Decimal(wei), unitEther).String()
+	},
+	"gwei": func(wei *big.Int) string {
+		if wei == nil {
+			return ""
+		}
+		return wei2Other(bigInt2Decimal(wei), unitGwei).String()
+	},
+}
+
+// parseFormat parses --format as go template, "\t" and "\n" in it are unescaped.
+func parseFormat(format string) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	return template.New("format").Funcs(formatFuncs).Option("missingkey=error").Parse(format)
+}
+
+// printFormatted prints result by --format template, a newline is appended if the output has no trailing newline.
+// It returns false and prints nothing if --format is not specified, then the caller prints result as usual.
+func printFormatted(result any) bool {
+	if globalFormatTemplate == nil {
+		return false
+	}
+	var buf bytes.Buffer
+	if err := globalFormatTemplate.Execute(&buf, result); err != nil {
+		fmt.Fprintf(os.Stderr, "apply --format to %T fail: %v\n", result, err)
+		os.Exit(1)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	fmt.Print(buf.String())
+	return true
+}
+
+// TxResult is the result of commands sending tx (transfer, call, deploy, send-raw etc.) available to --format.
+// Receipt fields are zero if the receipt is not waited for, e.g. with --dry-run or --not-check.
+type TxResult struct {
+	TxHash            common.Hash
+	From              common.Address
+	To                *common.Address // nil for contract creation
+	Nonce             uint64
+	Value             *big.Int // in wei
+	GasLimit          uint64
+	Status            uint64 // 1 for success, 0 for failure
+	BlockNumber       *big.Int
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int // in wei
+	Fee               *big.Int // GasUsed * EffectiveGasPrice, in wei
+	ContractAddress   *common.Address
+}
+
+// newTxResult builds TxResult of signedTx, receipt can be nil.
+func newTxResult(signedTx *types.Transaction, from common.Address, receipt *types.Receipt) *TxResult {
+	result := &TxResult{
+		TxHash:   signedTx.Hash(),
+		From:     from,
+		To:       signedTx.To(),
+		Nonce:    signedTx.Nonce(),
+		Value:    signedTx.Value(),
+		GasLimit: signedTx.Gas(),
+	}
+	if receipt != nil {
+		result.TxHash = receipt.TxHash
+		result.Status = receipt.Status
+		result.BlockNumber = receipt.BlockNumber
+		result.GasUsed = receipt.GasUsed
+		result.EffectiveGasPrice = receipt.EffectiveGasPrice
+		if receipt.EffectiveGasPrice != nil {
+			result.Fee = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		}
+		if signedTx.To() == nil {
+			contract := receipt.ContractAddress
+			result.ContractAddress = &contract
+		}
+	}
+	return result
+}
+
+// BalanceResult is the result of balance command available to --format.
+type BalanceResult struct {
+	Address    common.Address `json:"address"`
+	Balance    string         `json:"balance"`     // in --unit of balance command
+	BalanceWei *big.Int       `json:"balance_wei"` // in wei
+}
+
+// PriceResult is the result of price command available to --format.
+type PriceResult struct {
+	Coin     string
+	Currency string
+	Price    decimal.Decimal
+	Time     time.Time // zero for current price
+}
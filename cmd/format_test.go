@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseFormat(t *testing.T) {
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	tx := types.NewTransaction(7, to, big.NewInt(1500000000000000000), 21000, big.NewInt(2000000000), nil)
+	receipt := &types.Receipt{TxHash: tx.Hash(), Status: 1, BlockNumber: big.NewInt(100), GasUsed: 21000, EffectiveGasPrice: big.NewInt(2000000000)}
+	result := newTxResult(tx, common.Address{}, receipt)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{`{{.Nonce}} {{.GasUsed}} {{.Status}}`, "7 21000 1"},
+		{`{{.To}}`, to.Hex()},
+		{`{{ether .Value}}\t{{gwei .Fee}}`, "1.5\t42000"},
+		{`{{.BlockNumber}}{{with .ContractAddress}} {{.}}{{end}}`, "100"},
+		{`{{json .Status}}`, "1"},
+	}
+
+	for i, test := range tests {
+		tmpl, err := parseFormat(test.format)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, result); err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if buf.String() != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, buf.String())
+		}
+	}
+
+	tmpl, err := parseFormat(`{{.GasUsedX}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, result); err == nil {
+		t.Fatalf("expected error for unknown field")
+	}
+}
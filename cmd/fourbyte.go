@@ -11,22 +11,46 @@ import (
 var fourByteCmd = &cobra.Command{
 	Use:   "4byte [func-selector]",
 	Short: "Get the function signatures for the given selector from https://openchain.xyz/signatures",
-	Args:  cobra.ExactArgs(1),
+	Args:  inputArgs(cobra.ExactArgs(1)),
 	Run: func(cmd *cobra.Command, args []string) {
-		var funcHash = args[0]
-		if !strings.HasPrefix(funcHash, "0x") {
-			log.Fatalf("func-selector must starts with 0x")
-		}
+		forEachInput(args, []string{jsonlKeySelector, jsonlKeyData}, func(funcHash string) {
+			if !strings.HasPrefix(funcHash, "0x") {
+				log.Fatalf("func-selector must starts with 0x")
+			}
+			if len(funcHash) > 10 {
+				funcHash = funcHash[:10] // input data of tx, e.g. data of decode-tx --jsonl
+			}
 
-		funcSig, err := GetFuncSig(funcHash)
-		if err != nil {
-			log.Printf("getFuncSig failed %v", err)
-		}
-		for _, data := range funcSig {
-			fmt.Printf("%s\n", data)
-		}
-		if len(funcSig) == 0 {
-			fmt.Printf("Not found\n")
-		}
+			funcSig, err := GetFuncSig(funcHash)
+			if err != nil {
+				log.Printf("getFuncSig failed %v", err)
+			}
+			if printJSONL(map[string]any{jsonlKeySelector: funcHash, "signatures": funcSig}) {
+				return
+			}
+			for _, data := range funcSig {
+				fmt.Printf("%s\n", data)
+			}
+			if len(funcSig) == 0 {
+				fmt.Printf("Not found\n")
+			}
+		})
 	},
 }
+
+// funcSigCache caches function signatures looked up by GetFuncSig, so each selector is looked up only once.
+type funcSigCache map[string][]string
+
+// lookup returns function signatures of 4 bytes selector (e.g. "0xa9059cbb"), lookup failure is logged and treated
+// as not found.
+func (c funcSigCache) lookup(selector string) []string {
+	if sigs, ok := c[selector]; ok {
+		return sigs
+	}
+	sigs, err := GetFuncSig(selector)
+	if err != nil {
+		log.Printf("getFuncSig failed %v", err)
+	}
+	c[selector] = sigs
+	return sigs
+}
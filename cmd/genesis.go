@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const predeployCreate2Deployer = "create2-deployer"
+const predeployMulticall3 = "multicall3"
+
+var genesisChainId uint64
+var genesisFork string
+var genesisAllocFile string
+var genesisAllocUnit string
+var genesisPredeploys []string
+var genesisBlockGasLimit uint64
+var genesisTimestamp uint64
+var genesisExtraData string
+var genesisOutput string
+
+func init() {
+	genesisCmd.Flags().Uint64VarP(&genesisChainId, "chain-id", "", 1337, "the chain id")
+	genesisCmd.Flags().StringVarP(&genesisFork, "fork", "", "shanghai", strings.Join(ethutil.GenesisForks, " | ")+", all forks up to it are activated at genesis")
+	genesisCmd.Flags().StringVarP(&genesisAllocFile, "alloc-file", "", "", "the csv file of prefunded accounts, each line is address,balance")
+	genesisCmd.Flags().StringVarP(&genesisAllocUnit, "alloc-unit", "", "ether", "wei | gwei | ether, unit of balance in --alloc-file")
+	genesisCmd.Flags().StringSliceVarP(&genesisPredeploys, "predeploy", "", nil, predeployCreate2Deployer+" | "+predeployMulticall3+" | contract address, "+
+		"the code of multicall3 and contract address is copied from --node")
+	genesisCmd.Flags().Uint64VarP(&genesisBlockGasLimit, "block-gas-limit", "", 30000000, "the gas limit of genesis block")
+	genesisCmd.Flags().Uint64VarP(&genesisTimestamp, "timestamp", "", 0, "the timestamp of genesis block")
+	genesisCmd.Flags().StringVarP(&genesisExtraData, "extra-data", "", "0x", "the extra data of genesis block, in hex")
+	genesisCmd.Flags().StringVarP(&genesisOutput, "output", "o", "", "the output file, default is stdout")
+}
+
+// parseAllocCsv parses address,balance lines, a header line is allowed. Balance of duplicated address is summed.
+func parseAllocCsv(r io.Reader, unit string) (map[common.Address]*big.Int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var alloc = make(map[common.Address]*big.Int)
+	for i, record := range records {
+		if len(record) != 2 {
+			return nil, fmt.Errorf("line %d: expected 2 fields (address,balance), got %d", i+1, len(record))
+		}
+		if i == 0 && !isValidEthAddress(record[0]) {
+			continue // header
+		}
+		if !isValidEthAddress(record[0]) {
+			return nil, fmt.Errorf("line %d: %v is not a valid eth address", i+1, record[0])
+		}
+		balance, err := decimal.NewFromString(strings.TrimSpace(record[1]))
+		if err != nil || balance.IsNegative() {
+			return nil, fmt.Errorf("line %d: invalid balance %v", i+1, record[1])
+		}
+		address := common.HexToAddress(record[0])
+		if alloc[address] == nil {
+			alloc[address] = big.NewInt(0)
+		}
+		alloc[address].Add(alloc[address], unify2Wei(balance, unit).BigInt())
+	}
+	return alloc, nil
+}
+
+var genesisCmd = &cobra.Command{
+	Use:   "genesis",
+	Short: "Generate genesis.json (geth and reth compatible) for private chain, with prefunded accounts and predeployed contracts",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("genesis accepts no args")
+		}
+		if !contains(ethutil.GenesisForks, genesisFork) {
+			return fmt.Errorf("invalid --fork %v", genesisFork)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, genesisAllocUnit) {
+			return fmt.Errorf("invalid --alloc-unit %v", genesisAllocUnit)
+		}
+		for _, predeploy := range genesisPredeploys {
+			if predeploy != predeployCreate2Deployer && predeploy != predeployMulticall3 && !isValidEthAddress(predeploy) {
+				return fmt.Errorf("invalid --predeploy %v", predeploy)
+			}
+		}
+		if _, err := hexutil.Decode(genesisExtraData); err != nil {
+			return fmt.Errorf("invalid --extra-data: %w", err)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := ethutil.NewGenesisChainConfig(new(big.Int).SetUint64(genesisChainId), genesisFork)
+		checkErr(err)
+		var difficulty = big.NewInt(0)
+		if config.TerminalTotalDifficulty == nil {
+			difficulty = big.NewInt(1) // pre-merge chain
+		}
+		var genesis = ethutil.Genesis{
+			Config:     config,
+			Timestamp:  hexutil.Uint64(genesisTimestamp),
+			ExtraData:  hexutil.MustDecode(genesisExtraData),
+			GasLimit:   hexutil.Uint64(genesisBlockGasLimit),
+			Difficulty: (*hexutil.Big)(difficulty),
+			Alloc:      make(map[common.Address]*ethutil.GenesisAccount),
+		}
+
+		if genesisAllocFile != "" {
+			f, err := os.Open(genesisAllocFile)
+			checkErr(err)
+			alloc, err := parseAllocCsv(f, genesisAllocUnit)
+			f.Close()
+			checkErr(err)
+			for address, balance := range alloc {
+				genesis.AddAlloc(address, balance)
+			}
+			log.Printf("%v prefunded accounts", len(alloc))
+		}
+
+		for _, predeploy := range genesisPredeploys {
+			if predeploy == predeployCreate2Deployer {
+				genesis.AddPredeploy(ethutil.Create2DeployerAddress, ethutil.Create2DeployerCode)
+				continue
+			}
+
+			var address = common.HexToAddress(MulticallContractAddr)
+			if isValidEthAddress(predeploy) {
+				address = common.HexToAddress(predeploy)
+			}
+			if globalClient == nil {
+				log.Printf("Current network is %v", globalOptNode)
+				InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+			}
+			code, err := globalClient.EthClient.CodeAt(cmd.Context(), address, nil)
+			checkErr(err)
+			if len(code) == 0 {
+				log.Fatalf("%v is not a contract in %v, can not copy its code", address.Hex(), globalOptNode)
+			}
+			genesis.AddPredeploy(address, code)
+			log.Printf("code of %v (%v bytes) is copied from %v", address.Hex(), len(code), globalOptNode)
+		}
+
+		content, err := json.MarshalIndent(genesis, "", "  ")
+		checkErr(err)
+		if genesisOutput == "" {
+			fmt.Printf("%s\n", content)
+			return
+		}
+		checkErr(os.WriteFile(genesisOutput, append(content, '\n'), 0644))
+		log.Printf("genesis is written to %v", genesisOutput)
+	},
+}
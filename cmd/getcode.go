@@ -1,12 +1,12 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"log"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/spf13/cobra"
-	"log"
 )
 
 var getCodeCmd = &cobra.Command{
@@ -20,18 +20,18 @@ var getCodeCmd = &cobra.Command{
 			return fmt.Errorf("multiple contract-address is not supported")
 		}
 
-		if !isValidEthAddress(args[0]) {
+		if !isValidAddressArg(cmd.Context(), args[0]) {
 			return fmt.Errorf("%v is not a valid eth address", args[0])
 		}
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		contractAddress := args[0]
+		contractAddress := hexAddressArg(cmd.Context(), args[0])
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
 		byteCode, err := globalClient.EthClient.CodeAt(ctx, common.HexToAddress(contractAddress), nil)
 		checkErr(err)
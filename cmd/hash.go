@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var hashAlgorithm string
+var hashInputHex bool
+var hashInputFile bool
+
+func init() {
+	hashCmd.Flags().StringVarP(&hashAlgorithm, "algo", "a", "keccak256", "the hash algorithm, "+strings.Join(ethutil.HashAlgorithms, " | "))
+	hashCmd.Flags().BoolVarP(&hashInputHex, "hex", "", false, "the inputs are hex data, default is utf-8 strings")
+	hashCmd.Flags().BoolVarP(&hashInputFile, "file", "f", false, "the inputs are files (- means stdin), default is utf-8 strings")
+	hashCmd.MarkFlagsMutuallyExclusive("hex", "file")
+}
+
+var hashCmd = &cobra.Command{
+	Use:   "hash [flags] data ...",
+	Short: "Compute keccak256, sha256 or ripemd160 hash of strings, hex data or files",
+	Args: inputArgs(func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("requires at least one data")
+		}
+		return nil
+	}),
+	Run: func(cmd *cobra.Command, args []string) {
+		forEachInput(args, []string{jsonlKeyData}, func(arg string) {
+			var data []byte
+			var err error
+			if hashInputFile && arg == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else if hashInputFile {
+				data, err = os.ReadFile(arg)
+			} else if hashInputHex {
+				if !isValidHexString(arg) {
+					log.Fatalf("%v is not hex string", arg)
+				}
+				data = common.FromHex(arg)
+			} else {
+				data = []byte(arg)
+			}
+			checkErr(err)
+
+			hash, err := ethutil.Hash(hashAlgorithm, data)
+			checkErr(err)
+			if printJSONL(map[string]any{"hash": hexutil.Encode(hash), jsonlKeyData: arg}) {
+				return
+			}
+			if globalOptTerseOutput || len(args) == 1 {
+				fmt.Printf("%v\n", hexutil.Encode(hash))
+				return
+			}
+			fmt.Printf("%v  %v\n", hexutil.Encode(hash), arg)
+		})
+	},
+}
+
+var selectorCmd = &cobra.Command{
+	Use:   "selector signature ...",
+	Short: "Compute 4 bytes selector of function or custom error signature, e.g. 'transfer(address to, uint amount)'",
+	Args:  inputArgs(cobra.MinimumNArgs(1)),
+	Run: func(cmd *cobra.Command, args []string) {
+		forEachInput(args, []string{jsonlKeySignature}, func(arg string) {
+			selector, canonical, err := ethutil.FuncSelector(arg)
+			checkErr(err)
+			if printJSONL(map[string]any{jsonlKeySelector: hexutil.Encode(selector), jsonlKeySignature: canonical}) {
+				return
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", hexutil.Encode(selector))
+				return
+			}
+			fmt.Printf("%v  %v\n", hexutil.Encode(selector), canonical)
+		})
+	},
+}
+
+var topic0Cmd = &cobra.Command{
+	Use:   "topic0 signature ...",
+	Short: "Compute topic0 (hash of event signature), e.g. 'event Transfer(address indexed from, address indexed to, uint256 value)'",
+	Args:  inputArgs(cobra.MinimumNArgs(1)),
+	Run: func(cmd *cobra.Command, args []string) {
+		forEachInput(args, []string{jsonlKeySignature}, func(arg string) {
+			topic, canonical, err := ethutil.EventTopic(arg)
+			checkErr(err)
+			if printJSONL(map[string]any{"topic0": topic.Hex(), jsonlKeySignature: canonical}) {
+				return
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", topic.Hex())
+				return
+			}
+			fmt.Printf("%v  %v\n", topic.Hex(), canonical)
+		})
+	},
+}
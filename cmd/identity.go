@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var identityTexts []string
+var identityIpfsGateway string
+var identityNoActivity bool
+
+func init() {
+	identityCmd.Flags().StringSliceVarP(&identityTexts, "texts", "", []string{"url", "com.twitter", "com.github", "email"}, "the ENS text records to show, comma separated")
+	identityCmd.Flags().StringVarP(&identityIpfsGateway, "ipfs-gateway", "", ethutil.DefaultIpfsGateway, "the gateway used to rewrite ipfs:// URI of avatar")
+	identityCmd.Flags().BoolVarP(&identityNoActivity, "no-activity", "", false, "do not search first and last activity, which requires archive node")
+}
+
+var identityCmd = &cobra.Command{
+	Use:     "identity address|name",
+	Aliases: []string{"whois"},
+	Short:   "Show ENS name, avatar, text records, and first and last activity of address (or ENS name)",
+	Long: "Show ENS primary name (verified by resolving it), avatar (NFT avatar is resolved by its metadata), text " +
+		"records, and first and last activity of address (or ENS name). The activity is the block of first and last " +
+		"tx sent by address, found by binary search of historical nonce, so archive node is required.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires address or name")
+		}
+		if isValidEthAddress(args[0]) {
+			return nil
+		}
+		name, err := ethutil.EnsNormalize(args[0])
+		if err != nil {
+			return fmt.Errorf("%v is neither a valid eth address nor ENS name", args[0])
+		}
+		args[0] = name
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		var address common.Address
+		var name string
+		if isValidEthAddress(args[0]) {
+			address = common.HexToAddress(args[0])
+			var err error
+			name, err = ethutil.EnsLookup(ctx, globalClient.EthClient, address)
+			checkErr(err)
+			if name != "" {
+				if resolved, err := ethutil.EnsResolve(ctx, globalClient.EthClient, name); err != nil || resolved != address {
+					log.Printf("WARNING: primary name %v of %v does not resolve to it, ignore it", name, address.Hex())
+					name = ""
+				}
+			}
+		} else {
+			name = args[0]
+			var err error
+			address, err = ethutil.EnsResolve(ctx, globalClient.EthClient, name)
+			checkErr(err)
+			if address == (common.Address{}) {
+				log.Fatalf("%v does not resolve to any address", name)
+			}
+		}
+
+		var result = map[string]any{jsonlKeyAddress: address.Hex()}
+		var keys = []string{jsonlKeyAddress}
+		var set = func(key string, value any) {
+			result[key] = value
+			keys = append(keys, key)
+		}
+
+		if name != "" {
+			set("name", name)
+			if avatar, err := ethutil.EnsText(ctx, globalClient.EthClient, name, "avatar"); err != nil {
+				log.Printf("WARNING: get avatar of %v fail: %v", name, err)
+			} else if avatar != "" {
+				url, err := ethutil.EnsAvatarURL(ctx, globalClient.EthClient, avatar, address, identityIpfsGateway)
+				if err != nil {
+					log.Printf("WARNING: resolve avatar %v fail: %v", avatar, err)
+				} else {
+					set("avatar", url)
+				}
+			}
+			for _, key := range identityTexts {
+				value, err := ethutil.EnsText(ctx, globalClient.EthClient, name, key)
+				if err != nil {
+					log.Printf("WARNING: get text record %v of %v fail: %v", key, name, err)
+					continue
+				}
+				if value != "" {
+					set(key, value)
+				}
+			}
+		}
+
+		if !identityNoActivity {
+			first, last, nonce, err := ethutil.AccountActivity(ctx, globalClient.EthClient, address)
+			if err != nil {
+				log.Printf("WARNING: search activity fail (archive node is required): %v", err)
+			} else {
+				set("nonce", nonce)
+				if nonce > 0 {
+					set("first_activity", activityString(ctx, first))
+					set("last_activity", activityString(ctx, last))
+				}
+			}
+		}
+
+		if printJSONL(result) {
+			return
+		}
+		for _, key := range keys {
+			fmt.Printf("%v: %v\n", key, result[key])
+		}
+	},
+}
+
+// activityString returns block number and its time, e.g. "block 17400001 (2023-06-01T10:20:30Z)"
+func activityString(ctx context.Context, block uint64) string {
+	header, err := globalClient.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(block))
+	if err != nil {
+		return fmt.Sprintf("block %v", block)
+	}
+	return fmt.Sprintf("block %v (%v)", block, time.Unix(int64(header.Time), 0).UTC().Format(time.RFC3339))
+}
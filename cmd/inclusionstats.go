@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var inclusionStatsBlocks uint64
+
+func init() {
+	inclusionStatsCmd.Flags().Uint64VarP(&inclusionStatsBlocks, "blocks", "", 20, "the number of new blocks to sample")
+
+	addFlags(inclusionStatsCmd, txFlags, "priority-fee-floor")
+}
+
+var inclusionStatsCmd = &cobra.Command{
+	Use:   "inclusion-stats",
+	Short: "Sample new blocks and report how many blocks txs waited before inclusion, per priority fee bucket",
+	Long: "Sample new blocks and report how many blocks txs waited before inclusion, per priority fee bucket. " +
+		"Txs are first seen by a pending tx filter (eth_newPendingTransactionFilter), the buckets are split by " +
+		"the slow, average and fast max priority fee estimations, so the output is an empirical basis for --speed.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if inclusionStatsBlocks == 0 {
+			log.Fatalf("--blocks must be greater than 0")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+		client := globalClient.EthClient
+
+		estimate, err := newGasOracle(client).EstimateFees(ctx)
+		checkErr(err)
+		boundaries := []*big.Int{estimate.Slow, estimate.Average, estimate.Fast}
+
+		var filterId string
+		checkErr(globalClient.RpcClient.CallContext(ctx, &filterId, "eth_newPendingTransactionFilter"))
+		defer globalClient.RpcClient.Call(nil, "eth_uninstallFilter", filterId)
+
+		head, err := client.BlockNumber(ctx)
+		checkErr(err)
+		log.Printf("sampling %v blocks after block %v", inclusionStatsBlocks, head)
+
+		var firstSeen = make(map[common.Hash]uint64) // tx hash -> head when tx was first seen
+		var samples []ethutil.InclusionSample
+		var sampledBlocks uint64
+		for sampledBlocks < inclusionStatsBlocks {
+			var hashes []common.Hash
+			checkErr(globalClient.RpcClient.CallContext(ctx, &hashes, "eth_getFilterChanges", filterId))
+			for _, hash := range hashes {
+				if _, ok := firstSeen[hash]; !ok {
+					firstSeen[hash] = head
+				}
+			}
+
+			latest, err := client.BlockNumber(ctx)
+			checkErr(err)
+			for ; head < latest && sampledBlocks < inclusionStatsBlocks; sampledBlocks++ {
+				head++
+				block, err := client.BlockByNumber(ctx, new(big.Int).SetUint64(head))
+				checkErr(err)
+				if block.BaseFee() == nil {
+					log.Fatalf("base fee not found in block %v: %v", head, ethutil.ErrEip1559NotSupported)
+				}
+				var included int
+				for _, tx := range block.Transactions() {
+					seenAt, ok := firstSeen[tx.Hash()]
+					if !ok {
+						continue
+					}
+					delete(firstSeen, tx.Hash())
+					tip, err := tx.EffectiveGasTip(block.BaseFee())
+					if err != nil {
+						continue
+					}
+					samples = append(samples, ethutil.InclusionSample{Tip: tip, WaitBlocks: head - seenAt})
+					included++
+				}
+				log.Printf("block %v: %v of %v txs were seen pending", head, included, len(block.Transactions()))
+			}
+
+			// txs pending for too long (e.g. replaced or dropped) are forgotten
+			for hash, seenAt := range firstSeen {
+				if head > seenAt+inclusionStatsBlocks {
+					delete(firstSeen, hash)
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				checkErr(ctx.Err())
+			case <-time.After(time.Second):
+			}
+		}
+
+		if !globalOptTerseOutput {
+			fmt.Printf("%v txs sampled in %v blocks, tip estimations (%v): slow %v gwei, average %v gwei, fast %v gwei\n",
+				len(samples), sampledBlocks, estimate.Source,
+				wei2Other(bigInt2Decimal(estimate.Slow), unitGwei),
+				wei2Other(bigInt2Decimal(estimate.Average), unitGwei),
+				wei2Other(bigInt2Decimal(estimate.Fast), unitGwei))
+			fmt.Printf("%-32v %8v %10v %12v %10v\n", "tip (gwei)", "txs", "mean wait", "median wait", "p90 wait")
+		}
+		for _, bucket := range ethutil.SummarizeInclusion(samples, boundaries) {
+			var label string
+			switch {
+			case bucket.MinTip == nil:
+				label = fmt.Sprintf("< %v", wei2Other(bigInt2Decimal(bucket.MaxTip), unitGwei))
+			case bucket.MaxTip == nil:
+				label = fmt.Sprintf(">= %v", wei2Other(bigInt2Decimal(bucket.MinTip), unitGwei))
+			default:
+				label = fmt.Sprintf("%v - %v", wei2Other(bigInt2Decimal(bucket.MinTip), unitGwei), wei2Other(bigInt2Decimal(bucket.MaxTip), unitGwei))
+			}
+			fmt.Printf("%-32v %8v %10.2f %12v %10v\n", label, bucket.Count, bucket.MeanWait, bucket.MedianWait, bucket.P90Wait)
+		}
+	},
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var keyConvertTo string
+var keyConvertOutput string
+
+func init() {
+	keyConvertCmd.Flags().StringVarP(&keyConvertTo, "to", "", ethutil.KeyFormatHex, strings.Join(ethutil.KeyFormats, " | ")+", the output format")
+	keyConvertCmd.Flags().StringVarP(&keyConvertOutput, "output", "o", "", "the output file, default is stdout. binary formats (raw, sec1-der, pkcs8-der) are printed as hex on stdout")
+
+	keyCmd.AddCommand(keyConvertCmd)
+}
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Private key utilities: convert between hex, raw, SEC1/PKCS#8 PEM and DER, WIF",
+}
+
+var keyConvertCmd = &cobra.Command{
+	Use:   "convert private-key",
+	Short: "Convert secp256k1 private key between formats, the input format is detected automatically",
+	Long: "Convert secp256k1 private key between formats, the input format is detected automatically. " +
+		"private-key is the key (hex, WIF, hex of DER) or a file of key (e.g. PEM or DER exported from HSM and openssl). " +
+		"Keys in all these formats are also accepted by --private-key.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one private-key")
+		}
+		if !contains(ethutil.KeyFormats, keyConvertTo) {
+			return fmt.Errorf("invalid --to %v, expected one of %v", keyConvertTo, strings.Join(ethutil.KeyFormats, ", "))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		privateKey, format, err := ethutil.ParsePrivateKeyAny(readKeyInput(args[0]))
+		checkErr(err)
+		encoded, err := ethutil.MarshalPrivateKey(privateKey, keyConvertTo)
+		checkErr(err)
+		if !globalOptTerseOutput {
+			log.Printf("converting %v key of address %v to %v", format, extractAddressFromPrivateKey(privateKey).Hex(), keyConvertTo)
+		}
+
+		if keyConvertOutput != "" {
+			checkErr(os.WriteFile(keyConvertOutput, encoded, 0600))
+			return
+		}
+		switch keyConvertTo {
+		case ethutil.KeyFormatRaw, ethutil.KeyFormatSec1Der, ethutil.KeyFormatPkcs8Der:
+			fmt.Printf("%v\n", hexutil.Encode(encoded))
+		default:
+			fmt.Printf("%s\n", strings.TrimSuffix(string(encoded), "\n"))
+		}
+	},
+}
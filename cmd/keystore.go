@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// keystoreFile is the part of keystore V3 file used by ethutil. Totp is added by totp enroll --keystore, it is the
+// TOTP secret encrypted by keystore password, and the key itself is re-encrypted by both password and TOTP secret.
+type keystoreFile struct {
+	Crypto json.RawMessage      `json:"crypto"`
+	Totp   *keystore.CryptoJSON `json:"totp,omitempty"`
+}
+
+// keystoreScryptN and keystoreScryptP are scrypt parameters of keystore written by ethutil
+var keystoreScryptN, keystoreScryptP = keystore.StandardScryptN, keystore.StandardScryptP
+
+// keystoreKeys caches keys decrypted from keystore file, so password and TOTP code are asked only once per command
+var keystoreKeys = map[string]*ecdsa.PrivateKey{}
+
+// parseKeystoreFile returns nil if content is not a keystore V3 file.
+func parseKeystoreFile(content []byte) *keystoreFile {
+	var ks keystoreFile
+	if err := json.Unmarshal(content, &ks); err != nil || len(ks.Crypto) == 0 {
+		return nil
+	}
+	return &ks
+}
+
+// totpKeystorePassword returns the password which encrypts the key of keystore bound to TOTP secret.
+func totpKeystorePassword(password string, secret string) string {
+	return password + "\x00" + secret
+}
+
+// unlockKeystore decrypts keystore file by password. If the keystore is bound to TOTP (totp enroll --keystore), code
+// is called to read TOTP code and the TOTP secret is also returned.
+func unlockKeystore(content []byte, password string, code func() string) (*keystore.Key, string, error) {
+	ks := parseKeystoreFile(content)
+	if ks == nil {
+		return nil, "", fmt.Errorf("not a keystore file")
+	}
+	if ks.Totp == nil {
+		key, err := keystore.DecryptKey(content, password)
+		if errors.Is(err, keystore.ErrDecrypt) {
+			return nil, "", fmt.Errorf("%w, or the TOTP data of keystore is removed", err)
+		}
+		return key, "", err
+	}
+
+	secretBytes, err := keystore.DecryptDataV3(*ks.Totp, password)
+	if err != nil {
+		return nil, "", fmt.Errorf("decrypt TOTP secret of keystore fail: %w", err)
+	}
+	secret := string(secretBytes)
+	if err := checkTOTPCode(secret, code()); err != nil {
+		return nil, "", err
+	}
+	key, err := keystore.DecryptKey(content, totpKeystorePassword(password, secret))
+	if err != nil {
+		return nil, "", fmt.Errorf("decrypt keystore fail: %w", err)
+	}
+	return key, secret, nil
+}
+
+// buildPrivateKeyFromKeystore decrypts keystore file (--private-key) by password read from stdin, TOTP code is also
+// read if the keystore is bound to TOTP.
+func buildPrivateKeyFromKeystore(file string, content []byte) *ecdsa.PrivateKey {
+	if privateKey, ok := keystoreKeys[file]; ok {
+		return privateKey
+	}
+	key, _, err := unlockKeystore(content, readPassword(""), readTOTPCode)
+	checkErr(err)
+	keystoreKeys[file] = key.PrivateKey
+	return key.PrivateKey
+}
+
+// encryptKeystore encrypts key by password into keystore V3 file, totp is added to file if it's not nil.
+func encryptKeystore(key *keystore.Key, password string, totp *keystore.CryptoJSON) ([]byte, error) {
+	content, err := keystore.EncryptKey(key, password, keystoreScryptN, keystoreScryptP)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil {
+		return content, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(content, &fields); err != nil {
+		return nil, err
+	}
+	fields["totp"] = totp
+	return json.MarshalIndent(fields, "", "  ")
+}
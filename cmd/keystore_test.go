@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestUnlockKeystore(t *testing.T) {
+	keystoreScryptN, keystoreScryptP = keystore.LightScryptN, keystore.LightScryptP
+	defer func() {
+		keystoreScryptN, keystoreScryptP = keystore.StandardScryptN, keystore.StandardScryptP
+	}()
+
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &keystore.Key{Address: crypto.PubkeyToAddress(privateKey.PublicKey), PrivateKey: privateKey}
+	password := "secret password"
+	noCode := func() string {
+		t.Fatalf("TOTP code is asked")
+		return ""
+	}
+
+	plain, err := encryptKeystore(key, password, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlocked, secret, err := unlockKeystore(plain, password, noCode)
+	if err != nil || secret != "" || unlocked.Address != key.Address {
+		t.Fatalf("unlock keystore without TOTP fail: %v", err)
+	}
+
+	totpSecret, err := ethutil.NewTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := keystore.EncryptDataV3([]byte(totpSecret), []byte(password), keystoreScryptN, keystoreScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bound, err := encryptKeystore(key, totpKeystorePassword(password, totpSecret), &encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := ethutil.TOTPCode(totpSecret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unlocked, secret, err = unlockKeystore(bound, password, func() string { return code })
+	if err != nil || secret != totpSecret || unlocked.Address != key.Address {
+		t.Fatalf("unlock keystore bound to TOTP fail: %v", err)
+	}
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+	if _, _, err := unlockKeystore(bound, password, func() string { return wrongCode }); err == nil {
+		t.Fatalf("expected error of wrong TOTP code")
+	}
+
+	// the key can't be decrypted if TOTP data is removed from keystore
+	var fields map[string]any
+	if err := json.Unmarshal(bound, &fields); err != nil {
+		t.Fatal(err)
+	}
+	delete(fields, "totp")
+	stripped, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := unlockKeystore(stripped, password, noCode); err == nil || !strings.Contains(err.Error(), "TOTP data") {
+		t.Fatalf("expected error of removed TOTP data, got %v", err)
+	}
+
+	if _, _, err := unlockKeystore([]byte("0x1234"), password, noCode); err == nil {
+		t.Fatalf("expected error of non keystore")
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var mathUnchecked bool
+var mathUnit string
+
+func init() {
+	mathCmd.Flags().BoolVarP(&mathUnchecked, "unchecked", "", false, "wrap overflow and underflow modulo 2^256 like unchecked block of solidity, default is error")
+	mathCmd.Flags().StringVarP(&mathUnit, "unit", "u", "", "wei | gwei | ether, also print result in this unit")
+}
+
+var mathCmd = &cobra.Command{
+	Use:   "math 'expression'",
+	Short: "Evaluate expression over uint256, e.g. '1.5 ether * 3 / 7', 'mulDiv(a, b, c)', 'pct(a, 30)', '1 << 255'",
+	Long: "Evaluate expression over uint256 like the EVM. Number is decimal or hex, decimal can have fraction, " +
+		"exponent and unit suffix, e.g. 1.5ether, 30 gwei, 1e18, 0xff. Operators (precedence of solidity): ** ~ * / % + - " +
+		"<< >> & ^ | and parentheses. Functions: mulDiv(a, b, c), mulDivUp(a, b, c), pct(a, p) is a*p/100, " +
+		"bps(a, b) is a*b/10000, min(a, b), max(a, b), sqrt(a). Overflow and underflow are errors, unless --unchecked.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires expression")
+		}
+		if mathUnit != "" && !contains([]string{unitWei, unitGwei, unitEther}, mathUnit) {
+			return fmt.Errorf("invalid unit %v", mathUnit)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		result, err := ethutil.Uint256Eval(strings.Join(args, " "), mathUnchecked)
+		checkErr(err)
+
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", result)
+			return
+		}
+		fmt.Printf("dec: %v\n", result)
+		fmt.Printf("hex: %v\n", hexutil.EncodeBig(result))
+		if mathUnit != "" {
+			fmt.Printf("%v: %v\n", mathUnit, wei2Other(bigInt2Decimal(result), mathUnit).String())
+		}
+	},
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var mempoolWatchTo []string
+var mempoolWatchSelectors []string
+var mempoolWatchMinValue string
+var mempoolWatchMaxValue string
+var mempoolWatchUnit string
+var mempoolWatchTxpool bool
+var mempoolWatchPollInterval time.Duration
+var mempoolWatchCount uint64
+var mempoolWatchNoDecode bool
+
+func init() {
+	mempoolWatchCmd.Flags().StringSliceVarP(&mempoolWatchTo, "to", "", nil, "only watch txs to these addresses, comma separated")
+	mempoolWatchCmd.Flags().StringArrayVarP(&mempoolWatchSelectors, "selector", "", nil, "only watch txs calling these functions, 4 bytes selector (e.g. 0xa9059cbb) or function signature (e.g. 'transfer(address,uint256)'), specify multiple times for multiple functions")
+	mempoolWatchCmd.Flags().StringVarP(&mempoolWatchMinValue, "min-value", "", "", "only watch txs whose value is not less than this, in --unit")
+	mempoolWatchCmd.Flags().StringVarP(&mempoolWatchMaxValue, "max-value", "", "", "only watch txs whose value is not greater than this, in --unit")
+	mempoolWatchCmd.Flags().StringVarP(&mempoolWatchUnit, "unit", "u", unitEther, "wei | gwei | ether, unit of --min-value, --max-value and value in output")
+	mempoolWatchCmd.Flags().BoolVarP(&mempoolWatchTxpool, "txpool", "", false, "poll txpool_content instead of subscribing newPendingTransactions")
+	mempoolWatchCmd.Flags().DurationVarP(&mempoolWatchPollInterval, "poll-interval", "", time.Second, "the interval of polling txpool_content")
+	mempoolWatchCmd.Flags().Uint64VarP(&mempoolWatchCount, "count", "", 0, "exit after this many matched txs, 0 means watching until interrupted")
+	mempoolWatchCmd.Flags().BoolVarP(&mempoolWatchNoDecode, "no-decode", "", false, "do not look up function signature of selector from https://openchain.xyz/signatures")
+
+	mempoolCmd.AddCommand(mempoolWatchCmd)
+}
+
+var mempoolCmd = &cobra.Command{
+	Use:   "mempool",
+	Short: "Inspect pending txs in mempool of node",
+}
+
+// buildMempoolFilter builds the filter from flags of mempool watch.
+func buildMempoolFilter(ctx context.Context) (ethutil.MempoolFilter, error) {
+	var filter ethutil.MempoolFilter
+	for _, to := range mempoolWatchTo {
+		address, err := parseAddressArg(ctx, to)
+		if err != nil {
+			return filter, fmt.Errorf("--to %v is not a valid eth address", to)
+		}
+		filter.To = append(filter.To, address)
+	}
+	for _, selector := range mempoolWatchSelectors {
+		if isValidHexString(selector) {
+			if len(common.FromHex(selector)) != 4 {
+				return filter, fmt.Errorf("--selector %v is not 4 bytes", selector)
+			}
+			filter.Selectors = append(filter.Selectors, common.FromHex(selector))
+			continue
+		}
+		funcSelector, _, err := ethutil.FuncSelector(selector)
+		if err != nil {
+			return filter, fmt.Errorf("--selector %v is neither 4 bytes selector nor function signature: %w", selector, err)
+		}
+		filter.Selectors = append(filter.Selectors, funcSelector)
+	}
+	if mempoolWatchMinValue != "" {
+		value, err := decimal.NewFromString(mempoolWatchMinValue)
+		if err != nil {
+			return filter, fmt.Errorf("--min-value %v is not a valid number", mempoolWatchMinValue)
+		}
+		filter.MinValue = unify2Wei(value, mempoolWatchUnit).BigInt()
+	}
+	if mempoolWatchMaxValue != "" {
+		value, err := decimal.NewFromString(mempoolWatchMaxValue)
+		if err != nil {
+			return filter, fmt.Errorf("--max-value %v is not a valid number", mempoolWatchMaxValue)
+		}
+		filter.MaxValue = unify2Wei(value, mempoolWatchUnit).BigInt()
+	}
+	return filter, nil
+}
+
+// watchPendingTxs calls fn for each new pending tx. newPendingTransactions is subscribed if node is connected by
+// websocket or ipc, otherwise (or with --txpool) txpool_content is polled.
+func watchPendingTxs(ctx context.Context, fn func(tx *types.Transaction) bool) {
+	if !mempoolWatchTxpool && !strings.HasPrefix(globalOptNodeUrl, "http") {
+		ch := make(chan *types.Transaction, 256)
+		sub, err := ethutil.SubscribePendingTxs(ctx, globalClient.RpcClient, ch)
+		if err == nil {
+			defer sub.Unsubscribe()
+			log.Printf("subscribed newPendingTransactions")
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case err := <-sub.Err():
+					log.Fatalf("subscription of newPendingTransactions fail: %v", err)
+				case tx := <-ch:
+					if !fn(tx) {
+						return
+					}
+				}
+			}
+		}
+		log.Printf("subscribe newPendingTransactions fail: %v, polling txpool_content instead", err)
+	}
+
+	log.Printf("polling txpool_content every %v", mempoolWatchPollInterval)
+	var seen = make(map[common.Hash]bool)
+	for {
+		txs, err := ethutil.TxpoolPending(ctx, globalClient.RpcClient)
+		checkErr(err)
+		var pending = make(map[common.Hash]bool, len(txs)) // txs left mempool are forgotten
+		for _, tx := range txs {
+			pending[tx.Hash()] = true
+			if seen[tx.Hash()] {
+				continue
+			}
+			if !fn(tx) {
+				return
+			}
+		}
+		seen = pending
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(mempoolWatchPollInterval):
+		}
+	}
+}
+
+var mempoolWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Stream pending txs matching --to, --selector and value thresholds, with the function signatures of their selectors",
+	Long: "Stream pending txs matching --to, --selector and value thresholds, with the function signatures of their selectors. " +
+		"Full tx bodies are received by subscribing newPendingTransactions (websocket or ipc node url), or by polling " +
+		"txpool_content which requires the txpool namespace of node.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return fmt.Errorf("unknown args %v", args)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, mempoolWatchUnit) {
+			return fmt.Errorf("invalid unit %v", mempoolWatchUnit)
+		}
+		if mempoolWatchPollInterval <= 0 {
+			return fmt.Errorf("--poll-interval must be greater than 0")
+		}
+		_, err := buildMempoolFilter(cmd.Context())
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		filter, err := buildMempoolFilter(ctx)
+		checkErr(err)
+
+		var funcSigs = make(funcSigCache)
+		var matched uint64
+		watchPendingTxs(ctx, func(tx *types.Transaction) bool {
+			if !filter.Match(tx) {
+				return true
+			}
+			matched++
+			printPendingTx(tx, funcSigs)
+			return mempoolWatchCount == 0 || matched < mempoolWatchCount
+		})
+	},
+}
+
+// printPendingTx prints a matched pending tx, the function signatures of selector are looked up by funcSigs.
+func printPendingTx(tx *types.Transaction, funcSigs funcSigCache) {
+	from, _ := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx)
+	var selector string
+	if len(tx.Data()) >= 4 {
+		selector = hexutil.Encode(tx.Data()[:4])
+		if !mempoolWatchNoDecode {
+			funcSigs.lookup(selector)
+		}
+	}
+	value := wei2Other(bigInt2Decimal(tx.Value()), mempoolWatchUnit)
+
+	rawTx, err := ethutil.GenRawTx(tx)
+	checkErr(err)
+	if printJSONL(map[string]any{
+		jsonlKeyTxHash:   tx.Hash(),
+		"from":           from,
+		"to":             tx.To(),
+		"value":          value,
+		"nonce":          tx.Nonce(),
+		jsonlKeySelector: selector,
+		"signatures":     funcSigs[selector],
+		jsonlKeyRawTx:    rawTx,
+	}) {
+		return
+	}
+
+	to := "<contract creation>"
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	var call string
+	if selector != "" {
+		call = selector
+		if sigs := funcSigs[selector]; len(sigs) > 0 {
+			call = strings.Join(sigs, " | ")
+		}
+	}
+	if globalOptTerseOutput {
+		fmt.Printf("%v\n", tx.Hash().Hex())
+		return
+	}
+	fmt.Printf("%v %v -> %v value %v %v %v\n", tx.Hash().Hex(), from.Hex(), to, value, mempoolWatchUnit, call)
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var merkleTypes []string
+var merkleUnit string
+var merkleOutput string
+var merkleVerifyRoot string
+var merkleVerifyProof []string
+
+func init() {
+	merkleCmd.PersistentFlags().StringSliceVarP(&merkleTypes, "types", "", []string{"address", "uint256"}, "the abi types of leaf values (columns of csv)")
+	merkleBuildCmd.Flags().StringVarP(&merkleUnit, "unit", "", unitWei, "wei | gwei | ether, unit of uint columns in csv")
+	merkleBuildCmd.Flags().StringVarP(&merkleOutput, "output", "o", "", "the output json file, default is stdout")
+	merkleVerifyCmd.Flags().StringVarP(&merkleVerifyRoot, "root", "", "", "the merkle root")
+	merkleVerifyCmd.Flags().StringSliceVarP(&merkleVerifyProof, "proof", "", nil, "the proof, comma separated hashes")
+	_ = merkleVerifyCmd.MarkFlagRequired("root")
+
+	merkleCmd.AddCommand(merkleBuildCmd)
+	merkleCmd.AddCommand(merkleProofCmd)
+	merkleCmd.AddCommand(merkleVerifyCmd)
+}
+
+// parseMerkleCsv parses leaf values, each line has a column for each of types, a header line is allowed. uint columns
+// are converted from unit to wei.
+func parseMerkleCsv(r io.Reader, types []string, unit string) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var values [][]string
+	for i, record := range records {
+		if len(record) != len(types) {
+			return nil, fmt.Errorf("line %d: expected %d fields (%v), got %d", i+1, len(types), strings.Join(types, ","), len(record))
+		}
+		if i == 0 {
+			if _, err := ethutil.MerkleLeafHash(types, record); err != nil {
+				continue // header
+			}
+		}
+		var value []string
+		for j, field := range record {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(types[j], "uint") && !strings.HasSuffix(types[j], "]") {
+				amount, err := decimal.NewFromString(field)
+				if err != nil || amount.IsNegative() {
+					return nil, fmt.Errorf("line %d: invalid amount %v", i+1, field)
+				}
+				field = unify2Wei(amount, unit).BigInt().String()
+			}
+			value = append(value, field)
+		}
+		if _, err := ethutil.MerkleLeafHash(types, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseHashes parses hex strings as 32 bytes hashes.
+func parseHashes(hashes []string) ([]common.Hash, error) {
+	var result []common.Hash
+	for _, hash := range hashes {
+		if !isValidHexString(hash) || len(common.FromHex(hash)) != common.HashLength {
+			return nil, fmt.Errorf("%v is not a valid 32 bytes hash", hash)
+		}
+		result = append(result, common.HexToHash(hash))
+	}
+	return result, nil
+}
+
+var merkleCmd = &cobra.Command{
+	Use:   "merkle",
+	Short: "Build merkle tree for airdrop and verify proof, compatible with OpenZeppelin StandardMerkleTree",
+}
+
+var merkleBuildCmd = &cobra.Command{
+	Use:   "build csv-file",
+	Short: "Build merkle tree from csv (default columns are address,amount), output root, tree and proof of each leaf as json",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if merkleUnit != unitWei && merkleUnit != unitGwei && merkleUnit != unitEther {
+			log.Fatalf("invalid --unit %v", merkleUnit)
+		}
+		file, err := os.Open(args[0])
+		checkErr(err)
+		defer file.Close()
+		values, err := parseMerkleCsv(file, merkleTypes, merkleUnit)
+		checkErr(err)
+		tree, err := ethutil.NewMerkleTree(merkleTypes, values)
+		checkErr(err)
+
+		content, err := json.MarshalIndent(tree, "", "  ")
+		checkErr(err)
+		if merkleOutput == "" {
+			fmt.Printf("%s\n", content)
+			return
+		}
+		checkErr(os.WriteFile(merkleOutput, append(content, '\n'), 0644))
+		if !globalOptTerseOutput {
+			log.Printf("merkle tree of %v leaves is written to %v", len(values), merkleOutput)
+		}
+		fmt.Printf("%v\n", tree.Root.Hex())
+	},
+}
+
+var merkleProofCmd = &cobra.Command{
+	Use:   "proof tree-file value ...",
+	Short: "Print proof of leaf (e.g. address amount) in tree file created by merkle build",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		content, err := os.ReadFile(args[0])
+		checkErr(err)
+		var tree ethutil.MerkleTree
+		if err := json.Unmarshal(content, &tree); err != nil {
+			log.Fatalf("parse tree file %v fail: %v", args[0], err)
+		}
+		if len(tree.Tree) == 0 {
+			log.Fatalf("tree file %v is empty", args[0])
+		}
+
+		leaf, err := ethutil.MerkleLeafHash(tree.LeafEncoding, args[1:])
+		checkErr(err)
+		for _, value := range tree.Values {
+			if value.TreeIndex < len(tree.Tree) && tree.Tree[value.TreeIndex] == leaf {
+				var proof []string
+				for _, p := range tree.Proof(value.TreeIndex) {
+					proof = append(proof, p.Hex())
+				}
+				if globalOptTerseOutput {
+					fmt.Printf("%v\n", strings.Join(proof, ","))
+					return
+				}
+				fmt.Printf("leaf %v\nroot %v\nproof [%v]\n", leaf.Hex(), tree.Tree[0].Hex(), strings.Join(proof, ","))
+				return
+			}
+		}
+		log.Fatalf("leaf %v (%v) is not in tree", leaf.Hex(), strings.Join(args[1:], ","))
+	},
+}
+
+var merkleVerifyCmd = &cobra.Command{
+	Use:   "verify --root root --proof proof value ...",
+	Short: "Verify proof of leaf (e.g. address amount) against merkle root, exit with 1 if it's invalid",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roots, err := parseHashes([]string{merkleVerifyRoot})
+		checkErr(err)
+		proof, err := parseHashes(merkleVerifyProof)
+		checkErr(err)
+		leaf, err := ethutil.MerkleLeafHash(merkleTypes, args)
+		checkErr(err)
+		if !ethutil.VerifyMerkleProof(roots[0], leaf, proof) {
+			fmt.Printf("proof is INVALID\n")
+			os.Exit(1)
+		}
+		fmt.Printf("proof is valid\n")
+	},
+}
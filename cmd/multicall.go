@@ -1,27 +1,31 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"math/big"
 	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const MulticallContractAddr = "0xcA11bde05977b3631167028862bE2a173976CA11" // See https://github.com/mds1/multicall
 const MulticallFuncSignGetEthBalance = "4d2301cc" // 4 bytes func signature of `getEthBalance(address)`
 const MulticallFuncSignAggregate = "252dba42" // 4 bytes func signature of `aggregate((address,bytes)[])`
 
-func isMulticallDeployed(client *ethclient.Client) bool {
-	deployed, err := isContractAddress(client, common.HexToAddress(MulticallContractAddr))
+// isMulticallDeployed returns true if multicall contract is deployed at the block, nil block means latest block.
+func isMulticallDeployed(ctx context.Context, client *ethclient.Client, block *big.Int) bool {
+	bytecode, err := client.CodeAt(ctx, common.HexToAddress(MulticallContractAddr), block)
 	if err != nil {
 		return false
 	}
 
-	return deployed
+	return len(bytecode) > 0
 }
 
-func queryEthBalancesByMulticall(addresses []string) ([]*big.Int, error) {
+func queryEthBalancesByMulticall(ctx context.Context, addresses []string, block *big.Int) ([]*big.Int, error) {
 	contractAddress := common.HexToAddress(MulticallContractAddr)
 
 	funcSignGetEthBalance, err := hex.DecodeString(MulticallFuncSignGetEthBalance)
@@ -37,7 +41,7 @@ func queryEthBalancesByMulticall(addresses []string) ([]*big.Int, error) {
 	// build tx input data
 	var callDataForAggregate []string
 	for _, address := range addresses {
-		parameter, err := encodeParameters([]string{"address"}, []string{address})
+		parameter, err := ethutil.EncodeParameters([]string{"address"}, []string{address})
 		if err != nil {
 			return nil, err
 		}
@@ -48,13 +52,13 @@ func queryEthBalancesByMulticall(addresses []string) ([]*big.Int, error) {
 		callDataForAggregate = append(callDataForAggregate,
 			"("+contractAddress.String()+",0x"+hex.EncodeToString(callDataForGetEthBalance)+")")
 	}
-	txInputData, err := encodeParameters([]string{"(address,bytes)[]"},
+	txInputData, err := ethutil.EncodeParameters([]string{"(address,bytes)[]"},
 		[]string{"[" + strings.Join(callDataForAggregate, ",") + "]"})
 	// fmt.Printf("txInputData = %x\n", txInputData)
 
 	// call multicall contract function aggregate:
 	// function aggregate((address,bytes)[]) public payable returns (uint256 blockNumber, bytes[] memory returnData)
-	output, err := Call(globalClient.EthClient, contractAddress, append(funcSignAggregate, txInputData...))
+	output, err := ethutil.Call(ctx, globalClient.EthClient, contractAddress, append(funcSignAggregate, txInputData...), block)
 	checkErr(err)
 	// fmt.Printf("output = %x\n", output)
 	//
@@ -83,3 +87,16 @@ func queryEthBalancesByMulticall(addresses []string) ([]*big.Int, error) {
 
 	return rv, nil
 }
+
+// queryEthBalances queries eth balances of addresses by multicall if it's deployed at the block, otherwise by JSON-RPC
+// batch requests of eth_getBalance.
+func queryEthBalances(ctx context.Context, addresses []string, block *big.Int) ([]*big.Int, error) {
+	if isMulticallDeployed(ctx, globalClient.EthClient, block) {
+		return queryEthBalancesByMulticall(ctx, addresses, block)
+	}
+	var addrs []common.Address
+	for _, address := range addresses {
+		addrs = append(addrs, common.HexToAddress(address))
+	}
+	return ethutil.BatchBalances(ctx, globalClient.RpcClient, addrs, block, globalOptRpcBatchSize)
+}
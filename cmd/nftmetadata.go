@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var nftMetadataIpfsGateway string
+var nftMetadataSaveMedia string
+var nftMetadataFull bool
+
+func init() {
+	nftMetadataCmd.Flags().StringVarP(&nftMetadataIpfsGateway, "ipfs-gateway", "", ethutil.DefaultIpfsGateway, "the gateway used to fetch ipfs:// URI")
+	nftMetadataCmd.Flags().StringVarP(&nftMetadataSaveMedia, "save-media", "", "", "write images and animations embedded in metadata (data URI or SVG of on-chain NFT) to files in this directory")
+	nftMetadataCmd.Flags().BoolVarP(&nftMetadataFull, "full", "", false, "print long data URIs in metadata as they are, default they are summarized as media type and size")
+}
+
+var nftMetadataCmd = &cobra.Command{
+	Use:   "nft-metadata contract token-id",
+	Short: "Fetch metadata of NFT (ERC-721 or ERC-1155), data URI is decoded and ipfs:// URI is fetched by gateway",
+	Long: "Fetch metadata of NFT (ERC-721 or ERC-1155), data URI of on-chain NFT is decoded and ipfs:// URI is " +
+		"fetched by gateway. Images and animations embedded in metadata (data URI or SVG) can be written to files by " +
+		"--save-media, so fully on-chain NFTs can be inspected without a browser.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("requires contract and token-id")
+		}
+		if _, err := ethutil.ParseInteger(args[1]); err != nil {
+			return fmt.Errorf("token-id %v is not a valid integer", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		args[0] = resolveAbiName(args[0]) // contract can be a name added by abi add
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			log.Fatalf("%s is NOT a valid eth address", args[0])
+		}
+		tokenId, _ := ethutil.ParseInteger(args[1])
+
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		contract := mustParseAddressArg(ctx, args[0])
+		uri, err := ethutil.NftTokenURI(ctx, globalClient.EthClient, contract, tokenId)
+		checkErr(err)
+		if !globalOptTerseOutput {
+			if len(uri) > 100 {
+				log.Printf("token URI: %v... (%v chars)", uri[:100], len(uri))
+			} else {
+				log.Printf("token URI: %v", uri)
+			}
+		}
+
+		metadata, err := ethutil.FetchNftMetadata(ctx, uri, nftMetadataIpfsGateway)
+		checkErr(err)
+
+		if nftMetadataSaveMedia != "" {
+			media, err := ethutil.ExtractNftMedia(metadata)
+			checkErr(err)
+			if len(media) == 0 {
+				log.Printf("no media is embedded in metadata")
+			}
+			checkErr(os.MkdirAll(nftMetadataSaveMedia, 0755))
+			for _, m := range media {
+				file := filepath.Join(nftMetadataSaveMedia, fmt.Sprintf("%v-%v-%v%v", contract.Hex(), tokenId, m.Field, m.Extension()))
+				checkErr(os.WriteFile(file, m.Data, 0644))
+				log.Printf("%v (%v, %v bytes) is written to %v", m.Field, m.MediaType, len(m.Data), file)
+			}
+		}
+
+		if !nftMetadataFull {
+			metadata = ethutil.SummarizeDataURIs(metadata, 200)
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, metadata, "", "  "); err != nil {
+			// not JSON, print as it is
+			fmt.Printf("%s\n", metadata)
+			return
+		}
+		fmt.Printf("%s\n", indented.Bytes())
+	},
+}
@@ -0,0 +1,309 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var nonceReuseFromBlock int64
+var nonceReuseToBlock int64
+var nonceReuseTxFile string
+
+func init() {
+	nonceReuseCmd.Flags().Int64VarP(&nonceReuseFromBlock, "from-block", "", -1, "scan blocks starting from this block instead of querying tx list of block explorer, -1 means 1000 blocks before --to-block")
+	nonceReuseCmd.Flags().Int64VarP(&nonceReuseToBlock, "to-block", "", -1, "scan blocks up to this block (inclusive) instead of querying tx list of block explorer, -1 means latest block")
+	nonceReuseCmd.Flags().StringVarP(&nonceReuseTxFile, "tx-file", "f", "", "read tx hashes (one per line) from this file instead of querying tx list of block explorer, file - means read stdin")
+
+	addFlags(nonceReuseCmd, exportFlags)
+	addFlags(nonceReuseCmd, explorerFlags)
+}
+
+// txSignature is the signature related info of a tx which is needed for detecting ecdsa nonce reuse
+type txSignature struct {
+	TxHash      common.Hash
+	SigningHash common.Hash
+	R           *big.Int
+	S           *big.Int
+}
+
+var nonceReuseCmd = &cobra.Command{
+	Use:   "scan-nonce-reuse address",
+	Short: "Scan historical tx signatures of address and flag reused ecdsa nonce (r value)",
+	Long: "Scan historical tx signatures of address and flag reused ecdsa nonce (r value). " +
+		"If two different txs are signed with the same r, the private key of address can be computed by anyone.\n\n" +
+		"All txs sent by address are listed by block explorer api, use --from-block/--to-block to scan blocks of node " +
+		"instead, or --tx-file to scan given txs. A warning is printed if the history is not fully scanned.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		address := mustParseAddressArg(cmd.Context(), args[0])
+
+		var sigs []txSignature
+		var partial string // why the history of address is not fully covered
+		var err error
+		if nonceReuseTxFile != "" {
+			sigs, err = collectSignaturesFromTxFile(cmd.Context(), address, nonceReuseTxFile)
+			partial = "only txs in " + nonceReuseTxFile + " are scanned"
+		} else if nonceReuseFromBlock >= 0 || nonceReuseToBlock >= 0 {
+			sigs, partial, err = collectSignaturesFromBlocks(cmd.Context(), address, nonceReuseFromBlock, nonceReuseToBlock)
+		} else {
+			sigs, partial, err = collectSignaturesFromExplorer(cmd.Context(), address)
+		}
+		checkErr(err)
+
+		log.Printf("collected %v signatures of %v", len(sigs), address.Hex())
+		if partial != "" {
+			log.Printf("WARNING: history of %v is not fully scanned, %v", address.Hex(), partial)
+		}
+
+		reused := findReusedR(sigs)
+		table := newExportTable(exportColumn{"address", columnAddress}, exportColumn{"r", columnUint256},
+			exportColumn{"s", columnUint256}, exportColumn{"tx_hash", columnBytes})
+		for _, group := range reused {
+			for _, sig := range group {
+				table.Append(address, sig.R, sig.S, sig.TxHash)
+			}
+		}
+		if exportResults(table) {
+			return
+		}
+
+		if len(reused) == 0 {
+			if partial != "" {
+				fmt.Printf("inconclusive: no reused r value in %v scanned signatures, but history is not fully scanned (%v)\n", len(sigs), partial)
+				return
+			}
+			fmt.Printf("no reused r value found in all %v signatures\n", len(sigs))
+			return
+		}
+
+		for _, group := range reused {
+			if globalOptTerseOutput {
+				var hashes []string
+				for _, sig := range group {
+					hashes = append(hashes, sig.TxHash.Hex())
+				}
+				fmt.Printf("%064x %v\n", group[0].R, strings.Join(hashes, " "))
+			} else {
+				fmt.Printf("WARNING: r value %064x is reused by %v txs, the private key of %v is exposed\n", group[0].R, len(group), address.Hex())
+				for _, sig := range group {
+					fmt.Printf("  tx %v, s %064x\n", sig.TxHash.Hex(), sig.S)
+				}
+			}
+		}
+	},
+}
+
+// extractTxSignature extracts signature info from tx, the sender of tx is also returned.
+func extractTxSignature(tx *types.Transaction) (common.Address, *txSignature, error) {
+	var signer types.Signer
+	if tx.Protected() {
+		signer = types.LatestSignerForChainID(tx.ChainId())
+	} else {
+		signer = types.HomesteadSigner{}
+	}
+
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+
+	_, r, s := tx.RawSignatureValues()
+	return sender, &txSignature{
+		TxHash:      tx.Hash(),
+		SigningHash: signer.Hash(tx),
+		R:           r,
+		S:           s,
+	}, nil
+}
+
+// collectSignaturesFromExplorer collects signatures of all txs sent by address, the tx list is queried from block
+// explorer. The reason is returned if any tx is missing, e.g. tx can not be decoded.
+func collectSignaturesFromExplorer(ctx context.Context, address common.Address) ([]txSignature, string, error) {
+	explorer, err := newExplorerClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	txs, err := explorer.AllTxList(ctx, address, ethutil.ExplorerQuery{})
+	if err != nil {
+		return nil, "", fmt.Errorf("query tx list fail: %w", err)
+	}
+	// tx list of explorer may be incomplete, the nonce tells how many txs are sent
+	nonce, err := globalClient.EthClient.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("NonceAt fail: %w", err)
+	}
+
+	var sigs []txSignature
+	var skipped int
+	for _, item := range txs {
+		if item.From != address {
+			continue
+		}
+		tx, _, err := globalClient.EthClient.TransactionByHash(ctx, item.Hash)
+		if err != nil {
+			// e.g. tx type unknown to go-ethereum
+			log.Printf("skip tx %v: %v", item.Hash.Hex(), err)
+			skipped++
+			continue
+		}
+		_, sig, err := extractTxSignature(tx)
+		if err != nil {
+			log.Printf("skip tx %v: %v", item.Hash.Hex(), err)
+			skipped++
+			continue
+		}
+		sigs = append(sigs, *sig)
+	}
+
+	if skipped > 0 {
+		return sigs, fmt.Sprintf("%v txs are skipped", skipped), nil
+	}
+	if uint64(len(sigs)) < nonce {
+		return sigs, fmt.Sprintf("%v txs are found by block explorer, but nonce of address is %v", len(sigs), nonce), nil
+	}
+	return sigs, "", nil
+}
+
+// collectSignaturesFromBlocks scans blocks in range [fromBlock, toBlock] and collects signatures of txs sent by address.
+// Blocks fail to fetch or decode are skipped, the reason of partial scan is returned.
+func collectSignaturesFromBlocks(ctx context.Context, address common.Address, fromBlock, toBlock int64) ([]txSignature, string, error) {
+	if toBlock < 0 {
+		latest, err := globalClient.EthClient.BlockNumber(ctx)
+		if err != nil {
+			return nil, "", fmt.Errorf("BlockNumber fail: %w", err)
+		}
+		toBlock = int64(latest)
+	}
+	if fromBlock < 0 {
+		fromBlock = toBlock - 1000
+		if fromBlock < 0 {
+			fromBlock = 0
+		}
+	}
+	if fromBlock > toBlock {
+		return nil, "", fmt.Errorf("--from-block %v is greater than --to-block %v", fromBlock, toBlock)
+	}
+
+	log.Printf("scanning blocks [%v, %v]", fromBlock, toBlock)
+
+	var sigs []txSignature
+	var skipped []int64
+	for number := fromBlock; number <= toBlock; number++ {
+		block, err := globalClient.EthClient.BlockByNumber(ctx, big.NewInt(number))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, "", ctx.Err()
+			}
+			// e.g. block contains tx type unknown to go-ethereum
+			log.Printf("skip block %v: %v", number, err)
+			skipped = append(skipped, number)
+			continue
+		}
+		for _, tx := range block.Transactions() {
+			sender, sig, err := extractTxSignature(tx)
+			if err != nil {
+				log.Printf("skip tx %v: %v", tx.Hash().Hex(), err)
+				continue
+			}
+			if sender == address {
+				sigs = append(sigs, *sig)
+			}
+		}
+	}
+
+	var partial = fmt.Sprintf("only blocks [%v, %v] are scanned", fromBlock, toBlock)
+	if len(skipped) > 0 {
+		partial += fmt.Sprintf(", %v blocks are skipped %v", len(skipped), skipped)
+	}
+	return sigs, partial, nil
+}
+
+// collectSignaturesFromTxFile collects signatures of txs (sent by address) listed in file.
+func collectSignaturesFromTxFile(ctx context.Context, address common.Address, file string) ([]txSignature, error) {
+	var inputReader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed open file: %w", err)
+		}
+		defer f.Close()
+		inputReader = f
+	}
+
+	var sigs []txSignature
+	scanner := bufio.NewScanner(inputReader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		tx, _, err := globalClient.EthClient.TransactionByHash(ctx, common.HexToHash(line))
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Printf("skip tx %v: %v", line, err)
+			continue
+		}
+		sender, sig, err := extractTxSignature(tx)
+		if err != nil {
+			log.Printf("skip tx %v: %v", line, err)
+			continue
+		}
+		if sender != address {
+			log.Printf("skip tx %v, it is sent by %v", line, sender.Hex())
+			continue
+		}
+		sigs = append(sigs, *sig)
+	}
+
+	return sigs, scanner.Err()
+}
+
+// findReusedR groups signatures by r value, and returns groups that the same r is used to sign different messages.
+func findReusedR(sigs []txSignature) [][]txSignature {
+	var order []string
+	var groups = make(map[string][]txSignature)
+	for _, sig := range sigs {
+		key := sig.R.Text(16)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], sig)
+	}
+
+	var rc [][]txSignature
+	for _, key := range order {
+		group := groups[key]
+		for _, sig := range group[1:] {
+			// same r for different signing hash means nonce reuse
+			if sig.SigningHash != group[0].SigningHash {
+				rc = append(rc, group)
+				break
+			}
+		}
+	}
+	return rc
+}
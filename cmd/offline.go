@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/spf13/cobra"
+)
+
+// annotationOffline marks command which is allowed with --no-network, the mark of command group applies to all its
+// subcommands.
+const annotationOffline = "offline"
+
+// errNoNetwork is returned by any http request made with --no-network
+var errNoNetwork = errors.New("network access is disabled by --no-network")
+
+// offlineCommands are the commands allowed with --no-network. Some of them access network only when some options are
+// missing (e.g. build-tx without --nonce, genesis with --predeploy of contract address), they fail fast in this case.
+var offlineCommands = []*cobra.Command{
+	keccakCmd, hashCmd, selectorCmd, topic0Cmd, checksumCmd, pubkeyCmd, genkeyCmd, dumpAddrCmd, vanityCmd,
+	walletNewCmd, walletDecryptCmd, encodeParamCmd, decodeTxCmd, abiCmd, computeContractAddrCmd, buildTxCmd, signTxCmd,
+	personalSignCmd, personalVerifyCmd, signHashCmd, policyCmd, approveCmd, totpCmd, merkleCmd, genesisCmd, safeTxHashCmd, safeSignCmd,
+	safeCombineCmd, signDocCmd, verifyDocCmd, keyCmd, exportTxCmd, convertCmd, mathCmd,
+}
+
+func init() {
+	for _, cmd := range offlineCommands {
+		if cmd.Annotations == nil {
+			cmd.Annotations = make(map[string]string)
+		}
+		cmd.Annotations[annotationOffline] = "true"
+	}
+}
+
+// noNetworkTransport refuses all http requests
+type noNetworkTransport struct{}
+
+func (noNetworkTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errNoNetwork
+}
+
+// isOfflineCommand returns true if cmd or any of its parents is marked by annotationOffline, help and completion are
+// always offline.
+func isOfflineCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[annotationOffline] == "true" || c.Name() == "help" || c.Name() == "completion" {
+			return true
+		}
+	}
+	return !cmd.Runnable() // command group only prints help
+}
+
+// enforceNoNetwork exits if cmd is not allowed with --no-network, and makes all http requests fail. Websocket and ipc
+// connections are only made by InitGlobalClient, which is guarded by checkNetworkAllowed.
+func enforceNoNetwork(cmd *cobra.Command) {
+	if !globalOptNoNetwork {
+		return
+	}
+	if !isOfflineCommand(cmd) {
+		log.Fatalf("command %v requires network access, which is disabled by --no-network", cmd.CommandPath())
+	}
+	http.DefaultTransport = noNetworkTransport{}
+	http.DefaultClient.Transport = noNetworkTransport{}
+}
+
+// checkNetworkAllowed exits if --no-network is specified, it's called before connecting node. what describes the
+// purpose of network access.
+func checkNetworkAllowed(what string) {
+	if globalOptNoNetwork {
+		log.Fatalf("%v requires network access, which is disabled by --no-network", what)
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+func TestIsOfflineCommand(t *testing.T) {
+	tests := []struct {
+		cmd     *cobra.Command
+		offline bool
+	}{
+		{signTxCmd, true},
+		{pubkeyCompressCmd, true}, // subcommand of offline group
+		{walletNewCmd, true},
+		{walletScanCmd, false},
+		{balanceCmd, false},
+		{sendRawCmd, false},
+		{defiCmd, true}, // command group only prints help
+		{defiTokenCmd, false},
+	}
+
+	for i, test := range tests {
+		if offline := isOfflineCommand(test.cmd); offline != test.offline {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.offline, offline)
+		}
+	}
+}
+
+func TestOfflineGasSchedule(t *testing.T) {
+	tests := []struct {
+		chainId *big.Int
+		fork    string
+	}{
+		{big.NewInt(1), "prague"},
+		{big.NewInt(11155111), "prague"},
+		{big.NewInt(123456789), ethutil.LatestGasFork}, // unknown chain
+		{nil, ethutil.LatestGasFork},                   // pre-EIP155 tx
+	}
+
+	for i, test := range tests {
+		schedule, err := offlineGasSchedule(test.chainId)
+		if err != nil || schedule.Fork != test.fork {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.fork, schedule.Fork, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var opOutputRootL2Rpc string
+var opOutputRootOracle string
+var opOutputRootOutputIndex int64
+var opOutputRootL2Block int64
+var opOutputRootFactory string
+var opOutputRootGameIndex int64
+var opOutputRootGame string
+
+func init() {
+	opOutputRootCmd.Flags().StringVarP(&opOutputRootL2Rpc, "l2-rpc", "", "", "the node url of L2, which must serve eth_getProof at the block of output root")
+	opOutputRootCmd.Flags().StringVarP(&opOutputRootOracle, "oracle", "", "", "the L2OutputOracle contract on L1, used by chains without fault proofs")
+	opOutputRootCmd.Flags().Int64VarP(&opOutputRootOutputIndex, "output-index", "", -1, "the index of output proposal in --oracle")
+	opOutputRootCmd.Flags().Int64VarP(&opOutputRootL2Block, "l2-block", "", -1, "use the first output proposal in --oracle covering this L2 block, i.e. the block of withdrawal")
+	opOutputRootCmd.Flags().StringVarP(&opOutputRootFactory, "dispute-game-factory", "", "", "the DisputeGameFactory contract on L1, used by chains with fault proofs")
+	opOutputRootCmd.Flags().Int64VarP(&opOutputRootGameIndex, "game-index", "", -1, "the index of dispute game in --dispute-game-factory")
+	opOutputRootCmd.Flags().StringVarP(&opOutputRootGame, "game", "", "", "the dispute game proxy, e.g. the game used to prove withdrawal")
+}
+
+// gameStatusName returns name of dispute game status.
+func gameStatusName(status uint8) string {
+	switch status {
+	case ethutil.GameStatusInProgress:
+		return "IN_PROGRESS"
+	case ethutil.GameStatusChallengerWins:
+		return "CHALLENGER_WINS"
+	case ethutil.GameStatusDefenderWins:
+		return "DEFENDER_WINS"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", status)
+}
+
+// printOutputRoot prints the proposal and the recomputed output root proof.
+func printOutputRoot(proposal *ethutil.OutputProposal, proof *ethutil.OutputRootProof, matched bool) {
+	if printJSONL(map[string]any{
+		"l2_block":                    proposal.L2BlockNumber.String(),
+		"proposed_output_root":        proposal.OutputRoot.Hex(),
+		"computed_output_root":        proof.OutputRoot().Hex(),
+		"version":                     proof.Version.Hex(),
+		"state_root":                  proof.StateRoot.Hex(),
+		"message_passer_storage_root": proof.MessagePasserStorageRoot.Hex(),
+		"latest_blockhash":            proof.LatestBlockhash.Hex(),
+		"matched":                     matched,
+	}) {
+		return
+	}
+	if globalOptTerseOutput {
+		fmt.Printf("%v\n", matched)
+		return
+	}
+	if proposal.Game != nil {
+		fmt.Printf("dispute game: %v\n", proposal.Game.Hex())
+		fmt.Printf("game status: %v\n", gameStatusName(proposal.GameStatus))
+	}
+	fmt.Printf("proposed at: %v\n", time.Unix(int64(proposal.Timestamp), 0).UTC().Format(time.RFC3339))
+	fmt.Printf("L2 block: %v\n", proposal.L2BlockNumber)
+	fmt.Printf("proposed output root: %v\n", proposal.OutputRoot.Hex())
+	fmt.Printf("computed output root: %v\n", proof.OutputRoot().Hex())
+	fmt.Printf("  version: %v\n", proof.Version.Hex())
+	fmt.Printf("  state root: %v\n", proof.StateRoot.Hex())
+	fmt.Printf("  message passer storage root: %v\n", proof.MessagePasserStorageRoot.Hex())
+	fmt.Printf("  latest blockhash: %v\n", proof.LatestBlockhash.Hex())
+}
+
+var opOutputRootCmd = &cobra.Command{
+	Use:   "op-output-root --l2-rpc url (--game address | --dispute-game-factory address --game-index n | --oracle address (--output-index n | --l2-block n))",
+	Short: "Verify the output root of OP-stack chain proposed on L1, by recomputing it from L2 node",
+	Long: "Fetch the L2 output root proposed on L1 (current --node) by L2OutputOracle or dispute game, which is used to\n" +
+		"prove withdrawals, recompute it from block header and L2ToL1MessagePasser storage root served by --l2-rpc, and\n" +
+		"check they are equal. The recomputed output root proof is the outputRootProof of proveWithdrawalTransaction.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("op-output-root accepts no args")
+		}
+		if opOutputRootL2Rpc == "" {
+			return fmt.Errorf("--l2-rpc is required")
+		}
+		var sources int
+		for _, specified := range []bool{opOutputRootGame != "", opOutputRootFactory != "", opOutputRootOracle != ""} {
+			if specified {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("exactly one of --game, --dispute-game-factory and --oracle is required")
+		}
+		for _, address := range []string{opOutputRootGame, opOutputRootFactory, opOutputRootOracle} {
+			if address != "" && !isValidAddressArg(cmd.Context(), address) {
+				return fmt.Errorf("%v is not a valid eth address", address)
+			}
+		}
+		if opOutputRootFactory != "" && opOutputRootGameIndex < 0 {
+			return fmt.Errorf("--game-index is required by --dispute-game-factory")
+		}
+		if opOutputRootOracle != "" && (opOutputRootOutputIndex < 0) == (opOutputRootL2Block < 0) {
+			return fmt.Errorf("exactly one of --output-index and --l2-block is required by --oracle")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		var proposal *ethutil.OutputProposal
+		var err error
+		switch {
+		case opOutputRootOracle != "":
+			oracle := mustParseAddressArg(ctx, opOutputRootOracle)
+			index := big.NewInt(opOutputRootOutputIndex)
+			if opOutputRootL2Block >= 0 {
+				index, err = ethutil.L2OutputIndexAfter(ctx, globalClient, oracle, big.NewInt(opOutputRootL2Block))
+				checkErr(err)
+				log.Printf("output proposal %v covers L2 block %v", index, opOutputRootL2Block)
+			}
+			proposal, err = ethutil.L2OutputOracleProposal(ctx, globalClient, oracle, index)
+			checkErr(err)
+		default:
+			game := mustParseAddressArg(ctx, opOutputRootGame)
+			if opOutputRootFactory != "" {
+				game, err = ethutil.DisputeGameAtIndex(ctx, globalClient, mustParseAddressArg(ctx, opOutputRootFactory), big.NewInt(opOutputRootGameIndex))
+				checkErr(err)
+			}
+			proposal, err = ethutil.DisputeGameProposal(ctx, globalClient, game)
+			checkErr(err)
+		}
+
+		checkNetworkAllowed("connecting L2 node " + opOutputRootL2Rpc)
+		l2Client, err := ethutil.Dial(ctx, opOutputRootL2Rpc)
+		checkErr(err)
+		defer l2Client.Close()
+		proof, err := ethutil.ComputeOutputRoot(ctx, l2Client, proposal.L2BlockNumber)
+		checkErr(err)
+		matched := proof.OutputRoot() == proposal.OutputRoot
+
+		printOutputRoot(proposal, proof, matched)
+
+		if !matched {
+			log.Printf("output root mismatch, the proposal is invalid or --l2-rpc is not the node of this chain")
+			os.Exit(1)
+		}
+		if proposal.Game != nil && proposal.GameStatus != ethutil.GameStatusDefenderWins {
+			log.Printf("output root matches, but the game is %v, withdrawals proven by it can't be finalized until defender wins", gameStatusName(proposal.GameStatus))
+			return
+		}
+		log.Printf("output root matches")
+	},
+}
@@ -1,45 +1,101 @@
 package cmd
 
 import (
-	"crypto/ecdsa"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
+var personalSignHex bool
+var personalSignFile string
+var personalSignNoPrefix bool
+var personalSignAuth bool
+var personalSignAuthNonce string
+
+func init() {
+	personalSignCmd.Flags().BoolVarP(&personalSignHex, "hex", "", false, "msg is hex encoded binary data (e.g. a 32 bytes digest), the decoded bytes are signed instead of the hex string")
+	personalSignCmd.Flags().StringVarP(&personalSignFile, "file", "", "", "sign raw bytes of this file instead of msg")
+	personalSignCmd.Flags().BoolVarP(&personalSignNoPrefix, "no-prefix", "", false, "sign keccak256 of msg directly, without EIP191 prefix \"\\x19Ethereum Signed Message:\\n\" + len(msg)")
+	personalSignCmd.Flags().BoolVarP(&personalSignAuth, "auth", "", false, "append a nonce and the current time to msg for login, the signature can be verified by personal-verify --auth")
+	personalSignCmd.Flags().StringVarP(&personalSignAuthNonce, "auth-nonce", "", "", "the nonce issued by server for --auth, a random nonce is used if not specified")
+
+	addFlags(personalSignCmd, txFlags, "policy", "totp-file")
+}
+
 // personalSignCmd represents the personalSign command
 var personalSignCmd = &cobra.Command{
 	Use:   "personal-sign [msg]",
 	Short: "Create EIP191 personal sign",
-	Args:  cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if personalSignFile != "" {
+			if len(args) != 0 {
+				return fmt.Errorf("msg and --file cannot be specified at the same time")
+			}
+			if personalSignHex {
+				return fmt.Errorf("--hex and --file cannot be specified at the same time")
+			}
+			if personalSignAuth || personalSignAuthNonce != "" {
+				return fmt.Errorf("--auth and --file cannot be specified at the same time")
+			}
+			return nil
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one msg")
+		}
+		if personalSignAuthNonce != "" {
+			personalSignAuth = true
+		}
+		if personalSignAuth && (personalSignHex || personalSignNoPrefix) {
+			return fmt.Errorf("--auth can not be used with --hex or --no-prefix")
+		}
+		if personalSignHex {
+			if _, err := hexutil.Decode(args[0]); err != nil {
+				return fmt.Errorf("msg is not valid hex: %w", err)
+			}
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		var msg = args[0]
+		var msg []byte
+		var err error
+		if personalSignFile != "" {
+			msg, err = os.ReadFile(personalSignFile)
+			checkErr(err)
+		} else if personalSignHex {
+			msg = hexutil.MustDecode(args[0]) // validated in Args
+		} else if personalSignAuth {
+			nonce := personalSignAuthNonce
+			if nonce == "" {
+				nonce, err = ethutil.NewAuthNonce()
+				checkErr(err)
+			}
+			authMessage := &ethutil.AuthMessage{Statement: args[0], Nonce: nonce, IssuedAt: time.Now()}
+			msg = []byte(authMessage.String())
+			fmt.Printf("message: %q\n", msg)
+		} else {
+			msg = []byte(args[0])
+		}
 
 		if globalOptPrivateKey == "" {
 			log.Fatalf("--private-key is required for this command")
 		}
 
 		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
-		sig, err := personalSign(msg, privateKey)
+		checkNoPolicy("a message")
+		checkTOTP()
+		if personalSignNoPrefix {
+			sig, err := ethutil.SignKeccak(msg, privateKey)
+			checkErr(err)
+			fmt.Printf("sign (no prefix): %s, signer address: %s\n", sig, extractAddressFromPrivateKey(privateKey).String())
+			return
+		}
+		sig, err := ethutil.PersonalSignBytes(msg, privateKey)
 		checkErr(err)
 		fmt.Printf("personal sign: %s, signer address: %s\n", sig, extractAddressFromPrivateKey(privateKey).String())
 	},
 }
-
-
-// personalSign Returns personal_sign signature data
-// See: https://eips.ethereum.org/EIPS/eip-191
-// The signature data can be verified in https://etherscan.io/verifiedSignatures
-func personalSign(message string, privateKey *ecdsa.PrivateKey) (string, error) {
-	fullMessage := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
-	hash := crypto.Keccak256Hash([]byte(fullMessage))
-	signatureBytes, err := crypto.Sign(hash.Bytes(), privateKey)
-	if err != nil {
-		return "", err
-	}
-	signatureBytes[64] += 27
-	return hexutil.Encode(signatureBytes), nil
-}
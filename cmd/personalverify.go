@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var personalVerifyHex bool
+var personalVerifyFile string
+var personalVerifyAddress string
+var personalVerifyAuth bool
+var personalVerifyNonce string
+var personalVerifyMaxAge time.Duration
+var personalVerifyMaxSkew time.Duration
+var personalVerifyUsedNonces string
+
+func init() {
+	personalVerifyCmd.Flags().BoolVarP(&personalVerifyHex, "hex", "", false, "msg is hex encoded binary data, the decoded bytes are verified instead of the hex string")
+	personalVerifyCmd.Flags().StringVarP(&personalVerifyFile, "file", "", "", "verify raw bytes of this file instead of msg")
+	personalVerifyCmd.Flags().StringVarP(&personalVerifyAddress, "address", "", "", "the expected signer")
+	personalVerifyCmd.Flags().BoolVarP(&personalVerifyAuth, "auth", "", false, "msg is signed by personal-sign --auth, check its nonce and issued time")
+	personalVerifyCmd.Flags().StringVarP(&personalVerifyNonce, "nonce", "", "", "the nonce issued by server, only used by --auth")
+	personalVerifyCmd.Flags().DurationVarP(&personalVerifyMaxAge, "max-age", "", 5*time.Minute, "msg issued earlier than this duration ago is expired, only used by --auth")
+	personalVerifyCmd.Flags().DurationVarP(&personalVerifyMaxSkew, "max-skew", "", 30*time.Second, "tolerance of clock skew for msg issued in future, only used by --auth")
+	personalVerifyCmd.Flags().StringVarP(&personalVerifyUsedNonces, "used-nonces", "", "", "the file of used nonces (one per line), msg with a used nonce is rejected as replay and the nonce is appended after verification, only used by --auth")
+}
+
+// isUsedNonce returns true if nonce is a line of file, file not existing means no nonce is used.
+func isUsedNonce(file string, nonce string) (bool, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == nonce {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// addUsedNonce appends nonce to file.
+func addUsedNonce(file string, nonce string) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(nonce + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+var personalVerifyCmd = &cobra.Command{
+	Use:   "personal-verify [msg] signature",
+	Short: "Verify EIP191 personal sign, exit with 1 if it's invalid",
+	Long: "Verify EIP191 personal sign, exit with 1 if it's invalid. " +
+		"msg can be quoted as printed by personal-sign --auth (e.g. \"hello\\n\\nNonce: ...\"). " +
+		"With --auth, nonce and issued time in msg are checked, used nonces can be recorded to reject replay.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		expected := 2
+		if personalVerifyFile != "" {
+			expected = 1
+		}
+		if len(args) != expected {
+			return fmt.Errorf("requires msg (or --file) and signature")
+		}
+		if !isValidHexString(args[len(args)-1]) {
+			return fmt.Errorf("signature must hex string")
+		}
+		if personalVerifyAddress != "" && !isValidAddressArg(cmd.Context(), personalVerifyAddress) {
+			return fmt.Errorf("--address %v is not a valid eth address", personalVerifyAddress)
+		}
+		if personalVerifyHex && personalVerifyAuth {
+			return fmt.Errorf("--hex can not be used with --auth")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var msg []byte
+		if personalVerifyFile != "" {
+			var err error
+			msg, err = os.ReadFile(personalVerifyFile)
+			checkErr(err)
+		} else if personalVerifyHex {
+			var err error
+			msg, err = hexutil.Decode(args[0])
+			checkErr(err)
+		} else if unquoted, err := strconv.Unquote(args[0]); err == nil && strings.HasPrefix(args[0], `"`) {
+			msg = []byte(unquoted)
+		} else {
+			msg = []byte(args[0])
+		}
+		signature := common.FromHex(args[len(args)-1])
+
+		if !personalVerifyAuth {
+			signer, err := ethutil.RecoverPersonalSignBytes(msg, signature)
+			checkErr(err)
+			if personalVerifyAddress != "" && signer != mustParseAddressArg(cmd.Context(), personalVerifyAddress) {
+				log.Fatalf("signature is INVALID, it's signed by %v", signer.Hex())
+			}
+			fmt.Printf("signature is valid, signer address: %v\n", signer.Hex())
+			return
+		}
+
+		opts := ethutil.AuthVerifyOptions{Nonce: personalVerifyNonce, MaxAge: personalVerifyMaxAge, MaxSkew: personalVerifyMaxSkew}
+		if personalVerifyAddress != "" {
+			opts.Signer = mustParseAddressArg(cmd.Context(), personalVerifyAddress)
+		}
+		authMessage, signer, err := ethutil.VerifyAuthMessage(string(msg), signature, opts)
+		if err != nil {
+			log.Fatalf("signature is INVALID: %v", err)
+		}
+		if personalVerifyUsedNonces != "" {
+			used, err := isUsedNonce(personalVerifyUsedNonces, authMessage.Nonce)
+			checkErr(err)
+			if used {
+				log.Fatalf("signature is INVALID: nonce %v is already used, the signature is replayed", authMessage.Nonce)
+			}
+			checkErr(addUsedNonce(personalVerifyUsedNonces, authMessage.Nonce))
+		}
+		fmt.Printf("signature is valid, signer address: %v, nonce: %v, issued at: %v\n", signer.Hex(), authMessage.Nonce, authMessage.IssuedAt.Format(time.RFC3339))
+	},
+}
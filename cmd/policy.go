@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+var policyVerifySigner string
+var policyCheckTo string
+var policyCheckValue string
+var policyCheckData string
+var policyCheckChainId uint64
+
+// globalPolicy is loaded from --policy on first use
+var globalPolicy *ethutil.Policy
+
+func init() {
+	policyVerifyCmd.Flags().StringVarP(&policyVerifySigner, "signer", "", "", "the trusted signer, default is --policy-signer")
+	policyCheckCmd.Flags().StringVarP(&policyCheckTo, "to", "", "", "the destination of tx, empty means contract creation")
+	policyCheckCmd.Flags().StringVarP(&policyCheckValue, "value", "", "0", "the value of tx, unit is wei")
+	policyCheckCmd.Flags().StringVarP(&policyCheckData, "data", "", "", "the input data of tx")
+	policyCheckCmd.Flags().Uint64VarP(&policyCheckChainId, "chain-id", "", 0, "the chain id of tx, default is chain id of --node")
+
+	policyCmd.AddCommand(policySignCmd)
+	policyCmd.AddCommand(policyVerifyCmd)
+	policyCmd.AddCommand(policyCheckCmd)
+
+	addFlags(policyCmd, txFlags, "policy", "policy-signer", "totp-file")
+}
+
+// policySignatureFile returns the file of policy signature, i.e. <policy-file>.sig
+func policySignatureFile(policyFile string) string {
+	return policyFile + ".sig"
+}
+
+// loadPolicy reads policy file, the signature of it is verified if signer is not empty.
+func loadPolicy(policyFile string, signer string) (*ethutil.Policy, error) {
+	content, err := os.ReadFile(policyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file fail: %w", err)
+	}
+	if signer != "" {
+		signature, err := os.ReadFile(policySignatureFile(policyFile))
+		if err != nil {
+			return nil, fmt.Errorf("read policy signature fail: %w", err)
+		}
+		if err := ethutil.VerifyPolicySignature(content, string(signature), common.HexToAddress(signer)); err != nil {
+			return nil, err
+		}
+	}
+	return ethutil.ParsePolicy(content)
+}
+
+// checkPolicy exits if the tx is denied by --policy, and asks for confirmation if the matched rule requires it.
+// chainID is queried from client if it's nil. It does nothing if --policy is not specified.
+func checkPolicy(ctx context.Context, client *ethclient.Client, chainID *big.Int, to *common.Address, value *big.Int, data []byte) {
+	if globalOptPolicy == "" {
+		return
+	}
+	if globalPolicy == nil {
+		var err error
+		if globalPolicy, err = loadPolicy(globalOptPolicy, globalOptPolicySigner); err != nil {
+			log.Fatalf("load policy fail: %v", err)
+		}
+	}
+	if chainID == nil {
+		var err error
+		chainID, err = client.ChainID(ctx)
+		checkErr(err)
+	}
+
+	rule, err := globalPolicy.Evaluate(ethutil.PolicyTx{ChainId: chainID, To: to, Value: value, Data: data, Time: time.Now()})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if !globalOptTerseOutput {
+		log.Printf("tx is allowed by policy rule %v", rule.Name)
+	}
+	if rule.RequireConfirmation {
+		var dest = "contract creation"
+		if to != nil {
+			dest = to.Hex()
+		}
+		fmt.Fprintf(os.Stderr, "policy rule %v requires confirmation, send %v wei to %v with %v bytes data on chain %v? type yes to continue: ",
+			rule.Name, value, dest, len(data), chainID)
+		line, _ := stdinReader.ReadString('\n')
+		if strings.TrimSpace(line) != "yes" {
+			log.Fatalf("tx is not confirmed")
+		}
+	}
+}
+
+// checkNoPolicy exits if --policy is specified. It's called before signing what can't be evaluated by policy rules,
+// e.g. a raw hash or a message.
+func checkNoPolicy(what string) {
+	if globalOptPolicy != "" {
+		log.Fatalf("signing %v is refused, as it can not be evaluated by --policy %v", what, globalOptPolicy)
+	}
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Sign, verify and test the policy file (--policy) evaluated before signing any tx",
+}
+
+var policySignCmd = &cobra.Command{
+	Use:   "sign policy-file",
+	Short: "Sign policy file with --private-key, the signature is written to <policy-file>.sig",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for policy sign command")
+		}
+		content, err := os.ReadFile(args[0])
+		checkErr(err)
+		_, err = ethutil.ParsePolicy(content)
+		checkErr(err)
+
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		checkTOTP()
+		signature, err := ethutil.PersonalSignBytes(content, privateKey)
+		checkErr(err)
+		checkErr(os.WriteFile(policySignatureFile(args[0]), []byte(signature+"\n"), 0644))
+		if !globalOptTerseOutput {
+			log.Printf("policy is signed by %v, signature is written to %v", extractAddressFromPrivateKey(privateKey).Hex(), policySignatureFile(args[0]))
+		}
+		fmt.Printf("%v\n", signature)
+	},
+}
+
+var policyVerifyCmd = &cobra.Command{
+	Use:   "verify policy-file",
+	Short: "Verify policy file and its signature <policy-file>.sig",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		signer := policyVerifySigner
+		if signer == "" {
+			signer = globalOptPolicySigner
+		}
+		if !isValidEthAddress(signer) {
+			log.Fatalf("--signer (or --policy-signer) is required and must be a valid eth address")
+		}
+		policy, err := loadPolicy(args[0], signer)
+		checkErr(err)
+		fmt.Printf("policy is valid and signed by %v, %v rules\n", common.HexToAddress(signer).Hex(), len(policy.Rules))
+	},
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Evaluate a tx against --policy offline, print the matched rule",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("policy check accepts no args")
+		}
+		if policyCheckTo != "" && !isValidAddressArg(cmd.Context(), policyCheckTo) {
+			return fmt.Errorf("--to %v is not a valid eth address", policyCheckTo)
+		}
+		if _, ok := new(big.Int).SetString(policyCheckValue, 10); !ok {
+			return fmt.Errorf("invalid --value %v", policyCheckValue)
+		}
+		if policyCheckData != "" && !isValidHexString(policyCheckData) {
+			return fmt.Errorf("--data must be hex string")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPolicy == "" {
+			log.Fatalf("--policy is required for policy check command")
+		}
+		policy, err := loadPolicy(globalOptPolicy, globalOptPolicySigner)
+		checkErr(err)
+
+		var to *common.Address
+		if policyCheckTo != "" {
+			address := mustParseAddressArg(cmd.Context(), policyCheckTo)
+			to = &address
+		}
+		chainID := new(big.Int).SetUint64(policyCheckChainId)
+		if policyCheckChainId == 0 {
+			chainID = new(big.Int).SetUint64(nodeChainIdMap[globalOptNode])
+		}
+		value, _ := new(big.Int).SetString(policyCheckValue, 10) // validated in Args
+
+		rule, err := policy.Evaluate(ethutil.PolicyTx{ChainId: chainID, To: to, Value: value, Data: common.FromHex(policyCheckData), Time: time.Now()})
+		checkErr(err)
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", rule.Name)
+			return
+		}
+		fmt.Printf("allowed by rule %v, require confirmation: %v\n", rule.Name, rule.RequireConfirmation)
+	},
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var portfolioTokenList string
+var portfolioNoTokens bool
+var portfolioTransferBlocks uint64
+var portfolioTransfers int
+var portfolioLogsRange uint64
+
+func init() {
+	portfolioCmd.Flags().StringVarP(&portfolioTokenList, "token-list", "", ethutil.DefaultTokenListUrl, "the token list (https://tokenlists.org format), url or file")
+	portfolioCmd.Flags().BoolVarP(&portfolioNoTokens, "no-tokens", "", false, "do not scan token balances")
+	portfolioCmd.Flags().Uint64VarP(&portfolioTransferBlocks, "transfer-blocks", "", 10000, "search token transfers in this number of recent blocks, 0 means do not search")
+	portfolioCmd.Flags().IntVarP(&portfolioTransfers, "transfers", "", 10, "the max number of recent token transfers to show")
+	portfolioCmd.Flags().Uint64VarP(&portfolioLogsRange, "logs-range", "", 2000, "the max block range of each eth_getLogs request, many providers limit it")
+
+	addFlags(portfolioCmd, blockFlags)
+	addFlags(portfolioCmd, fiatFlags)
+}
+
+// portfolioTokenAmount returns value of token in its decimals and its symbol, or raw value and token address if token
+// is not in token list.
+func portfolioTokenAmount(tokens map[common.Address]ethutil.TokenListToken, token common.Address, value decimal.Decimal) (decimal.Decimal, string) {
+	if t, ok := tokens[token]; ok {
+		return value.Shift(-int32(t.Decimals)), t.Symbol
+	}
+	return value, token.Hex()
+}
+
+var portfolioCmd = &cobra.Command{
+	Use:   "portfolio address",
+	Short: "Summarize address: balance, nonce and nonce gap, token balances and recent token transfers",
+	Long: "Summarize address: balance, nonce, pending txs and nonce gap (missing nonces before queued txs, found by " +
+		"txpool_contentFrom if node supports it), non-zero balances of tokens in token list (see erc20 scan), and " +
+		"recent ERC-20 transfers from or to address found by eth_getLogs.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("address is required")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if portfolioLogsRange == 0 {
+			return fmt.Errorf("--logs-range must be greater than 0")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		address := mustParseAddressArg(ctx, args[0])
+		block := stateBlock(ctx)
+		var result = map[string]any{jsonlKeyAddress: address.Hex()}
+
+		balance, err := globalClient.EthClient.BalanceAt(ctx, address, block)
+		checkErr(err)
+		result["balance_wei"] = balance.String()
+		if value, ok := fiatValue(ctx, balance); ok {
+			result["balance_"+strings.ToLower(globalOptShowFiat)] = value.StringFixed(2)
+		}
+
+		gap, err := ethutil.DetectNonceGap(ctx, globalClient, address)
+		checkErr(err)
+		result["nonce"] = gap.Latest
+		result["pending_nonce"] = gap.Pending
+		result["queued_nonces"] = gap.Queued
+		result["missing_nonces"] = gap.Missing
+
+		var tokens = make(map[common.Address]ethutil.TokenListToken)
+		var tokenBalances []ethutil.TokenBalance
+		if !portfolioNoTokens {
+			list, err := erc20ScanTokens(ctx, portfolioTokenList)
+			if err != nil {
+				log.Printf("WARNING: load token list fail: %v", err)
+			} else {
+				for _, token := range list {
+					tokens[token.Address] = token
+				}
+				tokenBalances, err = erc20ScanBalances(ctx, address, list, block)
+				if err != nil {
+					log.Printf("WARNING: scan token balances fail: %v", err)
+				}
+			}
+		}
+		var tokenLines []map[string]any
+		for _, b := range tokenBalances {
+			tokenLines = append(tokenLines, map[string]any{
+				"token":    b.Token.Address.Hex(),
+				"symbol":   b.Token.Symbol,
+				"decimals": b.Token.Decimals,
+				"balance":  bigInt2Decimal(b.Balance).Shift(-int32(b.Token.Decimals)).String(),
+			})
+		}
+		if !portfolioNoTokens {
+			result["tokens"] = tokenLines
+		}
+
+		var transfers []ethutil.TokenTransfer
+		if portfolioTransferBlocks > 0 {
+			latest, err := globalClient.EthClient.BlockNumber(ctx)
+			checkErr(err)
+			var from uint64
+			if latest+1 > portfolioTransferBlocks {
+				from = latest + 1 - portfolioTransferBlocks
+			}
+			transfers, err = ethutil.RecentTokenTransfers(ctx, globalClient.EthClient, address, from, latest, portfolioLogsRange, portfolioTransfers)
+			if err != nil {
+				log.Printf("WARNING: search token transfers fail: %v", err)
+			}
+		}
+		var transferLines []map[string]any
+		for _, t := range transfers {
+			transferLines = append(transferLines, map[string]any{
+				"block":   t.Block,
+				"tx":      t.TxHash.Hex(),
+				"token":   t.Token.Hex(),
+				"from":    t.From.Hex(),
+				"to":      t.To.Hex(),
+				"value":   t.Value.String(),
+				"outflow": t.From == address,
+			})
+		}
+		if portfolioTransferBlocks > 0 {
+			result["transfers"] = transferLines
+		}
+
+		if printJSONL(result) {
+			return
+		}
+
+		fmt.Printf("address: %v\n", address.Hex())
+		fmt.Printf("balance: %v ether%v\n", wei2Other(bigInt2Decimal(balance), unitEther), fiatSuffix(ctx, balance))
+		fmt.Printf("nonce: %v\n", gap.Latest)
+		if gap.Pending > gap.Latest {
+			fmt.Printf("pending txs: %v (nonce %v to %v)\n", gap.Pending-gap.Latest, gap.Latest, gap.Pending-1)
+		}
+		if len(gap.Missing) > 0 {
+			fmt.Printf("nonce gap: queued txs of nonce %v are stuck until txs of nonce %v are sent\n",
+				joinUint64s(gap.Queued), joinUint64s(gap.Missing))
+		}
+
+		if !portfolioNoTokens {
+			fmt.Printf("tokens: %v\n", len(tokenBalances))
+			for _, b := range tokenBalances {
+				fmt.Printf("  %-10v %v %v\n", b.Token.Symbol, b.Token.Address.Hex(), bigInt2Decimal(b.Balance).Shift(-int32(b.Token.Decimals)))
+			}
+		}
+
+		if portfolioTransferBlocks > 0 {
+			fmt.Printf("recent token transfers (in last %v blocks): %v\n", portfolioTransferBlocks, len(transfers))
+			for _, t := range transfers {
+				amount, symbol := portfolioTokenAmount(tokens, t.Token, bigInt2Decimal(t.Value))
+				if t.From == address {
+					fmt.Printf("  block %v %v: sent %v %v to %v\n", t.Block, t.TxHash.Hex(), amount, symbol, t.To.Hex())
+				} else {
+					fmt.Printf("  block %v %v: received %v %v from %v\n", t.Block, t.TxHash.Hex(), amount, symbol, t.From.Hex())
+				}
+			}
+		}
+	},
+}
+
+// joinUint64s joins numbers by comma, e.g. "5, 6, 7"
+func joinUint64s(numbers []uint64) string {
+	var s []string
+	for _, n := range numbers {
+		s = append(s, fmt.Sprint(n))
+	}
+	return strings.Join(s, ", ")
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const priceSourceCoinGecko = "coingecko"
+const priceSourceChainlink = "chainlink"
+const priceSourceDefiLlama = "defillama"
+
+// defaultPriceCacheTTL is the default time to live of cached prices
+const defaultPriceCacheTTL = 5 * time.Minute
+
+// nodeCoinIdMap maps network to the CoinGecko coin id of its native coin, testnets are absent as their coins are
+// worthless.
+var nodeCoinIdMap = map[string]string{
+	nodeMainnet: "ethereum",
+	nodeBsc:     "binancecoin",
+	nodeHeco:    "huobi-token",
+}
+
+var priceAt string
+var priceBlock int64
+
+func init() {
+	priceCmd.Flags().StringVarP(&priceAt, "at", "", "", "the time of historical price, RFC3339 (e.g. 2023-05-01T08:30:00Z) or date (e.g. 2023-05-01, in UTC)")
+	priceCmd.Flags().Int64VarP(&priceBlock, "block", "", -1, "the block whose timestamp is the time of historical price")
+
+	addFlags(priceCmd, fiatFlags)
+}
+
+// globalPriceOracle is created on first use by fiatValue
+var globalPriceOracle ethutil.PriceOracle
+
+// defaultPriceCacheFile returns ~/.ethutil/price_cache.json
+func defaultPriceCacheFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ethutil", "price_cache.json")
+}
+
+// newPriceOracle builds price oracle according to --price-source, prices are cached in ~/.ethutil/price_cache.json.
+func newPriceOracle(ctx context.Context) (ethutil.PriceOracle, error) {
+	var oracle ethutil.PriceOracle
+	switch globalOptPriceSource {
+	case priceSourceCoinGecko:
+		oracle = &ethutil.CoinGeckoPriceOracle{ApiKey: globalCoinGeckoApiKey}
+	case priceSourceChainlink:
+		// chainlink feeds are read from mainnet regardless of current network
+		client := globalClient
+		if globalOptNode != nodeMainnet || client == nil {
+			var err error
+			client, err = ethutil.Dial(ctx, nodeUrlMap[nodeMainnet])
+			if err != nil {
+				return nil, fmt.Errorf("connect mainnet for chainlink feeds fail: %w", err)
+			}
+		}
+		oracle = &ethutil.ChainlinkPriceOracle{Client: client.EthClient}
+	case priceSourceDefiLlama:
+		oracle = &ethutil.DefiLlamaPriceOracle{}
+	default:
+		return nil, fmt.Errorf("invalid option for --price-source: %v", globalOptPriceSource)
+	}
+	return &ethutil.CachedPriceOracle{
+		Oracle:    oracle,
+		CacheFile: defaultPriceCacheFile(),
+		TTL:       globalPriceCacheTTL,
+	}, nil
+}
+
+// historicalPriceOracle returns the oracle of --price-source for historical prices, chainlink is not supported.
+func historicalPriceOracle(ctx context.Context) (*ethutil.CachedPriceOracle, error) {
+	if globalOptPriceSource == priceSourceChainlink {
+		return nil, fmt.Errorf("historical price is not supported by %v, use --price-source coingecko or defillama", priceSourceChainlink)
+	}
+	if globalPriceOracle == nil {
+		oracle, err := newPriceOracle(ctx)
+		if err != nil {
+			return nil, err
+		}
+		globalPriceOracle = oracle
+	}
+	return globalPriceOracle.(*ethutil.CachedPriceOracle), nil
+}
+
+// fiatValueAt is same as fiatValue, but uses the price at time t, e.g. the value at transaction time.
+func fiatValueAt(ctx context.Context, amountInWei *big.Int, t time.Time) (value decimal.Decimal, ok bool) {
+	if globalOptShowFiat == "" {
+		return decimal.Zero, false
+	}
+	coin, found := nodeCoinIdMap[globalOptNode]
+	if !found {
+		log.Printf("fiat value is unavailable for network %v", globalOptNode)
+		return decimal.Zero, false
+	}
+
+	oracle, err := historicalPriceOracle(ctx)
+	if err != nil {
+		log.Printf("create price oracle fail: %v", err)
+		return decimal.Zero, false
+	}
+	price, err := oracle.PriceAt(ctx, coin, globalOptShowFiat, t)
+	if err != nil {
+		log.Printf("get price of %v in %v at %v fail: %v", coin, globalOptShowFiat, t.UTC().Format(time.RFC3339), err)
+		return decimal.Zero, false
+	}
+	return wei2Other(bigInt2Decimal(amountInWei), unitEther).Mul(price).Round(2), true
+}
+
+// fiatValue returns the value of amountInWei native coin in --show-fiat currency. ok is false if --show-fiat is not
+// specified or price is unavailable, the reason of later is logged.
+func fiatValue(ctx context.Context, amountInWei *big.Int) (value decimal.Decimal, ok bool) {
+	if globalOptShowFiat == "" {
+		return decimal.Zero, false
+	}
+	coin, found := nodeCoinIdMap[globalOptNode]
+	if !found {
+		log.Printf("fiat value is unavailable for network %v", globalOptNode)
+		return decimal.Zero, false
+	}
+
+	if globalPriceOracle == nil {
+		oracle, err := newPriceOracle(ctx)
+		if err != nil {
+			log.Printf("create price oracle fail: %v", err)
+			return decimal.Zero, false
+		}
+		globalPriceOracle = oracle
+	}
+	price, err := globalPriceOracle.Price(ctx, coin, globalOptShowFiat)
+	if err != nil {
+		log.Printf("get price of %v in %v fail: %v", coin, globalOptShowFiat, err)
+		return decimal.Zero, false
+	}
+	return wei2Other(bigInt2Decimal(amountInWei), unitEther).Mul(price).Round(2), true
+}
+
+// fiatSuffix returns " (12.34 USD)" for amountInWei if --show-fiat is specified, otherwise returns empty string.
+func fiatSuffix(ctx context.Context, amountInWei *big.Int) string {
+	if value, ok := fiatValue(ctx, amountInWei); ok {
+		return fmt.Sprintf(" (%v %v)", value.StringFixed(2), strings.ToUpper(globalOptShowFiat))
+	}
+	return ""
+}
+
+// parsePriceTime parses RFC3339 time or date in UTC.
+func parsePriceTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+var priceCmd = &cobra.Command{
+	Use:   "price [coin]",
+	Short: "Show current or historical price of coin (CoinGecko coin id, default is native coin of --node) in --show-fiat currency (default USD)",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("too many args")
+		}
+		if priceAt != "" && priceBlock >= 0 {
+			return fmt.Errorf("--at and --block can not be specified at the same time")
+		}
+		if priceAt != "" {
+			if _, err := parsePriceTime(priceAt); err != nil {
+				return fmt.Errorf("invalid --at %v", priceAt)
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptShowFiat == "" {
+			globalOptShowFiat = "usd"
+		}
+		coin := nodeCoinIdMap[globalOptNode]
+		if len(args) == 1 {
+			coin = args[0]
+		}
+		if coin == "" {
+			log.Fatalf("network %v has no priced native coin, please specify coin", globalOptNode)
+		}
+
+		var at time.Time
+		if priceAt != "" {
+			at, _ = parsePriceTime(priceAt) // validated in Args
+		}
+		if priceBlock >= 0 {
+			log.Printf("Current network is %v", globalOptNode)
+
+			InitGlobalClient(ctx, globalOptNodeUrl)
+			header, err := globalClient.EthClient.HeaderByNumber(ctx, big.NewInt(priceBlock))
+			checkErr(err)
+			at = time.Unix(int64(header.Time), 0)
+		}
+
+		var price decimal.Decimal
+		if at.IsZero() {
+			oracle, err := newPriceOracle(ctx)
+			checkErr(err)
+			price, err = oracle.Price(ctx, coin, globalOptShowFiat)
+			checkErr(err)
+		} else {
+			oracle, err := historicalPriceOracle(ctx)
+			checkErr(err)
+			price, err = oracle.PriceAt(ctx, coin, globalOptShowFiat, at)
+			checkErr(err)
+		}
+
+		if printFormatted(&PriceResult{Coin: coin, Currency: strings.ToUpper(globalOptShowFiat), Price: price, Time: at}) {
+			return
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", price)
+			return
+		}
+		var when = "now"
+		if !at.IsZero() {
+			when = at.UTC().Format(time.RFC3339)
+		}
+		fmt.Printf("%v %v %v (%v)\n", coin, price, strings.ToUpper(globalOptShowFiat), when)
+	},
+}
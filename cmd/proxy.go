@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var proxyCompareBlock int64
+
+func init() {
+	proxyCmd.Flags().Int64VarP(&proxyCompareBlock, "compare-block", "", -1, "also detect proxy at this block, and diff implementation bytecode of this block and latest block")
+}
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy address",
+	Short: "Detect proxy pattern (EIP-1967, EIP-1822, beacon, EIP-1167 minimal proxy), show implementation and admin",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+		client := globalClient.EthClient
+		address := mustParseAddressArg(ctx, args[0])
+
+		info, err := ethutil.DetectProxy(ctx, client, address, nil)
+		checkErr(err)
+		if globalOptTerseOutput {
+			fmt.Printf("%v %v\n", info.Kind, info.Implementation.Hex())
+		} else {
+			printProxyInfo(info)
+		}
+		if info.Kind == ethutil.ProxyKindNone || proxyCompareBlock < 0 {
+			return
+		}
+
+		oldInfo, err := ethutil.DetectProxy(ctx, client, address, big.NewInt(proxyCompareBlock))
+		checkErr(err)
+		if oldInfo.Kind == ethutil.ProxyKindNone {
+			fmt.Printf("not a proxy at block %v\n", proxyCompareBlock)
+			return
+		}
+		if oldInfo.Implementation == info.Implementation {
+			fmt.Printf("implementation is not changed since block %v\n", proxyCompareBlock)
+			return
+		}
+
+		fmt.Printf("implementation at block %v: %v\n", proxyCompareBlock, oldInfo.Implementation.Hex())
+		oldCode, err := client.CodeAt(ctx, oldInfo.Implementation, big.NewInt(proxyCompareBlock))
+		checkErr(err)
+		newCode, err := client.CodeAt(ctx, info.Implementation, nil)
+		checkErr(err)
+		fmt.Printf("old implementation code: %v bytes, hash %v\n", len(oldCode), crypto.Keccak256Hash(oldCode).Hex())
+		fmt.Printf("new implementation code: %v bytes, hash %v\n", len(newCode), crypto.Keccak256Hash(newCode).Hex())
+		prefix, suffix := commonPrefixSuffix(oldCode, newCode)
+		if prefix == len(oldCode) && prefix == len(newCode) {
+			fmt.Printf("implementation code is identical\n")
+		} else {
+			fmt.Printf("implementation code differs in [%v, %v) of old and [%v, %v) of new\n",
+				prefix, len(oldCode)-suffix, prefix, len(newCode)-suffix)
+		}
+	},
+}
+
+func printProxyInfo(info *ethutil.ProxyInfo) {
+	fmt.Printf("proxy pattern: %v\n", info.Kind)
+	if info.Kind == ethutil.ProxyKindNone {
+		return
+	}
+	fmt.Printf("implementation: %v\n", info.Implementation.Hex())
+	if info.Admin != (common.Address{}) {
+		fmt.Printf("admin: %v\n", info.Admin.Hex())
+	}
+	if info.Beacon != (common.Address{}) {
+		fmt.Printf("beacon: %v\n", info.Beacon.Hex())
+	}
+}
+
+// commonPrefixSuffix returns the length of common prefix and common suffix (not overlapping the prefix) of a and b.
+func commonPrefixSuffix(a, b []byte) (prefix int, suffix int) {
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return prefix, suffix
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	pubkeyCmd.AddCommand(pubkeyFromPrivateKeyCmd)
+	pubkeyCmd.AddCommand(pubkeyToAddressCmd)
+	pubkeyCmd.AddCommand(pubkeyCompressCmd)
+	pubkeyCmd.AddCommand(pubkeyDecompressCmd)
+}
+
+// validateHexArgs returns error if any arg is not hex string.
+func validateHexArgs(name string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires %v", name)
+		}
+		for _, arg := range args {
+			if !isValidHexString(arg) {
+				return fmt.Errorf("%v %v is not hex string", name, arg)
+			}
+		}
+		return nil
+	}
+}
+
+// mustParsePublicKey parses compressed, uncompressed or raw (64 bytes) public key.
+func mustParsePublicKey(pubkey string) *ecdsa.PublicKey {
+	key, err := ethutil.ParsePublicKey(common.FromHex(pubkey))
+	checkErr(err)
+	return key
+}
+
+var pubkeyCmd = &cobra.Command{
+	Use:   "pubkey",
+	Short: "Public key utilities: derive from private key, convert to address, compress and decompress",
+}
+
+var pubkeyFromPrivateKeyCmd = &cobra.Command{
+	Use:   "from-private-key private-key ...",
+	Short: "Derive uncompressed public key, compressed public key and EIP-55 address from private key",
+	Args:  validateHexArgs("private-key"),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			privateKey := buildPrivateKeyFromHex(arg)
+			uncompressed := hexutil.Encode(crypto.FromECDSAPub(&privateKey.PublicKey))
+			compressed := hexutil.Encode(crypto.CompressPubkey(&privateKey.PublicKey))
+			address := extractAddressFromPrivateKey(privateKey).Hex()
+			if globalOptTerseOutput {
+				fmt.Printf("%v %v %v\n", uncompressed, compressed, address)
+				continue
+			}
+			fmt.Printf("public key %v, compressed public key %v, addr %v\n", uncompressed, compressed, address)
+		}
+	},
+}
+
+var pubkeyToAddressCmd = &cobra.Command{
+	Use:   "to-address public-key ...",
+	Short: "Compute EIP-55 address from public key (compressed, uncompressed or 64 bytes without 0x04 prefix)",
+	Args:  validateHexArgs("public-key"),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Printf("%v\n", crypto.PubkeyToAddress(*mustParsePublicKey(arg)).Hex())
+		}
+	},
+}
+
+var pubkeyCompressCmd = &cobra.Command{
+	Use:   "compress public-key ...",
+	Short: "Convert public key to compressed form (33 bytes)",
+	Args:  validateHexArgs("public-key"),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Printf("%v\n", hexutil.Encode(crypto.CompressPubkey(mustParsePublicKey(arg))))
+		}
+	},
+}
+
+var pubkeyDecompressCmd = &cobra.Command{
+	Use:   "decompress public-key ...",
+	Short: "Convert public key to uncompressed form (65 bytes, starts with 0x04)",
+	Args:  validateHexArgs("public-key"),
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Printf("%v\n", hexutil.Encode(crypto.FromECDSAPub(mustParsePublicKey(arg))))
+		}
+	},
+}
@@ -1,13 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"strings"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -20,6 +20,9 @@ var queryHexData string
 func init() {
 	queryCmd.Flags().StringVarP(&queryCmdABIFile, "abi-file", "", "", "the path of abi file, if this option specified, 'function definition' can be just function name")
 	queryCmd.Flags().StringVarP(&queryHexData, "hex-data", "", "", "the input hex data")
+
+	addFlags(queryCmd, blockFlags)
+	addFlags(queryCmd, explorerFlags)
 }
 
 var queryCmd = &cobra.Command{
@@ -32,19 +35,19 @@ var queryCmd = &cobra.Command{
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		if !validationQueryCmdOpts(args) {
+		if !validationQueryCmdOpts(cmd.Context(), args) {
 			_ = cmd.Help()
 			os.Exit(1)
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-		contractAddr := args[0]
+		contractAddr := hexAddressArg(cmd.Context(), args[0])
 
 		if !globalOptDryRun {
 			// don't check contract address if --dry-run specified
-			isContract, err := isContractAddress(globalClient.EthClient, common.HexToAddress(contractAddr))
+			isContract, err := ethutil.IsContractAddress(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr))
 			if err != nil {
 				panic(err)
 			}
@@ -60,7 +63,7 @@ var queryCmd = &cobra.Command{
 			}
 			txInputData, err := hex.DecodeString(queryHexData)
 			checkErr(err)
-			output, err := Call(globalClient.EthClient, common.HexToAddress(contractAddr), txInputData)
+			output, err := ethutil.Call(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr), txInputData, stateBlock(cmd.Context()))
 			checkErr(err)
 
 			log.Printf("Output raw data\n%v\n", hex.EncodeToString(output))
@@ -76,25 +79,17 @@ var queryCmd = &cobra.Command{
 		funcSignature := args[1]
 		inputArgData := args[2:]
 
-		if queryCmdABIFile != "" {
-			abiContent, err := os.ReadFile(queryCmdABIFile)
-			if err != nil {
-				log.Fatal(err)
-			}
-			funcName := funcSignature
-			funcSignature, err = extractFuncDefinition(string(abiContent), extractFuncName(funcName))
-			checkErr(err)
-			// log.Printf("extract func definition from abi: %v", funcDefinition)
-		}
+		funcSignature, err := resolveFuncSignature(cmd.Context(), common.HexToAddress(contractAddr), funcSignature, queryCmdABIFile)
+		checkErr(err)
 
-		txInputData, err := buildTxInputData(funcSignature, inputArgData)
+		txInputData, err := ethutil.BuildTxInputData(funcSignature, inputArgData)
 		checkErr(err)
 
 		if globalOptShowInputData {
 			log.Printf("input data = %v", hexutil.Encode(txInputData))
 		}
 
-		output, err := Call(globalClient.EthClient, common.HexToAddress(contractAddr), txInputData)
+		output, err := ethutil.Call(cmd.Context(), globalClient.EthClient, common.HexToAddress(contractAddr), txInputData, stateBlock(cmd.Context()))
 		checkErr(err)
 
 		printContractReturnData(funcSignature, output)
@@ -103,7 +98,7 @@ var queryCmd = &cobra.Command{
 
 func printContractReturnData(funcDefinition string, output []byte) {
 	var v = make(map[string]interface{})
-	returnArgs, err := buildReturnArgs(funcDefinition)
+	returnArgs, err := ethutil.BuildReturnArgs(funcDefinition)
 	checkErr(err)
 
 	log.Printf("Output raw data\n%v\n", hex.EncodeToString(output))
@@ -148,70 +143,11 @@ func printContractReturnData(funcDefinition string, output []byte) {
 	// fmt.Printf("raw output:\n%s\n", hex.Dump(output))
 }
 
-func validationQueryCmdOpts(args []string) bool {
-	if !isValidEthAddress(args[0]) {
+func validationQueryCmdOpts(ctx context.Context, args []string) bool {
+	args[0] = resolveAbiName(args[0]) // contract can be a name added by abi add
+	if !isValidAddressArg(ctx, args[0]) {
 		log.Printf("%s is NOT a valid eth address", args[0])
 		return false
 	}
 	return true
 }
-
-// funcDefinition example: "function balanceOf(address _owner) public constant returns (uint balance)"
-func buildReturnArgs(funcDefinition string) (abi.Arguments, error) {
-	returnsLoc := strings.Index(funcDefinition, "returns")
-	if returnsLoc < 0 {
-		// return immediately if keyword `returns` no found in input
-		return nil, nil
-	}
-	partAfterReturns := funcDefinition[returnsLoc:]
-
-	leftParenthesisLoc := strings.Index(partAfterReturns, "(")
-	if leftParenthesisLoc < 0 {
-		return nil, fmt.Errorf("char ) is not found after keyword returns")
-	}
-	rightParenthesisLoc := strings.LastIndex(partAfterReturns, ")")
-	if rightParenthesisLoc < 0 {
-		return nil, fmt.Errorf("char ) is not found after keyword returns")
-	}
-
-	var theReturnTypes abi.Arguments
-
-	returnPart := partAfterReturns[leftParenthesisLoc+1 : rightParenthesisLoc]
-	returnList := splitData(returnPart)
-	for index, returnElem := range returnList {
-		theReturnName := "ret" + strconv.FormatInt(int64(index), 10) // default name ret0, ret1, etc
-
-		if strings.HasSuffix(returnElem, "]") { // array
-			typ, err := BuildTupleArrayType(returnElem)
-			if err != nil {
-				return nil, fmt.Errorf("BuildTupleArrayType fail: %w", err)
-			}
-			theReturnTypes = append(theReturnTypes, abi.Argument{Type: typ, Name: theReturnName})
-		} else {
-			log.Printf("returnElem = %v", returnElem)
-			fields := strings.Fields(returnElem)
-			if len(fields) == 0 {
-				return nil, fmt.Errorf("func definition `%v` invalid, type missing in returns", funcDefinition)
-			}
-
-			typ, err := abi.NewType(typeNormalize(fields[0]), "", nil)
-			if err != nil {
-				return nil, fmt.Errorf("abi.NewType fail: %w", err)
-			}
-
-			if len(fields) > 1 {
-				if fields[1] == "memory" || fields[1] == "calldata" {
-					// skip keyword "memory" and "calldata"
-					if len(fields) > 2 {
-						theReturnName = fields[2]
-					}
-				} else {
-					theReturnName = fields[1]
-				}
-			}
-			theReturnTypes = append(theReturnTypes, abi.Argument{Type: typ, Name: theReturnName})
-		}
-	}
-
-	return theReturnTypes, nil
-}
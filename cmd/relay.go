@@ -0,0 +1,424 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+const relayerGelato = "gelato"
+
+var relayValue string
+var relayUnit string
+var relayHexData string
+var relayNonce int64
+var relaySignature string
+var relayAuthorization string
+var relayChainId int64
+var relayAuthNonce int64
+var relayLedger string
+var relayGelatoApiKey string
+var relayGelatoUrl string
+var relayReportChainId uint64
+
+func init() {
+	for _, cmd := range []*cobra.Command{relaySignCmd, relaySendCmd, relayGelatoCmd} {
+		cmd.Flags().StringVarP(&relayValue, "value", "", "0", "the amount of eth sent by beneficiary account, unit is ether and can be changed by --unit")
+		cmd.Flags().StringVarP(&relayUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+		cmd.Flags().StringVarP(&relayHexData, "hex-data", "", "", "the payload hex data of the call")
+		cmd.Flags().Int64VarP(&relayNonce, "relay-nonce", "", -1, "the nonce() of delegated beneficiary account, -1 means query it online")
+	}
+	for _, cmd := range []*cobra.Command{relaySendCmd, relayGelatoCmd} {
+		cmd.Flags().StringVarP(&relaySignature, "signature", "", "", "the signature of the call created by beneficiary with relay sign")
+		cmd.Flags().StringVarP(&relayLedger, "ledger", "", "", "the ledger file recording sponsored gas per beneficiary (default ~/.ethutil/sponsor-ledger.jsonl)")
+	}
+	relaySendCmd.Flags().StringVarP(&relayAuthorization, "authorization", "", "", "the EIP-7702 authorization (JSON created by relay authorize, or file of it) of beneficiary, "+
+		"if specified, the tx is sent as set code tx which delegates beneficiary account first")
+	relayAuthorizeCmd.Flags().Int64VarP(&relayChainId, "chain-id", "", -1, "the chain id of authorization, 0 means any chain, -1 means query it online")
+	relayAuthorizeCmd.Flags().Int64VarP(&relayAuthNonce, "auth-nonce", "", -1, "the nonce of beneficiary account, -1 means query it online")
+	relayGelatoCmd.Flags().StringVarP(&relayGelatoApiKey, "gelato-api-key", "", "", "the sponsor api key of Gelato 1Balance, which pays the gas")
+	relayGelatoCmd.Flags().StringVarP(&relayGelatoUrl, "gelato-url", "", ethutil.GelatoRelayUrl, "the url of Gelato Relay")
+	relayReportCmd.Flags().Uint64VarP(&relayReportChainId, "chain-id", "", 0, "only report sponsored txs of this chain, 0 means all chains")
+	relayReportCmd.Flags().StringVarP(&relayLedger, "ledger", "", "", "the ledger file recording sponsored gas per beneficiary (default ~/.ethutil/sponsor-ledger.jsonl)")
+
+	relayCmd.AddCommand(relayAuthorizeCmd)
+	relayCmd.AddCommand(relaySignCmd)
+	relayCmd.AddCommand(relaySendCmd)
+	relayCmd.AddCommand(relayGelatoCmd)
+	relayCmd.AddCommand(relayReportCmd)
+
+	addFlags(relayCmd, txFlags)
+}
+
+var relayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "Sponsor gas of other accounts by EIP-7702 delegation or Gelato Relay, with accounting per beneficiary",
+	Long: "Sponsor gas of other accounts (beneficiaries) by EIP-7702 delegation or Gelato Relay, with accounting per " +
+		"beneficiary. The beneficiary account is delegated to a contract compatible with BatchCallAndSponsor " +
+		"(execute((address,uint256,bytes)[],bytes) checking the personal signature of keccak256(nonce, calls) by the account), " +
+		"the beneficiary signs the authorization and the call offline, and the sponsor (--private-key) pays the gas.",
+}
+
+// validateRelayCallArgs checks args are n addresses, and flags of the call are valid.
+func validateRelayCallArgs(n int, names string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("requires %v", names)
+		}
+		for i := range args {
+			if !isValidAddressArg(cmd.Context(), args[i]) {
+				return fmt.Errorf("%v is not a valid eth address", args[i])
+			}
+		}
+		if !isValidHexString(relayHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if _, err := decimal.NewFromString(relayValue); err != nil {
+			return fmt.Errorf("%v is not a valid amount", relayValue)
+		}
+		if relaySignature != "" && (!isValidHexString(relaySignature) || len(common.FromHex(relaySignature)) != 65) {
+			return fmt.Errorf("--signature must be 65 bytes hex string")
+		}
+		return nil
+	}
+}
+
+// relayCalls returns the call of flags and the nonce of beneficiary account, which is queried online if --relay-nonce
+// is not specified.
+func relayCalls(ctx context.Context, beneficiary common.Address, to common.Address) ([]ethutil.SponsorCall, *big.Int) {
+	calls := []ethutil.SponsorCall{{
+		To:    to,
+		Value: unify2Wei(decimal.RequireFromString(relayValue), relayUnit).BigInt(),
+		Data:  common.FromHex(relayHexData),
+	}}
+	if relayNonce >= 0 {
+		return calls, big.NewInt(relayNonce)
+	}
+	if globalClient == nil {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+	}
+	nonce, err := ethutil.SponsorNonce(ctx, globalClient.EthClient, beneficiary)
+	checkErr(err)
+	return calls, nonce
+}
+
+// relayPolicyChainId returns --chain-id, or chain id of current network if it's not specified.
+func relayPolicyChainId(ctx context.Context) *big.Int {
+	if relayChainId >= 0 {
+		return big.NewInt(relayChainId)
+	}
+	if globalClient == nil {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+	}
+	chainID, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	return chainID
+}
+
+// relayExecuteData checks --signature is signed by beneficiary, and builds input data of execute of beneficiary account.
+func relayExecuteData(ctx context.Context, beneficiary common.Address, to common.Address) []byte {
+	if relaySignature == "" {
+		log.Fatalf("--signature is required, which is created by beneficiary with relay sign")
+	}
+	calls, nonce := relayCalls(ctx, beneficiary, to)
+	signature := common.FromHex(relaySignature)
+	signer, err := ethutil.SponsorCallsSigner(nonce, calls, signature)
+	checkErr(err)
+	if signer != beneficiary {
+		log.Fatalf("--signature is signed by %v with nonce %v, not beneficiary %v", signer.Hex(), nonce, beneficiary.Hex())
+	}
+	data, err := ethutil.SponsorExecuteData(calls, signature)
+	checkErr(err)
+	return data
+}
+
+// relayLedgerFile returns --ledger, default is ~/.ethutil/sponsor-ledger.jsonl
+func relayLedgerFile() string {
+	if relayLedger != "" {
+		return relayLedger
+	}
+	home, err := os.UserHomeDir()
+	checkErr(err)
+	return filepath.Join(home, ".ethutil", "sponsor-ledger.jsonl")
+}
+
+// recordSponsoredTx appends the sponsored gas of tx to the ledger.
+func recordSponsoredTx(ctx context.Context, beneficiary common.Address, relayer string, receipt *types.Receipt) {
+	chainID, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	file := relayLedgerFile()
+	checkErr(os.MkdirAll(filepath.Dir(file), 0700))
+	checkErr(ethutil.AppendSponsorRecord(file, ethutil.SponsorRecord{
+		Time:        time.Now().UTC(),
+		ChainID:     chainID.Uint64(),
+		Beneficiary: beneficiary,
+		Relayer:     relayer,
+		TxHash:      receipt.TxHash,
+		GasUsed:     receipt.GasUsed,
+		Fee:         (*hexutil.Big)(fee),
+	}))
+	log.Printf("sponsored %v gas (%v ether) for %v, recorded in %v", receipt.GasUsed, wei2Other(bigInt2Decimal(fee), unitEther), beneficiary.Hex(), file)
+}
+
+// readSetCodeAuthorization reads authorization from JSON or file of it.
+func readSetCodeAuthorization(s string) (*ethutil.SetCodeAuthorization, error) {
+	content := []byte(s)
+	if !strings.HasPrefix(strings.TrimSpace(s), "{") {
+		var err error
+		if content, err = os.ReadFile(s); err != nil {
+			return nil, err
+		}
+	}
+	var auth ethutil.SetCodeAuthorization
+	if err := json.Unmarshal(content, &auth); err != nil {
+		return nil, fmt.Errorf("parse authorization fail: %w", err)
+	}
+	return &auth, nil
+}
+
+var relayAuthorizeCmd = &cobra.Command{
+	Use:   "authorize delegate-address",
+	Short: "Sign EIP-7702 authorization delegating --private-key (beneficiary) account to delegate contract, no node is needed if --chain-id and --auth-nonce are specified",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires delegate-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for %v command", cmd.Name())
+		}
+		ctx := cmd.Context()
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		if relayChainId < 0 || relayAuthNonce < 0 {
+			log.Printf("Current network is %v", globalOptNode)
+			InitGlobalClient(ctx, globalOptNodeUrl)
+		}
+
+		chainID := big.NewInt(relayChainId)
+		if relayChainId < 0 {
+			var err error
+			chainID, err = globalClient.EthClient.ChainID(ctx)
+			checkErr(err)
+		}
+		nonce := uint64(relayAuthNonce)
+		if relayAuthNonce < 0 {
+			var err error
+			nonce, err = globalClient.EthClient.PendingNonceAt(ctx, extractAddressFromPrivateKey(privateKey))
+			checkErr(err)
+		}
+
+		checkNoPolicy("an EIP-7702 authorization")
+		checkTOTP()
+		auth, err := ethutil.SignSetCodeAuthorization(chainID, mustParseAddressArg(ctx, args[0]), nonce, privateKey)
+		checkErr(err)
+		content, err := json.Marshal(auth)
+		checkErr(err)
+		fmt.Printf("%s\n", content)
+	},
+}
+
+var relaySignCmd = &cobra.Command{
+	Use:   "sign to-address",
+	Short: "Sign the call of to-address by --private-key (beneficiary), which is executed by its delegated account with gas paid by sponsor",
+	Args:  validateRelayCallArgs(1, "to-address"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for %v command", cmd.Name())
+		}
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		calls, nonce := relayCalls(cmd.Context(), extractAddressFromPrivateKey(privateKey), mustParseAddressArg(cmd.Context(), args[0]))
+		if globalOptPolicy != "" {
+			chainID := relayPolicyChainId(cmd.Context())
+			for _, call := range calls {
+				checkPolicy(cmd.Context(), nil, chainID, &call.To, call.Value, call.Data)
+			}
+		}
+		checkTOTP()
+		signature, err := ethutil.SponsorSignCalls(nonce, calls, privateKey)
+		checkErr(err)
+		if !globalOptTerseOutput {
+			log.Printf("signed by %v with nonce %v", extractAddressFromPrivateKey(privateKey).Hex(), nonce)
+		}
+		fmt.Printf("%v\n", hexutil.Encode(signature))
+	},
+}
+
+var relaySendCmd = &cobra.Command{
+	Use:   "send beneficiary to-address",
+	Short: "Send the call signed by beneficiary from its delegated account, the gas is paid by --private-key (sponsor)",
+	Args:  validateRelayCallArgs(2, "beneficiary and to-address"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for %v command", cmd.Name())
+		}
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		beneficiary := mustParseAddressArg(ctx, args[0])
+		data := relayExecuteData(ctx, beneficiary, mustParseAddressArg(ctx, args[1]))
+		if globalOptShowInputData {
+			log.Printf("input data: 0x%x", data)
+		}
+
+		var txHash common.Hash
+		if relayAuthorization == "" {
+			tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &beneficiary, big.NewInt(0), nil, data)
+			checkErr(err)
+			log.Printf("transaction %s finished", tx)
+			txHash = common.HexToHash(tx)
+		} else {
+			auth, err := readSetCodeAuthorization(relayAuthorization)
+			checkErr(err)
+			authority, err := auth.Authority()
+			checkErr(err)
+			if authority != beneficiary {
+				log.Fatalf("--authorization is signed by %v, not beneficiary %v", authority.Hex(), beneficiary.Hex())
+			}
+			txHash = sendSetCodeTx(ctx, beneficiary, data, *auth)
+		}
+		if globalOptDryRun || transferNotCheck {
+			return
+		}
+
+		receipt, err := globalClient.EthClient.TransactionReceipt(ctx, txHash)
+		checkErr(err)
+		recordSponsoredTx(ctx, beneficiary, extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey)).Hex(), receipt)
+	},
+}
+
+// sendSetCodeTx sends EIP-7702 set code tx calling to with data and authorization from --private-key, and waits for
+// its receipt.
+func sendSetCodeTx(ctx context.Context, to common.Address, data []byte, auth ethutil.SetCodeAuthorization) common.Hash {
+	if len(globalOptApprovers) > 0 {
+		log.Fatalf("approval of set code tx is not supported, --approvers can not be used")
+	}
+	privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+	checkWeakPrivateKey(privateKey)
+	sponsor := extractAddressFromPrivateKey(privateKey)
+
+	chainID, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	opts := buildTxOptions(nil)
+	opts.TxType = txTypeEip1559
+	opts.ChainID = chainID
+	opts.GasOracle = newGasOracle(globalClient.EthClient)
+	eip1559Tx, err := ethutil.BuildTx(ctx, globalClient.EthClient, sponsor, &to, big.NewInt(0), data, opts)
+	checkErr(err)
+	tx, err := ethutil.NewSetCodeTx(eip1559Tx, chainID, []ethutil.SetCodeAuthorization{auth})
+	checkErr(err)
+
+	checkPolicy(ctx, globalClient.EthClient, nil, &to, big.NewInt(0), data)
+	checkTOTP()
+	rawTx, txHash, err := tx.Sign(privateKey)
+	checkErr(err)
+	if globalOptShowRawTx || globalOptDryRun {
+		log.Printf("raw tx = %v", hexutil.Encode(rawTx))
+	}
+	if globalOptDryRun {
+		fmt.Printf("%v\n", txHash.Hex())
+		return txHash
+	}
+
+	var result common.Hash
+	checkErr(globalClient.RpcClient.CallContext(ctx, &result, "eth_sendRawTransaction", hexutil.Encode(rawTx)))
+	if transferNotCheck {
+		fmt.Printf("%v\n", result.Hex())
+		return result
+	}
+	receipt, err := ethutil.WaitReceipt(ctx, globalClient, result, waitOptions())
+	checkErr(err)
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("tx %v minted, but status is failed, please check it in block explorer", result.Hex())
+	}
+	log.Printf("transaction %s finished", result.Hex())
+	return result
+}
+
+var relayGelatoCmd = &cobra.Command{
+	Use:   "gelato beneficiary to-address",
+	Short: "Submit the call signed by beneficiary to Gelato Relay, the gas is paid by Gelato 1Balance of --gelato-api-key",
+	Long: "Submit the call signed by beneficiary to Gelato Relay (sponsoredCall), the gas is paid by Gelato 1Balance " +
+		"of --gelato-api-key. The beneficiary account must be delegated already (e.g. by relay send --authorization), " +
+		"as authorization can not be submitted to Gelato.",
+	Args: validateRelayCallArgs(2, "beneficiary and to-address"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if relayGelatoApiKey == "" {
+			log.Fatalf("--gelato-api-key is required for %v command", cmd.Name())
+		}
+		log.Printf("Current network is %v", globalOptNode)
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		beneficiary := mustParseAddressArg(ctx, args[0])
+		code, err := globalClient.EthClient.CodeAt(ctx, beneficiary, nil)
+		checkErr(err)
+		if len(code) == 0 {
+			log.Fatalf("beneficiary %v is not delegated, send the first call by relay send --authorization", beneficiary.Hex())
+		}
+		data := relayExecuteData(ctx, beneficiary, mustParseAddressArg(ctx, args[1]))
+		chainID, err := globalClient.EthClient.ChainID(ctx)
+		checkErr(err)
+		if globalOptDryRun {
+			log.Printf("input data: 0x%x", data)
+			return
+		}
+
+		taskId, err := ethutil.GelatoSponsoredCall(ctx, relayGelatoUrl, relayGelatoApiKey, chainID, beneficiary, data)
+		checkErr(err)
+		log.Printf("gelato task %v is submitted", taskId)
+		txHash, err := ethutil.WaitGelatoTask(ctx, relayGelatoUrl, taskId, globalOptPollInterval)
+		checkErr(err)
+		log.Printf("transaction %s finished", txHash.Hex())
+
+		receipt, err := globalClient.EthClient.TransactionReceipt(ctx, txHash)
+		checkErr(err)
+		recordSponsoredTx(ctx, beneficiary, relayerGelato, receipt)
+	},
+}
+
+var relayReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show sponsored txs, gas and fee per beneficiary recorded in the ledger",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := ethutil.ReadSponsorRecords(relayLedgerFile())
+		checkErr(err)
+		summaries := ethutil.SummarizeSponsorRecords(records, relayReportChainId)
+		if !globalOptTerseOutput && !globalOptJsonl {
+			fmt.Printf("%-42v %6v %14v %v\n", "beneficiary", "txs", "gas used", "fee (ether)")
+		}
+		var total = new(big.Int)
+		for _, summary := range summaries {
+			total.Add(total, summary.Fee)
+			if printJSONL(map[string]any{jsonlKeyAddress: summary.Beneficiary.Hex(), "txs": summary.Txs, "gas_used": summary.GasUsed, "fee_wei": summary.Fee.String()}) {
+				continue
+			}
+			fmt.Printf("%-42v %6v %14v %v\n", summary.Beneficiary.Hex(), summary.Txs, summary.GasUsed, wei2Other(bigInt2Decimal(summary.Fee), unitEther))
+		}
+		if !globalOptTerseOutput && !globalOptJsonl {
+			fmt.Printf("total: %v ether for %v beneficiaries\n", wei2Other(bigInt2Decimal(total), unitEther), len(summaries))
+		}
+	},
+}
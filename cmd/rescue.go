@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/spf13/cobra"
+)
+
+var rescueTokens []string
+var rescuePrivateRelayUrl string
+var rescueTipMultiplier int64
+var rescueSkipEth bool
+var rescueSponsorKey string
+var rescueFlashbotsRelayUrl string
+var rescueBundleBlocks uint64
+
+func init() {
+	rescueCmd.Flags().StringSliceVarP(&rescueTokens, "token", "", []string{}, "ERC20 token contract to sweep, can be specified multiple times or separated by comma")
+	rescueCmd.Flags().StringVarP(&rescuePrivateRelayUrl, "private-relay", "", "", "broadcast txs through this private relay rpc (e.g. https://rpc.flashbots.net) instead of --node-url, avoid frontrunning bots")
+	rescueCmd.Flags().Int64VarP(&rescueTipMultiplier, "tip-multiplier", "", 5, "multiply the estimated max priority fee per gas by this value, not used on chains without eip1559")
+	rescueCmd.Flags().BoolVarP(&rescueSkipEth, "skip-eth", "", false, "do not sweep eth, only sweep tokens")
+	rescueCmd.Flags().StringVarP(&rescueSponsorKey, "sponsor-key", "", "", "private key of a clean account which pays gas, if specified, sweep txs are submitted as flashbots bundles together with a funding tx from this account")
+	rescueCmd.Flags().StringVarP(&rescueFlashbotsRelayUrl, "flashbots-relay", "", "", "the flashbots relay url used by --sponsor-key, default relay of current chain is used if not specified")
+	rescueCmd.Flags().Uint64VarP(&rescueBundleBlocks, "bundle-blocks", "", 10, "submit the bundle for this many following blocks, only used by --sponsor-key")
+
+	addFlags(rescueCmd, txFlags)
+}
+
+// rescueTx is a pre-signed sweep tx
+type rescueTx struct {
+	desc     string
+	signedTx *types.Transaction
+}
+
+var rescueCmd = &cobra.Command{
+	Use:   "rescue safe-address",
+	Short: "Sweep eth and tokens from a compromised account (--private-key) to safe-address as fast as possible",
+	Long: "Sweep eth and tokens from a compromised account (--private-key) to safe-address as fast as possible. " +
+		"All sweep txs are built and signed before broadcasting any of them, tokens are swept first and remaining eth is swept at last.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires safe-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		for i := range rescueTokens {
+			if !isValidAddressArg(cmd.Context(), rescueTokens[i]) {
+				return fmt.Errorf("token %v is not a valid eth address", rescueTokens[i])
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for rescue command")
+		}
+		if rescueTipMultiplier <= 0 {
+			log.Fatalf("--tip-multiplier must be greater than 0")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		fromAddress := extractAddressFromPrivateKey(privateKey)
+		safeAddress := mustParseAddressArg(cmd.Context(), args[0])
+		if fromAddress == safeAddress {
+			log.Fatalf("safe-address can not be the compromised address %v", fromAddress.Hex())
+		}
+
+		checkTOTP()
+		var sponsored = rescueSponsorKey != ""
+		txs, err := buildRescueTxs(cmd.Context(), privateKey, safeAddress, rescueTokens, sponsored)
+		checkErr(err)
+		if len(txs) == 0 {
+			log.Printf("nothing to rescue")
+			return
+		}
+
+		if sponsored {
+			// the compromised key is not checked, moving funds out of a weak key is the point of rescue
+			sponsorKey := buildPrivateKeyFromHex(rescueSponsorKey)
+			checkWeakPrivateKey(sponsorKey)
+			sponsorTx, err := buildSponsorTx(cmd.Context(), sponsorKey, fromAddress, txs)
+			checkErr(err)
+			// funding tx must be the first one in bundle
+			txs = append([]rescueTx{*sponsorTx}, txs...)
+		}
+
+		for _, tx := range txs {
+			rawTx, err := ethutil.GenRawTx(tx.signedTx)
+			checkErr(err)
+			log.Printf("%v: nonce %v, tx %v", tx.desc, tx.signedTx.Nonce(), tx.signedTx.Hash().Hex())
+			if globalOptShowRawTx {
+				log.Printf("raw tx = %v", rawTx)
+			}
+		}
+
+		if globalOptDryRun {
+			return
+		}
+
+		for _, tx := range txs {
+			checkApproval(tx.signedTx)
+		}
+
+		if sponsored {
+			checkErr(sendRescueBundle(cmd.Context(), txs))
+			return
+		}
+
+		var broadcastClient = globalClient.RpcClient
+		if rescuePrivateRelayUrl != "" {
+			broadcastClient, err = rpc.DialContext(cmd.Context(), rescuePrivateRelayUrl)
+			checkErr(err)
+			log.Printf("broadcasting through private relay %v", rescuePrivateRelayUrl)
+		}
+
+		// broadcast all txs back to back, do not wait receipt between them
+		for _, tx := range txs {
+			if _, err := ethutil.SendRawTransaction(cmd.Context(), broadcastClient, tx.signedTx); err != nil {
+				log.Printf("broadcast %v fail: %v", tx.desc, err)
+			}
+		}
+
+		for _, tx := range txs {
+			rp, err := ethutil.WaitReceipt(cmd.Context(), globalClient, tx.signedTx.Hash(), waitOptions())
+			if err != nil {
+				log.Printf("%v: %v", tx.desc, err)
+				continue
+			}
+			if rp.Status != types.ReceiptStatusSuccessful {
+				log.Printf("%v: tx %v failed", tx.desc, tx.signedTx.Hash().Hex())
+			} else {
+				log.Printf("%v: tx %v succeeded", tx.desc, tx.signedTx.Hash().Hex())
+			}
+		}
+	},
+}
+
+// buildRescueTxs builds and signs all sweep txs, token sweep txs come first and eth sweep tx is the last one.
+// If sponsored is true, gas is paid by a funding tx from sponsor, eth is not swept.
+func buildRescueTxs(ctx context.Context, privateKey *ecdsa.PrivateKey, safeAddress common.Address, tokens []string, sponsored bool) ([]rescueTx, error) {
+	client := globalClient.EthClient
+	fromAddress := extractAddressFromPrivateKey(privateKey)
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ChainID fail: %w", err)
+	}
+	nonce, err := client.PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("PendingNonceAt fail: %w", err)
+	}
+	ethBalance, err := client.PendingBalanceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, fmt.Errorf("PendingBalanceAt fail: %w", err)
+	}
+	log.Printf("balance of %v is %v ether", fromAddress.Hex(), wei2Other(bigInt2Decimal(ethBalance), unitEther))
+
+	// gasTipCap nil means chain doesn't support eip1559, legacy txs with gas price gasFeeCap are built
+	var gasTipCap, gasFeeCap *big.Int
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HeaderByNumber fail: %w", err)
+	}
+	if head.BaseFee == nil {
+		log.Printf("%v, fall back to eip155 tx with gas price", ethutil.ErrEip1559NotSupported)
+		gasFeeCap, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("SuggestGasPrice fail: %w", err)
+		}
+		log.Printf("gas price %v gwei", wei2Other(bigInt2Decimal(gasFeeCap), unitGwei))
+	} else {
+		gasTipCap, err = client.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("SuggestGasTipCap fail: %w", err)
+		}
+		gasTipCap.Mul(gasTipCap, big.NewInt(rescueTipMultiplier))
+		// tolerate base fee doubling in the following blocks
+		gasFeeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), gasTipCap)
+		log.Printf("max priority fee per gas %v gwei, max fee per gas %v gwei",
+			wei2Other(bigInt2Decimal(gasTipCap), unitGwei), wei2Other(bigInt2Decimal(gasFeeCap), unitGwei))
+	}
+
+	signer := types.LatestSignerForChainID(chainID)
+	var txs []rescueTx
+	var totalGasCost = new(big.Int)
+
+	for _, token := range tokens {
+		tokenAddress := mustParseAddressArg(ctx, token)
+		balanceOfData, err := ethutil.BuildTxInputData(erc20FuncSignature["balanceOf"], []string{fromAddress.Hex()})
+		if err != nil {
+			return nil, err
+		}
+		output, err := ethutil.Call(ctx, client, tokenAddress, balanceOfData, nil)
+		if err != nil {
+			return nil, fmt.Errorf("balanceOf of token %v fail: %w", token, err)
+		}
+		tokenBalance := new(big.Int).SetBytes(output)
+		log.Printf("token %v balance %v", tokenAddress.Hex(), tokenBalance)
+		if tokenBalance.Sign() == 0 {
+			continue
+		}
+
+		transferData, err := ethutil.BuildTxInputData(erc20FuncSignature["transfer"], []string{safeAddress.Hex(), tokenBalance.String()})
+		if err != nil {
+			return nil, err
+		}
+		gasLimit, err := client.EstimateGas(ctx, ethereum.CallMsg{From: fromAddress, To: &tokenAddress, Data: transferData})
+		if err != nil {
+			return nil, fmt.Errorf("EstimateGas for token %v fail: %w", token, err)
+		}
+		gasLimit = gasLimit * 12 / 10 // add 20% buffer
+
+		checkPolicy(ctx, nil, chainID, &tokenAddress, big.NewInt(0), transferData)
+		signedTx, err := types.SignNewTx(privateKey, signer,
+			newRescueTxData(chainID, nonce, &tokenAddress, big.NewInt(0), gasLimit, transferData, gasTipCap, gasFeeCap))
+		if err != nil {
+			return nil, fmt.Errorf("SignNewTx fail: %w", err)
+		}
+		txs = append(txs, rescueTx{desc: fmt.Sprintf("sweep token %v", tokenAddress.Hex()), signedTx: signedTx})
+		totalGasCost.Add(totalGasCost, new(big.Int).Mul(gasFeeCap, new(big.Int).SetUint64(gasLimit)))
+		nonce++
+	}
+
+	if sponsored {
+		return txs, nil
+	}
+
+	if totalGasCost.Cmp(ethBalance) > 0 {
+		log.Printf("warning: eth balance %v wei is not enough to pay gas %v wei of token sweep txs", ethBalance, totalGasCost)
+	}
+
+	if !rescueSkipEth {
+		ethGasCost := new(big.Int).Mul(gasFeeCap, big.NewInt(gasUsedByTransferEth))
+		amount := new(big.Int).Sub(ethBalance, totalGasCost)
+		amount.Sub(amount, ethGasCost)
+		if amount.Sign() <= 0 {
+			log.Printf("eth balance is not enough to pay gas of eth sweep tx, skip sweeping eth")
+		} else {
+			checkPolicy(ctx, nil, chainID, &safeAddress, amount, nil)
+			signedTx, err := types.SignNewTx(privateKey, signer,
+				newRescueTxData(chainID, nonce, &safeAddress, amount, gasUsedByTransferEth, nil, gasTipCap, gasFeeCap))
+			if err != nil {
+				return nil, fmt.Errorf("SignNewTx fail: %w", err)
+			}
+			txs = append(txs, rescueTx{desc: fmt.Sprintf("sweep %v ether", wei2Other(bigInt2Decimal(amount), unitEther)), signedTx: signedTx})
+		}
+	}
+
+	return txs, nil
+}
+
+// newRescueTxData returns an eip1559 tx, or a legacy tx with gas price gasFeeCap if gasTipCap is nil.
+func newRescueTxData(chainID *big.Int, nonce uint64, to *common.Address, value *big.Int, gas uint64, data []byte, gasTipCap, gasFeeCap *big.Int) types.TxData {
+	if gasTipCap == nil {
+		return &types.LegacyTx{Nonce: nonce, GasPrice: gasFeeCap, Gas: gas, To: to, Value: value, Data: data}
+	}
+	return &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gas,
+		To:        to,
+		Value:     value,
+		Data:      data,
+	}
+}
+
+// buildSponsorTx builds and signs the tx which funds compromised address with exactly the max gas cost of txs.
+func buildSponsorTx(ctx context.Context, sponsorKey *ecdsa.PrivateKey, compromisedAddress common.Address, txs []rescueTx) (*rescueTx, error) {
+	client := globalClient.EthClient
+	sponsorAddress := extractAddressFromPrivateKey(sponsorKey)
+	if sponsorAddress == compromisedAddress {
+		return nil, fmt.Errorf("--sponsor-key can not be the compromised key")
+	}
+
+	var amount = new(big.Int)
+	for _, tx := range txs {
+		amount.Add(amount, new(big.Int).Mul(tx.signedTx.GasFeeCap(), new(big.Int).SetUint64(tx.signedTx.Gas())))
+	}
+
+	nonce, err := client.PendingNonceAt(ctx, sponsorAddress)
+	if err != nil {
+		return nil, fmt.Errorf("PendingNonceAt fail: %w", err)
+	}
+
+	chainID := txs[0].signedTx.ChainId()
+	checkPolicy(ctx, nil, chainID, &compromisedAddress, amount, nil)
+	var gasTipCap *big.Int // nil for legacy txs
+	if txs[0].signedTx.Type() == types.DynamicFeeTxType {
+		gasTipCap = txs[0].signedTx.GasTipCap()
+	}
+	signedTx, err := types.SignNewTx(sponsorKey, types.LatestSignerForChainID(chainID),
+		newRescueTxData(chainID, nonce, &compromisedAddress, amount, gasUsedByTransferEth, nil, gasTipCap, txs[0].signedTx.GasFeeCap()))
+	if err != nil {
+		return nil, fmt.Errorf("SignNewTx fail: %w", err)
+	}
+	return &rescueTx{
+		desc:     fmt.Sprintf("fund %v ether from sponsor %v", wei2Other(bigInt2Decimal(amount), unitEther), sponsorAddress.Hex()),
+		signedTx: signedTx,
+	}, nil
+}
+
+// sendRescueBundle submits txs as a flashbots bundle targeting the following --bundle-blocks blocks, and waits until
+// the bundle is included or all target blocks are passed.
+func sendRescueBundle(ctx context.Context, txs []rescueTx) error {
+	client := globalClient.EthClient
+
+	relay, relayUrl, err := dialFlashbotsRelay(ctx, rescueFlashbotsRelayUrl)
+	if err != nil {
+		return err
+	}
+	defer relay.Close()
+
+	var bundle []*types.Transaction
+	for _, tx := range txs {
+		bundle = append(bundle, tx.signedTx)
+	}
+
+	current, err := client.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("BlockNumber fail: %w", err)
+	}
+	lastTarget := current + rescueBundleBlocks
+	for target := current + 1; target <= lastTarget; target++ {
+		bundleHash, err := relay.SendBundle(ctx, bundle, target)
+		if err != nil {
+			return err
+		}
+		log.Printf("bundle %v submitted to %v for block %v", bundleHash, relayUrl, target)
+	}
+
+	lastTx := bundle[len(bundle)-1]
+	for {
+		rp, err := client.TransactionReceipt(ctx, lastTx.Hash())
+		if err == nil {
+			log.Printf("bundle included in block %v", rp.BlockNumber)
+			for _, tx := range txs {
+				log.Printf("%v: tx %v", tx.desc, tx.signedTx.Hash().Hex())
+			}
+			return nil
+		}
+		if err != ethereum.NotFound {
+			return fmt.Errorf("TransactionReceipt fail: %w", err)
+		}
+
+		current, err = client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("BlockNumber fail: %w", err)
+		}
+		if current > lastTarget {
+			return fmt.Errorf("bundle is not included in blocks [%v, %v], please retry (maybe with a larger --tip-multiplier)", lastTarget-rescueBundleBlocks+1, lastTarget)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
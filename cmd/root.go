@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
@@ -25,32 +30,100 @@ var (
 	globalOptShowInputData        bool
 	globalOptShowEstimateGas      bool
 	globalOptTxType               string
+	globalOptTimeout              time.Duration
+	globalOptConfirmations        uint64
+	globalOptPollInterval         time.Duration
+	globalOptWaitTimeout          time.Duration
+	globalOptConfigFile           string
+	globalOptProfile              string
+	globalOptSpeed                string
+	globalOptPriorityFeeFloor     string
+	globalOptShowFiat             string
+	globalOptPriceSource          string
+	globalOptAllowWeakKey         bool
+	globalOptExport               string
+	globalOptExportFile           string
+	globalOptPolicy               string
+	globalOptPolicySigner         string
+	globalOptApprovers            []string
+	globalOptApprovalFiles        []string
+	globalOptApprovalThreshold    string
+	globalOptTotpFile             string
+	globalOptFormat               string
+	globalOptStdin                bool
+	globalOptJsonl                bool
+	globalOptNoNetwork            bool
+	globalOptPrivateTx            bool
+	globalOptPrivateTxRelay       string
+	globalOptFlashbotsAuthKey     string
+	globalOptBlock                string
+	globalOptRpcUrls              []string
+	globalOptRpcRetries           int
+	globalOptRpcTimeout           time.Duration
+	globalOptRps                  float64
+	globalOptConcurrency          int
+	globalOptRpcBatchSize         int
+	globalOptExplorerApiUrl       string
+	globalOptExplorerApiKey       string
+	globalOptExplorerKind         string
 	rootCmd                       = &cobra.Command{
 		Use:   "ethutil",
 		Short: "An Ethereum util, can transfer eth, check balance, call any contract function etc",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			enforceNoNetwork(cmd)
+			if globalOptTimeout > 0 {
+				var ctx context.Context
+				ctx, globalCancelTimeout = context.WithTimeout(cmd.Context(), globalOptTimeout)
+				cmd.SetContext(ctx)
+			}
+		},
 	}
 
-	globalClient *Client
-)
+	// globalCancelTimeout releases resources of the deadline context derived from --timeout
+	globalCancelTimeout context.CancelFunc = func() {}
 
-type Client struct {
-	EthClient *ethclient.Client
-	RpcClient *rpc.Client
-}
+	globalClient *ethutil.Client
 
-// InitGlobalClient initializes a client that uses the given RPC client.
-func InitGlobalClient(nodeUrl string) {
-	rpcClient, err := rpc.Dial(nodeUrl)
-	checkErr(err)
+	// globalEndpoints are the endpoints declared by --profile
+	globalEndpoints ethutil.Endpoints
+
+	// globalCoinGeckoApiKey, globalEtherscanApiKey and globalPriceCacheTTL are declared by --profile
+	globalCoinGeckoApiKey string
+	globalEtherscanApiKey string
+	globalPriceCacheTTL   = defaultPriceCacheTTL
 
-	globalClient = &Client{
-		EthClient: ethclient.NewClient(rpcClient),
-		RpcClient: rpcClient,
+	// globalRequireTOTP is declared by --profile, signing fails if TOTP file is missing
+	globalRequireTOTP bool
+)
+
+// InitGlobalClient initializes a client that connects to the given node url, rpc methods are dispatched to
+// archive/trace/broadcast endpoints of --profile if any.
+func InitGlobalClient(ctx context.Context, nodeUrl string) {
+	checkNetworkAllowed("connecting node " + nodeUrl)
+	var err error
+	endpoints := globalEndpoints
+	if nodeUrl != globalOptNodeUrl {
+		// e.g. a local fork node, fallbacks of current network are not its fallbacks
+		endpoints.Fallbacks = nil
 	}
+	endpoints.Default = nodeUrl
+	var retry ethutil.RetryOptions
+	var limit ethutil.RateLimit
+	if strings.HasPrefix(nodeUrl, "http://") || strings.HasPrefix(nodeUrl, "https://") {
+		// retry and rate limit are not supported by websocket and ipc
+		retry = ethutil.RetryOptions{Retries: globalOptRpcRetries, Timeout: globalOptRpcTimeout}
+		limit = ethutil.RateLimit{RPS: globalOptRps, Concurrency: globalOptConcurrency}
+	} else if globalOptRps > 0 || globalOptConcurrency > 0 {
+		log.Printf("--rps and --concurrency are ignored as %v is not a http(s) url", nodeUrl)
+	}
+	globalClient, err = ethutil.DialEndpointsWithLimit(ctx, endpoints, retry, limit)
+	checkErr(err)
+	checkChainPrefixes(ctx)
 }
 
-const txTypeEip155 = "eip155"
-const txTypeEip1559 = "eip1559"
+const txTypeEip155 = ethutil.TxTypeEip155
+const txTypeEip1559 = ethutil.TxTypeEip1559
+const txTypeEip2930 = ethutil.TxTypeEip2930
 
 const nodeMainnet = "mainnet"
 const nodeGoerli = "goerli"
@@ -77,18 +150,115 @@ var nodeTxExplorerUrlMap = map[string]string{
 	nodeHeco:    "https://scan.hecochain.com/tx/",
 }
 
+// nodeApiUrlMap maps network to the Etherscan-compatible api of its block explorer
 var nodeApiUrlMap = map[string]string{
-	nodeMainnet: "https://api.etherscan.io/api?module=contract&action=getsourcecode&address=%s",
-	nodeGoerli:  "https://api-goerli.etherscan.io/api?module=contract&action=getsourcecode&address=%s",
-	nodeSepolia: "https://api-sepolia.etherscan.io/api?module=contract&action=getsourcecode&address=%s",
-	nodeSokol:   "https://blockscout.com/poa/sokol/api?module=contract&action=getsourcecode&address=%s",
-	nodeBsc:     "https://api.bscscan.com/api?module=contract&action=getsourcecode&address=%s",
-	nodeHeco:    "https://api.hecoinfo.com/api?module=contract&action=getsourcecode&address=%s",
+	nodeMainnet: "https://api.etherscan.io/api",
+	nodeGoerli:  "https://api-goerli.etherscan.io/api",
+	nodeSepolia: "https://api-sepolia.etherscan.io/api",
+	nodeSokol:   "https://blockscout.com/poa/sokol/api",
+	nodeBsc:     "https://api.bscscan.com/api",
+	nodeHeco:    "https://api.hecoinfo.com/api",
+}
+
+var nodeChainIdMap = map[string]uint64{
+	nodeMainnet: 1,
+	nodeGoerli:  5,
+	nodeSepolia: 11155111,
+	nodeSokol:   77,
+	nodeBsc:     56,
+	nodeHeco:    128,
 }
 
-// Execute cobra root command
+// Execute cobra root command, the context of command is cancelled by Ctrl-C (SIGINT) or SIGTERM.
 func Execute() error {
-	return rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// restore default behavior of signals, so a second Ctrl-C kills the command which doesn't respect ctx
+		<-ctx.Done()
+		stop()
+	}()
+	defer func() { globalCancelTimeout() }()
+
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// Flags of features are not registered on rootCmd (except the tx flags which have been there from the beginning), but on
+// the commands using them by addFlags, so help of a command only lists flags it understands. The commands sharing a
+// flag share the same *pflag.Flag.
+var (
+	txFlags       = newTxFlags()
+	blockFlags    = newBlockFlags()
+	fiatFlags     = newFiatFlags()
+	exportFlags   = newExportFlags()
+	explorerFlags = newExplorerFlags()
+)
+
+// addFlags adds flags of fs to cmd and its subcommands, only the flags of names are added if names is not empty.
+func addFlags(cmd *cobra.Command, fs *pflag.FlagSet, names ...string) {
+	for _, name := range names {
+		if fs.Lookup(name) == nil {
+			panic("unknown flag " + name)
+		}
+	}
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if len(names) == 0 || contains(names, flag.Name) {
+			cmd.PersistentFlags().AddFlag(flag)
+		}
+	})
+}
+
+// newTxFlags returns flags of building, signing, broadcasting and waiting tx, except the ones on rootCmd.
+func newTxFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("tx", pflag.ExitOnError)
+	fs.BoolVarP(&globalOptAllowWeakKey, "allow-weak-key", "", false, "allow sending value from brainwallet key, i.e. keccak256 of a well known short string")
+	fs.StringVarP(&globalOptSpeed, "speed", "", ethutil.SpeedAverage, "slow | average | fast, the speed of estimated max priority fee per gas, only used by eip1559 tx")
+	fs.StringVarP(&globalOptPriorityFeeFloor, "priority-fee-floor", "", "", "the minimum estimated max priority fee per gas, unit is gwei. also used when no estimation method is supported by node")
+	fs.Uint64VarP(&globalOptConfirmations, "confirmations", "", 1, "wait until tx has this number of confirmations (blocks since and including the block of tx)")
+	fs.DurationVarP(&globalOptPollInterval, "poll-interval", "", 5*time.Second, "interval of polling tx receipt, receipt is also checked on each new block if node url is websocket or ipc")
+	fs.DurationVarP(&globalOptWaitTimeout, "wait-timeout", "", 0, "stop waiting for the receipt of tx after this duration (e.g. 5m), 0 means wait forever")
+	fs.StringVarP(&globalOptPolicy, "policy", "", "", "the policy file evaluated before signing any tx, tx not matching any rule of it is refused")
+	fs.StringVarP(&globalOptPolicySigner, "policy-signer", "", "", "the trusted signer of --policy, the signature in <policy>.sig is verified if specified")
+	fs.StringSliceVarP(&globalOptApprovers, "approvers", "", nil, "the trusted approvers, if specified, broadcasting tx requires an approval file (created by approve command) of one of them")
+	fs.StringSliceVarP(&globalOptApprovalFiles, "approval-file", "", nil, "the approval file created by approve command, can be specified multiple times")
+	fs.StringVarP(&globalOptApprovalThreshold, "approval-threshold", "", "", "only tx with value not less than this requires approval of --approvers, unit is ether. tx with input data (e.g. token transfer, contract call) always requires approval. default all tx requires approval")
+	fs.StringVarP(&globalOptTotpFile, "totp-file", "", "", "the TOTP file created by totp enroll, TOTP code is required before signing if it exists (default ~/.ethutil/totp.json)")
+	fs.BoolVarP(&globalOptPrivateTx, "private-tx", "", false, "send tx privately by eth_sendPrivateTransaction of flashbots relay instead of public mempool, avoid frontrunning and sandwich")
+	fs.StringVarP(&globalOptPrivateTxRelay, "private-tx-relay", "", "", "the flashbots relay url used by --private-tx, default relay of current chain is used if not specified")
+	fs.StringVarP(&globalOptFlashbotsAuthKey, "flashbots-auth-key", "", "", "the private key signing flashbots relay requests, it identifies searcher reputation and needs no eth. a random key is used if not specified")
+	return fs
+}
+
+// newBlockFlags returns flags of reading state at historical block.
+func newBlockFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("block", pflag.ExitOnError)
+	fs.StringVarP(&globalOptBlock, "block", "", "", "read state (balance, query, storage, account, erc20 etc) at this block number, tag (latest, pending, earliest, finalized, safe) or block hash, old blocks require archive node. default is latest")
+	return fs
+}
+
+// newFiatFlags returns flags of showing value in fiat currency.
+func newFiatFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("fiat", pflag.ExitOnError)
+	fs.StringVarP(&globalOptShowFiat, "show-fiat", "", "", "show value in this fiat currency (e.g. USD, EUR) for balance, estimate-gas and transfer")
+	fs.StringVarP(&globalOptPriceSource, "price-source", "", priceSourceCoinGecko, "coingecko | chainlink | defillama, the price source used by --show-fiat, chainlink feeds are read from mainnet, defillama only supports USD")
+	return fs
+}
+
+// newExportFlags returns flags of exporting scan results.
+func newExportFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("export", pflag.ExitOnError)
+	fs.StringVarP(&globalOptExport, "export", "", "", "dune | bigquery, export scan results (balance, wallet scan, nonce-reuse) as Dune upload csv or BigQuery newline delimited json")
+	fs.StringVarP(&globalOptExportFile, "export-file", "", "", "the file of --export, default is stdout. BigQuery schema is written to <export-file>.schema.json")
+	return fs
+}
+
+// newExplorerFlags returns flags of block explorer api.
+func newExplorerFlags() *pflag.FlagSet {
+	fs := pflag.NewFlagSet("explorer", pflag.ExitOnError)
+	fs.StringVarP(&globalOptExplorerApiUrl, "explorer-api-url", "", "", "the Etherscan-compatible api of block explorer (e.g. https://api.etherscan.io/api), default is the explorer of --node")
+	fs.StringVarP(&globalOptExplorerApiKey, "explorer-api-key", "", "", "the api key of block explorer, takes precedence over etherscan_api_key of profile")
+	fs.StringVarP(&globalOptExplorerKind, "explorer-kind", "", "", "etherscan | blockscout, the kind of block explorer, default is blockscout if host of explorer api contains blockscout, otherwise etherscan")
+	return fs
 }
 
 func init() {
@@ -96,19 +266,32 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVarP(&globalOptNodeUrl, "node-url", "", "", "the target connection node url, if this option specified, the --node option is ignored")
+	rootCmd.PersistentFlags().StringArrayVarP(&globalOptRpcUrls, "rpc", "", nil, "the http(s) node url, can be specified multiple times, the first is preferred and others are fallbacks on failure, takes precedence over --node-url")
+	rootCmd.PersistentFlags().IntVarP(&globalOptRpcRetries, "rpc-retries", "", 2, "max retries of failed http(s) rpc request (network error, timeout, 429 or 5xx), with exponential backoff, each retry fails over to next --rpc. tx broadcasting requests are never retried")
+	rootCmd.PersistentFlags().DurationVarP(&globalOptRpcTimeout, "rpc-timeout", "", 0, "timeout of each http(s) rpc request, 0 means no timeout")
+	rootCmd.PersistentFlags().Float64VarP(&globalOptRps, "rps", "", 0, "max http(s) rpc requests per second shared by all concurrent calls, retries included, 0 means no limit")
+	rootCmd.PersistentFlags().IntVarP(&globalOptConcurrency, "concurrency", "", 0, "max in-flight http(s) rpc requests, 0 means no limit")
+	rootCmd.PersistentFlags().IntVarP(&globalOptRpcBatchSize, "rpc-batch-size", "", ethutil.DefaultBatchSize, "max calls in a JSON-RPC batch request, used by bulk queries (e.g. balance of many addresses) if Multicall3 is not deployed, 1 disables batching")
 	rootCmd.PersistentFlags().StringVarP(&globalOptNode, "node", "", "goerli", "mainnet | goerli | sepolia |sokol | bsc | heco, the node type")
 	rootCmd.PersistentFlags().StringVarP(&globalOptGasPrice, "gas-price", "", "", "the gas price, unit is gwei.")
 	rootCmd.PersistentFlags().StringVarP(&globalOptMaxPriorityFeePerGas, "max-priority-fee-per-gas", "", "", "maximum fee per gas they are willing to give to miners, unit is gwei. see eip1559")
 	rootCmd.PersistentFlags().StringVarP(&globalOptMaxFeePerGas, "max-fee-per-gas", "", "", "maximum fee per gas they are willing to pay total, unit is gwei. see eip1559")
 	rootCmd.PersistentFlags().Uint64VarP(&globalOptGasLimit, "gas-limit", "", 0, "the gas limit")
 	rootCmd.PersistentFlags().Int64VarP(&globalOptNonce, "nonce", "", -1, "the nonce, -1 means check online")
-	rootCmd.PersistentFlags().StringVarP(&globalOptPrivateKey, "private-key", "k", "", "the private key, eth would be send from this account")
+	rootCmd.PersistentFlags().StringVarP(&globalOptPrivateKey, "private-key", "k", "", "the private key (hex, WIF, or file of SEC1/PKCS#8 PEM, DER or keystore V3), eth would be send from this account")
 	rootCmd.PersistentFlags().BoolVarP(&globalOptTerseOutput, "terse", "", false, "produce terse output")
 	rootCmd.PersistentFlags().BoolVarP(&globalOptDryRun, "dry-run", "", false, "do not broadcast tx")
 	rootCmd.PersistentFlags().BoolVarP(&globalOptShowRawTx, "show-raw-tx", "", false, "print raw signed tx")
 	rootCmd.PersistentFlags().BoolVarP(&globalOptShowInputData, "show-input-data", "", false, "print input data of tx")
 	rootCmd.PersistentFlags().BoolVarP(&globalOptShowEstimateGas, "show-estimate-gas", "", false, "print estimate gas of tx")
-	rootCmd.PersistentFlags().StringVarP(&globalOptTxType, "tx-type", "", "eip155", "eip155 | eip1559, the type of tx your want to send")
+	rootCmd.PersistentFlags().StringVarP(&globalOptTxType, "tx-type", "", "eip155", "eip155 | eip2930 | eip1559, the type of tx your want to send")
+	rootCmd.PersistentFlags().StringVarP(&globalOptFormat, "format", "", "", "print result of command (tx, balance, price) by this go template, e.g. '{{.TxHash}} {{.GasUsed}}'")
+	rootCmd.PersistentFlags().BoolVarP(&globalOptStdin, "stdin", "", false, "read inputs (addresses, hashes, raw txs, etc.) from stdin line by line instead of args, a line is plain value or JSON object")
+	rootCmd.PersistentFlags().BoolVarP(&globalOptJsonl, "jsonl", "", false, "print results as JSON lines, which can be piped to another command with --stdin")
+	rootCmd.PersistentFlags().BoolVarP(&globalOptNoNetwork, "no-network", "", false, "guarantee no network access for air-gapped machine, only offline commands are allowed (e.g. sign-tx, build-tx with --nonce and --chain-id)")
+	rootCmd.PersistentFlags().StringVarP(&globalOptConfigFile, "config", "", "", "the config file (default ~/.ethutil/config.json)")
+	rootCmd.PersistentFlags().StringVarP(&globalOptProfile, "profile", "", "", "use node urls declared by this profile in config file, --node-url takes precedence over node_url of profile")
+	rootCmd.PersistentFlags().DurationVarP(&globalOptTimeout, "timeout", "", 0, "abort the command if it does not finish within this duration (e.g. 30s, 5m), 0 means no timeout")
 
 	rootCmd.AddCommand(balanceCmd)
 	rootCmd.AddCommand(transferCmd)
@@ -127,7 +310,75 @@ func init() {
 	rootCmd.AddCommand(erc20Cmd)
 	rootCmd.AddCommand(keccakCmd)
 	rootCmd.AddCommand(personalSignCmd)
+	rootCmd.AddCommand(personalVerifyCmd)
 	rootCmd.AddCommand(downloadSrcCmd)
+	rootCmd.AddCommand(fetchAbiCmd)
+	rootCmd.AddCommand(abiCmd)
+	rootCmd.AddCommand(nonceReuseCmd)
+	rootCmd.AddCommand(rescueCmd)
+	rootCmd.AddCommand(accessListCmd)
+	rootCmd.AddCommand(safeCmd)
+	rootCmd.AddCommand(aaCmd)
+	rootCmd.AddCommand(buildTxCmd)
+	rootCmd.AddCommand(signTxCmd)
+	rootCmd.AddCommand(sendRawCmd)
+	rootCmd.AddCommand(estimateGasCmd)
+	rootCmd.AddCommand(inclusionStatsCmd)
+	rootCmd.AddCommand(accountCmd)
+	rootCmd.AddCommand(walletCmd)
+	rootCmd.AddCommand(storageCmd)
+	rootCmd.AddCommand(proxyCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(devnetCmd)
+	rootCmd.AddCommand(signHashCmd)
+	rootCmd.AddCommand(genesisCmd)
+	rootCmd.AddCommand(vanityCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(pubkeyCmd)
+	rootCmd.AddCommand(checksumCmd)
+	rootCmd.AddCommand(approveCmd)
+	rootCmd.AddCommand(totpCmd)
+	rootCmd.AddCommand(priceCmd)
+	rootCmd.AddCommand(hashCmd)
+	rootCmd.AddCommand(selectorCmd)
+	rootCmd.AddCommand(topic0Cmd)
+	rootCmd.AddCommand(defiCmd)
+	rootCmd.AddCommand(merkleCmd)
+	rootCmd.AddCommand(bundleCmd)
+	rootCmd.AddCommand(mempoolCmd)
+	rootCmd.AddCommand(signDocCmd)
+	rootCmd.AddCommand(verifyDocCmd)
+	rootCmd.AddCommand(keyCmd)
+	rootCmd.AddCommand(traceCmd)
+	rootCmd.AddCommand(exportTxCmd)
+	rootCmd.AddCommand(simulateCmd)
+	rootCmd.AddCommand(convertCmd)
+	rootCmd.AddCommand(forkCmd)
+	rootCmd.AddCommand(mathCmd)
+	rootCmd.AddCommand(nftMetadataCmd)
+	rootCmd.AddCommand(rpcCheckCmd)
+	rootCmd.AddCommand(ensCmd)
+	rootCmd.AddCommand(identityCmd)
+	rootCmd.AddCommand(feesCmd)
+	rootCmd.AddCommand(relayCmd)
+	rootCmd.AddCommand(portfolioCmd)
+	rootCmd.AddCommand(faucetCmd)
+	rootCmd.AddCommand(txListCmd)
+	rootCmd.AddCommand(internalTxsCmd)
+	rootCmd.AddCommand(explorerGasOracleCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(tmplCmd)
+	rootCmd.AddCommand(opOutputRootCmd)
+	rootCmd.AddCommand(compileCmd)
+	rootCmd.AddCommand(arbRetryableCmd)
+	rootCmd.AddCommand(finalityCmd)
+	rootCmd.AddCommand(selectorsCmd)
+	rootCmd.AddCommand(supportsInterfaceCmd)
+	rootCmd.AddCommand(erc4626Cmd)
+	rootCmd.AddCommand(exportBlocksCmd)
+	rootCmd.AddCommand(swapCmd)
+	rootCmd.AddCommand(wrapCmd)
+	rootCmd.AddCommand(unwrapCmd)
 }
 
 func initConfig() {
@@ -141,9 +392,88 @@ func initConfig() {
 		os.Exit(1)
 	}
 
+	if globalOptConfigFile == "" {
+		globalOptConfigFile = defaultConfigFile()
+	}
+
+	if globalOptProfile != "" {
+		p, err := loadProfile(globalOptConfigFile, globalOptProfile)
+		if err != nil {
+			log.Fatalf("load profile fail: %v", err)
+		}
+		globalEndpoints = p.Endpoints
+		if globalOptPriorityFeeFloor == "" {
+			globalOptPriorityFeeFloor = p.PriorityFeeFloor
+		}
+		if globalOptNodeUrl == "" {
+			globalOptNodeUrl = p.Default
+		}
+		if !fiatFlags.Changed("price-source") && p.PriceSource != "" {
+			globalOptPriceSource = p.PriceSource
+		}
+		if globalOptPolicy == "" {
+			globalOptPolicy = p.Policy
+		}
+		if globalOptPolicySigner == "" {
+			globalOptPolicySigner = p.PolicySigner
+		}
+		if len(globalOptApprovers) == 0 {
+			globalOptApprovers = p.Approvers
+		}
+		if globalOptApprovalThreshold == "" {
+			globalOptApprovalThreshold = p.ApprovalThreshold
+		}
+		if globalOptTotpFile == "" {
+			globalOptTotpFile = p.TotpFile
+		}
+		globalRequireTOTP = p.RequireTotp
+		globalCoinGeckoApiKey = p.CoinGeckoApiKey
+		globalEtherscanApiKey = p.EtherscanApiKey
+		if globalOptExplorerApiUrl == "" {
+			globalOptExplorerApiUrl = p.ExplorerApiUrl
+		}
+		if globalOptExplorerKind == "" {
+			globalOptExplorerKind = p.ExplorerKind
+		}
+		if p.PriceCacheTTL != "" {
+			if globalPriceCacheTTL, err = time.ParseDuration(p.PriceCacheTTL); err != nil {
+				log.Fatalf("invalid price_cache_ttl in profile %v: %v", globalOptProfile, p.PriceCacheTTL)
+			}
+		}
+	}
+
+	if len(globalOptRpcUrls) > 0 {
+		globalOptNodeUrl = globalOptRpcUrls[0]
+		globalEndpoints.Fallbacks = globalOptRpcUrls[1:]
+	}
 	if globalOptNodeUrl == "" {
 		globalOptNodeUrl = nodeUrlMap[globalOptNode]
 	}
+	if globalOptRpcRetries < 0 {
+		log.Printf("invalid option for --rpc-retries: %v", globalOptRpcRetries)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+	if globalOptRps < 0 {
+		log.Printf("invalid option for --rps: %v", globalOptRps)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+	if globalOptConcurrency < 0 {
+		log.Printf("invalid option for --concurrency: %v", globalOptConcurrency)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+	if globalOptRpcBatchSize < 1 {
+		log.Printf("invalid option for --rpc-batch-size: %v", globalOptRpcBatchSize)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+	if globalOptExplorerKind != "" && !contains([]string{ethutil.ExplorerKindEtherscan, ethutil.ExplorerKindBlockscout}, globalOptExplorerKind) {
+		log.Printf("invalid option for --explorer-kind: %v", globalOptExplorerKind)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
 
 	if globalOptGasPrice != "" {
 		if _, err = decimal.NewFromString(globalOptGasPrice); err != nil {
@@ -169,7 +499,63 @@ func initConfig() {
 		}
 	}
 
-	if !contains([]string{txTypeEip155, txTypeEip1559}, globalOptTxType) {
+	if globalOptPriorityFeeFloor != "" {
+		if _, err = decimal.NewFromString(globalOptPriorityFeeFloor); err != nil {
+			log.Printf("invalid option for --priority-fee-floor: %v", globalOptPriorityFeeFloor)
+			_ = rootCmd.Help()
+			os.Exit(1)
+		}
+	}
+
+	if globalOptApprovalThreshold != "" {
+		if _, err = decimal.NewFromString(globalOptApprovalThreshold); err != nil {
+			log.Printf("invalid option for --approval-threshold: %v", globalOptApprovalThreshold)
+			_ = rootCmd.Help()
+			os.Exit(1)
+		}
+	}
+
+	for _, approver := range globalOptApprovers {
+		if !isValidEthAddress(approver) {
+			log.Printf("invalid option for --approvers: %v", approver)
+			_ = rootCmd.Help()
+			os.Exit(1)
+		}
+	}
+
+	if !contains([]string{ethutil.SpeedSlow, ethutil.SpeedAverage, ethutil.SpeedFast}, globalOptSpeed) {
+		log.Printf("invalid option for --speed: %v", globalOptSpeed)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+
+	if !contains([]string{priceSourceCoinGecko, priceSourceChainlink, priceSourceDefiLlama}, globalOptPriceSource) {
+		log.Printf("invalid option for --price-source: %v", globalOptPriceSource)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+
+	if globalOptExport != "" && !contains([]string{exportFormatDune, exportFormatBigQuery}, globalOptExport) {
+		log.Printf("invalid option for --export: %v", globalOptExport)
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+
+	if globalOptFormat != "" && globalOptJsonl {
+		log.Printf("--format and --jsonl can not be specified at the same time")
+		_ = rootCmd.Help()
+		os.Exit(1)
+	}
+
+	if globalOptFormat != "" {
+		if globalFormatTemplate, err = parseFormat(globalOptFormat); err != nil {
+			log.Printf("invalid option for --format: %v", err)
+			_ = rootCmd.Help()
+			os.Exit(1)
+		}
+	}
+
+	if !contains([]string{txTypeEip155, txTypeEip2930, txTypeEip1559}, globalOptTxType) {
 		log.Printf("invalid option for --tx-type: %v", globalOptTxType)
 		_ = rootCmd.Help()
 		os.Exit(1)
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+var rpcCheckSamples int
+var rpcCheckTimeout time.Duration
+var rpcCheckWatch time.Duration
+var rpcCheckMaxLag uint64
+var rpcCheckCompareDepth uint64
+
+func init() {
+	rpcCheckCmd.Flags().IntVarP(&rpcCheckSamples, "samples", "", 5, "the number of eth_blockNumber calls to measure latency, the median is reported")
+	rpcCheckCmd.Flags().DurationVarP(&rpcCheckTimeout, "request-timeout", "", 15*time.Second, "timeout of checking each endpoint, a timed out endpoint is unreachable")
+	rpcCheckCmd.Flags().DurationVarP(&rpcCheckWatch, "watch", "", 0, "monitor head blocks of endpoints at this interval (e.g. 15s) and alert lagging or divergent endpoints, 0 means check once")
+	rpcCheckCmd.Flags().Uint64VarP(&rpcCheckMaxLag, "max-lag", "", 3, "alert endpoint whose head is more than this number of blocks behind the highest head, used by --watch")
+	rpcCheckCmd.Flags().Uint64VarP(&rpcCheckCompareDepth, "compare-depth", "", 2, "compare block hashes at this depth below the lowest head, so a reorg at the tip is not divergence, used by --watch")
+}
+
+// configuredEndpoints returns the distinct endpoints of --node-url (or --rpc) and --profile, including fallbacks.
+func configuredEndpoints() []string {
+	var endpoints []string
+	for _, endpoint := range append([]string{globalOptNodeUrl, globalEndpoints.Archive, globalEndpoints.Trace,
+		globalEndpoints.Broadcast}, globalEndpoints.Fallbacks...) {
+		if endpoint != "" && !contains(endpoints, endpoint) {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// watchHeads compares head blocks of endpoints every --watch interval until the command is cancelled, and prints
+// alerts of unreachable, lagging and divergent endpoints.
+func watchHeads(ctx context.Context, endpoints []string) {
+	var clients []*ethutil.Client
+	for _, endpoint := range endpoints {
+		client, err := ethutil.Dial(ctx, endpoint)
+		checkErr(err)
+		defer client.Close()
+		clients = append(clients, client)
+	}
+	log.Printf("monitoring head blocks of %v endpoints every %v", len(endpoints), rpcCheckWatch)
+	for {
+		statuses := ethutil.FetchHeads(ctx, endpoints, clients, rpcCheckCompareDepth, rpcCheckTimeout)
+		alerts := ethutil.EvaluateHeads(statuses, rpcCheckMaxLag)
+		now := time.Now().UTC().Format(time.RFC3339)
+		for _, alert := range alerts {
+			if printJSONL(map[string]any{"time": now, "endpoint": alert.Endpoint, "alert": alert.Kind, "message": alert.Message}) {
+				continue
+			}
+			fmt.Printf("%v ALERT %v %v: %v\n", now, alert.Kind, alert.Endpoint, alert.Message)
+		}
+		if len(alerts) == 0 && !globalOptJsonl && !globalOptTerseOutput {
+			var highest uint64
+			for _, status := range statuses {
+				if status.Number > highest {
+					highest = status.Number
+				}
+			}
+			fmt.Printf("%v ok, %v endpoints at head %v\n", now, len(endpoints), highest)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(rpcCheckWatch):
+		}
+	}
+}
+
+// formatOptionalUint formats v, or "-" if it's nil.
+func formatOptionalUint(v *uint64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
+
+var rpcCheckCmd = &cobra.Command{
+	Use:     "rpc-check [node-url ...]",
+	Aliases: []string{"node-info"},
+	Short:   "Check health of rpc endpoints: chain id, client version, sync status, latest block, txpool status and latency",
+	Long: "Check health of rpc endpoints concurrently: chain id, client version, sync status, latest block and its age, " +
+		"txpool status and latency, then rank them from the healthiest. The endpoint of --node is checked if no " +
+		"node-url is given. With --watch, head blocks of the endpoints (default all endpoints of --rpc and --profile) are\n" +
+		"compared periodically, and endpoints which are unreachable, lag behind or serve a divergent block hash are alerted.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if rpcCheckSamples <= 0 {
+			return fmt.Errorf("--samples must be greater than 0")
+		}
+		if rpcCheckWatch < 0 {
+			return fmt.Errorf("--watch must not be negative")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var endpoints = args
+		if len(endpoints) == 0 {
+			log.Printf("Current network is %v", globalOptNode)
+			endpoints = []string{globalOptNodeUrl}
+			if rpcCheckWatch > 0 {
+				endpoints = configuredEndpoints()
+			}
+		}
+		for _, endpoint := range endpoints {
+			checkNetworkAllowed("connecting node " + endpoint)
+		}
+		if rpcCheckWatch > 0 {
+			watchHeads(ctx, endpoints)
+			return
+		}
+
+		var infos = make([]*ethutil.NodeInfo, len(endpoints))
+		var wg sync.WaitGroup
+		for i, endpoint := range endpoints {
+			wg.Add(1)
+			go func(i int, endpoint string) {
+				defer wg.Done()
+				infos[i] = ethutil.CheckNode(ctx, endpoint, rpcCheckSamples, rpcCheckTimeout)
+			}(i, endpoint)
+		}
+		wg.Wait()
+		ethutil.RankNodes(infos)
+
+		var highest uint64
+		var chainIds = make(map[string]bool)
+		for _, info := range infos {
+			if info.Err == nil {
+				chainIds[info.ChainID.String()] = true
+				if info.LatestBlock > highest {
+					highest = info.LatestBlock
+				}
+			}
+		}
+
+		if !globalOptJsonl && !globalOptTerseOutput {
+			fmt.Printf("%-4v %-40v %8v %-28v %10v %7v %8v %-8v %-12v %9v\n",
+				"rank", "endpoint", "chain", "client", "block", "behind", "age", "syncing", "txpool", "latency")
+		}
+		for i, info := range infos {
+			if info.Err != nil {
+				if !printJSONL(map[string]any{"endpoint": info.Endpoint, "error": info.Err.Error()}) {
+					fmt.Printf("%-4v %-40v unreachable: %v\n", i+1, info.Endpoint, info.Err)
+				}
+				continue
+			}
+			var age = "-"
+			if !info.BlockTime.IsZero() {
+				age = time.Since(info.BlockTime).Round(time.Second).String()
+			}
+			var syncing = "no"
+			if info.Syncing {
+				syncing = fmt.Sprintf("%v/%v", info.SyncCurrent, info.SyncHighest)
+			}
+			if printJSONL(map[string]any{
+				"endpoint":       info.Endpoint,
+				"chain_id":       info.ChainID,
+				"client_version": info.ClientVersion,
+				"latest_block":   info.LatestBlock,
+				"blocks_behind":  info.BlocksBehind(highest),
+				"block_time":     info.BlockTime.Unix(),
+				"syncing":        info.Syncing,
+				"txpool_pending": info.TxpoolPending,
+				"txpool_queued":  info.TxpoolQueued,
+				"latency_ms":     info.Latency.Milliseconds(),
+			}) {
+				continue
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v %v\n", info.Endpoint, info.Latency.Round(time.Millisecond))
+				continue
+			}
+			fmt.Printf("%-4v %-40v %8v %-28v %10v %7v %8v %-8v %-12v %9v\n",
+				i+1, info.Endpoint, info.ChainID, info.ClientVersion, info.LatestBlock, info.BlocksBehind(highest), age, syncing,
+				formatOptionalUint(info.TxpoolPending)+"/"+formatOptionalUint(info.TxpoolQueued), info.Latency.Round(time.Millisecond))
+		}
+
+		if len(chainIds) > 1 {
+			log.Printf("WARNING: endpoints are on different chains")
+		}
+		if len(endpoints) > 1 && infos[0].Err == nil && !globalOptJsonl && !globalOptTerseOutput {
+			fmt.Printf("healthiest: %v\n", infos[0].Endpoint)
+		}
+	},
+}
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var safeThreshold uint64
+
+func init() {
+	safeAddOwnerCmd.Flags().Uint64VarP(&safeThreshold, "threshold", "", 0, "the new threshold, 0 means keep current threshold")
+	safeRemoveOwnerCmd.Flags().Uint64VarP(&safeThreshold, "threshold", "", 0, "the new threshold, 0 means keep current threshold (decreased if it exceeds the number of remaining owners)")
+
+	safeCmd.AddCommand(safeOwnersCmd)
+	safeCmd.AddCommand(safeSwapOwnerCmd)
+	safeCmd.AddCommand(safeAddOwnerCmd)
+	safeCmd.AddCommand(safeRemoveOwnerCmd)
+	safeCmd.AddCommand(safeChangeThresholdCmd)
+
+	addFlags(safeCmd, txFlags)
+}
+
+var safeCmd = &cobra.Command{
+	Use:   "safe",
+	Short: "Gnosis Safe helpers, e.g. rotate owners, sign, propose and execute safe tx",
+	Long: "Gnosis Safe helpers, e.g. rotate owners and threshold. " +
+		"If threshold of safe is 1 and --private-key is an owner, the safe tx is executed directly, " +
+		"otherwise the safe tx and its hash are printed for owners to confirm.",
+}
+
+// validateSafeArgs checks all args are valid eth addresses, the first n args are required.
+func validateSafeArgs(n int, names string) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != n {
+			return fmt.Errorf("requires %v", names)
+		}
+		for i := range args {
+			if !isValidAddressArg(cmd.Context(), args[i]) {
+				return fmt.Errorf("%v is not a valid eth address", args[i])
+			}
+		}
+		return nil
+	}
+}
+
+var safeOwnersCmd = &cobra.Command{
+	Use:   "owners safe-address",
+	Short: "Show owners and threshold of safe",
+	Args:  validateSafeArgs(1, "safe-address"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		owners, err := ethutil.SafeGetOwners(cmd.Context(), globalClient.EthClient, safe)
+		checkErr(err)
+		threshold, err := ethutil.SafeGetThreshold(cmd.Context(), globalClient.EthClient, safe)
+		checkErr(err)
+
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", threshold)
+		} else {
+			fmt.Printf("threshold: %v of %v\n", threshold, len(owners))
+		}
+		for _, owner := range owners {
+			fmt.Printf("%v\n", owner.Hex())
+		}
+	},
+}
+
+var safeSwapOwnerCmd = &cobra.Command{
+	Use:   "swap-owner safe-address old-owner new-owner",
+	Short: "Replace old-owner of safe with new-owner",
+	Args:  validateSafeArgs(3, "safe-address, old-owner and new-owner"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		owners, err := ethutil.SafeGetOwners(cmd.Context(), globalClient.EthClient, safe)
+		checkErr(err)
+
+		data, err := ethutil.SafeSwapOwnerData(owners, mustParseAddressArg(cmd.Context(), args[1]), mustParseAddressArg(cmd.Context(), args[2]))
+		checkErr(err)
+		execSafeTx(cmd.Context(), safe, safe, data)
+	},
+}
+
+var safeAddOwnerCmd = &cobra.Command{
+	Use:   "add-owner safe-address new-owner",
+	Short: "Add new-owner to safe, and optionally change threshold",
+	Args:  validateSafeArgs(2, "safe-address and new-owner"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		threshold := safeThreshold
+		if threshold == 0 {
+			var err error
+			threshold, err = ethutil.SafeGetThreshold(cmd.Context(), globalClient.EthClient, safe)
+			checkErr(err)
+		}
+
+		data, err := ethutil.SafeAddOwnerData(mustParseAddressArg(cmd.Context(), args[1]), threshold)
+		checkErr(err)
+		execSafeTx(cmd.Context(), safe, safe, data)
+	},
+}
+
+var safeRemoveOwnerCmd = &cobra.Command{
+	Use:   "remove-owner safe-address owner",
+	Short: "Remove owner from safe, and optionally change threshold",
+	Args:  validateSafeArgs(2, "safe-address and owner"),
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		owners, err := ethutil.SafeGetOwners(cmd.Context(), globalClient.EthClient, safe)
+		checkErr(err)
+		if len(owners) <= 1 {
+			log.Fatalf("can not remove the last owner of safe")
+		}
+
+		threshold := safeThreshold
+		if threshold == 0 {
+			threshold, err = ethutil.SafeGetThreshold(cmd.Context(), globalClient.EthClient, safe)
+			checkErr(err)
+			if threshold > uint64(len(owners)-1) {
+				threshold = uint64(len(owners) - 1)
+				log.Printf("threshold is decreased to %v", threshold)
+			}
+		}
+
+		data, err := ethutil.SafeRemoveOwnerData(owners, mustParseAddressArg(cmd.Context(), args[1]), threshold)
+		checkErr(err)
+		execSafeTx(cmd.Context(), safe, safe, data)
+	},
+}
+
+var safeChangeThresholdCmd = &cobra.Command{
+	Use:   "change-threshold safe-address threshold",
+	Short: "Change threshold of safe",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("requires safe-address and threshold")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		if _, err := strconv.ParseUint(args[1], 10, 64); err != nil {
+			return fmt.Errorf("%v is not a valid threshold", args[1])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		threshold, _ := strconv.ParseUint(args[1], 10, 64)
+		data, err := ethutil.SafeChangeThresholdData(threshold)
+		checkErr(err)
+
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		execSafeTx(cmd.Context(), safe, safe, data)
+	},
+}
+
+// execSafeTx executes a safe tx (call to with data) directly if threshold is 1 and --private-key is an owner,
+// otherwise prints the safe tx and its hash for owners to confirm.
+func execSafeTx(ctx context.Context, safe common.Address, to common.Address, data []byte) {
+	threshold, err := ethutil.SafeGetThreshold(ctx, globalClient.EthClient, safe)
+	checkErr(err)
+	owners, err := ethutil.SafeGetOwners(ctx, globalClient.EthClient, safe)
+	checkErr(err)
+
+	var owner common.Address
+	var isOwner bool
+	if globalOptPrivateKey != "" {
+		owner = extractAddressFromPrivateKey(buildPrivateKeyFromHex(globalOptPrivateKey))
+		for _, o := range owners {
+			if o == owner {
+				isOwner = true
+				break
+			}
+		}
+	}
+
+	if threshold == 1 && isOwner {
+		execData, err := ethutil.SafeExecTransactionData(ethutil.NewSafeTx(to, big.NewInt(0), data, nil), ethutil.SafePreValidatedSignature(owner))
+		checkErr(err)
+
+		tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &safe, big.NewInt(0), nil, execData)
+		checkErr(err)
+
+		log.Printf("transaction %s finished", tx)
+		return
+	}
+
+	nonce, err := ethutil.SafeGetNonce(ctx, globalClient.EthClient, safe)
+	checkErr(err)
+	safeTxHash, err := ethutil.SafeGetTransactionHash(ctx, globalClient.EthClient, safe, to, big.NewInt(0), data, nonce)
+	checkErr(err)
+
+	if !globalOptTerseOutput {
+		log.Printf("threshold of safe is %v, the following safe tx must be confirmed by owners", threshold)
+		fmt.Printf("to: %v\n", to.Hex())
+		fmt.Printf("value: 0\n")
+		fmt.Printf("data: %v\n", hexutil.Encode(data))
+		fmt.Printf("operation: 0\n")
+		fmt.Printf("nonce: %v\n", nonce)
+		fmt.Printf("safe tx hash: %v\n", safeTxHash.Hex())
+	} else {
+		fmt.Printf("%v %v\n", hexutil.Encode(data), safeTxHash.Hex())
+	}
+}
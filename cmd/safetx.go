@@ -0,0 +1,433 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var safeTxValue string
+var safeTxUnit string
+var safeTxHexData string
+var safeTxOperation string
+var safeTxNonce int64
+var safeTxChainId int64
+var safeTxServiceUrl string
+var safeTxSignatures []string
+
+func init() {
+	for _, cmd := range []*cobra.Command{safeTxHashCmd, safeSignCmd, safeProposeCmd, safeExecCmd} {
+		cmd.Flags().StringVarP(&safeTxValue, "value", "", "0", "the amount of eth sent by safe, unit is ether and can be changed by --unit")
+		cmd.Flags().StringVarP(&safeTxUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
+		cmd.Flags().StringVarP(&safeTxHexData, "hex-data", "", "", "the payload hex data of safe tx")
+		cmd.Flags().StringVarP(&safeTxOperation, "operation", "", "call", "call | delegatecall, the operation of safe tx")
+		cmd.Flags().Int64VarP(&safeTxNonce, "safe-nonce", "", -1, "the nonce of safe tx, -1 means query nonce of safe online")
+		cmd.Flags().Int64VarP(&safeTxChainId, "chain-id", "", 0, "the chain id, 0 means query it online")
+	}
+	for _, cmd := range []*cobra.Command{safeProposeCmd, safeConfirmCmd, safeExecCmd, safeStatusCmd} {
+		cmd.Flags().StringVarP(&safeTxServiceUrl, "tx-service-url", "", "", "the url of Safe Transaction Service, default is the official service of current chain")
+	}
+	safeExecCmd.Flags().StringSliceVarP(&safeTxSignatures, "signatures", "", nil, "the signatures of owners (created by safe sign), comma separated. default is confirmations in Safe Transaction Service")
+
+	safeCmd.AddCommand(safeTxHashCmd)
+	safeCmd.AddCommand(safeSignCmd)
+	safeCmd.AddCommand(safeCombineCmd)
+	safeCmd.AddCommand(safeProposeCmd)
+	safeCmd.AddCommand(safeConfirmCmd)
+	safeCmd.AddCommand(safeExecCmd)
+	safeCmd.AddCommand(safeStatusCmd)
+}
+
+// validateSafeTxArgs checks args are safe-address and to-address, and flags of safe tx are valid.
+func validateSafeTxArgs(cmd *cobra.Command, args []string) error {
+	if err := validateSafeArgs(2, "safe-address and to-address")(cmd, args); err != nil {
+		return err
+	}
+	if !isValidHexString(safeTxHexData) {
+		return fmt.Errorf("--hex-data must hex string")
+	}
+	if _, err := decimal.NewFromString(safeTxValue); err != nil {
+		return fmt.Errorf("%v is not a valid amount", safeTxValue)
+	}
+	if safeTxOperation != "call" && safeTxOperation != "delegatecall" {
+		return fmt.Errorf("invalid --operation %v", safeTxOperation)
+	}
+	return nil
+}
+
+// buildSafeTx builds safe tx of safe calling to by flags, and computes its hash. No node is needed if --safe-nonce and
+// --chain-id are specified.
+func buildSafeTx(ctx context.Context, safe common.Address, to common.Address) (*ethutil.SafeTx, common.Hash, *big.Int) {
+	if globalClient == nil && (safeTxNonce < 0 || safeTxChainId <= 0) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(ctx, globalOptNodeUrl)
+	}
+
+	chainID := big.NewInt(safeTxChainId)
+	if safeTxChainId <= 0 {
+		var err error
+		chainID, err = globalClient.EthClient.ChainID(ctx)
+		checkErr(err)
+	}
+	nonce := big.NewInt(safeTxNonce)
+	if safeTxNonce < 0 {
+		var err error
+		nonce, err = ethutil.SafeGetNonce(ctx, globalClient.EthClient, safe)
+		checkErr(err)
+	}
+
+	value := unify2Wei(decimal.RequireFromString(safeTxValue), safeTxUnit).BigInt()
+	safeTx := ethutil.NewSafeTx(to, value, common.FromHex(safeTxHexData), nonce)
+	if safeTxOperation == "delegatecall" {
+		safeTx.Operation = 1
+	}
+
+	domainSeparator, err := ethutil.SafeDomainSeparator(chainID, safe)
+	checkErr(err)
+	safeTxHash, err := safeTx.Hash(domainSeparator)
+	checkErr(err)
+	return safeTx, safeTxHash, chainID
+}
+
+// printSafeTx prints fields of safe tx and its hash.
+func printSafeTx(safeTx *ethutil.SafeTx, safeTxHash common.Hash) {
+	fmt.Printf("to: %v\n", safeTx.To.Hex())
+	fmt.Printf("value: %v\n", safeTx.Value)
+	fmt.Printf("data: %v\n", hexutil.Encode(safeTx.Data))
+	fmt.Printf("operation: %v\n", safeTx.Operation)
+	fmt.Printf("nonce: %v\n", safeTx.Nonce)
+	fmt.Printf("safe tx hash: %v\n", safeTxHash.Hex())
+}
+
+// getSafeTxServiceUrl returns --tx-service-url, or the official Safe Transaction Service of chain.
+func getSafeTxServiceUrl(ctx context.Context, chainID *big.Int) string {
+	if safeTxServiceUrl != "" {
+		return safeTxServiceUrl
+	}
+	if chainID == nil {
+		if globalClient == nil {
+			log.Printf("Current network is %v", globalOptNode)
+
+			InitGlobalClient(ctx, globalOptNodeUrl)
+		}
+		var err error
+		chainID, err = globalClient.EthClient.ChainID(ctx)
+		checkErr(err)
+	}
+	url, ok := ethutil.SafeTxServiceUrls[chainID.Int64()]
+	if !ok {
+		log.Fatalf("Safe Transaction Service of chain %v is unknown, please specify --tx-service-url", chainID)
+	}
+	return url
+}
+
+// signSafeTxHash signs safe tx hash with --private-key, returns the signature and the signer. The safe tx is evaluated
+// by --policy, safeTx nil means it's unknown, which is refused by --policy.
+func signSafeTxHash(ctx context.Context, safeTx *ethutil.SafeTx, chainID *big.Int, safeTxHash common.Hash) ([]byte, common.Address) {
+	if globalOptPrivateKey == "" {
+		log.Fatalf("--private-key is required to sign safe tx")
+	}
+	privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+	if safeTx == nil {
+		checkNoPolicy("a safe tx hash")
+	} else if globalOptPolicy != "" {
+		if safeTx.Operation != 0 {
+			// the code of to is executed in the context of safe, policy rules can't tell what it does
+			checkNoPolicy("a delegatecall safe tx")
+		}
+		checkPolicy(ctx, nil, chainID, &safeTx.To, safeTx.Value, safeTx.Data)
+	}
+	checkTOTP()
+	signature, err := ethutil.SafeSignTxHash(safeTxHash, privateKey)
+	checkErr(err)
+	return signature, extractAddressFromPrivateKey(privateKey)
+}
+
+// parseSafeSignatures parses hex signatures, each of them is one or more (combined) 65 bytes signatures.
+func parseSafeSignatures(signatures []string) ([][]byte, error) {
+	var result [][]byte
+	for _, signature := range signatures {
+		if !isValidHexString(signature) {
+			return nil, fmt.Errorf("signature %v must hex string", signature)
+		}
+		data := common.FromHex(signature)
+		if len(data) == 0 || len(data)%65 != 0 {
+			return nil, fmt.Errorf("signature %v must be multiple of 65 bytes, got %v bytes", signature, len(data))
+		}
+		for i := 0; i < len(data); i += 65 {
+			result = append(result, data[i:i+65])
+		}
+	}
+	return result, nil
+}
+
+var safeTxHashCmd = &cobra.Command{
+	Use:   "tx-hash safe-address to-address",
+	Short: "Compute EIP-712 hash of safe tx, no node is needed if --safe-nonce and --chain-id are specified",
+	Args:  validateSafeTxArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		safeTx, safeTxHash, _ := buildSafeTx(cmd.Context(), mustParseAddressArg(cmd.Context(), args[0]), mustParseAddressArg(cmd.Context(), args[1]))
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", safeTxHash.Hex())
+			return
+		}
+		printSafeTx(safeTx, safeTxHash)
+	},
+}
+
+var safeSignCmd = &cobra.Command{
+	Use:   "sign safe-address to-address",
+	Short: "Sign safe tx with --private-key (owner), no node is needed if --safe-nonce and --chain-id are specified",
+	Args:  validateSafeTxArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		safeTx, safeTxHash, chainID := buildSafeTx(cmd.Context(), mustParseAddressArg(cmd.Context(), args[0]), mustParseAddressArg(cmd.Context(), args[1]))
+		signature, owner := signSafeTxHash(cmd.Context(), safeTx, chainID, safeTxHash)
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", hexutil.Encode(signature))
+			return
+		}
+		printSafeTx(safeTx, safeTxHash)
+		fmt.Printf("owner: %v\n", owner.Hex())
+		fmt.Printf("signature: %v\n", hexutil.Encode(signature))
+	},
+}
+
+var safeCombineCmd = &cobra.Command{
+	Use:   "combine safe-tx-hash signature ...",
+	Short: "Combine signatures of owners (sorted by owner) for execTransaction",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("requires safe-tx-hash and signatures")
+		}
+		if _, err := parseHashes(args[:1]); err != nil {
+			return err
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		signatures, err := parseSafeSignatures(args[1:])
+		checkErr(err)
+		safeTxHash := common.HexToHash(args[0])
+		combined, err := ethutil.SafeCombineSignatures(safeTxHash, signatures)
+		checkErr(err)
+		if !globalOptTerseOutput {
+			for _, signature := range signatures {
+				owner, _ := ethutil.SafeSignatureOwner(safeTxHash, signature)
+				log.Printf("signed by %v", owner.Hex())
+			}
+		}
+		fmt.Printf("%v\n", hexutil.Encode(combined))
+	},
+}
+
+var safeProposeCmd = &cobra.Command{
+	Use:   "propose safe-address to-address",
+	Short: "Sign safe tx with --private-key (owner) and post it to Safe Transaction Service for other owners to confirm",
+	Args:  validateSafeTxArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		safe := mustParseAddressArg(cmd.Context(), args[0])
+		safeTx, safeTxHash, chainID := buildSafeTx(cmd.Context(), safe, mustParseAddressArg(cmd.Context(), args[1]))
+		signature, owner := signSafeTxHash(cmd.Context(), safeTx, chainID, safeTxHash)
+
+		checkErr(ethutil.SafeProposeTx(cmd.Context(), getSafeTxServiceUrl(cmd.Context(), chainID), safe, safeTx, safeTxHash, owner, signature))
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", safeTxHash.Hex())
+			return
+		}
+		printSafeTx(safeTx, safeTxHash)
+		log.Printf("safe tx is proposed by %v", owner.Hex())
+	},
+}
+
+var safeConfirmCmd = &cobra.Command{
+	Use:   "confirm safe-tx-hash",
+	Short: "Sign safe tx hash with --private-key (owner) and post the confirmation to Safe Transaction Service",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires safe-tx-hash")
+		}
+		_, err := parseHashes(args)
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		safeTxHash := common.HexToHash(args[0])
+		serviceUrl := getSafeTxServiceUrl(cmd.Context(), nil)
+		signature, owner := signSafeTxHash(cmd.Context(), nil, nil, safeTxHash)
+
+		checkErr(ethutil.SafeConfirmTx(cmd.Context(), serviceUrl, safeTxHash, signature))
+		if !globalOptTerseOutput {
+			log.Printf("safe tx %v is confirmed by %v", safeTxHash.Hex(), owner.Hex())
+		}
+	},
+}
+
+var safeExecCmd = &cobra.Command{
+	Use:   "exec safe-address to-address",
+	Short: "Execute safe tx by execTransaction with signatures of owners, the tx is sent by --private-key",
+	Args:  validateSafeTxArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required to send execTransaction")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		safe := mustParseAddressArg(ctx, args[0])
+		safeTx, safeTxHash, chainID := buildSafeTx(ctx, safe, mustParseAddressArg(ctx, args[1]))
+
+		var signatures [][]byte
+		var err error
+		if len(safeTxSignatures) > 0 {
+			signatures, err = parseSafeSignatures(safeTxSignatures)
+		} else {
+			signatures, err = ethutil.SafeTxConfirmations(ctx, getSafeTxServiceUrl(ctx, chainID), safeTxHash)
+		}
+		checkErr(err)
+
+		owners, err := ethutil.SafeGetOwners(ctx, globalClient.EthClient, safe)
+		checkErr(err)
+		threshold, err := ethutil.SafeGetThreshold(ctx, globalClient.EthClient, safe)
+		checkErr(err)
+		for _, signature := range signatures {
+			owner, err := ethutil.SafeSignatureOwner(safeTxHash, signature)
+			checkErr(err)
+			if !containsAddress(owners, owner) {
+				log.Fatalf("%v is not an owner of safe, its signature is invalid", owner.Hex())
+			}
+		}
+		if uint64(len(signatures)) < threshold {
+			log.Fatalf("threshold of safe is %v, but only %v signatures are provided", threshold, len(signatures))
+		}
+		combined, err := ethutil.SafeCombineSignatures(safeTxHash, signatures)
+		checkErr(err)
+
+		execData, err := ethutil.SafeExecTransactionData(safeTx, combined)
+		checkErr(err)
+		tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &safe, big.NewInt(0), nil, execData)
+		checkErr(err)
+
+		log.Printf("transaction %s finished", tx)
+	},
+}
+
+var safeStatusCmd = &cobra.Command{
+	Use:   "status safe-address safe-tx-hash",
+	Short: "Show which owners approved safe tx hash (on chain or in Safe Transaction Service) and how many more signatures are needed",
+	Long: "Show which owners approved safe tx hash and how many more signatures are needed to reach threshold. " +
+		"An owner approves on chain by approveHash (approvedHashes is not zero), or off chain by confirming it in " +
+		"Safe Transaction Service, whose signatures are verified. The owner sending execTransaction needs no signature.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("requires safe-address and safe-tx-hash")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		_, err := parseHashes(args[1:])
+		return err
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		safe := mustParseAddressArg(ctx, args[0])
+		safeTxHash := common.HexToHash(args[1])
+		owners, err := ethutil.SafeGetOwners(ctx, globalClient.EthClient, safe)
+		checkErr(err)
+		threshold, err := ethutil.SafeGetThreshold(ctx, globalClient.EthClient, safe)
+		checkErr(err)
+
+		var offChain = make(map[common.Address]bool)
+		var serviceTx *ethutil.SafeServiceTx
+		chainID, err := globalClient.EthClient.ChainID(ctx)
+		checkErr(err)
+		if _, ok := ethutil.SafeTxServiceUrls[chainID.Int64()]; !ok && safeTxServiceUrl == "" {
+			log.Printf("Safe Transaction Service of chain %v is unknown, only on-chain approvals are shown", chainID)
+		} else if serviceTx, err = ethutil.SafeGetServiceTx(ctx, getSafeTxServiceUrl(ctx, chainID), safeTxHash); err != nil {
+			log.Printf("WARNING: get safe tx from Safe Transaction Service fail, only on-chain approvals are shown: %v", err)
+		} else {
+			if serviceTx.Safe != safe {
+				log.Fatalf("safe tx %v belongs to safe %v, not %v", safeTxHash.Hex(), serviceTx.Safe.Hex(), safe.Hex())
+			}
+			for _, confirmation := range serviceTx.Confirmations {
+				owner, err := ethutil.SafeSignatureOwner(safeTxHash, confirmation.Signature)
+				if err != nil || owner != confirmation.Owner {
+					log.Printf("WARNING: signature of %v in Safe Transaction Service is not verified, ignore it", confirmation.Owner.Hex())
+					continue
+				}
+				offChain[owner] = true
+			}
+		}
+
+		var approvals []ethutil.SafeOwnerApproval
+		for _, owner := range owners {
+			onChain, err := ethutil.SafeApprovedHash(ctx, globalClient.EthClient, safe, owner, safeTxHash)
+			checkErr(err)
+			approvals = append(approvals, ethutil.SafeOwnerApproval{Owner: owner, OnChain: onChain, OffChain: offChain[owner]})
+		}
+		approved, missing := ethutil.SafeApprovalCount(approvals, threshold)
+
+		var result = map[string]any{"safe_tx_hash": safeTxHash.Hex(), "threshold": threshold, "approved": approved, "missing": missing}
+		var onChainOwners, offChainOwners = []string{}, []string{}
+		for _, approval := range approvals {
+			if approval.OnChain {
+				onChainOwners = append(onChainOwners, approval.Owner.Hex())
+			}
+			if approval.OffChain {
+				offChainOwners = append(offChainOwners, approval.Owner.Hex())
+			}
+		}
+		result["on_chain"], result["off_chain"] = onChainOwners, offChainOwners
+		if serviceTx != nil && serviceTx.IsExecuted {
+			result["executed"] = true
+		}
+		if printJSONL(result) {
+			return
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", missing)
+			return
+		}
+
+		fmt.Printf("%-42v %-9v %v\n", "owner", "on-chain", "off-chain")
+		for _, approval := range approvals {
+			fmt.Printf("%-42v %-9v %v\n", approval.Owner.Hex(), approval.OnChain, approval.OffChain)
+		}
+		fmt.Printf("approved: %v of %v owners, threshold %v, %v more signatures needed\n", approved, len(owners), threshold, missing)
+		if serviceTx != nil && serviceTx.IsExecuted {
+			if serviceTx.TransactionHash != nil {
+				fmt.Printf("safe tx is executed by tx %v\n", serviceTx.TransactionHash.Hex())
+			} else {
+				fmt.Printf("safe tx is executed\n")
+			}
+		} else if serviceTx != nil {
+			nonce, err := ethutil.SafeGetNonce(ctx, globalClient.EthClient, safe)
+			checkErr(err)
+			if nonce.Uint64() > serviceTx.Nonce {
+				fmt.Printf("safe nonce %v is used by another safe tx, this safe tx can not be executed\n", serviceTx.Nonce)
+			}
+		}
+	},
+}
+
+// containsAddress returns true if addr is in addresses.
+func containsAddress(addresses []common.Address, addr common.Address) bool {
+	for _, a := range addresses {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
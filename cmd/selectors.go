@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var selectorsNoLookup bool
+var selectorsNoFollowProxy bool
+
+func init() {
+	selectorsCmd.Flags().BoolVarP(&selectorsNoLookup, "no-lookup", "", false, "do not resolve selectors to function signatures by https://openchain.xyz/signatures")
+	selectorsCmd.Flags().BoolVarP(&selectorsNoFollowProxy, "no-follow-proxy", "", false, "list selectors of the proxy itself, instead of its implementation")
+
+	addFlags(selectorsCmd, blockFlags)
+}
+
+var selectorsCmd = &cobra.Command{
+	Use:   "selectors contract-address|runtime-bytecode",
+	Short: "List function selectors in the dispatcher of bytecode, i.e. the probable interface of unverified contract",
+	Long: "Extract 4 bytes function selectors from the function dispatcher of runtime bytecode, and resolve them to\n" +
+		"function signatures by https://openchain.xyz/signatures. The bytecode of contract address is read at --block,\n" +
+		"and the bytecode of implementation is used if the contract is a known proxy.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires contract-address or runtime-bytecode")
+		}
+		if !isValidEthAddress(args[0]) && !isValidHexString(args[0]) {
+			return fmt.Errorf("%v is not a valid eth address or hex string", args[0])
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		var code []byte
+		if isValidEthAddress(args[0]) {
+			log.Printf("Current network is %v", globalOptNode)
+			InitGlobalClient(ctx, globalOptNodeUrl)
+			block := stateBlock(ctx)
+			address := common.HexToAddress(args[0])
+			if !selectorsNoFollowProxy {
+				if info, err := ethutil.DetectProxy(ctx, globalClient.EthClient, address, block); err == nil && info.Kind != ethutil.ProxyKindNone {
+					log.Printf("%v is a %v proxy, list selectors of implementation %v", address.Hex(), info.Kind, info.Implementation.Hex())
+					address = info.Implementation
+				}
+			}
+			var err error
+			code, err = globalClient.EthClient.CodeAt(ctx, address, block)
+			checkErr(err)
+			if len(code) == 0 {
+				log.Fatalf("no runtime bytecode found for %v, it is not a deployed contract", address.Hex())
+			}
+		} else {
+			code = hexutil.MustDecode(args[0])
+		}
+
+		selectors := ethutil.ExtractSelectors(code)
+		if len(selectors) == 0 {
+			log.Printf("no function selector found, the dispatcher is not recognized")
+			return
+		}
+		var cache = make(funcSigCache)
+		for _, selector := range selectors {
+			var sigs []string
+			if !selectorsNoLookup {
+				sigs = cache.lookup(selector)
+			}
+			if printJSONL(map[string]any{jsonlKeySelector: selector, "signatures": sigs}) {
+				continue
+			}
+			if globalOptTerseOutput || selectorsNoLookup {
+				fmt.Printf("%v\n", selector)
+				continue
+			}
+			if len(sigs) == 0 {
+				fmt.Printf("%v unknown\n", selector)
+				continue
+			}
+			fmt.Printf("%v %v\n", selector, strings.Join(sigs, " | "))
+		}
+	},
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var sendRawFile string
+var sendRawWait bool
+var sendRawForce bool
+
+// sendRawChainID is the chain id of node, it's queried once for all txs read from --stdin
+var sendRawChainID *big.Int
+
+func init() {
+	sendRawCmd.Flags().StringVarP(&sendRawFile, "file", "f", "", "read signed tx from this file, file - means read stdin")
+	sendRawCmd.Flags().BoolVarP(&sendRawWait, "wait", "", false, "wait for the receipt of tx")
+	sendRawCmd.Flags().BoolVarP(&sendRawForce, "force", "", false, "broadcast even if chain id or nonce of tx does not match the node, i.e. the tx is expected to be rejected")
+
+	addFlags(sendRawCmd, txFlags)
+}
+
+var sendRawCmd = &cobra.Command{
+	Use:     "send-raw [signed-tx]",
+	Aliases: []string{"broadcast"},
+	Short:   "Broadcast signed raw tx, the signed tx can be read from argument, file or stdin",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("too many args")
+		}
+		if globalOptStdin && (len(args) > 0 || sendRawFile != "") {
+			return fmt.Errorf("signed-tx and --file can not be specified with --stdin")
+		}
+		if len(args) == 1 && sendRawFile != "" {
+			return fmt.Errorf("signed-tx and --file can not be specified at the same time")
+		}
+		if len(args) == 1 && !isValidHexString(args[0]) {
+			return fmt.Errorf("signed-tx must hex string")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		if globalOptStdin {
+			// broadcast each signed tx as soon as it's read
+			forEachInput(nil, []string{jsonlKeyRawTx}, func(rawTx string) {
+				sendRaw(cmd.Context(), rawTx)
+			})
+			return
+		}
+
+		var rawTx string
+		if len(args) == 1 {
+			rawTx = args[0]
+		} else {
+			var err error
+			rawTx, err = readRawTx(sendRawFile)
+			checkErr(err)
+		}
+		sendRaw(cmd.Context(), rawTx)
+	},
+}
+
+// sendRaw broadcasts signed tx, and waits for its receipt if --wait is specified.
+func sendRaw(ctx context.Context, rawTx string) {
+	signedTx, err := ethutil.ParseRawTx(rawTx)
+	checkErr(err)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(signedTx.ChainId()), signedTx)
+	checkErr(err)
+	checkBroadcast(ctx, signedTx, sender)
+
+	checkApproval(signedTx)
+	txHash, err := broadcastTx(ctx, globalClient, signedTx)
+	checkErr(err)
+	if globalFormatTemplate == nil && !globalOptJsonl {
+		fmt.Printf("%v\n", txHash.Hex())
+	}
+
+	if !sendRawWait {
+		if !printJSONL(map[string]any{jsonlKeyTxHash: txHash.Hex()}) {
+			printFormatted(newTxResult(signedTx, sender, nil))
+		}
+		return
+	}
+
+	rp, err := ethutil.WaitReceipt(ctx, globalClient, *txHash, waitOptions())
+	checkErr(err)
+	if !printJSONL(map[string]any{jsonlKeyTxHash: txHash.Hex(), "status": rp.Status, "block_number": rp.BlockNumber, "gas_used": rp.GasUsed}) {
+		printFormatted(newTxResult(signedTx, sender, rp))
+	}
+
+	if rp.Status != types.ReceiptStatusSuccessful {
+		log.Fatalf("tx %v failed in block %v", txHash.Hex(), rp.BlockNumber)
+	}
+	log.Printf("tx %v succeeded in block %v", txHash.Hex(), rp.BlockNumber)
+}
+
+// checkBroadcast warns about chain id and nonce of signed tx which do not match the node, and exits if the tx is
+// expected to be rejected unless --force is specified.
+func checkBroadcast(ctx context.Context, signedTx *types.Transaction, sender common.Address) {
+	if sendRawChainID == nil {
+		var err error
+		sendRawChainID, err = globalClient.EthClient.ChainID(ctx)
+		checkErr(err)
+	}
+	issues, err := ethutil.QueryBroadcastIssues(ctx, globalClient.EthClient, signedTx, sender, sendRawChainID)
+	checkErr(err)
+
+	var fatal bool
+	for _, issue := range issues {
+		log.Printf("WARNING: tx %v: %v", signedTx.Hash().Hex(), issue.Message)
+		fatal = fatal || issue.Fatal
+	}
+	if fatal && !sendRawForce {
+		log.Fatalf("tx %v is not broadcast as it will be rejected, use --force to broadcast it anyway", signedTx.Hash().Hex())
+	}
+}
+
+// readRawTx reads raw tx from file, file - or empty means read stdin.
+func readRawTx(file string) (string, error) {
+	var inputReader = os.Stdin
+	if file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return "", fmt.Errorf("failed open file: %w", err)
+		}
+		defer f.Close()
+		inputReader = f
+	}
+
+	content, err := io.ReadAll(inputReader)
+	if err != nil {
+		return "", err
+	}
+	rawTx := strings.TrimSpace(string(content))
+	if !isValidHexString(rawTx) {
+		return "", fmt.Errorf("signed tx must hex string")
+	}
+	return rawTx, nil
+}
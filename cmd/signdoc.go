@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+var signDocChainId uint64
+var signDocOutput string
+var verifyDocSigners []string
+var verifyDocChainId uint64
+
+func init() {
+	signDocCmd.Flags().Uint64VarP(&signDocChainId, "chain-id", "", 0, "the chain id bound to signature, 0 means query it online")
+	signDocCmd.Flags().StringVarP(&signDocOutput, "output", "o", "", "the output envelope file, default is stdout")
+	verifyDocCmd.Flags().StringSliceVarP(&verifyDocSigners, "signer", "", nil, "the expected signers (e.g. registered operators), comma separated. any signer is accepted if not specified")
+	verifyDocCmd.Flags().Uint64VarP(&verifyDocChainId, "chain-id", "", 0, "the expected chain id, any chain id is accepted if not specified")
+
+	addFlags(signDocCmd, txFlags, "policy", "totp-file")
+}
+
+var signDocCmd = &cobra.Command{
+	Use:   "sign-doc document.json",
+	Short: "Sign JSON document, the detached signature envelope bundles the signer and the chain id",
+	Long: "Sign JSON document (e.g. operator registration, off-chain governance attestation), the detached signature " +
+		"envelope bundles the signer and the chain id. keccak256 of canonical JSON (compact with sorted keys) of document " +
+		"is signed by EIP191 personal sign, so reformatting the document does not invalidate the signature.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := os.ReadFile(args[0])
+		checkErr(err)
+		if _, err := ethutil.CanonicalJSON(document); err != nil {
+			log.Fatalf("%v: %v", args[0], err)
+		}
+
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for this command")
+		}
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+
+		chainID := signDocChainId
+		if chainID == 0 {
+			log.Printf("Current network is %v", globalOptNode)
+
+			InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+			id, err := globalClient.EthClient.ChainID(cmd.Context())
+			checkErr(err)
+			chainID = id.Uint64()
+		}
+
+		checkNoPolicy("a document")
+		checkTOTP()
+		envelope, err := ethutil.SignDocument(document, chainID, privateKey, time.Now())
+		checkErr(err)
+		content, err := json.MarshalIndent(envelope, "", "  ")
+		checkErr(err)
+		if signDocOutput == "" {
+			fmt.Printf("%s\n", content)
+			return
+		}
+		checkErr(os.WriteFile(signDocOutput, append(content, '\n'), 0644))
+		log.Printf("envelope signed by %v for chain %v is written to %v", envelope.Signer.Hex(), chainID, signDocOutput)
+	},
+}
+
+var verifyDocCmd = &cobra.Command{
+	Use:   "verify-doc document.json envelope.json",
+	Short: "Verify the signature envelope of JSON document created by sign-doc, exit with 1 if it's invalid",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("requires document.json and envelope.json")
+		}
+		for _, signer := range verifyDocSigners {
+			if !isValidEthAddress(signer) {
+				return fmt.Errorf("--signer %v is not a valid eth address", signer)
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		document, err := os.ReadFile(args[0])
+		checkErr(err)
+		content, err := os.ReadFile(args[1])
+		checkErr(err)
+		var envelope ethutil.DocumentEnvelope
+		if err := json.Unmarshal(content, &envelope); err != nil {
+			log.Fatalf("parse envelope %v fail: %v", args[1], err)
+		}
+
+		if err := ethutil.VerifyDocument(document, &envelope); err != nil {
+			log.Fatalf("signature is INVALID: %v", err)
+		}
+		if verifyDocChainId != 0 && envelope.ChainID != verifyDocChainId {
+			log.Fatalf("signature is INVALID: it's signed for chain %v, not chain %v", envelope.ChainID, verifyDocChainId)
+		}
+		if len(verifyDocSigners) > 0 {
+			var expected bool
+			for _, signer := range verifyDocSigners {
+				if common.HexToAddress(signer) == envelope.Signer {
+					expected = true
+					break
+				}
+			}
+			if !expected {
+				log.Fatalf("signature is INVALID: signer %v is not any of --signer", envelope.Signer.Hex())
+			}
+		}
+		fmt.Printf("signature is valid, signer address: %v, chain id: %v, signed at: %v\n", envelope.Signer.Hex(), envelope.ChainID, envelope.SignedAt.Format(time.RFC3339))
+	},
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addFlags(signHashCmd, txFlags, "policy", "totp-file")
+}
+
+var signHashCmd = &cobra.Command{
+	Use:   "sign-hash 32-bytes-hash",
+	Short: "Sign 32 bytes digest with --private-key directly (no prefix, no hashing), output r, s, v, 65 bytes and EIP-2098 compact signature",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one 32-bytes-hash")
+		}
+		hash, err := hexutil.Decode(args[0])
+		if err != nil {
+			return fmt.Errorf("hash is not valid hex: %w", err)
+		}
+		if len(hash) != 32 {
+			return fmt.Errorf("hash must be 32 bytes, got %v bytes", len(hash))
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for this command")
+		}
+
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		checkNoPolicy("a raw hash")
+		checkTOTP()
+		signature, err := ethutil.SignHash(hexutil.MustDecode(args[0]), privateKey)
+		checkErr(err)
+		compact, err := ethutil.CompactSignature(signature)
+		checkErr(err)
+
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", hexutil.Encode(signature))
+			return
+		}
+		fmt.Printf("signer address: %v\n", extractAddressFromPrivateKey(privateKey).String())
+		fmt.Printf("signature (r || s || v): %v\n", hexutil.Encode(signature))
+		fmt.Printf("r: %v\n", hexutil.Encode(signature[:32]))
+		fmt.Printf("s: %v\n", hexutil.Encode(signature[32:64]))
+		fmt.Printf("v: %v (recovery id %v)\n", signature[64], signature[64]-27)
+		fmt.Printf("eip2098 compact signature (r || yParityAndS): %v\n", hexutil.Encode(compact))
+		fmt.Printf("yParityAndS: %v\n", hexutil.Encode(compact[32:]))
+	},
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+)
+
+var signTxChainId int64
+var signTxFork string
+var signTxChainConfig string
+
+func init() {
+	signTxCmd.Flags().Int64VarP(&signTxChainId, "chain-id", "", 0, "the chain id, required for eip155 tx because it's not encoded in unsigned eip155 tx")
+	signTxCmd.Flags().StringVarP(&signTxFork, "fork", "", "london", "the fork of chain, which decides signer and allowed tx types: frontier | homestead | spurious-dragon | berlin | london (or later)")
+	signTxCmd.Flags().StringVarP(&signTxChainConfig, "chain-config", "", "", "the chain config or genesis json file, the signer of the latest fork in it is used, conflicts with --fork")
+	signTxCmd.MarkFlagsMutuallyExclusive("fork", "chain-config")
+
+	addFlags(signTxCmd, txFlags, "allow-weak-key", "policy", "policy-signer", "totp-file")
+}
+
+// buildSignTxSigner returns signer of tx by --chain-config or --fork, no node is queried.
+func buildSignTxSigner(tx *types.Transaction) types.Signer {
+	if signTxChainConfig != "" {
+		content, err := os.ReadFile(signTxChainConfig)
+		checkErr(err)
+		signer, err := ethutil.NewChainConfigSigner(content)
+		checkErr(err)
+		if signTxChainId > 0 && signer.ChainID().Cmp(big.NewInt(signTxChainId)) != 0 {
+			log.Fatalf("chain id in chain config is %v, but --chain-id is %v", signer.ChainID(), signTxChainId)
+		}
+		return signer
+	}
+
+	var chainID *big.Int
+	if tx.Type() == types.LegacyTxType {
+		if signTxChainId > 0 {
+			chainID = big.NewInt(signTxChainId)
+		}
+	} else {
+		chainID = tx.ChainId()
+		if signTxChainId > 0 && chainID.Cmp(big.NewInt(signTxChainId)) != 0 {
+			log.Fatalf("chain id of tx is %v, but --chain-id is %v", chainID, signTxChainId)
+		}
+	}
+	signer, err := ethutil.NewForkSigner(signTxFork, chainID)
+	if err != nil && chainID == nil {
+		log.Fatalf("--chain-id is required for eip155 tx")
+	}
+	checkErr(err)
+	return signer
+}
+
+var signTxCmd = &cobra.Command{
+	Use:   "sign-tx unsigned-tx",
+	Short: "Sign unsigned tx (built by build-tx) with --private-key offline",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires unsigned-tx")
+		}
+		if !isValidHexString(args[0]) {
+			return fmt.Errorf("unsigned-tx must hex string")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for sign-tx command")
+		}
+
+		tx, err := ethutil.ParseRawTx(args[0])
+		checkErr(err)
+
+		signer := buildSignTxSigner(tx)
+		chainID := signer.ChainID()
+		if chainID == nil {
+			chainID = big.NewInt(signTxChainId) // pre-EIP155 signer, tx can be replayed on any chain
+		}
+
+		schedule, err := offlineGasSchedule(chainID)
+		checkErr(err)
+		checkErr(schedule.ValidateTx(tx))
+
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		checkWeakPrivateKey(privateKey)
+		checkPolicy(cmd.Context(), nil, chainID, tx.To(), tx.Value(), tx.Data())
+		checkTOTP()
+		signedTx, err := ethutil.SignTxWithSigner(tx, signer, privateKey)
+		checkErr(err)
+		if !signedTx.Protected() {
+			log.Printf("WARNING: tx is signed without chain id (EIP-155), it can be replayed on other chains")
+		}
+
+		rawTx, err := ethutil.GenRawTx(signedTx)
+		checkErr(err)
+
+		if !globalOptTerseOutput {
+			log.Printf("tx hash %v", signedTx.Hash().Hex())
+		}
+		fmt.Printf("%v\n", rawTx)
+	},
+}
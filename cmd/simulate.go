@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var simulateABIFile string
+var simulateHexData string
+var simulateFrom string
+var simulateValue string
+var simulateUnit string
+var simulateOverride string
+
+func init() {
+	simulateCmd.Flags().StringVarP(&simulateABIFile, "abi-file", "", "", "the path of abi file, if this option specified, 'function definition' can be just function name")
+	simulateCmd.Flags().StringVarP(&simulateHexData, "hex-data", "", "", "the input hex data")
+	simulateCmd.Flags().StringVarP(&simulateFrom, "from", "", "", "the sender of call, any address can be impersonated")
+	simulateCmd.Flags().StringVarP(&simulateValue, "value", "", "0", "the amount of eth sent with call, unit is ether and can be changed by --unit")
+	simulateCmd.Flags().StringVarP(&simulateUnit, "unit", "u", unitEther, "wei | gwei | ether, unit of --value")
+	simulateCmd.Flags().StringVarP(&simulateOverride, "override", "", "", "the state override set (balance, nonce, code, state or stateDiff of accounts), a JSON file or inline JSON")
+
+	addFlags(simulateCmd, blockFlags)
+	addFlags(simulateCmd, explorerFlags)
+}
+
+// readStateOverride reads state override from file, or parses override as inline JSON if it starts with "{".
+func readStateOverride(override string) (ethutil.StateOverride, error) {
+	if override == "" {
+		return nil, nil
+	}
+	content := []byte(override)
+	if !strings.HasPrefix(strings.TrimSpace(override), "{") {
+		var err error
+		if content, err = os.ReadFile(override); err != nil {
+			return nil, err
+		}
+	}
+	return ethutil.ParseStateOverride(content)
+}
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate address ['function definition' arg1 arg2 ...]",
+	Short: "Simulate a call by eth_call with state override, e.g. impersonate an address, patch balance, code or storage",
+	Long: "Simulate a call by eth_call with state override (--override), e.g. impersonate an address by --from, patch " +
+		"balance, nonce, code or storage of accounts, then test what-if scenarios without a fork node. Nothing is " +
+		"broadcast. The node must support the state override set of eth_call (geth, erigon, anvil etc).",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires address")
+		}
+		if len(simulateHexData) > 0 && len(args) > 1 {
+			return fmt.Errorf("--hex-data and 'function definition' cannot be specified at the same time")
+		}
+		if simulateHexData != "" && !isValidHexString(simulateHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if simulateFrom != "" && !isValidAddressArg(cmd.Context(), simulateFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", simulateFrom)
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, simulateUnit) {
+			return fmt.Errorf("invalid unit %v", simulateUnit)
+		}
+		if _, err := decimal.NewFromString(simulateValue); err != nil {
+			return fmt.Errorf("--value %v is not a valid amount", simulateValue)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		args[0] = resolveAbiName(args[0]) // contract can be a name added by abi add
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			log.Fatalf("%s is NOT a valid eth address", args[0])
+		}
+		override, err := readStateOverride(simulateOverride)
+		checkErr(err)
+
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		to := mustParseAddressArg(ctx, args[0])
+		var funcSignature string
+		var data []byte
+		if len(args) > 1 {
+			funcSignature, err = resolveFuncSignature(ctx, to, args[1], simulateABIFile)
+			checkErr(err)
+			data, err = ethutil.BuildTxInputData(funcSignature, args[2:])
+			checkErr(err)
+		} else if simulateHexData != "" {
+			data = common.FromHex(simulateHexData)
+		}
+		if globalOptShowInputData {
+			log.Printf("input data = %v", hexutil.Encode(data))
+		}
+
+		value := unify2Wei(decimal.RequireFromString(simulateValue), simulateUnit).BigInt()
+		callArgs := ethutil.CallArgs{To: &to, Value: (*hexutil.Big)(value), Data: data}
+		if simulateFrom != "" {
+			from := mustParseAddressArg(ctx, simulateFrom)
+			callArgs.From = &from
+		}
+		block := stateBlock(ctx)
+		if !globalOptTerseOutput && len(override) > 0 {
+			log.Printf("state of %v accounts is overridden", len(override))
+		}
+
+		output, err := ethutil.CallWithOverride(ctx, globalClient.RpcClient, callArgs, block, override)
+		if err != nil {
+			log.Fatalf("simulation fail: %v", err)
+		}
+		if funcSignature != "" {
+			printContractReturnData(funcSignature, output)
+			return
+		}
+		fmt.Printf("%v\n", hexutil.Encode(output))
+	},
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// keys of JSONL input and output, the JSONL output of one command can be piped to another command with --stdin
+const (
+	jsonlKeyAddress   = "address"
+	jsonlKeyTxHash    = "tx_hash"
+	jsonlKeyRawTx     = "raw_tx"
+	jsonlKeySelector  = "selector"
+	jsonlKeySignature = "signature"
+	jsonlKeyData      = "data"
+)
+
+// parseInputLine returns the input in line, which is either the plain value or a JSON object, in the latter case
+// the value of the first of keys found in the object is returned. ok is false for empty line and comment line.
+func parseInputLine(line string, keys []string) (input string, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false, nil
+	}
+	if !strings.HasPrefix(line, "{") {
+		return line, true, nil
+	}
+
+	var object map[string]any
+	if err := json.Unmarshal([]byte(line), &object); err != nil {
+		return "", false, fmt.Errorf("invalid JSON line %v: %w", line, err)
+	}
+	for _, key := range keys {
+		if value, found := object[key]; found {
+			if s, isString := value.(string); isString {
+				return s, true, nil
+			}
+			return "", false, fmt.Errorf("value of %v in JSON line %v is not a string", key, line)
+		}
+	}
+	return "", false, fmt.Errorf("none of %v is found in JSON line %v", strings.Join(keys, ", "), line)
+}
+
+// forEachInput calls fn for each of args, or for each input read from stdin if --stdin is specified. Inputs are read
+// line by line and fn is called as soon as a line is read, so commands can be chained by pipes in streaming way.
+// Each line is a plain value or a JSON object (e.g. JSONL output of another command), see parseInputLine.
+func forEachInput(args []string, keys []string, fn func(input string)) {
+	if !globalOptStdin {
+		for _, arg := range args {
+			fn(arg)
+		}
+		return
+	}
+	for {
+		line, err := stdinReader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			log.Fatalf("read stdin fail: %v", err)
+		}
+		input, ok, parseErr := parseInputLine(line, keys)
+		if parseErr != nil {
+			log.Fatalf("%v", parseErr)
+		}
+		if ok {
+			fn(input)
+		}
+		if err == io.EOF {
+			return
+		}
+	}
+}
+
+// printJSONL prints v as one line of JSON if --jsonl is specified, otherwise it returns false and prints nothing.
+func printJSONL(v any) bool {
+	if !globalOptJsonl {
+		return false
+	}
+	content, err := json.Marshal(v)
+	checkErr(err)
+	fmt.Printf("%s\n", content)
+	return true
+}
+
+// inputArgs wraps validator of args, which are not validated (and must be empty) if inputs are read from --stdin.
+func inputArgs(validator cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if globalOptStdin {
+			if len(args) > 0 {
+				return fmt.Errorf("args can not be specified with --stdin")
+			}
+			return nil
+		}
+		return validator(cmd, args)
+	}
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import "testing"
+
+func TestParseInputLine(t *testing.T) {
+	keys := []string{jsonlKeyRawTx, jsonlKeyTxHash}
+	tests := []struct {
+		line     string
+		expected string
+		ok       bool
+		err      bool
+	}{
+		{"0x1234\n", "0x1234", true, false},
+		{"  \n", "", false, false},
+		{"# comment\n", "", false, false},
+		{`{"tx_hash":"0xab","raw_tx":"0xcd"}`, "0xcd", true, false},
+		{`{"tx_hash":"0xab"}`, "0xab", true, false},
+		{`{"address":"0xab"}`, "", false, true},
+		{`{"raw_tx":1}`, "", false, true},
+		{`{"raw_tx":`, "", false, true},
+	}
+
+	for i, test := range tests {
+		input, ok, err := parseInputLine(test.line, keys)
+		if (err != nil) != test.err {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.err, err)
+		}
+		if input != test.expected || ok != test.ok {
+			t.Fatalf("test %d: expected: %v %v, got: %v %v", i, test.expected, test.ok, input, ok)
+		}
+	}
+}
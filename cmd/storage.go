@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/spf13/cobra"
+)
+
+var storageMappingSlot string
+var storageArraySlot string
+var storageKeys []string
+var storageIndex int64
+var storageLayoutFile string
+var storageVar string
+
+func init() {
+	storageCmd.Flags().StringVarP(&storageMappingSlot, "mapping-slot", "", "", "the slot of mapping, the slot of value is computed from it and --key")
+	storageCmd.Flags().StringVarP(&storageArraySlot, "array-slot", "", "", "the slot of dynamic array, the slot of element is computed from it and --index")
+	storageCmd.Flags().StringSliceVarP(&storageKeys, "key", "", nil, "the key of mapping, specify multiple times for nested mappings. address, number, bytes32 (0x-prefixed 32 bytes hex) or string")
+	storageCmd.Flags().Int64VarP(&storageIndex, "index", "", -1, "the index of dynamic array element")
+	storageCmd.Flags().StringVarP(&storageLayoutFile, "layout", "", "", "the storage layout json file generated by solc --storage-layout, used with --var")
+	storageCmd.Flags().StringVarP(&storageVar, "var", "", "", "the state variable in --layout to read and decode")
+
+	addFlags(storageCmd, blockFlags)
+}
+
+var storageCmd = &cobra.Command{
+	Use:   "storage address [slot]",
+	Short: "Read storage slot of contract, the slot can be computed from mapping key or array index and decoded by storage layout",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("requires address and optional slot")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		var slotSources int
+		for _, specified := range []bool{len(args) == 2, storageMappingSlot != "", storageArraySlot != "", storageVar != ""} {
+			if specified {
+				slotSources++
+			}
+		}
+		if slotSources != 1 {
+			return fmt.Errorf("exactly one of slot, --mapping-slot, --array-slot and --var is required")
+		}
+		if storageVar != "" && storageLayoutFile == "" {
+			return fmt.Errorf("--layout is required by --var")
+		}
+		if storageMappingSlot != "" && len(storageKeys) == 0 {
+			return fmt.Errorf("--key is required by --mapping-slot")
+		}
+		if storageArraySlot != "" && storageIndex < 0 {
+			return fmt.Errorf("--index is required by --array-slot")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var slot common.Hash
+		var typ *ethutil.StorageType // nil means print raw value
+		var offset int
+		var err error
+
+		switch {
+		case len(args) == 2:
+			slot, err = parseSlot(args[1])
+			checkErr(err)
+		case storageMappingSlot != "":
+			slot, err = parseSlot(storageMappingSlot)
+			checkErr(err)
+			for _, key := range storageKeys {
+				encodedKey, err := encodeStorageKey(key, "")
+				checkErr(err)
+				slot = ethutil.MappingSlot(encodedKey, slot)
+			}
+		case storageArraySlot != "":
+			slot, err = parseSlot(storageArraySlot)
+			checkErr(err)
+			slot = ethutil.ArraySlot(slot, big.NewInt(storageIndex), 1)
+		case storageVar != "":
+			slot, typ, offset = resolveLayoutSlot()
+		}
+		log.Printf("slot %v", slot.Hex())
+
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+
+		value, err := globalClient.EthClient.StorageAt(cmd.Context(), mustParseAddressArg(cmd.Context(), args[0]), slot, stateBlock(cmd.Context()))
+		checkErr(err)
+
+		if typ == nil {
+			fmt.Printf("%v\n", hexutil.Encode(value))
+			return
+		}
+		decoded, err := ethutil.DecodeStorageValue(common.BytesToHash(value), offset, *typ)
+		checkErr(err)
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", decoded)
+		} else {
+			fmt.Printf("%v (%v) = %v\n", storageVar, typ.Label, decoded)
+		}
+	},
+}
+
+// resolveLayoutSlot returns the slot, type and offset of --var (with --key and --index applied) in --layout.
+func resolveLayoutSlot() (common.Hash, *ethutil.StorageType, int) {
+	content, err := os.ReadFile(storageLayoutFile)
+	checkErr(err)
+	layout, err := ethutil.ParseStorageLayout(content)
+	checkErr(err)
+	entry, err := layout.Lookup(storageVar)
+	checkErr(err)
+	slot, err := entry.SlotHash()
+	checkErr(err)
+
+	var lookupType = func(name string) ethutil.StorageType {
+		t, ok := layout.Types[name]
+		if !ok {
+			log.Fatalf("type %v is not found in storage layout", name)
+		}
+		return t
+	}
+
+	typ := lookupType(entry.Type)
+	offset := entry.Offset
+	for _, key := range storageKeys {
+		if typ.Encoding != "mapping" {
+			log.Fatalf("--key is specified, but %v is not a mapping", typ.Label)
+		}
+		encodedKey, err := encodeStorageKey(key, lookupType(typ.Key).Label)
+		checkErr(err)
+		slot = ethutil.MappingSlot(encodedKey, slot)
+		typ, offset = lookupType(typ.Value), 0
+	}
+	if storageIndex >= 0 {
+		if typ.Encoding != "dynamic_array" {
+			log.Fatalf("--index is specified, but %v is not a dynamic array", typ.Label)
+		}
+		base := lookupType(typ.Base)
+		size := decimalToBigInt(base.NumberOfBytes)
+		if size.Cmp(big.NewInt(16)) <= 0 {
+			log.Fatalf("elements of %v are packed, read the slot of --array-slot instead", typ.Label)
+		}
+		elementSlots := new(big.Int).Div(new(big.Int).Add(size, big.NewInt(31)), big.NewInt(32))
+		slot = ethutil.ArraySlot(slot, big.NewInt(storageIndex), elementSlots.Int64())
+		typ, offset = base, 0
+	}
+	return slot, &typ, offset
+}
+
+// decimalToBigInt converts decimal string to big.Int, exits if it's invalid.
+func decimalToBigInt(str string) *big.Int {
+	v, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		log.Fatalf("invalid decimal %v", str)
+	}
+	return v
+}
+
+// parseSlot parses slot in decimal or 0x-prefixed hex.
+func parseSlot(str string) (common.Hash, error) {
+	v, ok := math.ParseBig256(str)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("invalid slot %v", str)
+	}
+	return common.BigToHash(v), nil
+}
+
+// encodeStorageKey encodes mapping key of solidity type typeLabel, empty typeLabel means inferring type from key.
+func encodeStorageKey(key string, typeLabel string) ([]byte, error) {
+	if typeLabel == "" {
+		_, isNumber := math.ParseBig256(key)
+		switch {
+		case isValidEthAddress(key) && has0xPrefix(key):
+			typeLabel = "address"
+		case has0xPrefix(key) && len(key) == 66 && isValidHexString(key):
+			typeLabel = "bytes32"
+		case isNumber:
+			typeLabel = "uint256"
+		default:
+			typeLabel = "string"
+		}
+	}
+
+	switch {
+	case typeLabel == "address" || strings.HasPrefix(typeLabel, "contract "):
+		if !isValidEthAddress(key) {
+			return nil, fmt.Errorf("%v is not a valid eth address", key)
+		}
+		return common.LeftPadBytes(common.HexToAddress(key).Bytes(), 32), nil
+	case typeLabel == "bool":
+		if key == "true" {
+			return common.LeftPadBytes([]byte{1}, 32), nil
+		}
+		return make([]byte, 32), nil
+	case strings.HasPrefix(typeLabel, "uint") || strings.HasPrefix(typeLabel, "int") || strings.HasPrefix(typeLabel, "enum "):
+		v, ok := new(big.Int).SetString(key, 0)
+		if !ok {
+			return nil, fmt.Errorf("%v is not a valid number", key)
+		}
+		return math.U256Bytes(v), nil // negative ints are two's complement
+	case typeLabel == "string":
+		return []byte(key), nil
+	case typeLabel == "bytes":
+		return hexutil.Decode(key)
+	case strings.HasPrefix(typeLabel, "bytes"): // bytesN is right padded
+		data, err := hexutil.Decode(key)
+		if err != nil {
+			return nil, err
+		}
+		return common.RightPadBytes(data, 32), nil
+	default:
+		return nil, fmt.Errorf("mapping key of type %v is not supported", typeLabel)
+	}
+}
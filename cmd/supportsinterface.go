@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/spf13/cobra"
+)
+
+// knownInterfaceId returns interface id of name of KnownInterfaces (e.g. erc721, ERC-721), or v itself if it's a
+// 4 bytes hex interface id.
+func knownInterfaceId(v string) (string, error) {
+	if isValidHexString(v) && len(v) == 10 {
+		return strings.ToLower(v), nil
+	}
+	normalize := func(name string) string {
+		return strings.ToLower(strings.NewReplacer("-", "", " ", "", "_", "").Replace(name))
+	}
+	for _, known := range ethutil.KnownInterfaces {
+		if normalize(known.Name) == normalize(v) {
+			if known.InterfaceId == "" {
+				return "", fmt.Errorf("%v has no ERC-165 interface id, run supports-interface without interface-id to detect it", known.Name)
+			}
+			return known.InterfaceId, nil
+		}
+	}
+	return "", fmt.Errorf("%v is neither a 4 bytes interface id nor a known interface", v)
+}
+
+func init() {
+	addFlags(supportsInterfaceCmd, blockFlags)
+}
+
+var supportsInterfaceCmd = &cobra.Command{
+	Use:   "supports-interface contract-address [interface-id|name]...",
+	Short: "Check interfaces supported by contract via ERC-165, or detect the common standards it implements",
+	Long: "Check interfaces (e.g. 0x80ac58cd or erc721) supported by contract via ERC-165 supportsInterface. Without\n" +
+		"interface-id, detect which of the common standards (ERC-20, 721, 1155, 2981, 4626 etc) the contract implements,\n" +
+		"ERC-20 and ERC-4626 have no ERC-165 interface id and are detected by heuristics.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return fmt.Errorf("requires contract-address")
+		}
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
+		}
+		for _, arg := range args[1:] {
+			if _, err := knownInterfaceId(arg); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+		block := stateBlock(ctx)
+		contract := mustParseAddressArg(ctx, args[0])
+
+		if len(args) == 1 {
+			supported, err := ethutil.DetectInterfaces(ctx, globalClient.EthClient, contract, block)
+			checkErr(err)
+			for _, known := range supported {
+				if printJSONL(map[string]any{"address": contract.Hex(), "interface": known.Name, "interface_id": known.InterfaceId}) {
+					continue
+				}
+				fmt.Printf("%v\n", known.Name)
+			}
+			if len(supported) == 0 && !globalOptTerseOutput {
+				log.Printf("no known interface is detected")
+			}
+			return
+		}
+
+		for _, arg := range args[1:] {
+			interfaceId, _ := knownInterfaceId(arg)
+			supported, err := ethutil.SupportsInterface(ctx, globalClient.EthClient, contract, interfaceId, block)
+			checkErr(err)
+			if printJSONL(map[string]any{"address": contract.Hex(), "interface_id": interfaceId, "supported": supported}) {
+				continue
+			}
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", supported)
+				continue
+			}
+			fmt.Printf("%v %v\n", interfaceId, supported)
+		}
+	},
+}
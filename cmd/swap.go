@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var swapVersion string
+var swapFee uint32
+var swapRouter string
+var swapQuoter string
+var swapSlippage string
+var swapDeadline time.Duration
+var swapRecipient string
+var swapNoApprove bool
+
+func init() {
+	swapCmd.PersistentFlags().StringVarP(&swapVersion, "uniswap-version", "", ethutil.UniswapV3, "v2 | v3, the Uniswap version")
+	swapCmd.PersistentFlags().Uint32VarP(&swapFee, "fee", "", 0, "the fee tier of v3 pool, e.g. 500 (0.05%), 3000 (0.3%), default is the tier with best quote")
+	swapCmd.PersistentFlags().StringVarP(&swapRouter, "router", "", "", "the UniswapV2Router02 (v2) or SwapRouter02 (v3) contract, default is the deployment of current chain")
+	swapCmd.PersistentFlags().StringVarP(&swapQuoter, "quoter", "", "", "the QuoterV2 contract of v3, default is the deployment of current chain")
+
+	swapExactInCmd.Flags().StringVarP(&swapSlippage, "slippage", "", "0.5", "the max slippage in percent, the tx reverts if output is less than quote minus slippage")
+	swapExactInCmd.Flags().DurationVarP(&swapDeadline, "deadline", "", 20*time.Minute, "the tx reverts if it's mined after this duration")
+	swapExactInCmd.Flags().StringVarP(&swapRecipient, "recipient", "", "", "the receiver of output token, default is the sender")
+	swapExactInCmd.Flags().BoolVarP(&swapNoApprove, "no-approve", "", false, "do not approve input token to router automatically when allowance is insufficient")
+
+	swapCmd.AddCommand(swapQuoteCmd)
+	swapCmd.AddCommand(swapExactInCmd)
+
+	addFlags(swapCmd, txFlags)
+}
+
+var swapCmd = &cobra.Command{
+	Use:   "swap",
+	Short: "Quote and swap tokens by Uniswap v2 or v3",
+	Long: "Quote and swap tokens by Uniswap v2 (getAmountsOut of router) or v3 (QuoterV2 and SwapRouter02). Token is\n" +
+		"an ERC-20 address or eth (the native token), amount is in units of the token, e.g. 1.5 means 1.5 ether of eth.",
+}
+
+// swapArgs validates args: token-in, token-out and amount-in.
+func swapArgs(cmd *cobra.Command, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("requires token-in, token-out and amount-in")
+	}
+	for i := range args[:2] {
+		if !isSwapEth(args[i]) && !isValidAddressArg(cmd.Context(), args[i]) {
+			return fmt.Errorf("%v is neither a valid eth address nor eth", args[i])
+		}
+	}
+	if isSwapEth(args[0]) && isSwapEth(args[1]) {
+		return fmt.Errorf("token-in and token-out are both eth")
+	}
+	if _, err := decimal.NewFromString(args[2]); err != nil {
+		return fmt.Errorf("%v is not a valid amount", args[2])
+	}
+	if swapVersion != ethutil.UniswapV2 && swapVersion != ethutil.UniswapV3 {
+		return fmt.Errorf("invalid --uniswap-version %v", swapVersion)
+	}
+	for _, address := range []string{swapRouter, swapQuoter, swapRecipient} {
+		if address != "" && !isValidAddressArg(cmd.Context(), address) {
+			return fmt.Errorf("%v is not a valid eth address", address)
+		}
+	}
+	return nil
+}
+
+// isSwapEth returns true if token of swap is the native token.
+func isSwapEth(token string) bool {
+	return strings.EqualFold(token, "eth")
+}
+
+// swapToken is a token of swap. Address is WETH for native token.
+type swapToken struct {
+	Address  common.Address
+	IsEth    bool
+	Symbol   string
+	Decimals uint8
+}
+
+// format formats amount of token with decimals and symbol.
+func (t swapToken) format(amount *big.Int) string {
+	value := bigInt2Decimal(amount).Shift(-int32(t.Decimals)).String()
+	if globalOptTerseOutput || t.Symbol == "" {
+		return value
+	}
+	return value + " " + t.Symbol
+}
+
+// swapQuote is the result of quoting, Fee is only for v3 and Path is only for v2.
+type swapQuote struct {
+	TokenIn   swapToken
+	TokenOut  swapToken
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	Router    common.Address
+	Fee       uint32
+	Path      []common.Address
+}
+
+// resolveSwapToken returns swapToken of arg, which is a token address or eth.
+func resolveSwapToken(ctx context.Context, arg string, weth common.Address) swapToken {
+	if isSwapEth(arg) {
+		return swapToken{Address: weth, IsEth: true, Symbol: "ETH", Decimals: 18}
+	}
+	token := swapToken{Address: mustParseAddressArg(ctx, arg)}
+	values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, token.Address, erc20FuncSignature["decimals"], nil)
+	if err != nil {
+		log.Fatalf("query decimals of %v fail, it may not be an ERC-20 token: %v", arg, err)
+	}
+	token.Decimals = values[0].(uint8)
+	if values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, token.Address, erc20FuncSignature["symbol"], nil); err == nil {
+		token.Symbol = values[0].(string)
+	}
+	return token
+}
+
+// quoteSwap connects to node and quotes swapping amount-in of token-in to token-out. For v2, the direct pair and the
+// route via WETH are both quoted and the better is returned.
+func quoteSwap(ctx context.Context, args []string) *swapQuote {
+	chainId := currentChainId(ctx)
+	if globalClient == nil {
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+	}
+	deployment := ethutil.KnownUniswapDeployments[chainId]
+	router := deployment.V3Router
+	if swapVersion == ethutil.UniswapV2 {
+		router = deployment.V2Router
+	}
+	if swapRouter != "" {
+		router = mustParseAddressArg(ctx, swapRouter)
+	}
+	quoter := deployment.V3Quoter
+	if swapQuoter != "" {
+		quoter = mustParseAddressArg(ctx, swapQuoter)
+	}
+	if router == (common.Address{}) || (swapVersion == ethutil.UniswapV3 && quoter == (common.Address{})) {
+		log.Fatalf("no known Uniswap %v deployment of chain %v, --router and --quoter (v3) are required", swapVersion, chainId)
+	}
+
+	weth, err := ethutil.UniswapWeth(ctx, globalClient.EthClient, swapVersion, router)
+	checkErr(err)
+	quote := &swapQuote{
+		TokenIn:  resolveSwapToken(ctx, args[0], weth),
+		TokenOut: resolveSwapToken(ctx, args[1], weth),
+		Router:   router,
+	}
+	if quote.TokenIn.Address == quote.TokenOut.Address {
+		log.Fatalf("token-in and token-out are the same token %v", quote.TokenIn.Address.Hex())
+	}
+	quote.AmountIn = decimal.RequireFromString(args[2]).Shift(int32(quote.TokenIn.Decimals)).BigInt()
+
+	if swapVersion == ethutil.UniswapV3 {
+		if swapFee > 0 {
+			quote.Fee = swapFee
+			quote.AmountOut, err = ethutil.UniswapV3Quote(ctx, globalClient.EthClient, quoter, quote.TokenIn.Address, quote.TokenOut.Address, swapFee, quote.AmountIn)
+		} else {
+			quote.AmountOut, quote.Fee, err = ethutil.UniswapV3BestQuote(ctx, globalClient.EthClient, quoter, quote.TokenIn.Address, quote.TokenOut.Address, quote.AmountIn)
+		}
+		checkErr(err)
+		return quote
+	}
+
+	paths := [][]common.Address{{quote.TokenIn.Address, quote.TokenOut.Address}}
+	if quote.TokenIn.Address != weth && quote.TokenOut.Address != weth {
+		paths = append(paths, []common.Address{quote.TokenIn.Address, weth, quote.TokenOut.Address})
+	}
+	for _, path := range paths {
+		amountOut, err := ethutil.UniswapV2Quote(ctx, globalClient.EthClient, router, path, quote.AmountIn)
+		if err != nil {
+			// the pair doesn't exist
+			continue
+		}
+		if quote.AmountOut == nil || amountOut.Cmp(quote.AmountOut) > 0 {
+			quote.AmountOut, quote.Path = amountOut, path
+		}
+	}
+	if quote.AmountOut == nil {
+		log.Fatalf("no v2 pair of %v and %v has liquidity", quote.TokenIn.Address.Hex(), quote.TokenOut.Address.Hex())
+	}
+	return quote
+}
+
+// route returns the description of pool or path of quote.
+func (q *swapQuote) route() string {
+	if swapVersion == ethutil.UniswapV3 {
+		return fmt.Sprintf("v3 pool of fee %v", q.Fee)
+	}
+	var hops []string
+	for _, token := range q.Path {
+		hops = append(hops, token.Hex())
+	}
+	return "v2 path " + strings.Join(hops, " -> ")
+}
+
+var swapQuoteCmd = &cobra.Command{
+	Use:   "quote token-in token-out amount-in",
+	Short: "Quote output amount of swapping exact input amount",
+	Args:  swapArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		quote := quoteSwap(cmd.Context(), args)
+		if printJSONL(map[string]any{
+			"token_in":   quote.TokenIn.Address.Hex(),
+			"token_out":  quote.TokenOut.Address.Hex(),
+			"amount_in":  bigInt2Decimal(quote.AmountIn).Shift(-int32(quote.TokenIn.Decimals)).String(),
+			"amount_out": bigInt2Decimal(quote.AmountOut).Shift(-int32(quote.TokenOut.Decimals)).String(),
+			"route":      quote.route(),
+		}) {
+			return
+		}
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", quote.TokenOut.format(quote.AmountOut))
+			return
+		}
+		fmt.Printf("%v -> %v (%v)\n", quote.TokenIn.format(quote.AmountIn), quote.TokenOut.format(quote.AmountOut), quote.route())
+	},
+}
+
+var swapExactInCmd = &cobra.Command{
+	Use:   "exact-in token-in token-out amount-in",
+	Short: "Swap exact input amount with slippage protection and deadline, approving input token to router if needed",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if err := swapArgs(cmd, args); err != nil {
+			return err
+		}
+		slippage, err := decimal.NewFromString(swapSlippage)
+		if err != nil || slippage.IsNegative() || slippage.GreaterThan(decimal.NewFromInt(100)) {
+			return fmt.Errorf("invalid --slippage %v, must be a percent between 0 and 100", swapSlippage)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for swap exact-in command")
+		}
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+		recipient := extractAddressFromPrivateKey(privateKey)
+		if swapRecipient != "" {
+			recipient = mustParseAddressArg(ctx, swapRecipient)
+		}
+
+		quote := quoteSwap(ctx, args)
+		slippageBps := decimal.RequireFromString(swapSlippage).Shift(2).IntPart()
+		minOut := ethutil.MinAmountOut(quote.AmountOut, slippageBps)
+		deadline := time.Now().Add(swapDeadline).Unix()
+		log.Printf("quote %v -> %v (%v), min output %v with slippage %v%%", quote.TokenIn.format(quote.AmountIn),
+			quote.TokenOut.format(quote.AmountOut), quote.route(), quote.TokenOut.format(minOut), swapSlippage)
+
+		if !quote.TokenIn.IsEth && !swapNoApprove {
+			approveIfInsufficient(ctx, privateKey, quote.TokenIn.Address, quote.Router, quote.AmountIn)
+		}
+
+		var txData []byte
+		var err error
+		if swapVersion == ethutil.UniswapV2 {
+			txData, err = ethutil.UniswapV2SwapData(quote.AmountIn, minOut, quote.Path, recipient, deadline, quote.TokenIn.IsEth, quote.TokenOut.IsEth)
+		} else {
+			txData, err = ethutil.UniswapV3SwapData(quote.TokenIn.Address, quote.TokenOut.Address, quote.Fee, quote.AmountIn, minOut, recipient, deadline, quote.TokenOut.IsEth)
+		}
+		checkErr(err)
+		value := big.NewInt(0)
+		if quote.TokenIn.IsEth {
+			value = quote.AmountIn
+		}
+		tx, err := Transact(ctx, globalClient, privateKey, &quote.Router, value, nil, txData)
+		checkErr(err)
+		log.Printf("transaction %s finished", tx)
+	},
+}
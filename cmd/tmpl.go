@@ -0,0 +1,257 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// txTemplate is a named command declared in "templates" of config file, the placeholders ${param} in args are
+// replaced by the values of params, which are given as --param value when running the template.
+type txTemplate struct {
+	Description string            `json:"description"`
+	Args        []string          `json:"args"`   // the args of ethutil, e.g. ["transfer", "0x...", "${amount}"]
+	Params      map[string]string `json:"params"` // the default values of params, empty means the param is required
+}
+
+var templatePlaceholderRE = regexp.MustCompile(`\$\{([A-Za-z0-9_-]+)\}`)
+
+// expand replaces the placeholders in args of template by values, or by default values if not given.
+func (t txTemplate) expand(values map[string]string) ([]string, error) {
+	if len(t.Args) == 0 {
+		return nil, fmt.Errorf("args of template is empty")
+	}
+	var err error
+	var expanded []string
+	for _, arg := range t.Args {
+		expanded = append(expanded, templatePlaceholderRE.ReplaceAllStringFunc(arg, func(placeholder string) string {
+			param := templatePlaceholderRE.FindStringSubmatch(placeholder)[1]
+			defaultValue, declared := t.Params[param]
+			if !declared {
+				err = fmt.Errorf("param %v is not declared in params of template", param)
+				return placeholder
+			}
+			value, given := values[param]
+			if !given {
+				value = defaultValue
+			}
+			if value == "" && err == nil {
+				err = fmt.Errorf("--%v is required", param)
+			}
+			return value
+		}))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return expanded, nil
+}
+
+// parseParams picks --param value (or --param=value) of params declared by template out of args, other args are
+// returned as is and passed to the expanded command.
+func (t txTemplate) parseParams(args []string) (values map[string]string, rest []string, err error) {
+	values = make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") {
+			rest = append(rest, arg)
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if _, declared := t.Params[name]; !declared {
+			rest = append(rest, arg)
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("flag needs an argument: --%v", name)
+			}
+			i++
+			value = args[i]
+		}
+		values[name] = value
+	}
+	return values, rest, nil
+}
+
+// splitTemplateArgs returns the template name, which is the first arg not belonging to flags, and other args. Flags
+// of flagSet are recognized so that their values are not mistaken for the name, the value of --config is returned.
+func splitTemplateArgs(args []string, flagSet *pflag.FlagSet) (name string, rest []string, config string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			if name == "" {
+				name = arg
+			} else {
+				rest = append(rest, arg)
+			}
+			continue
+		}
+		rest = append(rest, arg)
+		flagName, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flag := flagSet.Lookup(flagName)
+		if flag == nil || flag.Value.Type() == "bool" {
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+			rest = append(rest, value)
+		}
+		if flagName == "config" {
+			config = value
+		}
+	}
+	return name, rest, config
+}
+
+// shellQuote quotes arg by single quotes if it contains characters special to shell.
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-.,:/=@%+") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// loadTemplate loads the template name from config file.
+func loadTemplate(file string, name string) (*txTemplate, error) {
+	config, err := readConfigFile(file)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := config.Templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %v is not found in config file %v", name, file)
+	}
+	return &t, nil
+}
+
+// expandTemplateArgs expands the template selected by args of tmpl show/run, flags not declared as params of
+// template are appended to the expanded command.
+func expandTemplateArgs(cmd *cobra.Command, args []string) []string {
+	flagSet := pflag.NewFlagSet("tmpl", pflag.ContinueOnError)
+	for _, fs := range []*pflag.FlagSet{cmd.Root().PersistentFlags(), txFlags, blockFlags, fiatFlags, exportFlags, explorerFlags} {
+		flagSet.AddFlagSet(fs)
+	}
+	name, rest, config := splitTemplateArgs(args, flagSet)
+	if name == "" {
+		if contains(rest, "-h") || contains(rest, "--help") {
+			_ = cmd.Help()
+			os.Exit(0)
+		}
+		log.Fatalf("template name is required")
+	}
+	if config == "" {
+		config = globalOptConfigFile
+	}
+
+	t, err := loadTemplate(config, name)
+	checkErr(err)
+	values, rest, err := t.parseParams(rest)
+	if err != nil {
+		log.Fatalf("template %v: %v", name, err)
+	}
+	expanded, err := t.expand(values)
+	if err != nil {
+		log.Fatalf("template %v: %v", name, err)
+	}
+	return append(expanded, rest...)
+}
+
+func init() {
+	tmplCmd.AddCommand(tmplListCmd)
+	tmplCmd.AddCommand(tmplShowCmd)
+	tmplCmd.AddCommand(tmplRunCmd)
+}
+
+var tmplCmd = &cobra.Command{
+	Use:   "tmpl",
+	Short: "List, show and run named command templates declared in config file",
+}
+
+var tmplListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates in config file with their params and default values",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := readConfigFile(globalOptConfigFile)
+		checkErr(err)
+
+		var names []string
+		for name := range config.Templates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if globalOptTerseOutput {
+				fmt.Printf("%v\n", name)
+				continue
+			}
+			t := config.Templates[name]
+			fmt.Printf("%v\t%v\n", name, t.Description)
+			var params []string
+			for param := range t.Params {
+				params = append(params, param)
+			}
+			sort.Strings(params)
+			for _, param := range params {
+				if t.Params[param] == "" {
+					fmt.Printf("  --%v (required)\n", param)
+				} else {
+					fmt.Printf("  --%v (default %v)\n", param, t.Params[param])
+				}
+			}
+		}
+	},
+}
+
+var tmplShowCmd = &cobra.Command{
+	Use:                "show template-name [--param value]...",
+	Short:              "Print the command expanded from template, without running it",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		expanded := expandTemplateArgs(cmd, args)
+		var quoted []string
+		for _, arg := range expanded {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		fmt.Printf("ethutil %v\n", strings.Join(quoted, " "))
+	},
+}
+
+var tmplRunCmd = &cobra.Command{
+	Use:   "run template-name [--param value]... [flags]",
+	Short: "Run the command expanded from template, flags not declared as params are passed to the command",
+	Long: "Run the command expanded from template, e.g. `ethutil tmpl run top-up-relayer --amount 0.5eth` replaces ${amount} in args of\n" +
+		"template top-up-relayer by 0.5eth, other params take default values. Flags not declared as params (e.g. --dry-run) are\n" +
+		"appended to the expanded command.",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		expanded := expandTemplateArgs(cmd, args)
+		if !globalOptTerseOutput {
+			log.Printf("running %v", strings.Join(expanded, " "))
+		}
+
+		executable, err := os.Executable()
+		checkErr(err)
+		child := exec.Command(executable, expanded...)
+		child.Stdin = os.Stdin
+		child.Stdout = os.Stdout
+		child.Stderr = os.Stderr
+		if err := child.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			checkErr(err)
+		}
+	},
+}
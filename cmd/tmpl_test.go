@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestTxTemplateExpand(t *testing.T) {
+	tmpl := txTemplate{
+		Args:   []string{"transfer", "${to}", "${amount}", "--gas-price=${gas}"},
+		Params: map[string]string{"to": "", "amount": "0.1eth", "gas": "20"},
+	}
+
+	tests := []struct {
+		args     []string
+		expected []string
+		rest     []string
+		hasErr   bool
+	}{
+		{[]string{"--to", "0xabc"}, []string{"transfer", "0xabc", "0.1eth", "--gas-price=20"}, nil, false},
+		{[]string{"--to=0xabc", "--amount", "0.5eth", "--dry-run"}, []string{"transfer", "0xabc", "0.5eth", "--gas-price=20"}, []string{"--dry-run"}, false},
+		{[]string{"--node", "sepolia", "--to", "0xabc", "--gas", "5"}, []string{"transfer", "0xabc", "0.1eth", "--gas-price=5"}, []string{"--node", "sepolia"}, false},
+		{[]string{"--amount", "1eth"}, nil, nil, true}, // --to is required
+		{[]string{"--to"}, nil, nil, true},
+	}
+
+	for i, test := range tests {
+		values, rest, err := tmpl.parseParams(test.args)
+		var expanded []string
+		if err == nil {
+			expanded, err = tmpl.expand(values)
+		}
+		if (err != nil) != test.hasErr {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if test.hasErr {
+			continue
+		}
+		if !reflect.DeepEqual(expanded, test.expected) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, expanded)
+		}
+		if !reflect.DeepEqual(rest, test.rest) {
+			t.Fatalf("test %d: expected rest: %v, got: %v", i, test.rest, rest)
+		}
+	}
+
+	if _, err := (txTemplate{Args: []string{"${undeclared}"}}).expand(nil); err == nil {
+		t.Fatalf("expected error for undeclared param")
+	}
+}
+
+func TestSplitTemplateArgs(t *testing.T) {
+	flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flagSet.String("node", "", "")
+	flagSet.String("config", "", "")
+	flagSet.Bool("dry-run", false, "")
+
+	tests := []struct {
+		args   []string
+		name   string
+		rest   []string
+		config string
+	}{
+		{[]string{"top-up", "--amount", "1"}, "top-up", []string{"--amount", "1"}, ""},
+		{[]string{"top-up", "--amount", "1", "--config", "/tmp/c.json"}, "top-up", []string{"--amount", "1", "--config", "/tmp/c.json"}, "/tmp/c.json"},
+		{[]string{"--node", "sepolia", "--dry-run", "top-up"}, "top-up", []string{"--node", "sepolia", "--dry-run"}, ""},
+		{[]string{"--config=/tmp/c.json", "top-up"}, "top-up", []string{"--config=/tmp/c.json"}, "/tmp/c.json"},
+		{[]string{"--config", "/tmp/c.json"}, "", []string{"--config", "/tmp/c.json"}, "/tmp/c.json"},
+	}
+
+	for i, test := range tests {
+		name, rest, config := splitTemplateArgs(test.args, flagSet)
+		if name != test.name || !reflect.DeepEqual(rest, test.rest) || config != test.config {
+			t.Fatalf("test %d: expected: %v %v %v, got: %v %v %v", i, test.name, test.rest, test.config, name, rest, config)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{"0.5eth", "0.5eth"},
+		{"--node=sepolia", "--node=sepolia"},
+		{"transfer(address,uint256)", "'transfer(address,uint256)'"},
+		{"it's", `'it'\''s'`},
+		{"", "''"},
+	}
+
+	for i, test := range tests {
+		if got := shellQuote(test.arg); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/spf13/cobra"
+)
+
+var totpEnrollAccount string
+var totpEnrollPasswordFile string
+var totpEnrollForce bool
+var totpKeystore string
+
+// totpChecked makes the operator enter TOTP code only once per command, even if it signs multiple txs
+var totpChecked sync.Once
+
+func init() {
+	totpEnrollCmd.Flags().StringVarP(&totpEnrollAccount, "account", "", "ethutil", "the account name shown in authenticator app")
+	totpEnrollCmd.Flags().StringVarP(&totpEnrollPasswordFile, "password-file", "", "", "the file containing password which encrypts TOTP secret, password is read from stdin if not specified")
+	totpEnrollCmd.Flags().BoolVarP(&totpEnrollForce, "force", "", false, "overwrite the existing TOTP file")
+	for _, c := range []*cobra.Command{totpEnrollCmd, totpVerifyCmd, totpRemoveCmd} {
+		c.Flags().StringVarP(&totpKeystore, "keystore", "", "", "bind TOTP to this keystore V3 file instead of TOTP file, the key can't be decrypted without TOTP data")
+	}
+
+	totpCmd.AddCommand(totpEnrollCmd)
+	totpCmd.AddCommand(totpVerifyCmd)
+	totpCmd.AddCommand(totpRemoveCmd)
+
+	addFlags(totpCmd, txFlags, "totp-file")
+}
+
+// totpFile returns --totp-file, default is ~/.ethutil/totp.json
+func totpFile() string {
+	if globalOptTotpFile != "" {
+		return globalOptTotpFile
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ethutil", "totp.json")
+}
+
+// readTOTPCode reads TOTP code from stdin.
+func readTOTPCode() string {
+	fmt.Fprintf(os.Stderr, "TOTP code: ")
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("read TOTP code fail: %v", err)
+	}
+	return strings.TrimSpace(line)
+}
+
+// loadTOTPSecret decrypts TOTP secret in file by password read from stdin.
+func loadTOTPSecret(file string) (string, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	var encrypted keystore.CryptoJSON
+	if err := json.Unmarshal(content, &encrypted); err != nil {
+		return "", fmt.Errorf("parse TOTP file %v fail: %w", file, err)
+	}
+	secret, err := keystore.DecryptDataV3(encrypted, readPassword(""))
+	if err != nil {
+		return "", fmt.Errorf("decrypt TOTP secret fail: %w", err)
+	}
+	return string(secret), nil
+}
+
+// checkTOTPCode returns error if code is not the current TOTP code of secret.
+func checkTOTPCode(secret string, code string) error {
+	ok, err := ethutil.VerifyTOTP(secret, code, time.Now(), 1)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid TOTP code")
+	}
+	return nil
+}
+
+// verifyTOTPFile asks for password and TOTP code, exits if the code is wrong.
+func verifyTOTPFile(file string) {
+	secret, err := loadTOTPSecret(file)
+	checkErr(err)
+	checkErr(checkTOTPCode(secret, readTOTPCode()))
+}
+
+// checkTOTP asks for TOTP code before signing if TOTP file (--totp-file, default ~/.ethutil/totp.json) exists, which is
+// created by totp enroll. It exits if the code is wrong, or if TOTP file is missing while it's required, i.e.
+// --totp-file, or totp_file or require_totp of profile is set.
+// Keystore bound to TOTP (totp enroll --keystore) asks for TOTP code when it's decrypted, see unlockKeystore.
+func checkTOTP() {
+	totpChecked.Do(func() {
+		file := totpFile()
+		if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+			if globalOptTotpFile != "" || globalRequireTOTP {
+				log.Fatalf("TOTP file %v does not exist, but TOTP is required", file)
+			}
+			return
+		}
+		verifyTOTPFile(file)
+	})
+}
+
+// newConfirmedTOTPSecret generates TOTP secret, and exits unless the operator enters the code shown by authenticator app.
+func newConfirmedTOTPSecret() string {
+	secret, err := ethutil.NewTOTPSecret()
+	checkErr(err)
+	fmt.Printf("secret %v\n", secret)
+	fmt.Printf("uri %v\n", ethutil.TOTPProvisioningURI("ethutil", totpEnrollAccount, secret))
+	log.Printf("add the secret (or QR code of uri) to authenticator app, then enter the code it shows to confirm")
+
+	if err := checkTOTPCode(secret, readTOTPCode()); err != nil {
+		log.Fatalf("%v, TOTP is not enrolled", err)
+	}
+	return secret
+}
+
+// writeKeystore replaces keystore file with content.
+func writeKeystore(file string, content []byte) {
+	tmp := file + ".tmp"
+	checkErr(os.WriteFile(tmp, content, 0600))
+	checkErr(os.Rename(tmp, file))
+}
+
+// enrollKeystoreTOTP binds TOTP to keystore file: TOTP secret is encrypted by keystore password into the file, and
+// the key is re-encrypted by both password and TOTP secret. So signing with the keystore requires TOTP code, and fails
+// if the TOTP data is removed from the file.
+func enrollKeystoreTOTP(file string) {
+	content, err := os.ReadFile(file)
+	checkErr(err)
+	ks := parseKeystoreFile(content)
+	if ks == nil {
+		log.Fatalf("%v is not a keystore V3 file", file)
+	}
+	if ks.Totp != nil {
+		log.Fatalf("TOTP is already enrolled in keystore %v, remove it first", file)
+	}
+	password := readPassword(totpEnrollPasswordFile)
+	key, err := keystore.DecryptKey(content, password)
+	checkErr(err)
+
+	secret := newConfirmedTOTPSecret()
+	encrypted, err := keystore.EncryptDataV3([]byte(secret), []byte(password), keystoreScryptN, keystoreScryptP)
+	checkErr(err)
+	content, err = encryptKeystore(key, totpKeystorePassword(password, secret), &encrypted)
+	checkErr(err)
+	writeKeystore(file, content)
+	log.Printf("TOTP is enrolled, code is required when signing with keystore %v, which can only be decrypted by ethutil now", file)
+}
+
+var totpCmd = &cobra.Command{
+	Use:   "totp",
+	Short: "Require TOTP code of authenticator app before signing",
+}
+
+var totpEnrollCmd = &cobra.Command{
+	Use:   "enroll",
+	Short: "Generate TOTP secret for authenticator app, the secret is encrypted by password in TOTP file (--totp-file)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if totpKeystore != "" {
+			enrollKeystoreTOTP(totpKeystore)
+			return
+		}
+
+		file := totpFile()
+		if _, err := os.Stat(file); err == nil && !totpEnrollForce {
+			log.Fatalf("TOTP file %v already exists, use --force to overwrite it", file)
+		}
+
+		secret := newConfirmedTOTPSecret()
+		encrypted, err := keystore.EncryptDataV3([]byte(secret), []byte(readPassword(totpEnrollPasswordFile)), keystore.StandardScryptN, keystore.StandardScryptP)
+		checkErr(err)
+		content, err := json.MarshalIndent(encrypted, "", "  ")
+		checkErr(err)
+		checkErr(os.MkdirAll(filepath.Dir(file), 0700))
+		checkErr(os.WriteFile(file, content, 0600))
+		log.Printf("TOTP is enrolled, code is required before signing. TOTP file is %v", file)
+	},
+}
+
+var totpVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check password and TOTP code against TOTP file (--totp-file) or keystore (--keystore)",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if totpKeystore != "" {
+			content, err := os.ReadFile(totpKeystore)
+			checkErr(err)
+			_, secret, err := unlockKeystore(content, readPassword(""), readTOTPCode)
+			checkErr(err)
+			if secret == "" {
+				log.Fatalf("TOTP is not enrolled in keystore %v", totpKeystore)
+			}
+		} else {
+			verifyTOTPFile(totpFile())
+		}
+		fmt.Printf("TOTP code is valid\n")
+	},
+}
+
+var totpRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove TOTP file (--totp-file) or TOTP data of keystore (--keystore) after checking password and TOTP code",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if totpKeystore != "" {
+			content, err := os.ReadFile(totpKeystore)
+			checkErr(err)
+			password := readPassword("")
+			key, secret, err := unlockKeystore(content, password, readTOTPCode)
+			checkErr(err)
+			if secret == "" {
+				log.Fatalf("TOTP is not enrolled in keystore %v", totpKeystore)
+			}
+			content, err = encryptKeystore(key, password, nil)
+			checkErr(err)
+			writeKeystore(totpKeystore, content)
+			log.Printf("TOTP is removed from keystore %v", totpKeystore)
+			return
+		}
+
+		file := totpFile()
+		verifyTOTPFile(file)
+		checkErr(os.Remove(file))
+		log.Printf("TOTP file %v is removed", file)
+	},
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var traceTracer string
+var traceRaw bool
+var traceNoDecode bool
+var traceFrom string
+var traceTo string
+var traceValue string
+var traceUnit string
+var traceHexData string
+
+func init() {
+	traceCmd.Flags().StringVarP(&traceTracer, "tracer", "", ethutil.TracerCall, "callTracer | prestateTracer | structLogs, the tracer of node")
+	traceCmd.Flags().BoolVarP(&traceRaw, "raw", "", false, "print the raw JSON result of tracer")
+	traceCmd.Flags().BoolVarP(&traceNoDecode, "no-decode", "", false, "do not look up function names of call tree from https://openchain.xyz/signatures")
+	traceCmd.Flags().StringVarP(&traceFrom, "from", "", "", "the sender of traced call, only used without tx-hash")
+	traceCmd.Flags().StringVarP(&traceTo, "to", "", "", "the target of traced call (debug_traceCall), only used without tx-hash")
+	traceCmd.Flags().StringVarP(&traceValue, "value", "", "0", "the value of traced call, only used without tx-hash")
+	traceCmd.Flags().StringVarP(&traceUnit, "unit", "u", unitEther, "wei | gwei | ether, unit of --value")
+	traceCmd.Flags().StringVarP(&traceHexData, "hex-data", "", "", "the input data of traced call, only used without tx-hash")
+
+	addFlags(traceCmd, blockFlags)
+}
+
+var traceCmd = &cobra.Command{
+	Use:   "trace [tx-hash]",
+	Short: "Trace tx by debug_traceTransaction, or a call by debug_traceCall, and print the call tree with revert points",
+	Long: "Trace tx by debug_traceTransaction, or a call (--to, --hex-data etc) by debug_traceCall, and print the call " +
+		"tree with function names and revert points. The node must expose debug APIs.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("multiple tx-hash is not supported")
+		}
+		if !contains([]string{ethutil.TracerCall, ethutil.TracerPrestate, ethutil.TracerStructLogs}, traceTracer) {
+			return fmt.Errorf("invalid --tracer %v", traceTracer)
+		}
+		if len(args) == 1 {
+			if !isValidHexString(args[0]) || len(common.FromHex(args[0])) != common.HashLength {
+				return fmt.Errorf("tx-hash %v is not a valid hash", args[0])
+			}
+			if traceTo != "" {
+				return fmt.Errorf("tx-hash and --to can not be specified at the same time")
+			}
+			return nil
+		}
+		if !isValidAddressArg(cmd.Context(), traceTo) {
+			return fmt.Errorf("requires tx-hash or a valid --to")
+		}
+		if traceFrom != "" && !isValidAddressArg(cmd.Context(), traceFrom) {
+			return fmt.Errorf("--from %v is not a valid eth address", traceFrom)
+		}
+		if traceHexData != "" && !isValidHexString(traceHexData) {
+			return fmt.Errorf("--hex-data must hex string")
+		}
+		if !contains([]string{unitWei, unitGwei, unitEther}, traceUnit) {
+			return fmt.Errorf("invalid unit %v", traceUnit)
+		}
+		if _, err := decimal.NewFromString(traceValue); err != nil {
+			return fmt.Errorf("--value %v is not a valid number", traceValue)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Printf("Current network is %v", globalOptNode)
+
+		ctx := cmd.Context()
+		InitGlobalClient(ctx, globalOptNodeUrl)
+
+		var result json.RawMessage
+		var err error
+		if len(args) == 1 {
+			result, err = ethutil.TraceTransaction(ctx, globalClient.RpcClient, common.HexToHash(args[0]), traceTracer)
+		} else {
+			to := mustParseAddressArg(ctx, traceTo)
+			value := unify2Wei(decimal.RequireFromString(traceValue), traceUnit).BigInt()
+			callArgs := ethutil.CallArgs{To: &to, Value: (*hexutil.Big)(value), Data: common.FromHex(traceHexData)}
+			if traceFrom != "" {
+				from := mustParseAddressArg(ctx, traceFrom)
+				callArgs.From = &from
+			}
+			result, err = ethutil.TraceCall(ctx, globalClient.RpcClient, callArgs, stateBlock(ctx), traceTracer)
+		}
+		checkErr(err)
+
+		if traceRaw || globalOptJsonl || traceTracer == ethutil.TracerPrestate {
+			var indented bytes.Buffer
+			if traceRaw || globalOptJsonl {
+				checkErr(json.Compact(&indented, result))
+			} else {
+				checkErr(json.Indent(&indented, result, "", "  "))
+			}
+			fmt.Printf("%s\n", indented.Bytes())
+			return
+		}
+
+		switch traceTracer {
+		case ethutil.TracerCall:
+			var frame ethutil.CallFrame
+			checkErr(json.Unmarshal(result, &frame))
+			var funcSigs = make(funcSigCache)
+			ethutil.RenderCallTree(os.Stdout, &frame, func(selector []byte) string {
+				if traceNoDecode {
+					return ""
+				}
+				return strings.Join(funcSigs.lookup(hexutil.Encode(selector)), " | ")
+			})
+		case ethutil.TracerStructLogs:
+			var logs ethutil.StructLogsResult
+			checkErr(json.Unmarshal(result, &logs))
+			if !globalOptTerseOutput {
+				fmt.Printf("%-6v %-8v %-16v %12v %10v\n", "depth", "pc", "op", "gas", "cost")
+			}
+			for _, step := range logs.StructLogs {
+				line := fmt.Sprintf("%-6v %-8v %-16v %12v %10v", step.Depth, step.Pc, step.Op, step.Gas, step.GasCost)
+				if step.Error != "" {
+					line += " <-- " + step.Error
+				}
+				fmt.Println(line)
+			}
+			if !globalOptTerseOutput {
+				fmt.Printf("gas used %v, failed %v, return value 0x%v\n", logs.Gas, logs.Failed, strings.TrimPrefix(logs.ReturnValue, "0x"))
+			}
+		}
+	},
+}
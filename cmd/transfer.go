@@ -7,9 +7,9 @@ import (
 	"math/big"
 	"os"
 
+	"github.com/10gic/ethutil/pkg/ethutil"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/shopspring/decimal"
 	"github.com/spf13/cobra"
 )
@@ -22,6 +22,9 @@ func init() {
 	transferCmd.Flags().StringVarP(&transferUnit, "unit", "u", "ether", "wei | gwei | ether, unit of amount")
 	transferCmd.Flags().BoolVarP(&transferNotCheck, "not-check", "", false, "don't check result, return immediately after send transaction")
 	transferCmd.Flags().StringVarP(&transferHexData, "hex-data", "", "", "the payload hex data when transfer")
+
+	addFlags(transferCmd, txFlags)
+	addFlags(transferCmd, fiatFlags)
 }
 
 func validationTransferCmdOpts() bool {
@@ -46,7 +49,7 @@ func validationTransferCmdOpts() bool {
 
 const gasUsedByTransferEth = 21000 // The gas used by any transfer is always 21000
 
-func getGasPrice(client *ethclient.Client) (*big.Int, error) {
+func getGasPrice(ctx context.Context, client *ethclient.Client) (*big.Int, error) {
 	var gasPrice *big.Int
 	if globalOptGasPrice != "" {
 		gasPriceDecimal, err := decimal.NewFromString(globalOptGasPrice)
@@ -57,7 +60,7 @@ func getGasPrice(client *ethclient.Client) (*big.Int, error) {
 		return gasPriceDecimal.Mul(decimal.RequireFromString("1000000000")).BigInt(), nil
 	}
 
-	gasPrice, err := client.SuggestGasPrice(context.Background())
+	gasPrice, err := client.SuggestGasPrice(ctx)
 
 	if globalOptNode == nodeMainnet {
 		// in case of mainnet, get gap price from ethgasstation
@@ -86,12 +89,10 @@ var transferCmd = &cobra.Command{
 			return fmt.Errorf("too many args")
 		}
 
-		targetAddress := args[0]
-		transferAmt := args[1]
-
-		if !isValidEthAddress(targetAddress) {
-			return fmt.Errorf("%v is not a valid eth address", targetAddress)
+		if !isValidAddressArg(cmd.Context(), args[0]) {
+			return fmt.Errorf("%v is not a valid eth address", args[0])
 		}
+		transferAmt := args[1]
 
 		if transferAmt == "all" {
 			return nil
@@ -111,14 +112,14 @@ var transferCmd = &cobra.Command{
 		}
 		log.Printf("Current network is %v", globalOptNode)
 
-		targetAddress := args[0]
+		targetAddress := hexAddressArg(cmd.Context(), args[0])
 		transferAmt := args[1]
 
-		InitGlobalClient(globalOptNodeUrl)
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
 
-		ctx := context.Background()
+		ctx := cmd.Context()
 
-		gasPrice, err := getGasPrice(globalClient.EthClient)
+		gasPrice, err := getGasPrice(cmd.Context(), globalClient.EthClient)
 		checkErr(err)
 
 		var amount decimal.Decimal
@@ -146,7 +147,7 @@ var transferCmd = &cobra.Command{
 			amountInWei = unify2Wei(amount, transferUnit)
 		}
 
-		if tx, err := TransferHelper(globalClient.RpcClient, globalClient.EthClient, globalOptPrivateKey, targetAddress, amountInWei.BigInt(), gasPrice, common.FromHex(transferHexData)); err != nil {
+		if tx, err := TransferHelper(cmd.Context(), globalClient, globalOptPrivateKey, targetAddress, amountInWei.BigInt(), gasPrice, common.FromHex(transferHexData)); err != nil {
 			log.Fatalf("transfer fail: %v", err)
 		} else {
 			log.Printf("transfer finished, tx = %v", tx)
@@ -154,12 +155,13 @@ var transferCmd = &cobra.Command{
 	},
 }
 
-func TransferHelper(rcpClient *rpc.Client, client *ethclient.Client, privateKeyHex string, toAddress string, amountInWei *big.Int, gasPrice *big.Int, data []byte) (string, error) {
-	log.Printf("transfer %v ether (%v wei) from %v to %v",
+func TransferHelper(ctx context.Context, client *ethutil.Client, privateKeyHex string, toAddress string, amountInWei *big.Int, gasPrice *big.Int, data []byte) (string, error) {
+	log.Printf("transfer %v ether (%v wei)%v from %v to %v",
 		wei2Other(bigInt2Decimal(amountInWei), unitEther).String(),
 		amountInWei.String(),
+		fiatSuffix(ctx, amountInWei),
 		extractAddressFromPrivateKey(buildPrivateKeyFromHex(privateKeyHex)).String(),
 		toAddress)
 	var toAddr = common.HexToAddress(toAddress)
-	return Transact(rcpClient, client, buildPrivateKeyFromHex(privateKeyHex), &toAddr, amountInWei, gasPrice, data)
+	return Transact(ctx, client, buildPrivateKeyFromHex(privateKeyHex), &toAddr, amountInWei, gasPrice, data)
 }
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+)
+
+var vanityPrefix string
+var vanitySuffix string
+var vanityRegex string
+var vanityCaseSensitive bool
+var vanityWorkers int
+var vanityProgressInterval time.Duration
+var vanityCreate2Deployer string
+var vanityInitCode string
+var vanityInitCodeHash string
+
+func init() {
+	vanityCmd.Flags().StringVarP(&vanityPrefix, "prefix", "", "", "the hex prefix of address")
+	vanityCmd.Flags().StringVarP(&vanitySuffix, "suffix", "", "", "the hex suffix of address")
+	vanityCmd.Flags().StringVarP(&vanityRegex, "regex", "", "", "the regex matched against address hex without 0x, e.g. '^(dead|beef)'")
+	vanityCmd.Flags().BoolVarP(&vanityCaseSensitive, "case-sensitive", "", false, "match against EIP-55 checksum address, each letter makes it 2 times harder")
+	vanityCmd.Flags().IntVarP(&vanityWorkers, "workers", "", runtime.NumCPU(), "the number of goroutines grinding")
+	vanityCmd.Flags().DurationVarP(&vanityProgressInterval, "progress-interval", "", 5*time.Second, "the interval of progress report")
+	vanityCmd.Flags().StringVarP(&vanityCreate2Deployer, "create2-deployer", "", "", "grind CREATE2 salt of this deployer instead of private key, --init-code or --init-code-hash is required")
+	vanityCmd.Flags().StringVarP(&vanityInitCode, "init-code", "", "", "init code of contract, for CREATE2 salt grinding")
+	vanityCmd.Flags().StringVarP(&vanityInitCodeHash, "init-code-hash", "", "", "keccak256 of init code, for CREATE2 salt grinding")
+}
+
+// reportVanityProgress logs attempts, rate and estimated time until ctx is done.
+func reportVanityProgress(ctx context.Context, attempts *uint64, difficulty float64) {
+	var start = time.Now()
+	var ticker = time.NewTicker(vanityProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n := atomic.LoadUint64(attempts)
+		rate := float64(n) / time.Since(start).Seconds()
+		if rate == 0 {
+			continue
+		}
+		// probability of at least one match in n attempts
+		probability := 1 - math.Pow(1-1/difficulty, float64(n))
+		// time to reach 50% probability in total
+		eta := time.Duration((math.Ln2*difficulty - float64(n)) / rate * float64(time.Second))
+		if eta < 0 {
+			eta = 0
+		}
+		log.Printf("%v attempts, %.0f/s, probability of finding %.2f%%, estimated time to 50%%: %v",
+			n, rate, probability*100, eta.Round(time.Second))
+	}
+}
+
+var vanityCmd = &cobra.Command{
+	Use:   "vanity",
+	Short: "Grind private key (or CREATE2 salt) for address matching prefix, suffix or regex",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("vanity accepts no args")
+		}
+		if vanityPrefix == "" && vanitySuffix == "" && vanityRegex == "" {
+			return fmt.Errorf("at least one of --prefix, --suffix and --regex is required")
+		}
+		if _, err := ethutil.NewVanityMatcher(vanityPrefix, vanitySuffix, vanityRegex, vanityCaseSensitive); err != nil {
+			return err
+		}
+		if vanityWorkers <= 0 {
+			return fmt.Errorf("--workers must be greater than 0")
+		}
+		if vanityProgressInterval <= 0 {
+			return fmt.Errorf("--progress-interval must be greater than 0")
+		}
+		if vanityCreate2Deployer != "" {
+			if !isValidAddressArg(cmd.Context(), vanityCreate2Deployer) {
+				return fmt.Errorf("--create2-deployer %v is not a valid eth address", vanityCreate2Deployer)
+			}
+			if (vanityInitCode == "") == (vanityInitCodeHash == "") {
+				return fmt.Errorf("one of --init-code and --init-code-hash is required")
+			}
+			if vanityInitCode != "" && !isValidHexString(vanityInitCode) {
+				return fmt.Errorf("--init-code must be hex string")
+			}
+			if vanityInitCodeHash != "" {
+				if hash, err := hexutil.Decode(vanityInitCodeHash); err != nil || len(hash) != 32 {
+					return fmt.Errorf("--init-code-hash must be 32 bytes hex string")
+				}
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		matcher, _ := ethutil.NewVanityMatcher(vanityPrefix, vanitySuffix, vanityRegex, vanityCaseSensitive) // validated in Args
+		difficulty := matcher.Difficulty()
+		if vanityRegex != "" {
+			log.Printf("difficulty of --regex is not counted in estimated time")
+		}
+		log.Printf("grinding with %v workers, expected attempts: %.0f", vanityWorkers, difficulty)
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		var attempts uint64
+		go reportVanityProgress(ctx, &attempts, difficulty)
+		var start = time.Now()
+
+		if vanityCreate2Deployer != "" {
+			var initCodeHash common.Hash
+			if vanityInitCodeHash != "" {
+				initCodeHash = common.HexToHash(vanityInitCodeHash)
+			} else {
+				initCodeHash = crypto.Keccak256Hash(common.FromHex(vanityInitCode))
+			}
+			salt, address, err := ethutil.GrindCreate2Salt(ctx, matcher, mustParseAddressArg(cmd.Context(), vanityCreate2Deployer), initCodeHash, vanityWorkers, &attempts)
+			checkErr(err)
+			log.Printf("found after %v attempts in %v", atomic.LoadUint64(&attempts), time.Since(start).Round(time.Millisecond))
+			if globalOptTerseOutput {
+				fmt.Printf("%v %v\n", salt.Hex(), address.Hex())
+			} else {
+				fmt.Printf("salt %v, contract address %v\n", salt.Hex(), address.Hex())
+			}
+			return
+		}
+
+		privateKey, err := ethutil.GrindVanityKey(ctx, matcher, vanityWorkers, &attempts)
+		checkErr(err)
+		log.Printf("found after %v attempts in %v", atomic.LoadUint64(&attempts), time.Since(start).Round(time.Millisecond))
+		privateHexStr := hexutil.Encode(crypto.FromECDSA(privateKey))
+		addr := extractAddressFromPrivateKey(privateKey).String()
+		if globalOptTerseOutput {
+			fmt.Printf("%v %v\n", privateHexStr, addr)
+		} else {
+			fmt.Printf("private key %v, addr %v\n", privateHexStr, addr)
+		}
+	},
+}
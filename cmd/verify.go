@@ -0,0 +1,413 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/spf13/cobra"
+)
+
+var verifyContractName string
+var verifyCompilerVersion string
+var verifyOptimize bool
+var verifyRuns int
+var verifyConstructorArgs string
+var verifyEvmVersion string
+var verifyLicense int
+var verifyNoWait bool
+var verifyManifestFile string
+var verifyVerifier string
+var verifySourcifyUrl string
+
+const (
+	verifierExplorer = "explorer"
+	verifierSourcify = "sourcify"
+	verifierAll      = "all"
+)
+
+func init() {
+	verifyCmd.Flags().StringVarP(&verifyContractName, "contract-name", "", "", "the contract name, e.g. Token, or contracts/Token.sol:Token for standard json input")
+	verifyCmd.Flags().StringVarP(&verifyCompilerVersion, "compiler-version", "", "", "the full solc version, e.g. v0.8.19+commit.7dd6d404")
+	verifyCmd.Flags().BoolVarP(&verifyOptimize, "optimize", "", false, "the optimizer is enabled, ignored by standard json input")
+	verifyCmd.Flags().IntVarP(&verifyRuns, "runs", "", 200, "the optimizer runs, ignored by standard json input")
+	verifyCmd.Flags().StringVarP(&verifyConstructorArgs, "constructor-args", "", "", "the abi encoded constructor arguments in hex, it can't be specified with 'constructor signature' args which are encoded automatically")
+	verifyCmd.Flags().StringVarP(&verifyEvmVersion, "evm-version", "", "", "the evm version, default is the default of compiler")
+	verifyCmd.Flags().IntVarP(&verifyLicense, "license", "", 1, "the license type, 1 is no license, 3 is MIT, see https://etherscan.io/contract-license-types")
+	verifyCmd.Flags().BoolVarP(&verifyNoWait, "no-wait", "", false, "do not wait for the verification result, only print the guid")
+	verifyCmd.Flags().StringVarP(&verifyVerifier, "verifier", "", verifierExplorer, "explorer | sourcify | all, submit source to block explorer, Sourcify, or both")
+	verifyCmd.Flags().StringVarP(&verifySourcifyUrl, "sourcify-url", "", ethutil.DefaultSourcifyUrl, "the Sourcify server")
+	verifyCmd.Flags().StringVarP(&verifyManifestFile, "manifest", "", "", "the deployments manifest, verify the contract deployed on all chains in it, the verification status of each chain is written back to it")
+
+	addFlags(verifyCmd, txFlags, "poll-interval")
+	addFlags(verifyCmd, explorerFlags)
+}
+
+// verifyManifest is the deployments manifest of --manifest, for example:
+//
+//	{
+//	  "deployments": [
+//	    {"network": "mainnet", "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"},
+//	    {"network": "base", "explorer_api_url": "https://api.basescan.org/api", "explorer_api_key": "XXX",
+//	     "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", "constructor_args": "0x..."},
+//	    {"network": "optimism", "chain_id": 10, "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"}
+//	  ]
+//	}
+//
+// explorer_api_url can be omitted if network is one of --node, or chain_id is served by explorers of config file,
+// which is used if both are possible. verification_guid and verification_status are
+// written back to each deployment, deployments already verified are skipped when the manifest is verified again.
+type verifyManifest struct {
+	Deployments []verifyDeployment `json:"deployments"`
+
+	raw map[string]any // the content of manifest, to keep fields unknown to verifyDeployment when it's written back
+}
+
+type verifyDeployment struct {
+	Network            string `json:"network"`
+	ChainId            uint64 `json:"chain_id"` // default is chain id of network if it's one of --node
+	ExplorerApiUrl     string `json:"explorer_api_url"`
+	ExplorerApiKey     string `json:"explorer_api_key"`
+	ExplorerKind       string `json:"explorer_kind"` // etherscan | blockscout, default is detected by explorer_api_url
+	Address            string `json:"address"`
+	ConstructorArgs    string `json:"constructor_args"` // default is --constructor-args
+	VerificationGuid   string `json:"verification_guid"`
+	VerificationStatus string `json:"verification_status"`
+}
+
+// loadVerifyManifest reads and validates the deployments manifest.
+func loadVerifyManifest(file string) (*verifyManifest, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest fail: %w", err)
+	}
+	var manifest verifyManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("parse manifest %v fail: %w", file, err)
+	}
+	if err := json.Unmarshal(content, &manifest.raw); err != nil {
+		return nil, fmt.Errorf("parse manifest %v fail: %w", file, err)
+	}
+	if len(manifest.Deployments) == 0 {
+		return nil, fmt.Errorf("no deployment is found in manifest %v", file)
+	}
+	explorers, err := loadExplorerConfigs()
+	if err != nil {
+		return nil, err
+	}
+	var configured = func(chainId uint64) bool {
+		for _, explorer := range explorers {
+			if containsChainId(explorer.ChainIds, chainId) {
+				return true
+			}
+		}
+		return false
+	}
+	for i, deployment := range manifest.Deployments {
+		if deployment.Network == "" {
+			return nil, fmt.Errorf("deployment %d: network is required", i)
+		}
+		if !isValidEthAddress(deployment.Address) {
+			return nil, fmt.Errorf("deployment %v: %v is not a valid eth address", deployment.Network, deployment.Address)
+		}
+		if deployment.ExplorerApiUrl == "" && nodeApiUrlMap[deployment.Network] == "" && !configured(deployment.chainId()) {
+			return nil, fmt.Errorf("deployment %v: block explorer of network is unknown, explorer_api_url or chain_id served by explorers of config file is required", deployment.Network)
+		}
+		if deployment.ExplorerKind != "" && !contains([]string{ethutil.ExplorerKindEtherscan, ethutil.ExplorerKindBlockscout}, deployment.ExplorerKind) {
+			return nil, fmt.Errorf("deployment %v: invalid explorer_kind %v", deployment.Network, deployment.ExplorerKind)
+		}
+		if deployment.ConstructorArgs != "" && !isValidHexString(deployment.ConstructorArgs) {
+			return nil, fmt.Errorf("deployment %v: constructor_args must be hex", deployment.Network)
+		}
+	}
+	return &manifest, nil
+}
+
+// chainId returns chain_id of deployment, or chain id of network if it's one of --node.
+func (d verifyDeployment) chainId() uint64 {
+	if d.ChainId != 0 {
+		return d.ChainId
+	}
+	return nodeChainIdMap[d.Network]
+}
+
+// setStatus records the verification of deployment i, other fields of manifest are kept as they are.
+func (m *verifyManifest) setStatus(i int, guid string, status string) {
+	m.Deployments[i].VerificationGuid = guid
+	m.Deployments[i].VerificationStatus = status
+	if deployments, ok := m.raw["deployments"].([]any); ok && i < len(deployments) {
+		if deployment, ok := deployments[i].(map[string]any); ok {
+			deployment["verification_guid"] = guid
+			deployment["verification_status"] = status
+		}
+	}
+}
+
+// save writes manifest back to file.
+func (m *verifyManifest) save(file string) error {
+	content, err := json.MarshalIndent(m.raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(content, '\n'), 0644)
+}
+
+// isVerified reports whether status of explorer means the contract is verified.
+func isVerified(status string) bool {
+	return strings.HasPrefix(status, "Pass") || strings.Contains(strings.ToLower(status), "already verified")
+}
+
+// verifyEncodeConstructorArgs returns --constructor-args, or the constructor args encoded by args, which are
+// constructor signature followed by the values, e.g. ["constructor(string,uint256)", "Token", "100"].
+func verifyEncodeConstructorArgs(args []string) (string, error) {
+	if len(args) == 0 {
+		return verifyConstructorArgs, nil
+	}
+	if verifyConstructorArgs != "" {
+		return "", fmt.Errorf("--constructor-args can't be specified with constructor signature")
+	}
+	data, err := ethutil.BuildTxDataForContractDeploy(args[0], args[1:], nil)
+	if err != nil {
+		return "", fmt.Errorf("encode constructor args fail: %w", err)
+	}
+	return hexutil.Encode(data), nil
+}
+
+// verifyRequest returns the verification request of flags, the source and code format are from sourceFile, the
+// constructor args are --constructor-args or encoded from constructorArgs, see verifyEncodeConstructorArgs.
+func verifyRequest(sourceFile string, constructorArgs []string) ethutil.ExplorerVerifyRequest {
+	source, err := os.ReadFile(sourceFile)
+	checkErr(err)
+	var codeFormat = ethutil.ExplorerCodeFormatSingleFile
+	if strings.HasSuffix(strings.ToLower(sourceFile), ".json") {
+		codeFormat = ethutil.ExplorerCodeFormatStandardJson
+		if !strings.Contains(verifyContractName, ":") {
+			log.Fatalf("--contract-name must be in form of <path>:<name> for standard json input")
+		}
+	}
+	encodedConstructorArgs, err := verifyEncodeConstructorArgs(constructorArgs)
+	checkErr(err)
+	return ethutil.ExplorerVerifyRequest{
+		SourceCode:       string(source),
+		CodeFormat:       codeFormat,
+		ContractName:     verifyContractName,
+		CompilerVersion:  verifyCompilerVersion,
+		OptimizationUsed: verifyOptimize,
+		Runs:             verifyRuns,
+		ConstructorArgs:  encodedConstructorArgs,
+		EvmVersion:       verifyEvmVersion,
+		LicenseType:      verifyLicense,
+	}
+}
+
+// verifyOnExplorer submits req to block explorer of --node and waits for the result unless --no-wait, it returns
+// false if the verification fails.
+func verifyOnExplorer(ctx context.Context, req ethutil.ExplorerVerifyRequest) bool {
+	explorer, err := newExplorerClient(ctx)
+	checkErr(err)
+	guid, err := explorer.VerifySource(ctx, req)
+	checkErr(err)
+	log.Printf("verification is submitted to block explorer, guid %v", guid)
+	if verifyNoWait {
+		fmt.Printf("%v\n", guid)
+		return true
+	}
+
+	status, err := explorer.WaitVerifyStatus(ctx, guid, globalOptPollInterval)
+	checkErr(err)
+	fmt.Printf("%v\n", status)
+	return isVerified(status)
+}
+
+// verifyOnSourcify submits req to Sourcify, it returns false if the verification fails. Contract verified with
+// perfect match already is not submitted again.
+func verifyOnSourcify(ctx context.Context, sourceFile string, req ethutil.ExplorerVerifyRequest) bool {
+	log.Printf("Current network is %v", globalOptNode)
+	InitGlobalClient(ctx, globalOptNodeUrl)
+	chainID, err := globalClient.EthClient.ChainID(ctx)
+	checkErr(err)
+	checkNetworkAllowed("requesting Sourcify " + verifySourcifyUrl)
+	sourcify := &ethutil.SourcifyClient{BaseUrl: verifySourcifyUrl}
+
+	status, err := sourcify.CheckByAddress(ctx, req.Address, chainID.Uint64())
+	checkErr(err)
+	if status == ethutil.SourcifyMatchPerfect {
+		fmt.Printf("%v (already verified)\n", status)
+		return true
+	}
+
+	var input = []byte(req.SourceCode)
+	if req.CodeFormat != ethutil.ExplorerCodeFormatStandardJson {
+		input, err = ethutil.SingleFileStandardJsonInput(filepath.Base(sourceFile), req.SourceCode, req.OptimizationUsed, req.Runs, req.EvmVersion)
+		checkErr(err)
+	}
+	status, err = sourcify.VerifySolcJson(ctx, ethutil.SourcifyVerifyRequest{
+		Address:           req.Address,
+		ChainId:           chainID.Uint64(),
+		CompilerVersion:   req.CompilerVersion,
+		ContractName:      req.ContractName,
+		StandardJsonInput: input,
+	})
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		return false
+	}
+	fmt.Printf("%v\n", status)
+	return true
+}
+
+// verifyDeployments submits verification of all deployments of manifest not verified yet, then waits for the results
+// unless --no-wait. The manifest is saved after each status change. It returns false if any verification fails.
+func verifyDeployments(ctx context.Context, manifest *verifyManifest, req ethutil.ExplorerVerifyRequest) bool {
+	var explorers = make([]*ethutil.ExplorerClient, len(manifest.Deployments))
+	var allVerified = true
+	var update = func(i int, guid string, status string) {
+		manifest.setStatus(i, guid, status)
+		checkErr(manifest.save(verifyManifestFile))
+	}
+
+	for i, deployment := range manifest.Deployments {
+		if isVerified(deployment.VerificationStatus) {
+			log.Printf("%v %v is verified already, skip it", deployment.Network, deployment.Address)
+			continue
+		}
+		if deployment.ExplorerApiUrl != "" {
+			explorers[i] = newExplorerClientOf(deployment.ExplorerApiUrl, deployment.ExplorerApiKey)
+			if deployment.ExplorerKind != "" {
+				explorers[i].Kind = deployment.ExplorerKind
+			}
+		} else {
+			var err error
+			explorers[i], err = explorerOfChain(deployment.chainId(), nodeApiUrlMap[deployment.Network], deployment.ExplorerApiKey)
+			checkErr(err) // validated by loadVerifyManifest
+		}
+
+		deploymentReq := req
+		deploymentReq.Address = common.HexToAddress(deployment.Address)
+		if deployment.ConstructorArgs != "" {
+			deploymentReq.ConstructorArgs = deployment.ConstructorArgs
+		}
+		guid, err := explorers[i].VerifySource(ctx, deploymentReq)
+		if err != nil {
+			if isVerified(err.Error()) {
+				update(i, "", "Already Verified")
+			} else {
+				log.Printf("submit verification of %v %v fail: %v", deployment.Network, deployment.Address, err)
+				update(i, "", "Fail - "+err.Error())
+				allVerified = false
+			}
+			explorers[i] = nil
+			continue
+		}
+		log.Printf("verification of %v %v is submitted, guid %v", deployment.Network, deployment.Address, guid)
+		update(i, guid, "Pending in queue")
+	}
+
+	for i, deployment := range manifest.Deployments {
+		if explorers[i] == nil || verifyNoWait {
+			continue
+		}
+		status, err := explorers[i].WaitVerifyStatus(ctx, deployment.VerificationGuid, globalOptPollInterval)
+		if err != nil {
+			log.Printf("check verification status of %v %v fail: %v", deployment.Network, deployment.Address, err)
+			allVerified = false
+			continue
+		}
+		update(i, deployment.VerificationGuid, status)
+		if !isVerified(status) {
+			allVerified = false
+		}
+	}
+	return allVerified
+}
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify contract-address source-file [constructor signature] [arg]... | --manifest manifest-file source-file [constructor signature] [arg]...",
+	Aliases: []string{"verify-contract"},
+	Short:   "Submit source of contract to block explorer for verification",
+	Long: "Submit source of contract to Etherscan-compatible block explorer (--explorer-api-url, default the block " +
+		"explorer of --node) and/or Sourcify (--verifier) for verification, and wait for the result. source-file is a " +
+		"flattened solidity file, or a standard json input (*.json) whose compiler settings are used. Constructor args " +
+		"are encoded from constructor signature and args, e.g. 'constructor(string,uint256)' Token 100, or given by " +
+		"--constructor-args. With --manifest, the contract deployed on all chains listed in the deployments manifest are " +
+		"verified by their block explorers.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if !contains([]string{verifierExplorer, verifierSourcify, verifierAll}, verifyVerifier) {
+			return fmt.Errorf("invalid --verifier %v", verifyVerifier)
+		}
+		if verifyManifestFile != "" {
+			if len(args) < 1 {
+				return fmt.Errorf("source file is required")
+			}
+			if verifyVerifier != verifierExplorer {
+				return fmt.Errorf("--manifest only supports --verifier %v", verifierExplorer)
+			}
+		} else {
+			if len(args) < 2 {
+				return fmt.Errorf("contract address and source file are required")
+			}
+			if !isValidAddressArg(cmd.Context(), args[0]) {
+				return fmt.Errorf("%v is not a valid eth address", args[0])
+			}
+		}
+		if verifyContractName == "" {
+			return fmt.Errorf("--contract-name is required")
+		}
+		if !strings.HasPrefix(verifyCompilerVersion, "v") {
+			return fmt.Errorf("--compiler-version is required, e.g. v0.8.19+commit.7dd6d404")
+		}
+		if verifyConstructorArgs != "" && !isValidHexString(verifyConstructorArgs) {
+			return fmt.Errorf("--constructor-args must be hex")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if verifyManifestFile != "" {
+			manifest, err := loadVerifyManifest(verifyManifestFile)
+			checkErr(err)
+			allVerified := verifyDeployments(ctx, manifest, verifyRequest(args[0], args[1:]))
+			for _, deployment := range manifest.Deployments {
+				if printJSONL(map[string]any{
+					"network":       deployment.Network,
+					jsonlKeyAddress: common.HexToAddress(deployment.Address).Hex(),
+					"guid":          deployment.VerificationGuid,
+					"status":        deployment.VerificationStatus,
+				}) {
+					continue
+				}
+				fmt.Printf("%v %v %v\n", deployment.Network, common.HexToAddress(deployment.Address).Hex(), deployment.VerificationStatus)
+			}
+			if !allVerified {
+				os.Exit(1)
+			}
+			return
+		}
+
+		req := verifyRequest(args[1], args[2:])
+		req.Address = mustParseAddressArg(ctx, args[0])
+		var verified = true
+		if verifyVerifier != verifierSourcify {
+			if verifyVerifier == verifierAll {
+				fmt.Printf("explorer: ")
+			}
+			verified = verifyOnExplorer(ctx, req)
+		}
+		if verifyVerifier != verifierExplorer {
+			if verifyVerifier == verifierAll {
+				fmt.Printf("sourcify: ")
+			}
+			verified = verifyOnSourcify(ctx, args[1], req) && verified
+		}
+		if !verified {
+			os.Exit(1)
+		}
+	},
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "deployments.json")
+	content := `{
+  "contract": "Token",
+  "deployments": [
+    {"network": "mainnet", "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", "tx_hash": "0x01"},
+    {"network": "base", "explorer_api_url": "https://api.basescan.org/api", "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"}
+  ]
+}`
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := loadVerifyManifest(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest.setStatus(1, "guid1", "Pass - Verified")
+	if err := manifest.save(file); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	saved, err := loadVerifyManifest(file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved.Deployments[1].VerificationGuid != "guid1" || !isVerified(saved.Deployments[1].VerificationStatus) {
+		t.Fatalf("status is not saved: %+v", saved.Deployments[1])
+	}
+	if saved.Deployments[0].VerificationStatus != "" {
+		t.Fatalf("unexpected status: %+v", saved.Deployments[0])
+	}
+	// fields unknown to verifyDeployment are kept
+	if saved.raw["contract"] != "Token" {
+		t.Fatalf("contract is lost")
+	}
+	raw, _ := json.Marshal(saved.raw["deployments"])
+	var deployments []map[string]string
+	if err := json.Unmarshal(raw, &deployments); err != nil || deployments[0]["tx_hash"] != "0x01" {
+		t.Fatalf("tx_hash is lost: %s", raw)
+	}
+}
+
+func TestLoadVerifyManifestInvalid(t *testing.T) {
+	tests := []string{
+		`{"deployments": []}`,
+		`{"deployments": [{"address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"}]}`,
+		`{"deployments": [{"network": "mainnet", "address": "0x1234"}]}`,
+		`{"deployments": [{"network": "unknown", "address": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"}]}`,
+	}
+
+	for i, test := range tests {
+		file := filepath.Join(t.TempDir(), "deployments.json")
+		if err := os.WriteFile(file, []byte(test), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadVerifyManifest(file); err == nil {
+			t.Fatalf("test %d: expected error", i)
+		}
+	}
+}
+
+func TestVerifyEncodeConstructorArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+		hasErr   bool
+	}{
+		{nil, "", false},
+		{[]string{"constructor(uint256,bool)", "123", "true"}, "0x000000000000000000000000000000000000000000000000000000000000007b0000000000000000000000000000000000000000000000000000000000000001", false},
+		{[]string{"constructor(uint256,bool)", "123"}, "", true},
+	}
+
+	for i, test := range tests {
+		got, err := verifyEncodeConstructorArgs(test.args)
+		if (err != nil) != test.hasErr {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
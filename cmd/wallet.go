@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var walletScanCount int
+var walletScanPresets []string
+
+func init() {
+	walletScanCmd.Flags().IntVarP(&walletScanCount, "count", "n", 5, "the number of addresses checked for each derivation preset")
+	walletScanCmd.Flags().StringSliceVarP(&walletScanPresets, "presets", "", derivationPresetNames, "the derivation presets to check")
+
+	walletCmd.AddCommand(walletScanCmd)
+
+	addFlags(walletCmd, exportFlags)
+}
+
+var walletCmd = &cobra.Command{
+	Use:   "wallet",
+	Short: "HD wallet helpers",
+}
+
+var walletScanCmd = &cobra.Command{
+	Use:   "scan mnemonic",
+	Short: "Check the first N addresses of each derivation preset for balances, to locate accounts created by other wallets",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires exactly one mnemonic")
+		}
+		if !bip39.IsMnemonicValid(args[0]) {
+			return fmt.Errorf("invalid mnemonic")
+		}
+		if walletScanCount <= 0 {
+			return fmt.Errorf("--count must be greater than 0")
+		}
+		for _, preset := range walletScanPresets {
+			if _, ok := derivationPathPresets[preset]; !ok {
+				return fmt.Errorf("invalid derivation preset: %v", preset)
+			}
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		mnemonic := args[0]
+		log.Printf("Current network is %v", globalOptNode)
+
+		InitGlobalClient(cmd.Context(), globalOptNodeUrl)
+		ctx := cmd.Context()
+
+		type account struct {
+			preset string
+			path   string
+			addr   string
+		}
+
+		// presets may share paths (e.g. bip44 and trezor), each path is checked once
+		var accounts []account
+		var checked = make(map[string]bool)
+		for _, preset := range walletScanPresets {
+			for index := 0; index < walletScanCount; index++ {
+				path, err := derivationPathOfPreset(preset, index)
+				checkErr(err)
+				if checked[path] {
+					continue
+				}
+				checked[path] = true
+
+				privateKeyBytes, err := mnemonicToPrivateKey(mnemonic, path)
+				checkErr(err)
+				privateKey, err := crypto.ToECDSA(privateKeyBytes)
+				checkErr(err)
+				accounts = append(accounts, account{preset, path, extractAddressFromPrivateKey(privateKey).String()})
+			}
+		}
+
+		var addresses []string
+		for _, a := range accounts {
+			addresses = append(addresses, a.addr)
+		}
+
+		balances, err := queryEthBalances(ctx, addresses, nil)
+		checkErr(err)
+		// an account with zero balance may still be used, nonce tells it
+		var addrs []common.Address
+		for _, addr := range addresses {
+			addrs = append(addrs, common.HexToAddress(addr))
+		}
+		nonces, err := ethutil.BatchNonces(ctx, globalClient.RpcClient, addrs, nil, globalOptRpcBatchSize)
+		checkErr(err)
+
+		table := newExportTable(exportColumn{"derivation_preset", columnString}, exportColumn{"derivation_path", columnString},
+			exportColumn{"address", columnAddress}, exportColumn{"balance_wei", columnUint256}, exportColumn{"nonce", columnInt64})
+		var found int
+		for i, a := range accounts {
+			nonce := nonces[i]
+			used := balances[i].Sign() > 0 || nonce > 0
+			if used {
+				found++
+			}
+			table.Append(a.preset, a.path, a.addr, balances[i], nonce)
+			if globalOptExport != "" {
+				continue
+			}
+			if globalOptTerseOutput {
+				if used {
+					fmt.Printf("%v %v %s\n", a.path, a.addr, wei2Other(bigInt2Decimal(balances[i]), unitEther).String())
+				}
+				continue
+			}
+			fmt.Printf("%-14v %-20v %v, balance %s ether, nonce %v\n", a.preset, a.path, a.addr, wei2Other(bigInt2Decimal(balances[i]), unitEther).String(), nonce)
+		}
+		exportResults(table)
+		log.Printf("%v of %v addresses have balance or txs", found, len(accounts))
+	},
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/tyler-smith/go-bip39"
+)
+
+var walletNewCount int
+var walletNewMnemonic bool
+var walletNewWords int
+var walletNewKeystoreDir string
+var walletNewEncryptTo string
+var walletNewPasswordFile string
+var walletNewLightKdf bool
+var walletNewJson bool
+var walletDecryptPasswordFile string
+
+func init() {
+	walletNewCmd.Flags().IntVarP(&walletNewCount, "count", "n", 1, "the number of accounts to generate")
+	walletNewCmd.Flags().BoolVarP(&walletNewMnemonic, "mnemonic", "", false, "generate a new mnemonic and derive accounts from it (path m/44'/60'/0'/0/i)")
+	walletNewCmd.Flags().IntVarP(&walletNewWords, "words", "", 12, "12 | 24, the number of words of --mnemonic")
+	walletNewCmd.Flags().StringVarP(&walletNewKeystoreDir, "keystore-dir", "", "", "write each account as keystore json (V3) to this directory, private keys are not printed")
+	walletNewCmd.Flags().StringVarP(&walletNewEncryptTo, "encrypt-to", "", "", "write the generated accounts (and mnemonic) to this file encrypted by password, private keys are not printed. It can be read by wallet decrypt")
+	walletNewCmd.Flags().StringVarP(&walletNewPasswordFile, "password-file", "", "", "the file containing password of --keystore-dir and --encrypt-to, password is read from stdin if not specified")
+	walletNewCmd.Flags().BoolVarP(&walletNewLightKdf, "light-kdf", "", false, "use light scrypt parameters, faster but weaker")
+	walletNewCmd.Flags().BoolVarP(&walletNewJson, "json", "", false, "output in json")
+	walletDecryptCmd.Flags().BoolVarP(&walletNewJson, "json", "", false, "output in json")
+	walletDecryptCmd.Flags().StringVarP(&walletDecryptPasswordFile, "password-file", "", "", "the file containing password, password is read from stdin if not specified")
+
+	walletCmd.AddCommand(walletNewCmd)
+	walletCmd.AddCommand(walletDecryptCmd)
+}
+
+type walletNewAccount struct {
+	Address    string `json:"address"`
+	PrivateKey string `json:"private_key,omitempty"`
+	PublicKey  string `json:"public_key"`
+	Path       string `json:"path,omitempty"`
+	Keystore   string `json:"keystore,omitempty"`
+}
+
+type walletNewResult struct {
+	Mnemonic string             `json:"mnemonic,omitempty"`
+	Accounts []walletNewAccount `json:"accounts"`
+}
+
+// stdinReader is shared by prompts, so that lines buffered by one prompt are not lost when stdin is a pipe
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readPassword reads password from the first line of passwordFile, or from stdin if passwordFile is empty.
+func readPassword(passwordFile string) string {
+	if passwordFile != "" {
+		content, err := os.ReadFile(passwordFile)
+		checkErr(err)
+		return strings.TrimRight(strings.SplitN(string(content), "\n", 2)[0], "\r")
+	}
+	fmt.Fprintf(os.Stderr, "password: ")
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		log.Fatalf("read password fail: %v", err)
+	}
+	return strings.TrimRight(line, "\r\n")
+}
+
+// printWalletNewResult prints result in text or json (--json).
+func printWalletNewResult(result walletNewResult) {
+	if walletNewJson {
+		content, err := json.MarshalIndent(result, "", "  ")
+		checkErr(err)
+		fmt.Printf("%s\n", content)
+		return
+	}
+	if result.Mnemonic != "" {
+		fmt.Printf("mnemonic: %v\n", result.Mnemonic)
+	}
+	for _, account := range result.Accounts {
+		if globalOptTerseOutput {
+			fmt.Printf("%v\n", strings.TrimSpace(account.PrivateKey+" "+account.Address))
+			continue
+		}
+		var fields = []string{"addr " + account.Address}
+		if account.PrivateKey != "" {
+			fields = append(fields, "private key "+account.PrivateKey)
+		}
+		fields = append(fields, "public key "+account.PublicKey)
+		if account.Path != "" {
+			fields = append(fields, "path "+account.Path)
+		}
+		if account.Keystore != "" {
+			fields = append(fields, "keystore "+account.Keystore)
+		}
+		fmt.Printf("%v\n", strings.Join(fields, ", "))
+	}
+}
+
+var walletNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Generate new accounts (optionally from a new mnemonic), output as plain text, json, keystore json or encrypted file",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("wallet new accepts no args")
+		}
+		if walletNewCount <= 0 {
+			return fmt.Errorf("--count must be greater than 0")
+		}
+		if walletNewWords != 12 && walletNewWords != 24 {
+			return fmt.Errorf("--words must be 12 or 24")
+		}
+		if walletNewKeystoreDir != "" && walletNewEncryptTo != "" {
+			return fmt.Errorf("--keystore-dir and --encrypt-to cannot be specified at the same time")
+		}
+		if walletNewKeystoreDir != "" && walletNewMnemonic {
+			return fmt.Errorf("--keystore-dir cannot save mnemonic, use --encrypt-to instead")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		var result walletNewResult
+		var privateKeys []*ecdsa.PrivateKey
+		var paths []string
+		if walletNewMnemonic {
+			entropy, err := bip39.NewEntropy(walletNewWords / 3 * 32) // 12 words: 128 bits, 24 words: 256 bits
+			checkErr(err)
+			result.Mnemonic, err = bip39.NewMnemonic(entropy)
+			checkErr(err)
+			privateKeys, err = bip44MnemonicKeys(result.Mnemonic, walletNewCount)
+			checkErr(err)
+			for i := 0; i < walletNewCount; i++ {
+				path, _ := derivationPathOfPreset("bip44", i)
+				paths = append(paths, path)
+			}
+		} else {
+			for i := 0; i < walletNewCount; i++ {
+				privateKey, err := crypto.GenerateKey()
+				checkErr(err)
+				privateKeys = append(privateKeys, privateKey)
+			}
+		}
+
+		for i, privateKey := range privateKeys {
+			account := walletNewAccount{
+				Address:    extractAddressFromPrivateKey(privateKey).String(),
+				PrivateKey: hexutil.Encode(crypto.FromECDSA(privateKey)),
+				PublicKey:  hexutil.Encode(crypto.FromECDSAPub(&privateKey.PublicKey)),
+			}
+			if paths != nil {
+				account.Path = paths[i]
+			}
+			result.Accounts = append(result.Accounts, account)
+		}
+
+		var scryptN, scryptP = keystore.StandardScryptN, keystore.StandardScryptP
+		if walletNewLightKdf {
+			scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+		}
+
+		if walletNewKeystoreDir != "" {
+			password := readPassword(walletNewPasswordFile)
+			ks := keystore.NewKeyStore(walletNewKeystoreDir, scryptN, scryptP)
+			for i, privateKey := range privateKeys {
+				account, err := ks.ImportECDSA(privateKey, password)
+				checkErr(err)
+				result.Accounts[i].Keystore = account.URL.Path
+				result.Accounts[i].PrivateKey = ""
+			}
+		}
+
+		if walletNewEncryptTo != "" {
+			password := readPassword(walletNewPasswordFile)
+			plaintext, err := json.Marshal(result)
+			checkErr(err)
+			encrypted, err := keystore.EncryptDataV3(plaintext, []byte(password), scryptN, scryptP)
+			checkErr(err)
+			content, err := json.MarshalIndent(encrypted, "", "  ")
+			checkErr(err)
+			checkErr(os.WriteFile(walletNewEncryptTo, content, 0600))
+			log.Printf("%v accounts are encrypted to %v", len(result.Accounts), walletNewEncryptTo)
+
+			result.Mnemonic = ""
+			for i := range result.Accounts {
+				result.Accounts[i].PrivateKey = ""
+			}
+		}
+
+		printWalletNewResult(result)
+	},
+}
+
+var walletDecryptCmd = &cobra.Command{
+	Use:   "decrypt file",
+	Short: "Decrypt accounts encrypted by wallet new --encrypt-to",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		content, err := os.ReadFile(args[0])
+		checkErr(err)
+		var encrypted keystore.CryptoJSON
+		checkErr(json.Unmarshal(content, &encrypted))
+		plaintext, err := keystore.DecryptDataV3(encrypted, readPassword(walletDecryptPasswordFile))
+		checkErr(err)
+		var result walletNewResult
+		checkErr(json.Unmarshal(plaintext, &result))
+		printWalletNewResult(result)
+	},
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/10gic/ethutil/pkg/ethutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cobra"
+)
+
+var wethAddress string
+
+func init() {
+	for _, c := range []*cobra.Command{wrapCmd, unwrapCmd} {
+		c.Flags().StringVarP(&wethAddress, "weth", "", "", "the WETH contract, default is the canonical wrapped native token of current chain")
+	}
+
+	addFlags(wrapCmd, txFlags)
+	addFlags(unwrapCmd, txFlags)
+}
+
+// wethArgs validates args: amount in ether, or all if allowAll.
+func wethArgs(allowAll bool) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("requires amount")
+		}
+		if wethAddress != "" && !isValidAddressArg(cmd.Context(), wethAddress) {
+			return fmt.Errorf("%v is not a valid eth address", wethAddress)
+		}
+		if allowAll && args[0] == "all" {
+			return nil
+		}
+		if amount, err := decimal.NewFromString(args[0]); err != nil || !amount.IsPositive() {
+			return fmt.Errorf("%v is not a valid amount", args[0])
+		}
+		return nil
+	}
+}
+
+// currentWeth returns --weth, or the wrapped native token of current chain.
+func currentWeth(ctx context.Context) common.Address {
+	if wethAddress != "" {
+		return mustParseAddressArg(ctx, wethAddress)
+	}
+	chainId := currentChainId(ctx)
+	weth, ok := ethutil.WrappedNativeTokens[chainId]
+	if !ok {
+		log.Fatalf("no known WETH of chain %v, --weth is required", chainId)
+	}
+	return weth
+}
+
+var wrapCmd = &cobra.Command{
+	Use:   "wrap amount",
+	Short: "Wrap eth to WETH by deposit() of WETH contract, unit of amount is ether",
+	Args:  wethArgs(false),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for wrap command")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+		weth := currentWeth(ctx)
+
+		amount := unify2Wei(decimal.RequireFromString(args[0]), unitEther).BigInt()
+		txData, err := ethutil.BuildTxInputData("deposit()", nil)
+		checkErr(err)
+		log.Printf("wrap %v ether to WETH %v", args[0], weth.Hex())
+		tx, err := Transact(ctx, globalClient, buildPrivateKeyFromHex(globalOptPrivateKey), &weth, amount, nil, txData)
+		checkErr(err)
+		log.Printf("transaction %s finished", tx)
+	},
+}
+
+var unwrapCmd = &cobra.Command{
+	Use:   "unwrap amount",
+	Short: "Unwrap WETH to eth by withdraw(uint256) of WETH contract, unit of amount is ether, all means the whole WETH balance",
+	Args:  wethArgs(true),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if globalOptPrivateKey == "" {
+			log.Fatalf("--private-key is required for unwrap command")
+		}
+		log.Printf("Current network is %v", globalOptNode)
+		InitGlobalClient(ctx, globalOptNodeUrl)
+		weth := currentWeth(ctx)
+		privateKey := buildPrivateKeyFromHex(globalOptPrivateKey)
+
+		values, err := ethutil.CallAndUnpack(ctx, globalClient.EthClient, weth, erc20FuncSignature["balanceOf"], []string{extractAddressFromPrivateKey(privateKey).Hex()})
+		checkErr(err)
+		balance := values[0].(*big.Int)
+		amount := balance
+		if args[0] != "all" {
+			amount = unify2Wei(decimal.RequireFromString(args[0]), unitEther).BigInt()
+		}
+		if amount.Sign() == 0 || amount.Cmp(balance) > 0 {
+			log.Fatalf("WETH balance %v ether is insufficient", wei2Other(bigInt2Decimal(balance), unitEther))
+		}
+
+		txData, err := ethutil.BuildTxInputData("withdraw(uint256)", []string{amount.String()})
+		checkErr(err)
+		log.Printf("unwrap %v WETH of %v to ether", wei2Other(bigInt2Decimal(amount), unitEther), weth.Hex())
+		tx, err := Transact(ctx, globalClient, privateKey, &weth, big.NewInt(0), nil, txData)
+		checkErr(err)
+		log.Printf("transaction %s finished", tx)
+	},
+}
@@ -1,4 +1,4 @@
-package cmd
+package ethutil
 
 import (
 	"encoding/hex"
@@ -185,7 +185,7 @@ func TestEncodeParameters(t *testing.T) {
 	}
 
 	for i, tc := range tests {
-		got, _ := encodeParameters(tc.input1, tc.input2)
+		got, _ := EncodeParameters(tc.input1, tc.input2)
 		if !reflect.DeepEqual(tc.want, got) {
 			// fmt.Printf("%v", hex.EncodeToString(got))
 			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.want, got)
@@ -282,7 +282,7 @@ func TestParseFuncSignature(t *testing.T) {
 	}
 
 	for i, tc := range tests {
-		gotFn, gotArgs, _ := parseFuncSignature(tc.input)
+		gotFn, gotArgs, _ := ParseFuncSignature(tc.input)
 		if !reflect.DeepEqual(tc.wantFn, gotFn) {
 			// fmt.Printf("%v", hex.EncodeToString(got))
 			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.wantFn, gotFn)
@@ -293,3 +293,70 @@ func TestParseFuncSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFuncSignatureTupleArgs(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantFn   string
+		wantArgs []string
+	}{
+		{
+			input:    "fn((uint256,address))",
+			wantFn:   "fn",
+			wantArgs: []string{"(uint256,address)"},
+		},
+		{
+			input:    "fn(uint256)",
+			wantFn:   "fn",
+			wantArgs: []string{"uint256"},
+		},
+		{
+			input:    "fn()",
+			wantFn:   "fn",
+			wantArgs: nil,
+		},
+		{
+			input:    "fn((uint256,address)[])",
+			wantFn:   "fn",
+			wantArgs: []string{"(uint256,address)[]"},
+		},
+	}
+
+	for i, tc := range tests {
+		gotFn, gotArgs, err := ParseFuncSignature(tc.input)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i+1, err)
+		}
+		if tc.wantFn != gotFn {
+			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.wantFn, gotFn)
+		}
+		if !reflect.DeepEqual(tc.wantArgs, gotArgs) {
+			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.wantArgs, gotArgs)
+		}
+	}
+}
+
+func TestBuildReturnArgs(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantTypes []string
+	}{
+		{"function getOwners() returns (address[])", []string{"address[]"}},
+		{"function f() returns (uint256, bool[2])", []string{"uint256", "bool[2]"}},
+		{"function f() returns ((address, bool)[])", []string{"(address,bool)[]"}},
+	}
+
+	for i, tc := range tests {
+		args, err := BuildReturnArgs(tc.input)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i+1, err)
+		}
+		var gotTypes []string
+		for _, arg := range args {
+			gotTypes = append(gotTypes, arg.Type.String())
+		}
+		if !reflect.DeepEqual(tc.wantTypes, gotTypes) {
+			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.wantTypes, gotTypes)
+		}
+	}
+}
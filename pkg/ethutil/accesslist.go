@@ -0,0 +1,41 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CreateAccessList calls eth_createAccessList, returns the access list of the call and the gas used by the call
+// when the access list is attached.
+// See: https://eips.ethereum.org/EIPS/eip-2930
+func CreateAccessList(ctx context.Context, rpcClient *rpc.Client, fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte) (types.AccessList, uint64, error) {
+	var arg = map[string]interface{}{
+		"from": fromAddress,
+		"data": hexutil.Bytes(data),
+	}
+	if toAddress != nil {
+		arg["to"] = toAddress
+	}
+	if amount != nil {
+		arg["value"] = (*hexutil.Big)(amount)
+	}
+
+	var result struct {
+		AccessList types.AccessList `json:"accessList"`
+		GasUsed    hexutil.Uint64   `json:"gasUsed"`
+		Error      string           `json:"error,omitempty"`
+	}
+	if err := rpcClient.CallContext(ctx, &result, "eth_createAccessList", arg, "pending"); err != nil {
+		return nil, 0, fmt.Errorf("eth_createAccessList fail: %w", err)
+	}
+	if result.Error != "" {
+		return nil, 0, fmt.Errorf("eth_createAccessList fail: %v", result.Error)
+	}
+	return result.AccessList, uint64(result.GasUsed), nil
+}
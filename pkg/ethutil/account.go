@@ -0,0 +1,150 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Well-known ERC-4337 (EntryPoint v0.6) smart account contracts, they are deployed to the same address in all chains.
+var (
+	SimpleAccountFactoryAddress = common.HexToAddress("0x9406Cc6185a346906296840746125a0E44976454")
+	Safe4337ModuleAddress       = common.HexToAddress("0xa581c4A4DB7175302464fF3C06380BC3270b4037") // v0.2.0
+	SafeModuleSetupAddress      = common.HexToAddress("0x8EcD4ec46D4D2a6B64fE960B3D64e8B94B2234eb") // v0.2.0
+	SafeProxyFactoryAddress     = common.HexToAddress("0x4e1DCf7AD4e460CfD30791CCC4F9c8a4f820ec67") // v1.4.1
+	SafeL2SingletonAddress      = common.HexToAddress("0x29fcB43b46531BcA003ddC8FCB67FFE91900C762") // v1.4.1
+)
+
+// dummySignature is a well-formed ecdsa signature used for gas estimation.
+var dummySignature = hexutil.MustDecode("0xfffffffffffffffffffffffffffffff0000000000000000000000000000000007aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa1c")
+
+// SmartAccount builds and signs user operations of a kind of ERC-4337 smart account.
+type SmartAccount interface {
+	// InitCode returns factory address followed by factory call data, which deploys the account.
+	InitCode() ([]byte, error)
+	// ExecuteData returns account call data which calls to with value and data.
+	ExecuteData(to common.Address, value *big.Int, data []byte) ([]byte, error)
+	// DummySignature returns a signature for gas estimation.
+	DummySignature() []byte
+	// Sign signs op of account in entryPoint.
+	Sign(op *UserOperation, entryPoint common.Address, chainID *big.Int) ([]byte, error)
+}
+
+// SimpleAccount is the SimpleAccount of eth-infinitism account-abstraction v0.6, owned by an eoa.
+type SimpleAccount struct {
+	Owner *ecdsa.PrivateKey
+	Salt  *big.Int
+}
+
+func (a *SimpleAccount) InitCode() ([]byte, error) {
+	data, err := BuildTxInputData("createAccount(address,uint256)", []string{AddressFromPrivateKey(a.Owner).Hex(), a.Salt.String()})
+	if err != nil {
+		return nil, err
+	}
+	return append(SimpleAccountFactoryAddress.Bytes(), data...), nil
+}
+
+func (a *SimpleAccount) ExecuteData(to common.Address, value *big.Int, data []byte) ([]byte, error) {
+	return BuildTxInputData("execute(address,uint256,bytes)", []string{to.Hex(), value.String(), hexutil.Encode(data)})
+}
+
+func (a *SimpleAccount) DummySignature() []byte {
+	return dummySignature
+}
+
+func (a *SimpleAccount) Sign(op *UserOperation, entryPoint common.Address, chainID *big.Int) ([]byte, error) {
+	userOpHash, err := op.Hash(entryPoint, chainID)
+	if err != nil {
+		return nil, err
+	}
+	// SimpleAccount verifies personal_sign signature of user operation hash
+	signature, err := PersonalSignBytes(userOpHash.Bytes(), a.Owner)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.MustDecode(signature), nil
+}
+
+// Safe4337Account is a 1/1 Safe with Safe4337Module enabled, owned by an eoa.
+type Safe4337Account struct {
+	Owner *ecdsa.PrivateKey
+	Salt  *big.Int
+}
+
+func (a *Safe4337Account) InitCode() ([]byte, error) {
+	enableModulesData, err := BuildTxInputData("enableModules(address[])", []string{"[" + Safe4337ModuleAddress.Hex() + "]"})
+	if err != nil {
+		return nil, err
+	}
+	zero := common.Address{}.Hex()
+	setupData, err := BuildTxInputData("setup(address[],uint256,address,bytes,address,address,uint256,address)", []string{
+		"[" + AddressFromPrivateKey(a.Owner).Hex() + "]", "1", SafeModuleSetupAddress.Hex(), hexutil.Encode(enableModulesData),
+		Safe4337ModuleAddress.Hex(), zero, "0", zero,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := BuildTxInputData("createProxyWithNonce(address,bytes,uint256)", []string{SafeL2SingletonAddress.Hex(), hexutil.Encode(setupData), a.Salt.String()})
+	if err != nil {
+		return nil, err
+	}
+	return append(SafeProxyFactoryAddress.Bytes(), data...), nil
+}
+
+func (a *Safe4337Account) ExecuteData(to common.Address, value *big.Int, data []byte) ([]byte, error) {
+	return BuildTxInputData("executeUserOp(address,uint256,bytes,uint8)", []string{to.Hex(), value.String(), hexutil.Encode(data), "0"})
+}
+
+func (a *Safe4337Account) DummySignature() []byte {
+	// validAfter (6 bytes) and validUntil (6 bytes) followed by owner signature
+	return append(make([]byte, 12), dummySignature...)
+}
+
+func (a *Safe4337Account) Sign(op *UserOperation, entryPoint common.Address, chainID *big.Int) ([]byte, error) {
+	domainSeparator, err := EncodeParameters([]string{"bytes32", "uint256", "address"}, []string{
+		crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)")).Hex(),
+		chainID.String(),
+		Safe4337ModuleAddress.Hex(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// validAfter and validUntil are both 0, i.e. the signature never expires
+	structData, err := EncodeParameters(
+		[]string{"bytes32", "address", "uint256", "bytes32", "bytes32", "uint256", "uint256", "uint256", "uint256", "uint256", "bytes32", "uint48", "uint48", "address"},
+		[]string{
+			crypto.Keccak256Hash([]byte("SafeOp(address safe,uint256 nonce,bytes initCode,bytes callData,uint256 callGasLimit,uint256 verificationGasLimit,uint256 preVerificationGas,uint256 maxFeePerGas,uint256 maxPriorityFeePerGas,bytes paymasterAndData,uint48 validAfter,uint48 validUntil,address entryPoint)")).Hex(),
+			op.Sender.Hex(),
+			op.Nonce.ToInt().String(),
+			crypto.Keccak256Hash(op.InitCode).Hex(),
+			crypto.Keccak256Hash(op.CallData).Hex(),
+			op.CallGasLimit.ToInt().String(),
+			op.VerificationGasLimit.ToInt().String(),
+			op.PreVerificationGas.ToInt().String(),
+			op.MaxFeePerGas.ToInt().String(),
+			op.MaxPriorityFeePerGas.ToInt().String(),
+			crypto.Keccak256Hash(op.PaymasterAndData).Hex(),
+			"0",
+			"0",
+			entryPoint.Hex(),
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	var typedData = []byte{0x19, 0x01}
+	typedData = append(typedData, crypto.Keccak256(domainSeparator)...)
+	typedData = append(typedData, crypto.Keccak256(structData)...)
+	signature, err := crypto.Sign(crypto.Keccak256(typedData), a.Owner)
+	if err != nil {
+		return nil, fmt.Errorf("sign safe operation fail: %w", err)
+	}
+	signature[64] += 27
+
+	return append(make([]byte, 12), signature...), nil
+}
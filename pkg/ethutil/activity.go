@@ -0,0 +1,58 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// searchFirstBlock returns the first block in [lo, hi] which satisfies pred, pred must be monotone (false for
+// blocks before some block and true since it). hi+1 is returned if no block satisfies pred.
+func searchFirstBlock(lo, hi uint64, pred func(block uint64) (bool, error)) (uint64, error) {
+	end := hi + 1
+	for lo < end {
+		mid := lo + (end-lo)/2
+		ok, err := pred(mid)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			end = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// NonceBlock returns the first block (before or at latest) at which nonce of address is not less than nonce, i.e. the
+// block of the (nonce-1)th tx sent by address (or the creation of contract). It binary searches historical nonce,
+// so archive node is required.
+func NonceBlock(ctx context.Context, client *ethclient.Client, address common.Address, nonce uint64, latest uint64) (uint64, error) {
+	return searchFirstBlock(0, latest, func(block uint64) (bool, error) {
+		n, err := client.NonceAt(ctx, address, new(big.Int).SetUint64(block))
+		return n >= nonce, err
+	})
+}
+
+// AccountActivity returns the blocks of the first and the last tx sent by address, found by NonceBlock. Both are 0 if
+// address has never sent tx. Received transfers are not counted as activity.
+func AccountActivity(ctx context.Context, client *ethclient.Client, address common.Address) (first uint64, last uint64, nonce uint64, err error) {
+	latest, err := client.BlockNumber(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	nonce, err = client.NonceAt(ctx, address, new(big.Int).SetUint64(latest))
+	if err != nil || nonce == 0 {
+		return 0, 0, nonce, err
+	}
+	if first, err = NonceBlock(ctx, client, address, 1, latest); err != nil {
+		return 0, 0, nonce, err
+	}
+	if last, err = NonceBlock(ctx, client, address, nonce, latest); err != nil {
+		return 0, 0, nonce, err
+	}
+	return first, last, nonce, nil
+}
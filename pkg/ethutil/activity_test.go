@@ -0,0 +1,32 @@
+package ethutil
+
+import "testing"
+
+func TestSearchFirstBlock(t *testing.T) {
+	tests := []struct {
+		lo, hi   uint64
+		since    uint64 // pred is true since this block
+		expected uint64
+	}{
+		{0, 100, 0, 0},
+		{0, 100, 37, 37},
+		{0, 100, 100, 100},
+		{0, 100, 101, 101},
+		{10, 10, 10, 10},
+		{0, 17_000_000, 12_345_678, 12_345_678},
+	}
+
+	for i, test := range tests {
+		var calls int
+		got, err := searchFirstBlock(test.lo, test.hi, func(block uint64) (bool, error) {
+			calls++
+			return block >= test.since, nil
+		})
+		if err != nil || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+		if calls > 64 {
+			t.Fatalf("test %d: expected: at most 64 calls, got: %v", i, calls)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Approval is the approval of a signed tx by a second operator, it's required before broadcasting the tx in
+// two-person (four-eyes) mode. Signature is the personal_sign signature of the message returned by approvalMessage.
+type Approval struct {
+	TxHash    common.Hash    `json:"tx_hash"`
+	Approver  common.Address `json:"approver"`
+	ExpiresAt int64          `json:"expires_at,omitempty"` // unix timestamp, 0 means never expire
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// ApprovalRequired returns whether tx requires approval of a second operator, threshold nil means all txs require it.
+// Tx with input data (e.g. token transfer, safe exec or any contract call, contract creation) always requires approval,
+// as the value it moves can't be told by the value of tx.
+func ApprovalRequired(tx *types.Transaction, threshold *big.Int) bool {
+	if threshold == nil || len(tx.Data()) > 0 || tx.To() == nil {
+		return true
+	}
+	return tx.Value().Cmp(threshold) >= 0
+}
+
+// approvalMessage returns the message signed by approver.
+func approvalMessage(txHash common.Hash, expiresAt int64) []byte {
+	return []byte(fmt.Sprintf("approve tx %v, expires at %v", txHash.Hex(), expiresAt))
+}
+
+// NewApproval signs approval of tx txHash by privateKey of approver, expiresAt is unix timestamp, 0 means never expire.
+func NewApproval(txHash common.Hash, expiresAt int64, privateKey *ecdsa.PrivateKey) (*Approval, error) {
+	signature, err := PersonalSignBytes(approvalMessage(txHash, expiresAt), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Approval{
+		TxHash:    txHash,
+		Approver:  AddressFromPrivateKey(privateKey),
+		ExpiresAt: expiresAt,
+		Signature: hexutil.MustDecode(signature),
+	}, nil
+}
+
+// Verify checks that approval is signed for tx txHash by one of approvers, is not expired at now, and the approver is
+// not sender of tx (one person can not approve the tx of himself).
+func (a *Approval) Verify(txHash common.Hash, sender common.Address, approvers []common.Address, now time.Time) error {
+	if a.TxHash != txHash {
+		return fmt.Errorf("approval is for tx %v, not for tx %v", a.TxHash.Hex(), txHash.Hex())
+	}
+	if a.ExpiresAt > 0 && now.Unix() > a.ExpiresAt {
+		return fmt.Errorf("approval expired at %v", time.Unix(a.ExpiresAt, 0).UTC().Format(time.RFC3339))
+	}
+	signer, err := RecoverPersonalSignBytes(approvalMessage(a.TxHash, a.ExpiresAt), a.Signature)
+	if err != nil {
+		return fmt.Errorf("recover approver fail: %w", err)
+	}
+	if signer != a.Approver {
+		return fmt.Errorf("approval is signed by %v, not by approver %v", signer.Hex(), a.Approver.Hex())
+	}
+	if signer == sender {
+		return fmt.Errorf("approver %v is sender of tx, approval must come from another operator", signer.Hex())
+	}
+	for _, approver := range approvers {
+		if approver == signer {
+			return nil
+		}
+	}
+	return fmt.Errorf("%v is not a trusted approver", signer.Hex())
+}
@@ -0,0 +1,85 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestApprovalVerify(t *testing.T) {
+	approverKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	approver := AddressFromPrivateKey(approverKey)
+	sender := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	txHash := common.HexToHash("0x1d2c7ef38d4e5a8b6d0f5ef2c1c2b3a4f5e6d7c8b9a0f1e2d3c4b5a697887766")
+	now := time.Unix(1700000000, 0)
+
+	approval, err := NewApproval(txHash, now.Unix()+3600, approverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forever, err := NewApproval(txHash, 0, approverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := *approval
+	tampered.ExpiresAt = 0
+
+	tests := []struct {
+		approval  *Approval
+		txHash    common.Hash
+		sender    common.Address
+		approvers []common.Address
+		now       time.Time
+		valid     bool
+	}{
+		{approval, txHash, sender, []common.Address{approver}, now, true},
+		{forever, txHash, sender, []common.Address{approver}, now.Add(365 * 24 * time.Hour), true},
+		{approval, common.Hash{}, sender, []common.Address{approver}, now, false},
+		{approval, txHash, sender, []common.Address{approver}, now.Add(2 * time.Hour), false},
+		{approval, txHash, approver, []common.Address{approver}, now, false},
+		{approval, txHash, sender, []common.Address{sender}, now, false},
+		{&tampered, txHash, sender, []common.Address{approver}, now, false},
+	}
+
+	for i, test := range tests {
+		err := test.approval.Verify(test.txHash, test.sender, test.approvers, test.now)
+		if valid := err == nil; valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.valid, valid, err)
+		}
+	}
+}
+
+func TestApprovalRequired(t *testing.T) {
+	usdt := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	// transfer 1000000 USDT to 0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb
+	erc20Transfer, err := BuildTxInputData("transfer(address,uint256)", []string{to.Hex(), "1000000000000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oneEther := big.NewInt(1e18)
+	threshold := big.NewInt(5e17)
+
+	tests := []struct {
+		tx        *types.Transaction
+		threshold *big.Int
+		want      bool
+	}{
+		{types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(1)}), nil, true},
+		{types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(1)}), threshold, false},
+		{types.NewTx(&types.LegacyTx{To: &to, Value: oneEther}), threshold, true},
+		{types.NewTx(&types.LegacyTx{To: &to, Value: threshold}), threshold, true},
+		{types.NewTx(&types.LegacyTx{To: &usdt, Value: big.NewInt(0), Data: erc20Transfer}), threshold, true},
+		{types.NewTx(&types.DynamicFeeTx{To: &usdt, Value: big.NewInt(0), Data: erc20Transfer}), threshold, true},
+		{types.NewTx(&types.LegacyTx{To: nil, Value: big.NewInt(0)}), threshold, true},
+	}
+
+	for i, test := range tests {
+		if got := ApprovalRequired(test.tx, test.threshold); got != test.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ArbRetryableTxAddress is the precompile of Arbitrum managing retryable tickets on L2.
+var ArbRetryableTxAddress = common.HexToAddress("0x000000000000000000000000000000000000006E")
+
+// ArbNodeInterfaceAddress is the virtual contract of Arbitrum node, which is only callable by eth_call and
+// eth_estimateGas, e.g. to estimate gas of retryable ticket.
+var ArbNodeInterfaceAddress = common.HexToAddress("0x00000000000000000000000000000000000000C8")
+
+// Events of Arbitrum bridge on L1 and ArbRetryableTx on L2, which are used to track retryable tickets.
+var (
+	arbInboxMessageDeliveredTopic = crypto.Keccak256Hash([]byte("InboxMessageDelivered(uint256,bytes)"))
+	arbMessageDeliveredTopic      = crypto.Keccak256Hash([]byte("MessageDelivered(uint256,bytes32,address,uint8,address,bytes32,uint256,uint64)"))
+	arbRedeemScheduledTopic       = crypto.Keccak256Hash([]byte("RedeemScheduled(bytes32,bytes32,uint64,uint64,address,uint256,uint256)"))
+)
+
+// arbMessageKindSubmitRetryable is the kind of L1 message created by Inbox.createRetryableTicket.
+const arbMessageKindSubmitRetryable = 9
+
+// arbSubmitRetryableTxType is the tx type of ArbitrumSubmitRetryableTx, whose hash is the ticket id.
+const arbSubmitRetryableTxType = 0x69
+
+// RetryableSubmissionFee returns the submission fee of retryable ticket with data of dataLength, charged for storing
+// the ticket on L2, i.e. (1400 + 6 * dataLength) * l1BaseFee, same as Inbox.calculateRetryableSubmissionFee.
+func RetryableSubmissionFee(dataLength int, l1BaseFee *big.Int) *big.Int {
+	return new(big.Int).Mul(big.NewInt(int64(1400+6*dataLength)), l1BaseFee)
+}
+
+// EstimateRetryableGas estimates the L2 gas limit of retryable ticket by NodeInterface.estimateRetryableTicket, sender
+// is the sender on L1 (aliased if it's a contract).
+func EstimateRetryableGas(ctx context.Context, l2 *Client, sender common.Address, to common.Address, l2CallValue *big.Int, refundAddress common.Address, data []byte) (uint64, error) {
+	// deposit only needs to cover l2 call value in estimation
+	deposit := new(big.Int).Add(l2CallValue, big.NewInt(1e18))
+	input, err := BuildTxInputData("estimateRetryableTicket(address,uint256,address,uint256,address,address,bytes)", []string{
+		sender.Hex(), deposit.String(), to.Hex(), l2CallValue.String(), refundAddress.Hex(), refundAddress.Hex(), hexutil.Encode(data),
+	})
+	if err != nil {
+		return 0, err
+	}
+	gas, err := l2.EthClient.EstimateGas(ctx, ethereum.CallMsg{To: &ArbNodeInterfaceAddress, Data: input})
+	if err != nil {
+		return 0, fmt.Errorf("estimate gas of retryable ticket fail: %w", err)
+	}
+	return gas, nil
+}
+
+// RetryableTicket is a retryable ticket created on L1 by Inbox.createRetryableTicket.
+type RetryableTicket struct {
+	MessageNumber          *big.Int
+	From                   common.Address // the sender on L1, aliased if it's a contract
+	L1BaseFee              *big.Int
+	To                     common.Address // zero address means contract creation
+	L2CallValue            *big.Int
+	DepositValue           *big.Int // the value sent to inbox, which pays l2 call value, submission fee and gas
+	MaxSubmissionFee       *big.Int
+	ExcessFeeRefundAddress common.Address
+	CallValueRefundAddress common.Address
+	GasLimit               uint64
+	MaxFeePerGas           *big.Int
+	Data                   []byte
+}
+
+// arbSubmitRetryableTx is the rlp fields of ArbitrumSubmitRetryableTx of Arbitrum Nitro.
+type arbSubmitRetryableTx struct {
+	ChainId          *big.Int
+	RequestId        common.Hash
+	From             common.Address
+	L1BaseFee        *big.Int
+	DepositValue     *big.Int
+	GasFeeCap        *big.Int
+	Gas              uint64
+	RetryTo          *common.Address `rlp:"nil"`
+	RetryValue       *big.Int
+	Beneficiary      common.Address
+	MaxSubmissionFee *big.Int
+	FeeRefundAddr    common.Address
+	RetryData        []byte
+}
+
+// TicketId returns the ticket id of retryable on L2 chain l2ChainId, which is also the hash of the L2 tx creating
+// the ticket.
+func (t *RetryableTicket) TicketId(l2ChainId *big.Int) common.Hash {
+	var retryTo *common.Address
+	if t.To != (common.Address{}) {
+		retryTo = &t.To
+	}
+	encoded, _ := rlp.EncodeToBytes(&arbSubmitRetryableTx{
+		ChainId:          l2ChainId,
+		RequestId:        common.BigToHash(t.MessageNumber),
+		From:             t.From,
+		L1BaseFee:        t.L1BaseFee,
+		DepositValue:     t.DepositValue,
+		GasFeeCap:        t.MaxFeePerGas,
+		Gas:              t.GasLimit,
+		RetryTo:          retryTo,
+		RetryValue:       t.L2CallValue,
+		Beneficiary:      t.CallValueRefundAddress,
+		MaxSubmissionFee: t.MaxSubmissionFee,
+		FeeRefundAddr:    t.ExcessFeeRefundAddress,
+		RetryData:        t.Data,
+	})
+	return crypto.Keccak256Hash([]byte{arbSubmitRetryableTxType}, encoded)
+}
+
+// parseRetryableMessage parses data of InboxMessageDelivered, which is abi.encodePacked(uint256(to), l2CallValue,
+// msg.value, maxSubmissionCost, uint256(excessFeeRefundAddress), uint256(callValueRefundAddress), gasLimit,
+// maxFeePerGas, data.length, data).
+func parseRetryableMessage(data []byte, ticket *RetryableTicket) error {
+	if len(data) < 9*32 {
+		return fmt.Errorf("retryable message is too short: %v bytes", len(data))
+	}
+	word := func(i int) *big.Int { return new(big.Int).SetBytes(data[i*32 : (i+1)*32]) }
+	ticket.To = common.BytesToAddress(data[12:32])
+	ticket.L2CallValue = word(1)
+	ticket.DepositValue = word(2)
+	ticket.MaxSubmissionFee = word(3)
+	ticket.ExcessFeeRefundAddress = common.BytesToAddress(data[4*32+12 : 5*32])
+	ticket.CallValueRefundAddress = common.BytesToAddress(data[5*32+12 : 6*32])
+	ticket.GasLimit = word(6).Uint64()
+	ticket.MaxFeePerGas = word(7)
+	dataLength := word(8)
+	if !dataLength.IsInt64() || int64(len(data)-9*32) != dataLength.Int64() {
+		return fmt.Errorf("invalid data length %v of retryable message", dataLength)
+	}
+	ticket.Data = data[9*32:]
+	return nil
+}
+
+// ParseRetryableTickets returns retryable tickets created by L1 tx of receipt, from InboxMessageDelivered of inbox
+// and MessageDelivered of bridge.
+func ParseRetryableTickets(receipt *types.Receipt) ([]*RetryableTicket, error) {
+	var messages = make(map[string][]byte) // message number => data of InboxMessageDelivered
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 2 && log.Topics[0] == arbInboxMessageDeliveredTopic && len(log.Data) >= 64 {
+			// data is abi encoded bytes: offset, length, content
+			length := new(big.Int).SetBytes(log.Data[32:64])
+			if !length.IsInt64() || int64(len(log.Data)-64) < length.Int64() {
+				return nil, fmt.Errorf("invalid InboxMessageDelivered data")
+			}
+			messages[log.Topics[1].Big().String()] = log.Data[64 : 64+length.Int64()]
+		}
+	}
+
+	var tickets []*RetryableTicket
+	for _, log := range receipt.Logs {
+		// MessageDelivered(uint256 indexed messageIndex, bytes32 indexed beforeInboxAcc, address inbox, uint8 kind,
+		// address sender, bytes32 messageDataHash, uint256 baseFeeL1, uint64 timestamp)
+		if len(log.Topics) != 3 || log.Topics[0] != arbMessageDeliveredTopic || len(log.Data) < 6*32 {
+			continue
+		}
+		if new(big.Int).SetBytes(log.Data[32:64]).Int64() != arbMessageKindSubmitRetryable {
+			continue
+		}
+		messageNumber := log.Topics[1].Big()
+		data, ok := messages[messageNumber.String()]
+		if !ok {
+			return nil, fmt.Errorf("InboxMessageDelivered of message %v is not found", messageNumber)
+		}
+		ticket := &RetryableTicket{
+			MessageNumber: messageNumber,
+			From:          common.BytesToAddress(log.Data[2*32+12 : 3*32]),
+			L1BaseFee:     new(big.Int).SetBytes(log.Data[4*32 : 5*32]),
+		}
+		if err := parseRetryableMessage(data, ticket); err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, nil
+}
+
+// Status of retryable ticket on L2.
+const (
+	RetryableNotCreated       = "not created"        // the L2 tx creating ticket is not found, L1 message is not processed yet
+	RetryableRedeemed         = "redeemed"           // auto redeem or a manual redeem succeeded
+	RetryableRedeemable       = "redeemable"         // auto redeem failed or was not scheduled, it can be redeemed before timeout
+	RetryableExpiredOrRemoved = "expired or removed" // auto redeem failed and the ticket no longer exists
+)
+
+// RetryableStatus is the status of retryable ticket on L2.
+type RetryableStatus struct {
+	Status          string
+	CreationReceipt *types.Receipt // nil if not created
+	AutoRedeemTx    *common.Hash   // nil if auto redeem is not scheduled (e.g. gas limit or max fee is too low)
+	AutoRedeemOk    bool
+	Timeout         uint64 // unix time until which the ticket can be redeemed, only for RetryableRedeemable
+}
+
+// GetRetryableStatus returns status of ticket on L2. A ticket which still exists after failed auto redeem is
+// redeemable, a ticket which is gone may have been redeemed manually or expired, they are not distinguished.
+func GetRetryableStatus(ctx context.Context, l2 *Client, ticketId common.Hash) (*RetryableStatus, error) {
+	receipt, err := l2.EthClient.TransactionReceipt(ctx, ticketId)
+	if errors.Is(err, ethereum.NotFound) {
+		return &RetryableStatus{Status: RetryableNotCreated}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	status := &RetryableStatus{CreationReceipt: receipt}
+	for _, log := range receipt.Logs {
+		if log.Address == ArbRetryableTxAddress && len(log.Topics) == 4 && log.Topics[0] == arbRedeemScheduledTopic && log.Topics[1] == ticketId {
+			retryTxHash := log.Topics[2]
+			status.AutoRedeemTx = &retryTxHash
+		}
+	}
+	if status.AutoRedeemTx != nil {
+		redeemReceipt, err := l2.EthClient.TransactionReceipt(ctx, *status.AutoRedeemTx)
+		if err != nil {
+			return nil, fmt.Errorf("get receipt of auto redeem tx %v fail: %w", status.AutoRedeemTx.Hex(), err)
+		}
+		if redeemReceipt.Status == types.ReceiptStatusSuccessful {
+			status.AutoRedeemOk = true
+			status.Status = RetryableRedeemed
+			return status, nil
+		}
+	}
+
+	values, err := CallAndUnpack(ctx, l2.EthClient, ArbRetryableTxAddress, "function getTimeout(bytes32) returns (uint256)", []string{ticketId.Hex()})
+	if err != nil {
+		// getTimeout reverts with NoTicketWithID if the ticket is redeemed or expired
+		if isCallReverted(err) {
+			status.Status = RetryableExpiredOrRemoved
+			return status, nil
+		}
+		return nil, err
+	}
+	status.Status = RetryableRedeemable
+	status.Timeout = values[0].(*big.Int).Uint64()
+	return status, nil
+}
@@ -0,0 +1,128 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// retryableReceipt returns receipt with logs of inbox and bridge creating ticket of message number 7.
+func retryableReceipt(callData []byte) *types.Receipt {
+	word := func(v *big.Int) []byte { return common.LeftPadBytes(v.Bytes(), 32) }
+	addressWord := func(hex string) []byte { return common.LeftPadBytes(common.HexToAddress(hex).Bytes(), 32) }
+
+	var message []byte
+	message = append(message, addressWord("0x1111111111111111111111111111111111111111")...) // to
+	message = append(message, word(big.NewInt(100))...)                                     // l2CallValue
+	message = append(message, word(big.NewInt(5000))...)                                    // msg.value
+	message = append(message, word(big.NewInt(300))...)                                     // maxSubmissionCost
+	message = append(message, addressWord("0x2222222222222222222222222222222222222222")...) // excessFeeRefundAddress
+	message = append(message, addressWord("0x3333333333333333333333333333333333333333")...) // callValueRefundAddress
+	message = append(message, word(big.NewInt(21000))...)                                   // gasLimit
+	message = append(message, word(big.NewInt(10))...)                                      // maxFeePerGas
+	message = append(message, word(big.NewInt(int64(len(callData))))...)                    // data.length
+	message = append(message, callData...)
+
+	// abi encoded bytes, padded to 32 bytes
+	var inboxData []byte
+	inboxData = append(inboxData, word(big.NewInt(32))...)
+	inboxData = append(inboxData, word(big.NewInt(int64(len(message))))...)
+	inboxData = append(inboxData, common.RightPadBytes(message, (len(message)+31)/32*32)...)
+
+	var bridgeData []byte
+	bridgeData = append(bridgeData, addressWord("0x4444444444444444444444444444444444444444")...) // inbox
+	bridgeData = append(bridgeData, word(big.NewInt(arbMessageKindSubmitRetryable))...)           // kind
+	bridgeData = append(bridgeData, addressWord("0x5555555555555555555555555555555555555555")...) // sender
+	bridgeData = append(bridgeData, make([]byte, 32)...)                                          // messageDataHash
+	bridgeData = append(bridgeData, word(big.NewInt(30e9))...)                                    // baseFeeL1
+	bridgeData = append(bridgeData, word(big.NewInt(1700000000))...)                              // timestamp
+
+	messageNumber := common.BigToHash(big.NewInt(7))
+	return &types.Receipt{Logs: []*types.Log{
+		{Topics: []common.Hash{arbMessageDeliveredTopic, messageNumber, {}}, Data: bridgeData},
+		{Topics: []common.Hash{arbInboxMessageDeliveredTopic, messageNumber}, Data: inboxData},
+	}}
+}
+
+func TestParseRetryableTickets(t *testing.T) {
+	tickets, err := ParseRetryableTickets(retryableReceipt([]byte{0xde, 0xad, 0xbe, 0xef}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 1 {
+		t.Fatalf("expect 1 ticket, got %v", len(tickets))
+	}
+	ticket := tickets[0]
+	for _, tt := range []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"MessageNumber", ticket.MessageNumber.Int64(), int64(7)},
+		{"From", ticket.From, common.HexToAddress("0x5555555555555555555555555555555555555555")},
+		{"L1BaseFee", ticket.L1BaseFee.Int64(), int64(30e9)},
+		{"To", ticket.To, common.HexToAddress("0x1111111111111111111111111111111111111111")},
+		{"L2CallValue", ticket.L2CallValue.Int64(), int64(100)},
+		{"DepositValue", ticket.DepositValue.Int64(), int64(5000)},
+		{"MaxSubmissionFee", ticket.MaxSubmissionFee.Int64(), int64(300)},
+		{"ExcessFeeRefundAddress", ticket.ExcessFeeRefundAddress, common.HexToAddress("0x2222222222222222222222222222222222222222")},
+		{"CallValueRefundAddress", ticket.CallValueRefundAddress, common.HexToAddress("0x3333333333333333333333333333333333333333")},
+		{"GasLimit", ticket.GasLimit, uint64(21000)},
+		{"MaxFeePerGas", ticket.MaxFeePerGas.Int64(), int64(10)},
+		{"Data", common.Bytes2Hex(ticket.Data), "deadbeef"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%v: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestParseRetryableTicketsOfOtherMessage(t *testing.T) {
+	receipt := retryableReceipt(nil)
+	receipt.Logs[0].Data[63] = 3 // kind of L2 message
+	tickets, err := ParseRetryableTickets(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tickets) != 0 {
+		t.Errorf("expect no ticket, got %v", len(tickets))
+	}
+}
+
+func TestTicketId(t *testing.T) {
+	tickets, err := ParseRetryableTickets(retryableReceipt(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ticket := tickets[0]
+	id := ticket.TicketId(big.NewInt(42161))
+	if id != ticket.TicketId(big.NewInt(42161)) {
+		t.Errorf("ticket id is not deterministic")
+	}
+	if id == ticket.TicketId(big.NewInt(421614)) {
+		t.Errorf("ticket id should depend on chain id")
+	}
+	ticket.To = common.Address{}
+	if id == ticket.TicketId(big.NewInt(42161)) {
+		t.Errorf("ticket id should depend on destination")
+	}
+}
+
+func TestRetryableSubmissionFee(t *testing.T) {
+	tests := []struct {
+		dataLength int
+		baseFee    int64
+		want       int64
+	}{
+		{0, 10, 14000},
+		{100, 10, 20000},
+		{4, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := RetryableSubmissionFee(tt.dataLength, big.NewInt(tt.baseFee)); got.Int64() != tt.want {
+			t.Errorf("RetryableSubmissionFee(%v, %v) = %v, want %v", tt.dataLength, tt.baseFee, got, tt.want)
+		}
+	}
+}
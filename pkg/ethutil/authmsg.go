@@ -0,0 +1,113 @@
+package ethutil
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	authMessageNonceField    = "Nonce: "
+	authMessageIssuedAtField = "Issued At: "
+)
+
+// AuthMessage is a personal_sign message with embedded nonce and issued time, which is used by signature based login.
+// The server issues a random nonce, the user signs the message, then the server verifies the signer, the nonce and
+// the freshness of issued time, so a leaked signature can not be replayed.
+type AuthMessage struct {
+	Statement string // e.g. "Login to example.com", can be empty
+	Nonce     string
+	IssuedAt  time.Time
+}
+
+// NewAuthNonce returns a random nonce of 32 hex characters.
+func NewAuthNonce() (string, error) {
+	var nonce = make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(nonce), nil
+}
+
+// String formats the message signed by user, i.e. statement followed by a blank line, a "Nonce: " line and an
+// "Issued At: " line (RFC3339 time in UTC).
+func (m *AuthMessage) String() string {
+	var b strings.Builder
+	if m.Statement != "" {
+		b.WriteString(m.Statement)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(authMessageNonceField + m.Nonce + "\n")
+	b.WriteString(authMessageIssuedAtField + m.IssuedAt.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// ParseAuthMessage parses message formatted by AuthMessage.String.
+func ParseAuthMessage(message string) (*AuthMessage, error) {
+	lines := strings.Split(message, "\n")
+	n := len(lines)
+	if n < 2 || !strings.HasPrefix(lines[n-2], authMessageNonceField) || !strings.HasPrefix(lines[n-1], authMessageIssuedAtField) {
+		return nil, fmt.Errorf("message does not end with %q and %q lines", authMessageNonceField, authMessageIssuedAtField)
+	}
+	nonce := strings.TrimPrefix(lines[n-2], authMessageNonceField)
+	if nonce == "" {
+		return nil, fmt.Errorf("nonce is empty")
+	}
+	issuedAt, err := time.Parse(time.RFC3339, strings.TrimPrefix(lines[n-1], authMessageIssuedAtField))
+	if err != nil {
+		return nil, fmt.Errorf("invalid issued time: %w", err)
+	}
+
+	var statement string
+	if n > 2 {
+		if n < 4 || lines[n-3] != "" {
+			return nil, fmt.Errorf("statement must be followed by a blank line")
+		}
+		statement = strings.Join(lines[:n-3], "\n")
+	}
+	return &AuthMessage{Statement: statement, Nonce: nonce, IssuedAt: issuedAt}, nil
+}
+
+// AuthVerifyOptions are the checks of VerifyAuthMessage, zero value of a field disables the check.
+type AuthVerifyOptions struct {
+	Signer  common.Address // the expected signer
+	Nonce   string         // the nonce issued by server
+	MaxAge  time.Duration  // the freshness window, message issued earlier than Now - MaxAge is expired
+	MaxSkew time.Duration  // tolerance of clock skew, message issued later than Now + MaxSkew is rejected
+	Now     time.Time      // the current time, zero means time.Now()
+}
+
+// VerifyAuthMessage verifies personal_sign signature of auth message, and checks signer, nonce and issued time by
+// opts. It returns the parsed message and the signer.
+func VerifyAuthMessage(message string, signature []byte, opts AuthVerifyOptions) (*AuthMessage, common.Address, error) {
+	authMessage, err := ParseAuthMessage(message)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	signer, err := RecoverPersonalSignBytes([]byte(message), signature)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	if opts.Signer != (common.Address{}) && signer != opts.Signer {
+		return nil, signer, fmt.Errorf("message is signed by %v, not %v", signer.Hex(), opts.Signer.Hex())
+	}
+	if opts.Nonce != "" && authMessage.Nonce != opts.Nonce {
+		return nil, signer, fmt.Errorf("nonce of message is %v, not %v", authMessage.Nonce, opts.Nonce)
+	}
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if opts.MaxAge > 0 && authMessage.IssuedAt.Before(now.Add(-opts.MaxAge)) {
+		return nil, signer, fmt.Errorf("message is issued at %v, which is older than %v", authMessage.IssuedAt.Format(time.RFC3339), opts.MaxAge)
+	}
+	if authMessage.IssuedAt.After(now.Add(opts.MaxSkew)) {
+		return nil, signer, fmt.Errorf("message is issued at %v, which is in the future", authMessage.IssuedAt.Format(time.RFC3339))
+	}
+	return authMessage, signer, nil
+}
@@ -0,0 +1,75 @@
+package ethutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestParseAuthMessage(t *testing.T) {
+	issuedAt := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		message AuthMessage
+	}{
+		{AuthMessage{Statement: "Login to example.com", Nonce: "abc123", IssuedAt: issuedAt}},
+		{AuthMessage{Statement: "Login to example.com\nas admin", Nonce: "abc123", IssuedAt: issuedAt}},
+		{AuthMessage{Nonce: "abc123", IssuedAt: issuedAt}},
+	}
+
+	for i, test := range tests {
+		parsed, err := ParseAuthMessage(test.message.String())
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if *parsed != test.message {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.message, *parsed)
+		}
+	}
+
+	for i, message := range []string{
+		"hello",
+		"hello\nNonce: abc\nIssued At: 2023-06-01T10:00:00Z",
+		"Nonce: \nIssued At: 2023-06-01T10:00:00Z",
+		"Nonce: abc\nIssued At: yesterday",
+	} {
+		if _, err := ParseAuthMessage(message); err == nil {
+			t.Fatalf("test %d: expected error for %q", i, message)
+		}
+	}
+}
+
+func TestVerifyAuthMessage(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	signer := AddressFromPrivateKey(privateKey)
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	message := (&AuthMessage{Statement: "Login to example.com", Nonce: "abc123", IssuedAt: now.Add(-time.Minute)}).String()
+	signature, err := PersonalSign(message, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts  AuthVerifyOptions
+		valid bool
+	}{
+		{AuthVerifyOptions{Now: now}, true},
+		{AuthVerifyOptions{Signer: signer, Nonce: "abc123", MaxAge: 5 * time.Minute, Now: now}, true},
+		{AuthVerifyOptions{Signer: common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"), Now: now}, false},
+		{AuthVerifyOptions{Nonce: "other", Now: now}, false},
+		{AuthVerifyOptions{MaxAge: 30 * time.Second, Now: now}, false},                      // expired
+		{AuthVerifyOptions{Now: now.Add(-2 * time.Minute)}, false},                          // issued in future
+		{AuthVerifyOptions{MaxSkew: 5 * time.Minute, Now: now.Add(-2 * time.Minute)}, true}, // tolerate clock skew
+	}
+
+	for i, test := range tests {
+		_, got, err := VerifyAuthMessage(message, hexutil.MustDecode(signature), test.opts)
+		if valid := err == nil; valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.valid, valid, err)
+		}
+		if got != signer {
+			t.Fatalf("test %d: expected: %v, got: %v", i, signer.Hex(), got.Hex())
+		}
+	}
+}
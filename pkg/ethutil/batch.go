@@ -0,0 +1,120 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DefaultBatchSize is the default max number of calls in a JSON-RPC batch request, larger batches are rejected by
+// many providers.
+const DefaultBatchSize = 100
+
+// BatchCall sends elems by JSON-RPC batch requests of at most batchSize calls each. If batchSize is not greater than 1,
+// elems are sent one by one, which is required by nodes not supporting batch request. The error of the first failed
+// elem is returned.
+func BatchCall(ctx context.Context, client *rpc.Client, elems []rpc.BatchElem, batchSize int) error {
+	if err := sendBatch(ctx, client, elems, batchSize); err != nil {
+		return err
+	}
+	for _, elem := range elems {
+		if elem.Error != nil {
+			return fmt.Errorf("%v %v fail: %w", elem.Method, elem.Args, elem.Error)
+		}
+	}
+	return nil
+}
+
+// sendBatch is same as BatchCall, but errors of elems are left in elems, only the error of batch request is returned.
+func sendBatch(ctx context.Context, client *rpc.Client, elems []rpc.BatchElem, batchSize int) error {
+	if batchSize <= 1 {
+		for i := range elems {
+			elems[i].Error = client.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+		}
+	} else {
+		for start := 0; start < len(elems); start += batchSize {
+			end := start + batchSize
+			if end > len(elems) {
+				end = len(elems)
+			}
+			if err := client.BatchCallContext(ctx, elems[start:end]); err != nil {
+				return fmt.Errorf("BatchCallContext fail: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// BatchBalances returns eth balances of addresses at block (nil means latest) by batched eth_getBalance.
+func BatchBalances(ctx context.Context, client *rpc.Client, addresses []common.Address, block *big.Int, batchSize int) ([]*big.Int, error) {
+	var results = make([]hexutil.Big, len(addresses))
+	var elems []rpc.BatchElem
+	for i, address := range addresses {
+		elems = append(elems, rpc.BatchElem{Method: "eth_getBalance", Args: []any{address, blockTag(block)}, Result: &results[i]})
+	}
+	if err := BatchCall(ctx, client, elems, batchSize); err != nil {
+		return nil, err
+	}
+	var balances []*big.Int
+	for i := range results {
+		balances = append(balances, results[i].ToInt())
+	}
+	return balances, nil
+}
+
+// BatchNonces returns nonces of addresses at block (nil means latest) by batched eth_getTransactionCount.
+func BatchNonces(ctx context.Context, client *rpc.Client, addresses []common.Address, block *big.Int, batchSize int) ([]uint64, error) {
+	var results = make([]hexutil.Uint64, len(addresses))
+	var elems []rpc.BatchElem
+	for i, address := range addresses {
+		elems = append(elems, rpc.BatchElem{Method: "eth_getTransactionCount", Args: []any{address, blockTag(block)}, Result: &results[i]})
+	}
+	if err := BatchCall(ctx, client, elems, batchSize); err != nil {
+		return nil, err
+	}
+	var nonces []uint64
+	for _, nonce := range results {
+		nonces = append(nonces, uint64(nonce))
+	}
+	return nonces, nil
+}
+
+// BatchEthCalls returns output of calls at block (nil means latest) by batched eth_call. Unlike Multicall3, it works on
+// any chain, but a reverted call fails the whole batch.
+func BatchEthCalls(ctx context.Context, client *rpc.Client, calls []CallArgs, block *big.Int, batchSize int) ([][]byte, error) {
+	var results = make([]hexutil.Bytes, len(calls))
+	var elems []rpc.BatchElem
+	for i, call := range calls {
+		elems = append(elems, rpc.BatchElem{Method: "eth_call", Args: []any{call, blockTag(block)}, Result: &results[i]})
+	}
+	if err := BatchCall(ctx, client, elems, batchSize); err != nil {
+		return nil, err
+	}
+	var outputs [][]byte
+	for _, output := range results {
+		outputs = append(outputs, output)
+	}
+	return outputs, nil
+}
+
+// BatchTryEthCalls is same as BatchEthCalls, but a reverted call does not fail the others, its result is not Success.
+// It's the fallback of MulticallTryAggregate on chains without Multicall3.
+func BatchTryEthCalls(ctx context.Context, client *rpc.Client, calls []CallArgs, block *big.Int, batchSize int) ([]MulticallResult, error) {
+	var results = make([]hexutil.Bytes, len(calls))
+	var elems []rpc.BatchElem
+	for i, call := range calls {
+		elems = append(elems, rpc.BatchElem{Method: "eth_call", Args: []any{call, blockTag(block)}, Result: &results[i]})
+	}
+	if err := sendBatch(ctx, client, elems, batchSize); err != nil {
+		return nil, err
+	}
+	var outputs []MulticallResult
+	for i, elem := range elems {
+		outputs = append(outputs, MulticallResult{Success: elem.Error == nil, ReturnData: results[i]})
+	}
+	return outputs, nil
+}
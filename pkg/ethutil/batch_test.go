@@ -0,0 +1,87 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// newBalanceServer returns a server on which balance of address is its last byte, and the number of http requests
+// it received.
+func newBalanceServer() (*httptest.Server, *int32) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		type request struct {
+			Id     json.RawMessage `json:"id"`
+			Params []string        `json:"params"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		var reqs []request
+		batch := json.Unmarshal(body, &reqs) == nil
+		if !batch {
+			var req request
+			_ = json.Unmarshal(body, &req)
+			reqs = []request{req}
+		}
+		var resps []string
+		for _, req := range reqs {
+			address := common.HexToAddress(req.Params[0])
+			resps = append(resps, fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":"0x%x"}`, req.Id, address[19]))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if batch {
+			_, _ = fmt.Fprintf(w, "[%s]", strings.Join(resps, ","))
+		} else {
+			_, _ = fmt.Fprint(w, resps[0])
+		}
+	}))
+	return server, &hits
+}
+
+func TestBatchBalances(t *testing.T) {
+	tests := []struct {
+		addresses    int
+		batchSize    int
+		expectedHits int32
+	}{
+		{5, 100, 1},
+		{250, 100, 3},
+		{5, 1, 5}, // no batch
+		{0, 100, 0},
+	}
+
+	for i, test := range tests {
+		server, hits := newBalanceServer()
+		client, err := rpc.Dial(server.URL)
+		if err != nil {
+			t.Fatalf("test %d: dial fail: %v", i, err)
+		}
+		var addresses []common.Address
+		for j := 0; j < test.addresses; j++ {
+			addresses = append(addresses, common.BytesToAddress([]byte{byte(j)}))
+		}
+		balances, err := BatchBalances(context.Background(), client, addresses, nil, test.batchSize)
+		server.Close()
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if len(balances) != test.addresses || *hits != test.expectedHits {
+			t.Fatalf("test %d: expected: %v balances in %v requests, got: %v balances in %v requests", i, test.addresses, test.expectedHits, len(balances), *hits)
+		}
+		for j, balance := range balances {
+			if balance.Uint64() != uint64(byte(j)) {
+				t.Fatalf("test %d: expected balance %v of address %d, got: %v", i, byte(j), j, balance)
+			}
+		}
+	}
+}
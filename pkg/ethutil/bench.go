@@ -0,0 +1,184 @@
+package ethutil
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BenchCall is a kind of rpc call replayed by RunBench, calls are picked in proportion to Weight.
+type BenchCall struct {
+	Name   string
+	Method string
+	Params func() []any // params of each call, e.g. a random block range of eth_getLogs
+	Weight int
+}
+
+// BenchOptions controls the load generated by RunBench.
+type BenchOptions struct {
+	Qps            float64       // target number of calls per second
+	Duration       time.Duration // how long the load lasts
+	Concurrency    int           // max in-flight calls, achieved qps is lower than target if it's exhausted
+	RequestTimeout time.Duration
+}
+
+// BenchStats is the latency and error statistics of a kind of call.
+type BenchStats struct {
+	Name      string
+	Count     int
+	Errors    int
+	LastError error
+	P50       time.Duration // latencies are of successful calls
+	P90       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+}
+
+// ErrorRate returns the ratio of failed calls.
+func (s BenchStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+// BenchReport is the result of RunBench.
+type BenchReport struct {
+	Stats   []BenchStats // in the order of calls
+	Total   BenchStats
+	Elapsed time.Duration
+}
+
+// AchievedQps returns the number of calls finished per second.
+func (r BenchReport) AchievedQps() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Total.Count) / r.Elapsed.Seconds()
+}
+
+// benchSchedule returns the order of calls picked in one round, calls are interleaved by smooth weighted
+// round-robin so that the mix is even in any short period.
+func benchSchedule(calls []BenchCall) []int {
+	var total int
+	for _, call := range calls {
+		total += call.Weight
+	}
+	var current = make([]int, len(calls))
+	var schedule []int
+	for n := 0; n < total; n++ {
+		best := -1
+		for i, call := range calls {
+			if call.Weight <= 0 {
+				continue
+			}
+			current[i] += call.Weight
+			if best < 0 || current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		schedule = append(schedule, best)
+	}
+	return schedule
+}
+
+// summarizeLatency computes statistics of latencies, latencies is sorted in place.
+func summarizeLatency(stats *BenchStats, latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var pick = func(p int) time.Duration {
+		rank := (p*len(latencies) + 99) / 100 // nearest rank, same as percentile
+		if rank < 1 {
+			rank = 1
+		}
+		return latencies[rank-1]
+	}
+	stats.P50 = pick(50)
+	stats.P90 = pick(90)
+	stats.P99 = pick(99)
+	stats.Max = latencies[len(latencies)-1]
+}
+
+// RunBench replays calls against rpcClient at the target qps for the given duration, and reports latency
+// percentiles and error rates of each kind of call.
+func RunBench(ctx context.Context, rpcClient *rpc.Client, calls []BenchCall, opts BenchOptions) BenchReport {
+	schedule := benchSchedule(calls)
+	var report = BenchReport{Stats: make([]BenchStats, len(calls))}
+	var latencies = make([][]time.Duration, len(calls))
+	if len(schedule) == 0 || opts.Qps <= 0 {
+		return report
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var sem = make(chan struct{}, opts.Concurrency)
+	var ticker = time.NewTicker(time.Duration(float64(time.Second) / opts.Qps))
+	defer ticker.Stop()
+	var deadline = time.After(opts.Duration)
+
+	start := time.Now()
+loop:
+	for n := 0; ; n++ {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline:
+			break loop
+		case <-ticker.C:
+		}
+
+		index := schedule[n%len(schedule)]
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			call := calls[index]
+			var params []any
+			if call.Params != nil {
+				params = call.Params()
+			}
+			callCtx, cancel := context.WithTimeout(ctx, opts.RequestTimeout)
+			defer cancel()
+			var result any
+			begin := time.Now()
+			err := rpcClient.CallContext(callCtx, &result, call.Method, params...)
+			latency := time.Since(begin)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Stats[index].Count++
+			if err != nil {
+				report.Stats[index].Errors++
+				report.Stats[index].LastError = err
+				return
+			}
+			latencies[index] = append(latencies[index], latency)
+		}(index)
+	}
+	wg.Wait()
+	report.Elapsed = time.Since(start)
+
+	var all []time.Duration
+	report.Total.Name = "total"
+	for i := range calls {
+		report.Stats[i].Name = calls[i].Name
+		report.Total.Count += report.Stats[i].Count
+		report.Total.Errors += report.Stats[i].Errors
+		all = append(all, latencies[i]...)
+		summarizeLatency(&report.Stats[i], latencies[i])
+	}
+	summarizeLatency(&report.Total, all)
+	return report
+}
@@ -0,0 +1,54 @@
+package ethutil
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBenchSchedule(t *testing.T) {
+	tests := []struct {
+		weights  []int
+		expected []int
+	}{
+		{[]int{1}, []int{0}},
+		{[]int{1, 0, 1}, []int{0, 2}},
+		{[]int{3, 1}, []int{0, 0, 1, 0}},
+		{[]int{5, 1, 1}, []int{0, 0, 1, 0, 2, 0, 0}},
+		{[]int{0, 0}, nil},
+	}
+
+	for i, test := range tests {
+		var calls []BenchCall
+		for _, weight := range test.weights {
+			calls = append(calls, BenchCall{Weight: weight})
+		}
+		schedule := benchSchedule(calls)
+		if !reflect.DeepEqual(schedule, test.expected) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, schedule)
+		}
+	}
+}
+
+func TestSummarizeLatency(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		latencies []time.Duration
+		expected  BenchStats
+	}{
+		{nil, BenchStats{}},
+		{[]time.Duration{time.Second}, BenchStats{P50: time.Second, P90: time.Second, P99: time.Second, Max: time.Second}},
+		{latencies, BenchStats{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}},
+	}
+
+	for i, test := range tests {
+		var stats BenchStats
+		summarizeLatency(&stats, test.latencies)
+		if stats != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, stats)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// Kinds of block explorer api. Blockscout serves Etherscan-compatible api at /api, which is used for most requests,
+// but some apis (e.g. gasoracle) are only served by its REST api at /api/v2.
+const (
+	ExplorerKindEtherscan  = "etherscan"
+	ExplorerKindBlockscout = "blockscout"
+)
+
+// DetectExplorerKind returns ExplorerKindBlockscout if host of baseUrl looks like a Blockscout instance, e.g.
+// https://eth.blockscout.com/api, otherwise ExplorerKindEtherscan. Blockscout instances on custom domains can't be
+// detected, specify the kind explicitly for them.
+func DetectExplorerKind(baseUrl string) string {
+	if u, err := url.Parse(baseUrl); err == nil && strings.Contains(strings.ToLower(u.Host), "blockscout") {
+		return ExplorerKindBlockscout
+	}
+	return ExplorerKindEtherscan
+}
+
+// blockscoutRestUrl returns url of Blockscout REST api path (e.g. /stats) of the Etherscan-compatible api baseUrl.
+func blockscoutRestUrl(baseUrl string, path string) (string, error) {
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	if !strings.HasSuffix(baseUrl, "/api") {
+		return "", fmt.Errorf("blockscout api url %v doesn't end with /api", baseUrl)
+	}
+	return baseUrl + "/v2" + path, nil
+}
+
+// blockscoutGasPrice is a gas price of Blockscout stats in gwei, which is a number in old versions, and an object
+// with price and time in new versions.
+type blockscoutGasPrice decimal.Decimal
+
+func (p *blockscoutGasPrice) UnmarshalJSON(data []byte) error {
+	var price struct {
+		Price decimal.Decimal `json:"price"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		if err := json.Unmarshal(data, &price); err != nil {
+			return err
+		}
+		*p = blockscoutGasPrice(price.Price)
+		return nil
+	}
+	if err := json.Unmarshal(data, &price.Price); err != nil {
+		return err
+	}
+	*p = blockscoutGasPrice(price.Price)
+	return nil
+}
+
+// blockscoutGasOracle returns gas price suggestions by Blockscout stats api, LastBlock and SuggestBaseFee are not
+// available.
+func (c *ExplorerClient) blockscoutGasOracle(ctx context.Context) (*ExplorerGasOracle, error) {
+	statsUrl, err := blockscoutRestUrl(c.BaseUrl, "/stats")
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	body, err := httpGet(ctx, statsUrl)
+	if err != nil {
+		return nil, err
+	}
+	var stats struct {
+		GasPrices *struct {
+			Slow    blockscoutGasPrice `json:"slow"`
+			Average blockscoutGasPrice `json:"average"`
+			Fast    blockscoutGasPrice `json:"fast"`
+		} `json:"gas_prices"`
+	}
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return nil, fmt.Errorf("parse blockscout stats fail: %w", err)
+	}
+	if stats.GasPrices == nil {
+		return nil, fmt.Errorf("gas prices are not available in blockscout stats")
+	}
+	return &ExplorerGasOracle{
+		SafeGasPrice:    decimal.Decimal(stats.GasPrices.Slow),
+		ProposeGasPrice: decimal.Decimal(stats.GasPrices.Average),
+		FastGasPrice:    decimal.Decimal(stats.GasPrices.Fast),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDetectExplorerKind(t *testing.T) {
+	tests := []struct {
+		baseUrl string
+		want    string
+	}{
+		{"https://eth.blockscout.com/api", ExplorerKindBlockscout},
+		{"https://blockscout.com/poa/sokol/api", ExplorerKindBlockscout},
+		{"https://api.etherscan.io/api", ExplorerKindEtherscan},
+		{"https://explorer.example.com/blockscout/api", ExplorerKindEtherscan}, // only host is checked
+	}
+	for _, tt := range tests {
+		if got := DetectExplorerKind(tt.baseUrl); got != tt.want {
+			t.Errorf("DetectExplorerKind(%v) = %v, want %v", tt.baseUrl, got, tt.want)
+		}
+	}
+}
+
+func TestBlockscoutGasOracle(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats string
+	}{
+		{"number", `{"gas_prices":{"slow":1.5,"average":2,"fast":3.25}}`},
+		{"object", `{"gas_prices":{"slow":{"price":1.5,"time":30000},"average":{"price":2,"time":15000},"fast":{"price":3.25,"time":5000}}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/stats" {
+					t.Errorf("unexpected path %v", r.URL.Path)
+				}
+				_, _ = fmt.Fprint(w, tt.stats)
+			}))
+			defer server.Close()
+
+			client := &ExplorerClient{BaseUrl: server.URL + "/api", Kind: ExplorerKindBlockscout}
+			oracle, err := client.GasOracle(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if oracle.SafeGasPrice.String() != "1.5" || oracle.ProposeGasPrice.String() != "2" || oracle.FastGasPrice.String() != "3.25" {
+				t.Errorf("unexpected gas oracle %+v", oracle)
+			}
+		})
+	}
+}
+
+func TestBlockscoutContractSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, `{"message":"OK","status":"1","result":[{"ABI":"[]","ContractName":"Token",
+"FileName":"contracts/Token.sol","SourceCode":"import \"./Base.sol\";",
+"AdditionalSources":[{"Filename":"contracts/Base.sol","SourceCode":"contract Base {}"}]}]}`)
+	}))
+	defer server.Close()
+
+	client := &ExplorerClient{BaseUrl: server.URL + "/api", Kind: ExplorerKindBlockscout}
+	source, err := client.ContractSource(context.Background(), [20]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if source.Name != "Token" || len(source.Files) != 2 || source.Files["contracts/Base.sol"] != "contract Base {}" ||
+		source.Files["contracts/Token.sol"] != `import "./Base.sol";` {
+		t.Errorf("unexpected source %+v", source)
+	}
+}
@@ -0,0 +1,46 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// brainwalletWords are common short strings whose keccak256 hash has been used as private key. Funds sent to
+// these keys are swept by bots within seconds.
+var brainwalletWords = []string{
+	"", "password", "password1", "passw0rd", "123456", "12345678", "123456789", "1234567890", "111111", "000000",
+	"qwerty", "qwertyuiop", "asdf", "asdfgh", "zxcvbn", "abc123", "letmein", "iloveyou", "monkey", "dragon",
+	"football", "baseball", "sunshine", "princess", "welcome", "shadow", "master", "trustno1", "hunter2", "admin",
+	"root", "toor", "changeme", "default", "secret", "love", "god", "money", "test", "test123", "testing",
+	"foo", "bar", "foobar", "hello", "hello world", "cat", "dog", "apple", "banana", "pizza", "moon", "to the moon",
+	"hodl", "lamborghini", "wallet", "my wallet", "mywallet", "brainwallet", "private key", "crypto", "blockchain",
+	"genesis", "ethereum", "ether", "eth", "bitcoin", "btc", "bitcoin pizza", "satoshi", "satoshi nakamoto",
+	"vitalik", "vitalik buterin", "doge", "dogecoin", "litecoin", "correct horse battery staple",
+}
+
+// brainwalletKeys maps keccak256 of brainwallet words (including single letters and numbers 0-1000) to the word
+var brainwalletKeys = func() map[common.Hash]string {
+	var keys = make(map[common.Hash]string)
+	var add = func(word string) {
+		keys[crypto.Keccak256Hash([]byte(word))] = word
+	}
+	for _, word := range brainwalletWords {
+		add(word)
+	}
+	for c := 'a'; c <= 'z'; c++ {
+		add(string(c))
+	}
+	for i := 0; i <= 1000; i++ {
+		add(strconv.Itoa(i))
+	}
+	return keys
+}()
+
+// IsBrainwalletKey returns the word and true if privateKey is keccak256 of a well known short string.
+func IsBrainwalletKey(privateKey *ecdsa.PrivateKey) (string, bool) {
+	word, ok := brainwalletKeys[common.BytesToHash(crypto.FromECDSA(privateKey))]
+	return word, ok
+}
@@ -0,0 +1,42 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestIsBrainwalletKey(t *testing.T) {
+	tests := []struct {
+		privateKeyHex string
+		wantWord      string
+		wantOk        bool
+	}{
+		{
+			privateKeyHex: crypto.Keccak256Hash([]byte("password")).Hex()[2:],
+			wantWord:      "password",
+			wantOk:        true,
+		},
+		{
+			privateKeyHex: crypto.Keccak256Hash([]byte("42")).Hex()[2:],
+			wantWord:      "42",
+			wantOk:        true,
+		},
+		{
+			privateKeyHex: "ef065dcbc43081c63c0fbf389ec8df3872d9d61b1bc2e98d7a0a4395d11314d2",
+			wantWord:      "",
+			wantOk:        false,
+		},
+	}
+
+	for i, tt := range tests {
+		privateKey, err := crypto.HexToECDSA(tt.privateKeyHex)
+		if err != nil {
+			t.Fatalf("test %d: invalid private key: %v", i, err)
+		}
+		word, ok := IsBrainwalletKey(privateKey)
+		if word != tt.wantWord || ok != tt.wantOk {
+			t.Fatalf("test %d: expected: %v %v, got: %v %v", i, tt.wantWord, tt.wantOk, word, ok)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// BroadcastIssue is a problem of signed tx found before broadcasting it.
+type BroadcastIssue struct {
+	Fatal   bool // the tx will be rejected by node immediately
+	Message string
+}
+
+// CheckBroadcast compares chain id and nonce of tx signed by sender against the node, whose chain id is chainID and
+// sender's nonce of latest block and pending block are latestNonce and pendingNonce. The issues found predict
+// failures of broadcasting, e.g. "nonce too low" and "invalid chain id".
+func CheckBroadcast(tx *types.Transaction, sender common.Address, chainID *big.Int, latestNonce, pendingNonce uint64) []BroadcastIssue {
+	var issues []BroadcastIssue
+	if !tx.Protected() {
+		issues = append(issues, BroadcastIssue{false, "tx is not replay protected (no chain id is signed), it can be replayed on any chain"})
+	} else if tx.ChainId().Cmp(chainID) != 0 {
+		issues = append(issues, BroadcastIssue{true, fmt.Sprintf("tx is signed for chain %v, but the node is chain %v, "+
+			"re-sign the tx with chain id %v or broadcast it to a node of chain %v", tx.ChainId(), chainID, chainID, tx.ChainId())})
+		return issues // nonce of sender in another chain is meaningless
+	}
+
+	nonce := tx.Nonce()
+	switch {
+	case nonce < latestNonce:
+		issues = append(issues, BroadcastIssue{true, fmt.Sprintf("nonce too low, nonce %v of %v is already used by a confirmed tx, "+
+			"the next nonce is %v, re-sign the tx with --nonce %v", nonce, sender.Hex(), pendingNonce, pendingNonce)})
+	case nonce < pendingNonce:
+		issues = append(issues, BroadcastIssue{false, fmt.Sprintf("nonce %v of %v is used by a pending tx, this tx replaces it only if "+
+			"its fees are high enough (usually 10%% higher), otherwise it's rejected as underpriced", nonce, sender.Hex())})
+	case nonce > pendingNonce:
+		issues = append(issues, BroadcastIssue{false, fmt.Sprintf("nonce gap, the next nonce of %v is %v but nonce of tx is %v, "+
+			"the tx is queued until txs of nonce %v to %v are sent", sender.Hex(), pendingNonce, nonce, pendingNonce, nonce-1)})
+	}
+	return issues
+}
+
+// QueryBroadcastIssues queries chain id and nonce of sender from node, and checks tx by CheckBroadcast. chainID is
+// queried if it's nil.
+func QueryBroadcastIssues(ctx context.Context, client *ethclient.Client, tx *types.Transaction, sender common.Address, chainID *big.Int) ([]BroadcastIssue, error) {
+	if chainID == nil {
+		var err error
+		if chainID, err = client.ChainID(ctx); err != nil {
+			return nil, fmt.Errorf("ChainID fail: %w", err)
+		}
+	}
+	latestNonce, err := client.NonceAt(ctx, sender, nil)
+	if err != nil {
+		return nil, fmt.Errorf("NonceAt fail: %w", err)
+	}
+	pendingNonce, err := client.PendingNonceAt(ctx, sender)
+	if err != nil {
+		return nil, fmt.Errorf("PendingNonceAt fail: %w", err)
+	}
+	return CheckBroadcast(tx, sender, chainID, latestNonce, pendingNonce), nil
+}
@@ -0,0 +1,53 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestCheckBroadcast(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	sender := AddressFromPrivateKey(privateKey)
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	signTx := func(signer types.Signer, nonce uint64) *types.Transaction {
+		tx, err := types.SignNewTx(privateKey, signer, &types.LegacyTx{Nonce: nonce, GasPrice: big.NewInt(1), Gas: 21000, To: &to})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+
+	tests := []struct {
+		tx           *types.Transaction
+		chainID      int64
+		latestNonce  uint64
+		pendingNonce uint64
+		issues       int
+		fatal        bool
+	}{
+		{signTx(types.NewEIP155Signer(big.NewInt(5)), 3), 5, 3, 3, 0, false},
+		{signTx(types.NewEIP155Signer(big.NewInt(5)), 3), 1, 3, 3, 1, true},  // wrong chain
+		{signTx(types.NewEIP155Signer(big.NewInt(5)), 2), 5, 3, 3, 1, true},  // nonce too low
+		{signTx(types.NewEIP155Signer(big.NewInt(5)), 3), 5, 3, 4, 1, false}, // replace pending tx
+		{signTx(types.NewEIP155Signer(big.NewInt(5)), 5), 5, 3, 3, 1, false}, // nonce gap
+		{signTx(types.HomesteadSigner{}, 3), 5, 3, 3, 1, false},              // not replay protected
+		{signTx(types.HomesteadSigner{}, 1), 5, 3, 3, 2, true},               // not replay protected and nonce too low
+	}
+
+	for i, test := range tests {
+		issues := CheckBroadcast(test.tx, sender, big.NewInt(test.chainID), test.latestNonce, test.pendingNonce)
+		if len(issues) != test.issues {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.issues, len(issues), issues)
+		}
+		var fatal bool
+		for _, issue := range issues {
+			fatal = fatal || issue.Fatal
+		}
+		if fatal != test.fatal {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.fatal, fatal)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Call invokes the (constant) contract method at the given block, nil blockNumber means latest block.
+func Call(ctx context.Context, client *ethclient.Client, toAddress common.Address, data []byte, blockNumber *big.Int) ([]byte, error) {
+	msg := ethereum.CallMsg{To: &toAddress, Data: data}
+	return client.CallContract(ctx, msg, blockNumber)
+}
+
+// IsContractAddress returns true if there is code deployed at address.
+func IsContractAddress(ctx context.Context, client *ethclient.Client, address common.Address) (bool, error) {
+	bytecode, err := client.CodeAt(ctx, address, nil) // nil is latest block
+	if err != nil {
+		return false, err
+	}
+
+	return len(bytecode) > 0, nil
+}
+
+// CallAndUnpack invokes the (constant) contract method funcDefinition (e.g. "function nonces(address) returns (uint256)")
+// at latest block with args, and unpacks the return data.
+func CallAndUnpack(ctx context.Context, client *ethclient.Client, contract common.Address, funcDefinition string, args []string) ([]any, error) {
+	data, err := BuildTxInputData(funcDefinition, args)
+	if err != nil {
+		return nil, err
+	}
+	output, err := Call(ctx, client, contract, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call %v of %v fail: %w", ExtractFuncName(funcDefinition), contract.Hex(), err)
+	}
+	returnArgs, err := BuildReturnArgs(funcDefinition)
+	if err != nil {
+		return nil, err
+	}
+	values, err := returnArgs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("unpack return data of %v fail: %w", ExtractFuncName(funcDefinition), err)
+	}
+	if len(values) != len(returnArgs) {
+		return nil, fmt.Errorf("expected %v return values of %v, got %v", len(returnArgs), ExtractFuncName(funcDefinition), len(values))
+	}
+	return values, nil
+}
+
+// CallArgs is the call object of eth_call, debug_traceCall etc.
+type CallArgs struct {
+	From  *common.Address `json:"from,omitempty"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+	Data  hexutil.Bytes   `json:"data,omitempty"`
+	Gas   *hexutil.Uint64 `json:"gas,omitempty"`
+}
+
+// blockTag returns block parameter of rpc, block is interpreted like block parameter of ethclient, i.e. nil means
+// latest block, -1 means pending block, rpc.FinalizedBlockNumber and rpc.SafeBlockNumber mean the tags.
+func blockTag(block *big.Int) string {
+	switch {
+	case block == nil:
+		return "latest"
+	case block.Cmp(big.NewInt(-1)) == 0:
+		return "pending"
+	case block.Cmp(big.NewInt(int64(rpc.FinalizedBlockNumber))) == 0:
+		return "finalized"
+	case block.Cmp(big.NewInt(int64(rpc.SafeBlockNumber))) == 0:
+		return "safe"
+	}
+	return hexutil.EncodeBig(block)
+}
+
+// ParseBlock parses block number (decimal or hex), tag (latest, pending, earliest, finalized, safe) or block hash
+// into block parameter of ethclient, i.e. nil for latest block and negative number for other tags. The client is
+// only used to resolve block hash to its number.
+func ParseBlock(ctx context.Context, client *ethclient.Client, block string) (*big.Int, error) {
+	switch block {
+	case "", "latest":
+		return nil, nil
+	case "earliest":
+		return big.NewInt(0), nil
+	case "pending":
+		// ethclient treats -1 as pending, which is different from rpc.PendingBlockNumber
+		return big.NewInt(-1), nil
+	case "finalized":
+		return big.NewInt(int64(rpc.FinalizedBlockNumber)), nil
+	case "safe":
+		return big.NewInt(int64(rpc.SafeBlockNumber)), nil
+	}
+	if len(block) == 2+2*common.HashLength && has0xPrefix(block) {
+		header, err := client.HeaderByHash(ctx, common.HexToHash(block))
+		if err != nil {
+			return nil, fmt.Errorf("get block %v fail: %w", block, err)
+		}
+		return header.Number, nil
+	}
+	number, err := ParseInteger(block)
+	if err != nil || number.Sign() < 0 {
+		return nil, fmt.Errorf("invalid block %v, expected number, tag (latest, pending, earliest, finalized, safe) or block hash", block)
+	}
+	return number, nil
+}
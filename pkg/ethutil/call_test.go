@@ -0,0 +1,43 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestParseBlock(t *testing.T) {
+	tests := []struct {
+		block       string
+		expected    *big.Int
+		expectedTag string
+	}{
+		{"", nil, "latest"},
+		{"latest", nil, "latest"},
+		{"earliest", big.NewInt(0), "0x0"},
+		{"pending", big.NewInt(-1), "pending"},
+		{"finalized", big.NewInt(-3), "finalized"},
+		{"safe", big.NewInt(-4), "safe"},
+		{"17000000", big.NewInt(17000000), "0x1036640"},
+		{"0x1036640", big.NewInt(17000000), "0x1036640"},
+	}
+
+	for i, test := range tests {
+		block, err := ParseBlock(context.Background(), nil, test.block)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if (block == nil) != (test.expected == nil) || block != nil && block.Cmp(test.expected) != 0 {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, block)
+		}
+		if tag := blockTag(block); tag != test.expectedTag {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expectedTag, tag)
+		}
+	}
+
+	for _, block := range []string{"-1", "head", "0xzz"} {
+		if _, err := ParseBlock(context.Background(), nil, block); err == nil {
+			t.Fatalf("expected error for block %v", block)
+		}
+	}
+}
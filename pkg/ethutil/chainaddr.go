@@ -0,0 +1,109 @@
+package ethutil
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainShortNames maps EIP-3770 chain short names to chain ids, short names are from https://chainid.network/
+var ChainShortNames = map[string]uint64{
+	"eth":      1,
+	"gor":      5,
+	"sep":      11155111,
+	"oeth":     10,
+	"ogor":     420,
+	"bnb":      56,
+	"bnbt":     97,
+	"spoa":     77,
+	"rsk":      30,
+	"trsk":     31,
+	"gno":      100,
+	"heco":     128,
+	"matic":    137,
+	"maticmum": 80001,
+	"ftm":      250,
+	"zksync":   324,
+	"zkevm":    1101,
+	"base":     8453,
+	"basegor":  84531,
+	"arb1":     42161,
+	"arb-nova": 42170,
+	"celo":     42220,
+	"avax":     43114,
+	"linea":    59144,
+	"aurora":   1313161554,
+}
+
+var chainAddressRE = regexp.MustCompile(`^([a-zA-Z0-9-]{1,32}):(0[xX][0-9a-fA-F]{40})$`)
+
+// ChainShortName returns the EIP-3770 short name of chain id
+func ChainShortName(chainId uint64) (string, bool) {
+	var names []string
+	for name, id := range ChainShortNames {
+		if id == chainId {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
+
+// IsChainAddress reports whether s is an EIP-3770 chain-prefixed address (e.g. oeth:0x...) or a Safe{Wallet} url
+// with chain-prefixed address in its safe parameter (e.g. https://app.safe.global/home?safe=eth:0x...).
+func IsChainAddress(s string) bool {
+	if chainAddressRE.MatchString(s) {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	return chainAddressRE.MatchString(u.Query().Get("safe"))
+}
+
+// ParseChainAddress parses EIP-3770 chain-prefixed address, Safe{Wallet} url or plain hex address, returns the chain
+// id of the short name (0 for plain hex address).
+// See: https://eips.ethereum.org/EIPS/eip-3770
+func ParseChainAddress(s string) (shortName string, chainId uint64, address common.Address, err error) {
+	shortName, chainId, hexAddress, err := SplitChainAddress(s)
+	if err != nil {
+		return "", 0, common.Address{}, err
+	}
+	return shortName, chainId, common.HexToAddress(hexAddress), nil
+}
+
+// SplitChainAddress is like ParseChainAddress, but returns the hex address as it is in s, e.g. for checksum detection.
+func SplitChainAddress(s string) (shortName string, chainId uint64, hexAddress string, err error) {
+	if common.IsHexAddress(s) {
+		return "", 0, s, nil
+	}
+	v := s
+	if u, err := url.Parse(s); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		v = u.Query().Get("safe")
+	}
+	m := chainAddressRE.FindStringSubmatch(v)
+	if m == nil {
+		return "", 0, "", fmt.Errorf("%v is not a valid chain-prefixed address", s)
+	}
+	chainId, ok := ChainShortNames[m[1]]
+	if !ok {
+		return "", 0, "", fmt.Errorf("unknown chain short name %v in %v", m[1], s)
+	}
+	return m[1], chainId, m[2], nil
+}
+
+// FormatChainAddress returns EIP-3770 chain-prefixed checksummed address, e.g. oeth:0x...
+func FormatChainAddress(chainId uint64, address common.Address) (string, error) {
+	shortName, ok := ChainShortName(chainId)
+	if !ok {
+		return "", fmt.Errorf("unknown short name of chain %v", chainId)
+	}
+	return shortName + ":" + address.Hex(), nil
+}
@@ -0,0 +1,86 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseChainAddress(t *testing.T) {
+	address := common.HexToAddress("0x24f8209EC5f56A07C94e834627F0651c19ACa0ac")
+	tests := []struct {
+		input     string
+		shortName string
+		chainId   uint64
+		err       bool
+	}{
+		{"0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "", 0, false},
+		{"eth:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "eth", 1, false},
+		{"oeth:0x24f8209ec5f56a07c94e834627f0651c19aca0ac", "oeth", 10, false},
+		{"arb-nova:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "arb-nova", 42170, false},
+		{"https://app.safe.global/home?safe=matic:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "matic", 137, false},
+		{"foo:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "", 0, true},
+		{"eth:0x24f8209EC5f56A07C94e834627F0651c19ACa0", "", 0, true},
+		{"https://app.safe.global/home", "", 0, true},
+	}
+
+	for i, test := range tests {
+		shortName, chainId, got, err := ParseChainAddress(test.input)
+		if test.err {
+			if err == nil {
+				t.Fatalf("test %d: expected error, got: %v", i, got.Hex())
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if shortName != test.shortName || chainId != test.chainId || got != address {
+			t.Fatalf("test %d: expected: %v %v %v, got: %v %v %v", i, test.shortName, test.chainId, address.Hex(), shortName, chainId, got.Hex())
+		}
+	}
+}
+
+func TestSplitChainAddress(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0x24f8209ec5f56a07c94e834627f0651c19aca0ac", "0x24f8209ec5f56a07c94e834627f0651c19aca0ac"},
+		{"eth:0x24F8209EC5f56A07C94e834627F0651c19ACa0ac", "0x24F8209EC5f56A07C94e834627F0651c19ACa0ac"},
+		{"https://app.safe.global/home?safe=matic:0x24f8209ec5f56a07c94e834627f0651c19aca0ac", "0x24f8209ec5f56a07c94e834627f0651c19aca0ac"},
+	}
+
+	for i, test := range tests {
+		_, _, got, err := SplitChainAddress(test.input)
+		if err != nil || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+	}
+}
+
+func TestFormatChainAddress(t *testing.T) {
+	address := common.HexToAddress("0x24f8209ec5f56a07c94e834627f0651c19aca0ac")
+	tests := []struct {
+		chainId  uint64
+		expected string
+	}{
+		{1, "eth:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac"},
+		{10, "oeth:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac"},
+		{42161, "arb1:0x24f8209EC5f56A07C94e834627F0651c19ACa0ac"},
+		{12345, ""},
+	}
+
+	for i, test := range tests {
+		got, err := FormatChainAddress(test.chainId, address)
+		if test.expected == "" {
+			if err == nil {
+				t.Fatalf("test %d: expected error, got: %v", i, got)
+			}
+			continue
+		}
+		if err != nil || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+	}
+}
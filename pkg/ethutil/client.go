@@ -0,0 +1,41 @@
+package ethutil
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client wraps both the raw rpc client and the eth client of the same connection.
+type Client struct {
+	EthClient *ethclient.Client
+	RpcClient *rpc.Client
+}
+
+// Dial connects a client to the given node url. Known provider quirks in responses are normalized if
+// node url is a http(s) url.
+func Dial(ctx context.Context, nodeUrl string) (*Client, error) {
+	rpcClient, err := rpc.DialOptions(ctx, nodeUrl, rpc.WithHTTPClient(&http.Client{
+		Transport: NewNormalizeTransport(http.DefaultTransport),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClient(rpcClient), nil
+}
+
+// NewClient creates a client that uses the given rpc client.
+func NewClient(rpcClient *rpc.Client) *Client {
+	return &Client{
+		EthClient: ethclient.NewClient(rpcClient),
+		RpcClient: rpcClient,
+	}
+}
+
+// Close closes the underlying rpc connection.
+func (c *Client) Close() {
+	c.RpcClient.Close()
+}
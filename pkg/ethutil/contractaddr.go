@@ -0,0 +1,19 @@
+package ethutil
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ContractAddress returns the address of contract created by deployer with nonce (CREATE), i.e.
+// keccak256(rlp([deployer, nonce]))[12:]
+func ContractAddress(deployer common.Address, nonce uint64) common.Address {
+	return crypto.CreateAddress(deployer, nonce)
+}
+
+// Create2Address returns the address of contract created by deployer with salt and init code (CREATE2), i.e.
+// keccak256(0xff ++ deployer ++ salt ++ keccak256(initCode))[12:]
+// See: https://eips.ethereum.org/EIPS/eip-1014
+func Create2Address(deployer common.Address, salt common.Hash, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes())
+}
@@ -0,0 +1,53 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestContractAddress(t *testing.T) {
+	tests := []struct {
+		deployer string
+		nonce    uint64
+		want     string
+	}{
+		{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", 0, "0x3bb8C061Ec6EdB3E78777b983b96468CC4799888"},
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 0, "0xcd234A471b72ba2F1Ccf0A70FCABA648a5eeCD8d"},
+		{"0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", 1, "0x343c43A37D37dfF08AE8C4A11544c718AbB4fCF8"},
+	}
+
+	for i, test := range tests {
+		got := ContractAddress(common.HexToAddress(test.deployer), test.nonce)
+		if got != common.HexToAddress(test.want) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.want, got.Hex())
+		}
+	}
+}
+
+func TestCreate2Address(t *testing.T) {
+	// test vectors in https://eips.ethereum.org/EIPS/eip-1014
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0xdeadbeef00000000000000000000000000000000", "0x000000000000000000000000feed000000000000000000000000000000000000", "0x00", "0xD04116cDd17beBE565EB2422F2497E06cC1C9833"},
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0xdeadbeef", "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e"},
+		{"0x00000000000000000000000000000000deadbeef", "0x00000000000000000000000000000000000000000000000000000000cafebabe", "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+		{"0x0000000000000000000000000000000000000000", "0x0000000000000000000000000000000000000000000000000000000000000000", "0x", "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0"},
+	}
+
+	for i, test := range tests {
+		initCodeHash := crypto.Keccak256Hash(hexutil.MustDecode(test.initCode))
+		got := Create2Address(common.HexToAddress(test.deployer), common.HexToHash(test.salt), initCodeHash)
+		if got != common.HexToAddress(test.want) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.want, got.Hex())
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package ethutil
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ParseInteger parses decimal (e.g. "-123") or 0x prefixed hex (e.g. "0xff") integer.
+func ParseInteger(s string) (*big.Int, error) {
+	var n *big.Int
+	var ok bool
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = new(big.Int).SetString(s[2:], 16)
+	} else {
+		n, ok = new(big.Int).SetString(s, 10)
+	}
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %v", s)
+	}
+	return n, nil
+}
+
+// checkBits returns error if bits is not a valid size of solidity integer, i.e. 8, 16, ..., 256.
+func checkBits(bits int) error {
+	if bits <= 0 || bits > 256 || bits%8 != 0 {
+		return fmt.Errorf("invalid bits %v, expected one of 8, 16, ..., 256", bits)
+	}
+	return nil
+}
+
+// CheckUint returns error if n is out of range of uint<bits>.
+func CheckUint(n *big.Int, bits int) error {
+	if err := checkBits(bits); err != nil {
+		return err
+	}
+	if n.Sign() < 0 || n.BitLen() > bits {
+		return fmt.Errorf("%v is out of range of uint%v [0, 2^%v-1]", n, bits, bits)
+	}
+	return nil
+}
+
+// CheckInt returns error if n is out of range of int<bits>.
+func CheckInt(n *big.Int, bits int) error {
+	if err := checkBits(bits); err != nil {
+		return err
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	min := new(big.Int).Neg(max)
+	if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+		return fmt.Errorf("%v is out of range of int%v [-2^%v, 2^%v-1]", n, bits, bits-1, bits-1)
+	}
+	return nil
+}
+
+// ToTwosComplement returns two's complement of n in bits, n must be in range of int<bits>.
+func ToTwosComplement(n *big.Int, bits int) *big.Int {
+	if n.Sign() >= 0 {
+		return new(big.Int).Set(n)
+	}
+	return new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+}
+
+// FromTwosComplement interprets n (in range of uint<bits>) as two's complement of int<bits>.
+func FromTwosComplement(n *big.Int, bits int) *big.Int {
+	if n.Bit(bits-1) == 0 {
+		return new(big.Int).Set(n)
+	}
+	return new(big.Int).Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+}
+
+// PadBytes pads b with zero bytes to size, on the left (like uint and address) or on the right (like bytes<N> and
+// string).
+func PadBytes(b []byte, size int, right bool) ([]byte, error) {
+	if len(b) > size {
+		return nil, fmt.Errorf("%v bytes data can not be padded to %v bytes", len(b), size)
+	}
+	var padded = make([]byte, size)
+	if right {
+		copy(padded, b)
+	} else {
+		copy(padded[size-len(b):], b)
+	}
+	return padded, nil
+}
+
+// UnpadBytes removes zero bytes on the left, or on the right if right is true.
+func UnpadBytes(b []byte, right bool) []byte {
+	if right {
+		end := len(b)
+		for end > 0 && b[end-1] == 0 {
+			end--
+		}
+		return b[:end]
+	}
+	start := 0
+	for start < len(b) && b[start] == 0 {
+		start++
+	}
+	return b[start:]
+}
+
+// StringToBytes32 encodes short string as bytes32, i.e. its UTF-8 bytes padded on the right.
+func StringToBytes32(s string) (common.Hash, error) {
+	if len(s) > 32 {
+		return common.Hash{}, fmt.Errorf("string of %v bytes is longer than 32 bytes", len(s))
+	}
+	var h common.Hash
+	copy(h[:], s)
+	return h, nil
+}
+
+// Bytes32ToString decodes bytes32 encoded by StringToBytes32.
+func Bytes32ToString(b []byte) (string, error) {
+	if len(b) != 32 {
+		return "", fmt.Errorf("expected 32 bytes, got %v bytes", len(b))
+	}
+	s := UnpadBytes(b, true)
+	if !utf8.Valid(s) {
+		return "", errors.New("bytes32 is not a UTF-8 string")
+	}
+	return string(s), nil
+}
+
+// AddressToBytes32 returns address padded on the left to 32 bytes, as it's encoded in abi and storage.
+func AddressToBytes32(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+// Bytes32ToAddress returns the address in lower 20 bytes of b, the upper 12 bytes must be zero.
+func Bytes32ToAddress(b []byte) (common.Address, error) {
+	if len(b) != 32 {
+		return common.Address{}, fmt.Errorf("expected 32 bytes, got %v bytes", len(b))
+	}
+	for _, c := range b[:12] {
+		if c != 0 {
+			return common.Address{}, errors.New("upper 12 bytes are not zero, it's not an address")
+		}
+	}
+	return common.BytesToAddress(b[12:]), nil
+}
+
+// ParseBool decodes abi encoded bool (or integer), only 0 and 1 are valid.
+func ParseBool(n *big.Int) (bool, error) {
+	switch {
+	case n.Sign() == 0:
+		return false, nil
+	case n.Cmp(big.NewInt(1)) == 0:
+		return true, nil
+	default:
+		return false, fmt.Errorf("%v is not a valid bool, expected 0 or 1", n)
+	}
+}
+
+// ReverseBytes returns bytes in reversed order, i.e. converts big-endian to little-endian and vice versa.
+func ReverseBytes(b []byte) []byte {
+	var reversed = make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
@@ -0,0 +1,129 @@
+package ethutil
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCheckIntRange(t *testing.T) {
+	tests := []struct {
+		value     string
+		bits      int
+		validUint bool
+		validInt  bool
+	}{
+		{"0", 8, true, true},
+		{"255", 8, true, false},
+		{"256", 8, false, false},
+		{"127", 8, true, true},
+		{"-128", 8, false, true},
+		{"-129", 8, false, false},
+		{"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", 256, true, false},
+	}
+	for i, tt := range tests {
+		n, err := ParseInteger(tt.value)
+		if err != nil {
+			t.Fatalf("test %d: ParseInteger fail: %v", i, err)
+		}
+		if got := CheckUint(n, tt.bits) == nil; got != tt.validUint {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.validUint, got)
+		}
+		if got := CheckInt(n, tt.bits) == nil; got != tt.validInt {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.validInt, got)
+		}
+	}
+
+	if err := CheckUint(big.NewInt(1), 7); err == nil {
+		t.Fatalf("expected error for invalid bits, got nil")
+	}
+}
+
+func TestTwosComplement(t *testing.T) {
+	tests := []struct {
+		value    int64
+		bits     int
+		expected int64
+	}{
+		{-1, 8, 0xff},
+		{-128, 8, 0x80},
+		{127, 8, 0x7f},
+		{-2, 16, 0xfffe},
+	}
+	for i, tt := range tests {
+		got := ToTwosComplement(big.NewInt(tt.value), tt.bits)
+		if got.Int64() != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.expected, got)
+		}
+		if back := FromTwosComplement(got, tt.bits); back.Int64() != tt.value {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.value, back)
+		}
+	}
+}
+
+func TestPadBytes(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		size     int
+		right    bool
+		expected []byte
+	}{
+		{[]byte{1, 2}, 4, false, []byte{0, 0, 1, 2}},
+		{[]byte{1, 2}, 4, true, []byte{1, 2, 0, 0}},
+		{[]byte{1, 2}, 2, true, []byte{1, 2}},
+	}
+	for i, tt := range tests {
+		got, err := PadBytes(tt.input, tt.size, tt.right)
+		if err != nil {
+			t.Fatalf("test %d: PadBytes fail: %v", i, err)
+		}
+		if !bytes.Equal(got, tt.expected) {
+			t.Fatalf("test %d: expected: %x, got: %x", i, tt.expected, got)
+		}
+		if unpadded := UnpadBytes(got, tt.right); !bytes.Equal(unpadded, tt.input) {
+			t.Fatalf("test %d: expected: %x, got: %x", i, tt.input, unpadded)
+		}
+	}
+	if _, err := PadBytes([]byte{1, 2, 3}, 2, false); err == nil {
+		t.Fatalf("expected error for too long data, got nil")
+	}
+}
+
+func TestBytes32Conversions(t *testing.T) {
+	h, err := StringToBytes32("USDC")
+	if err != nil {
+		t.Fatalf("StringToBytes32 fail: %v", err)
+	}
+	if expected := "0x5553444300000000000000000000000000000000000000000000000000000000"; h.Hex() != expected {
+		t.Fatalf("expected: %v, got: %v", expected, h.Hex())
+	}
+	if s, err := Bytes32ToString(h[:]); err != nil || s != "USDC" {
+		t.Fatalf("expected: %v, got: %v %v", "USDC", s, err)
+	}
+	if _, err := StringToBytes32("this string is longer than 32 bytes"); err == nil {
+		t.Fatalf("expected error for long string, got nil")
+	}
+
+	address := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	padded := AddressToBytes32(address)
+	if got, err := Bytes32ToAddress(padded[:]); err != nil || got != address {
+		t.Fatalf("expected: %v, got: %v %v", address, got, err)
+	}
+	if _, err := Bytes32ToAddress(h[:]); err == nil {
+		t.Fatalf("expected error for non-address bytes32, got nil")
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	if v, err := ParseBool(big.NewInt(1)); err != nil || !v {
+		t.Fatalf("expected: true, got: %v %v", v, err)
+	}
+	if v, err := ParseBool(big.NewInt(0)); err != nil || v {
+		t.Fatalf("expected: false, got: %v %v", v, err)
+	}
+	if _, err := ParseBool(big.NewInt(2)); err == nil {
+		t.Fatalf("expected error for 2, got nil")
+	}
+}
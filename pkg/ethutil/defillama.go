@@ -0,0 +1,137 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// DefiLlamaApiUrl is the base url of DefiLlama TVL api
+const DefiLlamaApiUrl = "https://api.llama.fi"
+
+// DefiLlamaProtocol is a protocol returned by DefiLlama /protocols api.
+type DefiLlamaProtocol struct {
+	Name      string                     `json:"name"`
+	Slug      string                     `json:"slug"`
+	Symbol    string                     `json:"symbol"`
+	Category  string                     `json:"category"`
+	Url       string                     `json:"url"`
+	Chains    []string                   `json:"chains"`
+	Tvl       decimal.Decimal            `json:"tvl"`
+	ChainTvls map[string]decimal.Decimal `json:"chainTvls"` // keyed by chain, also contains entries like "Ethereum-borrowed" and "staking"
+	Change1d  decimal.NullDecimal        `json:"change_1d"` // percentage change of tvl in 1 day
+	Change7d  decimal.NullDecimal        `json:"change_7d"` // percentage change of tvl in 7 days
+	Mcap      decimal.NullDecimal        `json:"mcap"`      // market cap of protocol token
+}
+
+// DefiLlamaProtocols returns all protocols tracked by DefiLlama, baseUrl is empty means DefiLlamaApiUrl.
+func DefiLlamaProtocols(ctx context.Context, baseUrl string) ([]DefiLlamaProtocol, error) {
+	if baseUrl == "" {
+		baseUrl = DefiLlamaApiUrl
+	}
+	body, err := httpGet(ctx, baseUrl+"/protocols")
+	if err != nil {
+		return nil, err
+	}
+	var protocols []DefiLlamaProtocol
+	if err := json.Unmarshal(body, &protocols); err != nil {
+		return nil, fmt.Errorf("parse defillama protocols fail: %w", err)
+	}
+	return protocols, nil
+}
+
+// FindDefiLlamaProtocol finds protocol whose slug, name or symbol equals query (case-insensitive). If not found, the
+// protocols whose slug or name contains query are returned as candidates.
+func FindDefiLlamaProtocol(protocols []DefiLlamaProtocol, query string) (*DefiLlamaProtocol, []DefiLlamaProtocol) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	for _, field := range []func(p DefiLlamaProtocol) string{
+		func(p DefiLlamaProtocol) string { return p.Slug },
+		func(p DefiLlamaProtocol) string { return p.Name },
+		func(p DefiLlamaProtocol) string { return p.Symbol },
+	} {
+		for i := range protocols {
+			if strings.ToLower(field(protocols[i])) == query {
+				return &protocols[i], nil
+			}
+		}
+	}
+
+	var candidates []DefiLlamaProtocol
+	for _, p := range protocols {
+		if strings.Contains(strings.ToLower(p.Slug), query) || strings.Contains(strings.ToLower(p.Name), query) {
+			candidates = append(candidates, p)
+		}
+	}
+	return nil, candidates
+}
+
+// DefiLlamaToken is the price data of token returned by DefiLlama coins api.
+type DefiLlamaToken struct {
+	Key        string              `json:"-"` // e.g. ethereum:0xdac17f958d2ee523a2206206994597c13d831ec7 or coingecko:ethereum
+	Symbol     string              `json:"symbol"`
+	Decimals   int                 `json:"decimals"`
+	Price      decimal.Decimal     `json:"price"`
+	Timestamp  int64               `json:"timestamp"`
+	Confidence float64             `json:"confidence"`
+	Change24h  decimal.NullDecimal `json:"-"` // percentage change of price in 24 hours
+}
+
+// DefiLlamaTokens returns current price and 24 hours change of tokens, keys are in form of <chain>:<address> or
+// coingecko:<coin-id>. Tokens unknown to DefiLlama are absent in result. baseUrl is empty means DefiLlamaCoinsApiUrl.
+func DefiLlamaTokens(ctx context.Context, baseUrl string, keys []string) ([]DefiLlamaToken, error) {
+	if baseUrl == "" {
+		baseUrl = DefiLlamaCoinsApiUrl
+	}
+	var escaped []string
+	for _, key := range keys {
+		escaped = append(escaped, url.PathEscape(key))
+	}
+	coins := strings.Join(escaped, ",")
+
+	body, err := httpGet(ctx, baseUrl+"/prices/current/"+coins)
+	if err != nil {
+		return nil, err
+	}
+	var prices struct {
+		Coins map[string]DefiLlamaToken `json:"coins"`
+	}
+	if err := json.Unmarshal(body, &prices); err != nil {
+		return nil, fmt.Errorf("parse defillama prices fail: %w", err)
+	}
+
+	// e.g. {"coins":{"coingecko:ethereum":-1.23}}
+	body, err = httpGet(ctx, baseUrl+"/percentage/"+coins+"?period=24h")
+	if err != nil {
+		return nil, err
+	}
+	var percentages struct {
+		Coins map[string]decimal.Decimal `json:"coins"`
+	}
+	if err := json.Unmarshal(body, &percentages); err != nil {
+		return nil, fmt.Errorf("parse defillama percentages fail: %w", err)
+	}
+
+	var tokens []DefiLlamaToken
+	for _, key := range keys {
+		// addresses are returned in lowercase
+		token, ok := prices.Coins[key]
+		if !ok {
+			token, ok = prices.Coins[strings.ToLower(key)]
+		}
+		if !ok {
+			continue
+		}
+		token.Key = key
+		if change, ok := percentages.Coins[key]; ok {
+			token.Change24h = decimal.NullDecimal{Decimal: change, Valid: true}
+		} else if change, ok := percentages.Coins[strings.ToLower(key)]; ok {
+			token.Change24h = decimal.NullDecimal{Decimal: change, Valid: true}
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,49 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFindDefiLlamaProtocol(t *testing.T) {
+	var protocols []DefiLlamaProtocol
+	content := `[
+		{"name":"Lido","slug":"lido","symbol":"LDO","chains":["Ethereum"],"tvl":14000000000,"change_1d":null},
+		{"name":"Uniswap V3","slug":"uniswap-v3","symbol":"UNI","chains":["Ethereum","Arbitrum"],"tvl":3500000000,"change_1d":1.5},
+		{"name":"Uniswap V2","slug":"uniswap-v2","symbol":"UNI","chains":["Ethereum"],"tvl":1500000000}
+	]`
+	if err := json.Unmarshal([]byte(content), &protocols); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query      string
+		found      string
+		candidates int
+	}{
+		{"lido", "lido", 0},
+		{"Uniswap V3", "uniswap-v3", 0},
+		{"LDO", "lido", 0},
+		{"uni", "uniswap-v3", 0}, // first protocol whose symbol matches
+		{"uniswap", "", 2},
+		{"curve", "", 0},
+	}
+
+	for i, test := range tests {
+		found, candidates := FindDefiLlamaProtocol(protocols, test.query)
+		var slug string
+		if found != nil {
+			slug = found.Slug
+		}
+		if slug != test.found {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.found, slug)
+		}
+		if len(candidates) != test.candidates {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.candidates, len(candidates))
+		}
+	}
+
+	if protocols[0].Change1d.Valid || !protocols[1].Change1d.Valid {
+		t.Fatalf("expected: null change_1d of lido only, got: %v %v", protocols[0].Change1d, protocols[1].Change1d)
+	}
+}
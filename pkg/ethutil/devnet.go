@@ -0,0 +1,67 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// DevnetMnemonic is the default mnemonic of anvil and hardhat node.
+const DevnetMnemonic = "test test test test test test test test test test test junk"
+
+// ErrSetBalanceNotSupported is returned by DevnetSetBalance if node is neither anvil nor hardhat, e.g. geth --dev.
+var ErrSetBalanceNotSupported = errors.New("setBalance rpc is not supported by node")
+
+// isMethodNotFound reports whether err is the json-rpc error of unknown method.
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+		return true
+	}
+	// some nodes return a generic error code
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") || strings.Contains(msg, "does not exist")
+}
+
+// DevnetSetBalance sets balance of address by anvil_setBalance or hardhat_setBalance (anvil supports both).
+func DevnetSetBalance(ctx context.Context, rpcClient *rpc.Client, address common.Address, balance *big.Int) error {
+	for _, method := range []string{"anvil_setBalance", "hardhat_setBalance"} {
+		err := rpcClient.CallContext(ctx, nil, method, address, hexutil.EncodeBig(balance))
+		if err == nil {
+			return nil
+		}
+		if !isMethodNotFound(err) {
+			return fmt.Errorf("%v fail: %w", method, err)
+		}
+	}
+	return ErrSetBalanceNotSupported
+}
+
+// DevnetAccounts returns the unlocked accounts of dev node, the first one is the faucet of geth --dev.
+func DevnetAccounts(ctx context.Context, rpcClient *rpc.Client) ([]common.Address, error) {
+	var accounts []common.Address
+	if err := rpcClient.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+		return nil, fmt.Errorf("eth_accounts fail: %w", err)
+	}
+	return accounts, nil
+}
+
+// DevnetSendValue sends value from unlocked account of dev node by eth_sendTransaction.
+func DevnetSendValue(ctx context.Context, rpcClient *rpc.Client, from common.Address, to common.Address, value *big.Int) (common.Hash, error) {
+	var txHash common.Hash
+	err := rpcClient.CallContext(ctx, &txHash, "eth_sendTransaction", map[string]any{
+		"from":  from,
+		"to":    to,
+		"value": hexutil.EncodeBig(value),
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendTransaction fail: %w", err)
+	}
+	return txHash, nil
+}
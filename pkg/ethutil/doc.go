@@ -0,0 +1,7 @@
+// Package ethutil is the library behind the ethutil command line tool, it can be embedded
+// by Go programs which want to transfer eth, call any contract function, encode abi, sign
+// message etc without shelling out to the ethutil command.
+//
+// All functions return errors instead of exiting the process, and nothing is written to the
+// standard logger, progress messages are passed to the Logf field of options if it is set.
+package ethutil
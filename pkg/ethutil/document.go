@@ -0,0 +1,119 @@
+package ethutil
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DocumentEnvelopeVersion is the version of DocumentEnvelope format
+const DocumentEnvelopeVersion = 1
+
+// DocumentEnvelope is the detached signature of a JSON document (e.g. operator registration of DVT cluster,
+// off-chain governance attestation). It bundles the signer and the chain context, so the signature can't be replayed
+// on another chain, and it's verified by the document together with the envelope.
+//
+// The signed message is an EIP191 personal_sign message (see DocumentEnvelope.Message), which is human-readable in
+// hardware wallets and can be verified by any personal_sign tool.
+type DocumentEnvelope struct {
+	Version     int            `json:"version"`
+	Signer      common.Address `json:"signer"`
+	ChainID     uint64         `json:"chain_id"`
+	PayloadHash common.Hash    `json:"payload_hash"` // keccak256 of canonical JSON of document
+	SignedAt    time.Time      `json:"signed_at"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+// CanonicalJSON returns the canonical form of JSON document, i.e. compact JSON with object keys sorted, so
+// reformatting the document does not change its hash. Numbers are kept as is.
+func CanonicalJSON(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid JSON document: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("invalid JSON document: multiple JSON values")
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil { // keys of map are sorted by encoding/json
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// DocumentHash returns keccak256 of canonical JSON of document.
+func DocumentHash(document []byte) (common.Hash, error) {
+	canonical, err := CanonicalJSON(document)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(canonical), nil
+}
+
+// Message returns the personal_sign message of envelope.
+func (e *DocumentEnvelope) Message() string {
+	var b strings.Builder
+	b.WriteString("Eth Signed Document\n\n")
+	fmt.Fprintf(&b, "Version: %d\n", e.Version)
+	fmt.Fprintf(&b, "Signer: %s\n", e.Signer.Hex())
+	fmt.Fprintf(&b, "Chain ID: %d\n", e.ChainID)
+	fmt.Fprintf(&b, "Payload Hash: %s\n", e.PayloadHash.Hex())
+	fmt.Fprintf(&b, "Signed At: %s", e.SignedAt.UTC().Format(time.RFC3339))
+	return b.String()
+}
+
+// SignDocument signs JSON document for chainID, and returns the envelope of detached signature.
+func SignDocument(document []byte, chainID uint64, privateKey *ecdsa.PrivateKey, signedAt time.Time) (*DocumentEnvelope, error) {
+	payloadHash, err := DocumentHash(document)
+	if err != nil {
+		return nil, err
+	}
+	envelope := &DocumentEnvelope{
+		Version:     DocumentEnvelopeVersion,
+		Signer:      AddressFromPrivateKey(privateKey),
+		ChainID:     chainID,
+		PayloadHash: payloadHash,
+		SignedAt:    signedAt.UTC().Truncate(time.Second),
+	}
+	signature, err := PersonalSign(envelope.Message(), privateKey)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Signature = common.FromHex(signature)
+	return envelope, nil
+}
+
+// VerifyDocument verifies envelope is a valid signature of document by envelope.Signer. The caller checks the signer
+// and the chain id of envelope are expected.
+func VerifyDocument(document []byte, envelope *DocumentEnvelope) error {
+	if envelope.Version != DocumentEnvelopeVersion {
+		return fmt.Errorf("unsupported envelope version %v", envelope.Version)
+	}
+	payloadHash, err := DocumentHash(document)
+	if err != nil {
+		return err
+	}
+	if payloadHash != envelope.PayloadHash {
+		return fmt.Errorf("document is modified, its hash %v does not match payload hash %v of envelope", payloadHash.Hex(), envelope.PayloadHash.Hex())
+	}
+	signer, err := RecoverPersonalSignBytes([]byte(envelope.Message()), envelope.Signature)
+	if err != nil {
+		return err
+	}
+	if signer != envelope.Signer {
+		return fmt.Errorf("envelope is signed by %v, not by signer %v", signer.Hex(), envelope.Signer.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package ethutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		document string
+		expected string
+	}{
+		{`{"b": 1, "a": [true, null, "x"]}`, `{"a":[true,null,"x"],"b":1}`},
+		{"{\n  \"amount\": 123456789012345678901234567890,\n  \"url\": \"https://a.b/?x=1&y=<2>\"\n}", `{"amount":123456789012345678901234567890,"url":"https://a.b/?x=1&y=<2>"}`},
+		{`{"z": {"d": 1.50, "c": 2}}`, `{"z":{"c":2,"d":1.50}}`},
+	}
+
+	for i, tt := range tests {
+		got, err := CanonicalJSON([]byte(tt.document))
+		if err != nil {
+			t.Fatalf("test %d: CanonicalJSON fail: %v", i, err)
+		}
+		if string(got) != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %s", i, tt.expected, got)
+		}
+	}
+
+	for i, document := range []string{``, `{"a":1`, `{"a":1} {"b":2}`} {
+		if _, err := CanonicalJSON([]byte(document)); err == nil {
+			t.Fatalf("test %d: expected error for %q, got nil", i, document)
+		}
+	}
+}
+
+func TestSignVerifyDocument(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	document := []byte(`{"operator": "0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "cluster": 7}`)
+
+	envelope, err := SignDocument(document, 1, privateKey, time.Date(2023, 6, 1, 10, 2, 13, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("SignDocument fail: %v", err)
+	}
+	if envelope.Signer != AddressFromPrivateKey(privateKey) {
+		t.Fatalf("expected: %v, got: %v", AddressFromPrivateKey(privateKey), envelope.Signer)
+	}
+
+	reformatted := []byte("{\n  \"cluster\": 7,\n  \"operator\": \"0x24f8209EC5f56A07C94e834627F0651c19ACa0ac\"\n}")
+	if err := VerifyDocument(reformatted, envelope); err != nil {
+		t.Fatalf("VerifyDocument fail: %v", err)
+	}
+
+	if err := VerifyDocument([]byte(`{"operator": "0x24f8209EC5f56A07C94e834627F0651c19ACa0ac", "cluster": 8}`), envelope); err == nil {
+		t.Fatalf("expected error for modified document, got nil")
+	}
+
+	replayed := *envelope
+	replayed.ChainID = 5
+	if err := VerifyDocument(document, &replayed); err == nil {
+		t.Fatalf("expected error for modified chain id, got nil")
+	}
+}
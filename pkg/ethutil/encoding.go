@@ -0,0 +1,277 @@
+package ethutil
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58Encode encodes data by base58 of Bitcoin alphabet, which is also used by IPFS CIDv0 and Solana.
+func Base58Encode(data []byte) string {
+	var encoded []byte
+	x := new(big.Int).SetBytes(data)
+	mod := new(big.Int)
+	for x.Sign() > 0 {
+		x.DivMod(x, big.NewInt(58), mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// Base58Decode decodes base58 string of Bitcoin alphabet.
+func Base58Decode(s string) ([]byte, error) {
+	if s == "" {
+		return nil, errors.New("empty base58 string")
+	}
+	x := new(big.Int)
+	for _, c := range s {
+		index := strings.IndexRune(base58Alphabet, c)
+		if index < 0 {
+			return nil, fmt.Errorf("invalid base58 character %q", c)
+		}
+		x.Mul(x, big.NewInt(58))
+		x.Add(x, big.NewInt(int64(index)))
+	}
+	var leadingZeros int
+	for leadingZeros < len(s) && s[leadingZeros] == base58Alphabet[0] {
+		leadingZeros++
+	}
+	return append(make([]byte, leadingZeros), x.Bytes()...), nil
+}
+
+// Base58CheckEncode encodes payload by base58 with 4 bytes double sha256 checksum appended.
+func Base58CheckEncode(payload []byte) string {
+	checksum := doubleSha256(payload)
+	return Base58Encode(append(append([]byte{}, payload...), checksum[:4]...))
+}
+
+// Base58CheckDecode decodes base58check string and verifies its checksum.
+func Base58CheckDecode(s string) ([]byte, error) {
+	data, err := Base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 5 {
+		return nil, errors.New("base58 string too short")
+	}
+	payload, checksum := data[:len(data)-4], data[len(data)-4:]
+	if expected := doubleSha256(payload); !bytes.Equal(checksum, expected[:4]) {
+		return nil, errors.New("invalid base58 checksum")
+	}
+	return payload, nil
+}
+
+// Base64Decode decodes standard or URL-safe base64, with or without padding.
+func Base64Decode(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32mConst is the checksum constant of bech32m (BIP-350), it's 1 for bech32 (BIP-173).
+const bech32mConst = 0x2bc830a3
+
+func bech32Polymod(values []byte) uint32 {
+	generator := []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HrpExpand(hrp string) []byte {
+	var expanded []byte
+	for _, c := range []byte(hrp) {
+		expanded = append(expanded, c>>5)
+	}
+	expanded = append(expanded, 0)
+	for _, c := range []byte(hrp) {
+		expanded = append(expanded, c&31)
+	}
+	return expanded
+}
+
+// convertBits regroups data of fromBits bits into groups of toBits bits.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc, bits uint
+	var result []byte
+	maxValue := uint(1)<<toBits - 1
+	for _, b := range data {
+		if uint(b)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid data value %v", b)
+		}
+		acc = acc<<fromBits | uint(b)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return result, nil
+}
+
+// Bech32Encode encodes data with human-readable part hrp by bech32 (BIP-173), or bech32m (BIP-350) if bech32m is
+// true, e.g. address of Cosmos chains.
+func Bech32Encode(hrp string, data []byte, bech32m bool) (string, error) {
+	if hrp == "" {
+		return "", errors.New("empty human-readable part")
+	}
+	hrp = strings.ToLower(hrp)
+	values, _ := convertBits(data, 8, 5, true)
+	var constant uint32 = 1
+	if bech32m {
+		constant = bech32mConst
+	}
+	polymod := bech32Polymod(append(append(bech32HrpExpand(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ constant
+	for i := 0; i < 6; i++ {
+		values = append(values, byte(polymod>>(5*(5-i))&31))
+	}
+
+	var encoded strings.Builder
+	encoded.WriteString(hrp + "1")
+	for _, v := range values {
+		encoded.WriteByte(bech32Charset[v])
+	}
+	return encoded.String(), nil
+}
+
+// Bech32Decode decodes bech32 or bech32m string, returns human-readable part, data and whether it's bech32m.
+func Bech32Decode(s string) (string, []byte, bool, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, false, errors.New("mixed case bech32 string")
+	}
+	s = strings.ToLower(s)
+	separator := strings.LastIndexByte(s, '1')
+	if separator < 1 || separator+7 > len(s) {
+		return "", nil, false, errors.New("invalid bech32 separator position")
+	}
+	hrp := s[:separator]
+	var values []byte
+	for _, c := range s[separator+1:] {
+		index := strings.IndexRune(bech32Charset, c)
+		if index < 0 {
+			return "", nil, false, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		values = append(values, byte(index))
+	}
+
+	var bech32m bool
+	switch bech32Polymod(append(bech32HrpExpand(hrp), values...)) {
+	case 1:
+	case bech32mConst:
+		bech32m = true
+	default:
+		return "", nil, false, errors.New("invalid bech32 checksum")
+	}
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, false, err
+	}
+	return hrp, data, bech32m, nil
+}
+
+// cidV1Prefix is the prefix of CIDv1 converted from CIDv0, i.e. version 1 and codec dag-pb.
+var cidV1Prefix = []byte{0x01, 0x70}
+
+// sha256MultihashPrefix is the multihash prefix of 32 bytes sha2-256 digest, CIDv0 is this multihash.
+var sha256MultihashPrefix = []byte{0x12, 0x20}
+
+var cidBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// CIDv0ToV1 converts IPFS CIDv0 (base58, starts with "Qm") to CIDv1 (base32, starts with "b").
+func CIDv0ToV1(cid string) (string, error) {
+	multihash, err := Base58Decode(cid)
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDv0 %v: %w", cid, err)
+	}
+	if len(multihash) != 34 || !bytes.HasPrefix(multihash, sha256MultihashPrefix) {
+		return "", fmt.Errorf("invalid CIDv0 %v: not a sha2-256 multihash", cid)
+	}
+	return "b" + strings.ToLower(cidBase32.EncodeToString(append(append([]byte{}, cidV1Prefix...), multihash...))), nil
+}
+
+// CIDv1ToV0 converts IPFS CIDv1 (base32) of dag-pb and sha2-256 to CIDv0.
+func CIDv1ToV0(cid string) (string, error) {
+	if !strings.HasPrefix(cid, "b") {
+		return "", fmt.Errorf("only base32 CIDv1 (starts with b) is supported")
+	}
+	data, err := cidBase32.DecodeString(strings.ToUpper(cid[1:]))
+	if err != nil {
+		return "", fmt.Errorf("invalid CIDv1 %v: %w", cid, err)
+	}
+	if !bytes.HasPrefix(data, cidV1Prefix) {
+		return "", fmt.Errorf("CIDv1 %v is not dag-pb, can not be converted to CIDv0", cid)
+	}
+	multihash := data[len(cidV1Prefix):]
+	if len(multihash) != 34 || !bytes.HasPrefix(multihash, sha256MultihashPrefix) {
+		return "", fmt.Errorf("CIDv1 %v is not sha2-256, can not be converted to CIDv0", cid)
+	}
+	return Base58Encode(multihash), nil
+}
+
+// ParseDataURI decodes data URI (RFC 2397), e.g. "data:application/json;base64,eyJhIjoxfQ==" returned by tokenURI
+// of on-chain NFT. The media type is "text/plain;charset=US-ASCII" if it's omitted.
+func ParseDataURI(uri string) (string, []byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return "", nil, errors.New("not a data URI")
+	}
+	header, content, found := strings.Cut(uri[len("data:"):], ",")
+	if !found {
+		return "", nil, errors.New("invalid data URI: comma is missing")
+	}
+	mediaType, isBase64 := header, false
+	if strings.HasSuffix(header, ";base64") {
+		mediaType, isBase64 = strings.TrimSuffix(header, ";base64"), true
+	}
+	if mediaType == "" {
+		mediaType = "text/plain;charset=US-ASCII"
+	}
+
+	if isBase64 {
+		data, err := Base64Decode(content)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid base64 of data URI: %w", err)
+		}
+		return mediaType, data, nil
+	}
+	data, err := url.PathUnescape(content)
+	if err != nil {
+		// many on-chain NFTs embed raw SVG (e.g. "data:image/svg+xml;utf8,<svg ...>") with unescaped %
+		return mediaType, []byte(content), nil
+	}
+	return mediaType, []byte(data), nil
+}
@@ -0,0 +1,121 @@
+package ethutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		data     []byte
+		expected string
+	}{
+		{[]byte("hello world"), "StV1DL6CwTryKyV"},
+		{[]byte{0, 0, 1}, "112"},
+		{[]byte{}, ""},
+	}
+
+	for i, test := range tests {
+		encoded := Base58Encode(test.data)
+		if encoded != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, encoded)
+		}
+		if test.expected == "" {
+			continue
+		}
+		decoded, err := Base58Decode(encoded)
+		if err != nil || !bytes.Equal(decoded, test.data) {
+			t.Fatalf("test %d: expected: %x, got: %x (%v)", i, test.data, decoded, err)
+		}
+	}
+}
+
+func TestBech32(t *testing.T) {
+	// valid strings of BIP-173 and BIP-350
+	tests := []struct {
+		input   string
+		hrp     string
+		bech32m bool
+	}{
+		{"A12UEL5L", "a", false},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", "abcdef", false},
+		{"a1lqfn3a", "a", true},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", "abcdef", true},
+	}
+
+	for i, test := range tests {
+		hrp, data, bech32m, err := Bech32Decode(test.input)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if hrp != test.hrp || bech32m != test.bech32m {
+			t.Fatalf("test %d: expected: %v %v, got: %v %v", i, test.hrp, test.bech32m, hrp, bech32m)
+		}
+		encoded, err := Bech32Encode(hrp, data, bech32m)
+		if err != nil || encoded != strings.ToLower(test.input) {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, strings.ToLower(test.input), encoded, err)
+		}
+	}
+
+	// address of Evmos is bech32 of eth address
+	address := common.HexToAddress("0x24f8209EC5f56A07C94e834627F0651c19ACa0ac")
+	encoded, err := Bech32Encode("evmos", address.Bytes(), false)
+	if err != nil {
+		t.Fatalf("Bech32Encode fail: %v", err)
+	}
+	if _, data, _, err := Bech32Decode(encoded); err != nil || common.BytesToAddress(data) != address {
+		t.Fatalf("expected: %v, got: %x (%v)", address, data, err)
+	}
+
+	for _, input := range []string{"A1G7SGD8", "a12uel5l1", "aB12UEL5L", "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx"} {
+		if _, _, _, err := Bech32Decode(input); err == nil {
+			t.Fatalf("expected error for %v", input)
+		}
+	}
+}
+
+func TestCID(t *testing.T) {
+	var v0 = "QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR"
+	var v1 = "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+
+	converted, err := CIDv0ToV1(v0)
+	if err != nil || converted != v1 {
+		t.Fatalf("expected: %v, got: %v (%v)", v1, converted, err)
+	}
+	converted, err = CIDv1ToV0(v1)
+	if err != nil || converted != v0 {
+		t.Fatalf("expected: %v, got: %v (%v)", v0, converted, err)
+	}
+}
+
+func TestParseDataURI(t *testing.T) {
+	tests := []struct {
+		uri          string
+		expectedType string
+		expectedData string
+	}{
+		{"data:application/json;base64,eyJuYW1lIjoiIzEifQ==", "application/json", `{"name":"#1"}`},
+		{"data:application/json;utf8,{\"name\":\"%231\"}", "application/json;utf8", `{"name":"#1"}`},
+		{"data:,a+b", "text/plain;charset=US-ASCII", "a+b"},
+		{"data:image/svg+xml;base64,PHN2Zz48L3N2Zz4", "image/svg+xml", "<svg></svg>"},
+	}
+
+	for i, test := range tests {
+		mediaType, data, err := ParseDataURI(test.uri)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if mediaType != test.expectedType || string(data) != test.expectedData {
+			t.Fatalf("test %d: expected: %v %v, got: %v %s", i, test.expectedType, test.expectedData, mediaType, data)
+		}
+	}
+
+	for _, uri := range []string{"ipfs://QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR", "data:application/json;base64"} {
+		if _, _, err := ParseDataURI(uri); err == nil {
+			t.Fatalf("expected error for %v", uri)
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// EnsRegistry is the address of ENS registry, it's same in mainnet and testnets.
+var EnsRegistry = common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e")
+
+// EnsRegistrarControllers are the addresses of ETHRegistrarController, which registers and renews .eth names.
+var EnsRegistrarControllers = map[uint64]common.Address{
+	1:        common.HexToAddress("0x253553366Da8546fC250F225fe3d25d0C782303b"),
+	11155111: common.HexToAddress("0xFED6a969AaA60E4961FCD3EBF1A2e8913ac65B72"),
+}
+
+// EnsGracePeriod is the period after expiry of .eth name, during which only the owner can renew it.
+const EnsGracePeriod = 90 * 24 * time.Hour
+
+// status of .eth name returned by EnsExpiryStatus
+const (
+	EnsStatusActive  = "active"
+	EnsStatusGrace   = "grace period"
+	EnsStatusExpired = "expired"
+)
+
+// EnsNormalize lowercases name and checks it has no empty label. Full ENSIP-15 normalization (e.g. emoji and
+// confusable characters) is not supported.
+func EnsNormalize(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return "", fmt.Errorf("empty ens name")
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return "", fmt.Errorf("invalid ens name %v, empty label", name)
+		}
+	}
+	return name, nil
+}
+
+// EnsLabelhash returns keccak256 of label, it's the token id of .eth name in BaseRegistrar.
+func EnsLabelhash(label string) common.Hash {
+	return crypto.Keccak256Hash([]byte(label))
+}
+
+// EnsNamehash returns the node of name.
+// See: https://docs.ens.domains/ensip/1
+func EnsNamehash(name string) common.Hash {
+	var node common.Hash
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		node = crypto.Keccak256Hash(node.Bytes(), EnsLabelhash(labels[i]).Bytes())
+	}
+	return node
+}
+
+// EnsReverseName returns the reverse name of address, i.e. <lowercase hex address>.addr.reverse
+func EnsReverseName(address common.Address) string {
+	return strings.ToLower(address.Hex()[2:]) + ".addr.reverse"
+}
+
+// EnsOwner returns the owner of name in ENS registry. It's NameWrapper if the name is wrapped.
+func EnsOwner(ctx context.Context, client *ethclient.Client, name string) (common.Address, error) {
+	values, err := CallAndUnpack(ctx, client, EnsRegistry, "function owner(bytes32) returns (address)", []string{EnsNamehash(name).Hex()})
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+// EnsResolver returns the resolver of name, error is returned if the name has no resolver.
+func EnsResolver(ctx context.Context, client *ethclient.Client, name string) (common.Address, error) {
+	resolver, err := ensResolver(ctx, client, name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if resolver == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("%v has no resolver", name)
+	}
+	return resolver, nil
+}
+
+// ensResolver returns the resolver of name, zero address is returned if the name has no resolver.
+func ensResolver(ctx context.Context, client *ethclient.Client, name string) (common.Address, error) {
+	values, err := CallAndUnpack(ctx, client, EnsRegistry, "function resolver(bytes32) returns (address)", []string{EnsNamehash(name).Hex()})
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+// EnsResolve returns the eth address of name.
+func EnsResolve(ctx context.Context, client *ethclient.Client, name string) (common.Address, error) {
+	resolver, err := EnsResolver(ctx, client, name)
+	if err != nil {
+		return common.Address{}, err
+	}
+	values, err := CallAndUnpack(ctx, client, resolver, "function addr(bytes32) returns (address)", []string{EnsNamehash(name).Hex()})
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+// EnsText returns the text record key of name.
+func EnsText(ctx context.Context, client *ethclient.Client, name string, key string) (string, error) {
+	resolver, err := EnsResolver(ctx, client, name)
+	if err != nil {
+		return "", err
+	}
+	values, err := CallAndUnpack(ctx, client, resolver, "function text(bytes32,string) returns (string)", []string{EnsNamehash(name).Hex(), key})
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+// EnsLookup returns the primary name (reverse record) of address, empty string is returned if it has no primary
+// name. The name is not verified, caller should check it resolves to address.
+func EnsLookup(ctx context.Context, client *ethclient.Client, address common.Address) (string, error) {
+	reverseName := EnsReverseName(address)
+	resolver, err := ensResolver(ctx, client, reverseName)
+	if err != nil || resolver == (common.Address{}) {
+		return "", err
+	}
+	values, err := CallAndUnpack(ctx, client, resolver, "function name(bytes32) returns (string)", []string{EnsNamehash(reverseName).Hex()})
+	if err != nil {
+		return "", err
+	}
+	return values[0].(string), nil
+}
+
+// EnsReverseRegistrar returns the address of ReverseRegistrar, which is the owner of addr.reverse.
+func EnsReverseRegistrar(ctx context.Context, client *ethclient.Client) (common.Address, error) {
+	return EnsOwner(ctx, client, "addr.reverse")
+}
+
+// EnsBaseRegistrar returns the address of BaseRegistrar, which is the owner of eth.
+func EnsBaseRegistrar(ctx context.Context, client *ethclient.Client) (common.Address, error) {
+	return EnsOwner(ctx, client, "eth")
+}
+
+// EnsExpiry returns the expiry time of .eth name (2LD only, e.g. vitalik.eth), zero time is returned if it's
+// never registered.
+func EnsExpiry(ctx context.Context, client *ethclient.Client, name string) (time.Time, error) {
+	label, err := EnsEthLabel(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	baseRegistrar, err := EnsBaseRegistrar(ctx, client)
+	if err != nil {
+		return time.Time{}, err
+	}
+	values, err := CallAndUnpack(ctx, client, baseRegistrar, "function nameExpires(uint256) returns (uint256)", []string{EnsLabelhash(label).Big().String()})
+	if err != nil {
+		return time.Time{}, err
+	}
+	expires := values[0].(*big.Int).Int64()
+	if expires == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(expires, 0), nil
+}
+
+// EnsExpiryStatus returns EnsStatusActive, EnsStatusGrace or EnsStatusExpired of name expires at expires.
+func EnsExpiryStatus(expires time.Time, now time.Time) string {
+	if now.Before(expires) {
+		return EnsStatusActive
+	}
+	if now.Before(expires.Add(EnsGracePeriod)) {
+		return EnsStatusGrace
+	}
+	return EnsStatusExpired
+}
+
+// EnsEthLabel returns the label of .eth 2LD name, e.g. vitalik of vitalik.eth
+func EnsEthLabel(name string) (string, error) {
+	label := strings.TrimSuffix(name, ".eth")
+	if !strings.HasSuffix(name, ".eth") || label == "" || strings.Contains(label, ".") {
+		return "", fmt.Errorf("%v is not a .eth second-level name", name)
+	}
+	return label, nil
+}
+
+// EnsRentPrice returns the price (base and premium) in wei of renewing .eth name for duration.
+func EnsRentPrice(ctx context.Context, client *ethclient.Client, controller common.Address, name string, duration time.Duration) (*big.Int, error) {
+	label, err := EnsEthLabel(name)
+	if err != nil {
+		return nil, err
+	}
+	// the return value is struct Price { uint256 base; uint256 premium; }
+	values, err := CallAndUnpack(ctx, client, controller, "function rentPrice(string,uint256) returns (uint256,uint256)", []string{label, fmt.Sprint(int64(duration.Seconds()))})
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Add(values[0].(*big.Int), values[1].(*big.Int)), nil
+}
+
+// EnsSetAddrData builds input data of resolver setAddr, which sets eth address of name.
+func EnsSetAddrData(name string, address common.Address) ([]byte, error) {
+	return BuildTxInputData("setAddr(bytes32,address)", []string{EnsNamehash(name).Hex(), address.Hex()})
+}
+
+// EnsSetTextData builds input data of resolver setText, which sets text record key of name.
+func EnsSetTextData(name string, key string, value string) ([]byte, error) {
+	return BuildTxInputData("setText(bytes32,string,string)", []string{EnsNamehash(name).Hex(), key, value})
+}
+
+// EnsSetNameData builds input data of ReverseRegistrar setName, which sets primary name of sender.
+func EnsSetNameData(name string) ([]byte, error) {
+	return BuildTxInputData("setName(string)", []string{name})
+}
+
+// EnsRenewData builds input data of ETHRegistrarController renew, the tx must pay the rent price.
+func EnsRenewData(name string, duration time.Duration) ([]byte, error) {
+	label, err := EnsEthLabel(name)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTxInputData("renew(string,uint256)", []string{label, fmt.Sprint(int64(duration.Seconds()))})
+}
+
+// ensAvatarNftRE matches NFT avatar record, e.g. eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1000
+var ensAvatarNftRE = regexp.MustCompile(`^eip155:(\d+)/(erc721|erc1155):(0x[0-9a-fA-F]{40})/(\d+)$`)
+
+// parseEnsAvatarNft parses NFT avatar record, ok is false if avatar is not a NFT.
+func parseEnsAvatarNft(avatar string) (chainId uint64, standard string, contract common.Address, tokenId *big.Int, ok bool) {
+	m := ensAvatarNftRE.FindStringSubmatch(strings.ToLower(avatar))
+	if m == nil {
+		return 0, "", common.Address{}, nil, false
+	}
+	chainId, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0, "", common.Address{}, nil, false
+	}
+	tokenId, _ = new(big.Int).SetString(m[4], 10)
+	return chainId, m[2], common.HexToAddress(m[3]), tokenId, true
+}
+
+// EnsAvatarURL returns URL (http(s) or data URI) of image of avatar text record. NFT avatar is resolved by image in
+// metadata of the NFT, and it must be owned by owner, ipfs:// URI is rewritten to URL of ipfsGateway.
+// See: https://docs.ens.domains/ensip/12
+func EnsAvatarURL(ctx context.Context, client *ethclient.Client, avatar string, owner common.Address, ipfsGateway string) (string, error) {
+	nftChainId, standard, contract, tokenId, ok := parseEnsAvatarNft(avatar)
+	if !ok {
+		return TokenURIToHttp(avatar, ipfsGateway), nil
+	}
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return "", err
+	}
+	if chainId.Uint64() != nftChainId {
+		return "", fmt.Errorf("avatar NFT %v is on chain %v, but chain id of current network is %v", avatar, nftChainId, chainId)
+	}
+	if standard == "erc721" {
+		values, err := CallAndUnpack(ctx, client, contract, "function ownerOf(uint256) returns (address)", []string{tokenId.String()})
+		if err != nil {
+			return "", err
+		}
+		if values[0].(common.Address) != owner {
+			return "", fmt.Errorf("avatar NFT %v is not owned by %v", avatar, owner.Hex())
+		}
+	} else {
+		values, err := CallAndUnpack(ctx, client, contract, "function balanceOf(address,uint256) returns (uint256)", []string{owner.Hex(), tokenId.String()})
+		if err != nil {
+			return "", err
+		}
+		if values[0].(*big.Int).Sign() == 0 {
+			return "", fmt.Errorf("avatar NFT %v is not owned by %v", avatar, owner.Hex())
+		}
+	}
+
+	uri, err := NftTokenURI(ctx, client, contract, tokenId)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := FetchNftMetadata(ctx, uri, ipfsGateway)
+	if err != nil {
+		return "", err
+	}
+	var fields struct {
+		Image     string `json:"image"`
+		ImageUrl  string `json:"image_url"`
+		ImageData string `json:"image_data"`
+	}
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return "", fmt.Errorf("invalid metadata of avatar NFT %v: %w", avatar, err)
+	}
+	switch {
+	case fields.Image != "":
+		return TokenURIToHttp(fields.Image, ipfsGateway), nil
+	case fields.ImageUrl != "":
+		return TokenURIToHttp(fields.ImageUrl, ipfsGateway), nil
+	case fields.ImageData != "":
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(fields.ImageData)), nil
+	}
+	return "", fmt.Errorf("no image in metadata of avatar NFT %v", avatar)
+}
@@ -0,0 +1,130 @@
+package ethutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestEnsNamehash(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"", "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"eth", "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae"},
+		{"foo.eth", "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"},
+	}
+
+	for i, test := range tests {
+		if got := EnsNamehash(test.name).Hex(); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
+
+func TestEnsNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string // empty means error
+	}{
+		{"Vitalik.ETH", "vitalik.eth"},
+		{" foo.eth ", "foo.eth"},
+		{"foo..eth", ""},
+		{"", ""},
+	}
+
+	for i, test := range tests {
+		got, err := EnsNormalize(test.name)
+		if (err != nil) != (test.expected == "") || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+	}
+}
+
+func TestEnsEthLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string // empty means error
+	}{
+		{"vitalik.eth", "vitalik"},
+		{"sub.vitalik.eth", ""},
+		{".eth", ""},
+		{"vitalik.xyz", ""},
+	}
+
+	for i, test := range tests {
+		got, err := EnsEthLabel(test.name)
+		if (err != nil) != (test.expected == "") || got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.expected, got, err)
+		}
+	}
+}
+
+func TestEnsExpiryStatus(t *testing.T) {
+	expires := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		now      time.Time
+		expected string
+	}{
+		{expires.Add(-time.Hour), EnsStatusActive},
+		{expires, EnsStatusGrace},
+		{expires.Add(EnsGracePeriod - time.Second), EnsStatusGrace},
+		{expires.Add(EnsGracePeriod), EnsStatusExpired},
+	}
+
+	for i, test := range tests {
+		if got := EnsExpiryStatus(expires, test.now); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
+
+func TestEnsSetAddrData(t *testing.T) {
+	address := common.HexToAddress("0x24f8209EC5f56A07C94e834627F0651c19ACa0ac")
+	data, err := EnsSetAddrData("foo.eth", address)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "0xd5fa2b00" + "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f" +
+		"000000000000000000000000" + strings.ToLower(address.Hex()[2:])
+	if got := hexutil.Encode(data); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+
+	if got := EnsReverseName(address); got != "24f8209ec5f56a07c94e834627f0651c19aca0ac.addr.reverse" {
+		t.Fatalf("expected: %v, got: %v", "24f8209ec5f56a07c94e834627f0651c19aca0ac.addr.reverse", got)
+	}
+}
+
+func TestParseEnsAvatarNft(t *testing.T) {
+	tests := []struct {
+		avatar   string
+		ok       bool
+		chainId  uint64
+		standard string
+		contract string
+		tokenId  string
+	}{
+		{"eip155:1/erc721:0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB/1000", true, 1, "erc721", "0xb47e3cd837dDF8e4c57F05d70Ab865de6e193BBB", "1000"},
+		{"eip155:1/erc1155:0x495f947276749ce646f68ac8c248420045cb7b5e/8112316025873927737505937898915153732580103913704334048512380490797008551937", true, 1, "erc1155", "0x495f947276749Ce646f68AC8c248420045cb7b5e", "8112316025873927737505937898915153732580103913704334048512380490797008551937"},
+		{"https://example.com/avatar.png", false, 0, "", "", ""},
+		{"ipfs://QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR", false, 0, "", "", ""},
+	}
+
+	for i, test := range tests {
+		chainId, standard, contract, tokenId, ok := parseEnsAvatarNft(test.avatar)
+		if ok != test.ok {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.ok, ok)
+		}
+		if !ok {
+			continue
+		}
+		if chainId != test.chainId || standard != test.standard || contract.Hex() != test.contract || tokenId.String() != test.tokenId {
+			t.Fatalf("test %d: expected: %v %v %v %v, got: %v %v %v %v", i, test.chainId, test.standard, test.contract, test.tokenId, chainId, standard, contract.Hex(), tokenId)
+		}
+	}
+}
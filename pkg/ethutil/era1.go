@@ -0,0 +1,191 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// Era1MaxBlocks is the max number of blocks in an era1 file, era1 files start at multiples of it.
+const Era1MaxBlocks = 8192
+
+// e2store entry types of era1, see https://github.com/eth-clients/e2store-format-specs
+const (
+	era1TypeVersion            = 0x3265
+	era1TypeCompressedHeader   = 0x03
+	era1TypeCompressedBody     = 0x04
+	era1TypeCompressedReceipts = 0x05
+	era1TypeTotalDifficulty    = 0x06
+	era1TypeAccumulator        = 0x07
+	era1TypeBlockIndex         = 0x3266
+)
+
+// Era1Writer writes pre-merge blocks in era1 format: Version | block-tuple* | Accumulator | BlockIndex, where
+// block-tuple is snappy compressed header, body and receipts followed by total difficulty.
+type Era1Writer struct {
+	w        io.Writer
+	written  uint64
+	startNum uint64
+	offsets  []uint64 // offsets of headers
+	hashes   []common.Hash
+	tds      []*big.Int
+}
+
+// NewEra1Writer writes version entry to w and returns an Era1Writer of blocks starting at startNum.
+func NewEra1Writer(w io.Writer, startNum uint64) (*Era1Writer, error) {
+	writer := &Era1Writer{w: w, startNum: startNum}
+	if err := writer.writeEntry(era1TypeVersion, nil); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// writeEntry writes e2store entry: type (2 bytes), length (4 bytes), reserved (2 bytes) and data, little endian.
+func (e *Era1Writer) writeEntry(typ uint16, data []byte) error {
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint16(header, typ)
+	binary.LittleEndian.PutUint32(header[2:], uint32(len(data)))
+	if _, err := e.w.Write(append(header, data...)); err != nil {
+		return err
+	}
+	e.written += uint64(len(header) + len(data))
+	return nil
+}
+
+// writeCompressed writes rlp of v compressed by snappy framed format.
+func (e *Era1Writer) writeCompressed(typ uint16, v any) error {
+	encoded, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	sw := snappy.NewBufferedWriter(&buf)
+	if _, err := sw.Write(encoded); err != nil {
+		return err
+	}
+	if err := sw.Close(); err != nil {
+		return err
+	}
+	return e.writeEntry(typ, buf.Bytes())
+}
+
+// Add writes block with its receipts and total difficulty, blocks must be added in order.
+func (e *Era1Writer) Add(block *types.Block, receipts types.Receipts, td *big.Int) error {
+	if expected := e.startNum + uint64(len(e.offsets)); block.NumberU64() != expected {
+		return fmt.Errorf("block %v is added, expect block %v", block.NumberU64(), expected)
+	}
+	if len(e.offsets) >= Era1MaxBlocks {
+		return fmt.Errorf("era1 file can't contain more than %v blocks", Era1MaxBlocks)
+	}
+	if block.Difficulty().Sign() == 0 {
+		return fmt.Errorf("block %v is a post-merge block, era1 only archives pre-merge blocks", block.NumberU64())
+	}
+	e.offsets = append(e.offsets, e.written)
+	e.hashes = append(e.hashes, block.Hash())
+	e.tds = append(e.tds, td)
+	if err := e.writeCompressed(era1TypeCompressedHeader, block.Header()); err != nil {
+		return err
+	}
+	if err := e.writeCompressed(era1TypeCompressedBody, block.Body()); err != nil {
+		return err
+	}
+	if err := e.writeCompressed(era1TypeCompressedReceipts, receipts); err != nil {
+		return err
+	}
+	return e.writeEntry(era1TypeTotalDifficulty, uint256LittleEndian(td))
+}
+
+// Finalize writes accumulator and block index, and returns the accumulator root.
+func (e *Era1Writer) Finalize() (common.Hash, error) {
+	if len(e.offsets) == 0 {
+		return common.Hash{}, fmt.Errorf("no block is added")
+	}
+	root := Era1Accumulator(e.hashes, e.tds)
+	if err := e.writeEntry(era1TypeAccumulator, root[:]); err != nil {
+		return common.Hash{}, err
+	}
+	// starting-number | index* | count, each index is the offset of block relative to the beginning of index entry
+	base := e.written
+	index := make([]byte, 16+8*len(e.offsets))
+	binary.LittleEndian.PutUint64(index, e.startNum)
+	for i, offset := range e.offsets {
+		binary.LittleEndian.PutUint64(index[8+8*i:], uint64(int64(offset)-int64(base)))
+	}
+	binary.LittleEndian.PutUint64(index[8+8*len(e.offsets):], uint64(len(e.offsets)))
+	return root, e.writeEntry(era1TypeBlockIndex, index)
+}
+
+// uint256LittleEndian encodes x as 32 bytes little endian, i.e. ssz uint256.
+func uint256LittleEndian(x *big.Int) []byte {
+	encoded := common.LeftPadBytes(x.Bytes(), 32)
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return encoded
+}
+
+// Era1Accumulator returns ssz hash tree root of List[HeaderRecord, 8192], HeaderRecord is the block hash and total
+// difficulty of block.
+func Era1Accumulator(hashes []common.Hash, tds []*big.Int) common.Hash {
+	nodes := make([][32]byte, Era1MaxBlocks)
+	for i := range hashes {
+		nodes[i] = sha256.Sum256(append(hashes[i].Bytes(), uint256LittleEndian(tds[i])...))
+	}
+	// the unused leaves are zero, merkleize up to the root of 8192 leaves
+	for len(nodes) > 1 {
+		parents := make([][32]byte, len(nodes)/2)
+		for i := range parents {
+			parents[i] = sha256.Sum256(append(nodes[2*i][:], nodes[2*i+1][:]...))
+		}
+		nodes = parents
+	}
+	return sha256.Sum256(append(nodes[0][:], uint256LittleEndian(big.NewInt(int64(len(hashes))))...))
+}
+
+// Era1FileName returns the conventional file name of era1 file: <network>-<era number>-<short accumulator root>.era1.
+func Era1FileName(network string, startNum uint64, root common.Hash) string {
+	return fmt.Sprintf("%s-%05d-%s.era1", network, startNum/Era1MaxBlocks, common.Bytes2Hex(root[:4]))
+}
+
+// BlockReceipts returns receipts of block by eth_getBlockReceipts, or by eth_getTransactionReceipt of each tx if node
+// doesn't support eth_getBlockReceipts.
+func BlockReceipts(ctx context.Context, client *Client, block *types.Block) (types.Receipts, error) {
+	var receipts types.Receipts
+	err := client.RpcClient.CallContext(ctx, &receipts, "eth_getBlockReceipts", hexutil.EncodeBig(block.Number()))
+	if err == nil && len(receipts) == len(block.Transactions()) {
+		return receipts, nil
+	}
+	receipts = make(types.Receipts, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		receipt, err := client.EthClient.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("TransactionReceipt of %v fail: %w", tx.Hash().Hex(), err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return receipts, nil
+}
+
+// BlockTotalDifficulty returns the totalDifficulty field of block returned by eth_getBlockByNumber.
+func BlockTotalDifficulty(ctx context.Context, client *Client, blockNumber *big.Int) (*big.Int, error) {
+	var block struct {
+		TotalDifficulty *hexutil.Big `json:"totalDifficulty"`
+	}
+	if err := client.RpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(blockNumber), false); err != nil {
+		return nil, fmt.Errorf("eth_getBlockByNumber fail: %w", err)
+	}
+	if block.TotalDifficulty == nil {
+		return nil, fmt.Errorf("totalDifficulty of block %v is not returned by node", blockNumber)
+	}
+	return block.TotalDifficulty.ToInt(), nil
+}
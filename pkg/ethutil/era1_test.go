@@ -0,0 +1,114 @@
+package ethutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+type e2storeEntry struct {
+	offset int
+	typ    uint16
+	data   []byte
+}
+
+// readE2store splits e2store file into entries.
+func readE2store(t *testing.T, content []byte) []e2storeEntry {
+	var entries []e2storeEntry
+	for offset := 0; offset < len(content); {
+		length := int(binary.LittleEndian.Uint32(content[offset+2:]))
+		entries = append(entries, e2storeEntry{offset, binary.LittleEndian.Uint16(content[offset:]), content[offset+8 : offset+8+length]})
+		offset += 8 + length
+	}
+	return entries
+}
+
+func TestEra1Writer(t *testing.T) {
+	var blocks []*types.Block
+	var tds []*big.Int
+	parent := common.Hash{}
+	for number := int64(8192); number < 8195; number++ {
+		header := &types.Header{ParentHash: parent, Number: big.NewInt(number), Difficulty: big.NewInt(1000), GasLimit: 5000}
+		block := types.NewBlockWithHeader(header)
+		blocks = append(blocks, block)
+		tds = append(tds, big.NewInt(1000*(number+1)))
+		parent = block.Hash()
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewEra1Writer(&buf, 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, block := range blocks {
+		if err := writer.Add(block, types.Receipts{}, tds[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	root, err := writer.Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != Era1Accumulator([]common.Hash{blocks[0].Hash(), blocks[1].Hash(), blocks[2].Hash()}, tds) {
+		t.Errorf("Finalize() root = %v, mismatch accumulator", root.Hex())
+	}
+
+	entries := readE2store(t, buf.Bytes())
+	if len(entries) != 1+4*len(blocks)+2 {
+		t.Fatalf("got %v entries", len(entries))
+	}
+	if entries[0].typ != era1TypeVersion || entries[len(entries)-2].typ != era1TypeAccumulator || entries[len(entries)-1].typ != era1TypeBlockIndex {
+		t.Fatalf("unexpected entry types")
+	}
+	index := entries[len(entries)-1]
+	if start := binary.LittleEndian.Uint64(index.data); start != 8192 {
+		t.Errorf("starting number = %v", start)
+	}
+	if count := binary.LittleEndian.Uint64(index.data[len(index.data)-8:]); count != 3 {
+		t.Errorf("count = %v", count)
+	}
+	for i, block := range blocks {
+		offset := index.offset + int(int64(binary.LittleEndian.Uint64(index.data[8+8*i:])))
+		entry := entries[1+4*i]
+		if entry.offset != offset || entry.typ != era1TypeCompressedHeader {
+			t.Fatalf("index of block %v points to %v, header entry is at %v", i, offset, entry.offset)
+		}
+		encoded, err := io.ReadAll(snappy.NewReader(bytes.NewReader(entry.data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var header types.Header
+		if err := rlp.DecodeBytes(encoded, &header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Hash() != block.Hash() {
+			t.Errorf("header of block %v mismatch", i)
+		}
+		if td := entries[4+4*i].data; !bytes.Equal(td, uint256LittleEndian(tds[i])) || td[0] == 0 {
+			t.Errorf("total difficulty of block %v = %x", i, td)
+		}
+	}
+
+	if err := writer.Add(blocks[0], nil, tds[0]); err == nil {
+		t.Errorf("Add() out of order block, expect error")
+	}
+	postMerge := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(8192), Difficulty: big.NewInt(0)})
+	writer, _ = NewEra1Writer(io.Discard, 8192)
+	if err := writer.Add(postMerge, nil, big.NewInt(1)); err == nil {
+		t.Errorf("Add() post-merge block, expect error")
+	}
+}
+
+func TestEra1FileName(t *testing.T) {
+	root := common.HexToHash("0x5ec1ffb8c3b146f42606c74ced973dc16ec5a107c0345858c343fc94780b4218")
+	if got := Era1FileName("mainnet", 8192*3, root); got != "mainnet-00003-5ec1ffb8.era1" {
+		t.Errorf("Era1FileName() = %v", got)
+	}
+}
@@ -0,0 +1,156 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// KnownInterface is a standard interface detected by DetectInterfaces. InterfaceId is empty if the standard doesn't
+// register ERC-165 interface id (e.g. ERC-20, ERC-4626), which is detected by heuristics.
+type KnownInterface struct {
+	Name        string
+	InterfaceId string
+}
+
+// KnownInterfaces are the interfaces probed by DetectInterfaces.
+var KnownInterfaces = []KnownInterface{
+	{Name: "ERC-165", InterfaceId: "0x01ffc9a7"},
+	{Name: "ERC-20"},
+	{Name: "ERC-721", InterfaceId: "0x80ac58cd"},
+	{Name: "ERC-721 Metadata", InterfaceId: "0x5b5e139f"},
+	{Name: "ERC-721 Enumerable", InterfaceId: "0x780e9d63"},
+	{Name: "ERC-1155", InterfaceId: "0xd9b67a26"},
+	{Name: "ERC-1155 Metadata URI", InterfaceId: "0x0e89341c"},
+	{Name: "ERC-2981", InterfaceId: "0x2a55205a"},
+	{Name: "ERC-4906", InterfaceId: "0x49064906"},
+	{Name: "ERC-4626"},
+}
+
+// isCallReverted returns true if err of eth_call is a revert, rather than failure of node or network.
+func isCallReverted(err error) bool {
+	var dataErr rpc.DataError
+	return errors.As(err, &dataErr) || strings.Contains(err.Error(), "execution reverted")
+}
+
+// callSucceeds calls contract with data and returns true if it doesn't revert and returns at least minLength bytes.
+func callSucceeds(ctx context.Context, client *ethclient.Client, contract common.Address, data []byte, minLength int, blockNumber *big.Int) (bool, error) {
+	output, err := Call(ctx, client, contract, data, blockNumber)
+	if err != nil {
+		if isCallReverted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(output) >= minLength, nil
+}
+
+// supportsInterfaceRaw returns the result of supportsInterface(interfaceId), false if it reverts or the return data
+// is not a bool.
+func supportsInterfaceRaw(ctx context.Context, client *ethclient.Client, contract common.Address, interfaceId string, blockNumber *big.Int) (bool, error) {
+	data, err := BuildTxInputData("supportsInterface(bytes4)", []string{interfaceId})
+	if err != nil {
+		return false, err
+	}
+	output, err := Call(ctx, client, contract, data, blockNumber)
+	if err != nil {
+		if isCallReverted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(output) == 32 && new(big.Int).SetBytes(output).Cmp(big.NewInt(1)) == 0, nil
+}
+
+// SupportsInterface returns true if contract implements ERC-165 and supports interfaceId (e.g. "0x80ac58cd"), by the
+// detection procedure of ERC-165: supportsInterface(0x01ffc9a7) returns true and supportsInterface(0xffffffff)
+// returns false, so contracts whose fallback returns true are not reported. nil blockNumber means latest block.
+func SupportsInterface(ctx context.Context, client *ethclient.Client, contract common.Address, interfaceId string, blockNumber *big.Int) (bool, error) {
+	if supported, err := supportsInterfaceRaw(ctx, client, contract, "0x01ffc9a7", blockNumber); err != nil || !supported {
+		return false, err
+	}
+	if supported, err := supportsInterfaceRaw(ctx, client, contract, "0xffffffff", blockNumber); err != nil || supported {
+		return false, err
+	}
+	if strings.EqualFold(interfaceId, "0x01ffc9a7") {
+		return true, nil
+	}
+	return supportsInterfaceRaw(ctx, client, contract, interfaceId, blockNumber)
+}
+
+// looksLikeErc20 returns true if totalSupply(), balanceOf(address) and allowance(address,address) succeed, ERC-20
+// has no ERC-165 interface id. ERC-721 contracts also have totalSupply and balanceOf, but not allowance.
+func looksLikeErc20(ctx context.Context, client *ethclient.Client, contract common.Address, blockNumber *big.Int) (bool, error) {
+	zero := common.Address{}.Hex()
+	for _, call := range []struct {
+		sig  string
+		args []string
+	}{
+		{"totalSupply()", nil},
+		{"balanceOf(address)", []string{zero}},
+		{"allowance(address,address)", []string{zero, zero}},
+	} {
+		data, err := BuildTxInputData(call.sig, call.args)
+		if err != nil {
+			return false, err
+		}
+		if ok, err := callSucceeds(ctx, client, contract, data, 32, blockNumber); err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// looksLikeErc4626 returns true if asset() returns an address and convertToShares(uint256) succeeds, ERC-4626 has
+// no ERC-165 interface id.
+func looksLikeErc4626(ctx context.Context, client *ethclient.Client, contract common.Address, blockNumber *big.Int) (bool, error) {
+	output, err := Call(ctx, client, contract, hexutil.MustDecode("0x38d52e0f"), blockNumber) // asset()
+	if err != nil {
+		if isCallReverted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if len(output) != 32 || common.BytesToAddress(output) == (common.Address{}) || new(big.Int).SetBytes(output[:12]).Sign() != 0 {
+		return false, nil
+	}
+	data, err := BuildTxInputData("convertToShares(uint256)", []string{"1"})
+	if err != nil {
+		return false, err
+	}
+	return callSucceeds(ctx, client, contract, data, 32, blockNumber)
+}
+
+// DetectInterfaces returns the KnownInterfaces implemented by contract, ERC-20 and ERC-4626 are detected by
+// heuristics as they have no ERC-165 interface id. nil blockNumber means latest block.
+func DetectInterfaces(ctx context.Context, client *ethclient.Client, contract common.Address, blockNumber *big.Int) ([]KnownInterface, error) {
+	erc165, err := SupportsInterface(ctx, client, contract, "0x01ffc9a7", blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var supported []KnownInterface
+	for _, known := range KnownInterfaces {
+		var ok bool
+		switch {
+		case known.Name == "ERC-20":
+			ok, err = looksLikeErc20(ctx, client, contract, blockNumber)
+		case known.Name == "ERC-4626":
+			ok, err = looksLikeErc4626(ctx, client, contract, blockNumber)
+		case erc165:
+			ok, err = supportsInterfaceRaw(ctx, client, contract, known.InterfaceId, blockNumber)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			supported = append(supported, known)
+		}
+	}
+	return supported, nil
+}
@@ -0,0 +1,111 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// newContractServer returns node answering eth_call with results keyed by call data, other calls revert.
+func newContractServer(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage   `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		var call CallArgs
+		if err := json.Unmarshal(req.Params[0], &call); err != nil {
+			t.Error(err)
+		}
+		if result, ok := results[call.Data.String()]; ok {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%v"}`, req.Id, result)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":3,"message":"execution reverted","data":"0x"}}`, req.Id)
+	}))
+}
+
+// callData returns hex of call data of sig with args.
+func callData(t *testing.T, sig string, args ...string) string {
+	data, err := BuildTxInputData(sig, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hexutil.Encode(data)
+}
+
+func TestDetectInterfaces(t *testing.T) {
+	zero := common.Address{}.Hex()
+	erc165 := func(supported ...string) map[string]string {
+		results := map[string]string{
+			callData(t, "supportsInterface(bytes4)", "0x01ffc9a7"): words(1),
+			callData(t, "supportsInterface(bytes4)", "0xffffffff"): words(0),
+		}
+		for _, id := range []string{"0x80ac58cd", "0x5b5e139f", "0x780e9d63", "0xd9b67a26", "0x0e89341c", "0x2a55205a", "0x49064906"} {
+			results[callData(t, "supportsInterface(bytes4)", id)] = words(0)
+		}
+		for _, id := range supported {
+			results[callData(t, "supportsInterface(bytes4)", id)] = words(1)
+		}
+		return results
+	}
+	erc20 := map[string]string{
+		callData(t, "totalSupply()"):                          words(100),
+		callData(t, "balanceOf(address)", zero):               words(0),
+		callData(t, "allowance(address,address)", zero, zero): words(0),
+	}
+	vault := map[string]string{
+		callData(t, "asset()"):                       words(0x1234),
+		callData(t, "convertToShares(uint256)", "1"): words(1),
+	}
+	for k, v := range erc20 {
+		vault[k] = v
+	}
+	fallbackTrue := erc165()
+	fallbackTrue[callData(t, "supportsInterface(bytes4)", "0xffffffff")] = words(1)
+
+	tests := []struct {
+		name    string
+		results map[string]string
+		want    []string
+	}{
+		{"erc721", erc165("0x80ac58cd", "0x5b5e139f", "0x2a55205a"), []string{"ERC-165", "ERC-721", "ERC-721 Metadata", "ERC-2981"}},
+		{"erc1155", erc165("0xd9b67a26", "0x0e89341c"), []string{"ERC-165", "ERC-1155", "ERC-1155 Metadata URI"}},
+		{"erc20", erc20, []string{"ERC-20"}},
+		{"erc4626", vault, []string{"ERC-20", "ERC-4626"}},
+		{"fallback returns true", fallbackTrue, nil},
+		{"none", map[string]string{}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newContractServer(t, tt.results)
+			defer server.Close()
+			client, err := Dial(context.Background(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			supported, err := DetectInterfaces(context.Background(), client.EthClient, common.HexToAddress("0x01"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, known := range supported {
+				got = append(got, known.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DetectInterfaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
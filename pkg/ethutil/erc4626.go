@@ -0,0 +1,63 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// VaultInfo is the information of ERC-4626 tokenized vault and its underlying asset.
+type VaultInfo struct {
+	Asset         common.Address
+	AssetSymbol   string
+	AssetDecimals uint8
+	ShareSymbol   string
+	ShareDecimals uint8
+	TotalAssets   *big.Int
+	TotalSupply   *big.Int
+}
+
+// QueryVaultInfo queries asset, totals and decimals of ERC-4626 vault. Symbols are empty if they're not available,
+// e.g. MKR returns bytes32 symbol.
+func QueryVaultInfo(ctx context.Context, client *ethclient.Client, vault common.Address) (*VaultInfo, error) {
+	asset, err := CallAndUnpack(ctx, client, vault, "function asset() returns (address)", nil)
+	if err != nil {
+		return nil, err
+	}
+	info := &VaultInfo{Asset: asset[0].(common.Address)}
+	for _, call := range []struct {
+		contract common.Address
+		funcDef  string
+		set      func(v any)
+	}{
+		{info.Asset, "function decimals() returns (uint8)", func(v any) { info.AssetDecimals = v.(uint8) }},
+		{vault, "function decimals() returns (uint8)", func(v any) { info.ShareDecimals = v.(uint8) }},
+		{vault, "function totalAssets() returns (uint256)", func(v any) { info.TotalAssets = v.(*big.Int) }},
+		{vault, "function totalSupply() returns (uint256)", func(v any) { info.TotalSupply = v.(*big.Int) }},
+	} {
+		values, err := CallAndUnpack(ctx, client, call.contract, call.funcDef, nil)
+		if err != nil {
+			return nil, err
+		}
+		call.set(values[0])
+	}
+	if values, err := CallAndUnpack(ctx, client, info.Asset, "function symbol() returns (string)", nil); err == nil {
+		info.AssetSymbol = values[0].(string)
+	}
+	if values, err := CallAndUnpack(ctx, client, vault, "function symbol() returns (string)", nil); err == nil {
+		info.ShareSymbol = values[0].(string)
+	}
+	return info, nil
+}
+
+// VaultQuery calls view function of ERC-4626 vault taking one argument and returning uint256, e.g.
+// "convertToShares(uint256)", "previewRedeem(uint256)" or "maxWithdraw(address)".
+func VaultQuery(ctx context.Context, client *ethclient.Client, vault common.Address, funcSig string, arg string) (*big.Int, error) {
+	values, err := CallAndUnpack(ctx, client, vault, "function "+funcSig+" returns (uint256)", []string{arg})
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
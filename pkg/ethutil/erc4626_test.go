@@ -0,0 +1,68 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestQueryVaultInfo(t *testing.T) {
+	// the mock server doesn't distinguish contracts, so asset and vault share decimals and symbol
+	symbol := words(32, 5) + common.Bytes2Hex(common.RightPadBytes([]byte("vUSDC"), 32))
+	vault := map[string]string{
+		callData(t, "asset()"):                        words(0x1234),
+		callData(t, "decimals()"):                     words(6),
+		callData(t, "totalAssets()"):                  words(2000000),
+		callData(t, "totalSupply()"):                  words(1000000),
+		callData(t, "convertToAssets(uint256)", "10"): words(20),
+	}
+	withSymbol := map[string]string{callData(t, "symbol()"): symbol}
+	for k, v := range vault {
+		withSymbol[k] = v
+	}
+
+	tests := []struct {
+		name       string
+		results    map[string]string
+		wantErr    bool
+		wantSymbol string
+	}{
+		{"vault", vault, false, ""},
+		{"vault with symbol", withSymbol, false, "vUSDC"},
+		{"not vault", map[string]string{callData(t, "decimals()"): words(18)}, true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newContractServer(t, tt.results)
+			defer server.Close()
+			client, err := Dial(context.Background(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			info, err := QueryVaultInfo(context.Background(), client.EthClient, common.HexToAddress("0x01"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryVaultInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if info.Asset != common.HexToAddress("0x1234") || info.AssetDecimals != 6 || info.ShareDecimals != 6 ||
+				info.TotalAssets.Cmp(big.NewInt(2000000)) != 0 || info.TotalSupply.Cmp(big.NewInt(1000000)) != 0 {
+				t.Errorf("QueryVaultInfo() = %+v", info)
+			}
+			if info.AssetSymbol != tt.wantSymbol || info.ShareSymbol != tt.wantSymbol {
+				t.Errorf("QueryVaultInfo() symbols = %v %v, want %v", info.AssetSymbol, info.ShareSymbol, tt.wantSymbol)
+			}
+			assets, err := VaultQuery(context.Background(), client.EthClient, common.HexToAddress("0x01"), "convertToAssets(uint256)", "10")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if assets.Cmp(big.NewInt(20)) != 0 {
+				t.Errorf("VaultQuery() = %v, want 20", assets)
+			}
+		})
+	}
+}
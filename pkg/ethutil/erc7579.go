@@ -0,0 +1,125 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC-7579 module types
+const (
+	ModuleTypeValidator = 1
+	ModuleTypeExecutor  = 2
+	ModuleTypeFallback  = 3
+	ModuleTypeHook      = 4
+)
+
+// ModuleTypeNames are the names of ERC-7579 module types
+var ModuleTypeNames = map[uint64]string{
+	ModuleTypeValidator: "validator",
+	ModuleTypeExecutor:  "executor",
+	ModuleTypeFallback:  "fallback",
+	ModuleTypeHook:      "hook",
+}
+
+var (
+	moduleInstalledTopic   = crypto.Keccak256Hash([]byte("ModuleInstalled(uint256,address)"))
+	moduleUninstalledTopic = crypto.Keccak256Hash([]byte("ModuleUninstalled(uint256,address)"))
+)
+
+// Module is a module of ERC-7579 modular account.
+type Module struct {
+	Type    uint64
+	Address common.Address
+	Name    string // empty if module does not implement name()
+	Version string // empty if module does not implement version()
+}
+
+// callString calls funcName() returns (string) of contract, empty string is returned if the call fails.
+func callString(ctx context.Context, client *ethclient.Client, contract common.Address, funcName string) string {
+	funcDefinition := "function " + funcName + "() returns (string)"
+	data, err := BuildTxInputData(funcDefinition, nil)
+	if err != nil {
+		return ""
+	}
+	output, err := Call(ctx, client, contract, data, nil)
+	if err != nil {
+		return ""
+	}
+	returnArgs, err := BuildReturnArgs(funcDefinition)
+	if err != nil {
+		return ""
+	}
+	values, err := returnArgs.Unpack(output)
+	if err != nil || len(values) == 0 {
+		return ""
+	}
+	return values[0].(string)
+}
+
+// GetAccountId returns accountId() of ERC-7579 account, e.g. "biconomy.nexus.1.0.0".
+func GetAccountId(ctx context.Context, client *ethclient.Client, account common.Address) (string, error) {
+	id := callString(ctx, client, account, "accountId")
+	if id == "" {
+		return "", fmt.Errorf("accountId() of %v fail, it may not be an ERC-7579 account", account.Hex())
+	}
+	return id, nil
+}
+
+// IsModuleInstalled calls isModuleInstalled(moduleTypeId, module, "") of ERC-7579 account.
+func IsModuleInstalled(ctx context.Context, client *ethclient.Client, account common.Address, moduleType uint64, module common.Address) (bool, error) {
+	data, err := BuildTxInputData("isModuleInstalled(uint256,address,bytes)",
+		[]string{fmt.Sprint(moduleType), module.Hex(), "0x"})
+	if err != nil {
+		return false, err
+	}
+	output, err := Call(ctx, client, account, data, nil)
+	if err != nil {
+		return false, fmt.Errorf("isModuleInstalled fail: %w", err)
+	}
+	return new(big.Int).SetBytes(output).Sign() != 0, nil
+}
+
+// ModuleCandidates returns the modules ever installed in account according to ModuleInstalled and ModuleUninstalled
+// events from fromBlock to latest block. ERC-7579 has no enumeration method, so the candidates must be confirmed
+// by IsModuleInstalled.
+func ModuleCandidates(ctx context.Context, client *ethclient.Client, account common.Address, fromBlock *big.Int) ([]Module, error) {
+	logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		Addresses: []common.Address{account},
+		Topics:    [][]common.Hash{{moduleInstalledTopic, moduleUninstalledTopic}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("FilterLogs fail: %w", err)
+	}
+
+	var modules []Module
+	var seen = make(map[Module]bool)
+	for _, log := range logs {
+		// both fields are not indexed
+		if len(log.Data) != 64 {
+			continue
+		}
+		module := Module{
+			Type:    new(big.Int).SetBytes(log.Data[:32]).Uint64(),
+			Address: common.BytesToAddress(log.Data[32:]),
+		}
+		if !seen[module] {
+			seen[module] = true
+			modules = append(modules, module)
+		}
+	}
+	return modules, nil
+}
+
+// DescribeModule fills the metadata of module, i.e. name() and version() implemented by many ERC-7579 modules.
+func DescribeModule(ctx context.Context, client *ethclient.Client, module *Module) {
+	module.Name = strings.TrimSpace(callString(ctx, client, module.Address, "name"))
+	module.Version = strings.TrimSpace(callString(ctx, client, module.Address, "version"))
+}
@@ -0,0 +1,373 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// ExplorerClient is a client of Etherscan-compatible block explorer api (Etherscan, BscScan, Blockscout etc), which
+// covers data that JSON-RPC can't query efficiently, e.g. transactions of an account.
+// See: https://docs.etherscan.io/api-endpoints
+type ExplorerClient struct {
+	BaseUrl string        // e.g. https://api.etherscan.io/api
+	ApiKey  string        // optional, requests without api key are heavily rate limited
+	ChainId uint64        // optional, the chainid param of multi-chain api, e.g. https://api.etherscan.io/v2/api
+	Timeout time.Duration // optional, the deadline of each request
+	Kind    string        // optional, ExplorerKindEtherscan (default) or ExplorerKindBlockscout
+}
+
+// explorerResponse is the envelope of explorer api response.
+type explorerResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  json.RawMessage `json:"result"`
+}
+
+// url returns request url of params, api key and chain id are appended if they're specified.
+func (c *ExplorerClient) url(params url.Values) string {
+	if c.ApiKey != "" {
+		params.Set("apikey", c.ApiKey)
+	}
+	if c.ChainId != 0 {
+		params.Set("chainid", strconv.FormatUint(c.ChainId, 10))
+	}
+	return c.BaseUrl + "?" + params.Encode()
+}
+
+// withTimeout returns ctx with deadline of Timeout if it's specified.
+func (c *ExplorerClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout > 0 {
+		return context.WithTimeout(ctx, c.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// decodeExplorerResponse decodes result of explorer api response body into v. "No transactions found" and "No records found" are
+// not errors, v is left empty.
+func decodeExplorerResponse(body []byte, v any) error {
+	var resp explorerResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return fmt.Errorf("parse explorer response fail: %w", err)
+	}
+	if resp.Status == "0" {
+		if strings.HasPrefix(resp.Message, "No ") && strings.HasSuffix(resp.Message, " found") {
+			return nil
+		}
+		// result is the error detail, e.g. "Invalid API Key" or "Max rate limit reached"
+		return fmt.Errorf("explorer api fail: %v %s", resp.Message, resp.Result)
+	}
+	if err := json.Unmarshal(resp.Result, v); err != nil {
+		return fmt.Errorf("parse explorer result fail: %w: %s", err, resp.Result)
+	}
+	return nil
+}
+
+// get requests explorer api by GET, and decodes the result into v.
+func (c *ExplorerClient) get(ctx context.Context, params url.Values, v any) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	body, err := httpGet(ctx, c.url(params))
+	if err != nil {
+		return err
+	}
+	return decodeExplorerResponse(body, v)
+}
+
+// post requests explorer api by POST form, which is required by large params (e.g. source code).
+func (c *ExplorerClient) post(ctx context.Context, params url.Values, v any) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	if c.ApiKey != "" {
+		params.Set("apikey", c.ApiKey)
+	}
+	requestUrl := c.BaseUrl
+	if c.ChainId != 0 {
+		// multi-chain api requires chainid in query string
+		requestUrl += "?" + url.Values{"chainid": {strconv.FormatUint(c.ChainId, 10)}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestUrl, strings.NewReader(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("POST %v returns %v", c.BaseUrl, resp.Status)
+	}
+	return decodeExplorerResponse(body, v)
+}
+
+// ContractSource returns verified source of contract by getsourcecode api.
+func (c *ExplorerClient) ContractSource(ctx context.Context, address common.Address) (*ContractSource, error) {
+	return FetchEtherscanSource(ctx, c.url(url.Values{
+		"module":  {"contract"},
+		"action":  {"getsourcecode"},
+		"address": {address.Hex()},
+	}))
+}
+
+// ExplorerQuery is the common query of account list apis.
+type ExplorerQuery struct {
+	StartBlock uint64
+	EndBlock   uint64 // 0 means latest
+	Page       int    // starts from 1
+	Offset     int    // number of records per page
+	Desc       bool   // sort by block in descending order
+}
+
+func (q ExplorerQuery) params(module, action string) url.Values {
+	params := url.Values{
+		"module":     {module},
+		"action":     {action},
+		"startblock": {strconv.FormatUint(q.StartBlock, 10)},
+		"endblock":   {"99999999"},
+		"sort":       {"asc"},
+	}
+	if q.EndBlock > 0 {
+		params.Set("endblock", strconv.FormatUint(q.EndBlock, 10))
+	}
+	if q.Page > 0 && q.Offset > 0 {
+		params.Set("page", strconv.Itoa(q.Page))
+		params.Set("offset", strconv.Itoa(q.Offset))
+	}
+	if q.Desc {
+		params.Set("sort", "desc")
+	}
+	return params
+}
+
+// ExplorerTx is a tx returned by txlist api, numbers are in decimal strings in response.
+type ExplorerTx struct {
+	BlockNumber     uint64          `json:"blockNumber,string"`
+	TimeStamp       int64           `json:"timeStamp,string"`
+	Hash            common.Hash     `json:"hash"`
+	Nonce           uint64          `json:"nonce,string"`
+	From            common.Address  `json:"from"`
+	To              string          `json:"to"` // empty if tx creates contract
+	ContractAddress string          `json:"contractAddress"`
+	Value           decimal.Decimal `json:"value"`
+	Gas             uint64          `json:"gas,string"`
+	GasPrice        decimal.Decimal `json:"gasPrice"`
+	GasUsed         uint64          `json:"gasUsed,string"`
+	IsError         string          `json:"isError"` // "1" if tx failed
+	Input           string          `json:"input"`
+	FunctionName    string          `json:"functionName"` // e.g. "transfer(address _to, uint256 _value)", not returned by all explorers
+}
+
+// Time returns block time of tx.
+func (tx *ExplorerTx) Time() time.Time {
+	return time.Unix(tx.TimeStamp, 0)
+}
+
+// Failed returns true if tx is reverted.
+func (tx *ExplorerTx) Failed() bool {
+	return tx.IsError == "1"
+}
+
+// TxList returns normal txs sent from or to address.
+func (c *ExplorerClient) TxList(ctx context.Context, address common.Address, query ExplorerQuery) ([]ExplorerTx, error) {
+	params := query.params("account", "txlist")
+	params.Set("address", address.Hex())
+	var txs []ExplorerTx
+	if err := c.get(ctx, params, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// explorerPageSize is the number of records of each page queried by AllTxList.
+var explorerPageSize = 1000
+
+// AllTxList returns all normal txs sent from or to address in block range of query, in ascending order. Explorers limit
+// the records of one query (e.g. 10000 of etherscan), so txs are queried page by page with start block moved forward.
+func (c *ExplorerClient) AllTxList(ctx context.Context, address common.Address, query ExplorerQuery) ([]ExplorerTx, error) {
+	query.Page, query.Offset, query.Desc = 1, explorerPageSize, false
+	var txs []ExplorerTx
+	var seen = make(map[common.Hash]bool)
+	for {
+		page, err := c.TxList(ctx, address, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range page {
+			if !seen[tx.Hash] {
+				seen[tx.Hash] = true
+				txs = append(txs, tx)
+			}
+		}
+		if len(page) < explorerPageSize {
+			return txs, nil
+		}
+		// the last block may be partially returned, it's queried again by next page
+		if last := page[len(page)-1].BlockNumber; last > query.StartBlock {
+			query.StartBlock, query.Page = last, 1
+		} else {
+			query.Page++ // the page is full of txs in start block
+		}
+	}
+}
+
+// ExplorerInternalTx is an internal tx (value transfer or contract creation in call trace) returned by txlistinternal
+// api.
+type ExplorerInternalTx struct {
+	BlockNumber     uint64          `json:"blockNumber,string"`
+	TimeStamp       int64           `json:"timeStamp,string"`
+	Hash            common.Hash     `json:"hash"` // hash of parent tx, empty if queried by tx hash
+	From            common.Address  `json:"from"`
+	To              string          `json:"to"`
+	ContractAddress string          `json:"contractAddress"`
+	Value           decimal.Decimal `json:"value"`
+	Type            string          `json:"type"` // e.g. call, create
+	TraceId         string          `json:"traceId"`
+	IsError         string          `json:"isError"`
+	ErrCode         string          `json:"errCode"`
+}
+
+// Failed returns true if internal tx is reverted.
+func (tx *ExplorerInternalTx) Failed() bool {
+	return tx.IsError == "1"
+}
+
+// InternalTxsByAddress returns internal txs from or to address.
+func (c *ExplorerClient) InternalTxsByAddress(ctx context.Context, address common.Address, query ExplorerQuery) ([]ExplorerInternalTx, error) {
+	params := query.params("account", "txlistinternal")
+	params.Set("address", address.Hex())
+	var txs []ExplorerInternalTx
+	if err := c.get(ctx, params, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// InternalTxsByHash returns internal txs in tx.
+func (c *ExplorerClient) InternalTxsByHash(ctx context.Context, txHash common.Hash) ([]ExplorerInternalTx, error) {
+	params := url.Values{"module": {"account"}, "action": {"txlistinternal"}, "txhash": {txHash.Hex()}}
+	var txs []ExplorerInternalTx
+	if err := c.get(ctx, params, &txs); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// ExplorerGasOracle is the result of gasoracle api, prices are in gwei.
+type ExplorerGasOracle struct {
+	LastBlock       uint64          `json:"LastBlock,string"`
+	SafeGasPrice    decimal.Decimal `json:"SafeGasPrice"`
+	ProposeGasPrice decimal.Decimal `json:"ProposeGasPrice"`
+	FastGasPrice    decimal.Decimal `json:"FastGasPrice"`
+	SuggestBaseFee  decimal.Decimal `json:"suggestBaseFee"`
+	GasUsedRatio    string          `json:"gasUsedRatio"` // comma separated ratios of recent blocks
+}
+
+// GasOracle returns gas price suggestions of explorer.
+func (c *ExplorerClient) GasOracle(ctx context.Context) (*ExplorerGasOracle, error) {
+	if c.Kind == ExplorerKindBlockscout {
+		// gasoracle is not served by Etherscan-compatible api of Blockscout
+		return c.blockscoutGasOracle(ctx)
+	}
+	var oracle ExplorerGasOracle
+	if err := c.get(ctx, url.Values{"module": {"gastracker"}, "action": {"gasoracle"}}, &oracle); err != nil {
+		return nil, err
+	}
+	return &oracle, nil
+}
+
+// Code formats of source code of verifysourcecode api
+const (
+	ExplorerCodeFormatSingleFile   = "solidity-single-file"
+	ExplorerCodeFormatStandardJson = "solidity-standard-json-input"
+)
+
+// ExplorerVerifyRequest is the request of verifysourcecode api.
+type ExplorerVerifyRequest struct {
+	Address          common.Address
+	SourceCode       string // flattened source, or standard json input
+	CodeFormat       string // ExplorerCodeFormatSingleFile or ExplorerCodeFormatStandardJson
+	ContractName     string // e.g. Token, or contracts/Token.sol:Token for standard json input
+	CompilerVersion  string // e.g. v0.8.19+commit.7dd6d404
+	OptimizationUsed bool   // ignored by standard json input, which has its own settings
+	Runs             int
+	ConstructorArgs  string // abi encoded constructor arguments in hex, without 0x
+	EvmVersion       string // empty means default of compiler
+	LicenseType      int    // 1 means no license, see https://etherscan.io/contract-license-types
+}
+
+// VerifySource submits source of contract for verification, and returns the guid for VerifyStatus.
+func (c *ExplorerClient) VerifySource(ctx context.Context, req ExplorerVerifyRequest) (string, error) {
+	var optimizationUsed = "0"
+	if req.OptimizationUsed {
+		optimizationUsed = "1"
+	}
+	params := url.Values{
+		"module":                {"contract"},
+		"action":                {"verifysourcecode"},
+		"contractaddress":       {req.Address.Hex()},
+		"sourceCode":            {req.SourceCode},
+		"codeformat":            {req.CodeFormat},
+		"contractname":          {req.ContractName},
+		"compilerversion":       {req.CompilerVersion},
+		"optimizationUsed":      {optimizationUsed},
+		"runs":                  {strconv.Itoa(req.Runs)},
+		"constructorArguements": {strings.TrimPrefix(req.ConstructorArgs, "0x")}, // the typo is in the api
+		"licenseType":           {strconv.Itoa(req.LicenseType)},
+	}
+	if req.EvmVersion != "" {
+		params.Set("evmversion", req.EvmVersion)
+	}
+	var guid string
+	if err := c.post(ctx, params, &guid); err != nil {
+		return "", err
+	}
+	return guid, nil
+}
+
+// VerifyStatus returns status of verification guid, pending is true if it's still in queue. The status is e.g.
+// "Pass - Verified", "Fail - Unable to verify" or "Already Verified".
+func (c *ExplorerClient) VerifyStatus(ctx context.Context, guid string) (status string, pending bool, err error) {
+	body, err := httpGet(ctx, c.url(url.Values{"module": {"contract"}, "action": {"checkverifystatus"}, "guid": {guid}}))
+	if err != nil {
+		return "", false, err
+	}
+	var resp explorerResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", false, fmt.Errorf("parse explorer response fail: %w", err)
+	}
+	if err := json.Unmarshal(resp.Result, &status); err != nil {
+		return "", false, fmt.Errorf("parse explorer result fail: %w: %s", err, resp.Result)
+	}
+	// failed verification is status 0 too, so the result is returned as it is
+	return status, strings.HasPrefix(status, "Pending"), nil
+}
+
+// WaitVerifyStatus polls VerifyStatus until verification is not pending, and returns the final status.
+func (c *ExplorerClient) WaitVerifyStatus(ctx context.Context, guid string, pollInterval time.Duration) (string, error) {
+	for {
+		status, pending, err := c.VerifyStatus(ctx, guid)
+		if err != nil || !pending {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
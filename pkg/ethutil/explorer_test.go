@@ -0,0 +1,169 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newExplorerServer returns a server of explorer api with canned responses keyed by action.
+func newExplorerServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if r.Form.Get("apikey") != "KEY" {
+			_, _ = fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Invalid API Key"}`)
+			return
+		}
+		switch r.Form.Get("action") {
+		case "txlist":
+			if r.Form.Get("address") != "0x0000000000000000000000000000000000000001" {
+				_, _ = fmt.Fprint(w, `{"status":"0","message":"No transactions found","result":[]}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"status":"1","message":"OK","result":[{"blockNumber":"14923678","timeStamp":"1654646411",
+"hash":"0xc52783ad354aecc04c670047754f062e3d6d04e8f5b24774472651f9c3882c60","nonce":"1","from":"0x0000000000000000000000000000000000000001",
+"to":"0x0000000000000000000000000000000000000002","value":"1000000000000000000","gas":"21000","gasPrice":"30000000000",
+"isError":"1","input":"0x","contractAddress":"","gasUsed":"21000","functionName":""}]}`)
+		case "gasoracle":
+			_, _ = fmt.Fprint(w, `{"status":"1","message":"OK","result":{"LastBlock":"17400001","SafeGasPrice":"20","ProposeGasPrice":"21",
+"FastGasPrice":"23.5","suggestBaseFee":"19.8","gasUsedRatio":"0.5,0.6"}}`)
+		case "verifysourcecode":
+			if r.Method != http.MethodPost || r.Form.Get("constructorArguements") != "00ff" {
+				t.Errorf("unexpected verify request %v %v", r.Method, r.Form)
+			}
+			_, _ = fmt.Fprint(w, `{"status":"1","message":"OK","result":"guid123"}`)
+		case "checkverifystatus":
+			_, _ = fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Fail - Unable to verify"}`)
+		}
+	}))
+}
+
+func TestExplorerClient(t *testing.T) {
+	server := newExplorerServer(t)
+	defer server.Close()
+	ctx := context.Background()
+	client := &ExplorerClient{BaseUrl: server.URL, ApiKey: "KEY"}
+
+	txs, err := client.TxList(ctx, common.HexToAddress("0x01"), ExplorerQuery{Page: 1, Offset: 10, Desc: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 1 || txs[0].BlockNumber != 14923678 || txs[0].Value.String() != "1000000000000000000" || !txs[0].Failed() {
+		t.Errorf("unexpected txs %+v", txs)
+	}
+
+	txs, err = client.TxList(ctx, common.HexToAddress("0x02"), ExplorerQuery{})
+	if err != nil || len(txs) != 0 {
+		t.Errorf("expected no txs, got %v, %v", txs, err)
+	}
+
+	oracle, err := client.GasOracle(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oracle.LastBlock != 17400001 || oracle.FastGasPrice.String() != "23.5" {
+		t.Errorf("unexpected gas oracle %+v", oracle)
+	}
+
+	guid, err := client.VerifySource(ctx, ExplorerVerifyRequest{Address: common.HexToAddress("0x01"), ConstructorArgs: "0x00ff"})
+	if err != nil || guid != "guid123" {
+		t.Errorf("expected guid123, got %v, %v", guid, err)
+	}
+	status, pending, err := client.VerifyStatus(ctx, guid)
+	if err != nil || pending || status != "Fail - Unable to verify" {
+		t.Errorf("unexpected status %v, %v, %v", status, pending, err)
+	}
+
+	if _, err := (&ExplorerClient{BaseUrl: server.URL}).GasOracle(ctx); err == nil {
+		t.Errorf("expected error without api key")
+	}
+}
+
+func TestExplorerClientChainId(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("chainid") != "10" {
+			t.Errorf("expected chainid 10 in query of %v %v", r.Method, r.URL)
+		}
+		if r.URL.Query().Get("action") == "gasoracle" {
+			time.Sleep(100 * time.Millisecond)
+		}
+		_, _ = fmt.Fprint(w, `{"status":"1","message":"OK","result":"guid123"}`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	client := &ExplorerClient{BaseUrl: server.URL, ChainId: 10}
+
+	if _, _, err := client.VerifyStatus(ctx, "guid123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VerifySource(ctx, ExplorerVerifyRequest{Address: common.HexToAddress("0x01")}); err != nil {
+		t.Fatal(err)
+	}
+	client.Timeout = 10 * time.Millisecond
+	if _, err := client.GasOracle(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %v", requests)
+	}
+}
+
+func TestExplorerClientAllTxList(t *testing.T) {
+	defer func(size int) { explorerPageSize = size }(explorerPageSize)
+	explorerPageSize = 2
+
+	// 6 txs in blocks 1, 2, 2, 2, 3, 4
+	var blocks = []uint64{1, 2, 2, 2, 3, 4}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		startBlock, _ := strconv.ParseUint(query.Get("startblock"), 10, 64)
+		page, _ := strconv.Atoi(query.Get("page"))
+		if query.Get("sort") != "asc" || query.Get("offset") != "2" {
+			t.Errorf("unexpected query %v", r.URL.RawQuery)
+		}
+		var result []string
+		for i, block := range blocks {
+			if block >= startBlock {
+				result = append(result, fmt.Sprintf(`{"blockNumber":"%v","hash":"%v"}`, block, common.BigToHash(big.NewInt(int64(i+1))).Hex()))
+			}
+		}
+		if start := (page - 1) * 2; start < len(result) {
+			result = result[start:]
+		} else {
+			result = nil
+		}
+		if len(result) > 2 {
+			result = result[:2]
+		}
+		_, _ = fmt.Fprintf(w, `{"status":"1","message":"OK","result":[%v]}`, strings.Join(result, ","))
+	}))
+	defer server.Close()
+	client := &ExplorerClient{BaseUrl: server.URL}
+
+	txs, err := client.AllTxList(context.Background(), common.HexToAddress("0x01"), ExplorerQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != len(blocks) {
+		t.Fatalf("expected %v txs, got %+v", len(blocks), txs)
+	}
+	for i, tx := range txs {
+		if tx.BlockNumber != blocks[i] || tx.Hash != common.BigToHash(big.NewInt(int64(i+1))) {
+			t.Errorf("unexpected tx %v: %+v", i, tx)
+		}
+	}
+
+}
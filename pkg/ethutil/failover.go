@@ -0,0 +1,132 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// RetryOptions controls retries of http(s) rpc requests. A request is retried on network error, timeout, or http
+// status 429, 502, 503 and 504, each retry fails over to the next fallback url of the default endpoint. Requests
+// broadcasting txs (e.g. eth_sendRawTransaction) are sent once, as a failed attempt may have been accepted by node.
+type RetryOptions struct {
+	Retries int           // max retries of a request, 0 means no retry
+	Timeout time.Duration // timeout of each attempt, 0 means no timeout
+	Backoff time.Duration // delay before the first retry, it's doubled after each retry (at most 10s), 0 means 500ms
+}
+
+const maxRetryBackoff = 10 * time.Second
+
+// isRetryableStatus returns true if http status means the endpoint is overloaded or temporarily unavailable.
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+// failoverTransport retries rpc requests with exponential backoff, requests to the default url fail over among
+// the default url and its fallbacks. The url which succeeded last is preferred by later requests.
+type failoverTransport struct {
+	urls    []*url.URL // the default url and its fallbacks
+	options RetryOptions
+	base    http.RoundTripper
+
+	mu      sync.Mutex
+	current int // index of preferred url in urls
+}
+
+// cancelOnClose cancels the context of attempt after its response body is read.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+func sameEndpoint(a, b *url.URL) bool {
+	return a.Scheme == b.Scheme && a.Host == b.Host && a.Path == b.Path && a.RawQuery == b.RawQuery
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+
+	// only requests to the default url fail over, requests routed to other endpoints are retried on the same url
+	var candidates = []*url.URL{req.URL}
+	var start int
+	if sameEndpoint(req.URL, t.urls[0]) {
+		candidates = t.urls
+		t.mu.Lock()
+		start = t.current
+		t.mu.Unlock()
+	}
+
+	retries := t.options.Retries
+	for _, msg := range parseJsonrpcRequest(body) {
+		if broadcastMethods[msg.Method] {
+			retries = 0
+		}
+	}
+
+	var backoff = t.options.Backoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	for attempt := 0; ; attempt++ {
+		index := (start + attempt) % len(candidates)
+		resp, err := t.attempt(req, candidates[index], body)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			if len(candidates) > 1 {
+				t.mu.Lock()
+				t.current = index
+				t.mu.Unlock()
+			}
+			return resp, nil
+		}
+		if attempt >= retries || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// attempt sends body to target, with the timeout of options.
+func (t *failoverTransport) attempt(req *http.Request, target *url.URL, body []byte) (*http.Response, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.options.Timeout)
+	}
+	attemptReq := req.Clone(ctx)
+	attemptReq.URL = target
+	attemptReq.Host = target.Host
+	attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+	attemptReq.ContentLength = int64(len(body))
+
+	resp, err := t.base.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
@@ -0,0 +1,142 @@
+package ethutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRpcServer returns a server which responds eth_blockNumber with 0x10 after failures requests fail with status.
+func newRpcServer(failures int32, status int, hits *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(hits, 1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+}
+
+func TestFailoverTransport(t *testing.T) {
+	tests := []struct {
+		failures    []int32 // failures of each server, -1 means always fails
+		retries     int
+		expectErr   bool
+		expectedHit []int32
+	}{
+		{[]int32{0}, 0, false, []int32{1}},
+		{[]int32{2}, 0, true, []int32{1}},
+		{[]int32{2}, 2, false, []int32{3}},
+		{[]int32{-1, 0}, 0, false, []int32{1, 1}}, // retries default to number of fallbacks
+		{[]int32{-1, -1, 0}, 1, true, []int32{1, 1, 0}},
+	}
+
+	for i, test := range tests {
+		var hits = make([]int32, len(test.failures))
+		var endpoints Endpoints
+		for j, failures := range test.failures {
+			if failures < 0 {
+				failures = 1 << 30
+			}
+			server := newRpcServer(failures, http.StatusServiceUnavailable, &hits[j])
+			defer server.Close()
+			if j == 0 {
+				endpoints.Default = server.URL
+			} else {
+				endpoints.Fallbacks = append(endpoints.Fallbacks, server.URL)
+			}
+		}
+
+		client, err := DialEndpointsWithRetry(context.Background(), endpoints, RetryOptions{Retries: test.retries, Backoff: time.Millisecond})
+		if err != nil {
+			t.Fatalf("test %d: dial fail: %v", i, err)
+		}
+		blockNumber, err := client.EthClient.BlockNumber(context.Background())
+		if (err != nil) != test.expectErr {
+			t.Fatalf("test %d: expected error: %v, got: %v", i, test.expectErr, err)
+		}
+		if err == nil && blockNumber != 16 {
+			t.Fatalf("test %d: expected: %v, got: %v", i, 16, blockNumber)
+		}
+		for j := range hits {
+			if hits[j] != test.expectedHit[j] {
+				t.Fatalf("test %d: expected hits: %v, got: %v", i, test.expectedHit, hits)
+			}
+		}
+		client.Close()
+	}
+}
+
+func TestFailoverTransportPreferLastSucceeded(t *testing.T) {
+	var hits [2]int32
+	primary := newRpcServer(1, http.StatusTooManyRequests, &hits[0])
+	defer primary.Close()
+	fallback := newRpcServer(0, 0, &hits[1])
+	defer fallback.Close()
+
+	client, err := DialEndpointsWithRetry(context.Background(), Endpoints{Default: primary.URL, Fallbacks: []string{fallback.URL}},
+		RetryOptions{Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("dial fail: %v", err)
+	}
+	defer client.Close()
+	for i := 0; i < 3; i++ {
+		if _, err := client.EthClient.BlockNumber(context.Background()); err != nil {
+			t.Fatalf("call %d fail: %v", i, err)
+		}
+	}
+	if hits != [2]int32{1, 3} {
+		t.Fatalf("expected hits: %v, got: %v", [2]int32{1, 3}, hits)
+	}
+}
+
+func TestFailoverTransportNoRetryBroadcast(t *testing.T) {
+	var hits [2]int32
+	primary := newRpcServer(1, http.StatusGatewayTimeout, &hits[0])
+	defer primary.Close()
+	fallback := newRpcServer(0, 0, &hits[1])
+	defer fallback.Close()
+
+	client, err := DialEndpointsWithRetry(context.Background(), Endpoints{Default: primary.URL, Fallbacks: []string{fallback.URL}},
+		RetryOptions{Retries: 2, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("dial fail: %v", err)
+	}
+	defer client.Close()
+	var result string
+	if err := client.RpcClient.CallContext(context.Background(), &result, "eth_sendRawTransaction", "0x02"); err == nil {
+		t.Fatalf("expected error of the only attempt")
+	}
+	if hits != [2]int32{1, 0} {
+		t.Fatalf("expected hits: %v, got: %v", [2]int32{1, 0}, hits)
+	}
+}
+
+func TestFailoverTransportTimeout(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			time.Sleep(500 * time.Millisecond) // the first attempt times out
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+	}))
+	defer server.Close()
+
+	client, err := DialEndpointsWithRetry(context.Background(), Endpoints{Default: server.URL},
+		RetryOptions{Retries: 1, Timeout: 100 * time.Millisecond, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatalf("dial fail: %v", err)
+	}
+	defer client.Close()
+	if _, err := client.EthClient.BlockNumber(context.Background()); err != nil {
+		t.Fatalf("expected success after retry, got: %v", err)
+	}
+	if hits != 2 {
+		t.Fatalf("expected hits: %v, got: %v", 2, hits)
+	}
+}
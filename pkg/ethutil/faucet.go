@@ -0,0 +1,144 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// NonceManager assigns consecutive nonces to txs of an account locally, so many txs can be sent without waiting for
+// the previous ones to be mined. It's safe for concurrent use.
+type NonceManager struct {
+	Client  *ethclient.Client
+	Account common.Address
+
+	mu     sync.Mutex
+	next   uint64
+	synced bool
+}
+
+// Next returns the nonce of next tx, the pending nonce is queried on first use and after Reset.
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.synced {
+		nonce, err := m.Client.PendingNonceAt(ctx, m.Account)
+		if err != nil {
+			return 0, fmt.Errorf("PendingNonceAt fail: %w", err)
+		}
+		m.next, m.synced = nonce, true
+	}
+	nonce := m.next
+	m.next++
+	return nonce, nil
+}
+
+// Reset makes the next call of Next query the pending nonce again, it should be called if a tx with the nonce from
+// Next is not sent, otherwise the following txs are stuck behind the nonce gap.
+func (m *NonceManager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.synced = false
+}
+
+// FaucetLimiter limits drips of faucet: each key (e.g. address or ip of requester) can be served once per Interval,
+// and all keys together are served at most MaxPerMinute times in any minute. It's safe for concurrent use.
+type FaucetLimiter struct {
+	Interval     time.Duration // 0 means no limit per key
+	MaxPerMinute int           // 0 means no global limit
+
+	now func() time.Time // for test, nil means time.Now
+
+	mu     sync.Mutex
+	last   map[string]time.Time
+	recent []time.Time // times of drips in last minute
+}
+
+// Allow returns 0 if keys can be served now, and records the drip for all keys. Otherwise it returns how long the
+// requester should wait, nothing is recorded.
+func (l *FaucetLimiter) Allow(keys ...string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if l.now != nil {
+		now = l.now()
+	}
+	if l.last == nil {
+		l.last = make(map[string]time.Time)
+	}
+
+	var wait time.Duration
+	for _, key := range keys {
+		if last, ok := l.last[key]; ok && now.Sub(last) < l.Interval {
+			if w := l.Interval - now.Sub(last); w > wait {
+				wait = w
+			}
+		}
+	}
+	var recent []time.Time
+	for _, t := range l.recent {
+		if now.Sub(t) < time.Minute {
+			recent = append(recent, t)
+		}
+	}
+	l.recent = recent
+	if l.MaxPerMinute > 0 && len(l.recent) >= l.MaxPerMinute {
+		if w := time.Minute - now.Sub(l.recent[0]); w > wait {
+			wait = w
+		}
+	}
+	if wait > 0 {
+		return wait
+	}
+
+	for _, key := range keys {
+		l.last[key] = now
+	}
+	l.recent = append(l.recent, now)
+	// forget keys whose interval is over, so memory is bounded by the drips in an interval
+	for key, last := range l.last {
+		if now.Sub(last) >= l.Interval {
+			delete(l.last, key)
+		}
+	}
+	return 0
+}
+
+// FaucetGateRequest is the request sent to gate hook of faucet.
+type FaucetGateRequest struct {
+	Address common.Address `json:"address"`
+	Ip      string         `json:"ip"`
+	Captcha string         `json:"captcha"` // e.g. the response token of hCaptcha or Turnstile widget
+}
+
+// CheckFaucetGate posts request to the gate hook gateUrl, which verifies the captcha or any other condition (e.g.
+// GitHub account). The request is allowed if the hook responds 2xx, otherwise the response body is the reason.
+func CheckFaucetGate(ctx context.Context, gateUrl string, request FaucetGateRequest) error {
+	content, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gateUrl, bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request gate hook fail: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("rejected by gate hook (%v): %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func TestFaucetLimiter(t *testing.T) {
+	var now = time.Unix(1700000000, 0)
+	limiter := &FaucetLimiter{Interval: time.Hour, MaxPerMinute: 2, now: func() time.Time { return now }}
+	tests := []struct {
+		advance  time.Duration
+		keys     []string
+		expected time.Duration
+	}{
+		{0, []string{"a", "ip1"}, 0},
+		{10 * time.Second, []string{"a", "ip2"}, 59*time.Minute + 50*time.Second}, // same address
+		{0, []string{"b", "ip1"}, 59*time.Minute + 50*time.Second},                // same ip
+		{0, []string{"b", "ip2"}, 0},
+		{0, []string{"c", "ip3"}, 50 * time.Second}, // 2 drips in last minute
+		{50 * time.Second, []string{"c", "ip3"}, 0},
+		{time.Hour, []string{"a", "ip1"}, 0},
+	}
+	for i, test := range tests {
+		now = now.Add(test.advance)
+		if wait := limiter.Allow(test.keys...); wait != test.expected {
+			t.Errorf("case %v: expected wait %v, got %v", i, test.expected, wait)
+		}
+	}
+}
+
+func TestNonceManager(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		var req struct {
+			Id json.RawMessage `json:"id"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"0x5"}`, req.Id)
+	}))
+	defer server.Close()
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager := &NonceManager{Client: client, Account: common.HexToAddress("0x01")}
+	var nonces []uint64
+	for i := 0; i < 3; i++ {
+		nonce, err := manager.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces = append(nonces, nonce)
+	}
+	manager.Reset()
+	nonce, err := manager.Next(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonces = append(nonces, nonce)
+	if fmt.Sprint(nonces) != "[5 6 7 5]" || hits != 2 {
+		t.Errorf("expected nonces [5 6 7 5] by 2 requests, got %v by %v requests", nonces, hits)
+	}
+}
+
+func TestCheckFaucetGate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req FaucetGateRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if req.Captcha != "good" {
+			http.Error(w, "invalid captcha", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		captcha string
+		allowed bool
+	}{
+		{"good", true},
+		{"bad", false},
+	}
+	for _, test := range tests {
+		err := CheckFaucetGate(context.Background(), server.URL, FaucetGateRequest{Address: common.HexToAddress("0x01"), Captcha: test.captcha})
+		if (err == nil) != test.allowed {
+			t.Errorf("captcha %v: expected allowed %v, got error %v", test.captcha, test.allowed, err)
+		}
+	}
+}
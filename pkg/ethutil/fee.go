@@ -0,0 +1,116 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Speeds of fee estimation
+const (
+	SpeedSlow    = "slow"
+	SpeedAverage = "average"
+	SpeedFast    = "fast"
+)
+
+// FeeEstimate is the eip1559 fee estimation
+type FeeEstimate struct {
+	BaseFee *big.Int // base fee of pending block
+
+	// max priority fee per gas estimations
+	Slow    *big.Int
+	Average *big.Int
+	Fast    *big.Int
+
+	Source string // how max priority fee per gas is estimated, e.g. FeeSourceMaxPriorityFeePerGas
+}
+
+// MaxFeePerGas returns base fee plus the average max priority fee per gas.
+func (f *FeeEstimate) MaxFeePerGas() *big.Int {
+	return new(big.Int).Add(f.BaseFee, f.Average)
+}
+
+// Tip returns max priority fee per gas estimation of speed, empty speed means SpeedAverage.
+func (f *FeeEstimate) Tip(speed string) *big.Int {
+	switch speed {
+	case SpeedSlow:
+		return f.Slow
+	case SpeedFast:
+		return f.Fast
+	default:
+		return f.Average
+	}
+}
+
+// EstimateFees estimates fees by DefaultGasOracle without floor.
+func EstimateFees(ctx context.Context, client *ethclient.Client) (*FeeEstimate, error) {
+	return (&DefaultGasOracle{Client: client}).EstimateFees(ctx)
+}
+
+// feeHistoryTips uses rpc eth_feeHistory to estimate maxPriorityFeePerGas (slow, average and fast).
+// See https://docs.alchemy.com/docs/how-to-build-a-gas-fee-estimator-using-eip-1559
+//
+// $ curl -X POST --data '{ "id": 1, "jsonrpc": "2.0", "method": "eth_feeHistory", "params": ["0x4", "latest", [5, 50, 95]] }' https://mainnet.infura.io/v3/21a9f5ba4bce425795cac796a66d7472
+//
+//	{
+//	 "jsonrpc": "2.0",
+//	 "id": 1,
+//	 "result": {
+//	   "baseFeePerGas": [
+//	     "0x4ed3ef336",
+//	     "0x4d2c282cd",
+//	     "0x4db586991",
+//	     "0x4d8275e8e",
+//	     "0x4b5fb0a47"
+//	   ],
+//	   "gasUsedRatio": [
+//	     0.41600023333333336,
+//	     0.5278128666666667,
+//	     0.4897323,
+//	     0.3897776666666667
+//	   ],
+//	   "oldestBlock": "0xffc0a9",
+//	   "reward": [
+//	     [
+//	       "0x6b51f67",
+//	       "0x3b9aca00",
+//	       "0x106853ddd8"
+//	     ],
+//	     [
+//	       "0xa9970dc",
+//	       "0x1dcd6500",
+//	       "0x10abffd64"
+//	     ],
+//	     [
+//	       "0x6190547",
+//	       "0x1dcd6500",
+//	       "0x9becf3d3c"
+//	     ],
+//	     [
+//	       "0x94a104a",
+//	       "0x1dcd6500",
+//	       "0x1032d8cdb"
+//	     ]
+//	   ]
+//	 }
+//	}
+func feeHistoryTips(ctx context.Context, client *ethclient.Client) (slow, average, fast *big.Int, err error) {
+	feeHistory, err := client.FeeHistory(ctx, 4, nil, []float64{5, 50, 95})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("FeeHistory fail: %w", err)
+	}
+	if len(feeHistory.Reward) < 3 {
+		return nil, nil, nil, fmt.Errorf("FeeHistory returns %v rewards, at least 3 are required", len(feeHistory.Reward))
+	}
+
+	var avg = func(percentileIndex int) *big.Int {
+		var sum = new(big.Int)
+		for _, reward := range feeHistory.Reward[0:3] {
+			sum.Add(sum, reward[percentileIndex])
+		}
+		return sum.Div(sum, big.NewInt(3))
+	}
+	return avg(0), avg(1), avg(2), nil
+}
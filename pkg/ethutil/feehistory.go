@@ -0,0 +1,125 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// maxFeeHistoryBlocks is the max block count of an eth_feeHistory request accepted by geth.
+const maxFeeHistoryBlocks = 1024
+
+// FeeHistoryBlock is the fee data of a block returned by eth_feeHistory.
+type FeeHistoryBlock struct {
+	Number       uint64
+	BaseFee      *big.Int
+	GasUsedRatio float64
+	Rewards      []*big.Int // priority fee per gas at each percentile of FeeHistory
+}
+
+// FeeHistory is the fee data of consecutive blocks, in ascending order of block number.
+type FeeHistory struct {
+	Percentiles []float64
+	Blocks      []FeeHistoryBlock
+	NextBaseFee *big.Int // base fee of the block after the newest block
+}
+
+// GetFeeHistory returns fee history of blocks ending at newest (nil means latest) by eth_feeHistory, the window is
+// split into requests of at most 1024 blocks.
+func GetFeeHistory(ctx context.Context, client *ethclient.Client, blocks uint64, newest *big.Int, percentiles []float64) (*FeeHistory, error) {
+	var history = FeeHistory{Percentiles: percentiles}
+	for remaining := blocks; remaining > 0; {
+		count := remaining
+		if count > maxFeeHistoryBlocks {
+			count = maxFeeHistoryBlocks
+		}
+		result, err := client.FeeHistory(ctx, count, newest, percentiles)
+		if err != nil {
+			return nil, fmt.Errorf("FeeHistory fail: %w", err)
+		}
+		if len(result.BaseFee) == 0 || len(result.GasUsedRatio) == 0 {
+			break
+		}
+
+		var page []FeeHistoryBlock
+		for i, ratio := range result.GasUsedRatio {
+			block := FeeHistoryBlock{
+				Number:       result.OldestBlock.Uint64() + uint64(i),
+				BaseFee:      result.BaseFee[i],
+				GasUsedRatio: ratio,
+			}
+			if i < len(result.Reward) {
+				block.Rewards = result.Reward[i]
+			}
+			page = append(page, block)
+		}
+		if history.NextBaseFee == nil {
+			history.NextBaseFee = result.BaseFee[len(result.BaseFee)-1]
+		}
+		history.Blocks = append(page, history.Blocks...)
+
+		remaining -= uint64(len(page))
+		if result.OldestBlock.Sign() == 0 {
+			break
+		}
+		newest = new(big.Int).Sub(result.OldestBlock, big.NewInt(1))
+	}
+	return &history, nil
+}
+
+// FeeStats is min, average and max of a fee.
+type FeeStats struct {
+	Min *big.Int
+	Avg *big.Int
+	Max *big.Int
+}
+
+// newFeeStats returns stats of values, nil is returned if values is empty.
+func newFeeStats(values []*big.Int) *FeeStats {
+	if len(values) == 0 {
+		return nil
+	}
+	var stats = FeeStats{Min: values[0], Max: values[0], Avg: new(big.Int)}
+	for _, v := range values {
+		stats.Min = minBigInt(stats.Min, v)
+		stats.Max = maxBigInt(stats.Max, v)
+		stats.Avg.Add(stats.Avg, v)
+	}
+	stats.Avg.Div(stats.Avg, big.NewInt(int64(len(values))))
+	return &stats
+}
+
+// BaseFeeStats returns stats of base fee of blocks.
+func (h *FeeHistory) BaseFeeStats() *FeeStats {
+	var values []*big.Int
+	for _, b := range h.Blocks {
+		values = append(values, b.BaseFee)
+	}
+	return newFeeStats(values)
+}
+
+// RewardStats returns stats of priority fee at the ith percentile of blocks, blocks without reward (e.g. empty
+// blocks) are skipped.
+func (h *FeeHistory) RewardStats(i int) *FeeStats {
+	var values []*big.Int
+	for _, b := range h.Blocks {
+		if i < len(b.Rewards) {
+			values = append(values, b.Rewards[i])
+		}
+	}
+	return newFeeStats(values)
+}
+
+// AvgGasUsedRatio returns the average gas used ratio of blocks.
+func (h *FeeHistory) AvgGasUsedRatio() float64 {
+	if len(h.Blocks) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, b := range h.Blocks {
+		sum += b.GasUsedRatio
+	}
+	return sum / float64(len(h.Blocks))
+}
@@ -0,0 +1,93 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// newFeeHistoryServer returns a server whose latest block is latest, base fee of block n is n wei, gas used ratio of
+// block n is n%2/2 and its rewards are [n, 2n].
+func newFeeHistoryServer(latest uint64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage `json:"id"`
+			Params []any           `json:"params"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		count, _ := hexutil.DecodeUint64(req.Params[0].(string))
+		newest := latest
+		if req.Params[1].(string) != "latest" {
+			newest, _ = hexutil.DecodeUint64(req.Params[1].(string))
+		}
+		if count > newest+1 {
+			count = newest + 1
+		}
+		oldest := newest + 1 - count
+		var baseFees, ratios, rewards []string
+		for n := oldest; n <= newest; n++ {
+			baseFees = append(baseFees, fmt.Sprintf(`"0x%x"`, n))
+			ratios = append(ratios, strconv.FormatFloat(float64(n%2)/2, 'f', -1, 64))
+			rewards = append(rewards, fmt.Sprintf(`["0x%x","0x%x"]`, n, 2*n))
+		}
+		baseFees = append(baseFees, fmt.Sprintf(`"0x%x"`, newest+1))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{"oldestBlock":"0x%x","baseFeePerGas":[%s],"gasUsedRatio":[%s],"reward":[%s]}}`,
+			req.Id, oldest, strings.Join(baseFees, ","), strings.Join(ratios, ","), strings.Join(rewards, ","))
+	}))
+}
+
+func TestGetFeeHistory(t *testing.T) {
+	tests := []struct {
+		latest         uint64
+		blocks         uint64
+		expectedOldest uint64
+		expectedCount  int
+	}{
+		{100, 20, 81, 20},
+		{3000, 2000, 1001, 2000}, // split into 2 requests
+		{9, 20, 0, 10},           // window is longer than the chain
+	}
+
+	for i, test := range tests {
+		server := newFeeHistoryServer(test.latest)
+		client, err := ethclient.Dial(server.URL)
+		if err != nil {
+			t.Fatalf("test %d: dial fail: %v", i, err)
+		}
+		history, err := GetFeeHistory(context.Background(), client, test.blocks, nil, []float64{10, 90})
+		server.Close()
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if len(history.Blocks) != test.expectedCount || history.Blocks[0].Number != test.expectedOldest {
+			t.Fatalf("test %d: expected: %v blocks since %v, got: %v blocks since %v", i, test.expectedCount, test.expectedOldest, len(history.Blocks), history.Blocks[0].Number)
+		}
+		for j, block := range history.Blocks {
+			if block.Number != test.expectedOldest+uint64(j) || block.BaseFee.Uint64() != block.Number || block.Rewards[1].Uint64() != 2*block.Number {
+				t.Fatalf("test %d: unexpected block %d: %+v", i, j, block)
+			}
+		}
+		if history.NextBaseFee.Uint64() != test.latest+1 {
+			t.Fatalf("test %d: expected next base fee: %v, got: %v", i, test.latest+1, history.NextBaseFee)
+		}
+
+		stats := history.BaseFeeStats()
+		expectedAvg := (2*test.expectedOldest + uint64(test.expectedCount) - 1) / 2
+		if stats.Min.Uint64() != test.expectedOldest || stats.Max.Uint64() != test.latest || stats.Avg.Cmp(new(big.Int).SetUint64(expectedAvg)) != 0 {
+			t.Fatalf("test %d: unexpected base fee stats: %v %v %v", i, stats.Min, stats.Avg, stats.Max)
+		}
+		if ratio := history.AvgGasUsedRatio(); ratio < 0.24 || ratio > 0.26 {
+			t.Fatalf("test %d: unexpected avg gas used ratio: %v", i, ratio)
+		}
+	}
+}
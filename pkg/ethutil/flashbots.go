@@ -0,0 +1,168 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FlashbotsRelayUrls maps chain id to the default flashbots relay url of that chain.
+var FlashbotsRelayUrls = map[uint64]string{
+	1:        "https://relay.flashbots.net",
+	5:        "https://relay-goerli.flashbots.net",
+	11155111: "https://relay-sepolia.flashbots.net",
+}
+
+// flashbotsSignTransport adds X-Flashbots-Signature header to every request.
+// See: https://docs.flashbots.net/flashbots-auction/advanced/rpc-endpoint#authentication
+type flashbotsSignTransport struct {
+	authKey *ecdsa.PrivateKey
+	base    http.RoundTripper
+}
+
+func (t *flashbotsSignTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+
+	signature, err := PersonalSign(crypto.Keccak256Hash(body).Hex(), t.authKey)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.Header.Set("X-Flashbots-Signature", AddressFromPrivateKey(t.authKey).Hex()+":"+signature)
+	return t.base.RoundTrip(req)
+}
+
+// FlashbotsClient submits bundles to a flashbots relay.
+type FlashbotsClient struct {
+	rpcClient *rpc.Client
+}
+
+// DialFlashbots connects to a flashbots relay, authKey is only used to sign requests (identify searcher reputation),
+// it does not need to hold any eth.
+func DialFlashbots(relayUrl string, authKey *ecdsa.PrivateKey) (*FlashbotsClient, error) {
+	rpcClient, err := rpc.DialHTTPWithClient(relayUrl, &http.Client{
+		Transport: &flashbotsSignTransport{authKey: authKey, base: http.DefaultTransport},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &FlashbotsClient{rpcClient: rpcClient}, nil
+}
+
+// encodeRawTxs encodes signed txs as raw txs.
+func encodeRawTxs(txs []*types.Transaction) ([]string, error) {
+	var rawTxs []string
+	for _, tx := range txs {
+		rawTx, err := GenRawTx(tx)
+		if err != nil {
+			return nil, err
+		}
+		rawTxs = append(rawTxs, rawTx)
+	}
+	return rawTxs, nil
+}
+
+// SendBundle submits signed txs as a bundle which can only be included atomically and in order in block blockNumber.
+// The bundle hash returned by relay is returned.
+func (c *FlashbotsClient) SendBundle(ctx context.Context, txs []*types.Transaction, blockNumber uint64) (string, error) {
+	rawTxs, err := encodeRawTxs(txs)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		BundleHash string `json:"bundleHash"`
+	}
+	err = c.rpcClient.CallContext(ctx, &result, "eth_sendBundle", map[string]interface{}{
+		"txs":         rawTxs,
+		"blockNumber": hexutil.EncodeUint64(blockNumber),
+	})
+	if err != nil {
+		return "", fmt.Errorf("eth_sendBundle fail: %w", err)
+	}
+	return result.BundleHash, nil
+}
+
+// BundleTxResult is the simulation result of a tx in bundle.
+type BundleTxResult struct {
+	TxHash       common.Hash `json:"txHash"`
+	GasUsed      uint64      `json:"gasUsed"`
+	GasPrice     string      `json:"gasPrice"`
+	CoinbaseDiff string      `json:"coinbaseDiff"`
+	Error        string      `json:"error"`
+	Revert       string      `json:"revert"`
+}
+
+// BundleSimulation is the result of eth_callBundle, amounts are in wei.
+type BundleSimulation struct {
+	BundleHash        string           `json:"bundleHash"`
+	BundleGasPrice    string           `json:"bundleGasPrice"`
+	CoinbaseDiff      string           `json:"coinbaseDiff"`
+	EthSentToCoinbase string           `json:"ethSentToCoinbase"`
+	GasFees           string           `json:"gasFees"`
+	StateBlockNumber  uint64           `json:"stateBlockNumber"`
+	TotalGasUsed      uint64           `json:"totalGasUsed"`
+	Results           []BundleTxResult `json:"results"`
+}
+
+// CallBundle simulates txs as a bundle in block blockNumber on top of the latest state, the bundle is not submitted.
+func (c *FlashbotsClient) CallBundle(ctx context.Context, txs []*types.Transaction, blockNumber uint64) (*BundleSimulation, error) {
+	rawTxs, err := encodeRawTxs(txs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result BundleSimulation
+	err = c.rpcClient.CallContext(ctx, &result, "eth_callBundle", map[string]interface{}{
+		"txs":              rawTxs,
+		"blockNumber":      hexutil.EncodeUint64(blockNumber),
+		"stateBlockNumber": "latest",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("eth_callBundle fail: %w", err)
+	}
+	return &result, nil
+}
+
+// SendPrivateTransaction sends signed tx to relay privately, the tx is not visible in public mempool and is only
+// included by builders before block maxBlockNumber. The tx hash returned by relay is returned.
+func (c *FlashbotsClient) SendPrivateTransaction(ctx context.Context, tx *types.Transaction, maxBlockNumber uint64) (common.Hash, error) {
+	rawTx, err := GenRawTx(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	var result common.Hash
+	err = c.rpcClient.CallContext(ctx, &result, "eth_sendPrivateTransaction", map[string]interface{}{
+		"tx":             rawTx,
+		"maxBlockNumber": hexutil.EncodeUint64(maxBlockNumber),
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendPrivateTransaction fail: %w", err)
+	}
+	return result, nil
+}
+
+// Close closes the underlying rpc connection.
+func (c *FlashbotsClient) Close() {
+	c.rpcClient.Close()
+}
@@ -0,0 +1,80 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// SignerForks are the forks which change tx signing rules, in activation order. Forks after london (e.g. paris,
+// shanghai) sign tx in the same way as london.
+var SignerForks = []string{"frontier", "homestead", "spurious-dragon", "berlin", "london"}
+
+// NewForkSigner returns the signer of chain at fork, which applies the signing rules of the fork, e.g. signer of
+// spurious-dragon (EIP-155) only signs legacy tx, signer of berlin also signs EIP-2930 tx. chainID is not used by
+// frontier and homestead, whose signatures are not replay protected.
+func NewForkSigner(fork string, chainID *big.Int) (types.Signer, error) {
+	switch strings.ToLower(fork) {
+	case "frontier":
+		return types.FrontierSigner{}, nil
+	case "homestead":
+		return types.HomesteadSigner{}, nil
+	}
+
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, fmt.Errorf("chain id is required by fork %v", fork)
+	}
+	switch strings.ToLower(fork) {
+	case "spurious-dragon", "eip155":
+		return types.NewEIP155Signer(chainID), nil
+	case "berlin":
+		return types.NewEIP2930Signer(chainID), nil
+	case "london", "paris", "merge", "shanghai":
+		return types.NewLondonSigner(chainID), nil
+	default:
+		return nil, fmt.Errorf("unsupported fork %v, supported: %v", fork, strings.Join(SignerForks, ", "))
+	}
+}
+
+// NewChainConfigSigner returns the most permissive signer of chain config, i.e. the signer of the latest fork
+// scheduled in it. content is the json of chain config, or genesis json which contains chain config in "config".
+func NewChainConfigSigner(content []byte) (types.Signer, error) {
+	var genesis struct {
+		Config *params.ChainConfig `json:"config"`
+	}
+	if err := json.Unmarshal(content, &genesis); err != nil {
+		return nil, fmt.Errorf("parse chain config fail: %w", err)
+	}
+	config := genesis.Config
+	if config == nil {
+		config = new(params.ChainConfig)
+		if err := json.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("parse chain config fail: %w", err)
+		}
+	}
+	if config.ChainID == nil {
+		return nil, fmt.Errorf("chainId is not found in chain config")
+	}
+	return types.LatestSigner(config), nil
+}
+
+// SignTxWithSigner signs tx by signer offline, it fails if type of tx is not supported by signer.
+func SignTxWithSigner(tx *types.Transaction, signer types.Signer, privateKey *ecdsa.PrivateKey) (*types.Transaction, error) {
+	signedTx, err := types.SignTx(tx, signer, privateKey)
+	if errors.Is(err, types.ErrTxTypeNotSupported) {
+		return nil, fmt.Errorf("tx type %v is not supported by the fork", tx.Type())
+	}
+	if errors.Is(err, types.ErrInvalidChainId) {
+		return nil, fmt.Errorf("chain id of tx is %v, but chain id of signer is %v", tx.ChainId(), signer.ChainID())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("SignTx fail: %w", err)
+	}
+	return signedTx, nil
+}
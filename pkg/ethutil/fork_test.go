@@ -0,0 +1,96 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSignTxWithSigner(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	chainID := big.NewInt(5)
+	legacyTx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	accessListTx := types.NewTx(&types.AccessListTx{ChainID: chainID, Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	dynamicFeeTx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+
+	tests := []struct {
+		fork      string
+		chainID   *big.Int
+		tx        *types.Transaction
+		valid     bool
+		protected bool
+	}{
+		{"homestead", nil, legacyTx, true, false},
+		{"homestead", nil, dynamicFeeTx, false, false},
+		{"spurious-dragon", chainID, legacyTx, true, true},
+		{"spurious-dragon", chainID, accessListTx, false, false},
+		{"berlin", chainID, accessListTx, true, true},
+		{"berlin", chainID, dynamicFeeTx, false, false},
+		{"london", chainID, dynamicFeeTx, true, true},
+		{"shanghai", big.NewInt(1), dynamicFeeTx, false, false}, // chain id mismatch
+	}
+
+	for i, test := range tests {
+		signer, err := NewForkSigner(test.fork, test.chainID)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		signedTx, err := SignTxWithSigner(test.tx, signer, privateKey)
+		if valid := err == nil; valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.valid, valid, err)
+		}
+		if err != nil {
+			continue
+		}
+		if signedTx.Protected() != test.protected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.protected, signedTx.Protected())
+		}
+		if sender, err := types.Sender(signer, signedTx); err != nil || sender != AddressFromPrivateKey(privateKey) {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, AddressFromPrivateKey(privateKey).Hex(), sender.Hex(), err)
+		}
+	}
+
+	if _, err := NewForkSigner("london", nil); err == nil {
+		t.Fatalf("expected error for missing chain id")
+	}
+	if _, err := NewForkSigner("byzantium", chainID); err == nil {
+		t.Fatalf("expected error for unsupported fork")
+	}
+}
+
+func TestNewChainConfigSigner(t *testing.T) {
+	tests := []struct {
+		content string
+		txType  uint8 // the latest tx type supported
+	}{
+		{`{"config":{"chainId":1337,"homesteadBlock":0,"eip155Block":0,"berlinBlock":0,"londonBlock":0},"alloc":{}}`, types.DynamicFeeTxType},
+		{`{"chainId":56,"homesteadBlock":0,"eip155Block":0,"berlinBlock":0}`, types.AccessListTxType},
+		{`{"chainId":77,"homesteadBlock":0,"eip155Block":0}`, types.LegacyTxType},
+	}
+
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	for i, test := range tests {
+		signer, err := NewChainConfigSigner([]byte(test.content))
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		dynamicFeeTx := types.NewTx(&types.DynamicFeeTx{ChainID: signer.ChainID(), Gas: 21000, To: &to})
+		accessListTx := types.NewTx(&types.AccessListTx{ChainID: signer.ChainID(), Gas: 21000, To: &to})
+		var latest uint8 = types.LegacyTxType
+		for _, tx := range []*types.Transaction{accessListTx, dynamicFeeTx} {
+			if _, err := SignTxWithSigner(tx, signer, mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")); err == nil {
+				latest = tx.Type()
+			}
+		}
+		if latest != test.txType {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.txType, latest)
+		}
+	}
+
+	if _, err := NewChainConfigSigner([]byte(`{"homesteadBlock":0}`)); err == nil {
+		t.Fatalf("expected error for missing chain id")
+	}
+}
@@ -0,0 +1,146 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ForkImpersonateAccount makes fork node (anvil or hardhat) accept eth_sendTransaction from address without its
+// private key, by anvil_impersonateAccount or hardhat_impersonateAccount.
+func ForkImpersonateAccount(ctx context.Context, rpcClient *rpc.Client, address common.Address) error {
+	for _, method := range []string{"anvil_impersonateAccount", "hardhat_impersonateAccount"} {
+		err := rpcClient.CallContext(ctx, nil, method, address)
+		if err == nil {
+			return nil
+		}
+		if !isMethodNotFound(err) {
+			return fmt.Errorf("%v fail: %w", method, err)
+		}
+	}
+	return fmt.Errorf("impersonating account is not supported by node, anvil or hardhat node is required")
+}
+
+// ForkSendTransaction sends tx from impersonated (or unlocked) account of fork node by eth_sendTransaction.
+func ForkSendTransaction(ctx context.Context, rpcClient *rpc.Client, args CallArgs) (common.Hash, error) {
+	var txHash common.Hash
+	if err := rpcClient.CallContext(ctx, &txHash, "eth_sendTransaction", args); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendTransaction fail: %w", err)
+	}
+	return txHash, nil
+}
+
+// SlotDiff is the change of a storage slot.
+type SlotDiff struct {
+	Slot   common.Hash
+	Before common.Hash
+	After  common.Hash
+}
+
+// AccountDiff is the state change of an account made by a tx.
+type AccountDiff struct {
+	Address       common.Address
+	BalanceBefore *big.Int
+	BalanceAfter  *big.Int
+	NonceBefore   uint64
+	NonceAfter    uint64
+	CodeChanged   bool
+	Storage       []SlotDiff
+}
+
+// prestateAccount is an account in result of prestateTracer.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   *uint64                     `json:"nonce"`
+	Code    *hexutil.Bytes              `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// TraceStateDiff returns the state changes made by mined tx, by prestateTracer in diff mode.
+func TraceStateDiff(ctx context.Context, rpcClient *rpc.Client, txHash common.Hash) ([]AccountDiff, error) {
+	var result json.RawMessage
+	config := map[string]any{"tracer": TracerPrestate, "tracerConfig": map[string]any{"diffMode": true}}
+	if err := rpcClient.CallContext(ctx, &result, "debug_traceTransaction", txHash, config); err != nil {
+		return nil, fmt.Errorf("debug_traceTransaction fail: %w", err)
+	}
+	return parseStateDiff(result)
+}
+
+// parseStateDiff parses result of prestateTracer in diff mode. pre has the state before tx of modified accounts,
+// post has the modified fields only, a storage slot in pre but not in post of a modified account is cleared.
+func parseStateDiff(result []byte) ([]AccountDiff, error) {
+	var diff struct {
+		Pre  map[common.Address]prestateAccount `json:"pre"`
+		Post map[common.Address]prestateAccount `json:"post"`
+	}
+	if err := json.Unmarshal(result, &diff); err != nil {
+		return nil, fmt.Errorf("parse result of prestateTracer fail: %w", err)
+	}
+
+	var addresses []common.Address
+	for address := range diff.Pre {
+		addresses = append(addresses, address)
+	}
+	for address := range diff.Post {
+		if _, ok := diff.Pre[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool { return bytes.Compare(addresses[i][:], addresses[j][:]) < 0 })
+
+	var diffs []AccountDiff
+	for _, address := range addresses {
+		pre, post := diff.Pre[address], diff.Post[address]
+		_, modified := diff.Post[address]
+		accountDiff := AccountDiff{Address: address, BalanceBefore: new(big.Int)}
+		if pre.Balance != nil {
+			accountDiff.BalanceBefore = pre.Balance.ToInt()
+		}
+		accountDiff.BalanceAfter = accountDiff.BalanceBefore
+		if post.Balance != nil {
+			accountDiff.BalanceAfter = post.Balance.ToInt()
+		}
+		if pre.Nonce != nil {
+			accountDiff.NonceBefore = *pre.Nonce
+		}
+		accountDiff.NonceAfter = accountDiff.NonceBefore
+		if post.Nonce != nil {
+			accountDiff.NonceAfter = *post.Nonce
+		}
+		accountDiff.CodeChanged = post.Code != nil && (pre.Code == nil || !bytes.Equal(*pre.Code, *post.Code))
+
+		var slots []common.Hash
+		for slot := range pre.Storage {
+			slots = append(slots, slot)
+		}
+		for slot := range post.Storage {
+			if _, ok := pre.Storage[slot]; !ok {
+				slots = append(slots, slot)
+			}
+		}
+		sort.Slice(slots, func(i, j int) bool { return bytes.Compare(slots[i][:], slots[j][:]) < 0 })
+		for _, slot := range slots {
+			before := pre.Storage[slot]
+			after, ok := post.Storage[slot]
+			if !ok && !modified {
+				after = before
+			}
+			if before != after {
+				accountDiff.Storage = append(accountDiff.Storage, SlotDiff{Slot: slot, Before: before, After: after})
+			}
+		}
+
+		if accountDiff.BalanceBefore.Cmp(accountDiff.BalanceAfter) != 0 || accountDiff.NonceBefore != accountDiff.NonceAfter ||
+			accountDiff.CodeChanged || len(accountDiff.Storage) > 0 {
+			diffs = append(diffs, accountDiff)
+		}
+	}
+	return diffs, nil
+}
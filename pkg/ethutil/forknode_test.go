@@ -0,0 +1,51 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseStateDiff(t *testing.T) {
+	// prestateTracer diffMode result of an ERC20 transfer: sender pays fee, token balances of sender and receiver
+	// change, the balance slot of sender is cleared.
+	var result = `{
+		"pre": {
+			"0x24f8209ec5f56a07c94e834627f0651c19aca0ac": {"balance": "0xde0b6b3a7640000", "nonce": 3},
+			"0xdac17f958d2ee523a2206206994597c13d831ec7": {"balance": "0x0", "nonce": 1, "code": "0x6080",
+				"storage": {"0x0000000000000000000000000000000000000000000000000000000000000001": "0x0000000000000000000000000000000000000000000000000000000000000064"}},
+			"0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5": {"balance": "0x1"}
+		},
+		"post": {
+			"0x24f8209ec5f56a07c94e834627f0651c19aca0ac": {"balance": "0xde0b6b3a763fc18", "nonce": 4},
+			"0xdac17f958d2ee523a2206206994597c13d831ec7": {
+				"storage": {"0x0000000000000000000000000000000000000000000000000000000000000002": "0x0000000000000000000000000000000000000000000000000000000000000064"}},
+			"0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5": {"balance": "0x3e9"}
+		}
+	}`
+
+	diffs, err := parseStateDiff([]byte(result))
+	if err != nil {
+		t.Fatalf("parseStateDiff fail: %v", err)
+	}
+	if len(diffs) != 3 {
+		t.Fatalf("expected: %v diffs, got: %v", 3, len(diffs))
+	}
+
+	sender := diffs[0]
+	if sender.Address != common.HexToAddress("0x24f8209ec5f56a07c94e834627f0651c19aca0ac") || sender.NonceBefore != 3 || sender.NonceAfter != 4 ||
+		sender.BalanceBefore.Int64()-sender.BalanceAfter.Int64() != 1000 {
+		t.Fatalf("unexpected diff of sender: %+v", sender)
+	}
+	coinbase := diffs[1]
+	if coinbase.BalanceBefore.Int64() != 1 || coinbase.BalanceAfter.Int64() != 1001 {
+		t.Fatalf("unexpected diff of coinbase: %+v", coinbase)
+	}
+	token := diffs[2]
+	if token.CodeChanged || token.NonceBefore != token.NonceAfter || len(token.Storage) != 2 {
+		t.Fatalf("unexpected diff of token: %+v", token)
+	}
+	if token.Storage[0].After != (common.Hash{}) || token.Storage[1].Before != (common.Hash{}) || token.Storage[1].After.Big().Int64() != 100 {
+		t.Fatalf("unexpected storage diff of token: %+v", token.Storage)
+	}
+}
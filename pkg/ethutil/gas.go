@@ -0,0 +1,31 @@
+package ethutil
+
+import (
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// IntrinsicGasBreakdown is the intrinsic gas of a tx (the gas charged before any code is executed) and its components.
+type IntrinsicGasBreakdown struct {
+	Base          uint64 // 21000, or 53000 for contract creation
+	ZeroBytes     uint64 // number of zero bytes in calldata
+	NonZeroBytes  uint64 // number of non-zero bytes in calldata
+	CalldataGas   uint64
+	AccessListGas uint64
+	InitCodeGas   uint64 // eip3860, only for contract creation
+	Total         uint64
+	FloorGas      uint64 // eip7623, the min gas used by tx with calldata, 0 before prague
+}
+
+// MinGasLimit returns the min gas limit of tx, which is the larger of Total and FloorGas.
+func (b IntrinsicGasBreakdown) MinGasLimit() uint64 {
+	if b.FloorGas > b.Total {
+		return b.FloorGas
+	}
+	return b.Total
+}
+
+// IntrinsicGas computes intrinsic gas of a tx with data and accessList under the rules of LatestGasFork, use
+// GasSchedule.IntrinsicGas for other forks.
+func IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation bool) IntrinsicGasBreakdown {
+	return LatestGasSchedule.IntrinsicGas(data, accessList, isContractCreation)
+}
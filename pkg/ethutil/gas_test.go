@@ -0,0 +1,134 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestIntrinsicGas(t *testing.T) {
+	tests := []struct {
+		data               []byte
+		accessList         types.AccessList
+		isContractCreation bool
+		want               uint64
+	}{
+		{
+			data: nil,
+			want: 21000,
+		},
+		{
+			data: []byte{0x00, 0x01, 0x00, 0x02},
+			want: 21000 + 2*4 + 2*16,
+		},
+		{
+			data:       []byte{0x01},
+			accessList: types.AccessList{{Address: common.Address{}, StorageKeys: []common.Hash{{}, {}}}},
+			want:       21000 + 16 + 2400 + 2*1900,
+		},
+		{
+			data:               make([]byte, 33),
+			isContractCreation: true,
+			want:               53000 + 33*4 + 2*2,
+		},
+	}
+
+	for i, tt := range tests {
+		output := IntrinsicGas(tt.data, tt.accessList, tt.isContractCreation)
+		if output.Total != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output.Total)
+		}
+	}
+}
+
+func TestGasScheduleIntrinsicGas(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x00, 0x02}
+	tests := []struct {
+		fork               string
+		data               []byte
+		isContractCreation bool
+		want               uint64
+		wantFloor          uint64
+	}{
+		{"frontier", data, false, 21000 + 2*4 + 2*68, 0},
+		{"frontier", nil, true, 21000, 0},
+		{"homestead", nil, true, 53000, 0},
+		{"istanbul", data, false, 21000 + 2*4 + 2*16, 0},
+		{"london", make([]byte, 33), true, 53000 + 33*4, 0},
+		{"shanghai", make([]byte, 33), true, 53000 + 33*4 + 2*2, 0},
+		{"prague", data, false, 21000 + 2*4 + 2*16, 21000 + (2+2*4)*10},
+		{"prague", nil, false, 21000, 21000},
+	}
+	for _, tt := range tests {
+		schedule, err := GasScheduleOf(tt.fork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		output := schedule.IntrinsicGas(tt.data, nil, tt.isContractCreation)
+		if output.Total != tt.want || output.FloorGas != tt.wantFloor {
+			t.Errorf("%v: IntrinsicGas() = %v floor %v, want %v floor %v", tt.fork, output.Total, output.FloorGas, tt.want, tt.wantFloor)
+		}
+	}
+	if _, err := GasScheduleOf("osaka2"); err == nil {
+		t.Errorf("GasScheduleOf() unknown fork, expect error")
+	}
+}
+
+func TestForkAt(t *testing.T) {
+	tests := []struct {
+		chainId     uint64
+		blockNumber uint64
+		time        uint64
+		want        string
+	}{
+		{1, 0, 0, "frontier"},
+		{1, 1150000, 0, "homestead"},
+		{1, 13000000, 1600000000, "london"},
+		{1, 17034870, 1681338455, "shanghai"},
+		{1, 20000000, 1746612310, "cancun"},
+		{1, 22431084, 1746612311, "prague"},
+		{11155111, 1000, 1600000000, "london"},
+		{11155111, 8000000, 1750000000, "prague"},
+		{12345, 0, 0, LatestGasFork},
+	}
+	for _, tt := range tests {
+		if got := ForkAt(tt.chainId, tt.blockNumber, tt.time); got != tt.want {
+			t.Errorf("ForkAt(%v, %v, %v) = %v, want %v", tt.chainId, tt.blockNumber, tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestGasScheduleValidateTx(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	calldata := make([]byte, 100)
+	for i := range calldata {
+		calldata[i] = 1
+	}
+	tests := []struct {
+		fork    string
+		tx      *types.Transaction
+		wantErr bool
+	}{
+		{"cancun", types.NewTx(&types.LegacyTx{To: &to, Gas: 21000}), false},
+		{"cancun", types.NewTx(&types.LegacyTx{To: &to, Gas: 20999}), true},
+		// 100 non-zero bytes cost 1600 gas, but the floor of prague is 4000
+		{"cancun", types.NewTx(&types.LegacyTx{To: &to, Gas: 22600, Data: calldata}), false},
+		{"prague", types.NewTx(&types.LegacyTx{To: &to, Gas: 22600, Data: calldata}), true},
+		{"prague", types.NewTx(&types.LegacyTx{To: &to, Gas: 25000, Data: calldata}), false},
+		{"shanghai", types.NewTx(&types.LegacyTx{Gas: 10000000, Data: make([]byte, 49153)}), true},
+		{"london", types.NewTx(&types.LegacyTx{Gas: 10000000, Data: make([]byte, 49153)}), false},
+		{"istanbul", types.NewTx(&types.AccessListTx{To: &to, Gas: 21000}), true},
+		{"berlin", types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000}), true},
+		{"london", types.NewTx(&types.DynamicFeeTx{To: &to, Gas: 21000}), false},
+	}
+	for i, tt := range tests {
+		schedule, err := GasScheduleOf(tt.fork)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := schedule.ValidateTx(tt.tx); (err != nil) != tt.wantErr {
+			t.Errorf("test %d: ValidateTx() error = %v, wantErr %v", i, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ethutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Sources of max priority fee per gas estimation
+const (
+	FeeSourceMaxPriorityFeePerGas = "eth_maxPriorityFeePerGas"
+	FeeSourceFeeHistory           = "eth_feeHistory"
+	FeeSourceFloor                = "floor"
+	FeeSourceGasPrice             = "eth_gasPrice"
+)
+
+// ErrEip1559NotSupported is returned by GasOracle if the chain does not support eip1559, i.e. no base fee in block.
+var ErrEip1559NotSupported = errors.New("eip1559 is not supported by chain")
+
+// GasOracle estimates eip1559 fees.
+type GasOracle interface {
+	EstimateFees(ctx context.Context) (*FeeEstimate, error)
+}
+
+// DefaultGasOracle estimates max priority fee per gas by eth_maxPriorityFeePerGas, eth_feeHistory percentiles,
+// FloorTip and eth_gasPrice minus base fee in order, i.e. a later one is used only if the former ones are unavailable.
+// If both eth_maxPriorityFeePerGas and eth_feeHistory are available, the former is used as the average estimation,
+// and the latter is used as the slow and fast estimations. All estimations are raised to FloorTip if they are lower.
+type DefaultGasOracle struct {
+	Client   *ethclient.Client
+	FloorTip *big.Int // nil means no floor
+
+	Logf func(format string, v ...any) // receives the reason of falling back to FloorTip, nil means silent
+}
+
+func (o *DefaultGasOracle) EstimateFees(ctx context.Context) (*FeeEstimate, error) {
+	var estimate FeeEstimate
+
+	pendingBlock, err := o.Client.BlockByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("BlockByNumber fail: %w", err)
+	}
+	if pendingBlock.BaseFee() == nil {
+		return nil, fmt.Errorf("base fee not found in block %v: %w", pendingBlock.Number(), ErrEip1559NotSupported)
+	}
+	estimate.BaseFee = pendingBlock.BaseFee()
+
+	tip, tipErr := o.Client.SuggestGasTipCap(ctx) // eth_maxPriorityFeePerGas
+	slow, average, fast, historyErr := feeHistoryTips(ctx, o.Client)
+
+	switch {
+	case tipErr == nil && historyErr == nil:
+		estimate.Slow, estimate.Average, estimate.Fast = minBigInt(slow, tip), tip, maxBigInt(fast, tip)
+		estimate.Source = FeeSourceMaxPriorityFeePerGas
+	case tipErr == nil:
+		estimate.Slow, estimate.Average, estimate.Fast = tip, tip, tip
+		estimate.Source = FeeSourceMaxPriorityFeePerGas
+	case historyErr == nil:
+		estimate.Slow, estimate.Average, estimate.Fast = slow, average, fast
+		estimate.Source = FeeSourceFeeHistory
+	case o.FloorTip != nil:
+		if o.Logf != nil {
+			o.Logf("eth_maxPriorityFeePerGas fail: %v, %v, use floor %v wei", tipErr, historyErr, o.FloorTip)
+		}
+		estimate.Slow, estimate.Average, estimate.Fast = o.FloorTip, o.FloorTip, o.FloorTip
+		estimate.Source = FeeSourceFloor
+	default:
+		gasPrice, err := o.Client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("estimate max priority fee per gas fail, eth_maxPriorityFeePerGas: %v, %v, eth_gasPrice: %w", tipErr, historyErr, err)
+		}
+		tip = new(big.Int).Sub(gasPrice, estimate.BaseFee)
+		if tip.Sign() < 0 {
+			tip = new(big.Int)
+		}
+		estimate.Slow, estimate.Average, estimate.Fast = tip, tip, tip
+		estimate.Source = FeeSourceGasPrice
+	}
+
+	if o.FloorTip != nil {
+		estimate.Slow = maxBigInt(estimate.Slow, o.FloorTip)
+		estimate.Average = maxBigInt(estimate.Average, o.FloorTip)
+		estimate.Fast = maxBigInt(estimate.Fast, o.FloorTip)
+	}
+
+	return &estimate, nil
+}
+
+func minBigInt(x, y *big.Int) *big.Int {
+	if x.Cmp(y) < 0 {
+		return x
+	}
+	return y
+}
+
+func maxBigInt(x, y *big.Int) *big.Int {
+	if x.Cmp(y) > 0 {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,180 @@
+package ethutil
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GasForks are the hardforks of mainnet in activation order, GasScheduleOf accepts any of them.
+var GasForks = []string{"frontier", "homestead", "byzantium", "constantinople", "petersburg", "istanbul", "berlin",
+	"london", "paris", "shanghai", "cancun", "prague"}
+
+// LatestGasFork is the fork assumed for chains without known fork activations.
+const LatestGasFork = "prague"
+
+// GasSchedule is the tx level gas constants of a hardfork, used by intrinsic gas calculation and tx validation.
+type GasSchedule struct {
+	Fork                      string
+	TxGas                     uint64
+	TxGasContractCreation     uint64
+	TxDataZeroGas             uint64
+	TxDataNonZeroGas          uint64 // 68, 16 since istanbul (eip2028)
+	TxAccessListAddressGas    uint64 // 0 before berlin (eip2930)
+	TxAccessListStorageKeyGas uint64 // 0 before berlin (eip2930)
+	InitCodeWordGas           uint64 // 0 before shanghai (eip3860)
+	MaxInitCodeSize           int    // 0 means no limit, before shanghai (eip3860)
+	TxCostFloorPerToken       uint64 // 0 before prague (eip7623), a token is a zero byte or a quarter of non-zero byte
+	AccessListTx              bool   // eip2930 tx is supported, since berlin
+	DynamicFeeTx              bool   // eip1559 tx is supported, since london
+}
+
+// LatestGasSchedule is the gas schedule of LatestGasFork.
+var LatestGasSchedule, _ = GasScheduleOf(LatestGasFork)
+
+// GasScheduleOf returns gas schedule of fork, which is one of GasForks.
+func GasScheduleOf(fork string) (GasSchedule, error) {
+	index := -1
+	for i, f := range GasForks {
+		if f == fork {
+			index = i
+		}
+	}
+	if index < 0 {
+		return GasSchedule{}, fmt.Errorf("unknown fork %v", fork)
+	}
+	activated := func(f string) bool {
+		for _, g := range GasForks[:index+1] {
+			if g == f {
+				return true
+			}
+		}
+		return false
+	}
+
+	s := GasSchedule{Fork: fork, TxGas: 21000, TxGasContractCreation: 21000, TxDataZeroGas: 4, TxDataNonZeroGas: 68}
+	if activated("homestead") {
+		s.TxGasContractCreation = 53000
+	}
+	if activated("istanbul") {
+		s.TxDataNonZeroGas = 16
+	}
+	if activated("berlin") {
+		s.TxAccessListAddressGas = 2400
+		s.TxAccessListStorageKeyGas = 1900
+		s.AccessListTx = true
+	}
+	if activated("london") {
+		s.DynamicFeeTx = true
+	}
+	if activated("shanghai") {
+		s.InitCodeWordGas = 2
+		s.MaxInitCodeSize = 2 * 24576
+	}
+	if activated("prague") {
+		s.TxCostFloorPerToken = 10
+	}
+	return s, nil
+}
+
+// ForkActivation is the activation of fork, by block number before paris and by timestamp since shanghai.
+type ForkActivation struct {
+	Fork  string
+	Block uint64
+	Time  uint64
+}
+
+// KnownForkActivations are the activations of GasForks on mainnet and testnets keyed by chain id, forks without gas
+// changes are omitted.
+var KnownForkActivations = map[uint64][]ForkActivation{
+	1: {
+		{Fork: "frontier"},
+		{Fork: "homestead", Block: 1150000},
+		{Fork: "istanbul", Block: 9069000},
+		{Fork: "berlin", Block: 12244000},
+		{Fork: "london", Block: 12965000},
+		{Fork: "shanghai", Time: 1681338455},
+		{Fork: "cancun", Time: 1710338135},
+		{Fork: "prague", Time: 1746612311},
+	},
+	11155111: {
+		{Fork: "london"},
+		{Fork: "shanghai", Time: 1677557088},
+		{Fork: "cancun", Time: 1706655072},
+		{Fork: "prague", Time: 1741159776},
+	},
+	17000: {
+		{Fork: "shanghai"},
+		{Fork: "cancun", Time: 1707305664},
+		{Fork: "prague", Time: 1740434112},
+	},
+}
+
+// ForkAt returns the fork of chain at block with timestamp, LatestGasFork if activations of chain are unknown.
+func ForkAt(chainId uint64, blockNumber uint64, time uint64) string {
+	activations, ok := KnownForkActivations[chainId]
+	if !ok {
+		return LatestGasFork
+	}
+	fork := activations[0].Fork
+	for _, activation := range activations[1:] {
+		if (activation.Time > 0 && time >= activation.Time) || (activation.Time == 0 && blockNumber >= activation.Block) {
+			fork = activation.Fork
+		}
+	}
+	return fork
+}
+
+// IntrinsicGas computes intrinsic gas of a tx with data and accessList under the schedule.
+func (s GasSchedule) IntrinsicGas(data []byte, accessList types.AccessList, isContractCreation bool) IntrinsicGasBreakdown {
+	var breakdown IntrinsicGasBreakdown
+	if isContractCreation {
+		breakdown.Base = s.TxGasContractCreation
+		breakdown.InitCodeGas = (uint64(len(data)) + 31) / 32 * s.InitCodeWordGas
+	} else {
+		breakdown.Base = s.TxGas
+	}
+
+	for _, b := range data {
+		if b == 0 {
+			breakdown.ZeroBytes++
+		} else {
+			breakdown.NonZeroBytes++
+		}
+	}
+	breakdown.CalldataGas = breakdown.ZeroBytes*s.TxDataZeroGas + breakdown.NonZeroBytes*s.TxDataNonZeroGas
+
+	breakdown.AccessListGas = uint64(len(accessList))*s.TxAccessListAddressGas +
+		uint64(accessList.StorageKeys())*s.TxAccessListStorageKeyGas
+
+	breakdown.Total = breakdown.Base + breakdown.CalldataGas + breakdown.AccessListGas + breakdown.InitCodeGas
+	if s.TxCostFloorPerToken > 0 {
+		tokens := breakdown.ZeroBytes + breakdown.NonZeroBytes*4
+		breakdown.FloorGas = s.TxGas + tokens*s.TxCostFloorPerToken
+	}
+	return breakdown
+}
+
+// ValidateTx checks tx against the schedule: tx type is supported, gas limit covers intrinsic gas (and the calldata
+// floor of eip7623), and init code doesn't exceed the size limit of eip3860.
+func (s GasSchedule) ValidateTx(tx *types.Transaction) error {
+	switch tx.Type() {
+	case types.AccessListTxType:
+		if !s.AccessListTx {
+			return fmt.Errorf("eip2930 tx is not supported before berlin, fork is %v", s.Fork)
+		}
+	case types.DynamicFeeTxType:
+		if !s.DynamicFeeTx {
+			return fmt.Errorf("eip1559 tx is not supported before london, fork is %v", s.Fork)
+		}
+	}
+	isContractCreation := tx.To() == nil
+	if isContractCreation && s.MaxInitCodeSize > 0 && len(tx.Data()) > s.MaxInitCodeSize {
+		return fmt.Errorf("init code size %v exceeds limit %v", len(tx.Data()), s.MaxInitCodeSize)
+	}
+	intrinsic := s.IntrinsicGas(tx.Data(), tx.AccessList(), isContractCreation)
+	if required := intrinsic.MinGasLimit(); tx.Gas() < required {
+		return fmt.Errorf("gas limit %v is less than intrinsic gas %v (fork %v)", tx.Gas(), required, s.Fork)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ethutil
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// GenesisForks are the forks supported by NewGenesisChainConfig, in activation order.
+var GenesisForks = []string{"london", "paris", "shanghai", "cancun"}
+
+// Create2DeployerAddress is the address of deterministic deployment proxy (https://github.com/Arachnid/deterministic-deployment-proxy),
+// it's deployed at the same address in most chains.
+var Create2DeployerAddress = common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+
+// Create2DeployerCode is the runtime code of deterministic deployment proxy.
+var Create2DeployerCode = hexutil.MustDecode("0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe03601600081602082378035828234f58015156039578182fd5b8082525050506014600cf3")
+
+// GenesisAccount is an account in alloc of genesis.
+type GenesisAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Code    hexutil.Bytes               `json:"code,omitempty"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"`
+	Nonce   hexutil.Uint64              `json:"nonce,omitempty"`
+}
+
+// Genesis is genesis.json accepted by geth init and reth.
+type Genesis struct {
+	Config     *params.ChainConfig                `json:"config"`
+	Nonce      hexutil.Uint64                     `json:"nonce"`
+	Timestamp  hexutil.Uint64                     `json:"timestamp"`
+	ExtraData  hexutil.Bytes                      `json:"extraData"`
+	GasLimit   hexutil.Uint64                     `json:"gasLimit"`
+	Difficulty *hexutil.Big                       `json:"difficulty"`
+	MixHash    common.Hash                        `json:"mixHash"`
+	Coinbase   common.Address                     `json:"coinbase"`
+	Alloc      map[common.Address]*GenesisAccount `json:"alloc"`
+}
+
+// NewGenesisChainConfig returns chain config which activates all forks up to fork at genesis.
+func NewGenesisChainConfig(chainID *big.Int, fork string) (*params.ChainConfig, error) {
+	var forkIndex = -1
+	for i, f := range GenesisForks {
+		if f == fork {
+			forkIndex = i
+		}
+	}
+	if forkIndex < 0 {
+		return nil, fmt.Errorf("unsupported fork %v", fork)
+	}
+
+	var zero = big.NewInt(0)
+	var zeroTime = uint64(0)
+	config := &params.ChainConfig{
+		ChainID:             chainID,
+		HomesteadBlock:      zero,
+		EIP150Block:         zero,
+		EIP155Block:         zero,
+		EIP158Block:         zero,
+		ByzantiumBlock:      zero,
+		ConstantinopleBlock: zero,
+		PetersburgBlock:     zero,
+		IstanbulBlock:       zero,
+		MuirGlacierBlock:    zero,
+		BerlinBlock:         zero,
+		LondonBlock:         zero,
+		ArrowGlacierBlock:   zero,
+		GrayGlacierBlock:    zero,
+	}
+	if forkIndex >= 1 { // paris
+		config.TerminalTotalDifficulty = zero
+		config.TerminalTotalDifficultyPassed = true
+	}
+	if forkIndex >= 2 {
+		config.ShanghaiTime = &zeroTime
+	}
+	if forkIndex >= 3 {
+		config.CancunTime = &zeroTime
+	}
+	return config, nil
+}
+
+// AddAlloc adds balance to address in alloc of genesis.
+func (g *Genesis) AddAlloc(address common.Address, balance *big.Int) {
+	if g.Alloc == nil {
+		g.Alloc = make(map[common.Address]*GenesisAccount)
+	}
+	account, ok := g.Alloc[address]
+	if !ok {
+		account = &GenesisAccount{Balance: (*hexutil.Big)(big.NewInt(0))}
+		g.Alloc[address] = account
+	}
+	account.Balance = (*hexutil.Big)(new(big.Int).Add(account.Balance.ToInt(), balance))
+}
+
+// AddPredeploy deploys code at address in genesis, the balance of address is kept.
+func (g *Genesis) AddPredeploy(address common.Address, code []byte) {
+	g.AddAlloc(address, big.NewInt(0))
+	g.Alloc[address].Code = code
+	g.Alloc[address].Nonce = 1 // EIP-161, nonce of contract starts at 1
+}
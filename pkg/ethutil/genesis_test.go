@@ -0,0 +1,61 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewGenesisChainConfig(t *testing.T) {
+	tests := []struct {
+		fork     string
+		merged   bool
+		shanghai bool
+		cancun   bool
+	}{
+		{"london", false, false, false},
+		{"paris", true, false, false},
+		{"shanghai", true, true, false},
+		{"cancun", true, true, true},
+	}
+
+	for i, test := range tests {
+		config, err := NewGenesisChainConfig(big.NewInt(1337), test.fork)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if !config.IsLondon(common.Big0) {
+			t.Fatalf("test %d: expected: london is activated, got: not activated", i)
+		}
+		if merged := config.TerminalTotalDifficultyPassed; merged != test.merged {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.merged, merged)
+		}
+		if shanghai := config.IsShanghai(0); shanghai != test.shanghai {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.shanghai, shanghai)
+		}
+		if cancun := config.IsCancun(0); cancun != test.cancun {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.cancun, cancun)
+		}
+	}
+
+	if _, err := NewGenesisChainConfig(big.NewInt(1337), "frontier"); err == nil {
+		t.Fatalf("expected: error for unsupported fork, got: nil")
+	}
+}
+
+func TestGenesisAddAlloc(t *testing.T) {
+	var genesis Genesis
+	var address = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	genesis.AddAlloc(address, big.NewInt(100))
+	genesis.AddAlloc(address, big.NewInt(20))
+	genesis.AddPredeploy(address, []byte{0x00})
+
+	account := genesis.Alloc[address]
+	if account.Balance.ToInt().Int64() != 120 {
+		t.Fatalf("expected: %v, got: %v", 120, account.Balance.ToInt())
+	}
+	if len(account.Code) != 1 || account.Nonce != 1 {
+		t.Fatalf("expected: code is set and nonce is 1, got: code %x, nonce %v", account.Code, account.Nonce)
+	}
+}
@@ -0,0 +1,183 @@
+package ethutil
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HashAlgorithms are the algorithms supported by Hash
+var HashAlgorithms = []string{"keccak256", "sha256", "ripemd160"}
+
+// Hash computes hash of data by algorithm, which is one of HashAlgorithms.
+func Hash(algorithm string, data []byte) ([]byte, error) {
+	switch strings.ToLower(algorithm) {
+	case "keccak256", "keccak":
+		return crypto.Keccak256(data), nil
+	case "sha256":
+		sum := sha256.Sum256(data)
+		return sum[:], nil
+	case "ripemd160":
+		hasher := ripemd160.New()
+		hasher.Write(data)
+		return hasher.Sum(nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %v, supported: %v", algorithm, strings.Join(HashAlgorithms, ", "))
+	}
+}
+
+var signatureNameRe = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+var arraySuffixRe = regexp.MustCompile(`^(\[[0-9]*\])*$`)
+
+// CanonicalSignature converts human-readable function, event or error signature to the canonical form used to compute
+// selector and topic, i.e. name and types of parameters without spaces, parameter names and modifiers.
+// Example:
+// input: "function transfer(address to, uint amount) external returns (bool)"
+// output: "transfer(address,uint256)"
+//
+// input: "event Transfer(address indexed from, address indexed to, uint256 value)"
+// output: "Transfer(address,address,uint256)"
+//
+// input: "submit((address to, uint256 value)[] calls)"
+// output: "submit((address,uint256)[])"
+func CanonicalSignature(signature string) (string, error) {
+	input := strings.TrimSpace(signature)
+	for _, keyword := range []string{"function ", "event ", "error "} {
+		input = strings.TrimSpace(strings.TrimPrefix(input, keyword))
+	}
+
+	leftParenthesisLoc := strings.Index(input, "(")
+	if leftParenthesisLoc < 0 {
+		return "", fmt.Errorf("char ( is not found in signature `%v`", signature)
+	}
+	name := strings.TrimSpace(input[:leftParenthesisLoc])
+	if !signatureNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid name `%v` in signature `%v`", name, signature)
+	}
+	rightParenthesisLoc := matchingParenthesis(input, leftParenthesisLoc)
+	if rightParenthesisLoc < 0 {
+		return "", fmt.Errorf("char ) is not found in signature `%v`", signature)
+	}
+	// anything after parameters, e.g. "external view returns (uint256)" or "anonymous", is ignored
+
+	params, err := canonicalParams(input[leftParenthesisLoc+1 : rightParenthesisLoc])
+	if err != nil {
+		return "", fmt.Errorf("invalid signature `%v`: %w", signature, err)
+	}
+	return name + params, nil
+}
+
+// canonicalParams returns canonical form of comma separated parameters (without the enclosing parentheses), e.g.
+// "address indexed from, uint value" -> "(address,uint256)"
+func canonicalParams(input string) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		return "()", nil
+	}
+	var types []string
+	for _, param := range splitTopLevel(input) {
+		typ, err := canonicalParamType(strings.TrimSpace(param))
+		if err != nil {
+			return "", err
+		}
+		types = append(types, typ)
+	}
+	return "(" + strings.Join(types, ",") + ")", nil
+}
+
+// canonicalParamType returns canonical type of one parameter, e.g. "uint[] memory amounts" -> "uint256[]"
+func canonicalParamType(param string) (string, error) {
+	if param == "" {
+		return "", fmt.Errorf("empty parameter")
+	}
+	param = strings.TrimSpace(strings.TrimPrefix(param, "tuple"))
+	if strings.HasPrefix(param, "(") { // tuple
+		end := matchingParenthesis(param, 0)
+		if end < 0 {
+			return "", fmt.Errorf("char ) is not found in tuple `%v`", param)
+		}
+		components, err := canonicalParams(param[1:end])
+		if err != nil {
+			return "", err
+		}
+		var suffix string
+		if fields := strings.Fields(param[end+1:]); len(fields) > 0 && strings.HasPrefix(fields[0], "[") {
+			suffix = fields[0]
+		}
+		if !arraySuffixRe.MatchString(suffix) {
+			return "", fmt.Errorf("invalid array suffix `%v` of tuple", suffix)
+		}
+		return components + suffix, nil
+	}
+
+	fields := strings.Fields(param)
+	typ := fields[0]
+	if len(fields) >= 2 && typ == "address" && strings.HasPrefix(fields[1], "payable") {
+		typ += strings.TrimPrefix(fields[1], "payable") // "address payable[] a" -> "address[]"
+	}
+	typ = typeNormalize(typ)
+	if _, err := abi.NewType(typ, "", nil); err != nil {
+		return "", fmt.Errorf("invalid type `%v`: %w", typ, err)
+	}
+	return typ, nil
+}
+
+// matchingParenthesis returns index of ')' which closes '(' at index start of input, -1 if not found.
+func matchingParenthesis(input string, start int) int {
+	depth := 0
+	for i := start; i < len(input); i++ {
+		switch input[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits input by commas which are not inside parentheses.
+func splitTopLevel(input string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(input); i++ {
+		switch input[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, input[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, input[start:])
+}
+
+// FuncSelector returns 4 bytes selector of function (or custom error) signature, and the canonical signature.
+func FuncSelector(signature string) ([]byte, string, error) {
+	canonical, err := CanonicalSignature(signature)
+	if err != nil {
+		return nil, "", err
+	}
+	return crypto.Keccak256([]byte(canonical))[:4], canonical, nil
+}
+
+// EventTopic returns topic0 (hash of event signature), and the canonical signature.
+func EventTopic(signature string) (common.Hash, string, error) {
+	canonical, err := CanonicalSignature(signature)
+	if err != nil {
+		return common.Hash{}, "", err
+	}
+	return crypto.Keccak256Hash([]byte(canonical)), canonical, nil
+}
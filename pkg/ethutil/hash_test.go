@@ -0,0 +1,89 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		data      string
+		expected  string
+	}{
+		{"keccak256", "", "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"sha256", "abc", "0xba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"ripemd160", "abc", "0x8eb208f7e05d987a9b044a8e98c6b087f15a0bfc"},
+	}
+
+	for i, test := range tests {
+		hash, err := Hash(test.algorithm, []byte(test.data))
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if got := hexutil.Encode(hash); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+
+	if _, err := Hash("md5", nil); err == nil {
+		t.Fatalf("expected error for unsupported algorithm")
+	}
+}
+
+func TestFuncSelector(t *testing.T) {
+	tests := []struct {
+		signature string
+		canonical string
+		selector  string
+	}{
+		{"transfer(address,uint256)", "transfer(address,uint256)", "0xa9059cbb"},
+		{"function transfer(address to, uint amount) external returns (bool)", "transfer(address,uint256)", "0xa9059cbb"},
+		{"balanceOf(address)", "balanceOf(address)", "0x70a08231"},
+		{"totalSupply()", "totalSupply()", "0x18160ddd"},
+		{"error Error(string)", "Error(string)", "0x08c379a0"},
+		{"aggregate((address target, bytes callData)[] calls)", "aggregate((address,bytes)[])", "0x252dba42"},
+		{"f(address payable[] memory a, uint b)", "f(address[],uint256)", ""},
+	}
+
+	for i, test := range tests {
+		selector, canonical, err := FuncSelector(test.signature)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if canonical != test.canonical {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.canonical, canonical)
+		}
+		if test.selector != "" && hexutil.Encode(selector) != test.selector {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.selector, hexutil.Encode(selector))
+		}
+	}
+
+	for i, signature := range []string{"transfer", "transfer(adress,uint256)", "(address)", "f((uint256,address)"} {
+		if _, _, err := FuncSelector(signature); err == nil {
+			t.Fatalf("test %d: expected error for %v", i, signature)
+		}
+	}
+}
+
+func TestEventTopic(t *testing.T) {
+	tests := []struct {
+		signature string
+		expected  string
+	}{
+		{"Transfer(address,address,uint256)", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{"event Transfer(address indexed from, address indexed to, uint256 value)", "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{"event Approval(address indexed owner, address indexed spender, uint value)", "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"},
+	}
+
+	for i, test := range tests {
+		topic, _, err := EventTopic(test.signature)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if topic.Hex() != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, topic.Hex())
+		}
+	}
+}
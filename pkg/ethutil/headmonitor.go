@@ -0,0 +1,137 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Kinds of HeadAlert.
+const (
+	HeadAlertUnreachable = "unreachable"
+	HeadAlertLagging     = "lagging"
+	HeadAlertDivergent   = "divergent"
+)
+
+// HeadStatus is the head block of an endpoint, and its hash of the reference block compared across endpoints.
+type HeadStatus struct {
+	Endpoint  string
+	Number    uint64
+	Hash      common.Hash
+	RefNumber uint64
+	RefHash   common.Hash
+	Err       error // endpoint is unreachable if it's not nil
+}
+
+// HeadAlert is an endpoint which is unreachable, lags behind the highest head, or serves a block hash different from
+// the majority of endpoints.
+type HeadAlert struct {
+	Endpoint string
+	Kind     string
+	Message  string
+}
+
+// FetchHeads queries head block of clients concurrently, then the hash of reference block, which is depth blocks
+// below the lowest head, so all endpoints have it and a reorg at the tip doesn't make hashes differ.
+func FetchHeads(ctx context.Context, endpoints []string, clients []*Client, depth uint64, timeout time.Duration) []*HeadStatus {
+	statuses := make([]*HeadStatus, len(clients))
+	forEach := func(fn func(i int, ctx context.Context)) {
+		var wg sync.WaitGroup
+		for i := range clients {
+			if statuses[i] != nil && statuses[i].Err != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+				fn(i, ctx)
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	forEach(func(i int, ctx context.Context) {
+		statuses[i] = &HeadStatus{Endpoint: endpoints[i]}
+		header, err := clients[i].EthClient.HeaderByNumber(ctx, nil)
+		if err != nil {
+			statuses[i].Err = fmt.Errorf("eth_getBlockByNumber fail: %w", err)
+			return
+		}
+		statuses[i].Number, statuses[i].Hash = header.Number.Uint64(), header.Hash()
+	})
+
+	var lowest *uint64
+	for _, status := range statuses {
+		if status.Err == nil && (lowest == nil || status.Number < *lowest) {
+			lowest = &status.Number
+		}
+	}
+	if lowest == nil {
+		return statuses
+	}
+	var refNumber uint64
+	if *lowest > depth {
+		refNumber = *lowest - depth
+	}
+
+	forEach(func(i int, ctx context.Context) {
+		statuses[i].RefNumber = refNumber
+		header, err := clients[i].EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(refNumber))
+		if err != nil {
+			statuses[i].Err = fmt.Errorf("eth_getBlockByNumber %v fail: %w", refNumber, err)
+			return
+		}
+		statuses[i].RefHash = header.Hash()
+	})
+	return statuses
+}
+
+// EvaluateHeads returns alerts of statuses: unreachable endpoints, endpoints more than maxLag blocks behind the
+// highest head, and endpoints whose reference hash differs from the majority (ties are broken by the group with
+// the highest head).
+func EvaluateHeads(statuses []*HeadStatus, maxLag uint64) []HeadAlert {
+	var alerts []HeadAlert
+	var highest uint64
+	votes := make(map[common.Hash]int)
+	groupHighest := make(map[common.Hash]uint64)
+	for _, status := range statuses {
+		if status.Err != nil {
+			continue
+		}
+		if status.Number > highest {
+			highest = status.Number
+		}
+		votes[status.RefHash]++
+		if status.Number > groupHighest[status.RefHash] {
+			groupHighest[status.RefHash] = status.Number
+		}
+	}
+	var canonical common.Hash
+	for _, status := range statuses {
+		hash := status.RefHash
+		if status.Err == nil && (votes[hash] > votes[canonical] || (votes[hash] == votes[canonical] && groupHighest[hash] > groupHighest[canonical])) {
+			canonical = hash
+		}
+	}
+
+	for _, status := range statuses {
+		switch {
+		case status.Err != nil:
+			alerts = append(alerts, HeadAlert{status.Endpoint, HeadAlertUnreachable, status.Err.Error()})
+		case status.RefHash != canonical:
+			alerts = append(alerts, HeadAlert{status.Endpoint, HeadAlertDivergent,
+				fmt.Sprintf("hash of block %v is %v, but %v of %v endpoints serve %v", status.RefNumber, status.RefHash.Hex(),
+					votes[canonical], len(statuses), canonical.Hex())})
+		case highest-status.Number > maxLag:
+			alerts = append(alerts, HeadAlert{status.Endpoint, HeadAlertLagging,
+				fmt.Sprintf("head block %v is %v blocks behind the highest head %v", status.Number, highest-status.Number, highest)})
+		}
+	}
+	return alerts
+}
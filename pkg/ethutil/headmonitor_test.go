@@ -0,0 +1,48 @@
+package ethutil
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEvaluateHeads(t *testing.T) {
+	a, b := common.HexToHash("0xa"), common.HexToHash("0xb")
+	tests := []struct {
+		name     string
+		statuses []*HeadStatus
+		want     map[string]string // endpoint -> alert kind
+	}{
+		{"healthy", []*HeadStatus{
+			{Endpoint: "1", Number: 100, RefHash: a},
+			{Endpoint: "2", Number: 99, RefHash: a},
+		}, map[string]string{}},
+		{"lagging", []*HeadStatus{
+			{Endpoint: "1", Number: 100, RefHash: a},
+			{Endpoint: "2", Number: 96, RefHash: a},
+			{Endpoint: "3", Err: errors.New("timeout")},
+		}, map[string]string{"2": HeadAlertLagging, "3": HeadAlertUnreachable}},
+		{"divergent minority", []*HeadStatus{
+			{Endpoint: "1", Number: 100, RefHash: a},
+			{Endpoint: "2", Number: 100, RefHash: b},
+			{Endpoint: "3", Number: 100, RefHash: a},
+		}, map[string]string{"2": HeadAlertDivergent}},
+		{"divergent tie broken by highest head", []*HeadStatus{
+			{Endpoint: "1", Number: 90, RefHash: a},
+			{Endpoint: "2", Number: 101, RefHash: b},
+		}, map[string]string{"1": HeadAlertDivergent}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, alert := range EvaluateHeads(tt.statuses, 3) {
+				got[alert.Endpoint] = alert.Kind
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EvaluateHeads() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
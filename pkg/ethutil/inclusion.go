@@ -0,0 +1,71 @@
+package ethutil
+
+import (
+	"math/big"
+	"sort"
+)
+
+// InclusionSample is an observed tx, WaitBlocks is the number of blocks between the tx was first seen in mempool
+// and the tx was included.
+type InclusionSample struct {
+	Tip        *big.Int // effective priority fee per gas paid by tx
+	WaitBlocks uint64
+}
+
+// InclusionBucket is the inclusion latency statistics of samples whose tip is in [MinTip, MaxTip).
+type InclusionBucket struct {
+	MinTip *big.Int // nil means unbounded
+	MaxTip *big.Int // nil means unbounded
+
+	Count      int
+	MeanWait   float64
+	MedianWait uint64
+	P90Wait    uint64
+}
+
+// SummarizeInclusion groups samples into len(boundaries)+1 buckets split by boundaries (in ascending order),
+// and computes the inclusion latency statistics of each bucket.
+func SummarizeInclusion(samples []InclusionSample, boundaries []*big.Int) []InclusionBucket {
+	var buckets = make([]InclusionBucket, len(boundaries)+1)
+	var waits = make([][]uint64, len(buckets))
+	for i := range buckets {
+		if i > 0 {
+			buckets[i].MinTip = boundaries[i-1]
+		}
+		if i < len(boundaries) {
+			buckets[i].MaxTip = boundaries[i]
+		}
+	}
+
+	for _, sample := range samples {
+		index := sort.Search(len(boundaries), func(i int) bool {
+			return sample.Tip.Cmp(boundaries[i]) < 0
+		})
+		waits[index] = append(waits[index], sample.WaitBlocks)
+	}
+
+	for i, w := range waits {
+		if len(w) == 0 {
+			continue
+		}
+		sort.Slice(w, func(x, y int) bool { return w[x] < w[y] })
+		var sum uint64
+		for _, v := range w {
+			sum += v
+		}
+		buckets[i].Count = len(w)
+		buckets[i].MeanWait = float64(sum) / float64(len(w))
+		buckets[i].MedianWait = percentile(w, 50)
+		buckets[i].P90Wait = percentile(w, 90)
+	}
+	return buckets
+}
+
+// percentile returns the p-th percentile (nearest rank) of sorted values, values must not be empty.
+func percentile(sorted []uint64, p int) uint64 {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
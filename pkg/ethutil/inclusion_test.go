@@ -0,0 +1,43 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSummarizeInclusion(t *testing.T) {
+	var samples = []InclusionSample{
+		{Tip: big.NewInt(1), WaitBlocks: 5},
+		{Tip: big.NewInt(1), WaitBlocks: 3},
+		{Tip: big.NewInt(10), WaitBlocks: 2},
+		{Tip: big.NewInt(15), WaitBlocks: 1},
+		{Tip: big.NewInt(20), WaitBlocks: 1},
+		{Tip: big.NewInt(30), WaitBlocks: 1},
+	}
+	var boundaries = []*big.Int{big.NewInt(10), big.NewInt(20)}
+
+	tests := []struct {
+		count      int
+		meanWait   float64
+		medianWait uint64
+		p90Wait    uint64
+	}{
+		{count: 2, meanWait: 4, medianWait: 3, p90Wait: 5},
+		{count: 2, meanWait: 1.5, medianWait: 1, p90Wait: 2},
+		{count: 2, meanWait: 1, medianWait: 1, p90Wait: 1},
+	}
+
+	buckets := SummarizeInclusion(samples, boundaries)
+	if len(buckets) != len(tests) {
+		t.Fatalf("expected: %v buckets, got: %v", len(tests), len(buckets))
+	}
+	for i, tt := range tests {
+		b := buckets[i]
+		if b.Count != tt.count || b.MeanWait != tt.meanWait || b.MedianWait != tt.medianWait || b.P90Wait != tt.p90Wait {
+			t.Fatalf("test %d: expected: %+v, got: %+v", i, tt, b)
+		}
+	}
+	if buckets[0].MinTip != nil || buckets[2].MaxTip != nil {
+		t.Fatalf("expected unbounded first and last buckets")
+	}
+}
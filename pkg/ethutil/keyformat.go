@@ -0,0 +1,230 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// formats of private key supported by ParsePrivateKeyAny and MarshalPrivateKey
+const (
+	KeyFormatHex      = "hex"       // 32 bytes hex string, the format of --private-key
+	KeyFormatRaw      = "raw"       // 32 bytes binary
+	KeyFormatSec1Pem  = "sec1-pem"  // "EC PRIVATE KEY" PEM of RFC 5915, e.g. openssl ecparam -genkey -name secp256k1
+	KeyFormatSec1Der  = "sec1-der"  // DER of RFC 5915 ECPrivateKey
+	KeyFormatPkcs8Pem = "pkcs8-pem" // "PRIVATE KEY" PEM of PKCS#8, e.g. exported from HSM or cloud KMS
+	KeyFormatPkcs8Der = "pkcs8-der" // DER of PKCS#8 PrivateKeyInfo
+	KeyFormatWif      = "wif"       // Bitcoin wallet import format, base58check of 0x80 + key + 0x01 (compressed)
+)
+
+// KeyFormats are all formats of private key
+var KeyFormats = []string{KeyFormatHex, KeyFormatRaw, KeyFormatSec1Pem, KeyFormatSec1Der, KeyFormatPkcs8Pem, KeyFormatPkcs8Der, KeyFormatWif}
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+// sec1PrivateKey is ECPrivateKey of RFC 5915
+type sec1PrivateKey struct {
+	Version    int
+	PrivateKey []byte
+	Curve      asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey  asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// pkcs8PrivateKey is PrivateKeyInfo of RFC 5208, optional attributes are ignored
+type pkcs8PrivateKey struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ParsePrivateKeyAny parses secp256k1 private key in any of KeyFormats, the detected format is returned. Hex string
+// of DER is also accepted.
+func ParsePrivateKeyAny(input []byte) (*ecdsa.PrivateKey, string, error) {
+	text := strings.TrimSpace(string(input))
+	if hexString := strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X"); hexString != "" {
+		if decoded, err := hex.DecodeString(hexString); err == nil {
+			if len(decoded) == 32 {
+				key, err := toECDSA(decoded)
+				return key, KeyFormatHex, err
+			}
+			return parseDerPrivateKey(decoded)
+		}
+	}
+	if strings.HasPrefix(text, "-----BEGIN") {
+		return parsePemPrivateKey([]byte(text))
+	}
+	if len(input) == 32 {
+		key, err := toECDSA(input)
+		return key, KeyFormatRaw, err
+	}
+	if len(input) > 0 && input[0] == 0x30 { // SEQUENCE
+		return parseDerPrivateKey(input)
+	}
+	if key, err := parseWif(text); err == nil {
+		return key, KeyFormatWif, nil
+	}
+	return nil, "", errors.New("unrecognized private key format, expected one of " + strings.Join(KeyFormats, ", "))
+}
+
+// toECDSA builds secp256k1 private key from big endian bytes, which are left padded to 32 bytes if shorter.
+func toECDSA(d []byte) (*ecdsa.PrivateKey, error) {
+	if len(d) > 32 {
+		return nil, fmt.Errorf("invalid private key length %v, expected at most 32 bytes", len(d))
+	}
+	var padded = make([]byte, 32)
+	copy(padded[32-len(d):], d)
+	return crypto.ToECDSA(padded)
+}
+
+func parsePemPrivateKey(content []byte) (*ecdsa.PrivateKey, string, error) {
+	for {
+		var block *pem.Block
+		block, content = pem.Decode(content)
+		if block == nil {
+			return nil, "", errors.New("no private key found in PEM")
+		}
+		switch block.Type {
+		case "EC PARAMETERS": // output by openssl ecparam -genkey without -noout
+			continue
+		case "EC PRIVATE KEY":
+			key, err := parseSec1PrivateKey(block.Bytes)
+			return key, KeyFormatSec1Pem, err
+		case "PRIVATE KEY":
+			key, err := parsePkcs8PrivateKey(block.Bytes)
+			return key, KeyFormatPkcs8Pem, err
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, "", errors.New("encrypted PEM is not supported, decrypt it first, e.g. openssl pkcs8 -in key.pem")
+		default:
+			return nil, "", fmt.Errorf("unsupported PEM type %v", block.Type)
+		}
+	}
+}
+
+// parseDerPrivateKey parses DER of SEC1 or PKCS#8 private key.
+func parseDerPrivateKey(der []byte) (*ecdsa.PrivateKey, string, error) {
+	if key, err := parsePkcs8PrivateKey(der); err == nil {
+		return key, KeyFormatPkcs8Der, nil
+	}
+	key, err := parseSec1PrivateKey(der)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid DER of SEC1 or PKCS#8 private key: %w", err)
+	}
+	return key, KeyFormatSec1Der, nil
+}
+
+func parseSec1PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	var sec1 sec1PrivateKey
+	if rest, err := asn1.Unmarshal(der, &sec1); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after SEC1 private key")
+	}
+	if sec1.Version != 1 {
+		return nil, fmt.Errorf("unsupported SEC1 private key version %v", sec1.Version)
+	}
+	if len(sec1.Curve) > 0 && !sec1.Curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("unsupported curve %v, only secp256k1 (%v) is supported", sec1.Curve, oidSecp256k1)
+	}
+	return toECDSA(sec1.PrivateKey)
+}
+
+func parsePkcs8PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	var pkcs8 pkcs8PrivateKey
+	if _, err := asn1.Unmarshal(der, &pkcs8); err != nil {
+		return nil, err
+	}
+	if !pkcs8.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		return nil, fmt.Errorf("unsupported PKCS#8 algorithm %v, only EC (%v) is supported", pkcs8.Algo.Algorithm, oidPublicKeyECDSA)
+	}
+	var curve asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(pkcs8.Algo.Parameters.FullBytes, &curve); err != nil {
+		return nil, fmt.Errorf("invalid curve of PKCS#8 private key: %w", err)
+	}
+	if !curve.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("unsupported curve %v, only secp256k1 (%v) is supported", curve, oidSecp256k1)
+	}
+	return parseSec1PrivateKey(pkcs8.PrivateKey)
+}
+
+// MarshalPrivateKey encodes private key in format, which is one of KeyFormats.
+func MarshalPrivateKey(key *ecdsa.PrivateKey, format string) ([]byte, error) {
+	switch format {
+	case KeyFormatHex:
+		return []byte("0x" + hex.EncodeToString(crypto.FromECDSA(key))), nil
+	case KeyFormatRaw:
+		return crypto.FromECDSA(key), nil
+	case KeyFormatSec1Der, KeyFormatSec1Pem:
+		der, err := marshalSec1PrivateKey(key, true)
+		if err != nil || format == KeyFormatSec1Der {
+			return der, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	case KeyFormatPkcs8Der, KeyFormatPkcs8Pem:
+		sec1, err := marshalSec1PrivateKey(key, false) // curve is in algorithm identifier
+		if err != nil {
+			return nil, err
+		}
+		curve, err := asn1.Marshal(oidSecp256k1)
+		if err != nil {
+			return nil, err
+		}
+		der, err := asn1.Marshal(pkcs8PrivateKey{
+			Algo:       pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: curve}},
+			PrivateKey: sec1,
+		})
+		if err != nil || format == KeyFormatPkcs8Der {
+			return der, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	case KeyFormatWif:
+		payload := append(append([]byte{0x80}, crypto.FromECDSA(key)...), 0x01)
+		return []byte(Base58CheckEncode(payload)), nil
+	default:
+		return nil, fmt.Errorf("unsupported key format %v, expected one of %v", format, strings.Join(KeyFormats, ", "))
+	}
+}
+
+func marshalSec1PrivateKey(key *ecdsa.PrivateKey, withCurve bool) ([]byte, error) {
+	sec1 := sec1PrivateKey{
+		Version:    1,
+		PrivateKey: crypto.FromECDSA(key),
+		PublicKey:  asn1.BitString{Bytes: crypto.FromECDSAPub(&key.PublicKey), BitLength: 65 * 8},
+	}
+	if withCurve {
+		sec1.Curve = oidSecp256k1
+	}
+	return asn1.Marshal(sec1)
+}
+
+// parseWif parses private key in Bitcoin wallet import format, both mainnet (0x80) and testnet (0xef) versions are
+// accepted, with or without the compressed flag.
+func parseWif(wif string) (*ecdsa.PrivateKey, error) {
+	payload, err := Base58CheckDecode(wif)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 33 && !(len(payload) == 34 && payload[33] == 0x01) {
+		return nil, fmt.Errorf("invalid WIF payload length %v", len(payload))
+	}
+	if payload[0] != 0x80 && payload[0] != 0xef {
+		return nil, fmt.Errorf("invalid WIF version %#x", payload[0])
+	}
+	return toECDSA(payload[1:33])
+}
+
+func doubleSha256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
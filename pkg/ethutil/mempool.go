@@ -0,0 +1,103 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// MempoolFilter selects pending txs, an empty field matches any tx.
+type MempoolFilter struct {
+	To        []common.Address // tx to any of these addresses, contract creation never matches
+	Selectors [][]byte         // tx calling any of these 4 bytes function selectors
+	MinValue  *big.Int         // tx with value not less than MinValue, in wei
+	MaxValue  *big.Int         // tx with value not greater than MaxValue, in wei
+}
+
+// Match returns true if tx satisfies all conditions of filter.
+func (f MempoolFilter) Match(tx *types.Transaction) bool {
+	if len(f.To) > 0 {
+		if tx.To() == nil {
+			return false
+		}
+		var found bool
+		for _, to := range f.To {
+			if *tx.To() == to {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Selectors) > 0 {
+		var found bool
+		for _, selector := range f.Selectors {
+			if len(tx.Data()) >= 4 && bytes.Equal(tx.Data()[:4], selector) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinValue != nil && tx.Value().Cmp(f.MinValue) < 0 {
+		return false
+	}
+	if f.MaxValue != nil && tx.Value().Cmp(f.MaxValue) > 0 {
+		return false
+	}
+	return true
+}
+
+// parseTxpoolContent parses the result of txpool_content, the pending txs are returned in order of sender and nonce.
+func parseTxpoolContent(content []byte) ([]*types.Transaction, error) {
+	var result struct {
+		Pending map[common.Address]map[string]*types.Transaction `json:"pending"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("parse txpool_content fail: %w", err)
+	}
+
+	var senders []common.Address
+	for sender := range result.Pending {
+		senders = append(senders, sender)
+	}
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+
+	var txs []*types.Transaction
+	for _, sender := range senders {
+		var senderTxs []*types.Transaction
+		for _, tx := range result.Pending[sender] {
+			senderTxs = append(senderTxs, tx)
+		}
+		sort.Slice(senderTxs, func(i, j int) bool { return senderTxs[i].Nonce() < senderTxs[j].Nonce() })
+		txs = append(txs, senderTxs...)
+	}
+	return txs, nil
+}
+
+// TxpoolPending returns the pending txs in mempool of node by txpool_content, which is supported by geth, erigon,
+// anvil etc if txpool namespace is enabled.
+func TxpoolPending(ctx context.Context, rpcClient *rpc.Client) ([]*types.Transaction, error) {
+	var content json.RawMessage
+	if err := rpcClient.CallContext(ctx, &content, "txpool_content"); err != nil {
+		return nil, fmt.Errorf("txpool_content fail: %w", err)
+	}
+	return parseTxpoolContent(content)
+}
+
+// SubscribePendingTxs subscribes full bodies of new pending txs by eth_subscribe("newPendingTransactions", true),
+// which requires a websocket or ipc connection.
+func SubscribePendingTxs(ctx context.Context, rpcClient *rpc.Client, ch chan<- *types.Transaction) (*rpc.ClientSubscription, error) {
+	return rpcClient.EthSubscribe(ctx, ch, "newPendingTransactions", true)
+}
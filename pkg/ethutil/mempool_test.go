@@ -0,0 +1,65 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestMempoolFilterMatch(t *testing.T) {
+	var router = common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	var other = common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	var swap = common.FromHex("0x38ed1739")
+
+	newTx := func(to *common.Address, value int64, data string) *types.Transaction {
+		return types.NewTx(&types.LegacyTx{To: to, Value: big.NewInt(value), Data: common.FromHex(data)})
+	}
+
+	tests := []struct {
+		filter   MempoolFilter
+		tx       *types.Transaction
+		expected bool
+	}{
+		{MempoolFilter{}, newTx(nil, 0, ""), true},
+		{MempoolFilter{To: []common.Address{router}}, newTx(&router, 0, ""), true},
+		{MempoolFilter{To: []common.Address{router}}, newTx(&other, 0, ""), false},
+		{MempoolFilter{To: []common.Address{router}}, newTx(nil, 0, ""), false},
+		{MempoolFilter{To: []common.Address{other, router}}, newTx(&router, 0, ""), true},
+		{MempoolFilter{Selectors: [][]byte{swap}}, newTx(&router, 0, "0x38ed17390000"), true},
+		{MempoolFilter{Selectors: [][]byte{swap}}, newTx(&router, 0, "0x38ed"), false},
+		{MempoolFilter{Selectors: [][]byte{swap}}, newTx(&router, 0, "0xa9059cbb"), false},
+		{MempoolFilter{MinValue: big.NewInt(10)}, newTx(&router, 10, ""), true},
+		{MempoolFilter{MinValue: big.NewInt(10)}, newTx(&router, 9, ""), false},
+		{MempoolFilter{MaxValue: big.NewInt(10)}, newTx(&router, 11, ""), false},
+		{MempoolFilter{To: []common.Address{router}, Selectors: [][]byte{swap}, MinValue: big.NewInt(1)}, newTx(&router, 1, "0x38ed1739"), true},
+		{MempoolFilter{To: []common.Address{router}, Selectors: [][]byte{swap}, MinValue: big.NewInt(1)}, newTx(&router, 0, "0x38ed1739"), false},
+	}
+
+	for i, tt := range tests {
+		if got := tt.filter.Match(tt.tx); got != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.expected, got)
+		}
+	}
+}
+
+func TestParseTxpoolContent(t *testing.T) {
+	var content = `{"pending":{"0x24f8209ec5f56a07c94e834627f0651c19aca0ac":{"1":{"blockHash":null,"blockNumber":null,"from":"0x24f8209ec5f56a07c94e834627f0651c19aca0ac","gas":"0x5208","gasPrice":"0x3b9aca00","hash":"0x0000000000000000000000000000000000000000000000000000000000000000","input":"0x","nonce":"0x1","to":"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb","transactionIndex":null,"value":"0x1","type":"0x0","v":"0x1b","r":"0x1","s":"0x1"},"0":{"blockHash":null,"blockNumber":null,"from":"0x24f8209ec5f56a07c94e834627f0651c19aca0ac","gas":"0x5208","gasPrice":"0x3b9aca00","hash":"0x0000000000000000000000000000000000000000000000000000000000000000","input":"0x","nonce":"0x0","to":"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb","transactionIndex":null,"value":"0x2","type":"0x0","v":"0x1b","r":"0x1","s":"0x1"}}},"queued":{}}`
+
+	txs, err := parseTxpoolContent([]byte(content))
+	if err != nil {
+		t.Fatalf("parseTxpoolContent fail: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected: %v txs, got: %v", 2, len(txs))
+	}
+	for i, tx := range txs {
+		if tx.Nonce() != uint64(i) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, i, tx.Nonce())
+		}
+	}
+	if txs[0].Value().Int64() != 2 {
+		t.Fatalf("expected: %v, got: %v", 2, txs[0].Value())
+	}
+}
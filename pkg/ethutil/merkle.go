@@ -0,0 +1,115 @@
+package ethutil
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MerkleTreeFormat is the format of OpenZeppelin StandardMerkleTree dump
+const MerkleTreeFormat = "standard-v1"
+
+// MerkleTree is compatible with StandardMerkleTree of OpenZeppelin merkle-tree library, its json is the same as
+// StandardMerkleTree.dump() (plus root and proofs), so it can be loaded by StandardMerkleTree.load().
+// The tree is a complete binary tree stored in array, leaves are sorted by hash and placed at the end in reverse order.
+// See: https://github.com/OpenZeppelin/merkle-tree
+type MerkleTree struct {
+	Format       string        `json:"format"`
+	Root         common.Hash   `json:"root"`
+	Tree         []common.Hash `json:"tree"`
+	Values       []MerkleValue `json:"values"`
+	LeafEncoding []string      `json:"leafEncoding"`
+}
+
+// MerkleValue is a leaf of MerkleTree, Proof is the proof of it against root.
+type MerkleValue struct {
+	Value     []string      `json:"value"`
+	TreeIndex int           `json:"treeIndex"`
+	Proof     []common.Hash `json:"proof,omitempty"`
+}
+
+// MerkleLeafHash returns the leaf hash of values, i.e. keccak256(keccak256(abi.encode(values))), which is the same as
+// `keccak256(bytes.concat(keccak256(abi.encode(...))))` in solidity.
+func MerkleLeafHash(types []string, values []string) (common.Hash, error) {
+	if len(types) != len(values) {
+		return common.Hash{}, fmt.Errorf("expected %v values (%v), got %v", len(types), types, len(values))
+	}
+	encoded, err := EncodeParameters(types, values)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(crypto.Keccak256(encoded)), nil
+}
+
+// hashPair returns keccak256 of sorted a and b, the same as Hashes.commutativeKeccak256 of OpenZeppelin contracts.
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// NewMerkleTree builds merkle tree of values, each value is encoded by types (e.g. address,uint256).
+func NewMerkleTree(types []string, values [][]string) (*MerkleTree, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("expected non-zero number of leaves")
+	}
+
+	type hashedValue struct {
+		valueIndex int
+		hash       common.Hash
+	}
+	var hashedValues []hashedValue
+	for i, value := range values {
+		hash, err := MerkleLeafHash(types, value)
+		if err != nil {
+			return nil, fmt.Errorf("leaf %d: %w", i, err)
+		}
+		hashedValues = append(hashedValues, hashedValue{i, hash})
+	}
+	sort.SliceStable(hashedValues, func(i, j int) bool {
+		return bytes.Compare(hashedValues[i].hash[:], hashedValues[j].hash[:]) < 0
+	})
+
+	tree := make([]common.Hash, 2*len(values)-1)
+	merkleValues := make([]MerkleValue, len(values))
+	for leafIndex, v := range hashedValues {
+		treeIndex := len(tree) - 1 - leafIndex
+		tree[treeIndex] = v.hash
+		merkleValues[v.valueIndex] = MerkleValue{Value: values[v.valueIndex], TreeIndex: treeIndex}
+	}
+	for i := len(tree) - 1 - len(values); i >= 0; i-- {
+		tree[i] = hashPair(tree[2*i+1], tree[2*i+2])
+	}
+
+	t := &MerkleTree{Format: MerkleTreeFormat, Root: tree[0], Tree: tree, Values: merkleValues, LeafEncoding: types}
+	for i := range t.Values {
+		t.Values[i].Proof = t.Proof(t.Values[i].TreeIndex)
+	}
+	return t, nil
+}
+
+// Proof returns the proof of leaf at treeIndex, i.e. the siblings from leaf to root.
+func (t *MerkleTree) Proof(treeIndex int) []common.Hash {
+	var proof []common.Hash
+	for i := treeIndex; i > 0; i = (i - 1) / 2 {
+		sibling := i + 1
+		if i%2 == 0 {
+			sibling = i - 1
+		}
+		proof = append(proof, t.Tree[sibling])
+	}
+	return proof
+}
+
+// VerifyMerkleProof checks that leaf is in the tree of root, the same as MerkleProof.verify of OpenZeppelin contracts.
+func VerifyMerkleProof(root common.Hash, leaf common.Hash, proof []common.Hash) bool {
+	computed := leaf
+	for _, p := range proof {
+		computed = hashPair(computed, p)
+	}
+	return computed == root
+}
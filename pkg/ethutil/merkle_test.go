@@ -0,0 +1,66 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestNewMerkleTree(t *testing.T) {
+	types := []string{"address", "uint256"}
+	tests := []struct {
+		values [][]string
+		root   string
+	}{
+		{
+			// example in README of OpenZeppelin merkle-tree
+			[][]string{
+				{"0x1111111111111111111111111111111111111111", "5000000000000000000"},
+				{"0x2222222222222222222222222222222222222222", "2500000000000000000"},
+			},
+			"0xd4dee0beab2d53f2cc83e567171bd2820e49898130a22622b10ead383e90bd77",
+		},
+		{
+			[][]string{
+				{"0x1111111111111111111111111111111111111111", "1"},
+				{"0x2222222222222222222222222222222222222222", "2"},
+				{"0x3333333333333333333333333333333333333333", "3"},
+				{"0x4444444444444444444444444444444444444444", "4"},
+				{"0x5555555555555555555555555555555555555555", "5"},
+			},
+			"",
+		},
+	}
+
+	for i, test := range tests {
+		tree, err := NewMerkleTree(types, test.values)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if test.root != "" && tree.Root.Hex() != test.root {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.root, tree.Root.Hex())
+		}
+		for j, value := range tree.Values {
+			leaf, err := MerkleLeafHash(types, value.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tree.Tree[value.TreeIndex] != leaf {
+				t.Fatalf("test %d: leaf %d is not at tree index %d", i, j, value.TreeIndex)
+			}
+			if !VerifyMerkleProof(tree.Root, leaf, value.Proof) {
+				t.Fatalf("test %d: proof of leaf %d is invalid", i, j)
+			}
+			if VerifyMerkleProof(common.Hash{}, leaf, value.Proof) {
+				t.Fatalf("test %d: proof of leaf %d is valid for wrong root", i, j)
+			}
+		}
+	}
+
+	if _, err := NewMerkleTree(types, nil); err == nil {
+		t.Fatalf("expected error for empty tree")
+	}
+	if _, err := NewMerkleTree(types, [][]string{{"0x1111111111111111111111111111111111111111"}}); err == nil {
+		t.Fatalf("expected error for missing value")
+	}
+}
@@ -0,0 +1,75 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Multicall3Address is the address of Multicall3, which is deployed at the same address on most chains.
+// See: https://github.com/mds1/multicall
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// multicall3Abi is the abi of tryAggregate((address,bytes)[]) of Multicall3
+const multicall3Abi = `[{"name":"tryAggregate","type":"function","stateMutability":"payable",
+"inputs":[{"name":"requireSuccess","type":"bool"},{"name":"calls","type":"tuple[]","components":[{"name":"target","type":"address"},{"name":"callData","type":"bytes"}]}],
+"outputs":[{"name":"returnData","type":"tuple[]","components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}]}]}]`
+
+// MulticallResult is the result of a call in Multicall3 tryAggregate.
+type MulticallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// MulticallTryAggregate executes calls by Multicall3 tryAggregate at block (nil means latest), at most chunkSize calls
+// are sent in one eth_call. Unlike aggregate, a reverted call does not fail the others, its result is not Success.
+func MulticallTryAggregate(ctx context.Context, client *ethclient.Client, calls []CallArgs, block *big.Int, chunkSize int) ([]MulticallResult, error) {
+	parsed, err := abi.JSON(strings.NewReader(multicall3Abi))
+	if err != nil {
+		return nil, err
+	}
+	type call struct {
+		Target   common.Address
+		CallData []byte
+	}
+	if chunkSize <= 0 {
+		chunkSize = len(calls)
+	}
+
+	var results []MulticallResult
+	for start := 0; start < len(calls); start += chunkSize {
+		end := start + chunkSize
+		if end > len(calls) {
+			end = len(calls)
+		}
+		var chunk []call
+		for _, c := range calls[start:end] {
+			if c.To == nil {
+				return nil, fmt.Errorf("multicall can not create contract")
+			}
+			chunk = append(chunk, call{Target: *c.To, CallData: c.Data})
+		}
+		data, err := parsed.Pack("tryAggregate", false, chunk)
+		if err != nil {
+			return nil, err
+		}
+		output, err := Call(ctx, client, Multicall3Address, data, block)
+		if err != nil {
+			return nil, fmt.Errorf("call tryAggregate of multicall fail: %w", err)
+		}
+		var returnData []MulticallResult
+		if err := parsed.UnpackIntoInterface(&returnData, "tryAggregate", output); err != nil {
+			return nil, fmt.Errorf("unpack return data of tryAggregate fail: %w", err)
+		}
+		if len(returnData) != len(chunk) {
+			return nil, fmt.Errorf("expected %v results of tryAggregate, got %v", len(chunk), len(returnData))
+		}
+		results = append(results, returnData...)
+	}
+	return results, nil
+}
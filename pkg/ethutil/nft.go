@@ -0,0 +1,142 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// DefaultIpfsGateway is the gateway used to fetch ipfs:// URI.
+const DefaultIpfsGateway = "https://ipfs.io"
+
+// NftTokenURI returns the metadata URI of token, by tokenURI(uint256) of ERC-721, or uri(uint256) of ERC-1155 if
+// the former fails. The {id} placeholder in URI of ERC-1155 is substituted.
+func NftTokenURI(ctx context.Context, client *ethclient.Client, contract common.Address, tokenId *big.Int) (string, error) {
+	values, err := CallAndUnpack(ctx, client, contract, "function tokenURI(uint256) returns (string)", []string{tokenId.String()})
+	if err == nil {
+		return values[0].(string), nil
+	}
+	values, err1155 := CallAndUnpack(ctx, client, contract, "function uri(uint256) returns (string)", []string{tokenId.String()})
+	if err1155 != nil {
+		return "", fmt.Errorf("neither tokenURI (ERC-721) nor uri (ERC-1155) is supported: %w", err)
+	}
+	return substituteTokenId(values[0].(string), tokenId), nil
+}
+
+// substituteTokenId replaces {id} in URI of ERC-1155 by lowercase hex token id padded to 64 chars.
+func substituteTokenId(uri string, tokenId *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", tokenId))
+}
+
+// TokenURIToHttp rewrites ipfs:// (and ipfs://ipfs/) URI to URL of ipfs gateway, and ar:// URI to arweave.net.
+func TokenURIToHttp(uri string, ipfsGateway string) string {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return strings.TrimSuffix(ipfsGateway, "/") + "/ipfs/" + path
+	case strings.HasPrefix(uri, "ar://"):
+		return "https://arweave.net/" + strings.TrimPrefix(uri, "ar://")
+	}
+	return uri
+}
+
+// FetchNftMetadata returns the metadata of token URI, data URI (e.g. of on-chain NFT) is decoded without network
+// access, ipfs:// URI is fetched by ipfsGateway.
+func FetchNftMetadata(ctx context.Context, uri string, ipfsGateway string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		_, data, err := ParseDataURI(uri)
+		return data, err
+	}
+	url := TokenURIToHttp(uri, ipfsGateway)
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("unsupported token URI %v", uri)
+	}
+	return httpGet(ctx, url)
+}
+
+// NftMedia is an image or animation embedded in NFT metadata.
+type NftMedia struct {
+	Field     string // field of metadata, i.e. image, image_data or animation_url
+	MediaType string
+	Data      []byte
+}
+
+// Extension returns the file extension of media type, ".bin" if it's unknown.
+func (m NftMedia) Extension() string {
+	mediaType, _, _ := strings.Cut(m.MediaType, ";")
+	switch strings.TrimSpace(strings.ToLower(mediaType)) {
+	case "image/svg+xml":
+		return ".svg"
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "text/html":
+		return ".html"
+	case "application/json":
+		return ".json"
+	case "video/mp4":
+		return ".mp4"
+	}
+	return ".bin"
+}
+
+// ExtractNftMedia returns media embedded in metadata of fully on-chain NFT, i.e. data URI in image or
+// animation_url, and raw SVG in image_data. Media referenced by http or ipfs URI are not returned.
+func ExtractNftMedia(metadata []byte) ([]NftMedia, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return nil, fmt.Errorf("parse metadata fail: %w", err)
+	}
+
+	var media []NftMedia
+	for _, field := range []string{"image", "image_data", "animation_url"} {
+		value, ok := fields[field].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if strings.HasPrefix(value, "data:") {
+			mediaType, data, err := ParseDataURI(value)
+			if err != nil {
+				return nil, fmt.Errorf("decode %v fail: %w", field, err)
+			}
+			media = append(media, NftMedia{Field: field, MediaType: mediaType, Data: data})
+		} else if field == "image_data" {
+			media = append(media, NftMedia{Field: field, MediaType: "image/svg+xml", Data: []byte(value)})
+		}
+	}
+	return media, nil
+}
+
+// dataURIStringRE matches JSON string of data URI.
+var dataURIStringRE = regexp.MustCompile(`"data:(?:[^"\\]|\\.)*"`)
+
+// SummarizeDataURIs replaces data URI longer than maxLen in JSON by its media type and size, so metadata with
+// embedded images is readable in terminal.
+func SummarizeDataURIs(metadata []byte, maxLen int) []byte {
+	return dataURIStringRE.ReplaceAllFunc(metadata, func(quoted []byte) []byte {
+		if len(quoted) <= maxLen+2 {
+			return quoted
+		}
+		var uri string
+		if err := json.Unmarshal(quoted, &uri); err != nil {
+			return quoted
+		}
+		mediaType, data, err := ParseDataURI(uri)
+		if err != nil {
+			return quoted
+		}
+		summary, _ := json.Marshal(fmt.Sprintf("data:%v (%v bytes)", mediaType, len(data)))
+		return summary
+	})
+}
@@ -0,0 +1,76 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+	"testing"
+)
+
+func TestTokenURIToHttp(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected string
+	}{
+		{"ipfs://QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR/1", "https://ipfs.io/ipfs/QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR/1"},
+		{"ipfs://ipfs/QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR", "https://ipfs.io/ipfs/QmbWqxBEKC3P8tqsKc98xmWNzrzDtRLMiMPL8wBuTGsMnR"},
+		{"ar://abc", "https://arweave.net/abc"},
+		{"https://example.com/1.json", "https://example.com/1.json"},
+	}
+
+	for i, test := range tests {
+		if got := TokenURIToHttp(test.uri, DefaultIpfsGateway+"/"); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+	}
+}
+
+func TestSubstituteTokenId(t *testing.T) {
+	var expected = "https://example.com/000000000000000000000000000000000000000000000000000000000004cce0.json"
+	if got := substituteTokenId("https://example.com/{id}.json", big.NewInt(314592)); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+}
+
+func TestFetchNftMetadataDataURI(t *testing.T) {
+	metadata, err := FetchNftMetadata(context.Background(), "data:application/json;base64,eyJuYW1lIjoiIzEifQ==", DefaultIpfsGateway)
+	if err != nil || string(metadata) != `{"name":"#1"}` {
+		t.Fatalf("expected: %v, got: %s (%v)", `{"name":"#1"}`, metadata, err)
+	}
+}
+
+func TestExtractNftMedia(t *testing.T) {
+	// metadata of on-chain NFT, image is base64 svg, animation_url is utf8 html, image_data is raw svg
+	var metadata = `{"name":"#1","image":"data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=",` +
+		`"image_data":"<svg><rect width=\"100%\"/></svg>","animation_url":"data:text/html;utf8,<p>100%</p>",` +
+		`"external_url":"https://example.com/1"}`
+
+	media, err := ExtractNftMedia([]byte(metadata))
+	if err != nil {
+		t.Fatalf("ExtractNftMedia fail: %v", err)
+	}
+	expected := []struct {
+		field     string
+		extension string
+		data      string
+	}{
+		{"image", ".svg", "<svg></svg>"},
+		{"image_data", ".svg", `<svg><rect width="100%"/></svg>`},
+		{"animation_url", ".html", "<p>100%</p>"},
+	}
+	if len(media) != len(expected) {
+		t.Fatalf("expected: %v media, got: %v", len(expected), len(media))
+	}
+	for i, m := range media {
+		if m.Field != expected[i].field || m.Extension() != expected[i].extension || string(m.Data) != expected[i].data {
+			t.Fatalf("test %d: expected: %v, got: %v %v %s", i, expected[i], m.Field, m.Extension(), m.Data)
+		}
+	}
+}
+
+func TestSummarizeDataURIs(t *testing.T) {
+	var metadata = `{"image":"data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=","short":"data:,a"}`
+	var expected = `{"image":"data:image/svg+xml (11 bytes)","short":"data:,a"}`
+	if got := string(SummarizeDataURIs([]byte(metadata), 20)); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected, got)
+	}
+}
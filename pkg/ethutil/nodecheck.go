@@ -0,0 +1,110 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// NodeInfo is the status of an rpc endpoint reported by CheckNode.
+type NodeInfo struct {
+	Endpoint      string
+	ChainID       *big.Int
+	ClientVersion string
+	Syncing       bool
+	SyncCurrent   uint64 // the current and highest block of syncing node
+	SyncHighest   uint64
+	LatestBlock   uint64
+	BlockTime     time.Time // timestamp of latest block
+	TxpoolPending *uint64   // nil if txpool_status is not supported
+	TxpoolQueued  *uint64
+	Latency       time.Duration // median latency of eth_blockNumber
+	Err           error         // endpoint is unreachable if it's not nil
+}
+
+// BlocksBehind returns how many blocks the endpoint is behind the highest block.
+func (n *NodeInfo) BlocksBehind(highest uint64) uint64 {
+	if n.LatestBlock >= highest {
+		return 0
+	}
+	return highest - n.LatestBlock
+}
+
+// CheckNode queries chain id, client version, sync status, latest block and txpool status of endpoint, and measures
+// latency by samples calls of eth_blockNumber. Unsupported optional methods (web3_clientVersion, txpool_status)
+// are ignored.
+func CheckNode(ctx context.Context, endpoint string, samples int, timeout time.Duration) *NodeInfo {
+	var info = &NodeInfo{Endpoint: endpoint}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := Dial(ctx, endpoint)
+	if err != nil {
+		info.Err = err
+		return info
+	}
+	defer client.Close()
+
+	var latencies []time.Duration
+	for i := 0; i < samples; i++ {
+		begin := time.Now()
+		if info.LatestBlock, err = client.EthClient.BlockNumber(ctx); err != nil {
+			info.Err = fmt.Errorf("eth_blockNumber fail: %w", err)
+			return info
+		}
+		latencies = append(latencies, time.Since(begin))
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	info.Latency = latencies[len(latencies)/2]
+
+	if info.ChainID, err = client.EthClient.ChainID(ctx); err != nil {
+		info.Err = fmt.Errorf("eth_chainId fail: %w", err)
+		return info
+	}
+	if header, err := client.EthClient.HeaderByNumber(ctx, new(big.Int).SetUint64(info.LatestBlock)); err == nil {
+		info.BlockTime = time.Unix(int64(header.Time), 0)
+	}
+	if progress, err := client.EthClient.SyncProgress(ctx); err == nil && progress != nil {
+		info.Syncing = true
+		info.SyncCurrent, info.SyncHighest = progress.CurrentBlock, progress.HighestBlock
+	}
+	_ = client.RpcClient.CallContext(ctx, &info.ClientVersion, "web3_clientVersion")
+
+	var txpool struct {
+		Pending hexutil.Uint64 `json:"pending"`
+		Queued  hexutil.Uint64 `json:"queued"`
+	}
+	if err := client.RpcClient.CallContext(ctx, &txpool, "txpool_status"); err == nil {
+		pending, queued := uint64(txpool.Pending), uint64(txpool.Queued)
+		info.TxpoolPending, info.TxpoolQueued = &pending, &queued
+	}
+	return info
+}
+
+// RankNodes sorts endpoints from the healthiest: reachable before unreachable, then synced before syncing, then
+// fewer blocks behind the highest block of all endpoints, then lower latency.
+func RankNodes(infos []*NodeInfo) {
+	var highest uint64
+	for _, info := range infos {
+		if info.Err == nil && info.LatestBlock > highest {
+			highest = info.LatestBlock
+		}
+	}
+	sort.SliceStable(infos, func(i, j int) bool {
+		a, b := infos[i], infos[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Syncing != b.Syncing {
+			return !a.Syncing
+		}
+		if a.BlocksBehind(highest) != b.BlocksBehind(highest) {
+			return a.BlocksBehind(highest) < b.BlocksBehind(highest)
+		}
+		return a.Latency < b.Latency
+	})
+}
@@ -0,0 +1,28 @@
+package ethutil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRankNodes(t *testing.T) {
+	infos := []*NodeInfo{
+		{Endpoint: "unreachable", Err: errors.New("connection refused")},
+		{Endpoint: "syncing", LatestBlock: 100, Syncing: true, Latency: time.Millisecond},
+		{Endpoint: "behind", LatestBlock: 98, Latency: 10 * time.Millisecond},
+		{Endpoint: "slow", LatestBlock: 100, Latency: 300 * time.Millisecond},
+		{Endpoint: "fast", LatestBlock: 100, Latency: 50 * time.Millisecond},
+	}
+	RankNodes(infos)
+
+	expected := []string{"fast", "slow", "behind", "syncing", "unreachable"}
+	for i, info := range infos {
+		if info.Endpoint != expected[i] {
+			t.Fatalf("test %d: expected: %v, got: %v", i, expected[i], info.Endpoint)
+		}
+	}
+	if behind := infos[2].BlocksBehind(100); behind != 2 {
+		t.Fatalf("expected: %v, got: %v", 2, behind)
+	}
+}
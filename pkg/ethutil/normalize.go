@@ -0,0 +1,232 @@
+package ethutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Providers (geth, erigon, nethermind, Alchemy, Ankr, etc) differ in some details of json-rpc responses, e.g. some
+// omit fields which are required by go-ethereum, some wrap revert data in an object. normalizeTransport corrects
+// these known quirks so the commands behave identically regardless of provider.
+
+var zeroHash = "0x0000000000000000000000000000000000000000000000000000000000000000"
+var zeroBloom = "0x" + strings.Repeat("0", 512)
+
+// emptyUnclesHash is the sha3Uncles of a block without uncles.
+var emptyUnclesHash = "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347"
+
+// headerDefaults are the default values of header fields which are zero on non-PoW chains, so filling them doesn't
+// change the block hash. Fields such as stateRoot, transactionsRoot and receiptsRoot are never defaulted, a block
+// without them fails to decode rather than decodes with a wrong hash.
+var headerDefaults = map[string]interface{}{
+	"mixHash":   zeroHash,
+	"nonce":     "0x0000000000000000",
+	"logsBloom": zeroBloom,
+}
+
+// txDefaults are the default values of fields required by types.Transaction, some chains (e.g. system txs of L2)
+// return txs without signature.
+var txDefaults = map[string]interface{}{
+	"v":     "0x0",
+	"r":     "0x0",
+	"s":     "0x0",
+	"input": "0x",
+	"value": "0x0",
+}
+
+// receiptDefaults are the default values of fields required by types.Receipt
+var receiptDefaults = map[string]interface{}{
+	"logsBloom":         zeroBloom,
+	"logs":              []interface{}{},
+	"cumulativeGasUsed": "0x0",
+}
+
+// revertDataInMessageRE matches revert data in error message, e.g. "execution reverted: 0x08c379a0..."
+var revertDataInMessageRE = regexp.MustCompile(`(?i)revert[^0-9]*?(0x[0-9a-f]{8,})`)
+
+// fillDefaults sets missing (or null) fields of obj to their default value.
+func fillDefaults(obj map[string]interface{}, defaults map[string]interface{}) {
+	for key, value := range defaults {
+		if v, ok := obj[key]; !ok || v == nil {
+			obj[key] = value
+		}
+	}
+}
+
+// normalizeResult corrects result of method in place.
+func normalizeResult(method string, result interface{}) {
+	obj, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	switch method {
+	case "eth_getBlockByNumber", "eth_getBlockByHash", "eth_getUncleByBlockNumberAndIndex", "eth_getUncleByBlockHashAndIndex":
+		fillDefaults(obj, headerDefaults)
+		if uncles, ok := obj["uncles"].([]interface{}); ok && len(uncles) == 0 {
+			fillDefaults(obj, map[string]interface{}{"sha3Uncles": emptyUnclesHash})
+		}
+		if txs, ok := obj["transactions"].([]interface{}); ok {
+			for _, tx := range txs {
+				if txObj, ok := tx.(map[string]interface{}); ok {
+					fillDefaults(txObj, txDefaults)
+				}
+			}
+		}
+	case "eth_getTransactionByHash", "eth_getTransactionByBlockHashAndIndex", "eth_getTransactionByBlockNumberAndIndex":
+		fillDefaults(obj, txDefaults)
+	case "eth_getTransactionReceipt":
+		fillDefaults(obj, receiptDefaults)
+	}
+}
+
+// normalizeErrorData returns revert data as a 0x-prefixed hex string if it can be found in different error shapes,
+// otherwise data is returned unchanged.
+func normalizeErrorData(message string, data interface{}) interface{} {
+	switch d := data.(type) {
+	case string:
+		d = strings.TrimSpace(strings.TrimPrefix(d, "Reverted ")) // erigon and nethermind
+		if !has0xPrefix(d) && isHex(d) && len(d) >= 8 {
+			d = "0x" + d
+		}
+		return d
+	case map[string]interface{}:
+		// e.g. {"data": "0x..."} or {"originalError": {"data": "0x..."}}
+		for _, key := range []string{"data", "originalError", "result"} {
+			if v, ok := d[key]; ok {
+				if normalized, ok := normalizeErrorData(message, v).(string); ok && has0xPrefix(normalized) {
+					return normalized
+				}
+			}
+		}
+		return data
+	case nil:
+		if matches := revertDataInMessageRE.FindStringSubmatch(message); matches != nil {
+			return matches[1]
+		}
+		return nil
+	default:
+		return data
+	}
+}
+
+func has0xPrefix(str string) bool {
+	return len(str) >= 2 && str[0] == '0' && (str[1] == 'x' || str[1] == 'X')
+}
+
+func isHex(str string) bool {
+	if len(str)%2 != 0 {
+		return false
+	}
+	for _, c := range []byte(str) {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeMessage corrects a json-rpc response message in place, method is the method of corresponding request.
+func normalizeMessage(method string, msg map[string]interface{}) {
+	if result, ok := msg["result"]; ok {
+		normalizeResult(method, result)
+	}
+	if errObj, ok := msg["error"].(map[string]interface{}); ok {
+		message, _ := errObj["message"].(string)
+		if data := normalizeErrorData(message, errObj["data"]); data != nil {
+			errObj["data"] = data
+		}
+	}
+}
+
+// normalizeTransport is a http.RoundTripper which normalizes json-rpc responses.
+type normalizeTransport struct {
+	base http.RoundTripper
+}
+
+// NewNormalizeTransport returns a http.RoundTripper which corrects known provider quirks in json-rpc responses.
+func NewNormalizeTransport(base http.RoundTripper) http.RoundTripper {
+	return &normalizeTransport{base: base}
+}
+
+func (t *normalizeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(normalizeResponseBody(reqBody, respBody)))
+	resp.ContentLength = -1
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// normalizeResponseBody normalizes the response body of request body, respBody is returned unchanged if
+// it can't be parsed.
+func normalizeResponseBody(reqBody, respBody []byte) []byte {
+	type request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	var methods = make(map[string]string) // id -> method
+	var reqs []request
+	if err := json.Unmarshal(reqBody, &reqs); err != nil {
+		var req request
+		if err := json.Unmarshal(reqBody, &req); err != nil {
+			return respBody
+		}
+		reqs = append(reqs, req)
+	}
+	for _, req := range reqs {
+		methods[string(req.ID)] = req.Method
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(respBody))
+	decoder.UseNumber()
+	var resp interface{}
+	if err := decoder.Decode(&resp); err != nil {
+		return respBody
+	}
+
+	var msgs []interface{}
+	if batch, ok := resp.([]interface{}); ok {
+		msgs = batch
+	} else {
+		msgs = []interface{}{resp}
+	}
+	for _, m := range msgs {
+		msg, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, _ := json.Marshal(msg["id"])
+		normalizeMessage(methods[string(id)], msg)
+	}
+
+	normalized, err := json.Marshal(resp)
+	if err != nil {
+		return respBody
+	}
+	return normalized
+}
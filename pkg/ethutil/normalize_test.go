@@ -0,0 +1,87 @@
+package ethutil
+
+import (
+	"testing"
+)
+
+func TestNormalizeErrorData(t *testing.T) {
+	tests := []struct {
+		message string
+		data    interface{}
+		want    interface{}
+	}{
+		{
+			message: "execution reverted",
+			data:    "0x08c379a0",
+			want:    "0x08c379a0",
+		},
+		{
+			message: "VM execution error.",
+			data:    "Reverted 0x08c379a0",
+			want:    "0x08c379a0",
+		},
+		{
+			message: "execution reverted",
+			data:    "08c379a0",
+			want:    "0x08c379a0",
+		},
+		{
+			message: "execution reverted",
+			data:    map[string]interface{}{"message": "execution reverted", "data": "0x08c379a0"},
+			want:    "0x08c379a0",
+		},
+		{
+			message: "execution reverted",
+			data:    map[string]interface{}{"originalError": map[string]interface{}{"data": "0x08c379a0"}},
+			want:    "0x08c379a0",
+		},
+		{
+			message: "execution reverted: 0x08c379a0",
+			data:    nil,
+			want:    "0x08c379a0",
+		},
+		{
+			message: "insufficient funds for gas * price + value",
+			data:    nil,
+			want:    nil,
+		},
+	}
+
+	for i, tt := range tests {
+		output := normalizeErrorData(tt.message, tt.data)
+		if output != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+		}
+	}
+}
+
+func TestNormalizeResponseBody(t *testing.T) {
+	tests := []struct {
+		request  string
+		response string
+		want     string
+	}{
+		{
+			request:  `{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionByHash","params":["0x01"]}`,
+			response: `{"jsonrpc":"2.0","id":1,"result":{"hash":"0x01","nonce":"0x1","gas":"0x5208","input":"0x","value":"0x1"}}`,
+			want:     `{"id":1,"jsonrpc":"2.0","result":{"gas":"0x5208","hash":"0x01","input":"0x","nonce":"0x1","r":"0x0","s":"0x0","v":"0x0","value":"0x1"}}`,
+		},
+		{
+			request:  `{"jsonrpc":"2.0","id":1,"method":"eth_getBlockByNumber","params":["0x10",false]}`,
+			response: `{"jsonrpc":"2.0","id":1,"result":{"number":"0x10","uncles":[],"transactions":[]}}`,
+			want:     `{"id":1,"jsonrpc":"2.0","result":{"logsBloom":"` + zeroBloom + `","mixHash":"` + zeroHash + `","nonce":"0x0000000000000000","number":"0x10","sha3Uncles":"` + emptyUnclesHash + `","transactions":[],"uncles":[]}}`,
+		},
+		{
+			request:  `[{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[]},{"jsonrpc":"2.0","id":2,"method":"eth_blockNumber","params":[]}]`,
+			response: `[{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"VM execution error.","data":"Reverted 0x08c379a0"}},{"jsonrpc":"2.0","id":2,"result":"0x10"}]`,
+			want:     `[{"error":{"code":3,"data":"0x08c379a0","message":"VM execution error."},"id":1,"jsonrpc":"2.0"},{"id":2,"jsonrpc":"2.0","result":"0x10"}]`,
+		},
+	}
+
+	for i, tt := range tests {
+		output := string(normalizeResponseBody([]byte(tt.request), []byte(tt.response)))
+		if output != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+		}
+	}
+}
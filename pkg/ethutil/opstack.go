@@ -0,0 +1,124 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// L2ToL1MessagePasserAddress is the predeploy of OP-stack chains storing withdrawals initiated on L2, its storage
+// root is committed in output root so withdrawals can be proven on L1.
+var L2ToL1MessagePasserAddress = common.HexToAddress("0x4200000000000000000000000000000000000016")
+
+// OutputRootProof is the preimage of OP-stack output root (version 0), which is the outputRootProof argument of
+// OptimismPortal.proveWithdrawalTransaction.
+type OutputRootProof struct {
+	Version                  common.Hash
+	StateRoot                common.Hash
+	MessagePasserStorageRoot common.Hash
+	LatestBlockhash          common.Hash
+}
+
+// OutputRoot returns keccak256(version ++ stateRoot ++ messagePasserStorageRoot ++ latestBlockhash).
+func (p OutputRootProof) OutputRoot() common.Hash {
+	return crypto.Keccak256Hash(p.Version[:], p.StateRoot[:], p.MessagePasserStorageRoot[:], p.LatestBlockhash[:])
+}
+
+// ComputeOutputRoot computes output root proof of L2 block from the L2 node, which must serve eth_getProof at the block.
+func ComputeOutputRoot(ctx context.Context, l2 *Client, blockNumber *big.Int) (*OutputRootProof, error) {
+	header, err := l2.EthClient.HeaderByNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("get header of L2 block %v fail: %w", blockNumber, err)
+	}
+	var proof struct {
+		StorageHash common.Hash `json:"storageHash"`
+	}
+	if err := l2.RpcClient.CallContext(ctx, &proof, "eth_getProof", L2ToL1MessagePasserAddress, []string{}, hexutil.EncodeBig(blockNumber)); err != nil {
+		return nil, fmt.Errorf("get proof of L2ToL1MessagePasser at L2 block %v fail: %w", blockNumber, err)
+	}
+	return &OutputRootProof{
+		StateRoot:                header.Root,
+		MessagePasserStorageRoot: proof.StorageHash,
+		LatestBlockhash:          header.Hash(),
+	}, nil
+}
+
+// OutputProposal is an output root proposed on L1, by L2OutputOracle (before fault proofs) or by a dispute game.
+type OutputProposal struct {
+	OutputRoot    common.Hash
+	L2BlockNumber *big.Int
+	Timestamp     uint64 // the time of proposal
+	Game          *common.Address
+	GameStatus    uint8 // see GameStatus* constants, only valid if Game is not nil
+}
+
+// Status of dispute game, the output root of game is valid only if defender wins.
+const (
+	GameStatusInProgress     = 0
+	GameStatusChallengerWins = 1
+	GameStatusDefenderWins   = 2
+)
+
+// L2OutputOracleProposal returns the output proposal at index of L2OutputOracle.
+func L2OutputOracleProposal(ctx context.Context, l1 *Client, oracle common.Address, index *big.Int) (*OutputProposal, error) {
+	// the returned struct Types.OutputProposal has only static fields, which is encoded as the fields in sequence
+	values, err := CallAndUnpack(ctx, l1.EthClient, oracle, "function getL2Output(uint256) returns (bytes32, uint128, uint128)", []string{index.String()})
+	if err != nil {
+		return nil, err
+	}
+	return &OutputProposal{
+		OutputRoot:    values[0].([32]byte),
+		Timestamp:     values[1].(*big.Int).Uint64(),
+		L2BlockNumber: values[2].(*big.Int),
+	}, nil
+}
+
+// L2OutputIndexAfter returns index of the first output proposal of L2OutputOracle covering l2BlockNumber, i.e. the
+// proposal to prove withdrawals initiated in that block.
+func L2OutputIndexAfter(ctx context.Context, l1 *Client, oracle common.Address, l2BlockNumber *big.Int) (*big.Int, error) {
+	values, err := CallAndUnpack(ctx, l1.EthClient, oracle, "function getL2OutputIndexAfter(uint256) returns (uint256)", []string{l2BlockNumber.String()})
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// DisputeGameAtIndex returns the game proxy at index of DisputeGameFactory.
+func DisputeGameAtIndex(ctx context.Context, l1 *Client, factory common.Address, index *big.Int) (common.Address, error) {
+	values, err := CallAndUnpack(ctx, l1.EthClient, factory, "function gameAtIndex(uint256) returns (uint32, uint64, address)", []string{index.String()})
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[2].(common.Address), nil
+}
+
+// DisputeGameProposal returns the output root claimed by dispute game.
+func DisputeGameProposal(ctx context.Context, l1 *Client, game common.Address) (*OutputProposal, error) {
+	rootClaim, err := CallAndUnpack(ctx, l1.EthClient, game, "function rootClaim() returns (bytes32)", nil)
+	if err != nil {
+		return nil, err
+	}
+	l2BlockNumber, err := CallAndUnpack(ctx, l1.EthClient, game, "function l2BlockNumber() returns (uint256)", nil)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := CallAndUnpack(ctx, l1.EthClient, game, "function createdAt() returns (uint64)", nil)
+	if err != nil {
+		return nil, err
+	}
+	status, err := CallAndUnpack(ctx, l1.EthClient, game, "function status() returns (uint8)", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OutputProposal{
+		OutputRoot:    rootClaim[0].([32]byte),
+		L2BlockNumber: l2BlockNumber[0].(*big.Int),
+		Timestamp:     createdAt[0].(uint64),
+		Game:          &game,
+		GameStatus:    status[0].(uint8),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestOutputRoot(t *testing.T) {
+	proof := OutputRootProof{
+		StateRoot:                common.HexToHash("0x01"),
+		MessagePasserStorageRoot: common.HexToHash("0x02"),
+		LatestBlockhash:          common.HexToHash("0x03"),
+	}
+	preimage := make([]byte, 128)
+	preimage[63], preimage[95], preimage[127] = 1, 2, 3
+	if proof.OutputRoot() != crypto.Keccak256Hash(preimage) {
+		t.Errorf("unexpected output root %v", proof.OutputRoot().Hex())
+	}
+}
+
+func TestComputeOutputRoot(t *testing.T) {
+	stateRoot := common.HexToHash("0xaa")
+	storageRoot := common.HexToHash("0xbb")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		var result string
+		switch req.Method {
+		case "eth_getBlockByNumber":
+			result = fmt.Sprintf(`{"parentHash":"%v","sha3Uncles":"%v","miner":"%v","stateRoot":"%v","transactionsRoot":"%v",
+"receiptsRoot":"%v","logsBloom":"0x%0512x","difficulty":"0x0","number":"0x64","gasLimit":"0x1c9c380","gasUsed":"0x0",
+"timestamp":"0x64","extraData":"0x","mixHash":"%v","nonce":"0x0000000000000000","baseFeePerGas":"0x1"}`,
+				common.Hash{}.Hex(), common.Hash{}.Hex(), common.Address{}.Hex(), stateRoot.Hex(), common.Hash{}.Hex(),
+				common.Hash{}.Hex(), 0, common.Hash{}.Hex())
+		case "eth_getProof":
+			if string(req.Params[0]) != `"0x4200000000000000000000000000000000000016"` || string(req.Params[2]) != `"0x64"` {
+				t.Errorf("unexpected params %s", req.Params)
+			}
+			result = fmt.Sprintf(`{"storageHash":"%v"}`, storageRoot.Hex())
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%v}`, req.Id, result)
+	}))
+	defer server.Close()
+
+	client, err := Dial(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := ComputeOutputRoot(context.Background(), client, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proof.StateRoot != stateRoot || proof.MessagePasserStorageRoot != storageRoot || proof.LatestBlockhash == (common.Hash{}) {
+		t.Errorf("unexpected proof %+v", proof)
+	}
+}
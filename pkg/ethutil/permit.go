@@ -0,0 +1,119 @@
+package ethutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// PermitTypeHash is the EIP-712 type hash of EIP-2612 Permit
+var PermitTypeHash = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+
+// PermitFuncSignature is the function signature of EIP-2612 permit
+const PermitFuncSignature = "permit(address,address,uint256,uint256,uint8,bytes32,bytes32)"
+
+// Permit is the EIP-2612 permit message, which approves Spender to spend Value of Owner's token until Deadline.
+// See: https://eips.ethereum.org/EIPS/eip-2612
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int // unix timestamp
+}
+
+// PermitDomainSeparator computes EIP-712 domain separator of token, which is
+// keccak256(abi.encode(keccak256("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"), keccak256(name), keccak256(version), chainId, token))
+func PermitDomainSeparator(name, version string, chainID *big.Int, token common.Address) (common.Hash, error) {
+	data, err := EncodeParameters([]string{"bytes32", "bytes32", "bytes32", "uint256", "address"}, []string{
+		crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)")).Hex(),
+		crypto.Keccak256Hash([]byte(name)).Hex(),
+		crypto.Keccak256Hash([]byte(version)).Hex(),
+		chainID.String(),
+		token.Hex(),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// Hash returns the EIP-712 digest of permit, i.e. keccak256("\x19\x01" || domainSeparator || hashStruct(permit)).
+func (p *Permit) Hash(domainSeparator common.Hash) (common.Hash, error) {
+	structData, err := EncodeParameters([]string{"bytes32", "address", "address", "uint256", "uint256", "uint256"}, []string{
+		PermitTypeHash.Hex(),
+		p.Owner.Hex(),
+		p.Spender.Hex(),
+		p.Value.String(),
+		p.Nonce.String(),
+		p.Deadline.String(),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], crypto.Keccak256(structData)), nil
+}
+
+// Sign signs permit by privateKey of owner, returns 65 bytes signature r || s || v, v is 27 or 28.
+func (p *Permit) Sign(domainSeparator common.Hash, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	if AddressFromPrivateKey(privateKey) != p.Owner {
+		return nil, fmt.Errorf("private key is not of owner %v", p.Owner.Hex())
+	}
+	hash, err := p.Hash(domainSeparator)
+	if err != nil {
+		return nil, err
+	}
+	return SignHash(hash[:], privateKey)
+}
+
+// CallData returns input data of permit(owner, spender, value, deadline, v, r, s) with signature r || s || v.
+func (p *Permit) CallData(signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes, got %v bytes", len(signature))
+	}
+	return BuildTxInputData(PermitFuncSignature, []string{
+		p.Owner.Hex(),
+		p.Spender.Hex(),
+		p.Value.String(),
+		p.Deadline.String(),
+		fmt.Sprintf("%d", signature[64]),
+		hexutil.Encode(signature[:32]),
+		hexutil.Encode(signature[32:64]),
+	})
+}
+
+// PermitTokenInfo is the information of EIP-2612 token required to sign permit.
+type PermitTokenInfo struct {
+	Name            string
+	Version         string // "1" if token does not implement version()
+	Nonce           *big.Int
+	DomainSeparator common.Hash // zero if token does not implement DOMAIN_SEPARATOR()
+}
+
+// QueryPermitTokenInfo queries name, version, nonce of owner and domain separator of token.
+func QueryPermitTokenInfo(ctx context.Context, client *ethclient.Client, token common.Address, owner common.Address) (*PermitTokenInfo, error) {
+	name, err := CallAndUnpack(ctx, client, token, "function name() returns (string)", nil)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := CallAndUnpack(ctx, client, token, "function nonces(address) returns (uint256)", []string{owner.Hex()})
+	if err != nil {
+		return nil, fmt.Errorf("%w, token may not support EIP-2612 permit", err)
+	}
+	info := &PermitTokenInfo{Name: name[0].(string), Version: "1", Nonce: nonce[0].(*big.Int)}
+
+	// version() and DOMAIN_SEPARATOR() are optional
+	if version, err := CallAndUnpack(ctx, client, token, "function version() returns (string)", nil); err == nil {
+		info.Version = version[0].(string)
+	}
+	if domainSeparator, err := CallAndUnpack(ctx, client, token, "function DOMAIN_SEPARATOR() returns (bytes32)", nil); err == nil {
+		info.DomainSeparator = domainSeparator[0].([32]byte)
+	}
+	return info, nil
+}
@@ -0,0 +1,71 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPermitSign(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	token := common.HexToAddress("0x1f9840a85d5aF5bf1D1762F925BDADdC4201F984")
+	domainSeparator, err := PermitDomainSeparator("Uniswap", "1", big.NewInt(1), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		value    *big.Int
+		nonce    *big.Int
+		deadline *big.Int
+	}{
+		{big.NewInt(1000000), big.NewInt(0), big.NewInt(1700000000)},
+		{new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)), big.NewInt(5), big.NewInt(1800000000)},
+	}
+
+	for i, test := range tests {
+		permit := &Permit{
+			Owner:    AddressFromPrivateKey(privateKey),
+			Spender:  common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"),
+			Value:    test.value,
+			Nonce:    test.nonce,
+			Deadline: test.deadline,
+		}
+		signature, err := permit.Sign(domainSeparator, privateKey)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		hash, err := permit.Hash(domainSeparator)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recoverable := append([]byte{}, signature...)
+		recoverable[64] -= 27
+		pubkey, err := crypto.SigToPub(hash[:], recoverable)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signer := crypto.PubkeyToAddress(*pubkey); signer != permit.Owner {
+			t.Fatalf("test %d: expected: %v, got: %v", i, permit.Owner.Hex(), signer.Hex())
+		}
+
+		data, err := permit.CallData(signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if selector := hexutil.Encode(data[:4]); selector != "0xd505accf" {
+			t.Fatalf("test %d: expected: %v, got: %v", i, "0xd505accf", selector)
+		}
+		if len(data) != 4+7*32 {
+			t.Fatalf("test %d: expected: %v, got: %v", i, 4+7*32, len(data))
+		}
+	}
+
+	other := &Permit{Owner: common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"), Spender: common.Address{}, Value: big.NewInt(1), Nonce: big.NewInt(0), Deadline: big.NewInt(0)}
+	if _, err := other.Sign(domainSeparator, privateKey); err == nil {
+		t.Fatalf("expected error for private key of other owner")
+	}
+}
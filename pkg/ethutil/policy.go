@@ -0,0 +1,270 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyCreate is the destination of contract creation tx in PolicyRule.Destinations.
+const PolicyCreate = "create"
+
+// PolicyNoData is the selector of tx without input data (plain eth transfer) in PolicyRule.Selectors.
+const PolicyNoData = "0x"
+
+// Policy is a list of rules evaluated before signing a tx, the tx is allowed only if it matches at least one rule,
+// for example:
+//
+//	{
+//	  "rules": [
+//	    {
+//	      "name": "payroll",
+//	      "chain_ids": [1],
+//	      "destinations": ["0xdAC17F958D2ee523a2206206994597C13D831ec7"],
+//	      "selectors": ["0xa9059cbb"],
+//	      "max_value": "0",
+//	      "time_window": {"weekdays": ["mon", "tue", "wed", "thu", "fri"], "start": "09:00", "end": "18:00", "timezone": "UTC"},
+//	      "require_confirmation": true
+//	    }
+//	  ]
+//	}
+//
+// or in YAML:
+//
+//	rules:
+//	  - name: payroll
+//	    chain_ids: [1]
+//	    destinations: ["0xdAC17F958D2ee523a2206206994597C13D831ec7"]
+//	    selectors: ["0xa9059cbb"]
+//	    max_value: "0"
+//	    time_window: {weekdays: [mon, tue, wed, thu, fri], start: "09:00", end: "18:00", timezone: UTC}
+//	    require_confirmation: true
+type Policy struct {
+	Rules []PolicyRule `json:"rules"`
+}
+
+// PolicyRule matches tx, an empty field matches anything.
+type PolicyRule struct {
+	Name                string            `json:"name"`
+	ChainIds            []uint64          `json:"chain_ids,omitempty"`
+	Destinations        []string          `json:"destinations,omitempty"` // address, or "create" for contract creation
+	Selectors           []string          `json:"selectors,omitempty"`    // 4 bytes function selector, or "0x" for tx without data
+	MaxValue            string            `json:"max_value,omitempty"`    // unit is wei
+	TimeWindow          *PolicyTimeWindow `json:"time_window,omitempty"`
+	RequireConfirmation bool              `json:"require_confirmation,omitempty"`
+}
+
+// PolicyTimeWindow is the daily time window [Start, End) in Timezone (default UTC), End before Start means the window
+// spans midnight.
+type PolicyTimeWindow struct {
+	Weekdays []string `json:"weekdays,omitempty"` // sun, mon, tue, wed, thu, fri, sat
+	Start    string   `json:"start,omitempty"`    // 15:04, default 00:00
+	End      string   `json:"end,omitempty"`      // 15:04, default 24:00
+	Timezone string   `json:"timezone,omitempty"` // IANA name, e.g. Asia/Shanghai
+}
+
+// PolicyTx is the tx evaluated by Policy.
+type PolicyTx struct {
+	ChainId *big.Int
+	To      *common.Address // nil means contract creation
+	Value   *big.Int
+	Data    []byte
+	Time    time.Time
+}
+
+var policyWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParsePolicy parses and validates policy file content, which is JSON or YAML with the same field names.
+func ParsePolicy(content []byte) (*Policy, error) {
+	if !json.Valid(content) {
+		// YAML is converted to JSON, so the json tags of fields are reused
+		var doc any
+		if err := yaml.Unmarshal(content, &doc); err != nil {
+			return nil, fmt.Errorf("parse policy fail: %w", err)
+		}
+		var err error
+		if content, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("parse policy fail: %w", err)
+		}
+	}
+	var policy Policy
+	if err := json.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("parse policy fail: %w", err)
+	}
+	if len(policy.Rules) == 0 {
+		return nil, fmt.Errorf("policy has no rule, every tx would be denied")
+	}
+	for i, rule := range policy.Rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("rule %v (%v): %w", i, rule.Name, err)
+		}
+	}
+	return &policy, nil
+}
+
+func (r *PolicyRule) validate() error {
+	for _, destination := range r.Destinations {
+		if destination != PolicyCreate && !common.IsHexAddress(destination) {
+			return fmt.Errorf("invalid destination %v", destination)
+		}
+	}
+	for _, selector := range r.Selectors {
+		if b, err := hexutil.Decode(selector); selector != PolicyNoData && (err != nil || len(b) != 4) {
+			return fmt.Errorf("invalid selector %v", selector)
+		}
+	}
+	if r.MaxValue != "" {
+		if _, ok := new(big.Int).SetString(r.MaxValue, 10); !ok {
+			return fmt.Errorf("invalid max_value %v", r.MaxValue)
+		}
+	}
+	if w := r.TimeWindow; w != nil {
+		for _, weekday := range w.Weekdays {
+			if _, ok := policyWeekdays[strings.ToLower(weekday)]; !ok {
+				return fmt.Errorf("invalid weekday %v", weekday)
+			}
+		}
+		for _, clock := range []string{w.Start, w.End} {
+			if _, err := parsePolicyClock(clock, 0); err != nil {
+				return err
+			}
+		}
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %v", w.Timezone)
+		}
+	}
+	return nil
+}
+
+// parsePolicyClock returns minutes since midnight of HH:MM, def is returned if clock is empty.
+func parsePolicyClock(clock string, def int) (int, error) {
+	if clock == "" {
+		return def, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %v, format is HH:MM", clock)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// match returns nil if tx matches the rule, or the reason why not.
+func (r *PolicyRule) match(tx PolicyTx) error {
+	if len(r.ChainIds) > 0 {
+		var found bool
+		for _, chainId := range r.ChainIds {
+			found = found || (tx.ChainId != nil && tx.ChainId.Cmp(new(big.Int).SetUint64(chainId)) == 0)
+		}
+		if !found {
+			return fmt.Errorf("chain id %v is not allowed", tx.ChainId)
+		}
+	}
+
+	if len(r.Destinations) > 0 {
+		var found bool
+		for _, destination := range r.Destinations {
+			if tx.To == nil {
+				found = found || destination == PolicyCreate
+			} else {
+				found = found || (destination != PolicyCreate && common.HexToAddress(destination) == *tx.To)
+			}
+		}
+		if !found {
+			var to = PolicyCreate
+			if tx.To != nil {
+				to = tx.To.Hex()
+			}
+			return fmt.Errorf("destination %v is not allowed", to)
+		}
+	}
+
+	if len(r.Selectors) > 0 {
+		var selector = PolicyNoData
+		if len(tx.Data) >= 4 {
+			selector = hexutil.Encode(tx.Data[:4])
+		} else if len(tx.Data) > 0 {
+			selector = hexutil.Encode(tx.Data)
+		}
+		var found bool
+		for _, s := range r.Selectors {
+			found = found || strings.EqualFold(s, selector)
+		}
+		if !found {
+			return fmt.Errorf("selector %v is not allowed", selector)
+		}
+	}
+
+	if r.MaxValue != "" {
+		maxValue, _ := new(big.Int).SetString(r.MaxValue, 10) // validated in ParsePolicy
+		if tx.Value != nil && tx.Value.Cmp(maxValue) > 0 {
+			return fmt.Errorf("value %v wei exceeds max value %v wei", tx.Value, maxValue)
+		}
+	}
+
+	if w := r.TimeWindow; w != nil {
+		location, _ := time.LoadLocation(w.Timezone) // validated in ParsePolicy
+		now := tx.Time.In(location)
+		if len(w.Weekdays) > 0 {
+			var found bool
+			for _, weekday := range w.Weekdays {
+				found = found || policyWeekdays[strings.ToLower(weekday)] == now.Weekday()
+			}
+			if !found {
+				return fmt.Errorf("%v is not allowed", now.Weekday())
+			}
+		}
+		start, _ := parsePolicyClock(w.Start, 0)
+		end, _ := parsePolicyClock(w.End, 24*60)
+		minutes := now.Hour()*60 + now.Minute()
+		var inWindow bool
+		if start <= end {
+			inWindow = minutes >= start && minutes < end
+		} else {
+			inWindow = minutes >= start || minutes < end
+		}
+		if !inWindow {
+			return fmt.Errorf("%v is out of time window %v-%v %v", now.Format("15:04"), w.Start, w.End, location)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the first rule matched by tx, or error containing the reasons of all rules if no rule matches.
+func (p *Policy) Evaluate(tx PolicyTx) (*PolicyRule, error) {
+	var reasons []string
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if err := rule.match(tx); err != nil {
+			reasons = append(reasons, fmt.Sprintf("rule %v: %v", rule.Name, err))
+			continue
+		}
+		return rule, nil
+	}
+	return nil, fmt.Errorf("tx is denied by policy: %v", strings.Join(reasons, "; "))
+}
+
+// VerifyPolicySignature checks that signature is the personal_sign signature of policy file content by signer.
+// The signature can be created by PersonalSignBytes.
+func VerifyPolicySignature(content []byte, signature string, signer common.Address) error {
+	sig, err := hexutil.Decode(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("invalid policy signature")
+	}
+	recovered, err := RecoverPersonalSignBytes(content, sig)
+	if err != nil {
+		return fmt.Errorf("recover policy signer fail: %w", err)
+	}
+	if recovered != signer {
+		return fmt.Errorf("policy is signed by %v, not by trusted signer %v", recovered.Hex(), signer.Hex())
+	}
+	return nil
+}
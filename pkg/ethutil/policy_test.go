@@ -0,0 +1,149 @@
+package ethutil
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestPolicyEvaluate(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{
+  "rules": [
+    {
+      "name": "usdt-transfer",
+      "chain_ids": [1],
+      "destinations": ["0xdAC17F958D2ee523a2206206994597C13D831ec7"],
+      "selectors": ["0xa9059cbb"],
+      "max_value": "0",
+      "time_window": {"weekdays": ["mon", "tue", "wed", "thu", "fri"], "start": "09:00", "end": "18:00", "timezone": "UTC"}
+    },
+    {
+      "name": "small-eth",
+      "selectors": ["0x"],
+      "max_value": "1000000000000000000",
+      "time_window": {"start": "22:00", "end": "02:00"}
+    }
+  ]
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usdt := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	other := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	transfer := hexutil.MustDecode("0xa9059cbb0000")
+	approve := hexutil.MustDecode("0x095ea7b30000")
+	monday := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	sunday := time.Date(2023, 4, 30, 10, 0, 0, 0, time.UTC)
+	night := time.Date(2023, 4, 30, 23, 30, 0, 0, time.UTC)
+	early := time.Date(2023, 5, 1, 1, 59, 0, 0, time.UTC)
+	oneEther, _ := new(big.Int).SetString("1000000000000000000", 10)
+	twoEther := new(big.Int).Mul(oneEther, big.NewInt(2))
+
+	tests := []struct {
+		tx   PolicyTx
+		want string // name of matched rule, empty means denied
+	}{
+		{PolicyTx{ChainId: big.NewInt(1), To: &usdt, Value: big.NewInt(0), Data: transfer, Time: monday}, "usdt-transfer"},
+		{PolicyTx{ChainId: big.NewInt(5), To: &usdt, Value: big.NewInt(0), Data: transfer, Time: monday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &usdt, Value: big.NewInt(0), Data: approve, Time: monday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &other, Value: big.NewInt(0), Data: transfer, Time: monday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &usdt, Value: big.NewInt(1), Data: transfer, Time: monday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &usdt, Value: big.NewInt(0), Data: transfer, Time: sunday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &other, Value: oneEther, Time: night}, "small-eth"},
+		{PolicyTx{ChainId: big.NewInt(1), To: &other, Value: oneEther, Time: early}, "small-eth"},
+		{PolicyTx{ChainId: big.NewInt(1), To: &other, Value: twoEther, Time: night}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: &other, Value: oneEther, Time: monday}, ""},
+		{PolicyTx{ChainId: big.NewInt(1), To: nil, Value: big.NewInt(0), Time: night}, "small-eth"},
+	}
+
+	for i, test := range tests {
+		rule, err := policy.Evaluate(test.tx)
+		var got string
+		if err == nil {
+			got = rule.Name
+		}
+		if got != test.want {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.want, got, err)
+		}
+	}
+}
+
+func TestParsePolicyYAML(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`
+rules:
+  - name: usdt-transfer
+    chain_ids: [1]
+    destinations: ["0xdAC17F958D2ee523a2206206994597C13D831ec7"]
+    selectors: ["0xa9059cbb"]
+    max_value: "0"
+    time_window: {weekdays: [mon, tue, wed, thu, fri], start: "09:00", end: "18:00", timezone: UTC}
+    require_confirmation: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PolicyRule{
+		Name:                "usdt-transfer",
+		ChainIds:            []uint64{1},
+		Destinations:        []string{"0xdAC17F958D2ee523a2206206994597C13D831ec7"},
+		Selectors:           []string{"0xa9059cbb"},
+		MaxValue:            "0",
+		TimeWindow:          &PolicyTimeWindow{Weekdays: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "09:00", End: "18:00", Timezone: "UTC"},
+		RequireConfirmation: true,
+	}
+	if len(policy.Rules) != 1 || !reflect.DeepEqual(policy.Rules[0], want) {
+		t.Fatalf("expected: %+v, got: %+v", want, policy.Rules)
+	}
+}
+
+func TestParsePolicyInvalid(t *testing.T) {
+	tests := []string{
+		`{"rules": []}`,
+		`{"rules": [{"destinations": ["0x1234"]}]}`,
+		`{"rules": [{"selectors": ["0xa9059c"]}]}`,
+		`{"rules": [{"max_value": "1 ether"}]}`,
+		`{"rules": [{"time_window": {"weekdays": ["monday"]}}]}`,
+		`{"rules": [{"time_window": {"start": "9am"}}]}`,
+		`{"rules": [{"time_window": {"timezone": "Mars/Olympus"}}]}`,
+		"rules:\n  - destinations: [0x1234]",
+		"rules: [",
+	}
+
+	for i, test := range tests {
+		if _, err := ParsePolicy([]byte(test)); err == nil {
+			t.Fatalf("test %d: expected: error, got: nil", i)
+		}
+	}
+}
+
+func TestVerifyPolicySignature(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	signer := AddressFromPrivateKey(privateKey)
+	content := []byte(`{"rules": [{"name": "any"}]}`)
+	signature, err := PersonalSignBytes(content, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		content []byte
+		signer  common.Address
+		valid   bool
+	}{
+		{content, signer, true},
+		{[]byte(`{"rules": [{"name": "all"}]}`), signer, false},
+		{content, common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"), false},
+	}
+
+	for i, test := range tests {
+		err := VerifyPolicySignature(test.content, signature+"\n", test.signer)
+		if valid := err == nil; valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.valid, valid, err)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// erc20TransferTopic is topic0 of event Transfer(address indexed from, address indexed to, uint256 value)
+var erc20TransferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// TokenTransfer is an ERC-20 Transfer event.
+type TokenTransfer struct {
+	Block    uint64
+	TxHash   common.Hash
+	LogIndex uint
+	Token    common.Address
+	From     common.Address
+	To       common.Address
+	Value    *big.Int
+}
+
+// parseTokenTransfers parses ERC-20 Transfer events in logs, ERC-721 Transfer events (whose token id is indexed) and
+// removed logs are skipped. Transfers are sorted from the latest.
+func parseTokenTransfers(logs []types.Log) []TokenTransfer {
+	var transfers []TokenTransfer
+	var seen = make(map[string]bool)
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) != 3 || log.Topics[0] != erc20TransferTopic || len(log.Data) != 32 {
+			continue
+		}
+		// a transfer to self is matched by both from and to queries
+		key := log.TxHash.Hex() + ":" + strconv.FormatUint(uint64(log.Index), 10)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		transfers = append(transfers, TokenTransfer{
+			Block:    log.BlockNumber,
+			TxHash:   log.TxHash,
+			LogIndex: log.Index,
+			Token:    log.Address,
+			From:     common.BytesToAddress(log.Topics[1].Bytes()),
+			To:       common.BytesToAddress(log.Topics[2].Bytes()),
+			Value:    new(big.Int).SetBytes(log.Data),
+		})
+	}
+	sort.SliceStable(transfers, func(i, j int) bool {
+		if transfers[i].Block != transfers[j].Block {
+			return transfers[i].Block > transfers[j].Block
+		}
+		return transfers[i].LogIndex > transfers[j].LogIndex
+	})
+	return transfers
+}
+
+// RecentTokenTransfers returns ERC-20 transfers from or to address in blocks [fromBlock, toBlock] by eth_getLogs,
+// sorted from the latest. The blocks are queried backward in ranges of rangeSize blocks (many providers limit the
+// range of eth_getLogs), and it stops once limit (0 means no limit) transfers are found.
+func RecentTokenTransfers(ctx context.Context, client *ethclient.Client, address common.Address, fromBlock, toBlock uint64, rangeSize uint64, limit int) ([]TokenTransfer, error) {
+	if rangeSize == 0 {
+		rangeSize = toBlock - fromBlock + 1
+	}
+	topic := common.BytesToHash(address.Bytes())
+	var transfers []TokenTransfer
+	for end := toBlock; end >= fromBlock; {
+		start := fromBlock
+		if end-fromBlock+1 > rangeSize {
+			start = end + 1 - rangeSize
+		}
+		var logs []types.Log
+		for _, topics := range [][][]common.Hash{
+			{{erc20TransferTopic}, {topic}},      // sent
+			{{erc20TransferTopic}, nil, {topic}}, // received
+		} {
+			result, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Topics:    topics,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("FilterLogs of blocks [%v, %v] fail: %w", start, end, err)
+			}
+			logs = append(logs, result...)
+		}
+		transfers = append(transfers, parseTokenTransfers(logs)...)
+		if (limit > 0 && len(transfers) >= limit) || start == 0 {
+			break
+		}
+		end = start - 1
+	}
+	if limit > 0 && len(transfers) > limit {
+		transfers = transfers[:limit]
+	}
+	return transfers, nil
+}
+
+// NonceGap is the nonce state of an account in mempool of node.
+type NonceGap struct {
+	Latest  uint64   // nonce at latest block, i.e. the number of mined txs
+	Pending uint64   // nonce at pending block, Pending - Latest txs are waiting to be mined
+	Queued  []uint64 // nonces of queued txs, which are not executable until the missing nonces are filled
+	Missing []uint64 // nonces missing between Pending and the largest queued nonce
+}
+
+// missingNonces returns the nonces in [pending, max(queued)) which are not queued.
+func missingNonces(pending uint64, queued []uint64) []uint64 {
+	var queuedSet = make(map[uint64]bool)
+	var max uint64
+	for _, nonce := range queued {
+		queuedSet[nonce] = true
+		if nonce > max {
+			max = nonce
+		}
+	}
+	var missing []uint64
+	for nonce := pending; nonce < max; nonce++ {
+		if !queuedSet[nonce] {
+			missing = append(missing, nonce)
+		}
+	}
+	return missing
+}
+
+// DetectNonceGap returns nonce state of address. The queued txs are read by txpool_contentFrom, which is supported by
+// geth and its forks if txpool namespace is enabled, Queued and Missing are empty if it's not supported.
+func DetectNonceGap(ctx context.Context, client *Client, address common.Address) (*NonceGap, error) {
+	latest, err := client.EthClient.NonceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("NonceAt fail: %w", err)
+	}
+	pending, err := client.EthClient.PendingNonceAt(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("PendingNonceAt fail: %w", err)
+	}
+	gap := &NonceGap{Latest: latest, Pending: pending}
+
+	var content struct {
+		Queued map[string]json.RawMessage `json:"queued"`
+	}
+	if err := client.RpcClient.CallContext(ctx, &content, "txpool_contentFrom", address); err != nil {
+		var rpcErr rpc.Error
+		var httpErr rpc.HTTPError
+		if errors.As(err, &rpcErr) || errors.As(err, &httpErr) {
+			return gap, nil // not supported
+		}
+		return nil, fmt.Errorf("txpool_contentFrom fail: %w", err)
+	}
+	for key := range content.Queued {
+		nonce, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonce %v in txpool_contentFrom result", key)
+		}
+		if nonce >= pending {
+			gap.Queued = append(gap.Queued, nonce)
+		}
+	}
+	sort.Slice(gap.Queued, func(i, j int) bool { return gap.Queued[i] < gap.Queued[j] })
+	gap.Missing = missingNonces(pending, gap.Queued)
+	return gap, nil
+}
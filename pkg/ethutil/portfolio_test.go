@@ -0,0 +1,62 @@
+package ethutil
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestParseTokenTransfers(t *testing.T) {
+	alice, bob := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b")
+	transfer := func(block uint64, index uint, from, to common.Address, value byte) types.Log {
+		return types.Log{
+			BlockNumber: block,
+			Index:       index,
+			TxHash:      common.BigToHash(common.Big1),
+			Topics:      []common.Hash{erc20TransferTopic, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+			Data:        common.LeftPadBytes([]byte{value}, 32),
+		}
+	}
+	nft := transfer(3, 0, alice, bob, 0)
+	nft.Topics = append(nft.Topics, common.BigToHash(common.Big1)) // token id of ERC-721 is indexed
+	nft.Data = nil
+	removed := transfer(4, 0, alice, bob, 9)
+	removed.Removed = true
+
+	logs := []types.Log{
+		transfer(1, 0, alice, bob, 1),
+		transfer(2, 5, bob, alice, 2),
+		transfer(2, 1, alice, alice, 3),
+		transfer(2, 1, alice, alice, 3), // transfer to self is returned by both queries
+		nft,
+		removed,
+	}
+	var values []int64
+	for _, tr := range parseTokenTransfers(logs) {
+		values = append(values, tr.Value.Int64())
+	}
+	if fmt.Sprint(values) != "[2 3 1]" {
+		t.Errorf("expected values [2 3 1], got %v", values)
+	}
+}
+
+func TestMissingNonces(t *testing.T) {
+	tests := []struct {
+		pending  uint64
+		queued   []uint64
+		expected []uint64
+	}{
+		{5, nil, nil},
+		{5, []uint64{7}, []uint64{5, 6}},
+		{5, []uint64{6, 8, 9}, []uint64{5, 7}},
+		{0, []uint64{1}, []uint64{0}},
+	}
+	for _, test := range tests {
+		missing := missingNonces(test.pending, test.queued)
+		if fmt.Sprint(missing) != fmt.Sprint(test.expected) {
+			t.Errorf("pending %v queued %v: expected %v, got %v", test.pending, test.queued, test.expected, missing)
+		}
+	}
+}
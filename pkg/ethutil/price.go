@@ -0,0 +1,336 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shopspring/decimal"
+)
+
+// PriceOracle returns the price of coin in fiat currency. coin is a CoinGecko coin id (e.g. ethereum, binancecoin),
+// currency is a fiat currency code (e.g. usd, eur), both are case-insensitive.
+type PriceOracle interface {
+	Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error)
+}
+
+// HistoricalPriceOracle returns the price of coin in fiat currency at time t, e.g. the value at transaction time
+// required by tax and accounting.
+type HistoricalPriceOracle interface {
+	PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error)
+}
+
+// CoinGeckoApiUrl is the base url of CoinGecko public api
+const CoinGeckoApiUrl = "https://api.coingecko.com/api/v3"
+
+// CoinGeckoPriceOracle queries price by CoinGecko simple/price api.
+type CoinGeckoPriceOracle struct {
+	BaseUrl string // empty means CoinGeckoApiUrl
+	ApiKey  string // optional, sent as x-cg-demo-api-key header
+}
+
+func (o *CoinGeckoPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	coin, currency = strings.ToLower(coin), strings.ToLower(currency)
+
+	// e.g. {"ethereum":{"usd":1862.57}}
+	var result map[string]map[string]decimal.Decimal
+	if err := o.get(ctx, fmt.Sprintf("/simple/price?ids=%s&vs_currencies=%s", url.QueryEscape(coin), url.QueryEscape(currency)), &result); err != nil {
+		return decimal.Zero, err
+	}
+	price, ok := result[coin][currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price of %v in %v is not found in coingecko", coin, currency)
+	}
+	return price, nil
+}
+
+// PriceAt returns the daily price (at 00:00 UTC of the day of t) by CoinGecko coins/{id}/history api.
+func (o *CoinGeckoPriceOracle) PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error) {
+	coin, currency = strings.ToLower(coin), strings.ToLower(currency)
+
+	// e.g. {"id":"ethereum","market_data":{"current_price":{"usd":1862.57,...},...},...}
+	var result struct {
+		MarketData struct {
+			CurrentPrice map[string]decimal.Decimal `json:"current_price"`
+		} `json:"market_data"`
+	}
+	date := t.UTC().Format("02-01-2006")
+	if err := o.get(ctx, fmt.Sprintf("/coins/%s/history?date=%s&localization=false", url.PathEscape(coin), date), &result); err != nil {
+		return decimal.Zero, err
+	}
+	price, ok := result.MarketData.CurrentPrice[currency]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price of %v in %v at %v is not found in coingecko", coin, currency, date)
+	}
+	return price, nil
+}
+
+// get requests CoinGecko api path and decodes json response into v.
+func (o *CoinGeckoPriceOracle) get(ctx context.Context, path string, v any) error {
+	baseUrl := o.BaseUrl
+	if baseUrl == "" {
+		baseUrl = CoinGeckoApiUrl
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if o.ApiKey != "" {
+		req.Header.Set("x-cg-demo-api-key", o.ApiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("coingecko returns %v: %s", resp.Status, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("parse coingecko response fail: %w", err)
+	}
+	return nil
+}
+
+// DefiLlamaCoinsApiUrl is the base url of DefiLlama coins api
+const DefiLlamaCoinsApiUrl = "https://coins.llama.fi"
+
+// DefiLlamaPriceOracle queries price by DefiLlama coins api, coins are identified by CoinGecko coin id. Only usd is
+// supported as currency.
+type DefiLlamaPriceOracle struct {
+	BaseUrl string // empty means DefiLlamaCoinsApiUrl
+}
+
+func (o *DefiLlamaPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	return o.price(ctx, "/prices/current/", coin, currency)
+}
+
+// PriceAt returns the price closest to t.
+func (o *DefiLlamaPriceOracle) PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error) {
+	return o.price(ctx, fmt.Sprintf("/prices/historical/%d/", t.Unix()), coin, currency)
+}
+
+func (o *DefiLlamaPriceOracle) price(ctx context.Context, path string, coin string, currency string) (decimal.Decimal, error) {
+	if strings.ToLower(currency) != "usd" {
+		return decimal.Zero, fmt.Errorf("defillama only supports price in usd, not in %v", currency)
+	}
+	baseUrl := o.BaseUrl
+	if baseUrl == "" {
+		baseUrl = DefiLlamaCoinsApiUrl
+	}
+	key := "coingecko:" + strings.ToLower(coin)
+	body, err := httpGet(ctx, baseUrl+path+url.PathEscape(key))
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	// e.g. {"coins":{"coingecko:ethereum":{"price":1862.57,"symbol":"ETH","timestamp":1682899200,"confidence":0.99}}}
+	var result struct {
+		Coins map[string]struct {
+			Price decimal.Decimal `json:"price"`
+		} `json:"coins"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return decimal.Zero, fmt.Errorf("parse defillama response fail: %w", err)
+	}
+	entry, ok := result.Coins[key]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price of %v is not found in defillama", coin)
+	}
+	return entry.Price, nil
+}
+
+// ChainlinkFeedsMainnet are the Chainlink price feeds on Ethereum mainnet, keyed by "coin/currency".
+var ChainlinkFeedsMainnet = map[string]common.Address{
+	"ethereum/usd":    common.HexToAddress("0x5f4eC3Df9cbd43714FE2740f5E3616155c5b8419"),
+	"binancecoin/usd": common.HexToAddress("0x14e613AC84a31f709eadbdF89C6CC390fDc9540A"),
+	"eur/usd":         common.HexToAddress("0xb49f677943BC038e9857d61E7d053CaA2C1734C1"),
+	"gbp/usd":         common.HexToAddress("0x5c0Ab2d9b5a7ed9f470386e82BB36A3613cDd4b5"),
+	"jpy/usd":         common.HexToAddress("0xBcE206caE7f0ec07b545EddE332A47C2F75bbeb3"),
+}
+
+// ChainlinkPriceOracle reads price from Chainlink aggregators. If there is no "coin/currency" feed, price is derived
+// from "coin/usd" and "currency/usd" feeds.
+type ChainlinkPriceOracle struct {
+	Client *ethclient.Client
+	Feeds  map[string]common.Address // nil means ChainlinkFeedsMainnet
+}
+
+func (o *ChainlinkPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	coin, currency = strings.ToLower(coin), strings.ToLower(currency)
+	if feed, ok := o.feed(coin, currency); ok {
+		return chainlinkLatestAnswer(ctx, o.Client, feed)
+	}
+
+	coinFeed, ok1 := o.feed(coin, "usd")
+	currencyFeed, ok2 := o.feed(currency, "usd")
+	if !ok1 || !ok2 {
+		return decimal.Zero, fmt.Errorf("chainlink feed of %v/%v is not found", coin, currency)
+	}
+	coinPrice, err := chainlinkLatestAnswer(ctx, o.Client, coinFeed)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	currencyPrice, err := chainlinkLatestAnswer(ctx, o.Client, currencyFeed)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if currencyPrice.IsZero() {
+		return decimal.Zero, fmt.Errorf("chainlink price of %v/usd is zero", currency)
+	}
+	return coinPrice.DivRound(currencyPrice, 18), nil
+}
+
+func (o *ChainlinkPriceOracle) feed(base, quote string) (common.Address, bool) {
+	feeds := o.Feeds
+	if feeds == nil {
+		feeds = ChainlinkFeedsMainnet
+	}
+	feed, ok := feeds[base+"/"+quote]
+	return feed, ok
+}
+
+// chainlinkLatestAnswer returns the latest answer of Chainlink aggregator, scaled by its decimals.
+func chainlinkLatestAnswer(ctx context.Context, client *ethclient.Client, feed common.Address) (decimal.Decimal, error) {
+	var values []any
+	for _, funcDefinition := range []string{
+		"function decimals() returns (uint8)",
+		"function latestRoundData() returns (uint80, int256, uint256, uint256, uint80)",
+	} {
+		data, err := BuildTxInputData(funcDefinition, nil)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		output, err := Call(ctx, client, feed, data, nil)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("call %v of chainlink feed %v fail: %w", ExtractFuncName(funcDefinition), feed.Hex(), err)
+		}
+		returnArgs, err := BuildReturnArgs(funcDefinition)
+		if err != nil {
+			return decimal.Zero, err
+		}
+		unpacked, err := returnArgs.Unpack(output)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("unpack return data of %v fail: %w", ExtractFuncName(funcDefinition), err)
+		}
+		values = append(values, unpacked...)
+	}
+
+	decimals := values[0].(uint8)
+	answer := values[2].(*big.Int) // values[1] is roundId
+	return decimal.NewFromBigInt(answer, -int32(decimals)), nil
+}
+
+// priceCacheEntry is an entry of price cache file
+type priceCacheEntry struct {
+	Price     decimal.Decimal `json:"price"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// CachedPriceOracle caches prices of Oracle in memory and in CacheFile (if not empty), so repeated invocations
+// within TTL don't hit the rate limits of price source.
+type CachedPriceOracle struct {
+	Oracle    PriceOracle
+	CacheFile string
+	TTL       time.Duration
+
+	mu    sync.Mutex
+	cache map[string]priceCacheEntry // "coin/currency" -> entry
+}
+
+func (o *CachedPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := strings.ToLower(coin + "/" + currency)
+	if o.cache == nil {
+		o.cache = o.load()
+	}
+	if entry, ok := o.cache[key]; ok && time.Since(entry.UpdatedAt) < o.TTL {
+		return entry.Price, nil
+	}
+
+	price, err := o.Oracle.Price(ctx, coin, currency)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	o.cache[key] = priceCacheEntry{Price: price, UpdatedAt: time.Now()}
+	o.save() // cache is best effort, error is ignored
+	return price, nil
+}
+
+// PriceAt returns the historical price of Oracle, which must implement HistoricalPriceOracle. t is truncated to hour,
+// historical prices never expire in cache.
+func (o *CachedPriceOracle) PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error) {
+	historical, ok := o.Oracle.(HistoricalPriceOracle)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("price source does not support historical price")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	t = t.Truncate(time.Hour)
+	key := strings.ToLower(coin+"/"+currency) + fmt.Sprintf("@%d", t.Unix())
+	if o.cache == nil {
+		o.cache = o.load()
+	}
+	if entry, ok := o.cache[key]; ok {
+		return entry.Price, nil
+	}
+
+	price, err := historical.PriceAt(ctx, coin, currency, t)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	o.cache[key] = priceCacheEntry{Price: price, UpdatedAt: time.Now()}
+	o.save()
+	return price, nil
+}
+
+// load reads cache file, an empty cache is returned if cache file does not exist or is broken.
+func (o *CachedPriceOracle) load() map[string]priceCacheEntry {
+	var cache = make(map[string]priceCacheEntry)
+	if o.CacheFile == "" {
+		return cache
+	}
+	content, err := os.ReadFile(o.CacheFile)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return make(map[string]priceCacheEntry)
+	}
+	return cache
+}
+
+func (o *CachedPriceOracle) save() {
+	if o.CacheFile == "" {
+		return
+	}
+	content, err := json.MarshalIndent(o.cache, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(o.CacheFile), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(o.CacheFile, content, 0600)
+}
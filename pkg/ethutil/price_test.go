@@ -0,0 +1,83 @@
+package ethutil
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type countingPriceOracle struct {
+	calls int
+}
+
+func (o *countingPriceOracle) Price(ctx context.Context, coin string, currency string) (decimal.Decimal, error) {
+	o.calls++
+	return decimal.NewFromInt(int64(o.calls)), nil
+}
+
+func (o *countingPriceOracle) PriceAt(ctx context.Context, coin string, currency string, t time.Time) (decimal.Decimal, error) {
+	o.calls++
+	return decimal.NewFromInt(t.Unix()), nil
+}
+
+func TestCachedPriceOracle(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "price_cache.json")
+	tests := []struct {
+		ttl       time.Duration
+		coin      string
+		wantPrice int64
+		wantCalls int
+	}{
+		{ttl: time.Hour, coin: "ethereum", wantPrice: 1, wantCalls: 1},
+		{ttl: time.Hour, coin: "ETHEREUM", wantPrice: 1, wantCalls: 0}, // cached in file
+		{ttl: time.Hour, coin: "binancecoin", wantPrice: 1, wantCalls: 1},
+		{ttl: 0, coin: "ethereum", wantPrice: 1, wantCalls: 1}, // expired
+	}
+
+	for i, tt := range tests {
+		source := &countingPriceOracle{}
+		oracle := &CachedPriceOracle{Oracle: source, CacheFile: cacheFile, TTL: tt.ttl}
+		price, err := oracle.Price(context.Background(), tt.coin, "usd")
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if !price.Equal(decimal.NewFromInt(tt.wantPrice)) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.wantPrice, price)
+		}
+		if source.calls != tt.wantCalls {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.wantCalls, source.calls)
+		}
+	}
+}
+
+func TestCachedPriceOracleHistorical(t *testing.T) {
+	cacheFile := filepath.Join(t.TempDir(), "price_cache.json")
+	at := time.Unix(1682899200, 0) // 2023-05-01 00:00:00 UTC
+	tests := []struct {
+		t         time.Time
+		wantPrice int64
+		wantCalls int
+	}{
+		{t: at.Add(10 * time.Minute), wantPrice: at.Unix(), wantCalls: 1},
+		{t: at.Add(50 * time.Minute), wantPrice: at.Unix(), wantCalls: 0}, // same hour
+		{t: at.Add(time.Hour), wantPrice: at.Add(time.Hour).Unix(), wantCalls: 1},
+	}
+
+	for i, tt := range tests {
+		source := &countingPriceOracle{}
+		oracle := &CachedPriceOracle{Oracle: source, CacheFile: cacheFile, TTL: 0} // historical price never expires
+		price, err := oracle.PriceAt(context.Background(), "ethereum", "usd", tt.t)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if !price.Equal(decimal.NewFromInt(tt.wantPrice)) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.wantPrice, price)
+		}
+		if source.calls != tt.wantCalls {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.wantCalls, source.calls)
+		}
+	}
+}
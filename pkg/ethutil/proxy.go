@@ -0,0 +1,114 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Proxy patterns
+const (
+	ProxyKindNone         = "none"
+	ProxyKindEip1967      = "eip1967"
+	ProxyKindEip1822      = "eip1822"
+	ProxyKindBeacon       = "eip1967-beacon"
+	ProxyKindZeppelinOS   = "zeppelinos"
+	ProxyKindMinimalProxy = "eip1167"
+)
+
+var (
+	// Eip1822ProxiableSlot is keccak256("PROXIABLE"), the implementation slot of EIP-1822 (UUPS)
+	Eip1822ProxiableSlot = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+	// ZeppelinOSImplementationSlot is keccak256("org.zeppelinos.proxy.implementation"), used by legacy OpenZeppelin proxies (e.g. USDC)
+	ZeppelinOSImplementationSlot = common.HexToHash("0x7050c9e0f4ca769c69bd3a8ef740bc37934f8e2c036e5a723fd8ee048ed3f8c3")
+	// ZeppelinOSAdminSlot is keccak256("org.zeppelinos.proxy.admin")
+	ZeppelinOSAdminSlot = common.HexToHash("0x10d6a54a4754c8869d6886b5f5d7fbfa5b4522237ea5c60d11bc4e7a1ff9390b")
+)
+
+// minimal proxy (EIP-1167) runtime bytecode is minimalProxyPrefix + implementation address + minimalProxySuffix
+var minimalProxyPrefix = hexutil.MustDecode("0x363d3d373d3d3d363d73")
+var minimalProxySuffix = hexutil.MustDecode("0x5af43d82803e903d91602b57fd5bf3")
+
+// ProxyInfo is the detected proxy pattern of a contract.
+type ProxyInfo struct {
+	Kind           string
+	Implementation common.Address
+	Admin          common.Address // zero if the pattern has no admin slot or it's not set
+	Beacon         common.Address // only set for ProxyKindBeacon
+}
+
+// ParseMinimalProxy returns the implementation address if code is an EIP-1167 minimal proxy.
+func ParseMinimalProxy(code []byte) (common.Address, bool) {
+	if len(code) != len(minimalProxyPrefix)+common.AddressLength+len(minimalProxySuffix) ||
+		!bytes.HasPrefix(code, minimalProxyPrefix) || !bytes.HasSuffix(code, minimalProxySuffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(minimalProxyPrefix) : len(minimalProxyPrefix)+common.AddressLength]), true
+}
+
+// DetectProxy detects the proxy pattern of address at the given block, nil blockNumber means latest block.
+// Kind is ProxyKindNone if no known pattern is detected.
+func DetectProxy(ctx context.Context, client *ethclient.Client, address common.Address, blockNumber *big.Int) (*ProxyInfo, error) {
+	code, err := client.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("CodeAt fail: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%v is not a contract", address.Hex())
+	}
+	if impl, ok := ParseMinimalProxy(code); ok {
+		return &ProxyInfo{Kind: ProxyKindMinimalProxy, Implementation: impl}, nil
+	}
+
+	var readSlot = func(slot common.Hash) (common.Address, error) {
+		return GetAddressInSlot(ctx, client, address, slot, blockNumber)
+	}
+
+	for _, pattern := range []struct {
+		kind      string
+		implSlot  common.Hash
+		adminSlot common.Hash
+	}{
+		{ProxyKindEip1967, Eip1967ImplementationSlot, Eip1967AdminSlot},
+		{ProxyKindEip1822, Eip1822ProxiableSlot, common.Hash{}},
+		{ProxyKindZeppelinOS, ZeppelinOSImplementationSlot, ZeppelinOSAdminSlot},
+	} {
+		impl, err := readSlot(pattern.implSlot)
+		if err != nil {
+			return nil, err
+		}
+		if impl == (common.Address{}) {
+			continue
+		}
+		info := &ProxyInfo{Kind: pattern.kind, Implementation: impl}
+		if pattern.adminSlot != (common.Hash{}) {
+			if info.Admin, err = readSlot(pattern.adminSlot); err != nil {
+				return nil, err
+			}
+		}
+		return info, nil
+	}
+
+	beacon, err := readSlot(Eip1967BeaconSlot)
+	if err != nil {
+		return nil, err
+	}
+	if beacon != (common.Address{}) {
+		data, err := BuildTxInputData("implementation()", nil)
+		if err != nil {
+			return nil, err
+		}
+		output, err := Call(ctx, client, beacon, data, blockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("call implementation() of beacon %v fail: %w", beacon.Hex(), err)
+		}
+		return &ProxyInfo{Kind: ProxyKindBeacon, Implementation: common.BytesToAddress(output), Beacon: beacon}, nil
+	}
+
+	return &ProxyInfo{Kind: ProxyKindNone}, nil
+}
@@ -0,0 +1,40 @@
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestParseMinimalProxy(t *testing.T) {
+	tests := []struct {
+		code     string
+		wantImpl string
+		wantOk   bool
+	}{
+		{
+			code:     "0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5bf3",
+			wantImpl: "0xbEbeBeBEbeBebeBeBEBEbebEBeBeBebeBeBebebe",
+			wantOk:   true,
+		},
+		{
+			// truncated
+			code:     "0x363d3d373d3d3d363d73bebebebebebebebebebebebebebebebebebebebe5af43d82803e903d91602b57fd5b",
+			wantImpl: "0x0000000000000000000000000000000000000000",
+			wantOk:   false,
+		},
+		{
+			code:     "0x6080604052",
+			wantImpl: "0x0000000000000000000000000000000000000000",
+			wantOk:   false,
+		},
+	}
+
+	for i, tt := range tests {
+		impl, ok := ParseMinimalProxy(hexutil.MustDecode(tt.code))
+		if impl != common.HexToAddress(tt.wantImpl) || ok != tt.wantOk {
+			t.Fatalf("test %d: expected: %v %v, got: %v %v", i, tt.wantImpl, tt.wantOk, impl.Hex(), ok)
+		}
+	}
+}
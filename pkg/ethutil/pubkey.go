@@ -0,0 +1,85 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidChecksum is returned by ParseChecksumAddress if mixed case address does not match its EIP-55 checksum.
+var ErrInvalidChecksum = errors.New("invalid EIP-55 checksum")
+
+// ERC1191ChainIds are the chains known to use ERC-1191 checksum, i.e. RSK mainnet and testnet.
+var ERC1191ChainIds = []uint64{30, 31}
+
+// ParsePublicKey parses secp256k1 public key in compressed (33 bytes), uncompressed (65 bytes, starts with 0x04) or
+// raw (64 bytes, uncompressed without 0x04) form.
+func ParsePublicKey(pubkey []byte) (*ecdsa.PublicKey, error) {
+	switch len(pubkey) {
+	case 33:
+		return crypto.DecompressPubkey(pubkey)
+	case 64:
+		return crypto.UnmarshalPubkey(append([]byte{0x04}, pubkey...))
+	case 65:
+		return crypto.UnmarshalPubkey(pubkey)
+	default:
+		return nil, fmt.Errorf("invalid public key length %v, expected 33, 64 or 65 bytes", len(pubkey))
+	}
+}
+
+// ParseChecksumAddress parses hex address, the EIP-55 checksum is verified if it's in mixed case. checksummed
+// reports whether the address is in mixed case, all lowercase or all uppercase address has no checksum.
+// See: https://eips.ethereum.org/EIPS/eip-55
+func ParseChecksumAddress(s string) (address common.Address, checksummed bool, err error) {
+	address, checksummed, _, err = DetectChecksum(s, nil)
+	return
+}
+
+// DetectChecksum is same as ParseChecksumAddress, but mixed case address is also accepted if it matches ERC-1191
+// checksum of any chain in chainIds, chainId reports the chain of ERC-1191 checksum (0 for EIP-55 checksum).
+// See: https://eips.ethereum.org/EIPS/eip-1191
+func DetectChecksum(s string, chainIds []uint64) (address common.Address, checksummed bool, chainId uint64, err error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, false, 0, fmt.Errorf("%v is not a valid address", s)
+	}
+	address = common.HexToAddress(s)
+	hex := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return address, false, 0, nil
+	}
+	if address.Hex()[2:] == hex {
+		return address, true, 0, nil
+	}
+	for _, chainId := range chainIds {
+		if ChecksumAddressERC1191(address, chainId)[2:] == hex {
+			return address, true, chainId, nil
+		}
+	}
+	return address, true, 0, ErrInvalidChecksum
+}
+
+// ChecksumAddressERC1191 returns address in ERC-1191 checksum of chainId, which is EIP-55 checksum with chain id
+// included in hashed string, chainId 0 means EIP-55 checksum.
+func ChecksumAddressERC1191(address common.Address, chainId uint64) string {
+	if chainId == 0 {
+		return address.Hex()
+	}
+	lower := strings.ToLower(address.Hex()[2:])
+	hash := crypto.Keccak256([]byte(fmt.Sprintf("%d0x%s", chainId, lower)))
+	var result = []byte(lower)
+	for i, c := range result {
+		// uppercase the letter if the corresponding nibble of hash >= 8
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c > '9' && nibble&0xf >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
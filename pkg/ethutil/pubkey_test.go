@@ -0,0 +1,99 @@
+package ethutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParsePublicKey(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	uncompressed := crypto.FromECDSAPub(&privateKey.PublicKey)
+	tests := []struct {
+		pubkey []byte
+		valid  bool
+	}{
+		{crypto.CompressPubkey(&privateKey.PublicKey), true},
+		{uncompressed, true},
+		{uncompressed[1:], true},
+		{uncompressed[:64], false},
+		{hexutil.MustDecode("0x0200"), false},
+	}
+
+	for i, test := range tests {
+		pubkey, err := ParsePublicKey(test.pubkey)
+		if valid := err == nil; valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.valid, valid, err)
+		}
+		if err == nil && crypto.PubkeyToAddress(*pubkey) != AddressFromPrivateKey(privateKey) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, AddressFromPrivateKey(privateKey), crypto.PubkeyToAddress(*pubkey))
+		}
+	}
+}
+
+func TestParseChecksumAddress(t *testing.T) {
+	tests := []struct {
+		address     string
+		checksummed bool
+		err         error
+	}{
+		// test vectors in https://eips.ethereum.org/EIPS/eip-55
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true, nil},
+		{"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", true, nil},
+		{"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", true, nil},
+		{"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", true, nil},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", false, nil},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", false, nil},
+		{"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true, ErrInvalidChecksum},
+	}
+
+	for i, test := range tests {
+		_, checksummed, err := ParseChecksumAddress(test.address)
+		if !errors.Is(err, test.err) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.err, err)
+		}
+		if checksummed != test.checksummed {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.checksummed, checksummed)
+		}
+	}
+
+	if _, _, err := ParseChecksumAddress("0x1234"); err == nil {
+		t.Fatalf("expected: error, got: nil")
+	}
+}
+
+func TestChecksumAddressERC1191(t *testing.T) {
+	// test vectors in https://eips.ethereum.org/EIPS/eip-1191
+	tests := []struct {
+		chainId  uint64
+		expected string
+	}{
+		{30, "0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD"},
+		{30, "0xFb6916095cA1Df60bb79ce92cE3EA74c37c5d359"},
+		{30, "0xDBF03B407c01E7CD3cBea99509D93F8Dddc8C6FB"},
+		{30, "0xD1220A0Cf47c7B9BE7a2e6ba89F429762E7B9adB"},
+		{31, "0x5aAeb6053F3e94c9b9A09F33669435E7EF1BEaEd"},
+		{31, "0xFb6916095CA1dF60bb79CE92ce3Ea74C37c5D359"},
+		{31, "0xdbF03B407C01E7cd3cbEa99509D93f8dDDc8C6fB"},
+		{31, "0xd1220a0CF47c7B9Be7A2E6Ba89f429762E7b9adB"},
+		{0, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+	}
+
+	for i, test := range tests {
+		address := common.HexToAddress(test.expected)
+		if got := ChecksumAddressERC1191(address, test.chainId); got != test.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+		_, checksummed, chainId, err := DetectChecksum(test.expected, ERC1191ChainIds)
+		if err != nil || !checksummed || chainId != test.chainId {
+			t.Fatalf("test %d: expected: %v, got: %v (%v)", i, test.chainId, chainId, err)
+		}
+	}
+
+	if _, _, _, err := DetectChecksum("0x5aaEB6053f3e94c9b9a09f33669435E7ef1bEAeD", nil); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("expected: %v, got: %v", ErrInvalidChecksum, err)
+	}
+}
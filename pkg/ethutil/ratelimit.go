@@ -0,0 +1,101 @@
+package ethutil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit limits http(s) rpc requests sent by a client, the limit is shared by all goroutines using the client and
+// applies to each attempt of retried requests. A batch request is counted as one request.
+type RateLimit struct {
+	RPS         float64 // max requests per second, 0 means no limit
+	Concurrency int     // max in-flight requests, 0 means no limit
+}
+
+// rateLimitTransport spaces out requests by 1/RPS and bounds the number of in-flight requests, a request is in-flight
+// until its response body is closed.
+type rateLimitTransport struct {
+	interval time.Duration
+	sem      chan struct{}
+	base     http.RoundTripper
+
+	mu   sync.Mutex
+	next time.Time // earliest time the next request can be sent
+}
+
+func newRateLimitTransport(limit RateLimit, base http.RoundTripper) *rateLimitTransport {
+	t := &rateLimitTransport{base: base}
+	if limit.RPS > 0 {
+		t.interval = time.Duration(float64(time.Second) / limit.RPS)
+	}
+	if limit.Concurrency > 0 {
+		t.sem = make(chan struct{}, limit.Concurrency)
+	}
+	return t
+}
+
+// releaseOnClose releases the concurrency slot of request after its response body is read.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	defer r.once.Do(r.release)
+	return r.ReadCloser.Close()
+}
+
+// wait blocks until the next request is allowed by RPS.
+func (t *rateLimitTransport) wait(ctx context.Context) error {
+	if t.interval <= 0 {
+		return nil
+	}
+	t.mu.Lock()
+	at := t.next
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	t.next = at.Add(t.interval)
+	t.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var release = func() {}
+	if t.sem != nil {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case t.sem <- struct{}{}:
+		}
+		release = func() { <-t.sem }
+	}
+	if err := t.wait(req.Context()); err != nil {
+		release()
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
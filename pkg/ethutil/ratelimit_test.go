@@ -0,0 +1,71 @@
+package ethutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitTransport(t *testing.T) {
+	tests := []struct {
+		limit          RateLimit
+		requests       int
+		minElapsed     time.Duration
+		maxConcurrency int32 // 0 means not checked
+	}{
+		{RateLimit{RPS: 50}, 6, 100 * time.Millisecond, 0},
+		{RateLimit{Concurrency: 2}, 6, 0, 2},
+		{RateLimit{RPS: 100, Concurrency: 1}, 4, 30 * time.Millisecond, 1},
+	}
+
+	for i, test := range tests {
+		var inFlight, maxInFlight int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`))
+		}))
+
+		client, err := DialEndpointsWithLimit(context.Background(), Endpoints{Default: server.URL}, RetryOptions{}, test.limit)
+		if err != nil {
+			t.Fatalf("test %d: dial fail: %v", i, err)
+		}
+		start := time.Now()
+		var wg sync.WaitGroup
+		var failures int32
+		for j := 0; j < test.requests; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.EthClient.BlockNumber(context.Background()); err != nil {
+					atomic.AddInt32(&failures, 1)
+				}
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(start)
+		server.Close()
+
+		if failures > 0 {
+			t.Fatalf("test %d: expected: no failure, got: %v failures", i, failures)
+		}
+		if elapsed < test.minElapsed {
+			t.Fatalf("test %d: expected: elapsed >= %v, got: %v", i, test.minElapsed, elapsed)
+		}
+		if test.maxConcurrency > 0 && maxInFlight > test.maxConcurrency {
+			t.Fatalf("test %d: expected: concurrency <= %v, got: %v", i, test.maxConcurrency, maxInFlight)
+		}
+	}
+}
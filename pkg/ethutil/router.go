@@ -0,0 +1,208 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Endpoints are the node urls used for different kinds of rpc methods, empty url falls back to Default.
+type Endpoints struct {
+	Default   string   `json:"node_url"`      // latest-state reads and all other methods
+	Archive   string   `json:"archive_url"`   // reads of historical state
+	Trace     string   `json:"trace_url"`     // trace_* and debug_* methods
+	Broadcast string   `json:"broadcast_url"` // methods which broadcast txs
+	Fallbacks []string `json:"fallback_urls"` // used in turn if Default fails, see RetryOptions
+}
+
+// endpoint kinds
+const (
+	endpointDefault = iota
+	endpointArchive
+	endpointTrace
+	endpointBroadcast
+)
+
+// stateMethodBlockParamIndex maps methods reading state to the position of their block param.
+var stateMethodBlockParamIndex = map[string]int{
+	"eth_getBalance":          1,
+	"eth_getCode":             1,
+	"eth_getTransactionCount": 1,
+	"eth_getStorageAt":        2,
+	"eth_call":                1,
+	"eth_estimateGas":         1,
+	"eth_createAccessList":    1,
+	"eth_getProof":            2,
+}
+
+// broadcastMethods are the methods which broadcast txs, they are never retried as the tx may have been accepted.
+var broadcastMethods = map[string]bool{
+	"eth_sendRawTransaction":     true,
+	"eth_sendTransaction":        true,
+	"eth_sendBundle":             true,
+	"eth_sendPrivateTransaction": true,
+}
+
+// jsonrpcMessage is the method and params of a json-rpc request.
+type jsonrpcMessage struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// parseJsonrpcRequest returns messages of request body, which is a single request or a batch.
+func parseJsonrpcRequest(body []byte) []jsonrpcMessage {
+	var msgs []jsonrpcMessage
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' { // batch
+		_ = json.Unmarshal(body, &msgs)
+	} else {
+		var msg jsonrpcMessage
+		_ = json.Unmarshal(body, &msg)
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// routeMethod returns the endpoint kind of rpc method with params.
+func routeMethod(method string, params []json.RawMessage) int {
+	if broadcastMethods[method] {
+		return endpointBroadcast
+	}
+	if strings.HasPrefix(method, "trace_") || strings.HasPrefix(method, "debug_") {
+		return endpointTrace
+	}
+	if index, ok := stateMethodBlockParamIndex[method]; ok && index < len(params) {
+		var tag string
+		if err := json.Unmarshal(params[index], &tag); err != nil {
+			// block hash object, e.g. {"blockHash": "0x..."}
+			return endpointArchive
+		}
+		if tag != "latest" && tag != "pending" && tag != "safe" && tag != "finalized" {
+			return endpointArchive
+		}
+	}
+	return endpointDefault
+}
+
+// routingTransport dispatches each json-rpc request to the endpoint of its method.
+type routingTransport struct {
+	urls map[int]*url.URL
+	base http.RoundTripper
+}
+
+func (t *routingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+
+	msgs := parseJsonrpcRequest(body)
+
+	// a batch is routed to a non-default endpoint only if all methods in it are routed to the same endpoint
+	kind := endpointDefault
+	for i, msg := range msgs {
+		k := routeMethod(msg.Method, msg.Params)
+		if i == 0 {
+			kind = k
+		} else if k != kind {
+			kind = endpointDefault
+			break
+		}
+	}
+
+	target, ok := t.urls[kind]
+	if !ok {
+		target = t.urls[endpointDefault]
+	}
+
+	req = req.Clone(req.Context())
+	req.URL = target
+	req.Host = target.Host
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	return t.base.RoundTrip(req)
+}
+
+// DialEndpoints connects a client which dispatches each rpc method to the appropriate endpoint.
+// If only Default is set, it's same as Dial. Otherwise, all endpoints must be http(s) urls.
+func DialEndpoints(ctx context.Context, endpoints Endpoints) (*Client, error) {
+	return DialEndpointsWithRetry(ctx, endpoints, RetryOptions{})
+}
+
+// DialEndpointsWithRetry is same as DialEndpoints, but failed requests are retried by retry options, and fail over
+// to Fallbacks of endpoints.
+func DialEndpointsWithRetry(ctx context.Context, endpoints Endpoints, retry RetryOptions) (*Client, error) {
+	return DialEndpointsWithLimit(ctx, endpoints, retry, RateLimit{})
+}
+
+// DialEndpointsWithLimit is same as DialEndpointsWithRetry, and requests to all endpoints are limited by limit.
+func DialEndpointsWithLimit(ctx context.Context, endpoints Endpoints, retry RetryOptions, limit RateLimit) (*Client, error) {
+	if endpoints.Archive == "" && endpoints.Trace == "" && endpoints.Broadcast == "" && len(endpoints.Fallbacks) == 0 &&
+		retry == (RetryOptions{}) && limit == (RateLimit{}) {
+		return Dial(ctx, endpoints.Default)
+	}
+
+	var urls = make(map[int]*url.URL)
+	for kind, rawUrl := range map[int]string{
+		endpointDefault:   endpoints.Default,
+		endpointArchive:   endpoints.Archive,
+		endpointTrace:     endpoints.Trace,
+		endpointBroadcast: endpoints.Broadcast,
+	} {
+		if rawUrl == "" {
+			continue
+		}
+		u, err := parseHttpEndpoint(rawUrl)
+		if err != nil {
+			return nil, err
+		}
+		urls[kind] = u
+	}
+	if _, ok := urls[endpointDefault]; !ok {
+		return nil, fmt.Errorf("default node url is required")
+	}
+	var failoverUrls = []*url.URL{urls[endpointDefault]}
+	for _, rawUrl := range endpoints.Fallbacks {
+		u, err := parseHttpEndpoint(rawUrl)
+		if err != nil {
+			return nil, err
+		}
+		failoverUrls = append(failoverUrls, u)
+	}
+	if retry.Retries == 0 && len(failoverUrls) > 1 {
+		retry.Retries = len(failoverUrls) - 1 // try each url once
+	}
+
+	var transport http.RoundTripper = NewNormalizeTransport(http.DefaultTransport)
+	if limit != (RateLimit{}) {
+		transport = newRateLimitTransport(limit, transport)
+	}
+	transport = &failoverTransport{urls: failoverUrls, options: retry, base: transport}
+	if len(urls) > 1 {
+		transport = &routingTransport{urls: urls, base: transport}
+	}
+	rpcClient, err := rpc.DialHTTPWithClient(endpoints.Default, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(rpcClient), nil
+}
+
+// parseHttpEndpoint parses rawUrl, which must be a http(s) url.
+func parseHttpEndpoint(rawUrl string) (*url.URL, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint %v is not a http(s) url, only http(s) endpoints can be split or fail over", rawUrl)
+	}
+	return u, nil
+}
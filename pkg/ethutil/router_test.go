@@ -0,0 +1,61 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRouteMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		params string
+		want   int
+	}{
+		{
+			method: "eth_sendRawTransaction",
+			params: `["0x02f8"]`,
+			want:   endpointBroadcast,
+		},
+		{
+			method: "debug_traceTransaction",
+			params: `["0x8f2b5a0b8b1c9c6c6e1b0e2b2c3b5a8f9e0d1c2b3a4f5e6d7c8b9a0f1e2d3c4b"]`,
+			want:   endpointTrace,
+		},
+		{
+			method: "eth_getBalance",
+			params: `["0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", "latest"]`,
+			want:   endpointDefault,
+		},
+		{
+			method: "eth_getBalance",
+			params: `["0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", "0x10"]`,
+			want:   endpointArchive,
+		},
+		{
+			method: "eth_call",
+			params: `[{"to": "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"}, {"blockHash": "0x8f2b5a0b8b1c9c6c6e1b0e2b2c3b5a8f9e0d1c2b3a4f5e6d7c8b9a0f1e2d3c4b"}]`,
+			want:   endpointArchive,
+		},
+		{
+			method: "eth_getStorageAt",
+			params: `["0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb", "0x0", "pending"]`,
+			want:   endpointDefault,
+		},
+		{
+			method: "eth_blockNumber",
+			params: `[]`,
+			want:   endpointDefault,
+		},
+	}
+
+	for i, tt := range tests {
+		var params []json.RawMessage
+		if err := json.Unmarshal([]byte(tt.params), &params); err != nil {
+			t.Fatalf("test %d: invalid params: %v", i, err)
+		}
+		output := routeMethod(tt.method, params)
+		if output != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SafeSentinelOwners is the head of the owners linked list in Gnosis Safe contract.
+var SafeSentinelOwners = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+// safeCall calls constant method funcDefinition of safe, and unpacks the first return value.
+func safeCall(ctx context.Context, client *ethclient.Client, safe common.Address, funcDefinition string) (any, error) {
+	data, err := BuildTxInputData(funcDefinition, nil)
+	if err != nil {
+		return nil, err
+	}
+	output, err := Call(ctx, client, safe, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	returnArgs, err := BuildReturnArgs(funcDefinition)
+	if err != nil {
+		return nil, err
+	}
+	values, err := returnArgs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("unpack return data of %v fail: %w", ExtractFuncName(funcDefinition), err)
+	}
+	return values[0], nil
+}
+
+// SafeGetOwners returns owners of safe, in the order of the owners linked list.
+func SafeGetOwners(ctx context.Context, client *ethclient.Client, safe common.Address) ([]common.Address, error) {
+	value, err := safeCall(ctx, client, safe, "function getOwners() returns (address[])")
+	if err != nil {
+		return nil, err
+	}
+	return value.([]common.Address), nil
+}
+
+// SafeGetThreshold returns the number of required confirmations of safe.
+func SafeGetThreshold(ctx context.Context, client *ethclient.Client, safe common.Address) (uint64, error) {
+	value, err := safeCall(ctx, client, safe, "function getThreshold() returns (uint256)")
+	if err != nil {
+		return 0, err
+	}
+	return value.(*big.Int).Uint64(), nil
+}
+
+// SafeGetNonce returns the nonce of safe, i.e. the nonce of next safe tx.
+func SafeGetNonce(ctx context.Context, client *ethclient.Client, safe common.Address) (*big.Int, error) {
+	value, err := safeCall(ctx, client, safe, "function nonce() returns (uint256)")
+	if err != nil {
+		return nil, err
+	}
+	return value.(*big.Int), nil
+}
+
+// SafePrevOwner returns the owner pointing to owner in the owners linked list, which is required by
+// swapOwner and removeOwner. owners must be in the order returned by getOwners.
+func SafePrevOwner(owners []common.Address, owner common.Address) (common.Address, error) {
+	for i, o := range owners {
+		if o == owner {
+			if i == 0 {
+				return SafeSentinelOwners, nil
+			}
+			return owners[i-1], nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("%v is not an owner", owner.Hex())
+}
+
+// SafeSwapOwnerData builds input data of swapOwner, which replaces oldOwner with newOwner.
+func SafeSwapOwnerData(owners []common.Address, oldOwner, newOwner common.Address) ([]byte, error) {
+	prevOwner, err := SafePrevOwner(owners, oldOwner)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTxInputData("swapOwner(address,address,address)", []string{prevOwner.Hex(), oldOwner.Hex(), newOwner.Hex()})
+}
+
+// SafeAddOwnerData builds input data of addOwnerWithThreshold.
+func SafeAddOwnerData(newOwner common.Address, threshold uint64) ([]byte, error) {
+	return BuildTxInputData("addOwnerWithThreshold(address,uint256)", []string{newOwner.Hex(), fmt.Sprint(threshold)})
+}
+
+// SafeRemoveOwnerData builds input data of removeOwner.
+func SafeRemoveOwnerData(owners []common.Address, owner common.Address, threshold uint64) ([]byte, error) {
+	prevOwner, err := SafePrevOwner(owners, owner)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTxInputData("removeOwner(address,address,uint256)", []string{prevOwner.Hex(), owner.Hex(), fmt.Sprint(threshold)})
+}
+
+// SafeChangeThresholdData builds input data of changeThreshold.
+func SafeChangeThresholdData(threshold uint64) ([]byte, error) {
+	return BuildTxInputData("changeThreshold(uint256)", []string{fmt.Sprint(threshold)})
+}
+
+// SafeGetTransactionHash returns the hash (to be signed by owners) of a safe tx which calls to with data, no refund is used.
+func SafeGetTransactionHash(ctx context.Context, client *ethclient.Client, safe common.Address, to common.Address, value *big.Int, data []byte, nonce *big.Int) (common.Hash, error) {
+	input, err := BuildTxInputData("getTransactionHash(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,uint256)",
+		[]string{to.Hex(), value.String(), hexutil.Encode(data), "0", "0", "0", "0", common.Address{}.Hex(), common.Address{}.Hex(), nonce.String()})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	output, err := Call(ctx, client, safe, input, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(output), nil
+}
+
+// SafePreValidatedSignature returns the signature which is regarded valid by safe if the safe tx is sent by owner itself.
+// See: https://docs.safe.global/advanced/smart-account-signatures#pre-validated-signatures
+func SafePreValidatedSignature(owner common.Address) []byte {
+	var signature = make([]byte, 65)
+	copy(signature[12:32], owner.Bytes()) // r is owner, s is zero
+	signature[64] = 1                     // v is 1
+	return signature
+}
+
+// SafeApprovedHash returns true if owner approved hash on chain by approveHash of safe, then the pre-validated
+// signature of owner is valid for the safe tx, whoever sends it.
+func SafeApprovedHash(ctx context.Context, client *ethclient.Client, safe common.Address, owner common.Address, hash common.Hash) (bool, error) {
+	values, err := CallAndUnpack(ctx, client, safe, "function approvedHashes(address, bytes32) returns (uint256)", []string{owner.Hex(), hash.Hex()})
+	if err != nil {
+		return false, err
+	}
+	return values[0].(*big.Int).Sign() != 0, nil
+}
+
+// SafeOwnerApproval is the approval of an owner for a safe tx hash.
+type SafeOwnerApproval struct {
+	Owner    common.Address
+	OnChain  bool // approved by approveHash
+	OffChain bool // confirmed (signed) in Safe Transaction Service
+}
+
+// SafeApprovalCount returns the number of owners who approved (on chain or off chain), and the number of signatures
+// still required to reach threshold.
+func SafeApprovalCount(approvals []SafeOwnerApproval, threshold uint64) (approved uint64, missing uint64) {
+	for _, approval := range approvals {
+		if approval.OnChain || approval.OffChain {
+			approved++
+		}
+	}
+	if approved < threshold {
+		missing = threshold - approved
+	}
+	return approved, missing
+}
+
+// SafeTxTypeHash is the EIP-712 type hash of SafeTx
+var SafeTxTypeHash = crypto.Keccak256Hash([]byte("SafeTx(address to,uint256 value,bytes data,uint8 operation,uint256 safeTxGas,uint256 baseGas,uint256 gasPrice,address gasToken,address refundReceiver,uint256 nonce)"))
+
+// SafeTx is a safe tx, which is signed by owners and executed by execTransaction of safe.
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8 // 0 is call, 1 is delegatecall
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// NewSafeTx returns a safe tx which calls to with value and data, no refund is used.
+func NewSafeTx(to common.Address, value *big.Int, data []byte, nonce *big.Int) *SafeTx {
+	return &SafeTx{
+		To:        to,
+		Value:     value,
+		Data:      data,
+		SafeTxGas: big.NewInt(0),
+		BaseGas:   big.NewInt(0),
+		GasPrice:  big.NewInt(0),
+		Nonce:     nonce,
+	}
+}
+
+// SafeDomainSeparator computes EIP-712 domain separator of safe (v1.3.0 or later), which is
+// keccak256(abi.encode(keccak256("EIP712Domain(uint256 chainId,address verifyingContract)"), chainId, safe))
+func SafeDomainSeparator(chainID *big.Int, safe common.Address) (common.Hash, error) {
+	data, err := EncodeParameters([]string{"bytes32", "uint256", "address"}, []string{
+		crypto.Keccak256Hash([]byte("EIP712Domain(uint256 chainId,address verifyingContract)")).Hex(),
+		chainID.String(),
+		safe.Hex(),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(data), nil
+}
+
+// Hash returns the safe tx hash signed by owners, i.e. keccak256("\x19\x01" || domainSeparator || hashStruct(safeTx)).
+// It's the same as getTransactionHash of safe, but computed offline.
+func (tx *SafeTx) Hash(domainSeparator common.Hash) (common.Hash, error) {
+	structData, err := EncodeParameters([]string{"bytes32", "address", "uint256", "bytes32", "uint8", "uint256", "uint256", "uint256", "address", "address", "uint256"}, []string{
+		SafeTxTypeHash.Hex(),
+		tx.To.Hex(),
+		tx.Value.String(),
+		crypto.Keccak256Hash(tx.Data).Hex(),
+		fmt.Sprint(tx.Operation),
+		tx.SafeTxGas.String(),
+		tx.BaseGas.String(),
+		tx.GasPrice.String(),
+		tx.GasToken.Hex(),
+		tx.RefundReceiver.Hex(),
+		tx.Nonce.String(),
+	})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator[:], crypto.Keccak256(structData)), nil
+}
+
+// SafeSignTxHash signs safe tx hash by privateKey of owner, returns 65 bytes signature r || s || v, v is 27 or 28.
+func SafeSignTxHash(safeTxHash common.Hash, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	return SignHash(safeTxHash[:], privateKey)
+}
+
+// SafeSignatureOwner returns the owner of signature of safe tx hash. Supported signatures are ECDSA signature (v is
+// 27 or 28), eth_sign signature (v is 31 or 32) and pre-validated signature (v is 1). Contract signature (v is 0) is
+// not supported.
+func SafeSignatureOwner(safeTxHash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes, got %v bytes", len(signature))
+	}
+	v := signature[64]
+	var hash []byte
+	switch v {
+	case 1:
+		return common.BytesToAddress(signature[:32]), nil
+	case 27, 28:
+		hash = safeTxHash[:]
+	case 31, 32:
+		hash = accounts.TextHash(safeTxHash[:])
+		v -= 4
+	default:
+		return common.Address{}, fmt.Errorf("unsupported signature type, v is %v", v)
+	}
+	recoverable := append([]byte{}, signature[:64]...)
+	recoverable = append(recoverable, v-27)
+	pubkey, err := crypto.SigToPub(hash, recoverable)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recover signer fail: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// SafeCombineSignatures concatenates signatures of safe tx hash sorted by owner in ascending order, which is
+// required by execTransaction. Signatures of the same owner are rejected.
+func SafeCombineSignatures(safeTxHash common.Hash, signatures [][]byte) ([]byte, error) {
+	type ownerSignature struct {
+		owner     common.Address
+		signature []byte
+	}
+	var ownerSignatures []ownerSignature
+	seen := make(map[common.Address]bool)
+	for _, signature := range signatures {
+		owner, err := SafeSignatureOwner(safeTxHash, signature)
+		if err != nil {
+			return nil, err
+		}
+		if seen[owner] {
+			return nil, fmt.Errorf("duplicate signature of owner %v", owner.Hex())
+		}
+		seen[owner] = true
+		ownerSignatures = append(ownerSignatures, ownerSignature{owner, signature})
+	}
+	sort.Slice(ownerSignatures, func(i, j int) bool {
+		return bytes.Compare(ownerSignatures[i].owner[:], ownerSignatures[j].owner[:]) < 0
+	})
+
+	var combined []byte
+	for _, s := range ownerSignatures {
+		combined = append(combined, s.signature...)
+	}
+	return combined, nil
+}
+
+// SafeExecTransactionData builds input data of execTransaction of safe tx with combined signatures.
+func SafeExecTransactionData(tx *SafeTx, signatures []byte) ([]byte, error) {
+	return BuildTxInputData("execTransaction(address,uint256,bytes,uint8,uint256,uint256,uint256,address,address,bytes)",
+		[]string{tx.To.Hex(), tx.Value.String(), hexutil.Encode(tx.Data), fmt.Sprint(tx.Operation), tx.SafeTxGas.String(),
+			tx.BaseGas.String(), tx.GasPrice.String(), tx.GasToken.Hex(), tx.RefundReceiver.Hex(), hexutil.Encode(signatures)})
+}
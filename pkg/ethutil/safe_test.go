@@ -0,0 +1,143 @@
+package ethutil
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSafePrevOwner(t *testing.T) {
+	var owner1 = common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	var owner2 = common.HexToAddress("0xB2aC853cF815B47903bc19BF4860540306F4f944")
+	var owner3 = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	var owners = []common.Address{owner1, owner2, owner3}
+
+	tests := []struct {
+		input   common.Address
+		want    common.Address
+		wantErr bool
+	}{
+		{
+			input: owner1,
+			want:  SafeSentinelOwners,
+		},
+		{
+			input: owner2,
+			want:  owner1,
+		},
+		{
+			input: owner3,
+			want:  owner2,
+		},
+		{
+			input:   common.HexToAddress("0x0000000000000000000000000000000000000002"),
+			wantErr: true,
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := SafePrevOwner(owners, tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if output != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want.Hex(), output.Hex())
+		}
+	}
+}
+
+func TestSafeTxHash(t *testing.T) {
+	safe := common.HexToAddress("0x1C8b9B78e3085866521FE206fa4c1a67F49f153A")
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	domainSeparator, err := SafeDomainSeparator(big.NewInt(5), safe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// computed by EIP-712 typed data hashing of SafeTx
+	expected := "0x3a8442039857217a33aa4ea779624d2a10a513a982ce9cac544cd7f85725b125"
+	hash, err := NewSafeTx(to, big.NewInt(1e18), common.FromHex("0xa9059cbb"), big.NewInt(7)).Hash(domainSeparator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash.Hex() != expected {
+		t.Fatalf("expected: %v, got: %v", expected, hash.Hex())
+	}
+}
+
+func TestSafeCombineSignatures(t *testing.T) {
+	safeTxHash := common.HexToHash("0x3a8442039857217a33aa4ea779624d2a10a513a982ce9cac544cd7f85725b125")
+	key1 := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	key2 := mustParsePrivateKey("0x3a5bd3bf6e6e2e1d1bd2e8a0ba7c8d04cf3e5e0f0d6b5b8e1f5a4f8b9c0d1e2f")
+	sig1, err := SafeSignTxHash(safeTxHash, key1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig2, err := SafeSignTxHash(safeTxHash, key2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner3 := common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	sig3 := SafePreValidatedSignature(owner3)
+
+	signatures := map[common.Address][]byte{AddressFromPrivateKey(key1): sig1, AddressFromPrivateKey(key2): sig2, owner3: sig3}
+	for owner, signature := range signatures {
+		got, err := SafeSignatureOwner(safeTxHash, signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != owner {
+			t.Fatalf("expected: %v, got: %v", owner.Hex(), got.Hex())
+		}
+	}
+
+	combined, err := SafeCombineSignatures(safeTxHash, [][]byte{sig3, sig1, sig2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(combined) != 3*65 {
+		t.Fatalf("expected: %v, got: %v", 3*65, len(combined))
+	}
+	var prev common.Address
+	for i := 0; i < len(combined); i += 65 {
+		owner, err := SafeSignatureOwner(safeTxHash, combined[i:i+65])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(owner[:], prev[:]) <= 0 {
+			t.Fatalf("signatures are not sorted by owner")
+		}
+		prev = owner
+	}
+
+	if _, err := SafeCombineSignatures(safeTxHash, [][]byte{sig1, sig1}); err == nil {
+		t.Fatalf("expected error for duplicate signatures")
+	}
+}
+
+func TestSafeApprovalCount(t *testing.T) {
+	var owner1 = common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	var owner2 = common.HexToAddress("0xB2aC853cF815B47903bc19BF4860540306F4f944")
+	var owner3 = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+
+	tests := []struct {
+		approvals        []SafeOwnerApproval
+		threshold        uint64
+		expectedApproved uint64
+		expectedMissing  uint64
+	}{
+		{[]SafeOwnerApproval{{Owner: owner1}, {Owner: owner2}, {Owner: owner3}}, 2, 0, 2},
+		{[]SafeOwnerApproval{{Owner: owner1, OffChain: true}, {Owner: owner2}, {Owner: owner3}}, 2, 1, 1},
+		{[]SafeOwnerApproval{{Owner: owner1, OnChain: true, OffChain: true}, {Owner: owner2, OnChain: true}, {Owner: owner3}}, 2, 2, 0},
+		{[]SafeOwnerApproval{{Owner: owner1, OffChain: true}, {Owner: owner2, OffChain: true}, {Owner: owner3, OnChain: true}}, 2, 3, 0},
+	}
+
+	for i, test := range tests {
+		approved, missing := SafeApprovalCount(test.approvals, test.threshold)
+		if approved != test.expectedApproved || missing != test.expectedMissing {
+			t.Fatalf("test %d: expected: %v %v, got: %v %v", i, test.expectedApproved, test.expectedMissing, approved, missing)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SafeTxServiceUrls are the base urls of Safe Transaction Service by chain id.
+// See: https://docs.safe.global/api-supported-networks
+var SafeTxServiceUrls = map[int64]string{
+	1:        "https://safe-transaction-mainnet.safe.global",
+	5:        "https://safe-transaction-goerli.safe.global",
+	56:       "https://safe-transaction-bsc.safe.global",
+	100:      "https://safe-transaction-gnosis-chain.safe.global",
+	11155111: "https://safe-transaction-sepolia.safe.global",
+}
+
+// safeTxServiceRequest sends json request to Safe Transaction Service, and decodes json response into v if it's not nil.
+func safeTxServiceRequest(ctx context.Context, method string, url string, body any, v any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%v %v returns %v: %s", method, url, resp.Status, respBody)
+	}
+	if v != nil {
+		if err := json.Unmarshal(respBody, v); err != nil {
+			return fmt.Errorf("parse safe transaction service response fail: %w", err)
+		}
+	}
+	return nil
+}
+
+// SafeProposeTx posts safe tx with signature of sender (an owner) to Safe Transaction Service, then other owners can
+// confirm it in Safe web app or by SafeConfirmTx.
+func SafeProposeTx(ctx context.Context, baseUrl string, safe common.Address, tx *SafeTx, safeTxHash common.Hash, sender common.Address, signature []byte) error {
+	body := map[string]any{
+		"safe":                    safe.Hex(),
+		"to":                      tx.To.Hex(),
+		"value":                   tx.Value.String(),
+		"data":                    nil,
+		"operation":               tx.Operation,
+		"safeTxGas":               tx.SafeTxGas.String(),
+		"baseGas":                 tx.BaseGas.String(),
+		"gasPrice":                tx.GasPrice.String(),
+		"gasToken":                tx.GasToken.Hex(),
+		"refundReceiver":          tx.RefundReceiver.Hex(),
+		"nonce":                   tx.Nonce.String(),
+		"contractTransactionHash": safeTxHash.Hex(),
+		"sender":                  sender.Hex(),
+		"signature":               hexutil.Encode(signature),
+		"origin":                  "ethutil",
+	}
+	if len(tx.Data) > 0 {
+		body["data"] = hexutil.Encode(tx.Data)
+	}
+	return safeTxServiceRequest(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/safes/%s/multisig-transactions/", baseUrl, safe.Hex()), body, nil)
+}
+
+// SafeConfirmTx posts signature of an owner for safe tx which is already proposed to Safe Transaction Service.
+func SafeConfirmTx(ctx context.Context, baseUrl string, safeTxHash common.Hash, signature []byte) error {
+	body := map[string]any{"signature": hexutil.Encode(signature)}
+	return safeTxServiceRequest(ctx, http.MethodPost, fmt.Sprintf("%s/api/v1/multisig-transactions/%s/confirmations/", baseUrl, safeTxHash.Hex()), body, nil)
+}
+
+// SafeTxConfirmation is the confirmation (signature) of an owner in Safe Transaction Service.
+type SafeTxConfirmation struct {
+	Owner     common.Address `json:"owner"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// SafeServiceTx is the safe tx in Safe Transaction Service.
+type SafeServiceTx struct {
+	Safe            common.Address       `json:"safe"`
+	Nonce           uint64               `json:"nonce"`
+	IsExecuted      bool                 `json:"isExecuted"`
+	TransactionHash *common.Hash         `json:"transactionHash"` // the tx executing safe tx, nil if it's not executed
+	Confirmations   []SafeTxConfirmation `json:"confirmations"`
+}
+
+// SafeGetServiceTx returns safe tx proposed to Safe Transaction Service.
+func SafeGetServiceTx(ctx context.Context, baseUrl string, safeTxHash common.Hash) (*SafeServiceTx, error) {
+	var result SafeServiceTx
+	if err := safeTxServiceRequest(ctx, http.MethodGet, fmt.Sprintf("%s/api/v1/multisig-transactions/%s/", baseUrl, safeTxHash.Hex()), nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SafeTxConfirmations returns signatures of owners who confirmed safe tx in Safe Transaction Service.
+func SafeTxConfirmations(ctx context.Context, baseUrl string, safeTxHash common.Hash) ([][]byte, error) {
+	tx, err := SafeGetServiceTx(ctx, baseUrl, safeTxHash)
+	if err != nil {
+		return nil, err
+	}
+	var signatures [][]byte
+	for _, confirmation := range tx.Confirmations {
+		signatures = append(signatures, confirmation.Signature)
+	}
+	return signatures, nil
+}
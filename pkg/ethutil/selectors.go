@@ -0,0 +1,54 @@
+package ethutil
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ExtractSelectors returns 4 bytes function selectors (e.g. "0xa9059cbb") of the function dispatcher in runtime
+// bytecode, in order of appearance. It matches comparisons of calldata selector followed by conditional jump, i.e.
+// PUSH4 selector [DUP2] (EQ | XOR) PUSHn dest JUMPI, which covers dispatchers generated by solc and vyper. Some
+// selectors may be missed if the dispatcher is unusual, e.g. hand-written or jump table based.
+func ExtractSelectors(code []byte) []string {
+	// decode instructions, data of PUSHn is not instruction
+	type instruction struct {
+		op   vm.OpCode
+		data []byte
+	}
+	var instructions []instruction
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if op >= vm.PUSH1 && op <= vm.PUSH32 {
+			size := int(op-vm.PUSH1) + 1
+			if pc+1+size > len(code) {
+				break // truncated push, e.g. in metadata
+			}
+			instructions = append(instructions, instruction{op, code[pc+1 : pc+1+size]})
+			pc += size
+			continue
+		}
+		instructions = append(instructions, instruction{op: op})
+	}
+
+	var selectors []string
+	var seen = make(map[string]bool)
+	for i, ins := range instructions {
+		if ins.op != vm.PUSH4 {
+			continue
+		}
+		j := i + 1
+		if j < len(instructions) && instructions[j].op == vm.DUP2 {
+			j++
+		}
+		if j+2 >= len(instructions) || (instructions[j].op != vm.EQ && instructions[j].op != vm.XOR) ||
+			instructions[j+1].op < vm.PUSH1 || instructions[j+1].op > vm.PUSH4 || instructions[j+2].op != vm.JUMPI {
+			continue
+		}
+		selector := hexutil.Encode(ins.data)
+		if !seen[selector] {
+			seen[selector] = true
+			selectors = append(selectors, selector)
+		}
+	}
+	return selectors
+}
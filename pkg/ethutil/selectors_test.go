@@ -0,0 +1,48 @@
+package ethutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestExtractSelectors(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			// DUP1 PUSH4 a9059cbb EQ PUSH2 0040 JUMPI DUP1 PUSH4 70a08231 EQ PUSH2 0050 JUMPI
+			"solc",
+			"0x8063a9059cbb14610040578063" + "70a0823114610050575b",
+			[]string{"0xa9059cbb", "0x70a08231"},
+		},
+		{
+			// PUSH4 a9059cbb DUP2 EQ PUSH2 0040 JUMPI, PUSH4 70a08231 DUP2 XOR PUSH1 50 JUMPI
+			"optimized and vyper",
+			"0x63a9059cbb81146100405763" + "70a082318118605057",
+			[]string{"0xa9059cbb", "0x70a08231"},
+		},
+		{
+			// PUSH4 ffffffff AND is mask of selector, PUSH32 containing 63xxxxxxxx14 is data
+			"mask and push data",
+			"0x63ffffffff167f63a9059cbb1461004057000000000000000000000000000000000000000000000000",
+			nil,
+		},
+		{
+			// duplicated selector and truncated push
+			"duplicated",
+			"0x63a9059cbb1461004057" + "63a9059cbb146100605761",
+			[]string{"0xa9059cbb"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractSelectors(hexutil.MustDecode(tt.code)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSelectors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
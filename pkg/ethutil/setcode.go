@@ -0,0 +1,183 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// SetCodeTxType is the type of EIP-7702 set code tx.
+// See: https://eips.ethereum.org/EIPS/eip-7702
+const SetCodeTxType = 0x04
+
+// setCodeAuthorizationMagic is the prefix of the signing data of EIP-7702 authorization.
+const setCodeAuthorizationMagic = 0x05
+
+// SetCodeAuthorization is an EIP-7702 authorization, by which the authority (signer) delegates its code to Address.
+// Chain id 0 means the authorization is valid on any chain. The json format is same as geth.
+type SetCodeAuthorization struct {
+	ChainID *hexutil.Big   `json:"chainId"`
+	Address common.Address `json:"address"`
+	Nonce   hexutil.Uint64 `json:"nonce"`
+	V       hexutil.Uint64 `json:"yParity"`
+	R       *hexutil.Big   `json:"r"`
+	S       *hexutil.Big   `json:"s"`
+}
+
+// setCodeAuthorizationRLP is the rlp encoding layout of SetCodeAuthorization.
+type setCodeAuthorizationRLP struct {
+	ChainID *big.Int
+	Address common.Address
+	Nonce   uint64
+	V       uint8
+	R       *big.Int
+	S       *big.Int
+}
+
+// setCodeAuthorizationHash returns the hash signed by authority, i.e. keccak256(0x05 || rlp([chain_id, address, nonce])).
+func setCodeAuthorizationHash(chainID *big.Int, address common.Address, nonce uint64) (common.Hash, error) {
+	encoded, err := rlp.EncodeToBytes([]any{chainID, address, nonce})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{setCodeAuthorizationMagic}, encoded), nil
+}
+
+// SignSetCodeAuthorization signs authorization which delegates code of the account of privateKey to delegate. nonce
+// must be the nonce of the account when the tx is executed, i.e. plus 1 if the account sends the tx itself.
+func SignSetCodeAuthorization(chainID *big.Int, delegate common.Address, nonce uint64, privateKey *ecdsa.PrivateKey) (*SetCodeAuthorization, error) {
+	hash, err := setCodeAuthorizationHash(chainID, delegate, nonce)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash[:], privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SetCodeAuthorization{
+		ChainID: (*hexutil.Big)(chainID),
+		Address: delegate,
+		Nonce:   hexutil.Uint64(nonce),
+		V:       hexutil.Uint64(signature[64]),
+		R:       (*hexutil.Big)(new(big.Int).SetBytes(signature[:32])),
+		S:       (*hexutil.Big)(new(big.Int).SetBytes(signature[32:64])),
+	}, nil
+}
+
+// Authority returns the signer of authorization.
+func (a *SetCodeAuthorization) Authority() (common.Address, error) {
+	if a.ChainID == nil || a.R == nil || a.S == nil {
+		return common.Address{}, fmt.Errorf("chainId, r and s of authorization are required")
+	}
+	if a.V > 1 {
+		return common.Address{}, fmt.Errorf("yParity of authorization must be 0 or 1, got %v", uint64(a.V))
+	}
+	hash, err := setCodeAuthorizationHash(a.ChainID.ToInt(), a.Address, uint64(a.Nonce))
+	if err != nil {
+		return common.Address{}, err
+	}
+	var signature = make([]byte, 65)
+	a.R.ToInt().FillBytes(signature[:32])
+	a.S.ToInt().FillBytes(signature[32:64])
+	signature[64] = byte(a.V)
+	pubkey, err := crypto.SigToPub(hash[:], signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recover authority fail: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+func (a *SetCodeAuthorization) rlpLayout() setCodeAuthorizationRLP {
+	return setCodeAuthorizationRLP{a.ChainID.ToInt(), a.Address, uint64(a.Nonce), uint8(a.V), a.R.ToInt(), a.S.ToInt()}
+}
+
+// SetCodeTx is an EIP-7702 set code tx, which is not supported by types.Transaction of the go-ethereum version in use.
+type SetCodeTx struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         common.Address // contract creation is not allowed
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+	AuthList   []SetCodeAuthorization
+}
+
+// NewSetCodeTx returns set code tx with fields of eip1559 tx and authorizations.
+func NewSetCodeTx(tx *types.Transaction, chainID *big.Int, authList []SetCodeAuthorization) (*SetCodeTx, error) {
+	if tx.Type() != types.DynamicFeeTxType {
+		return nil, fmt.Errorf("set code tx requires eip1559 fees, got tx type %v", tx.Type())
+	}
+	if tx.To() == nil {
+		return nil, fmt.Errorf("set code tx can not create contract")
+	}
+	if len(authList) == 0 {
+		return nil, fmt.Errorf("set code tx requires at least one authorization")
+	}
+	return &SetCodeTx{
+		ChainID:    chainID,
+		Nonce:      tx.Nonce(),
+		GasTipCap:  tx.GasTipCap(),
+		GasFeeCap:  tx.GasFeeCap(),
+		Gas:        tx.Gas(),
+		To:         *tx.To(),
+		Value:      tx.Value(),
+		Data:       tx.Data(),
+		AccessList: tx.AccessList(),
+		AuthList:   authList,
+	}, nil
+}
+
+// fields returns the rlp fields of tx without signature.
+func (tx *SetCodeTx) fields() []any {
+	var authList = make([]setCodeAuthorizationRLP, 0, len(tx.AuthList))
+	for i := range tx.AuthList {
+		authList = append(authList, tx.AuthList[i].rlpLayout())
+	}
+	var accessList = tx.AccessList
+	if accessList == nil {
+		accessList = types.AccessList{}
+	}
+	return []any{tx.ChainID, tx.Nonce, tx.GasTipCap, tx.GasFeeCap, tx.Gas, tx.To, tx.Value, tx.Data, accessList, authList}
+}
+
+// SigHash returns the hash signed by sender, i.e. keccak256(0x04 || rlp(fields without signature)).
+func (tx *SetCodeTx) SigHash() (common.Hash, error) {
+	encoded, err := rlp.EncodeToBytes(tx.fields())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash([]byte{SetCodeTxType}, encoded), nil
+}
+
+// Sign signs tx with privateKey, returns the raw signed tx and its hash.
+func (tx *SetCodeTx) Sign(privateKey *ecdsa.PrivateKey) ([]byte, common.Hash, error) {
+	for i := range tx.AuthList {
+		if tx.AuthList[i].ChainID == nil || tx.AuthList[i].R == nil || tx.AuthList[i].S == nil {
+			return nil, common.Hash{}, fmt.Errorf("authorization %d is not signed", i)
+		}
+	}
+	sigHash, err := tx.SigHash()
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	signature, err := crypto.Sign(sigHash[:], privateKey)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	fields := append(tx.fields(), uint8(signature[64]), new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:64]))
+	encoded, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	raw := append([]byte{SetCodeTxType}, encoded...)
+	return raw, crypto.Keccak256Hash(raw), nil
+}
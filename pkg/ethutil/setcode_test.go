@@ -0,0 +1,94 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestSetCodeAuthorization(t *testing.T) {
+	privateKey, _ := crypto.HexToECDSA("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	delegate := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+
+	tests := []struct {
+		chainID *big.Int
+		nonce   uint64
+	}{
+		{big.NewInt(1), 0},
+		{big.NewInt(11155111), 7},
+		{big.NewInt(0), 1}, // valid on any chain
+	}
+
+	for i, test := range tests {
+		auth, err := SignSetCodeAuthorization(test.chainID, delegate, test.nonce, privateKey)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		authority, err := auth.Authority()
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if authority != crypto.PubkeyToAddress(privateKey.PublicKey) {
+			t.Fatalf("test %d: expected authority: %v, got: %v", i, crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), authority.Hex())
+		}
+		// the authority changes if any signed field is tampered
+		auth.Nonce++
+		if tampered, _ := auth.Authority(); tampered == authority {
+			t.Fatalf("test %d: authority of tampered authorization is not changed", i)
+		}
+	}
+}
+
+func TestSetCodeTxSign(t *testing.T) {
+	sponsorKey, _ := crypto.GenerateKey()
+	authorityKey, _ := crypto.GenerateKey()
+	to := crypto.PubkeyToAddress(authorityKey.PublicKey)
+	chainID := big.NewInt(11155111)
+	auth, err := SignSetCodeAuthorization(chainID, common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb"), 0, authorityKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eip1559Tx := types.NewTx(&types.DynamicFeeTx{ChainID: chainID, Nonce: 3, GasTipCap: big.NewInt(1e9), GasFeeCap: big.NewInt(3e10),
+		Gas: 100000, To: &to, Value: big.NewInt(0), Data: []byte{0x12, 0x34}})
+	tx, err := NewSetCodeTx(eip1559Tx, chainID, []SetCodeAuthorization{*auth})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, hash, err := tx.Sign(sponsorKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw[0] != SetCodeTxType || hash != crypto.Keccak256Hash(raw) {
+		t.Fatalf("unexpected raw tx %x or hash %v", raw, hash.Hex())
+	}
+
+	// chain_id, nonce, max_priority_fee_per_gas, max_fee_per_gas, gas_limit, destination, value, data, access_list,
+	// authorization_list, y_parity, r, s
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(raw[1:], &fields); err != nil || len(fields) != 13 {
+		t.Fatalf("expected 13 rlp fields, got %v (%v)", len(fields), err)
+	}
+	var v uint8
+	var r, s *big.Int
+	_ = rlp.DecodeBytes(fields[10], &v)
+	_ = rlp.DecodeBytes(fields[11], &r)
+	_ = rlp.DecodeBytes(fields[12], &s)
+	sigHash, _ := tx.SigHash()
+	var signature = make([]byte, 65)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:64])
+	signature[64] = v
+	pubkey, err := crypto.SigToPub(sigHash[:], signature)
+	if err != nil || crypto.PubkeyToAddress(*pubkey) != crypto.PubkeyToAddress(sponsorKey.PublicKey) {
+		t.Fatalf("sender of signed tx is not the sponsor (%v)", err)
+	}
+
+	if _, err := NewSetCodeTx(eip1559Tx, chainID, nil); err == nil {
+		t.Fatalf("expected error of set code tx without authorization")
+	}
+}
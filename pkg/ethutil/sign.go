@@ -0,0 +1,137 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ParsePrivateKey builds ecdsa.PrivateKey from hex string (the leading 0x is optional).
+func ParsePrivateKey(privateKeyHex string) (*ecdsa.PrivateKey, error) {
+	if len(privateKeyHex) >= 2 && privateKeyHex[0] == '0' && (privateKeyHex[1] == 'x' || privateKeyHex[1] == 'X') {
+		privateKeyHex = privateKeyHex[2:] // remove leading 0x
+	}
+
+	return crypto.HexToECDSA(privateKeyHex)
+}
+
+// AddressFromPrivateKey extracts address from ecdsa.PrivateKey.
+func AddressFromPrivateKey(privateKey *ecdsa.PrivateKey) common.Address {
+	return crypto.PubkeyToAddress(privateKey.PublicKey)
+}
+
+// PersonalSign returns personal_sign signature data
+// See: https://eips.ethereum.org/EIPS/eip-191
+// The signature data can be verified in https://etherscan.io/verifiedSignatures
+func PersonalSign(message string, privateKey *ecdsa.PrivateKey) (string, error) {
+	return PersonalSignBytes([]byte(message), privateKey)
+}
+
+// PersonalSignBytes returns personal_sign signature of binary message, e.g. a 32 bytes digest. The length in
+// prefix is the number of bytes, not the length of hex string.
+func PersonalSignBytes(message []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return SignKeccak(append([]byte(prefix), message...), privateKey)
+}
+
+// RecoverPersonalSignBytes returns the signer of personal_sign signature of message, v of signature can be 0, 1, 27
+// or 28.
+func RecoverPersonalSignBytes(message []byte, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes, got %v bytes", len(signature))
+	}
+	var sig = make([]byte, 65)
+	copy(sig, signature)
+	sig[64] = byte(GetRecoveryId(big.NewInt(int64(sig[64]))))
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	pubkey, err := crypto.SigToPub(crypto.Keccak256([]byte(prefix), message), sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubkey), nil
+}
+
+// SignKeccak signs keccak256 of data without EIP191 prefix, v of the returned signature is 27 or 28.
+func SignKeccak(data []byte, privateKey *ecdsa.PrivateKey) (string, error) {
+	signature, err := SignHash(crypto.Keccak256(data), privateKey)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(signature), nil
+}
+
+// SignHash signs 32 bytes digest directly, returns 65 bytes signature r || s || v, v is 27 or 28.
+func SignHash(hash []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("hash must be 32 bytes, got %v bytes", len(hash))
+	}
+	signature, err := crypto.Sign(hash, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// CompactSignature converts 65 bytes signature r || s || v to 64 bytes EIP-2098 compact signature r || yParityAndS,
+// the highest bit of yParityAndS is the y parity.
+// See: https://eips.ethereum.org/EIPS/eip-2098
+func CompactSignature(signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("signature must be 65 bytes, got %v bytes", len(signature))
+	}
+	var compact = make([]byte, 64)
+	copy(compact, signature[:64])
+	if compact[32]&0x80 != 0 {
+		return nil, fmt.Errorf("s is not canonical (in lower half order)")
+	}
+	if GetRecoveryId(big.NewInt(int64(signature[64]))) == 1 {
+		compact[32] |= 0x80
+	}
+	return compact, nil
+}
+
+// GetRecoveryId gets ecdsa recover id (0 or 1) from v.
+func GetRecoveryId(v *big.Int) int {
+	// Note: can be simplified by checking parity (i.e. odd-even)
+	var recoveryId int
+	if v.Int64() == 0 || v.Int64() == 1 { // v in eip2718
+		recoveryId = int(v.Int64())
+	} else if v.Int64() == 27 || v.Int64() == 28 { // v before eip155
+		recoveryId = int(v.Int64()) - 27
+	} else { // v in eip155
+		// derive chainId
+		var chainId = int((v.Int64() - 35) / 2)
+		// derive recoveryId
+		recoveryId = int(v.Int64()) - 35 - 2*chainId
+	}
+	return recoveryId
+}
+
+// BuildECDSASignature builds a 65-byte compact ECDSA signature (containing the recovery id as the last element)
+func BuildECDSASignature(v, r, s *big.Int) []byte {
+	var recoveryId = GetRecoveryId(v)
+
+	var rBytes = make([]byte, 32, 32)
+	var sBytes = make([]byte, 32, 32)
+	copy(rBytes[32-len(r.Bytes()):], r.Bytes())
+	copy(sBytes[32-len(s.Bytes()):], s.Bytes())
+
+	var rsBytes = append(rBytes, sBytes...)
+	return append(rsBytes, byte(recoveryId))
+}
+
+// RecoverPubkey recover public key, returns 65 bytes uncompressed public key
+func RecoverPubkey(v, r, s *big.Int, msg []byte) ([]byte, error) {
+	signature := BuildECDSASignature(v, r, s)
+
+	// recover public key from msg (hash of data) and ECDSA signature
+	// crypto.Ecrecover msg: 32 bytes hash
+	// crypto.Ecrecover signature: 65-byte compact ECDSA signature
+	// crypto.Ecrecover return 65 bytes uncompressed public key
+	return crypto.Ecrecover(msg, signature)
+}
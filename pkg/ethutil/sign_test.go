@@ -0,0 +1,105 @@
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func mustParsePrivateKey(privateKeyHex string) *ecdsa.PrivateKey {
+	privateKey, err := ParsePrivateKey(privateKeyHex)
+	if err != nil {
+		panic(err)
+	}
+	return privateKey
+}
+
+func TestPersonalSign(t *testing.T) {
+	tests := []struct {
+		input1 string
+		input2 string
+		want   string
+	}{
+		{
+			input1: "abc",
+			input2: "0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21",
+			want:   "0x3ba90844d3f6e4b9bbb31a9e79b685d3873c10420528a9f67271a98b62230b323d8a4f0780f74513ac3296673b36938383c8723d6e47de8f6d785a0b0579f2001b",
+		},
+		{
+			input1: "hello eth",
+			input2: "0x4f66baf5a1c3a91b6cf8173cdb60d12496e1f572cee6f9f86bc507d87a9790d7",
+			want:   "0xa2999d1ac51dece28dbfd6a1aa417fa48a3f9107f5fe9cdf5578859fb65763b52faf34dfb7f86069b1da162145b32949545c7cafef0017844bd3e7191d2236ae1c",
+		},
+	}
+
+	for i, tc := range tests {
+		got, _ := PersonalSign(tc.input1, mustParsePrivateKey(tc.input2))
+		if tc.want != got {
+			t.Fatalf("test %d: expected: %v, got: %v", i+1, tc.want, got)
+		}
+	}
+}
+
+func TestSignKeccak(t *testing.T) {
+	privateKey := mustParsePrivateKey("0x47ab031333b76182b744e1e3b6ddb28604fdeb6ec8afdd4961335f81815c6f21")
+	tests := []struct {
+		message []byte
+	}{
+		{[]byte("abc")},
+		{[]byte{0x00, 0xff, 0x19, 0x01}},
+		{crypto.Keccak256([]byte("hello eth"))},
+	}
+
+	for i, tc := range tests {
+		got, err := SignKeccak(tc.message, privateKey)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		signature := hexutil.MustDecode(got)
+		if signature[64] != 27 && signature[64] != 28 {
+			t.Fatalf("test %d: expected: v is 27 or 28, got: %v", i, signature[64])
+		}
+		signature[64] -= 27
+		pubkey, err := crypto.SigToPub(crypto.Keccak256(tc.message), signature)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if crypto.PubkeyToAddress(*pubkey) != AddressFromPrivateKey(privateKey) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, AddressFromPrivateKey(privateKey), crypto.PubkeyToAddress(*pubkey))
+		}
+	}
+}
+
+func TestCompactSignature(t *testing.T) {
+	// test vectors in https://eips.ethereum.org/EIPS/eip-2098
+	tests := []struct {
+		signature string
+		want      string
+	}{
+		{
+			signature: "0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b90" + "7e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064" + "1b",
+			want:      "0x68a020a209d3d56c46f38cc50a33f704f4a9a10a59377f8dd762ac66910e9b90" + "7e865ad05c4035ab5792787d4a0297a43617ae897930a6fe4d822b8faea52064",
+		},
+		{
+			signature: "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76" + "139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793" + "1c",
+			want:      "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76" + "939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+		},
+		{
+			// v is recovery id
+			signature: "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76" + "139c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793" + "01",
+			want:      "0x9328da16089fcba9bececa81663203989f2df5fe1faa6291a45381c81bd17f76" + "939c6d6b623b42da56557e5e734a43dc83345ddfadec52cbe24d0cc64f550793",
+		},
+	}
+
+	for i, tc := range tests {
+		compact, err := CompactSignature(hexutil.MustDecode(tc.signature))
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if got := hexutil.Encode(compact); got != tc.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SolcSettings is the compiler settings of standard json input.
+type SolcSettings struct {
+	Optimize   bool
+	Runs       int
+	EvmVersion string // optional, default is the default of compiler
+}
+
+// StandardJsonInput returns solc standard json input of sources, which maps source unit name (e.g. contracts/Token.sol)
+// to content. Imported files not in sources are resolved by compiler, e.g. by --base-path of solc.
+func StandardJsonInput(sources map[string]string, settings SolcSettings) ([]byte, error) {
+	var inputSources = make(map[string]any)
+	for name, content := range sources {
+		inputSources[name] = map[string]string{"content": content}
+	}
+	inputSettings := map[string]any{
+		"optimizer": map[string]any{"enabled": settings.Optimize, "runs": settings.Runs},
+		"outputSelection": map[string]any{
+			"*": map[string]any{"*": []string{"abi", "evm.bytecode", "evm.deployedBytecode", "metadata"}},
+		},
+	}
+	if settings.EvmVersion != "" {
+		inputSettings["evmVersion"] = settings.EvmVersion
+	}
+	return json.Marshal(map[string]any{
+		"language": "Solidity",
+		"sources":  inputSources,
+		"settings": inputSettings,
+	})
+}
+
+// SolcContract is a compiled contract in standard json output.
+type SolcContract struct {
+	Abi json.RawMessage `json:"abi"`
+	Evm struct {
+		Bytecode struct {
+			Object string `json:"object"` // hex without 0x, empty for abstract contract and interface
+		} `json:"bytecode"`
+		DeployedBytecode struct {
+			Object string `json:"object"`
+		} `json:"deployedBytecode"`
+	} `json:"evm"`
+	Metadata string `json:"metadata"`
+}
+
+// SolcOutput is solc standard json output.
+type SolcOutput struct {
+	Errors []struct {
+		Severity         string `json:"severity"` // error, warning or info
+		FormattedMessage string `json:"formattedMessage"`
+		Message          string `json:"message"`
+	} `json:"errors"`
+	Contracts map[string]map[string]SolcContract `json:"contracts"` // source unit name => contract name => contract
+}
+
+// ParseSolcOutput parses solc standard json output, errors reported by compiler are returned as error, warnings are
+// returned as they are.
+func ParseSolcOutput(output []byte) (*SolcOutput, []string, error) {
+	var result SolcOutput
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, nil, fmt.Errorf("parse output of compiler fail: %w", err)
+	}
+	var errs, warnings []string
+	for _, e := range result.Errors {
+		message := strings.TrimSpace(e.FormattedMessage)
+		if message == "" {
+			message = e.Message
+		}
+		if e.Severity == "error" {
+			errs = append(errs, message)
+		} else {
+			warnings = append(warnings, message)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, warnings, fmt.Errorf("compile fail:\n%v", strings.Join(errs, "\n"))
+	}
+	return &result, warnings, nil
+}
+
+// ContractNames returns the fully qualified names (<source>:<name>) of compiled contracts, sorted.
+func (o *SolcOutput) ContractNames() []string {
+	var names []string
+	for source, contracts := range o.Contracts {
+		for name := range contracts {
+			names = append(names, source+":"+name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Contract returns the contract of name, which is a contract name (e.g. Token) or a fully qualified name (e.g.
+// contracts/Token.sol:Token) if the contract name is ambiguous. The fully qualified name is returned too.
+func (o *SolcOutput) Contract(name string) (string, *SolcContract, error) {
+	var matches []string
+	for _, fullName := range o.ContractNames() {
+		if fullName == name || strings.HasSuffix(fullName, ":"+name) {
+			matches = append(matches, fullName)
+		}
+	}
+	if len(matches) == 0 {
+		return "", nil, fmt.Errorf("contract %v is not found in output of compiler", name)
+	}
+	if len(matches) > 1 {
+		return "", nil, fmt.Errorf("contract name %v is ambiguous, use one of %v", name, strings.Join(matches, ", "))
+	}
+	source, contractName, _ := strings.Cut(matches[0], ":")
+	contract := o.Contracts[source][contractName]
+	return matches[0], &contract, nil
+}
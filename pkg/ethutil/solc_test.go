@@ -0,0 +1,55 @@
+package ethutil
+
+import (
+	"testing"
+)
+
+func TestParseSolcOutput(t *testing.T) {
+	output := `{
+  "errors": [{"severity": "warning", "formattedMessage": "Warning: SPDX license identifier not provided.\n"}],
+  "contracts": {
+    "contracts/Token.sol": {
+      "Token": {"abi": [], "evm": {"bytecode": {"object": "6080"}, "deployedBytecode": {"object": "6001"}}},
+      "Ownable": {"abi": [], "evm": {"bytecode": {"object": "6002"}, "deployedBytecode": {"object": "6003"}}}
+    },
+    "contracts/Other.sol": {
+      "Ownable": {"abi": [], "evm": {"bytecode": {"object": "6004"}, "deployedBytecode": {"object": "6005"}}}
+    }
+  }
+}`
+	result, warnings, err := ParseSolcOutput([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0] != "Warning: SPDX license identifier not provided." {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	tests := []struct {
+		name     string
+		fullName string
+		bytecode string
+		hasErr   bool
+	}{
+		{"Token", "contracts/Token.sol:Token", "6080", false},
+		{"contracts/Other.sol:Ownable", "contracts/Other.sol:Ownable", "6004", false},
+		{"Ownable", "", "", true}, // ambiguous
+		{"Missing", "", "", true},
+	}
+	for i, test := range tests {
+		fullName, contract, err := result.Contract(test.name)
+		if (err != nil) != test.hasErr {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if test.hasErr {
+			continue
+		}
+		if fullName != test.fullName || contract.Evm.Bytecode.Object != test.bytecode {
+			t.Fatalf("test %d: unexpected contract %v %+v", i, fullName, contract)
+		}
+	}
+
+	if _, _, err := ParseSolcOutput([]byte(`{"errors": [{"severity": "error", "formattedMessage": "ParserError: Expected ';'"}]}`)); err == nil {
+		t.Errorf("expected compile error")
+	}
+}
@@ -0,0 +1,197 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrContractNotVerified is returned if source of contract is not found in block explorer or Sourcify.
+var ErrContractNotVerified = errors.New("contract is not verified")
+
+// SourcifyServerUrl is the base url of Sourcify server
+const SourcifyServerUrl = "https://sourcify.dev/server"
+
+// ContractSource is the verified source of a contract.
+type ContractSource struct {
+	Name  string
+	Abi   string            // abi json
+	Files map[string]string // file path -> content
+}
+
+// httpGet gets url and returns response body, the response status must be 200.
+func httpGet(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %v returns %v", url, resp.Status)
+	}
+	return body, nil
+}
+
+// FetchEtherscanSource fetches source of contract by Etherscan-compatible getsourcecode api, requestUrl is the full
+// url, e.g. https://api.etherscan.io/api?module=contract&action=getsourcecode&address=0x...
+func FetchEtherscanSource(ctx context.Context, requestUrl string) (*ContractSource, error) {
+	body, err := httpGet(ctx, requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	type respMsg struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Result  json.RawMessage `json:"result"`
+	}
+	var data respMsg
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+	var results []struct {
+		SourceCode   string `json:"SourceCode"`
+		ABI          string `json:"ABI"`
+		ContractName string `json:"ContractName"`
+		// returned by Blockscout, SourceCode is the content of FileName, and other files are in AdditionalSources
+		FileName          string `json:"FileName"`
+		AdditionalSources []struct {
+			Filename   string `json:"Filename"`
+			SourceCode string `json:"SourceCode"`
+		} `json:"AdditionalSources"`
+	}
+	if err := json.Unmarshal(data.Result, &results); err != nil {
+		// result is an error string if request fails, e.g. "Invalid API Key"
+		return nil, fmt.Errorf("getsourcecode fail: %v %s", data.Message, data.Result)
+	}
+	if len(results) == 0 || len(results[0].SourceCode) == 0 {
+		return nil, ErrContractNotVerified
+	}
+
+	if results[0].FileName != "" && !strings.HasPrefix(results[0].SourceCode, "{") {
+		files := map[string]string{results[0].FileName: results[0].SourceCode}
+		for _, source := range results[0].AdditionalSources {
+			files[source.Filename] = source.SourceCode
+		}
+		return &ContractSource{Name: results[0].ContractName, Abi: results[0].ABI, Files: files}, nil
+	}
+	files, err := parseEtherscanSourceCode(results[0].SourceCode, results[0].ContractName)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractSource{Name: results[0].ContractName, Abi: results[0].ABI, Files: files}, nil
+}
+
+// parseEtherscanSourceCode parses SourceCode field of getsourcecode api, which has 3 formats.
+func parseEtherscanSourceCode(sourceCode string, contractName string) (map[string]string, error) {
+	var files = make(map[string]string)
+
+	if strings.HasPrefix(sourceCode, "{{") {
+		// Solidity Standard Json-Input
+		// An example: https://api.etherscan.io/api?module=contract&action=getsourcecode&address=0xa7EE3E16367D6Bd9dC59cc32Cdcc2eE51b663a4F
+
+		// Remove one leading "{" and one trailing "}"
+		sourceCode = strings.TrimPrefix(sourceCode, "{")
+		sourceCode = strings.TrimSuffix(sourceCode, "}")
+
+		type sourceJson struct {
+			Language string `json:"language"`
+			Sources  map[string]struct {
+				Content string `json:"content"`
+			} `json:"sources"`
+		}
+		var data sourceJson
+		if err := json.Unmarshal([]byte(sourceCode), &data); err != nil {
+			return nil, err
+		}
+		for name, source := range data.Sources {
+			files[name] = source.Content
+		}
+	} else if strings.HasPrefix(sourceCode, "{") {
+		// Solidity Multiple files format
+		// An example: https://api.etherscan.io/api?module=contract&action=getsourcecode&address=0x35036A4b7b012331f23F2945C08A5274CED38AC2
+		var data map[string]struct {
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal([]byte(sourceCode), &data); err != nil {
+			return nil, err
+		}
+		for name, source := range data {
+			files[name] = source.Content
+		}
+	} else {
+		// Solidity Single file
+		// An example: https://api.etherscan.io/api?module=contract&action=getsourcecode&address=0xdac17f958d2ee523a2206206994597c13d831ec7
+		files[contractName+".sol"] = sourceCode
+	}
+	return files, nil
+}
+
+// FetchSourcifySource fetches source of contract (full or partial match) from Sourcify.
+func FetchSourcifySource(ctx context.Context, chainID uint64, address common.Address) (*ContractSource, error) {
+	body, err := httpGet(ctx, fmt.Sprintf("%s/files/any/%d/%s", SourcifyServerUrl, chainID, address.Hex()))
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, ErrContractNotVerified
+		}
+		return nil, err
+	}
+
+	var data struct {
+		Status string `json:"status"` // full or partial
+		Files  []struct {
+			Name    string `json:"name"`
+			Path    string `json:"path"`
+			Content string `json:"content"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("parse sourcify response fail: %w", err)
+	}
+
+	var source = ContractSource{Files: make(map[string]string)}
+	for _, file := range data.Files {
+		// path is like /home/data/repository/contracts/full_match/1/0x.../sources/contracts/Foo.sol
+		name := file.Name
+		if i := strings.Index(file.Path, "/sources/"); i >= 0 {
+			name = file.Path[i+len("/sources/"):]
+		}
+		source.Files[name] = file.Content
+
+		if file.Name == "metadata.json" {
+			var metadata struct {
+				Output struct {
+					Abi json.RawMessage `json:"abi"`
+				} `json:"output"`
+				Settings struct {
+					CompilationTarget map[string]string `json:"compilationTarget"`
+				} `json:"settings"`
+			}
+			if err := json.Unmarshal([]byte(file.Content), &metadata); err != nil {
+				return nil, fmt.Errorf("parse metadata.json fail: %w", err)
+			}
+			source.Abi = string(metadata.Output.Abi)
+			for _, contractName := range metadata.Settings.CompilationTarget {
+				source.Name = contractName
+			}
+		}
+	}
+	if source.Abi == "" {
+		return nil, fmt.Errorf("metadata.json is not found in sourcify response")
+	}
+	return &source, nil
+}
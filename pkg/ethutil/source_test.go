@@ -0,0 +1,44 @@
+package ethutil
+
+import (
+	"testing"
+)
+
+func TestParseEtherscanSourceCode(t *testing.T) {
+	tests := []struct {
+		sourceCode   string
+		contractName string
+		want         map[string]string
+	}{
+		{
+			sourceCode:   "contract A {}",
+			contractName: "A",
+			want:         map[string]string{"A.sol": "contract A {}"},
+		},
+		{
+			sourceCode:   `{"A.sol": {"content": "contract A {}"}, "B.sol": {"content": "contract B {}"}}`,
+			contractName: "A",
+			want:         map[string]string{"A.sol": "contract A {}", "B.sol": "contract B {}"},
+		},
+		{
+			sourceCode:   `{{"language": "Solidity", "sources": {"contracts/A.sol": {"content": "contract A {}"}}}}`,
+			contractName: "A",
+			want:         map[string]string{"contracts/A.sol": "contract A {}"},
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := parseEtherscanSourceCode(tt.sourceCode, tt.contractName)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if len(output) != len(tt.want) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+		}
+		for name, content := range tt.want {
+			if output[name] != content {
+				t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultSourcifyUrl is the server of public Sourcify instance.
+const DefaultSourcifyUrl = "https://sourcify.dev/server"
+
+// SourcifyClient is a client of Sourcify server, the decentralized source verification service, which matches
+// the metadata hash in bytecode in addition to the bytecode itself. See: https://docs.sourcify.dev/docs/api/
+type SourcifyClient struct {
+	BaseUrl string // e.g. https://sourcify.dev/server
+}
+
+// SourcifyVerifyRequest is the request of verification by standard json input.
+type SourcifyVerifyRequest struct {
+	Address           common.Address
+	ChainId           uint64
+	CompilerVersion   string // e.g. 0.8.19+commit.7dd6d404, leading v is accepted
+	ContractName      string // the name of contract, e.g. Token, or contracts/Token.sol:Token
+	StandardJsonInput []byte
+}
+
+// SourcifyMatchPerfect and SourcifyMatchPartial are match status of Sourcify, partial match means the bytecode
+// matches but the metadata (e.g. comments or file names) differs.
+const (
+	SourcifyMatchPerfect = "perfect"
+	SourcifyMatchPartial = "partial"
+)
+
+// VerifySolcJson submits standard json input for verification, Sourcify compiles and compares synchronously, and
+// returns the match status.
+func (c *SourcifyClient) VerifySolcJson(ctx context.Context, req SourcifyVerifyRequest) (string, error) {
+	contractName := req.ContractName
+	if i := strings.LastIndex(contractName, ":"); i >= 0 {
+		contractName = contractName[i+1:]
+	}
+	content, err := json.Marshal(map[string]any{
+		"address":         req.Address.Hex(),
+		"chain":           strconv.FormatUint(req.ChainId, 10),
+		"compilerVersion": strings.TrimPrefix(req.CompilerVersion, "v"),
+		"contractName":    contractName,
+		"files":           map[string]string{"input.json": string(req.StandardJsonInput)},
+	})
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.BaseUrl+"/verify/solc-json", bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Error  string `json:"error"`
+		Result []struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("POST %v returns %v: %s", httpReq.URL, resp.Status, bytes.TrimSpace(body))
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("sourcify verification fail: %v", result.Error)
+	}
+	if len(result.Result) == 0 || result.Result[0].Status == "" {
+		return "", fmt.Errorf("sourcify verification fail: %s", bytes.TrimSpace(body))
+	}
+	return result.Result[0].Status, nil
+}
+
+// CheckByAddress returns the match status of address on chain, "false" means it's not verified.
+func (c *SourcifyClient) CheckByAddress(ctx context.Context, address common.Address, chainId uint64) (string, error) {
+	body, err := httpGet(ctx, c.BaseUrl+"/check-by-addresses?"+url.Values{
+		"addresses": {address.Hex()},
+		"chainIds":  {strconv.FormatUint(chainId, 10)},
+	}.Encode())
+	if err != nil {
+		return "", err
+	}
+	var result []struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("parse sourcify response fail: %w", err)
+	}
+	if len(result) == 0 {
+		return "", fmt.Errorf("address %v is not found in sourcify response", address.Hex())
+	}
+	return result[0].Status, nil
+}
+
+// SingleFileStandardJsonInput returns standard json input of single (flattened) source file, with the compiler
+// settings used by Etherscan for single file verification. evmVersion is optional.
+func SingleFileStandardJsonInput(fileName string, source string, optimize bool, runs int, evmVersion string) ([]byte, error) {
+	return StandardJsonInput(map[string]string{fileName: source}, SolcSettings{Optimize: optimize, Runs: runs, EvmVersion: evmVersion})
+}
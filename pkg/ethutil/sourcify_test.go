@@ -0,0 +1,97 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSourcifyClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/verify/solc-json":
+			var req struct {
+				Address         string            `json:"address"`
+				Chain           string            `json:"chain"`
+				CompilerVersion string            `json:"compilerVersion"`
+				ContractName    string            `json:"contractName"`
+				Files           map[string]string `json:"files"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			if req.ContractName != "Token" || req.CompilerVersion != "0.8.19+commit.7dd6d404" || req.Chain != "11155111" || req.Files["input.json"] == "" {
+				t.Errorf("unexpected verify request %+v", req)
+			}
+			if req.Address == "0x0000000000000000000000000000000000000002" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"The deployed and recompiled bytecode don't match."}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"result":[{"address":"%v","chainId":"%v","status":"partial"}]}`, req.Address, req.Chain)
+		case "/check-by-addresses":
+			if r.URL.Query().Get("chainIds") != "11155111" {
+				t.Errorf("unexpected query %v", r.URL.RawQuery)
+			}
+			_, _ = fmt.Fprintf(w, `[{"address":"%v","status":"false"}]`, r.URL.Query().Get("addresses"))
+		}
+	}))
+	defer server.Close()
+	ctx := context.Background()
+	client := &SourcifyClient{BaseUrl: server.URL}
+
+	input, err := SingleFileStandardJsonInput("Token.sol", "contract Token {}", true, 200, "paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := SourcifyVerifyRequest{
+		Address:           common.HexToAddress("0x01"),
+		ChainId:           11155111,
+		CompilerVersion:   "v0.8.19+commit.7dd6d404",
+		ContractName:      "contracts/Token.sol:Token",
+		StandardJsonInput: input,
+	}
+	status, err := client.VerifySolcJson(ctx, req)
+	if err != nil || status != SourcifyMatchPartial {
+		t.Errorf("unexpected status %v, error %v", status, err)
+	}
+
+	req.Address = common.HexToAddress("0x02")
+	if _, err := client.VerifySolcJson(ctx, req); err == nil {
+		t.Errorf("expected error of mismatched bytecode")
+	}
+
+	status, err = client.CheckByAddress(ctx, common.HexToAddress("0x01"), 11155111)
+	if err != nil || status != "false" {
+		t.Errorf("unexpected status %v, error %v", status, err)
+	}
+}
+
+func TestSingleFileStandardJsonInput(t *testing.T) {
+	input, err := SingleFileStandardJsonInput("Token.sol", "contract Token {}", true, 1000, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Sources  map[string]struct{ Content string } `json:"sources"`
+		Settings struct {
+			Optimizer struct {
+				Enabled bool `json:"enabled"`
+				Runs    int  `json:"runs"`
+			} `json:"optimizer"`
+			EvmVersion *string `json:"evmVersion"`
+		} `json:"settings"`
+	}
+	if err := json.Unmarshal(input, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Sources["Token.sol"].Content != "contract Token {}" || !parsed.Settings.Optimizer.Enabled ||
+		parsed.Settings.Optimizer.Runs != 1000 || parsed.Settings.EvmVersion != nil {
+		t.Errorf("unexpected standard json input %s", input)
+	}
+}
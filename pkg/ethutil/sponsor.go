@@ -0,0 +1,284 @@
+package ethutil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SponsorCall is a call executed by the EIP-7702 delegated account of beneficiary, whose gas is paid by sponsor.
+type SponsorCall struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+// SponsorCallsDigest returns the digest of calls signed by beneficiary, which is compatible with the delegate contract
+// BatchCallAndSponsor: keccak256(abi.encodePacked(nonce, to0, value0, data0, to1, value1, data1, ...)).
+// See: https://book.getfoundry.sh/tutorials/eip7702
+func SponsorCallsDigest(nonce *big.Int, calls []SponsorCall) common.Hash {
+	var encoded = common.LeftPadBytes(nonce.Bytes(), 32)
+	for _, call := range calls {
+		encoded = append(encoded, call.To.Bytes()...)
+		encoded = append(encoded, common.LeftPadBytes(call.Value.Bytes(), 32)...)
+		encoded = append(encoded, call.Data...)
+	}
+	return crypto.Keccak256Hash(encoded)
+}
+
+// SponsorSignCalls signs calls by beneficiary, the signature is EIP-191 personal signature of SponsorCallsDigest.
+func SponsorSignCalls(nonce *big.Int, calls []SponsorCall, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	digest := SponsorCallsDigest(nonce, calls)
+	signature, err := PersonalSignBytes(digest[:], privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Decode(signature)
+}
+
+// SponsorCallsSigner returns the signer of calls signed by SponsorSignCalls.
+func SponsorCallsSigner(nonce *big.Int, calls []SponsorCall, signature []byte) (common.Address, error) {
+	digest := SponsorCallsDigest(nonce, calls)
+	return RecoverPersonalSignBytes(digest[:], signature)
+}
+
+// SponsorExecuteData builds input data of execute((address,uint256,bytes)[],bytes) of delegated account.
+func SponsorExecuteData(calls []SponsorCall, signature []byte) ([]byte, error) {
+	var elems []string
+	for _, call := range calls {
+		elems = append(elems, fmt.Sprintf("(%v,%v,%v)", call.To.Hex(), call.Value, hexutil.Encode(call.Data)))
+	}
+	return BuildTxInputData("execute((address,uint256,bytes)[],bytes)",
+		[]string{"[" + strings.Join(elems, ",") + "]", hexutil.Encode(signature)})
+}
+
+// SponsorNonce returns nonce() of the delegated account, which is 0 if account is not delegated yet.
+func SponsorNonce(ctx context.Context, client *ethclient.Client, account common.Address) (*big.Int, error) {
+	code, err := client.CodeAt(ctx, account, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return big.NewInt(0), nil
+	}
+	values, err := CallAndUnpack(ctx, client, account, "function nonce() returns (uint256)", nil)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// GelatoRelayUrl is the base url of Gelato Relay.
+// See: https://docs.gelato.network/web3-services/relay
+const GelatoRelayUrl = "https://api.gelato.digital"
+
+// Gelato task states, see: https://docs.gelato.network/web3-services/relay/tracking-your-relay-request
+const (
+	GelatoTaskExecSuccess  = "ExecSuccess"
+	GelatoTaskExecReverted = "ExecReverted"
+	GelatoTaskCancelled    = "Cancelled"
+)
+
+// gelatoRequest sends json request to Gelato Relay, and decodes json response into v.
+func gelatoRequest(ctx context.Context, method string, url string, body any, v any) error {
+	var reader io.Reader
+	if body != nil {
+		content, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(content)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%v %v returns %v: %s", method, url, resp.Status, respBody)
+	}
+	if err := json.Unmarshal(respBody, v); err != nil {
+		return fmt.Errorf("parse gelato response fail: %w", err)
+	}
+	return nil
+}
+
+// GelatoSponsoredCall submits a call of target to Gelato Relay, the gas is paid by Gelato 1Balance of apiKey. It
+// returns the task id.
+func GelatoSponsoredCall(ctx context.Context, baseUrl string, apiKey string, chainID *big.Int, target common.Address, data []byte) (string, error) {
+	body := map[string]any{
+		"chainId":       chainID.String(),
+		"target":        target.Hex(),
+		"data":          hexutil.Encode(data),
+		"sponsorApiKey": apiKey,
+	}
+	var result struct {
+		TaskId string `json:"taskId"`
+	}
+	if err := gelatoRequest(ctx, http.MethodPost, baseUrl+"/relays/v2/sponsored-call", body, &result); err != nil {
+		return "", err
+	}
+	if result.TaskId == "" {
+		return "", fmt.Errorf("no task id is returned by gelato")
+	}
+	return result.TaskId, nil
+}
+
+// GelatoTask is the status of Gelato Relay task.
+type GelatoTask struct {
+	TaskState        string       `json:"taskState"`
+	TransactionHash  *common.Hash `json:"transactionHash"`
+	LastCheckMessage string       `json:"lastCheckMessage"`
+}
+
+// GelatoTaskStatus returns status of Gelato Relay task.
+func GelatoTaskStatus(ctx context.Context, baseUrl string, taskId string) (*GelatoTask, error) {
+	var result struct {
+		Task GelatoTask `json:"task"`
+	}
+	if err := gelatoRequest(ctx, http.MethodGet, baseUrl+"/tasks/status/"+taskId, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result.Task, nil
+}
+
+// WaitGelatoTask polls status of Gelato Relay task until it's executed, reverted or cancelled, and returns the tx.
+func WaitGelatoTask(ctx context.Context, baseUrl string, taskId string, pollInterval time.Duration) (common.Hash, error) {
+	for {
+		task, err := GelatoTaskStatus(ctx, baseUrl, taskId)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		switch task.TaskState {
+		case GelatoTaskExecSuccess:
+			if task.TransactionHash == nil {
+				return common.Hash{}, fmt.Errorf("gelato task %v succeeded without transaction hash", taskId)
+			}
+			return *task.TransactionHash, nil
+		case GelatoTaskExecReverted, GelatoTaskCancelled:
+			return common.Hash{}, fmt.Errorf("gelato task %v is %v: %v", taskId, task.TaskState, task.LastCheckMessage)
+		}
+		select {
+		case <-ctx.Done():
+			return common.Hash{}, ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// SponsorRecord is a line of sponsor ledger, i.e. a tx whose gas is paid for beneficiary.
+type SponsorRecord struct {
+	Time        time.Time      `json:"time"`
+	ChainID     uint64         `json:"chain_id"`
+	Beneficiary common.Address `json:"beneficiary"`
+	Relayer     string         `json:"relayer"` // sponsor address, or "gelato"
+	TxHash      common.Hash    `json:"tx_hash"`
+	GasUsed     uint64         `json:"gas_used"`
+	Fee         *hexutil.Big   `json:"fee_wei"` // gas used * effective gas price
+}
+
+// AppendSponsorRecord appends record to the ledger file (json lines).
+func AppendSponsorRecord(file string, record SponsorRecord) error {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(content, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadSponsorRecords reads records of the ledger file, empty result is returned if file does not exist.
+func ReadSponsorRecords(file string) ([]SponsorRecord, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []SponsorRecord
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record SponsorRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %v of %v is invalid: %w", line, file, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// SponsorSummary is the sponsored gas of a beneficiary.
+type SponsorSummary struct {
+	Beneficiary common.Address
+	Txs         int
+	GasUsed     uint64
+	Fee         *big.Int
+}
+
+// SummarizeSponsorRecords sums records of chainID (0 means all chains) by beneficiary, sorted by fee in descending order.
+func SummarizeSponsorRecords(records []SponsorRecord, chainID uint64) []SponsorSummary {
+	var summaries = make(map[common.Address]*SponsorSummary)
+	for _, record := range records {
+		if chainID != 0 && record.ChainID != chainID {
+			continue
+		}
+		summary, ok := summaries[record.Beneficiary]
+		if !ok {
+			summary = &SponsorSummary{Beneficiary: record.Beneficiary, Fee: new(big.Int)}
+			summaries[record.Beneficiary] = summary
+		}
+		summary.Txs++
+		summary.GasUsed += record.GasUsed
+		if record.Fee != nil {
+			summary.Fee.Add(summary.Fee, record.Fee.ToInt())
+		}
+	}
+	var result []SponsorSummary
+	for _, summary := range summaries {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if c := result[i].Fee.Cmp(result[j].Fee); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(result[i].Beneficiary[:], result[j].Beneficiary[:]) < 0
+	})
+	return result
+}
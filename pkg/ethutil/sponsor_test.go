@@ -0,0 +1,82 @@
+package ethutil
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSponsorCallsDigest(t *testing.T) {
+	to := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	calls := []SponsorCall{
+		{To: to, Value: big.NewInt(1), Data: nil},
+		{To: to, Value: big.NewInt(0), Data: common.FromHex("0xa9059cbb")},
+	}
+	// keccak256(abi.encodePacked(uint256(5), to, uint256(1), "", to, uint256(0), hex"a9059cbb"))
+	expected := crypto.Keccak256Hash(
+		common.LeftPadBytes([]byte{5}, 32), to.Bytes(), common.LeftPadBytes([]byte{1}, 32),
+		to.Bytes(), make([]byte, 32), common.FromHex("0xa9059cbb"))
+	if got := SponsorCallsDigest(big.NewInt(5), calls); got != expected {
+		t.Fatalf("expected: %v, got: %v", expected.Hex(), got.Hex())
+	}
+
+	privateKey, _ := crypto.GenerateKey()
+	signature, err := SponsorSignCalls(big.NewInt(5), calls, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := SponsorCallsSigner(big.NewInt(5), calls, signature)
+	if err != nil || signer != crypto.PubkeyToAddress(privateKey.PublicKey) {
+		t.Fatalf("unexpected signer %v (%v)", signer.Hex(), err)
+	}
+	if signer, _ := SponsorCallsSigner(big.NewInt(6), calls, signature); signer == crypto.PubkeyToAddress(privateKey.PublicKey) {
+		t.Fatalf("signature is valid for another nonce")
+	}
+}
+
+func TestSummarizeSponsorRecords(t *testing.T) {
+	alice := common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	bob := common.HexToAddress("0xB2aC853cF815B47903bc19BF4860540306F4f944")
+	file := filepath.Join(t.TempDir(), "ledger.jsonl")
+	for _, record := range []SponsorRecord{
+		{ChainID: 1, Beneficiary: alice, GasUsed: 50000, Fee: (*hexutil.Big)(big.NewInt(100))},
+		{ChainID: 1, Beneficiary: bob, GasUsed: 21000, Fee: (*hexutil.Big)(big.NewInt(300))},
+		{ChainID: 1, Beneficiary: alice, GasUsed: 30000, Fee: (*hexutil.Big)(big.NewInt(250))},
+		{ChainID: 10, Beneficiary: bob, GasUsed: 21000, Fee: (*hexutil.Big)(big.NewInt(1000))},
+	} {
+		record.Time = time.Date(2023, 6, 1, 10, 20, 30, 0, time.UTC)
+		if err := AppendSponsorRecord(file, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	records, err := ReadSponsorRecords(file)
+	if err != nil || len(records) != 4 {
+		t.Fatalf("expected 4 records, got %v (%v)", len(records), err)
+	}
+
+	tests := []struct {
+		chainID  uint64
+		expected []SponsorSummary
+	}{
+		{1, []SponsorSummary{{alice, 2, 80000, big.NewInt(350)}, {bob, 1, 21000, big.NewInt(300)}}},
+		{0, []SponsorSummary{{bob, 2, 42000, big.NewInt(1300)}, {alice, 2, 80000, big.NewInt(350)}}},
+		{5, nil},
+	}
+	for i, test := range tests {
+		got := SummarizeSponsorRecords(records, test.chainID)
+		if len(got) != len(test.expected) {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expected, got)
+		}
+		for j := range got {
+			e := test.expected[j]
+			if got[j].Beneficiary != e.Beneficiary || got[j].Txs != e.Txs || got[j].GasUsed != e.GasUsed || got[j].Fee.Cmp(e.Fee) != 0 {
+				t.Fatalf("test %d: expected: %v, got: %v", i, e, got[j])
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// EIP-1967 proxy storage slots, see https://eips.ethereum.org/EIPS/eip-1967
+var (
+	Eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc") // bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1)
+	Eip1967AdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103") // bytes32(uint256(keccak256('eip1967.proxy.admin')) - 1)
+	Eip1967BeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50") // bytes32(uint256(keccak256('eip1967.proxy.beacon')) - 1)
+)
+
+// AccountState is the state of an account at a block.
+type AccountState struct {
+	Address  common.Address
+	Balance  *big.Int
+	Nonce    uint64
+	CodeSize int
+	CodeHash common.Hash // keccak256 of code, it's keccak256 of empty bytes for EOA
+}
+
+// IsContract returns true if there is code deployed at the account.
+func (s *AccountState) IsContract() bool {
+	return s.CodeSize > 0
+}
+
+// GetAccountState returns the state of address at the given block, nil blockNumber means latest block.
+func GetAccountState(ctx context.Context, client *ethclient.Client, address common.Address, blockNumber *big.Int) (*AccountState, error) {
+	balance, err := client.BalanceAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("BalanceAt fail: %w", err)
+	}
+	nonce, err := client.NonceAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("NonceAt fail: %w", err)
+	}
+	code, err := client.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("CodeAt fail: %w", err)
+	}
+
+	return &AccountState{
+		Address:  address,
+		Balance:  balance,
+		Nonce:    nonce,
+		CodeSize: len(code),
+		CodeHash: crypto.Keccak256Hash(code),
+	}, nil
+}
+
+// GetAddressInSlot returns the address stored in the lower 20 bytes of storage slot, e.g. Eip1967ImplementationSlot.
+func GetAddressInSlot(ctx context.Context, client *ethclient.Client, address common.Address, slot common.Hash, blockNumber *big.Int) (common.Address, error) {
+	value, err := client.StorageAt(ctx, address, slot, blockNumber)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("StorageAt fail: %w", err)
+	}
+	return common.BytesToAddress(value), nil
+}
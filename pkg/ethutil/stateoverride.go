@@ -0,0 +1,173 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OverrideAccount is the overridden fields of an account in state override set of eth_call. State replaces the
+// whole storage of account, StateDiff only patches the given slots.
+type OverrideAccount struct {
+	Nonce     *hexutil.Uint64              `json:"nonce,omitempty"`
+	Code      *hexutil.Bytes               `json:"code,omitempty"`
+	Balance   *hexutil.Big                 `json:"balance,omitempty"`
+	State     *map[common.Hash]common.Hash `json:"state,omitempty"` // pointer as empty State clears storage
+	StateDiff *map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverride is the state override set of eth_call, supported by geth, erigon, anvil etc.
+type StateOverride map[common.Address]OverrideAccount
+
+// overrideAccountInput is the user friendly form of OverrideAccount, numbers are decimal or hex strings (or JSON
+// numbers), storage slots and values can be shorter than 32 bytes.
+type overrideAccountInput struct {
+	Nonce     json.RawMessage   `json:"nonce"`
+	Code      string            `json:"code"`
+	Balance   json.RawMessage   `json:"balance"`
+	State     map[string]string `json:"state"`
+	StateDiff map[string]string `json:"stateDiff"`
+}
+
+// ParseStateOverride parses state override set in JSON, e.g.
+//
+//	{"0x...": {"balance": "1000000000000000000", "nonce": 5, "code": "0x6080...", "stateDiff": {"0x0": "0x1"}}}
+//
+// balance is in wei, numbers can be decimal or hex, storage slots and values are left padded to 32 bytes.
+func ParseStateOverride(content []byte) (StateOverride, error) {
+	var input map[string]overrideAccountInput
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		return nil, fmt.Errorf("invalid state override: %w", err)
+	}
+
+	var override = make(StateOverride)
+	for address, account := range input {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("invalid address %v in state override", address)
+		}
+		var result OverrideAccount
+		if account.Nonce != nil {
+			nonce, err := parseOverrideNumber(account.Nonce)
+			if err != nil || !nonce.IsUint64() {
+				return nil, fmt.Errorf("invalid nonce %s of %v", account.Nonce, address)
+			}
+			result.Nonce = (*hexutil.Uint64)(new(uint64))
+			*result.Nonce = hexutil.Uint64(nonce.Uint64())
+		}
+		if account.Balance != nil {
+			balance, err := parseOverrideNumber(account.Balance)
+			if err != nil {
+				return nil, fmt.Errorf("invalid balance %s of %v", account.Balance, address)
+			}
+			result.Balance = (*hexutil.Big)(balance)
+		}
+		if account.Code != "" {
+			code, err := hexutil.Decode(account.Code)
+			if err != nil {
+				return nil, fmt.Errorf("invalid code of %v: %w", address, err)
+			}
+			result.Code = (*hexutil.Bytes)(&code)
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return nil, fmt.Errorf("state and stateDiff of %v can not be specified at the same time", address)
+		}
+		var err error
+		if result.State, err = parseOverrideStorage(account.State); err != nil {
+			return nil, fmt.Errorf("invalid state of %v: %w", address, err)
+		}
+		if result.StateDiff, err = parseOverrideStorage(account.StateDiff); err != nil {
+			return nil, fmt.Errorf("invalid stateDiff of %v: %w", address, err)
+		}
+		override[common.HexToAddress(address)] = result
+	}
+	return override, nil
+}
+
+// parseOverrideNumber parses JSON number, or string of decimal or hex number.
+func parseOverrideNumber(raw json.RawMessage) (*big.Int, error) {
+	s := strings.Trim(string(raw), `"`)
+	var n *big.Int
+	var ok bool
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		n, ok = new(big.Int).SetString(s[2:], 16)
+	} else {
+		n, ok = new(big.Int).SetString(s, 10)
+	}
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("invalid number %v", s)
+	}
+	return n, nil
+}
+
+func parseOverrideStorage(storage map[string]string) (*map[common.Hash]common.Hash, error) {
+	if storage == nil {
+		return nil, nil
+	}
+	var result = make(map[common.Hash]common.Hash)
+	for slot, value := range storage {
+		slotBytes, err := decodeShortHex(slot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid slot %v", slot)
+		}
+		valueBytes, err := decodeShortHex(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %v of slot %v", value, slot)
+		}
+		result[common.BytesToHash(slotBytes)] = common.BytesToHash(valueBytes)
+	}
+	return &result, nil
+}
+
+// decodeShortHex decodes 0x prefixed hex string of at most 32 bytes, odd length (e.g. "0x0") is allowed.
+func decodeShortHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, errors.New("missing 0x prefix")
+	}
+	s = s[2:]
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) > 32 {
+		return nil, errors.New("longer than 32 bytes")
+	}
+	return decoded, nil
+}
+
+// CallWithOverride executes call by eth_call on state of block (nil means latest block) patched by override. The
+// revert reason is decoded into error if call reverts.
+func CallWithOverride(ctx context.Context, rpcClient *rpc.Client, args CallArgs, block *big.Int, override StateOverride) ([]byte, error) {
+	var result hexutil.Bytes
+	var params = []any{args, blockTag(block)}
+	if len(override) > 0 {
+		params = append(params, override)
+	}
+	if err := rpcClient.CallContext(ctx, &result, "eth_call", params...); err != nil {
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) {
+			if data, ok := dataErr.ErrorData().(string); ok {
+				if reason, unpackErr := abi.UnpackRevert(common.FromHex(data)); unpackErr == nil {
+					return nil, fmt.Errorf("%w: %v", err, reason)
+				}
+				return nil, fmt.Errorf("%w: revert data %v", err, data)
+			}
+		}
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseStateOverride(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`{"0x24f8209EC5f56A07C94e834627F0651c19ACa0ac": {"balance": "1000000000000000000", "nonce": 5}}`,
+			`{"0x24f8209ec5f56a07c94e834627f0651c19aca0ac":{"nonce":"0x5","balance":"0xde0b6b3a7640000"}}`,
+		},
+		{
+			`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"balance": "0x10", "nonce": "0x1", "code": "0x6000", "stateDiff": {"0x0": "0x01"}}}`,
+			`{"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb":{"nonce":"0x1","code":"0x6000","balance":"0x10","stateDiff":{"0x0000000000000000000000000000000000000000000000000000000000000000":"0x0000000000000000000000000000000000000000000000000000000000000001"}}}`,
+		},
+		{
+			`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"state": {}}}`,
+			`{"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb":{"state":{}}}`,
+		},
+	}
+	for i, tt := range tests {
+		override, err := ParseStateOverride([]byte(tt.input))
+		if err != nil {
+			t.Fatalf("test %d: ParseStateOverride fail: %v", i, err)
+		}
+		got, _ := json.Marshal(override)
+		if string(got) != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %s", i, tt.expected, got)
+		}
+	}
+
+	for i, input := range []string{
+		`{"0x1234": {"balance": "1"}}`,
+		`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"balance": "-1"}}`,
+		`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"balance": "1 ether"}}`,
+		`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"code": "6000"}}`,
+		`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"state": {"0x0": "0x1"}, "stateDiff": {"0x0": "0x1"}}}`,
+		`{"0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb": {"storage": {"0x0": "0x1"}}}`,
+	} {
+		if _, err := ParseStateOverride([]byte(input)); err == nil {
+			t.Fatalf("test %d: expected error for %v, got nil", i, input)
+		}
+	}
+}
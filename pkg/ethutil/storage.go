@@ -0,0 +1,142 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MappingSlot returns the slot of mapping value, key is the encoded key (left padded to 32 bytes for value types,
+// raw bytes for string and bytes), slot is the slot of mapping itself.
+func MappingSlot(key []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(key, slot.Bytes())
+}
+
+// ArraySlot returns the slot of element index of dynamic array at slot, elementSlots is the number of slots
+// occupied by each element (1 for value types). Elements smaller than 16 bytes are packed, so this is the slot
+// of element index only if elementSlots >= 1 and no packing occurs.
+func ArraySlot(slot common.Hash, index *big.Int, elementSlots int64) common.Hash {
+	start := new(big.Int).SetBytes(crypto.Keccak256(slot.Bytes()))
+	start.Add(start, new(big.Int).Mul(index, big.NewInt(elementSlots)))
+	return common.BigToHash(math.U256(start))
+}
+
+// StorageLayout is the storage layout generated by solc (--storage-layout), see
+// https://docs.soliditylang.org/en/latest/internals/layout_in_storage.html#json-output
+type StorageLayout struct {
+	Storage []StorageLayoutEntry   `json:"storage"`
+	Types   map[string]StorageType `json:"types"`
+}
+
+// StorageLayoutEntry is a state variable in StorageLayout.
+type StorageLayoutEntry struct {
+	Label  string `json:"label"`
+	Offset int    `json:"offset"` // offset in bytes within the slot
+	Slot   string `json:"slot"`   // decimal string
+	Type   string `json:"type"`   // key of StorageLayout.Types
+}
+
+// StorageType is a type in StorageLayout.
+type StorageType struct {
+	Encoding      string `json:"encoding"` // inplace, mapping, dynamic_array or bytes
+	Label         string `json:"label"`    // e.g. uint256, address, mapping(address => uint256)
+	NumberOfBytes string `json:"numberOfBytes"`
+	Key           string `json:"key,omitempty"`   // key type of mapping
+	Value         string `json:"value,omitempty"` // value type of mapping
+	Base          string `json:"base,omitempty"`  // element type of array
+}
+
+// ParseStorageLayout parses storage layout json, both the storageLayout object and the whole contract output
+// containing a "storageLayout" field are accepted.
+func ParseStorageLayout(content []byte) (*StorageLayout, error) {
+	var wrapper struct {
+		StorageLayout *StorageLayout `json:"storageLayout"`
+	}
+	if err := json.Unmarshal(content, &wrapper); err == nil && wrapper.StorageLayout != nil {
+		return wrapper.StorageLayout, nil
+	}
+
+	var layout StorageLayout
+	if err := json.Unmarshal(content, &layout); err != nil {
+		return nil, fmt.Errorf("parse storage layout fail: %w", err)
+	}
+	if len(layout.Storage) == 0 {
+		return nil, fmt.Errorf("no state variable found in storage layout")
+	}
+	return &layout, nil
+}
+
+// Lookup returns the state variable label.
+func (l *StorageLayout) Lookup(label string) (*StorageLayoutEntry, error) {
+	for i := range l.Storage {
+		if l.Storage[i].Label == label {
+			return &l.Storage[i], nil
+		}
+	}
+	return nil, fmt.Errorf("state variable %v is not found in storage layout", label)
+}
+
+// SlotHash returns slot of entry as hash.
+func (e *StorageLayoutEntry) SlotHash() (common.Hash, error) {
+	slot, ok := new(big.Int).SetString(e.Slot, 10)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("invalid slot %v of %v", e.Slot, e.Label)
+	}
+	return common.BigToHash(slot), nil
+}
+
+// DecodeStorageValue decodes the value of inplace type typ at offset of slot value, and the short (less than 32
+// bytes) string or bytes stored in slot value.
+func DecodeStorageValue(value common.Hash, offset int, typ StorageType) (string, error) {
+	switch typ.Encoding {
+	case "bytes":
+		// short values are stored with length*2 in the lowest byte, long values store length*2+1 in slot
+		if value[31]&1 == 1 {
+			length := new(big.Int).Rsh(value.Big(), 1)
+			return "", fmt.Errorf("%v of %v bytes is stored out of slot, read slot keccak256(slot)", typ.Label, length)
+		}
+		data := value[:value[31]/2]
+		if typ.Label == "string" {
+			return string(data), nil
+		}
+		return hexutil.Encode(data), nil
+	case "inplace":
+	default:
+		return "", fmt.Errorf("can not decode %v of encoding %v from a single slot", typ.Label, typ.Encoding)
+	}
+
+	size, err := strconv.Atoi(typ.NumberOfBytes)
+	if err != nil || size <= 0 || size > 32 {
+		return "", fmt.Errorf("invalid numberOfBytes %v of %v", typ.NumberOfBytes, typ.Label)
+	}
+	if offset < 0 || offset+size > 32 {
+		return "", fmt.Errorf("invalid offset %v of %v", offset, typ.Label)
+	}
+	// values are right aligned, i.e. offset counts from the lowest byte
+	data := value[32-offset-size : 32-offset]
+
+	label := typ.Label
+	switch {
+	case label == "bool":
+		return strconv.FormatBool(data[len(data)-1] != 0), nil
+	case label == "address" || label == "address payable" || strings.HasPrefix(label, "contract "):
+		return common.BytesToAddress(data).Hex(), nil
+	case strings.HasPrefix(label, "uint") || strings.HasPrefix(label, "enum "):
+		return new(big.Int).SetBytes(data).String(), nil
+	case strings.HasPrefix(label, "int"):
+		v := new(big.Int).SetBytes(data)
+		if data[0]&0x80 != 0 { // negative, two's complement
+			v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(size*8)))
+		}
+		return v.String(), nil
+	default: // bytesN, structs packed in one slot, etc
+		return hexutil.Encode(data), nil
+	}
+}
@@ -0,0 +1,89 @@
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestArraySlot(t *testing.T) {
+	tests := []struct {
+		slot         int64
+		index        int64
+		elementSlots int64
+		want         string
+	}{
+		{
+			slot:         0,
+			index:        0,
+			elementSlots: 1,
+			want:         "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563", // keccak256(uint256(0))
+		},
+		{
+			slot:         0,
+			index:        2,
+			elementSlots: 2,
+			want:         "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e567",
+		},
+	}
+
+	for i, tt := range tests {
+		output := ArraySlot(common.BigToHash(big.NewInt(tt.slot)), big.NewInt(tt.index), tt.elementSlots)
+		if output.Hex() != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output.Hex())
+		}
+	}
+}
+
+func TestDecodeStorageValue(t *testing.T) {
+	tests := []struct {
+		value  string
+		offset int
+		typ    StorageType
+		want   string
+	}{
+		{
+			value:  "0x0000000000000000000000000000000000000000000000000000000000000064",
+			offset: 0,
+			typ:    StorageType{Encoding: "inplace", Label: "uint256", NumberOfBytes: "32"},
+			want:   "100",
+		},
+		{
+			// bool at offset 20 packed with an address
+			value:  "0x00000000000000000000018f36975cdea2e6e64f85719788c8efbbe89dfbbb",
+			offset: 20,
+			typ:    StorageType{Encoding: "inplace", Label: "bool", NumberOfBytes: "1"},
+			want:   "true",
+		},
+		{
+			value:  "0x00000000000000000000018f36975cdea2e6e64f85719788c8efbbe89dfbbb",
+			offset: 0,
+			typ:    StorageType{Encoding: "inplace", Label: "address", NumberOfBytes: "20"},
+			want:   "0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb",
+		},
+		{
+			value:  "0x00000000000000000000000000000000000000000000000000000000000000ff",
+			offset: 0,
+			typ:    StorageType{Encoding: "inplace", Label: "int8", NumberOfBytes: "1"},
+			want:   "-1",
+		},
+		{
+			// short string "abc", the lowest byte is length*2
+			value:  "0x6162630000000000000000000000000000000000000000000000000000000006",
+			offset: 0,
+			typ:    StorageType{Encoding: "bytes", Label: "string", NumberOfBytes: "32"},
+			want:   "abc",
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := DecodeStorageValue(common.HexToHash(tt.value), tt.offset, tt.typ)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		if output != tt.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.want, output)
+		}
+	}
+}
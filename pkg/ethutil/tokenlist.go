@@ -0,0 +1,102 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DefaultTokenListUrl is the url of the default token list of Uniswap, which covers popular tokens of many chains.
+const DefaultTokenListUrl = "https://tokens.uniswap.org"
+
+// TokenListToken is a token in token list.
+type TokenListToken struct {
+	ChainId  uint64         `json:"chainId"`
+	Address  common.Address `json:"address"`
+	Name     string         `json:"name"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+}
+
+// TokenList is a token list in the format of https://tokenlists.org
+type TokenList struct {
+	Name   string           `json:"name"`
+	Tokens []TokenListToken `json:"tokens"`
+}
+
+// LoadTokenList loads token list from source, which is a http(s) url or a file.
+func LoadTokenList(ctx context.Context, source string) (*TokenList, error) {
+	var content []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		content, err = httpGet(ctx, source)
+	} else {
+		content, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list TokenList
+	if err := json.Unmarshal(content, &list); err != nil {
+		return nil, fmt.Errorf("parse token list %v fail: %w", source, err)
+	}
+	return &list, nil
+}
+
+// TokensOfChain returns tokens of chainId in list, duplicated addresses are removed.
+func (l *TokenList) TokensOfChain(chainId uint64) []TokenListToken {
+	var seen = make(map[common.Address]bool)
+	var tokens []TokenListToken
+	for _, token := range l.Tokens {
+		if token.ChainId != chainId || seen[token.Address] {
+			continue
+		}
+		seen[token.Address] = true
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// TokenBalance is the balance of a token.
+type TokenBalance struct {
+	Token   TokenListToken
+	Balance *big.Int
+}
+
+// balanceOfSelector is the selector of balanceOf(address)
+var balanceOfSelector = hexutil.MustDecode("0x70a08231")
+
+// TokenBalanceCalls returns the calls of balanceOf(owner) of tokens.
+func TokenBalanceCalls(owner common.Address, tokens []TokenListToken) []CallArgs {
+	var calls []CallArgs
+	for i := range tokens {
+		calls = append(calls, CallArgs{
+			To:   &tokens[i].Address,
+			Data: append(append([]byte{}, balanceOfSelector...), common.LeftPadBytes(owner.Bytes(), 32)...),
+		})
+	}
+	return calls
+}
+
+// NonZeroTokenBalances decodes results of TokenBalanceCalls, and returns non-zero balances. The tokens whose calls
+// failed or returned malformed data (e.g. not a contract) are skipped.
+func NonZeroTokenBalances(tokens []TokenListToken, results []MulticallResult) []TokenBalance {
+	var balances []TokenBalance
+	for i, result := range results {
+		if i >= len(tokens) || !result.Success || len(result.ReturnData) != 32 {
+			continue
+		}
+		balance := new(big.Int).SetBytes(result.ReturnData)
+		if balance.Sign() == 0 {
+			continue
+		}
+		balances = append(balances, TokenBalance{Token: tokens[i], Balance: balance})
+	}
+	return balances
+}
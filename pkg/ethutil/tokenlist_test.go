@@ -0,0 +1,127 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+func TestTokensOfChain(t *testing.T) {
+	list := TokenList{Tokens: []TokenListToken{
+		{ChainId: 1, Address: common.HexToAddress("0x01"), Symbol: "A"},
+		{ChainId: 56, Address: common.HexToAddress("0x02"), Symbol: "B"},
+		{ChainId: 1, Address: common.HexToAddress("0x03"), Symbol: "C"},
+		{ChainId: 1, Address: common.HexToAddress("0x01"), Symbol: "A2"}, // duplicated
+	}}
+	tests := []struct {
+		chainId  uint64
+		expected []string
+	}{
+		{1, []string{"A", "C"}},
+		{56, []string{"B"}},
+		{5, nil},
+	}
+	for _, test := range tests {
+		var symbols []string
+		for _, token := range list.TokensOfChain(test.chainId) {
+			symbols = append(symbols, token.Symbol)
+		}
+		if fmt.Sprint(symbols) != fmt.Sprint(test.expected) {
+			t.Errorf("chain %v: expected %v, got %v", test.chainId, test.expected, symbols)
+		}
+	}
+}
+
+func TestNonZeroTokenBalances(t *testing.T) {
+	tokens := []TokenListToken{{Symbol: "A"}, {Symbol: "B"}, {Symbol: "C"}, {Symbol: "D"}}
+	results := []MulticallResult{
+		{Success: true, ReturnData: common.LeftPadBytes([]byte{7}, 32)},
+		{Success: true, ReturnData: make([]byte, 32)},                    // zero
+		{Success: false, ReturnData: common.LeftPadBytes([]byte{8}, 32)}, // reverted
+		{Success: true, ReturnData: nil},                                 // not a contract
+	}
+	balances := NonZeroTokenBalances(tokens, results)
+	if len(balances) != 1 || balances[0].Token.Symbol != "A" || balances[0].Balance.Int64() != 7 {
+		t.Errorf("unexpected balances %+v", balances)
+	}
+}
+
+// newMulticallServer returns a server of Multicall3 tryAggregate, on which the call of target succeeds if the last
+// byte of target is odd and returns the last byte as uint256.
+func newMulticallServer(t *testing.T) *httptest.Server {
+	parsed, err := abi.JSON(strings.NewReader(multicall3Abi))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := parsed.Methods["tryAggregate"]
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage `json:"id"`
+			Params []json.RawMessage
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var call CallArgs
+		_ = json.Unmarshal(req.Params[0], &call)
+		values, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			t.Error(err)
+		}
+		var input struct {
+			RequireSuccess bool
+			Calls          []struct {
+				Target   common.Address
+				CallData []byte
+			}
+		}
+		if err := method.Inputs.Copy(&input, values); err != nil {
+			t.Error(err)
+		}
+		var results []MulticallResult
+		for _, c := range input.Calls {
+			last := c.Target[19]
+			results = append(results, MulticallResult{Success: last%2 == 1, ReturnData: common.LeftPadBytes([]byte{last}, 32)})
+		}
+		output, err := method.Outputs.Pack(results)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%s"}`, req.Id, hexutil.Encode(output))
+	}))
+}
+
+func TestMulticallTryAggregate(t *testing.T) {
+	server := newMulticallServer(t)
+	defer server.Close()
+	client, err := ethclient.Dial(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tokens []TokenListToken
+	for i := 1; i <= 5; i++ {
+		tokens = append(tokens, TokenListToken{Address: common.BigToAddress(big.NewInt(int64(i)))})
+	}
+	results, err := MulticallTryAggregate(context.Background(), client, TokenBalanceCalls(common.HexToAddress("0xff"), tokens), nil, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balances := NonZeroTokenBalances(tokens, results)
+	var got []int64
+	for _, balance := range balances {
+		got = append(got, balance.Balance.Int64())
+	}
+	if fmt.Sprint(got) != "[1 3 5]" {
+		t.Errorf("expected balances [1 3 5], got %v", got)
+	}
+}
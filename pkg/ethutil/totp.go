@@ -0,0 +1,95 @@
+package ethutil
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTPPeriod is the time step of TOTP code, same as Google Authenticator.
+const TOTPPeriod = 30 * time.Second
+
+// TOTPDigits is the number of digits of TOTP code, same as Google Authenticator.
+const TOTPDigits = 6
+
+// totpEncoding is base32 without padding, the encoding of secret in authenticator apps.
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret generates random 20 bytes secret, returns it in base32 (the form entered in authenticator apps).
+func NewTOTPSecret() (string, error) {
+	var secret = make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// decodeTOTPSecret decodes base32 secret, spaces and padding are ignored, case insensitive.
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	secret = strings.ToUpper(strings.TrimRight(strings.ReplaceAll(secret, " ", ""), "="))
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base32 TOTP secret: %w", err)
+	}
+	return key, nil
+}
+
+// hotp returns HOTP code of counter, see https://www.rfc-editor.org/rfc/rfc4226
+func hotp(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	var mod uint32 = 1
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, code%mod)
+}
+
+// TOTPCode returns TOTP code of base32 secret at t, see https://www.rfc-editor.org/rfc/rfc6238
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(t.Unix())/uint64(TOTPPeriod/time.Second), TOTPDigits), nil
+}
+
+// VerifyTOTP reports whether code is TOTP code of secret at t, codes of skew periods before and after t are also
+// accepted to tolerate clock drift.
+func VerifyTOTP(secret string, code string, t time.Time, skew int) (bool, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return false, err
+	}
+	code = strings.TrimSpace(code)
+	counter := int64(t.Unix()) / int64(TOTPPeriod/time.Second)
+	for i := -skew; i <= skew; i++ {
+		if counter+int64(i) < 0 {
+			continue
+		}
+		if hmac.Equal([]byte(hotp(key, uint64(counter+int64(i)), TOTPDigits)), []byte(code)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// TOTPProvisioningURI returns otpauth uri of secret, which can be converted to QR code and scanned by authenticator apps.
+// See: https://github.com/google/google-authenticator/wiki/Key-Uri-Format
+func TOTPProvisioningURI(issuer string, account string, secret string) string {
+	var params = url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	return fmt.Sprintf("otpauth://totp/%v:%v?%v", url.PathEscape(issuer), url.PathEscape(account), params.Encode())
+}
@@ -0,0 +1,65 @@
+package ethutil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHotp(t *testing.T) {
+	// test vectors (SHA1) in https://www.rfc-editor.org/rfc/rfc6238#appendix-B
+	key := []byte("12345678901234567890")
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for i, test := range tests {
+		got := hotp(key, uint64(test.unix/30), 8)
+		if got != test.want {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.want, got)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	// base32 of "12345678901234567890"
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	now := time.Unix(1111111111, 0)
+	tests := []struct {
+		code  string
+		t     time.Time
+		valid bool
+	}{
+		{"050471", now, true},
+		{" 050471\n", now, true},
+		{"050471", now.Add(TOTPPeriod), true},
+		{"050471", now.Add(-TOTPPeriod), true},
+		{"050471", now.Add(3 * TOTPPeriod), false},
+		{"050472", now, false},
+		{"", now, false},
+	}
+
+	for i, test := range tests {
+		valid, err := VerifyTOTP(secret, test.code, test.t, 1)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if valid != test.valid {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.valid, valid)
+		}
+	}
+
+	if code, _ := TOTPCode(secret, now); code != "050471" {
+		t.Fatalf("expected: %v, got: %v", "050471", code)
+	}
+	if _, err := VerifyTOTP("not base32!", "050471", now, 1); err == nil {
+		t.Fatalf("expected: error, got: nil")
+	}
+}
@@ -0,0 +1,196 @@
+package ethutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// tracers supported by TraceTransaction and TraceCall
+const (
+	TracerCall       = "callTracer"
+	TracerPrestate   = "prestateTracer"
+	TracerStructLogs = "structLogs" // the default opcode logger, no tracer is specified in request
+)
+
+// CallFrame is a frame of call tree returned by callTracer.
+type CallFrame struct {
+	Type         string          `json:"type"` // CALL, STATICCALL, DELEGATECALL, CREATE etc.
+	From         common.Address  `json:"from"`
+	To           *common.Address `json:"to,omitempty"`
+	Value        *hexutil.Big    `json:"value,omitempty"`
+	Gas          hexutil.Uint64  `json:"gas"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	Input        hexutil.Bytes   `json:"input"`
+	Output       hexutil.Bytes   `json:"output,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	RevertReason string          `json:"revertReason,omitempty"`
+	Calls        []CallFrame     `json:"calls,omitempty"`
+}
+
+// StructLog is an opcode step returned by the default tracer.
+type StructLog struct {
+	Pc      uint64 `json:"pc"`
+	Op      string `json:"op"`
+	Gas     uint64 `json:"gas"`
+	GasCost uint64 `json:"gasCost"`
+	Depth   int    `json:"depth"`
+	Error   string `json:"error,omitempty"`
+}
+
+// StructLogsResult is the result of the default tracer.
+type StructLogsResult struct {
+	Gas         uint64      `json:"gas"`
+	Failed      bool        `json:"failed"`
+	ReturnValue string      `json:"returnValue"`
+	StructLogs  []StructLog `json:"structLogs"`
+}
+
+// traceConfig returns the config of debug_traceTransaction and debug_traceCall for tracer.
+func traceConfig(tracer string) (map[string]any, error) {
+	switch tracer {
+	case TracerCall, TracerPrestate:
+		return map[string]any{"tracer": tracer}, nil
+	case TracerStructLogs:
+		return map[string]any{"disableStorage": true, "enableMemory": false, "enableReturnData": false}, nil
+	default:
+		return nil, fmt.Errorf("unsupported tracer %v, expected %v, %v or %v", tracer, TracerCall, TracerPrestate, TracerStructLogs)
+	}
+}
+
+// TraceTransaction traces mined tx by debug_traceTransaction, the raw result of tracer is returned.
+func TraceTransaction(ctx context.Context, rpcClient *rpc.Client, txHash common.Hash, tracer string) (json.RawMessage, error) {
+	config, err := traceConfig(tracer)
+	if err != nil {
+		return nil, err
+	}
+	var result json.RawMessage
+	if err := rpcClient.CallContext(ctx, &result, "debug_traceTransaction", txHash, config); err != nil {
+		return nil, fmt.Errorf("debug_traceTransaction fail: %w", err)
+	}
+	return result, nil
+}
+
+// TraceCall traces call on state of block by debug_traceCall, nil block means latest block. The raw result of tracer
+// is returned.
+func TraceCall(ctx context.Context, rpcClient *rpc.Client, args CallArgs, block *big.Int, tracer string) (json.RawMessage, error) {
+	config, err := traceConfig(tracer)
+	if err != nil {
+		return nil, err
+	}
+	var result json.RawMessage
+	if err := rpcClient.CallContext(ctx, &result, "debug_traceCall", args, blockTag(block), config); err != nil {
+		return nil, fmt.Errorf("debug_traceCall fail: %w", err)
+	}
+	return result, nil
+}
+
+// panicSelector is the selector of Panic(uint256) raised by solidity
+var panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+
+// panicReasons are the panic codes of solidity, see https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler inserted panic",
+	0x01: "assert failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// Reverted returns true if frame fails.
+func (f *CallFrame) Reverted() bool {
+	return f.Error != ""
+}
+
+// Reason returns the revert reason of failed frame, which is decoded from Error(string) or Panic(uint256) in output
+// if it's not given by tracer.
+func (f *CallFrame) Reason() string {
+	if f.RevertReason != "" {
+		return f.RevertReason
+	}
+	if reason, err := abi.UnpackRevert(f.Output); err == nil {
+		return reason
+	}
+	if len(f.Output) == 36 && bytes.Equal(f.Output[:4], panicSelector) {
+		code := new(big.Int).SetBytes(f.Output[4:])
+		if reason, ok := panicReasons[code.Uint64()]; code.IsUint64() && ok {
+			return reason
+		}
+		return fmt.Sprintf("panic code %#x", code)
+	}
+	if len(f.Output) >= 4 {
+		return fmt.Sprintf("custom error %v", hexutil.Encode(f.Output[:4]))
+	}
+	return ""
+}
+
+// RenderCallTree writes call tree of frame, a line for each frame indented by depth. funcName returns the function
+// name of 4 bytes selector (e.g. "transfer(address,uint256)"), empty name means unknown. The deepest failed frames
+// are marked as revert points.
+func RenderCallTree(w io.Writer, frame *CallFrame, funcName func(selector []byte) string) {
+	renderCallFrame(w, frame, 0, funcName)
+}
+
+func renderCallFrame(w io.Writer, frame *CallFrame, depth int, funcName func(selector []byte) string) {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("  ", depth))
+	b.WriteString(frame.Type)
+	b.WriteString(" ")
+	b.WriteString(frame.From.Hex())
+	b.WriteString(" -> ")
+	if frame.To != nil {
+		b.WriteString(frame.To.Hex())
+	} else {
+		b.WriteString("<contract creation>")
+	}
+
+	if !strings.HasPrefix(frame.Type, "CREATE") && len(frame.Input) >= 4 {
+		name := funcName(frame.Input[:4])
+		if name == "" {
+			name = hexutil.Encode(frame.Input[:4])
+		}
+		b.WriteString(" ")
+		b.WriteString(name)
+	}
+	if frame.Value != nil && frame.Value.ToInt().Sign() > 0 {
+		fmt.Fprintf(&b, " value %v wei", frame.Value.ToInt())
+	}
+	fmt.Fprintf(&b, " gas used %d", uint64(frame.GasUsed))
+
+	if frame.Reverted() {
+		var revertPoint = true // no failed sub call, the frame itself reverts
+		for _, call := range frame.Calls {
+			if call.Reverted() {
+				revertPoint = false
+				break
+			}
+		}
+		if revertPoint {
+			b.WriteString(" <-- REVERT POINT")
+		}
+		fmt.Fprintf(&b, " [%v", frame.Error)
+		if reason := frame.Reason(); reason != "" {
+			fmt.Fprintf(&b, ": %v", reason)
+		}
+		b.WriteString("]")
+	}
+	fmt.Fprintln(w, b.String())
+
+	for i := range frame.Calls {
+		renderCallFrame(w, &frame.Calls[i], depth+1, funcName)
+	}
+}
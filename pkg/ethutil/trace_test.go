@@ -0,0 +1,57 @@
+package ethutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestRenderCallTree(t *testing.T) {
+	// callTracer result of a router call whose token transfer reverts with Error("insufficient balance")
+	var result = `{
+		"type": "CALL", "from": "0x24f8209ec5f56a07c94e834627f0651c19aca0ac", "to": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d",
+		"value": "0x0", "gas": "0x30d40", "gasUsed": "0x7530", "input": "0x38ed17390000", "error": "execution reverted",
+		"calls": [
+			{"type": "STATICCALL", "from": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", "to": "0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb",
+			 "gas": "0x1000", "gasUsed": "0x100", "input": "0x70a08231"},
+			{"type": "CALL", "from": "0x7a250d5630b4cf539739df2c5dacb4c659f2488d", "to": "0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb",
+			 "value": "0xa", "gas": "0x1000", "gasUsed": "0x200", "input": "0xa9059cbb", "error": "execution reverted",
+			 "output": "0x08c379a000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000014696e73756666696369656e742062616c616e6365000000000000000000000000"}
+		]
+	}`
+	var frame CallFrame
+	if err := json.Unmarshal([]byte(result), &frame); err != nil {
+		t.Fatalf("unmarshal fail: %v", err)
+	}
+
+	var names = map[string]string{"0xa9059cbb": "transfer(address,uint256)"}
+	var buf bytes.Buffer
+	RenderCallTree(&buf, &frame, func(selector []byte) string { return names[hexutil.Encode(selector)] })
+
+	expected := `CALL 0x24f8209EC5f56A07C94e834627F0651c19ACa0ac -> 0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D 0x38ed1739 gas used 30000 [execution reverted]
+  STATICCALL 0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D -> 0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb 0x70a08231 gas used 256
+  CALL 0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D -> 0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb transfer(address,uint256) value 10 wei gas used 512 <-- REVERT POINT [execution reverted: insufficient balance]
+`
+	if buf.String() != expected {
+		t.Fatalf("expected: %v, got: %v", expected, buf.String())
+	}
+}
+
+func TestCallFrameReason(t *testing.T) {
+	tests := []struct {
+		frame    CallFrame
+		expected string
+	}{
+		{CallFrame{RevertReason: "given by tracer"}, "given by tracer"},
+		{CallFrame{Output: hexutil.MustDecode("0x4e487b710000000000000000000000000000000000000000000000000000000000000011")}, "arithmetic underflow or overflow"},
+		{CallFrame{Output: hexutil.MustDecode("0xe450d38c0000")}, "custom error 0xe450d38c"},
+		{CallFrame{}, ""},
+	}
+	for i, tt := range tests {
+		if got := tt.frame.Reason(); got != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,390 @@
+package ethutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const TxTypeEip155 = "eip155"
+const TxTypeEip1559 = "eip1559"
+const TxTypeEip2930 = "eip2930"
+
+const GasUsedByTransferEth = 21000 // The gas used by any transfer is always 21000
+
+const defaultGasLimitForDeploy = 7000000
+const defaultGasLimitForContract = 900000
+
+// TxOptions controls how a tx is built, zero value fields are filled online.
+type TxOptions struct {
+	TxType   string  // TxTypeEip155 (default), TxTypeEip2930 or TxTypeEip1559
+	Nonce    *uint64 // nil means query pending nonce online
+	GasLimit uint64  // 0 means use a default gas limit according to the tx
+	ChainID  *big.Int
+
+	GasPrice             *big.Int // for TxTypeEip155 and TxTypeEip2930, nil means query suggested gas price online
+	MaxPriorityFeePerGas *big.Int // for TxTypeEip1559, nil means estimate online
+	MaxFeePerGas         *big.Int // for TxTypeEip1559, nil means estimate online
+
+	AccessList types.AccessList // for TxTypeEip2930 and TxTypeEip1559
+
+	GasOracle GasOracle // for TxTypeEip1559, nil means DefaultGasOracle without floor
+	Speed     string    // for TxTypeEip1559, SpeedSlow, SpeedAverage (default) or SpeedFast
+
+	Logf func(format string, v ...any) // receives messages of fee estimation and fallback, nil means silent
+}
+
+func (opts TxOptions) logf(format string, v ...any) {
+	if opts.Logf != nil {
+		opts.Logf(format, v...)
+	}
+}
+
+// BuildTx builds an unsigned tx, toAddress nil means contract creation.
+func BuildTx(ctx context.Context, client *ethclient.Client, fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte, opts TxOptions) (*types.Transaction, error) {
+	var nonce uint64
+	if opts.Nonce == nil {
+		var err error
+		nonce, err = client.PendingNonceAt(ctx, fromAddress)
+		if err != nil {
+			return nil, fmt.Errorf("PendingNonceAt fail: %w", err)
+		}
+	} else {
+		nonce = *opts.Nonce
+	}
+
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 { // if user not specified
+		gasLimit = GasUsedByTransferEth
+
+		if toAddress == nil {
+			gasLimit = defaultGasLimitForDeploy // the default gas limit for deploy contract
+		} else {
+			isContract, err := IsContractAddress(ctx, client, *toAddress)
+			if err != nil {
+				return nil, fmt.Errorf("IsContractAddress fail: %w", err)
+			}
+			if isContract { // GasUsedByTransferEth may be not enough if send to contract
+				gasLimit = defaultGasLimitForContract
+			}
+			if len(data) > 0 { // GasUsedByTransferEth may be not enough if with payload data
+				gasLimit = defaultGasLimitForContract
+			}
+		}
+	}
+
+	if opts.TxType == TxTypeEip1559 {
+		maxPriorityFeePerGas := opts.MaxPriorityFeePerGas
+		maxFeePerGas := opts.MaxFeePerGas
+		var legacyFallback bool
+		if maxPriorityFeePerGas == nil || maxFeePerGas == nil {
+			var gasOracle = opts.GasOracle
+			if gasOracle == nil {
+				gasOracle = &DefaultGasOracle{Client: client, Logf: opts.Logf}
+			}
+			estimate, err := gasOracle.EstimateFees(ctx)
+			if errors.Is(err, ErrEip1559NotSupported) {
+				opts.logf("%v, fall back to eip155 tx with gas price", err)
+				legacyFallback = true
+			} else if err != nil {
+				return nil, err
+			} else {
+				if maxPriorityFeePerGas == nil {
+					maxPriorityFeePerGas = estimate.Tip(opts.Speed)
+					opts.logf("max priority fee per gas %v wei, estimated by %v", maxPriorityFeePerGas, estimate.Source)
+				}
+				if maxFeePerGas == nil {
+					maxFeePerGas = new(big.Int).Add(estimate.BaseFee, maxPriorityFeePerGas)
+				}
+			}
+		}
+
+		if !legacyFallback {
+			return types.NewTx(&types.DynamicFeeTx{
+				ChainID:    opts.ChainID,
+				Nonce:      nonce,
+				To:         toAddress, // nil means contract creation
+				Value:      amount,
+				Gas:        gasLimit,
+				GasTipCap:  maxPriorityFeePerGas,
+				GasFeeCap:  maxFeePerGas,
+				Data:       data,
+				AccessList: opts.AccessList,
+			}), nil
+		}
+	}
+
+	gasPrice := opts.GasPrice
+	if opts.TxType == TxTypeEip1559 { // fall back to legacy tx
+		if opts.MaxFeePerGas == nil && opts.MaxPriorityFeePerGas != nil {
+			return nil, fmt.Errorf("max priority fee per gas is not supported by eip155 tx, specify max fee per gas or gas price instead")
+		}
+		if gasPrice == nil && opts.MaxFeePerGas != nil {
+			gasPrice = opts.MaxFeePerGas
+			opts.logf("use max fee per gas %v wei as gas price", gasPrice)
+		}
+	}
+	if gasPrice == nil {
+		var err error
+		gasPrice, err = client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("SuggestGasPrice fail: %w", err)
+		}
+	}
+
+	if opts.TxType == TxTypeEip2930 {
+		return types.NewTx(&types.AccessListTx{
+			ChainID:    opts.ChainID,
+			Nonce:      nonce,
+			To:         toAddress, // nil means contract creation
+			Value:      amount,
+			Gas:        gasLimit,
+			GasPrice:   gasPrice,
+			Data:       data,
+			AccessList: opts.AccessList,
+		}), nil
+	}
+
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       toAddress, // nil means contract creation
+		Value:    amount,
+		Gas:      gasLimit,
+		GasPrice: gasPrice,
+		Data:     data,
+	}), nil
+}
+
+// SignTx signs tx with privateKey, chainID nil means query it online.
+func SignTx(ctx context.Context, client *ethclient.Client, tx *types.Transaction, privateKey *ecdsa.PrivateKey, chainID *big.Int) (*types.Transaction, error) {
+	if chainID == nil {
+		var err error
+		chainID, err = client.ChainID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ChainID fail: %w", err)
+		}
+	}
+
+	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("SignTx fail: %w", err)
+	}
+	return signedTx, nil
+}
+
+// EstimateGas estimates gas used by the tx if it's sent by fromAddress.
+func EstimateGas(ctx context.Context, client *ethclient.Client, fromAddress common.Address, tx *types.Transaction) (uint64, error) {
+	msg := ethereum.CallMsg{
+		From:  fromAddress,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+
+		AccessList: tx.AccessList(),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		msg.GasFeeCap = tx.GasFeeCap()
+		msg.GasTipCap = tx.GasTipCap()
+	} else {
+		msg.GasPrice = tx.GasPrice()
+	}
+	return client.EstimateGas(ctx, msg)
+}
+
+// GenRawTx return raw tx, a hex string with 0x prefix
+func GenRawTx(signedTx *types.Transaction) (string, error) {
+	data, err := signedTx.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return hexutil.Encode(data), nil
+}
+
+// ParseRawTx decodes raw tx (hex string, the leading 0x is optional), both signed and unsigned tx are supported.
+func ParseRawTx(rawTx string) (*types.Transaction, error) {
+	if !strings.HasPrefix(rawTx, "0x") {
+		rawTx = "0x" + rawTx
+	}
+	data, err := hexutil.Decode(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid raw tx: %w", err)
+	}
+
+	var tx = new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("decode raw tx fail: %w", err)
+	}
+	return tx, nil
+}
+
+// SendRawTransaction broadcast signed tx and return tx returned by rpc node
+func SendRawTransaction(ctx context.Context, rpcClient *rpc.Client, signedTx *types.Transaction) (*common.Hash, error) {
+	rawTx, err := GenRawTx(signedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result hexutil.Bytes
+	err = rpcClient.CallContext(ctx, &result, "eth_sendRawTransaction", rawTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var hash = common.HexToHash(hexutil.Encode(result))
+	return &hash, nil
+}
+
+// WaitOptions controls how WaitReceipt waits for tx.
+type WaitOptions struct {
+	Confirmations uint64        // number of blocks since (and including) the block of tx, 0 is same as 1
+	PollInterval  time.Duration // interval of polling receipt, 0 means 5s
+	Timeout       time.Duration // max time of waiting, 0 means no timeout
+
+	Logf func(format string, v ...any) // receives messages of waiting progress and reorgs, nil means silent
+}
+
+func (opts WaitOptions) logf(format string, v ...any) {
+	if opts.Logf != nil {
+		opts.Logf(format, v...)
+	}
+}
+
+const defaultPollInterval = 5 * time.Second
+
+// WaitReceipt waits until tx is mined and has enough confirmations, then returns its receipt. Receipt is checked
+// on each new head if node supports subscription (websocket or ipc), and every poll interval anyway. Reorgs (tx moved
+// to another block or disappeared) are warned, and it keeps waiting until the block of tx is canonical and deep enough.
+func WaitReceipt(ctx context.Context, client *Client, txHash common.Hash, opts WaitOptions) (*types.Receipt, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaultPollInterval
+	}
+	if opts.Confirmations == 0 {
+		opts.Confirmations = 1
+	}
+
+	// heads is nil (never receives) if subscription is not supported
+	var heads chan *types.Header
+	headCh := make(chan *types.Header, 16)
+	if sub, err := client.EthClient.SubscribeNewHead(ctx, headCh); err == nil {
+		defer sub.Unsubscribe()
+		heads = headCh
+	}
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+
+	var head uint64 // latest block number known, 0 means unknown
+	var minedIn *types.Receipt
+	for {
+		rp, err := client.EthClient.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			if minedIn == nil {
+				opts.logf("tx %v mined in block %v (%v)", txHash.String(), rp.BlockNumber, rp.BlockHash.String())
+			} else if minedIn.BlockHash != rp.BlockHash {
+				opts.logf("WARNING: reorg detected, tx %v moved from block %v (%v) to block %v (%v)", txHash.String(),
+					minedIn.BlockNumber, minedIn.BlockHash.String(), rp.BlockNumber, rp.BlockHash.String())
+			}
+			minedIn = rp
+			if opts.Confirmations <= 1 {
+				return rp, nil
+			}
+			block := rp.BlockNumber.Uint64()
+			if head < block { // unknown or stale
+				if head, err = client.EthClient.BlockNumber(ctx); err != nil {
+					return nil, waitErr(ctx, txHash, fmt.Errorf("BlockNumber fail: %w", err))
+				}
+			}
+			if head >= block {
+				confirmations := head - block + 1
+				if confirmations >= opts.Confirmations {
+					// the receipt may be read before the reorg, make sure its block is still canonical
+					canonical, err := canonicalBlockHash(ctx, client.RpcClient, rp.BlockNumber)
+					if err != nil {
+						return nil, waitErr(ctx, txHash, err)
+					}
+					if canonical == rp.BlockHash {
+						return rp, nil
+					}
+					opts.logf("WARNING: reorg detected, block %v (%v) of tx %v is replaced by %v", rp.BlockNumber,
+						rp.BlockHash.String(), txHash.String(), canonical.String())
+				} else {
+					opts.logf("tx %v has %v of %v confirmations", txHash.String(), confirmations, opts.Confirmations)
+				}
+			}
+		} else if errors.Is(err, ethereum.NotFound) {
+			if minedIn != nil {
+				opts.logf("WARNING: reorg detected, tx %v not found anymore, block %v (%v) is reorged out", txHash.String(),
+					minedIn.BlockNumber, minedIn.BlockHash.String())
+				minedIn = nil
+			} else {
+				opts.logf("tx %v not found (may be pending) in network, re-check after %v", txHash.String(), opts.PollInterval)
+			}
+		} else {
+			return nil, waitErr(ctx, txHash, fmt.Errorf("TransactionReceipt fail: %w", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, waitErr(ctx, txHash, ctx.Err())
+		case header := <-heads:
+			head = header.Number.Uint64()
+		case <-ticker.C:
+			head = 0
+		}
+	}
+}
+
+// canonicalBlockHash returns hash of the canonical block of number. The hash field of block is used as hash computed
+// from header is not same as it in some chains with extra header fields.
+func canonicalBlockHash(ctx context.Context, rpcClient *rpc.Client, number *big.Int) (common.Hash, error) {
+	var block struct {
+		Hash common.Hash `json:"hash"`
+	}
+	if err := rpcClient.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeBig(number), false); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_getBlockByNumber fail: %w", err)
+	}
+	return block.Hash, nil
+}
+
+// waitErr returns a timeout error if ctx exceeded its deadline, otherwise err.
+func waitErr(ctx context.Context, txHash common.Hash, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timeout waiting receipt of tx %v: %w", txHash.String(), ctx.Err())
+	}
+	return err
+}
+
+// Transact builds, signs and broadcasts a tx, the signed tx is returned without waiting for its receipt.
+func Transact(ctx context.Context, client *Client, privateKey *ecdsa.PrivateKey, toAddress *common.Address, amount *big.Int, data []byte, opts TxOptions) (*types.Transaction, error) {
+	tx, err := BuildTx(ctx, client.EthClient, AddressFromPrivateKey(privateKey), toAddress, amount, data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	signedTx, err := SignTx(ctx, client.EthClient, tx, privateKey, opts.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := SendRawTransaction(ctx, client.RpcClient, signedTx); err != nil {
+		return nil, fmt.Errorf("SendRawTransaction fail: %w", err)
+	}
+	return signedTx, nil
+}
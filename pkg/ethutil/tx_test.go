@@ -0,0 +1,98 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newReceiptServer returns a server on which tx is mined after pending polls of receipt. The tx is in block 0x10 of a
+// stale fork in the next reorged polls, then in canonical block 0x11. Block number increases by 1 on each
+// eth_blockNumber since 0x10.
+func newReceiptServer(pending, reorged int32) *httptest.Server {
+	var receiptHits, blockHits int32
+	canonical := "0x" + strings.Repeat("33", 32)
+	receipt := func(blockNumber, blockHash string) string {
+		return `{"transactionHash":"0x` + strings.Repeat("11", 32) + `","blockHash":"` + blockHash +
+			`","blockNumber":"` + blockNumber + `","transactionIndex":"0x0","cumulativeGasUsed":"0x5208","gasUsed":"0x5208",` +
+			`"logsBloom":"0x` + strings.Repeat("00", 256) + `","logs":[],"status":"0x1","type":"0x0","effectiveGasPrice":"0x1"}`
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var result = "null"
+		switch req.Method {
+		case "eth_getTransactionReceipt":
+			if hits := atomic.AddInt32(&receiptHits, 1); hits > pending+reorged {
+				result = receipt("0x11", canonical)
+			} else if hits > pending {
+				result = receipt("0x10", "0x"+strings.Repeat("22", 32))
+			}
+		case "eth_blockNumber":
+			result = fmt.Sprintf(`"0x%x"`, 0x10+atomic.AddInt32(&blockHits, 1)-1)
+		case "eth_getBlockByNumber":
+			result = `{"hash":"` + canonical + `"}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, req.Id, result)
+	}))
+}
+
+func TestWaitReceipt(t *testing.T) {
+	tests := []struct {
+		pending       int32
+		reorged       int32
+		confirmations uint64
+		timeout       time.Duration
+		expectedBlock uint64
+		expectErr     bool
+	}{
+		{0, 0, 0, 0, 0x11, false},
+		{2, 0, 1, 0, 0x11, false},
+		{1, 0, 3, 0, 0x11, false},
+		{0, 1, 1, 0, 0x10, false}, // reorg is not detected without confirmations
+		{1, 3, 2, 0, 0x11, false},
+		{1 << 30, 0, 1, 50 * time.Millisecond, 0, true},
+	}
+
+	for i, test := range tests {
+		server := newReceiptServer(test.pending, test.reorged)
+		client, err := Dial(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("test %d: dial fail: %v", i, err)
+		}
+		var messages []string
+		rp, err := WaitReceipt(context.Background(), client, common.Hash{}, WaitOptions{
+			Confirmations: test.confirmations,
+			PollInterval:  time.Millisecond,
+			Timeout:       test.timeout,
+			Logf: func(format string, v ...any) {
+				messages = append(messages, fmt.Sprintf(format, v...))
+			},
+		})
+		server.Close()
+		if reorgWarned := strings.Contains(strings.Join(messages, "\n"), "reorg detected"); reorgWarned != (test.reorged > 0 && test.confirmations > 1) {
+			t.Fatalf("test %d: unexpected messages: %v", i, messages)
+		}
+		if (err != nil) != test.expectErr {
+			t.Fatalf("test %d: expected error: %v, got: %v", i, test.expectErr, err)
+		}
+		if err == nil && rp.BlockNumber.Uint64() != test.expectedBlock {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.expectedBlock, rp.BlockNumber)
+		}
+		if test.expectErr && !strings.Contains(err.Error(), "timeout") {
+			t.Fatalf("test %d: expected: timeout error, got: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package ethutil
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// uint256Units are the unit suffixes of number literal of expression.
+var uint256Units = map[string]int32{"wei": 0, "gwei": 9, "ether": 18}
+
+// uint256Funcs are the functions of expression and their number of arguments.
+var uint256Funcs = map[string]int{
+	"mulDiv":   3, // a * b / c, the intermediate product is not truncated like mulDiv of OpenZeppelin
+	"mulDivUp": 3, // a * b / c, rounding up
+	"pct":      2, // a * p / 100
+	"bps":      2, // a * b / 10000
+	"min":      2,
+	"max":      2,
+	"sqrt":     1, // floor of square root
+}
+
+// Uint256Eval evaluates expression over uint256 like the EVM. Number literal is decimal or 0x prefixed hex, decimal
+// can have fraction, exponent and unit suffix (wei, gwei, ether), e.g. "1.5ether", "2e9", "0xff". Operators and
+// precedence follow solidity: ** (right associative), unary ~, * / %, + -, << >>, &, ^, |, parentheses group
+// sub-expressions. Functions: mulDiv(a, b, c), mulDivUp(a, b, c), pct(a, p), bps(a, b), min(a, b), max(a, b),
+// sqrt(a). Overflow and underflow are errors like checked arithmetic of solidity 0.8, unless unchecked is true
+// (results wrap modulo 2^256).
+func Uint256Eval(expr string, unchecked bool) (*big.Int, error) {
+	tokens, err := tokenizeUint256Expr(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &uint256Parser{tokens: tokens, unchecked: unchecked}
+	result, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return result, nil
+}
+
+func isIdentChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.'
+}
+
+// nextWord returns the leading identifier of s.
+func nextWord(s string) string {
+	i := 0
+	for i < len(s) && isIdentChar(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+func tokenizeUint256Expr(expr string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case strings.HasPrefix(expr[i:], "**") || strings.HasPrefix(expr[i:], "<<") || strings.HasPrefix(expr[i:], ">>"):
+			tokens = append(tokens, expr[i:i+2])
+			i += 2
+		case strings.ContainsRune("+-*/%&|^~(),", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		case isIdentChar(c):
+			j := i
+			for j < len(expr) && (isIdentChar(expr[j]) ||
+				// exponent sign, e.g. 1e+18
+				(expr[j] == '+' || expr[j] == '-') && j > i && (expr[j-1] == 'e' || expr[j-1] == 'E') && expr[i] >= '0' && expr[i] <= '9' && !strings.HasPrefix(expr[i:], "0x")) {
+				j++
+			}
+			// a number can be separated from its unit by spaces, e.g. "1.5 ether"
+			if expr[i] >= '0' && expr[i] <= '9' {
+				k := j
+				for k < len(expr) && expr[k] == ' ' {
+					k++
+				}
+				if _, ok := uint256Units[nextWord(expr[k:])]; ok && k > j {
+					j = k + len(nextWord(expr[k:]))
+				}
+			}
+			tokens = append(tokens, strings.ReplaceAll(expr[i:j], " ", ""))
+			i = j
+		default:
+			return nil, fmt.Errorf("unexpected character %q", c)
+		}
+	}
+	return tokens, nil
+}
+
+// parseUint256Literal parses number literal, see Uint256Eval.
+func parseUint256Literal(s string) (*big.Int, error) {
+	lower := strings.ToLower(strings.ReplaceAll(s, "_", ""))
+	if strings.HasPrefix(lower, "0x") {
+		n, ok := new(big.Int).SetString(lower[2:], 16)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %v", s)
+		}
+		return n, nil
+	}
+
+	var exp int32
+	// match the longest unit, "gwei" before "wei"
+	for _, unit := range []string{"gwei", "ether", "wei"} {
+		if strings.HasSuffix(lower, unit) {
+			lower, exp = strings.TrimSuffix(lower, unit), uint256Units[unit]
+			break
+		}
+	}
+	d, err := decimal.NewFromString(lower)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %v", s)
+	}
+	d = d.Shift(exp)
+	if !d.IsInteger() {
+		return nil, fmt.Errorf("%v is not an integer", s)
+	}
+	return d.BigInt(), nil
+}
+
+type uint256Parser struct {
+	tokens    []string
+	pos       int
+	unchecked bool
+}
+
+// uint256BinaryPrecedence is the precedence of binary operators, higher binds tighter.
+var uint256BinaryPrecedence = map[string]int{
+	"|": 1, "^": 2, "&": 3, "<<": 4, ">>": 4, "+": 5, "-": 5, "*": 6, "/": 6, "%": 6, "**": 8,
+}
+
+func (p *uint256Parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *uint256Parser) expect(token string) error {
+	if p.peek() != token {
+		if p.peek() == "" {
+			return fmt.Errorf("expected %q, got end of expression", token)
+		}
+		return fmt.Errorf("expected %q, got %q", token, p.peek())
+	}
+	p.pos++
+	return nil
+}
+
+// parseBinary parses binary operators of precedence higher than minPrecedence by precedence climbing.
+func (p *uint256Parser) parseBinary(minPrecedence int) (*big.Int, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		precedence, ok := uint256BinaryPrecedence[op]
+		if !ok || precedence <= minPrecedence {
+			return left, nil
+		}
+		p.pos++
+		var next = precedence
+		if op == "**" {
+			next = precedence - 1 // right associative
+		}
+		right, err := p.parseBinary(next)
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.apply(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseUnary parses ~, which binds tighter than all binary operators except **.
+func (p *uint256Parser) parseUnary() (*big.Int, error) {
+	if p.peek() == "~" {
+		p.pos++
+		operand, err := p.parseBinary(7)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).Xor(operand, maxUint256), nil
+	}
+	if p.peek() == "-" {
+		return nil, fmt.Errorf("negative number is not supported in uint256")
+	}
+	return p.parsePrimary()
+}
+
+func (p *uint256Parser) parsePrimary() (*big.Int, error) {
+	token := p.peek()
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "(":
+		p.pos++
+		value, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		return value, p.expect(")")
+	case token[0] >= '0' && token[0] <= '9' || token[0] == '.':
+		p.pos++
+		n, err := parseUint256Literal(token)
+		if err != nil {
+			return nil, err
+		}
+		if n.Cmp(maxUint256) > 0 {
+			return nil, fmt.Errorf("%v overflows uint256", token)
+		}
+		return n, nil
+	}
+
+	argc, ok := uint256Funcs[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown function or unexpected %q", token)
+	}
+	p.pos++
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	var args []*big.Int
+	for i := 0; i < argc; i++ {
+		if i > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, fmt.Errorf("%v requires %v arguments: %w", token, argc, err)
+			}
+		}
+		arg, err := p.parseBinary(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, fmt.Errorf("%v requires %v arguments: %w", token, argc, err)
+	}
+	return p.call(token, args)
+}
+
+// checkResult truncates result to uint256 in unchecked mode, or returns error if it overflows.
+func (p *uint256Parser) checkResult(op string, result *big.Int) (*big.Int, error) {
+	if result.Sign() >= 0 && result.Cmp(maxUint256) <= 0 {
+		return result, nil
+	}
+	if !p.unchecked {
+		if result.Sign() < 0 {
+			return nil, fmt.Errorf("underflow in %v", op)
+		}
+		return nil, fmt.Errorf("overflow in %v", op)
+	}
+	return result.And(result, maxUint256), nil // And of negative big.Int is two's complement
+}
+
+func (p *uint256Parser) apply(op string, a, b *big.Int) (*big.Int, error) {
+	if (op == "/" || op == "%") && b.Sign() == 0 {
+		return nil, fmt.Errorf("division by zero")
+	}
+	switch op {
+	case "+":
+		return p.checkResult(op, new(big.Int).Add(a, b))
+	case "-":
+		return p.checkResult(op, new(big.Int).Sub(a, b))
+	case "*":
+		return p.checkResult(op, new(big.Int).Mul(a, b))
+	case "/":
+		return new(big.Int).Div(a, b), nil
+	case "%":
+		return new(big.Int).Mod(a, b), nil
+	case "**":
+		if p.unchecked {
+			return new(big.Int).Exp(a, b, new(big.Int).Add(maxUint256, big.NewInt(1))), nil
+		}
+		// avoid computing huge power, it overflows anyway
+		if a.Cmp(big.NewInt(1)) > 0 && b.Cmp(big.NewInt(256)) > 0 {
+			return nil, fmt.Errorf("overflow in **")
+		}
+		return p.checkResult(op, new(big.Int).Exp(a, b, nil))
+	case "&":
+		return new(big.Int).And(a, b), nil
+	case "|":
+		return new(big.Int).Or(a, b), nil
+	case "^":
+		return new(big.Int).Xor(a, b), nil
+	case "<<":
+		// shift is not checked in solidity, the high bits are dropped
+		if b.Cmp(big.NewInt(256)) >= 0 {
+			return new(big.Int), nil
+		}
+		return new(big.Int).And(new(big.Int).Lsh(a, uint(b.Uint64())), maxUint256), nil
+	case ">>":
+		if b.Cmp(big.NewInt(256)) >= 0 {
+			return new(big.Int), nil
+		}
+		return new(big.Int).Rsh(a, uint(b.Uint64())), nil
+	}
+	return nil, fmt.Errorf("unknown operator %v", op)
+}
+
+func (p *uint256Parser) call(name string, args []*big.Int) (*big.Int, error) {
+	switch name {
+	case "mulDiv", "mulDivUp":
+		if args[2].Sign() == 0 {
+			return nil, fmt.Errorf("division by zero in %v", name)
+		}
+		product := new(big.Int).Mul(args[0], args[1])
+		quotient, remainder := new(big.Int).QuoRem(product, args[2], new(big.Int))
+		if name == "mulDivUp" && remainder.Sign() != 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+		// mulDiv reverts if result overflows, even in unchecked block
+		if quotient.Cmp(maxUint256) > 0 {
+			return nil, fmt.Errorf("overflow in %v", name)
+		}
+		return quotient, nil
+	case "pct", "bps":
+		var denominator = big.NewInt(100)
+		if name == "bps" {
+			denominator = big.NewInt(10000)
+		}
+		product, err := p.checkResult(name, new(big.Int).Mul(args[0], args[1]))
+		if err != nil {
+			return nil, err
+		}
+		return product.Div(product, denominator), nil
+	case "min":
+		if args[0].Cmp(args[1]) < 0 {
+			return args[0], nil
+		}
+		return args[1], nil
+	case "max":
+		if args[0].Cmp(args[1]) > 0 {
+			return args[0], nil
+		}
+		return args[1], nil
+	case "sqrt":
+		return new(big.Int).Sqrt(args[0]), nil
+	}
+	return nil, fmt.Errorf("unknown function %v", name)
+}
@@ -0,0 +1,59 @@
+package ethutil
+
+import (
+	"testing"
+)
+
+func TestUint256Eval(t *testing.T) {
+	var maxHex = "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	tests := []struct {
+		expr      string
+		unchecked bool
+		expected  string // empty means error
+	}{
+		{"1 + 2 * 3", false, "7"},
+		{"(1 + 2) * 3", false, "9"},
+		{"2 ** 3 ** 2", false, "512"},
+		{"1 << 8 | 1", false, "257"},
+		{"0xff & 0x0f ^ 0x01", false, "14"},
+		{"~0", false, maxHex},
+		{"1.5 ether", false, "1500000000000000000"},
+		{"2gwei + 1wei", false, "2000000001"},
+		{"1e18 / 3", false, "333333333333333333"},
+		{"1e-1", false, ""},
+		{"1_000_000 % 7", false, "1"},
+		{"mulDiv(" + maxHex + ", " + maxHex + ", " + maxHex + ")", false, maxHex},
+		{"mulDivUp(10, 10, 3)", false, "34"},
+		{"pct(1 ether, 3)", false, "30000000000000000"},
+		{"bps(10000, 25)", false, "25"},
+		{"min(3, 2) + max(3, 2) + sqrt(17)", false, "9"},
+		{"2 ** 256", false, ""},
+		{"2 ** 256", true, "0"},
+		{"0 - 1", false, ""},
+		{"0 - 1", true, maxHex},
+		{maxHex + " + 1", false, ""},
+		{"1 << 256", false, "0"},
+		{"1 / 0", true, ""},
+		{"mulDiv(1, 2)", false, ""},
+		{"foo(1)", false, ""},
+		{"(1 + 2", false, ""},
+		{"-1", false, ""},
+	}
+
+	for i, test := range tests {
+		result, err := Uint256Eval(test.expr, test.unchecked)
+		if test.expected == "" {
+			if err == nil {
+				t.Fatalf("test %d: expected error, got: %v", i, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("test %d: unexpected error: %v", i, err)
+		}
+		expected, _ := ParseInteger(test.expected)
+		if result.Cmp(expected) != 0 {
+			t.Fatalf("test %d: expected: %v, got: %v", i, expected, result)
+		}
+	}
+}
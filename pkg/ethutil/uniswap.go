@@ -0,0 +1,182 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Supported Uniswap versions.
+const (
+	UniswapV2 = "v2"
+	UniswapV3 = "v3"
+)
+
+// UniswapDeployment is the periphery contracts of Uniswap on a chain: UniswapV2Router02, QuoterV2 and SwapRouter02
+// of v3.
+type UniswapDeployment struct {
+	V2Router common.Address
+	V3Quoter common.Address
+	V3Router common.Address
+}
+
+// KnownUniswapDeployments are Uniswap deployments keyed by chain id, see https://docs.uniswap.org/contracts/v3/reference/deployments/
+var KnownUniswapDeployments = map[uint64]UniswapDeployment{
+	1: {
+		V2Router: common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D"),
+		V3Quoter: common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+		V3Router: common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	},
+	11155111: {
+		V2Router: common.HexToAddress("0xeE567Fe1712Faf6149d80dA1E6934E354124CfE3"),
+		V3Quoter: common.HexToAddress("0xEd1f6473345F45b75F8179591dd5bA1888cf2FB3"),
+		V3Router: common.HexToAddress("0x3bFA4769FB09eefC5a80d6E87c3B9C650f7Ae48E"),
+	},
+	10: {
+		V2Router: common.HexToAddress("0x4A7b5Da61326A6379179b40d00F57E5bbDC962c2"),
+		V3Quoter: common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+		V3Router: common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	},
+	137: {
+		V2Router: common.HexToAddress("0xedf6066a2b290C185783862C7F4776A2C8077AD1"),
+		V3Quoter: common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+		V3Router: common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	},
+	8453: {
+		V2Router: common.HexToAddress("0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"),
+		V3Quoter: common.HexToAddress("0x3d4e44Eb1374240CE5F1B871ab261CD16335B76a"),
+		V3Router: common.HexToAddress("0x2626664c2603336E57B271c5C0b26F421741e481"),
+	},
+	42161: {
+		V2Router: common.HexToAddress("0x4752ba5DBc23f44D87826276BF6Fd6b1C372aD24"),
+		V3Quoter: common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+		V3Router: common.HexToAddress("0x68b3465833fb72A70ecDF485E0e4C7bD8665Fc45"),
+	},
+}
+
+// UniswapV3FeeTiers are the fee tiers (in hundredths of a bip) of Uniswap v3 pools.
+var UniswapV3FeeTiers = []uint32{100, 500, 3000, 10000}
+
+// uniswapV3AddressThis is the ADDRESS_THIS constant of SwapRouter02, swap output sent to it is kept by router, e.g.
+// for unwrapping WETH9 in the same multicall.
+var uniswapV3AddressThis = common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+// UniswapWeth returns the wrapped native token used by router, by WETH() of v2 router or WETH9() of v3 router.
+func UniswapWeth(ctx context.Context, client *ethclient.Client, version string, router common.Address) (common.Address, error) {
+	funcDef := "function WETH9() returns (address)"
+	if version == UniswapV2 {
+		funcDef = "function WETH() returns (address)"
+	}
+	values, err := CallAndUnpack(ctx, client, router, funcDef, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+// addressArray formats addresses as array argument of BuildTxInputData.
+func addressArray(addresses []common.Address) string {
+	var elems []string
+	for _, address := range addresses {
+		elems = append(elems, address.Hex())
+	}
+	return "[" + strings.Join(elems, ",") + "]"
+}
+
+// UniswapV2Quote returns output amount of swapping amountIn along path by getAmountsOut of v2 router.
+func UniswapV2Quote(ctx context.Context, client *ethclient.Client, router common.Address, path []common.Address, amountIn *big.Int) (*big.Int, error) {
+	values, err := CallAndUnpack(ctx, client, router, "function getAmountsOut(uint256,address[]) returns (uint256[])",
+		[]string{amountIn.String(), addressArray(path)})
+	if err != nil {
+		return nil, err
+	}
+	amounts := values[0].([]*big.Int)
+	if len(amounts) != len(path) {
+		return nil, fmt.Errorf("getAmountsOut returns %v amounts, expect %v", len(amounts), len(path))
+	}
+	return amounts[len(amounts)-1], nil
+}
+
+// UniswapV3Quote returns output amount of swapping amountIn in the v3 pool of fee tier, by quoteExactInputSingle of
+// QuoterV2. The call reverts if the pool doesn't exist.
+func UniswapV3Quote(ctx context.Context, client *ethclient.Client, quoter common.Address, tokenIn, tokenOut common.Address, fee uint32, amountIn *big.Int) (*big.Int, error) {
+	values, err := CallAndUnpack(ctx, client, quoter,
+		"function quoteExactInputSingle((address,address,uint256,uint24,uint160)) returns (uint256, uint160, uint32, uint256)",
+		[]string{fmt.Sprintf("(%v,%v,%v,%v,0)", tokenIn.Hex(), tokenOut.Hex(), amountIn, fee)})
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// UniswapV3BestQuote quotes amountIn in pools of all UniswapV3FeeTiers, and returns the best output amount and its
+// fee tier. Pools not deployed or without liquidity are skipped.
+func UniswapV3BestQuote(ctx context.Context, client *ethclient.Client, quoter common.Address, tokenIn, tokenOut common.Address, amountIn *big.Int) (*big.Int, uint32, error) {
+	var best *big.Int
+	var bestFee uint32
+	for _, fee := range UniswapV3FeeTiers {
+		amountOut, err := UniswapV3Quote(ctx, client, quoter, tokenIn, tokenOut, fee, amountIn)
+		if err != nil {
+			if isCallReverted(err) {
+				continue
+			}
+			return nil, 0, err
+		}
+		if best == nil || amountOut.Cmp(best) > 0 {
+			best, bestFee = amountOut, fee
+		}
+	}
+	if best == nil {
+		return nil, 0, fmt.Errorf("no v3 pool of %v and %v has liquidity", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, bestFee, nil
+}
+
+// MinAmountOut returns amountOut reduced by slippage in basis points (e.g. 50 is 0.5%), rounding down.
+func MinAmountOut(amountOut *big.Int, slippageBps int64) *big.Int {
+	minOut := new(big.Int).Mul(amountOut, big.NewInt(10000-slippageBps))
+	return minOut.Div(minOut, big.NewInt(10000))
+}
+
+// UniswapV2SwapData builds input data of v2 router swapping exact amountIn along path. If ethIn, native token is
+// sent as value and path starts with WETH; if ethOut, path ends with WETH and native token is sent to recipient.
+func UniswapV2SwapData(amountIn, minOut *big.Int, path []common.Address, recipient common.Address, deadline int64, ethIn, ethOut bool) ([]byte, error) {
+	args := []string{minOut.String(), addressArray(path), recipient.Hex(), fmt.Sprint(deadline)}
+	switch {
+	case ethIn:
+		return BuildTxInputData("swapExactETHForTokens(uint256,address[],address,uint256)", args)
+	case ethOut:
+		return BuildTxInputData("swapExactTokensForETH(uint256,uint256,address[],address,uint256)", append([]string{amountIn.String()}, args...))
+	default:
+		return BuildTxInputData("swapExactTokensForTokens(uint256,uint256,address[],address,uint256)", append([]string{amountIn.String()}, args...))
+	}
+}
+
+// UniswapV3SwapData builds input data of multicall(deadline, data) of SwapRouter02 swapping exact amountIn in the
+// pool of fee tier. If tokenIn is WETH and native token is sent as value, router wraps it. If ethOut, tokenOut must
+// be WETH and it's unwrapped and sent to recipient.
+func UniswapV3SwapData(tokenIn, tokenOut common.Address, fee uint32, amountIn, minOut *big.Int, recipient common.Address, deadline int64, ethOut bool) ([]byte, error) {
+	swapRecipient := recipient
+	if ethOut {
+		swapRecipient = uniswapV3AddressThis
+	}
+	swap, err := BuildTxInputData("exactInputSingle((address,address,uint24,address,uint256,uint256,uint160))",
+		[]string{fmt.Sprintf("(%v,%v,%v,%v,%v,%v,0)", tokenIn.Hex(), tokenOut.Hex(), fee, swapRecipient.Hex(), amountIn, minOut)})
+	if err != nil {
+		return nil, err
+	}
+	calls := []string{hexutil.Encode(swap)}
+	if ethOut {
+		unwrap, err := BuildTxInputData("unwrapWETH9(uint256,address)", []string{minOut.String(), recipient.Hex()})
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, hexutil.Encode(unwrap))
+	}
+	return BuildTxInputData("multicall(uint256,bytes[])", []string{fmt.Sprint(deadline), "[" + strings.Join(calls, ",") + "]"})
+}
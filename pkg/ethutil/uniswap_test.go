@@ -0,0 +1,114 @@
+package ethutil
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestUniswapQuote(t *testing.T) {
+	tokenIn := common.HexToAddress("0x01")
+	tokenOut := common.HexToAddress("0x02")
+	quoteSig := "quoteExactInputSingle((address,address,uint256,uint24,uint160))"
+	quote := func(fee string) string {
+		return callData(t, quoteSig, "("+tokenIn.Hex()+","+tokenOut.Hex()+",1000,"+fee+",0)")
+	}
+	server := newContractServer(t, map[string]string{
+		quote("500"):  words(990, 0, 0, 0),
+		quote("3000"): words(995, 0, 0, 0),
+		callData(t, "getAmountsOut(uint256,address[])", "1000", "["+tokenIn.Hex()+","+tokenOut.Hex()+"]"): words(32, 2, 1000, 997),
+	})
+	defer server.Close()
+	client, err := Dial(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	amountOut, fee, err := UniswapV3BestQuote(context.Background(), client.EthClient, common.HexToAddress("0x03"), tokenIn, tokenOut, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amountOut.Int64() != 995 || fee != 3000 {
+		t.Errorf("UniswapV3BestQuote() = %v %v, want 995 3000", amountOut, fee)
+	}
+	if _, _, err := UniswapV3BestQuote(context.Background(), client.EthClient, common.HexToAddress("0x03"), tokenOut, tokenIn, big.NewInt(1000)); err == nil {
+		t.Errorf("UniswapV3BestQuote() without pool, expect error")
+	}
+
+	amountOut, err = UniswapV2Quote(context.Background(), client.EthClient, common.HexToAddress("0x04"), []common.Address{tokenIn, tokenOut}, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amountOut.Int64() != 997 {
+		t.Errorf("UniswapV2Quote() = %v, want 997", amountOut)
+	}
+}
+
+func TestMinAmountOut(t *testing.T) {
+	tests := []struct {
+		amountOut   int64
+		slippageBps int64
+		want        int64
+	}{
+		{10000, 50, 9950},
+		{999, 50, 994},
+		{1000, 0, 1000},
+	}
+	for _, tt := range tests {
+		if got := MinAmountOut(big.NewInt(tt.amountOut), tt.slippageBps); got.Int64() != tt.want {
+			t.Errorf("MinAmountOut(%v, %v) = %v, want %v", tt.amountOut, tt.slippageBps, got, tt.want)
+		}
+	}
+}
+
+func TestUniswapSwapData(t *testing.T) {
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	token := common.HexToAddress("0x01")
+	recipient := common.HexToAddress("0x05")
+	amountIn, minOut := big.NewInt(1000), big.NewInt(990)
+
+	tests := []struct {
+		name       string
+		build      func() ([]byte, error)
+		wantPrefix string
+		contains   []string
+	}{
+		{"v2 tokens", func() ([]byte, error) {
+			return UniswapV2SwapData(amountIn, minOut, []common.Address{token, weth}, recipient, 100, false, false)
+		}, "0x38ed1739", nil},
+		{"v2 eth in", func() ([]byte, error) {
+			return UniswapV2SwapData(amountIn, minOut, []common.Address{weth, token}, recipient, 100, true, false)
+		}, "0x7ff36ab5", nil},
+		{"v2 eth out", func() ([]byte, error) {
+			return UniswapV2SwapData(amountIn, minOut, []common.Address{token, weth}, recipient, 100, false, true)
+		}, "0x18cbafe5", nil},
+		{"v3", func() ([]byte, error) {
+			return UniswapV3SwapData(token, weth, 3000, amountIn, minOut, recipient, 100, false)
+		}, "0x5ae401dc", []string{"04e45aaf"}},
+		{"v3 eth out", func() ([]byte, error) {
+			return UniswapV3SwapData(token, weth, 3000, amountIn, minOut, recipient, 100, true)
+		}, "0x5ae401dc", []string{"04e45aaf", "49404b7c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			encoded := hexutil.Encode(data)
+			if !strings.HasPrefix(encoded, tt.wantPrefix) {
+				t.Errorf("selector = %v, want %v", encoded[:10], tt.wantPrefix)
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(encoded, s) {
+					t.Errorf("data doesn't contain %v", s)
+				}
+			}
+		})
+	}
+}
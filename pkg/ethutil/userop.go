@@ -0,0 +1,231 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// EntryPointV06Address is the address of ERC-4337 EntryPoint v0.6, it's same in all chains.
+var EntryPointV06Address = common.HexToAddress("0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789")
+
+// UserOperation is the ERC-4337 (EntryPoint v0.6) user operation, it's encoded as the json format accepted by bundlers.
+type UserOperation struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+// Hash returns the user operation hash, which is signed by the owner of account.
+func (op *UserOperation) Hash(entryPoint common.Address, chainID *big.Int) (common.Hash, error) {
+	packed, err := EncodeParameters(
+		[]string{"address", "uint256", "bytes32", "bytes32", "uint256", "uint256", "uint256", "uint256", "uint256", "bytes32"},
+		[]string{
+			op.Sender.Hex(),
+			op.Nonce.ToInt().String(),
+			crypto.Keccak256Hash(op.InitCode).Hex(),
+			crypto.Keccak256Hash(op.CallData).Hex(),
+			op.CallGasLimit.ToInt().String(),
+			op.VerificationGasLimit.ToInt().String(),
+			op.PreVerificationGas.ToInt().String(),
+			op.MaxFeePerGas.ToInt().String(),
+			op.MaxPriorityFeePerGas.ToInt().String(),
+			crypto.Keccak256Hash(op.PaymasterAndData).Hex(),
+		})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	encoded, err := EncodeParameters([]string{"bytes32", "address", "uint256"},
+		[]string{crypto.Keccak256Hash(packed).Hex(), entryPoint.Hex(), chainID.String()})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// RequiredPrefund returns the max gas cost of op, which must be held by sender (if no paymaster is used).
+func (op *UserOperation) RequiredPrefund() *big.Int {
+	gas := new(big.Int).Add(op.CallGasLimit.ToInt(), op.VerificationGasLimit.ToInt())
+	gas.Add(gas, op.PreVerificationGas.ToInt())
+	return gas.Mul(gas, op.MaxFeePerGas.ToInt())
+}
+
+// GetSenderAddress computes the counterfactual address of account created by initCode, by calling
+// EntryPoint.getSenderAddress which always reverts with SenderAddressResult(address).
+func GetSenderAddress(ctx context.Context, client *ethclient.Client, entryPoint common.Address, initCode []byte) (common.Address, error) {
+	data, err := BuildTxInputData("getSenderAddress(bytes)", []string{hexutil.Encode(initCode)})
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	_, err = Call(ctx, client, entryPoint, data, nil)
+	if err == nil {
+		return common.Address{}, fmt.Errorf("getSenderAddress does not revert")
+	}
+	dataErr, ok := err.(rpc.DataError)
+	if !ok {
+		return common.Address{}, err
+	}
+	revertData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return common.Address{}, err
+	}
+
+	// SenderAddressResult(address)
+	selector := hexutil.Encode(crypto.Keccak256([]byte("SenderAddressResult(address)"))[0:4])
+	if !strings.HasPrefix(revertData, selector) || len(revertData) != len(selector)+64 {
+		return common.Address{}, fmt.Errorf("unexpected revert data of getSenderAddress: %v", revertData)
+	}
+	return common.HexToAddress(revertData[len(selector):]), nil
+}
+
+// GetUserOpNonce returns the next nonce of sender with key 0 in entryPoint.
+func GetUserOpNonce(ctx context.Context, client *ethclient.Client, entryPoint common.Address, sender common.Address) (*big.Int, error) {
+	data, err := BuildTxInputData("getNonce(address,uint192)", []string{sender.Hex(), "0"})
+	if err != nil {
+		return nil, err
+	}
+	output, err := Call(ctx, client, entryPoint, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(output), nil
+}
+
+// DepositInfo is the deposit and stake of an account or paymaster in EntryPoint.
+type DepositInfo struct {
+	Deposit         *big.Int
+	Staked          bool
+	Stake           *big.Int
+	UnstakeDelaySec uint32
+	WithdrawTime    uint64 // the time when stake can be withdrawn, 0 means stake is not unlocked
+}
+
+// GetDepositInfo returns the deposit and stake of account in entryPoint.
+func GetDepositInfo(ctx context.Context, client *ethclient.Client, entryPoint common.Address, account common.Address) (*DepositInfo, error) {
+	const funcDefinition = "function getDepositInfo(address) returns (uint112, bool, uint112, uint32, uint48)"
+	data, err := BuildTxInputData(funcDefinition, []string{account.Hex()})
+	if err != nil {
+		return nil, err
+	}
+	output, err := Call(ctx, client, entryPoint, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	returnArgs, err := BuildReturnArgs(funcDefinition)
+	if err != nil {
+		return nil, err
+	}
+	values, err := returnArgs.Unpack(output)
+	if err != nil {
+		return nil, fmt.Errorf("unpack return data of getDepositInfo fail: %w", err)
+	}
+	return &DepositInfo{
+		Deposit:         values[0].(*big.Int),
+		Staked:          values[1].(bool),
+		Stake:           values[2].(*big.Int),
+		UnstakeDelaySec: values[3].(uint32),
+		WithdrawTime:    values[4].(*big.Int).Uint64(),
+	}, nil
+}
+
+// BundlerClient talks to an ERC-4337 bundler.
+type BundlerClient struct {
+	rpcClient  *rpc.Client
+	entryPoint common.Address
+}
+
+// UserOperationGasEstimate is the result of eth_estimateUserOperationGas.
+type UserOperationGasEstimate struct {
+	PreVerificationGas   *hexutil.Big `json:"preVerificationGas"`
+	VerificationGasLimit *hexutil.Big `json:"verificationGasLimit"`
+	CallGasLimit         *hexutil.Big `json:"callGasLimit"`
+}
+
+// UserOperationReceipt is the result of eth_getUserOperationReceipt, only frequently used fields are included.
+type UserOperationReceipt struct {
+	UserOpHash    common.Hash  `json:"userOpHash"`
+	Success       bool         `json:"success"`
+	Reason        string       `json:"reason"`
+	ActualGasCost *hexutil.Big `json:"actualGasCost"`
+	Receipt       struct {
+		TransactionHash common.Hash `json:"transactionHash"`
+	} `json:"receipt"`
+}
+
+// DialBundler connects to the bundler, user operations are sent to entryPoint.
+func DialBundler(ctx context.Context, bundlerUrl string, entryPoint common.Address) (*BundlerClient, error) {
+	rpcClient, err := rpc.DialContext(ctx, bundlerUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &BundlerClient{rpcClient: rpcClient, entryPoint: entryPoint}, nil
+}
+
+// EstimateUserOperationGas estimates gas limits of op, signature of op should be a dummy signature of correct length.
+func (c *BundlerClient) EstimateUserOperationGas(ctx context.Context, op *UserOperation) (*UserOperationGasEstimate, error) {
+	var result UserOperationGasEstimate
+	if err := c.rpcClient.CallContext(ctx, &result, "eth_estimateUserOperationGas", op, c.entryPoint); err != nil {
+		return nil, fmt.Errorf("eth_estimateUserOperationGas fail: %w", err)
+	}
+	return &result, nil
+}
+
+// SendUserOperation sends signed op to bundler, the user operation hash is returned.
+func (c *BundlerClient) SendUserOperation(ctx context.Context, op *UserOperation) (common.Hash, error) {
+	var result common.Hash
+	if err := c.rpcClient.CallContext(ctx, &result, "eth_sendUserOperation", op, c.entryPoint); err != nil {
+		return common.Hash{}, fmt.Errorf("eth_sendUserOperation fail: %w", err)
+	}
+	return result, nil
+}
+
+// GetUserOperationReceipt returns the receipt of user operation, nil means it's not included yet (or unknown).
+func (c *BundlerClient) GetUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*UserOperationReceipt, error) {
+	var result *UserOperationReceipt
+	if err := c.rpcClient.CallContext(ctx, &result, "eth_getUserOperationReceipt", userOpHash); err != nil {
+		return nil, fmt.Errorf("eth_getUserOperationReceipt fail: %w", err)
+	}
+	return result, nil
+}
+
+// WaitUserOperationReceipt polls eth_getUserOperationReceipt until the user operation is included or ctx is done.
+func (c *BundlerClient) WaitUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*UserOperationReceipt, error) {
+	for {
+		result, err := c.GetUserOperationReceipt(ctx, userOpHash)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second * 5):
+		}
+	}
+}
+
+// Close closes the underlying rpc connection.
+func (c *BundlerClient) Close() {
+	c.rpcClient.Close()
+}
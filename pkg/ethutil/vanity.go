@@ -0,0 +1,168 @@
+package ethutil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// VanityMatcher matches address against prefix, suffix and regexp (all are optional), they are compared with the
+// hex of address without 0x. If CaseSensitive is true, they are compared with EIP-55 checksum address.
+type VanityMatcher struct {
+	Prefix        string
+	Suffix        string
+	Regexp        *regexp.Regexp
+	CaseSensitive bool
+}
+
+// NewVanityMatcher validates prefix and suffix, and compiles regex.
+func NewVanityMatcher(prefix string, suffix string, regex string, caseSensitive bool) (*VanityMatcher, error) {
+	prefix = strings.TrimPrefix(prefix, "0x")
+	for _, s := range []string{prefix, suffix} {
+		if _, err := hex.DecodeString(strings.Repeat("0", len(s)%2) + s); err != nil {
+			return nil, fmt.Errorf("%v is not hex", s)
+		}
+	}
+	if len(prefix)+len(suffix) > 2*common.AddressLength {
+		return nil, fmt.Errorf("prefix and suffix are too long")
+	}
+	var matcher = VanityMatcher{Prefix: prefix, Suffix: suffix, CaseSensitive: caseSensitive}
+	if !caseSensitive {
+		matcher.Prefix = strings.ToLower(prefix)
+		matcher.Suffix = strings.ToLower(suffix)
+	}
+	if regex != "" {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return nil, err
+		}
+		matcher.Regexp = re
+	}
+	return &matcher, nil
+}
+
+// Match reports whether address matches.
+func (m *VanityMatcher) Match(address common.Address) bool {
+	var s string
+	if m.CaseSensitive {
+		s = address.Hex()[2:]
+	} else {
+		s = hex.EncodeToString(address[:])
+	}
+	return strings.HasPrefix(s, m.Prefix) && strings.HasSuffix(s, m.Suffix) && (m.Regexp == nil || m.Regexp.MatchString(s))
+}
+
+// Difficulty returns the expected number of attempts to find a match, regexp is not counted.
+func (m *VanityMatcher) Difficulty() float64 {
+	var difficulty = math.Pow(16, float64(len(m.Prefix)+len(m.Suffix)))
+	if m.CaseSensitive {
+		// each letter has 1/2 chance to be in the required case
+		var letters int
+		for _, c := range m.Prefix + m.Suffix {
+			if !unicode.IsDigit(c) {
+				letters++
+			}
+		}
+		difficulty *= math.Pow(2, float64(letters))
+	}
+	return difficulty
+}
+
+// grind runs try in workers goroutines until one of them returns true or ctx is done, attempts is increased by the
+// number of tries.
+func grind(ctx context.Context, workers int, attempts *uint64, try func(worker int) bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				found := try(worker)
+				atomic.AddUint64(attempts, 1)
+				if found {
+					cancel()
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// GrindVanityKey generates private keys in workers goroutines until address of key matches, attempts is increased
+// by the number of generated keys, it can be read concurrently by atomic.LoadUint64 to report progress.
+func GrindVanityKey(ctx context.Context, matcher *VanityMatcher, workers int, attempts *uint64) (*ecdsa.PrivateKey, error) {
+	var mu sync.Mutex
+	var found *ecdsa.PrivateKey
+	grind(ctx, workers, attempts, func(worker int) bool {
+		privateKey, err := crypto.GenerateKey()
+		if err != nil || !matcher.Match(crypto.PubkeyToAddress(privateKey.PublicKey)) {
+			return false
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if found == nil {
+			found = privateKey
+		}
+		return true
+	})
+	if found == nil {
+		return nil, ctx.Err()
+	}
+	return found, nil
+}
+
+// GrindCreate2Salt searches salt in workers goroutines until CREATE2 address of deployer, salt and initCodeHash
+// matches, attempts is increased by the number of tried salts.
+func GrindCreate2Salt(ctx context.Context, matcher *VanityMatcher, deployer common.Address, initCodeHash common.Hash, workers int, attempts *uint64) (common.Hash, common.Address, error) {
+	var salts = make([]common.Hash, workers)
+	for i := range salts {
+		// each worker starts from a random salt and increases it, so workers never try the same salt
+		if _, err := rand.Read(salts[i][:]); err != nil {
+			return common.Hash{}, common.Address{}, err
+		}
+	}
+
+	var mu sync.Mutex
+	var foundSalt *common.Hash
+	var foundAddress common.Address
+	grind(ctx, workers, attempts, func(worker int) bool {
+		salt := &salts[worker]
+		for i := len(salt) - 1; i >= 0; i-- {
+			salt[i]++
+			if salt[i] != 0 {
+				break
+			}
+		}
+		address := Create2Address(deployer, *salt, initCodeHash)
+		if !matcher.Match(address) {
+			return false
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if foundSalt == nil {
+			s := *salt
+			foundSalt = &s
+			foundAddress = address
+		}
+		return true
+	})
+	if foundSalt == nil {
+		return common.Hash{}, common.Address{}, ctx.Err()
+	}
+	return *foundSalt, foundAddress, nil
+}
@@ -0,0 +1,66 @@
+package ethutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVanityMatcher(t *testing.T) {
+	var address = common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	tests := []struct {
+		prefix        string
+		suffix        string
+		regex         string
+		caseSensitive bool
+		match         bool
+		difficulty    float64
+	}{
+		{"f39f", "", "", false, true, 65536},
+		{"0xF39F", "", "", false, true, 65536},
+		{"f39F", "", "", true, true, 65536 * 4},
+		{"f39f", "", "", true, false, 65536 * 4},
+		{"", "2266", "", false, true, 65536},
+		{"f3", "66", "", false, true, 65536},
+		{"f3", "67", "", false, false, 65536},
+		{"", "", "^f3.*66$", false, true, 1},
+		{"", "", "dead", false, false, 1},
+	}
+
+	for i, test := range tests {
+		matcher, err := NewVanityMatcher(test.prefix, test.suffix, test.regex, test.caseSensitive)
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if match := matcher.Match(address); match != test.match {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.match, match)
+		}
+		if difficulty := matcher.Difficulty(); difficulty != test.difficulty {
+			t.Fatalf("test %d: expected: %v, got: %v", i, test.difficulty, difficulty)
+		}
+	}
+
+	if _, err := NewVanityMatcher("xyz", "", "", false); err == nil {
+		t.Fatalf("expected: error for non-hex prefix, got: nil")
+	}
+}
+
+func TestGrindCreate2Salt(t *testing.T) {
+	matcher, _ := NewVanityMatcher("00", "", "", false)
+	deployer := common.HexToAddress("0x4e59b44847b379578588920cA78FbF26c0B4956C")
+	initCodeHash := crypto.Keccak256Hash([]byte{0x00})
+
+	var attempts uint64
+	salt, address, err := GrindCreate2Salt(context.Background(), matcher, deployer, initCodeHash, 4, &attempts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes()); address != expected || !matcher.Match(address) {
+		t.Fatalf("expected: %v, got: %v", expected, address)
+	}
+	if attempts == 0 {
+		t.Fatalf("expected: attempts > 0, got: 0")
+	}
+}
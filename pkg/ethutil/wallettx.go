@@ -0,0 +1,123 @@
+package ethutil
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// erc20TransferSelector is the selector of transfer(address,uint256)
+var erc20TransferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+
+// WalletTxParams is the tx object of eth_sendTransaction request sent to wallet, e.g. the params of MetaMask
+// ethereum.request and WalletConnect session request.
+type WalletTxParams struct {
+	From                 *common.Address `json:"from,omitempty"`
+	To                   *common.Address `json:"to,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas,omitempty"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// WalletConnectRequest is the params of WalletConnect v2 session request (wc_sessionRequest), the topic of session is
+// filled by dapp.
+type WalletConnectRequest struct {
+	ChainID string `json:"chainId"` // CAIP-2 chain id, e.g. "eip155:1"
+	Request struct {
+		Method string           `json:"method"`
+		Params []WalletTxParams `json:"params"`
+	} `json:"request"`
+}
+
+// unsignedTxChainID returns chain id of typed tx, or chainID for legacy tx whose chain id is only encoded in signature.
+func unsignedTxChainID(tx *types.Transaction, chainID *big.Int) *big.Int {
+	if tx.Type() != types.LegacyTxType {
+		return tx.ChainId()
+	}
+	return chainID
+}
+
+// NewWalletTxParams converts unsigned tx to params of eth_sendTransaction. from is optional, chainID is used if tx
+// does not encode chain id (unsigned legacy tx).
+func NewWalletTxParams(tx *types.Transaction, from *common.Address, chainID *big.Int) (*WalletTxParams, error) {
+	chainID = unsignedTxChainID(tx, chainID)
+	if chainID == nil || chainID.Sign() <= 0 {
+		return nil, fmt.Errorf("chain id is not encoded in tx, please specify it")
+	}
+	params := &WalletTxParams{
+		From:    from,
+		To:      tx.To(),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Data:    tx.Data(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.Type() == types.DynamicFeeTxType {
+		params.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		params.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	} else {
+		params.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	}
+	return params, nil
+}
+
+// NewWalletConnectRequest wraps params into WalletConnect v2 session request of eth_sendTransaction.
+func NewWalletConnectRequest(params *WalletTxParams) *WalletConnectRequest {
+	request := &WalletConnectRequest{ChainID: fmt.Sprintf("eip155:%v", params.ChainID.ToInt())}
+	request.Request.Method = "eth_sendTransaction"
+	request.Request.Params = []WalletTxParams{*params}
+	return request
+}
+
+// EIP681Link returns EIP-681 payment link of tx, e.g. "ethereum:0x...@1?value=1000000000000000000", which can be
+// shown as QR code and opened by mobile wallets. Only eth transfer and ERC20 transfer can be expressed by EIP-681,
+// the nonce is left to wallet.
+func EIP681Link(tx *types.Transaction, chainID *big.Int) (string, error) {
+	chainID = unsignedTxChainID(tx, chainID)
+	if tx.To() == nil {
+		return "", fmt.Errorf("contract creation can not be expressed by EIP-681 link")
+	}
+
+	var b strings.Builder
+	var query = url.Values{}
+	data := tx.Data()
+	switch {
+	case len(data) == 0:
+		b.WriteString("ethereum:" + tx.To().Hex())
+		if chainID != nil && chainID.Sign() > 0 {
+			fmt.Fprintf(&b, "@%v", chainID)
+		}
+		query.Set("value", tx.Value().String())
+	case len(data) == 4+32+32 && bytes.Equal(data[:4], erc20TransferSelector) && tx.Value().Sign() == 0:
+		b.WriteString("ethereum:" + tx.To().Hex())
+		if chainID != nil && chainID.Sign() > 0 {
+			fmt.Fprintf(&b, "@%v", chainID)
+		}
+		b.WriteString("/transfer")
+		query.Set("address", common.BytesToAddress(data[4:36]).Hex())
+		query.Set("uint256", new(big.Int).SetBytes(data[36:68]).String())
+	default:
+		return "", fmt.Errorf("only eth transfer and ERC20 transfer can be expressed by EIP-681 link")
+	}
+	if tx.Gas() > 0 {
+		query.Set("gasLimit", fmt.Sprintf("%v", tx.Gas()))
+	}
+	if tx.Type() != types.DynamicFeeTxType && tx.GasPrice().Sign() > 0 {
+		query.Set("gasPrice", tx.GasPrice().String())
+	}
+	b.WriteString("?")
+	b.WriteString(query.Encode())
+	return b.String(), nil
+}
@@ -0,0 +1,76 @@
+package ethutil
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestEIP681Link(t *testing.T) {
+	var to = common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	var token = common.HexToAddress("0xdAC17F958D2ee523a2206206994597C13D831ec7")
+	var transferData = common.FromHex("0xa9059cbb0000000000000000000000008f36975cdea2e6e64f85719788c8efbbe89dfbbb00000000000000000000000000000000000000000000000000000000000f4240")
+
+	tests := []struct {
+		tx       *types.Transaction
+		expected string
+	}{
+		{
+			types.NewTx(&types.LegacyTx{To: &to, Value: big.NewInt(1e18), Gas: 21000, GasPrice: big.NewInt(2e10)}),
+			"ethereum:0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb@1?gasLimit=21000&gasPrice=20000000000&value=1000000000000000000",
+		},
+		{
+			types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(5), To: &token, Value: big.NewInt(0), Data: transferData, GasFeeCap: big.NewInt(1), GasTipCap: big.NewInt(1)}),
+			"ethereum:0xdAC17F958D2ee523a2206206994597C13D831ec7@5/transfer?address=0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb&uint256=1000000",
+		},
+	}
+	for i, tt := range tests {
+		got, err := EIP681Link(tt.tx, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("test %d: EIP681Link fail: %v", i, err)
+		}
+		if got != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %v", i, tt.expected, got)
+		}
+	}
+
+	if _, err := EIP681Link(types.NewTx(&types.LegacyTx{To: &to, Data: common.FromHex("0x12345678")}), big.NewInt(1)); err == nil {
+		t.Fatalf("expected error for arbitrary call, got nil")
+	}
+}
+
+func TestNewWalletTxParams(t *testing.T) {
+	var to = common.HexToAddress("0x8F36975cdeA2e6E64f85719788C8EFBBe89DFBbb")
+	var from = common.HexToAddress("0x24f8209EC5f56A07C94e834627F0651c19ACa0ac")
+
+	tests := []struct {
+		tx       *types.Transaction
+		expected string
+	}{
+		{
+			types.NewTx(&types.LegacyTx{Nonce: 3, To: &to, Value: big.NewInt(1e18), Gas: 21000, GasPrice: big.NewInt(2e10)}),
+			`{"from":"0x24f8209ec5f56a07c94e834627f0651c19aca0ac","to":"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb","value":"0xde0b6b3a7640000","gas":"0x5208","gasPrice":"0x4a817c800","nonce":"0x3","chainId":"0x1"}`,
+		},
+		{
+			types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(5), To: &to, Value: big.NewInt(0), Data: []byte{1}, Gas: 50000, GasFeeCap: big.NewInt(3e10), GasTipCap: big.NewInt(1e9)}),
+			`{"from":"0x24f8209ec5f56a07c94e834627f0651c19aca0ac","to":"0x8f36975cdea2e6e64f85719788c8efbbe89dfbbb","value":"0x0","data":"0x01","gas":"0xc350","maxFeePerGas":"0x6fc23ac00","maxPriorityFeePerGas":"0x3b9aca00","nonce":"0x0","chainId":"0x5"}`,
+		},
+	}
+	for i, tt := range tests {
+		params, err := NewWalletTxParams(tt.tx, &from, big.NewInt(1))
+		if err != nil {
+			t.Fatalf("test %d: NewWalletTxParams fail: %v", i, err)
+		}
+		got, _ := json.Marshal(params)
+		if string(got) != tt.expected {
+			t.Fatalf("test %d: expected: %v, got: %s", i, tt.expected, got)
+		}
+	}
+
+	if _, err := NewWalletTxParams(types.NewTx(&types.LegacyTx{To: &to}), nil, nil); err == nil {
+		t.Fatalf("expected error for missing chain id, got nil")
+	}
+}
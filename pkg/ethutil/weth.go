@@ -0,0 +1,19 @@
+package ethutil
+
+import "github.com/ethereum/go-ethereum/common"
+
+// WrappedNativeTokens are the canonical wrapped native tokens (WETH, or WBNB, WPOL etc on chains whose native token
+// is not eth) keyed by chain id, they all implement deposit() and withdraw(uint256) of WETH9.
+var WrappedNativeTokens = map[uint64]common.Address{
+	1:        common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+	11155111: common.HexToAddress("0xfFf9976782d46CC05630D1f6eBAb18b2324d6B14"),
+	10:       common.HexToAddress("0x4200000000000000000000000000000000000006"),
+	8453:     common.HexToAddress("0x4200000000000000000000000000000000000006"),
+	42161:    common.HexToAddress("0x82aF49447D8a07e3bd95BD0d56f35241523fBab1"),
+	59144:    common.HexToAddress("0xe5D7C2a44FfDDf6b295A15c148167daaAf5Cf34f"),
+	534352:   common.HexToAddress("0x5300000000000000000000000000000000000004"),
+	56:       common.HexToAddress("0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"),
+	137:      common.HexToAddress("0x0d500B1d8E8eF31E21C99d1Db9A6444d3ADf1270"),
+	100:      common.HexToAddress("0xe91D153E0b41518A2Ce8Dd3D7944Fa863463a97d"),
+	43114:    common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7"),
+}
@@ -0,0 +1,116 @@
+package ethutil
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Supported zk-rollups of ZkRollupFinality.
+const (
+	ZkRollupLinea  = "linea"
+	ZkRollupScroll = "scroll"
+	ZkRollupZkEvm  = "zkevm" // Polygon zkEVM and other chains of Polygon RollupManager
+)
+
+// ZkRollupConfig is the rollup kind and the L1 contract tracking batches of a zk-rollup: LineaRollup of Linea,
+// ScrollChain of Scroll and PolygonRollupManager of Polygon zkEVM.
+type ZkRollupConfig struct {
+	Rollup   string
+	Contract common.Address
+	RollupId uint32 // only for zkevm, the rollup id in RollupManager
+}
+
+// KnownZkRollups are zk-rollups on mainnet and sepolia, keyed by L2 chain id.
+var KnownZkRollups = map[uint64]ZkRollupConfig{
+	59144:  {Rollup: ZkRollupLinea, Contract: common.HexToAddress("0xd19d4B5d358258f05D7B411E21A1460D11B0876F")},
+	59141:  {Rollup: ZkRollupLinea, Contract: common.HexToAddress("0xB218f8A4Bc926cF1cA7b3423c154a0D627Bdb7E5")},
+	534352: {Rollup: ZkRollupScroll, Contract: common.HexToAddress("0xa13BAF47339d63B743e7Da8741db5456DAc1E556")},
+	534351: {Rollup: ZkRollupScroll, Contract: common.HexToAddress("0x2D567EcE699Eabe5afCd141eDB7A4f2D0D6ce8a0")},
+	1101:   {Rollup: ZkRollupZkEvm, Contract: common.HexToAddress("0x5132A183E9F3CB7C848b0AAC5Ae0c4f0491B7aB2"), RollupId: 1},
+	2442:   {Rollup: ZkRollupZkEvm, Contract: common.HexToAddress("0x32d33D5137a7cFFb54c5Bf8371172bcEc5f310ff"), RollupId: 1},
+}
+
+// ZkRollupFinality is the L1 status of the batch containing an L2 block. Committed means the batch data is posted
+// to L1, proven means its validity proof is verified on L1, and finalized means its state root is final on L1.
+type ZkRollupFinality struct {
+	L2BlockNumber uint64
+	Batch         *big.Int // nil if the rollup doesn't index batches on L1, e.g. Linea
+	Committed     bool
+	CommitUnknown bool // committed but not finalized data can't be queried on L1, e.g. Linea
+	Proven        bool
+	Finalized     bool
+	LastFinalized *big.Int // the last finalized batch, or L2 block if Batch is nil
+	LastCommitted *big.Int // the last committed batch, nil if unknown
+}
+
+// ZkEvmBatchOfBlock returns the batch of L2 block from Polygon zkEVM node by zkevm_batchNumberByBlockNumber.
+func ZkEvmBatchOfBlock(ctx context.Context, l2 *Client, blockNumber uint64) (*big.Int, error) {
+	var batch hexutil.Big
+	if err := l2.RpcClient.CallContext(ctx, &batch, "zkevm_batchNumberByBlockNumber", hexutil.EncodeUint64(blockNumber)); err != nil {
+		return nil, fmt.Errorf("zkevm_batchNumberByBlockNumber fail: %w", err)
+	}
+	return batch.ToInt(), nil
+}
+
+// ZkRollupBlockFinality returns the L1 status of L2 block. batch is the batch containing the block, it's required by
+// Scroll whose L2 block to batch mapping is not available on L1 nor L2 node, and it's ignored by Linea.
+func ZkRollupBlockFinality(ctx context.Context, l1 *Client, config ZkRollupConfig, blockNumber uint64, batch *big.Int) (*ZkRollupFinality, error) {
+	result := &ZkRollupFinality{L2BlockNumber: blockNumber}
+	switch config.Rollup {
+	case ZkRollupLinea:
+		// LineaRollup submits data as blobs identified by shnarf, and verifies proof on finalization, so only the
+		// last finalized L2 block can be queried
+		values, err := CallAndUnpack(ctx, l1.EthClient, config.Contract, "function currentL2BlockNumber() returns (uint256)", nil)
+		if err != nil {
+			return nil, err
+		}
+		result.LastFinalized = values[0].(*big.Int)
+		result.Finalized = result.LastFinalized.Cmp(new(big.Int).SetUint64(blockNumber)) >= 0
+		result.Proven = result.Finalized
+		result.Committed = result.Finalized
+		result.CommitUnknown = !result.Finalized
+	case ZkRollupScroll:
+		if batch == nil {
+			return nil, fmt.Errorf("batch index is required by scroll")
+		}
+		result.Batch = batch
+		values, err := CallAndUnpack(ctx, l1.EthClient, config.Contract, "function lastFinalizedBatchIndex() returns (uint256)", nil)
+		if err != nil {
+			return nil, err
+		}
+		result.LastFinalized = values[0].(*big.Int)
+		// ScrollChain verifies proof in finalizeBundle, a batch is proven once finalized
+		result.Finalized = result.LastFinalized.Cmp(batch) >= 0
+		result.Proven = result.Finalized
+		values, err = CallAndUnpack(ctx, l1.EthClient, config.Contract, "function committedBatches(uint256) returns (bytes32)", []string{batch.String()})
+		if err != nil {
+			return nil, err
+		}
+		result.Committed = result.Finalized || values[0].([32]byte) != [32]byte{}
+	case ZkRollupZkEvm:
+		if batch == nil {
+			return nil, fmt.Errorf("batch number is required by zkevm")
+		}
+		result.Batch = batch
+		// the leading static fields of RollupData struct, the trailing fields differ between versions of RollupManager
+		values, err := CallAndUnpack(ctx, l1.EthClient, config.Contract,
+			"function rollupIDToRollupData(uint32) returns (address, uint64, address, uint64, bytes32, uint64, uint64)",
+			[]string{fmt.Sprint(config.RollupId)})
+		if err != nil {
+			return nil, err
+		}
+		result.LastCommitted = new(big.Int).SetUint64(values[5].(uint64))
+		result.LastFinalized = new(big.Int).SetUint64(values[6].(uint64))
+		result.Committed = result.LastCommitted.Cmp(batch) >= 0
+		// verified batches of trusted aggregator are consolidated immediately
+		result.Finalized = result.LastFinalized.Cmp(batch) >= 0
+		result.Proven = result.Finalized
+	default:
+		return nil, fmt.Errorf("unsupported rollup %v", config.Rollup)
+	}
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package ethutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newZkRollupServer returns node answering eth_call by the function selector of call data.
+func newZkRollupServer(t *testing.T, results map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Id     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		var call CallArgs
+		if err := json.Unmarshal(req.Params[0], &call); err != nil {
+			t.Error(err)
+		}
+		data := call.Data.String()
+		for sig, result := range results {
+			if strings.HasPrefix(data, "0x"+common.Bytes2Hex(crypto.Keccak256([]byte(sig))[:4])) {
+				_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":"%v"}`, req.Id, result)
+				return
+			}
+		}
+		t.Errorf("unexpected call %v", data)
+	}))
+}
+
+// words returns abi encoding of uint256 values.
+func words(values ...int64) string {
+	var encoded string
+	for _, value := range values {
+		encoded += common.Bytes2Hex(common.LeftPadBytes(big.NewInt(value).Bytes(), 32))
+	}
+	return "0x" + encoded
+}
+
+func TestZkRollupBlockFinality(t *testing.T) {
+	tests := []struct {
+		name          string
+		rollup        string
+		results       map[string]string
+		blockNumber   uint64
+		batch         *big.Int
+		wantCommitted bool
+		wantFinalized bool
+		wantUnknown   bool
+	}{
+		{"linea finalized", ZkRollupLinea, map[string]string{"currentL2BlockNumber()": words(1000)}, 1000, nil, true, true, false},
+		{"linea not finalized", ZkRollupLinea, map[string]string{"currentL2BlockNumber()": words(1000)}, 1001, nil, false, false, true},
+		{"scroll committed", ZkRollupScroll, map[string]string{"lastFinalizedBatchIndex()": words(50), "committedBatches(uint256)": words(1)}, 1, big.NewInt(51), true, false, false},
+		{"scroll not committed", ZkRollupScroll, map[string]string{"lastFinalizedBatchIndex()": words(50), "committedBatches(uint256)": words(0)}, 1, big.NewInt(52), false, false, false},
+		{"scroll finalized", ZkRollupScroll, map[string]string{"lastFinalizedBatchIndex()": words(50), "committedBatches(uint256)": words(0)}, 1, big.NewInt(50), true, true, false},
+		{"zkevm committed", ZkRollupZkEvm, map[string]string{"rollupIDToRollupData(uint32)": words(1, 1101, 2, 9, 0, 200, 150, 0, 0, 0, 1, 0)}, 1, big.NewInt(180), true, false, false},
+		{"zkevm finalized", ZkRollupZkEvm, map[string]string{"rollupIDToRollupData(uint32)": words(1, 1101, 2, 9, 0, 200, 150, 0, 0, 0, 1, 0)}, 1, big.NewInt(150), true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newZkRollupServer(t, tt.results)
+			defer server.Close()
+			client, err := Dial(context.Background(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			got, err := ZkRollupBlockFinality(context.Background(), client, ZkRollupConfig{Rollup: tt.rollup, RollupId: 1}, tt.blockNumber, tt.batch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Committed != tt.wantCommitted || got.Finalized != tt.wantFinalized || got.Proven != tt.wantFinalized || got.CommitUnknown != tt.wantUnknown {
+				t.Errorf("unexpected finality %+v", got)
+			}
+		})
+	}
+}